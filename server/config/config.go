@@ -12,14 +12,30 @@ type Config struct {
 	SessionSecret  string `env:"SECRET,required"`
 	Domain         string `env:"DOMAIN"`
 	CorsOrigin     string `env:"CORS_ORIGIN,required"`
+	StorageDriver  string `env:"STORAGE_DRIVER,default=s3"`
+	StoragePath    string `env:"STORAGE_PATH,default=uploads"`
+	PublicUrl      string `env:"PUBLIC_URL,default=http://localhost:4000"`
 	AccessKey      string `env:"AWS_ACCESS_KEY"`
 	SecretKey      string `env:"SECRET_KEY"`
 	BucketName     string `env:"BUCKET_NAME"`
 	Region         string `env:"REGION"`
-	GmailUser      string `env:"GMAIL_USER"`
-	GmailPassword  string `env:"GMAIL_PASSWORD"`
+	S3Endpoint     string `env:"S3_ENDPOINT"`
+	S3PathStyle    bool   `env:"S3_PATH_STYLE,default=false"`
+	MailDriver     string `env:"MAIL_DRIVER,default=smtp"`
+	MailFrom       string `env:"MAIL_FROM"`
+	MailPath       string `env:"MAIL_PATH,default=mails"`
+	SMTPHost       string `env:"SMTP_HOST,default=smtp.gmail.com"`
+	SMTPPort       int    `env:"SMTP_PORT,default=587"`
+	SMTPSecurity   string `env:"SMTP_SECURITY,default=starttls"`
+	SMTPUsername   string `env:"SMTP_USERNAME"`
+	SMTPPassword   string `env:"SMTP_PASSWORD"`
+	GmailUser      string `env:"GMAIL_USER"`     // Deprecated: use SMTP_USERNAME
+	GmailPassword  string `env:"GMAIL_PASSWORD"` // Deprecated: use SMTP_PASSWORD
 	HandlerTimeOut int64  `env:"HANDLER_TIMEOUT,default=5"`
 	MaxBodyBytes   int64  `env:"MAX_BODY_BYTES,default=4194304"`
+	FileTypes      string `env:"ALLOWED_FILE_TYPES"`
+	KeepOriginals  string `env:"KEEP_ORIGINAL_TYPES"`
+	Restrictions   string `env:"UNVERIFIED_RESTRICTIONS"`
 }
 
 func LoadConfig(ctx context.Context) (config Config, err error) {
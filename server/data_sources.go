@@ -40,11 +40,34 @@ func initDS(ctx context.Context, cfg config.Config) (*dataSources, error) {
 		&model.DMMember{},
 		&model.Message{},
 		&model.Attachment{},
+		&model.Upload{},
+		&model.UploadChunk{},
+		&model.MessageRevision{},
 		&model.VCMember{},
+		&model.Role{},
+		&model.MemberRole{},
+		&model.PermissionOverwrite{},
+		&model.Reaction{},
+		&model.Mention{},
+		&model.ThreadMember{},
+		&model.AccessToken{},
+		&model.Command{},
+		&model.Webhook{},
 	); err != nil {
 		return nil, fmt.Errorf("error migrating models: %w", err)
 	}
 
+	// Add the full-text search column for messages
+	if err = db.Exec(fmt.Sprintf(`
+		ALTER TABLE messages ADD COLUMN IF NOT EXISTS search tsvector
+		GENERATED ALWAYS AS (to_tsvector('%s', coalesce(text, ''))) STORED`, model.SearchConfig)).Error; err != nil {
+		return nil, fmt.Errorf("error creating search column: %w", err)
+	}
+
+	if err = db.Exec("CREATE INDEX IF NOT EXISTS idx_messages_search ON messages USING GIN (search)").Error; err != nil {
+		return nil, fmt.Errorf("error creating search index: %w", err)
+	}
+
 	if err = db.SetupJoinTable(&model.Guild{}, "Members", &model.Member{}); err != nil {
 		return nil, fmt.Errorf("error creating join table: %w", err)
 	}
@@ -70,19 +93,34 @@ func initDS(ctx context.Context, cfg config.Config) (*dataSources, error) {
 	}
 
 	// Initialize S3 Session
-	sess, err := session.NewSession(
-		&aws.Config{
+	var sess *session.Session
+
+	switch cfg.StorageDriver {
+	case model.StorageDriverS3:
+		awsConfig := &aws.Config{
 			Credentials: credentials.NewStaticCredentials(
 				cfg.AccessKey,
-				cfg.SessionSecret,
+				cfg.SecretKey,
 				"",
 			),
-			Region: aws.String(cfg.Region),
-		},
-	)
-
-	if err != nil {
-		return nil, fmt.Errorf("error creating s3 session: %w", err)
+			Region:           aws.String(cfg.Region),
+			S3ForcePathStyle: aws.Bool(cfg.S3PathStyle),
+		}
+
+		// Custom endpoints for S3-compatible storages like MinIO
+		if cfg.S3Endpoint != "" {
+			awsConfig.Endpoint = aws.String(cfg.S3Endpoint)
+		}
+
+		sess, err = session.NewSession(awsConfig)
+
+		if err != nil {
+			return nil, fmt.Errorf("error creating s3 session: %w", err)
+		}
+	case model.StorageDriverLocal:
+		log.Printf("Storing files in %s\n", cfg.StoragePath)
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", cfg.StorageDriver)
 	}
 
 	return &dataSources{
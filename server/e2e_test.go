@@ -2206,7 +2206,7 @@ func TestMain_MessagesE2E(t *testing.T) {
 				assert.NotNil(t, message.CreatedAt)
 				assert.NotNil(t, message.UpdatedAt)
 				assert.NotNil(t, message.User)
-				assert.Nil(t, message.Attachment)
+				assert.Empty(t, message.Attachments)
 
 				author := message.User
 				assert.Equal(t, authUser.Username, author.Username)
@@ -2272,7 +2272,7 @@ func TestMain_MessagesE2E(t *testing.T) {
 				assert.NotNil(t, message.CreatedAt)
 				assert.NotNil(t, message.UpdatedAt)
 				assert.NotNil(t, message.User)
-				assert.Nil(t, message.Attachment)
+				assert.Empty(t, message.Attachments)
 
 				author := message.User
 				assert.Equal(t, authUser.Username, author.Username)
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+/*
+ * AccessTokenHandler contains all routes related to personal access tokens (/api/account/tokens)
+ */
+
+// GetAccessTokens returns the access tokens of the current user
+// GetAccessTokens godoc
+// @Tags Account
+// @Summary Get Current User's Access Tokens
+// @Produce  json
+// @Success 200 {array} model.AccessToken
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /account/tokens [get]
+func (h *Handler) GetAccessTokens(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+
+	tokens, err := h.tokenService.GetAccessTokens(userId)
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+type createAccessTokenReq struct {
+	// Name to recognize the token. Between 1 and 50 characters
+	Name string `json:"name"`
+	// Any of messages.read, messages.send and guilds.manage
+	Scopes []string `json:"scopes"`
+	// Optional date after which the token stops working
+	ExpiresAt *time.Time `json:"expiresAt"`
+} //@name CreateAccessTokenRequest
+
+func (r createAccessTokenReq) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Name, validation.Required, validation.Length(1, 50)),
+		validation.Field(&r.Scopes, validation.Required, validation.By(areTokenScopes)),
+		validation.Field(&r.ExpiresAt, validation.Min(time.Now()).Error(apperrors.TokenExpiryError)),
+	)
+}
+
+func (r *createAccessTokenReq) sanitize() {
+	r.Name = strings.TrimSpace(r.Name)
+}
+
+// areTokenScopes checks that the scopes only contain the scopes of access tokens
+func areTokenScopes(value interface{}) error {
+	scopes, _ := value.([]string)
+
+	for _, scope := range scopes {
+		valid := false
+		for _, s := range model.AccessTokenScopes {
+			if s == scope {
+				valid = true
+				break
+			}
+		}
+
+		if !valid {
+			return errors.New(apperrors.InvalidTokenScope)
+		}
+	}
+
+	return nil
+}
+
+// CreateAccessToken creates a new access token for scripts.
+// The token is only returned in this response.
+// CreateAccessToken godoc
+// @Tags Account
+// @Summary Create Access Token
+// @Accept  json
+// @Produce  json
+// @Param request body createAccessTokenReq true "Create Access Token"
+// @Success 201 {object} model.CreatedAccessToken
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /account/tokens [post]
+func (h *Handler) CreateAccessToken(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+	var req createAccessTokenReq
+
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	req.sanitize()
+
+	token, err := h.tokenService.CreateAccessToken(userId, req.Name, req.Scopes, req.ExpiresAt)
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+// DeleteAccessToken revokes the given access token
+// DeleteAccessToken godoc
+// @Tags Account
+// @Summary Delete Access Token
+// @Produce  json
+// @Param tokenId path string true "Access Token ID"
+// @Success 200 {object} model.Success
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /account/tokens/{tokenId} [delete]
+func (h *Handler) DeleteAccessToken(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+	tokenId := c.Param("tokenId")
+
+	if err := h.tokenService.DeleteAccessToken(userId, tokenId); err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	// Connections opened with the token stay open otherwise
+	h.socketService.DisconnectAccessTokens(userId, []string{tokenId})
+
+	c.JSON(http.StatusOK, true)
+}
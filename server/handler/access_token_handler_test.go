@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler_CreateAccessToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	uid := fixture.RandID()
+
+	t.Run("Success", func(t *testing.T) {
+		scopes := []string{model.ScopeReadMessages, model.ScopeSendMessages}
+		created := &model.CreatedAccessToken{
+			AccessToken: model.AccessToken{
+				BaseModel: model.BaseModel{ID: fixture.RandID()},
+				UserId:    uid,
+				Name:      "Backup script",
+				Scopes:    scopes,
+			},
+			Token: model.AccessTokenPrefix + "abcdef",
+		}
+
+		mockTokenService := new(mocks.AccessTokenService)
+		mockTokenService.On("CreateAccessToken", uid, "Backup script", scopes, (*time.Time)(nil)).Return(created, nil)
+
+		router := getAuthenticatedTestRouter(uid)
+
+		NewHandler(&Config{
+			R:            router,
+			TokenService: mockTokenService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"name":   " Backup script ",
+			"scopes": scopes,
+		})
+
+		request, _ := http.NewRequest(http.MethodPost, "/api/account/tokens", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(created)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		assert.Contains(t, rr.Body.String(), created.Token)
+		assert.NotContains(t, rr.Body.String(), "tokenHash")
+		mockTokenService.AssertExpectations(t)
+	})
+
+	testCases := []struct {
+		name   string
+		body   gin.H
+		field  string
+		reason string
+	}{
+		{
+			name:   "Name required",
+			body:   gin.H{"scopes": []string{model.ScopeReadMessages}},
+			field:  "name",
+			reason: "cannot be blank.",
+		},
+		{
+			name:   "Scopes required",
+			body:   gin.H{"name": "Backup script", "scopes": []string{}},
+			field:  "scopes",
+			reason: "cannot be blank.",
+		},
+		{
+			name:   "Unknown scope",
+			body:   gin.H{"name": "Backup script", "scopes": []string{"account.write"}},
+			field:  "scopes",
+			reason: apperrors.InvalidTokenScope + ".",
+		},
+		{
+			name: "Expiry in the past",
+			body: gin.H{
+				"name":      "Backup script",
+				"scopes":    []string{model.ScopeReadMessages},
+				"expiresAt": time.Now().Add(-time.Hour),
+			},
+			field:  "expiresAt",
+			reason: apperrors.TokenExpiryError + ".",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			mockTokenService := new(mocks.AccessTokenService)
+
+			router := getAuthenticatedTestRouter(uid)
+
+			NewHandler(&Config{
+				R:            router,
+				TokenService: mockTokenService,
+			})
+
+			rr := httptest.NewRecorder()
+
+			reqBody, _ := json.Marshal(tc.body)
+
+			request, _ := http.NewRequest(http.MethodPost, "/api/account/tokens", bytes.NewBuffer(reqBody))
+			request.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(rr, request)
+
+			respBody, _ := json.Marshal(getTestFieldErrorResponse(tc.field, tc.reason))
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.Equal(t, respBody, rr.Body.Bytes())
+			mockTokenService.AssertNotCalled(t, "CreateAccessToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestHandler_DeleteAccessToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	uid := fixture.RandID()
+
+	t.Run("Success", func(t *testing.T) {
+		mockTokenService := new(mocks.AccessTokenService)
+		mockTokenService.On("DeleteAccessToken", uid, "token").Return(nil)
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("DisconnectAccessTokens", uid, []string{"token"}).Return()
+
+		router := getAuthenticatedTestRouter(uid)
+
+		NewHandler(&Config{
+			R:             router,
+			TokenService:  mockTokenService,
+			SocketService: mockSocketService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		request, _ := http.NewRequest(http.MethodDelete, "/api/account/tokens/token", nil)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(true)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockTokenService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
+	t.Run("Token not found", func(t *testing.T) {
+		mockError := apperrors.NewNotFound("token", "token")
+
+		mockTokenService := new(mocks.AccessTokenService)
+		mockTokenService.On("DeleteAccessToken", uid, "token").Return(mockError)
+
+		router := getAuthenticatedTestRouter(uid)
+
+		NewHandler(&Config{
+			R:            router,
+			TokenService: mockTokenService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		request, _ := http.NewRequest(http.MethodDelete, "/api/account/tokens/token", nil)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(gin.H{
+			"error": mockError,
+		})
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+	})
+}
+
+func TestHandler_AccessTokenScopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	uid := fixture.RandID()
+	accessToken := &model.AccessToken{
+		BaseModel: model.BaseModel{ID: fixture.RandID()},
+		UserId:    uid,
+		Scopes:    []string{model.ScopeReadMessages},
+	}
+
+	t.Run("Account routes reject access tokens", func(t *testing.T) {
+		mockTokenService := new(mocks.AccessTokenService)
+		mockUserService := new(mocks.UserService)
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:            router,
+			UserService:  mockUserService,
+			TokenService: mockTokenService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		request, _ := http.NewRequest(http.MethodGet, "/api/account/tokens", nil)
+		request.Header.Set("Authorization", "Bearer "+model.AccessTokenPrefix+"abcdef")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		mockTokenService.AssertNotCalled(t, "Authenticate", mock.Anything)
+		mockTokenService.AssertNotCalled(t, "GetAccessTokens", mock.Anything)
+	})
+
+	t.Run("Sending messages requires the send scope", func(t *testing.T) {
+		token := model.AccessTokenPrefix + "abcdef"
+
+		mockTokenService := new(mocks.AccessTokenService)
+		mockTokenService.On("Authenticate", token).Return(accessToken, nil)
+
+		mockMessageService := new(mocks.MessageService)
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:              router,
+			MessageService: mockMessageService,
+			TokenService:   mockTokenService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"text": "Hello World",
+		})
+
+		request, _ := http.NewRequest(http.MethodPost, "/api/messages/"+fixture.RandID(), bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(gin.H{
+			"error": apperrors.NewAuthorization(apperrors.MissingTokenScope),
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockTokenService.AssertExpectations(t)
+	})
+}
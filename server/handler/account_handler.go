@@ -5,6 +5,7 @@ import (
 	"github.com/gin-gonic/gin"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/sentrionic/valkyrie/model"
 	"github.com/sentrionic/valkyrie/model/apperrors"
 	"log"
 	"mime/multipart"
@@ -101,9 +102,10 @@ func (h *Handler) Edit(c *gin.Context) {
 	}
 
 	authUser.Username = req.Username
+	emailChanged := authUser.Email != req.Email
 
 	// New email, check if it's unique
-	if authUser.Email != req.Email {
+	if emailChanged {
 		inUse := h.userService.IsEmailAlreadyInUse(req.Email)
 
 		if inUse {
@@ -111,15 +113,13 @@ func (h *Handler) Edit(c *gin.Context) {
 			return
 		}
 		authUser.Email = req.Email
+		authUser.EmailVerifiedAt = nil
 	}
 
 	if req.Image != nil {
 
-		// Validate image mime-type is allowable
-		mimeType := req.Image.Header.Get("Content-Type")
-
-		if valid := isAllowedImageType(mimeType); !valid {
-			toFieldErrorResponse(c, "Image", apperrors.InvalidImageType)
+		// Validate image mime-type, size and content are allowable
+		if ok := h.checkFile(c, "Image", req.Image, true); !ok {
 			return
 		}
 
@@ -127,9 +127,8 @@ func (h *Handler) Edit(c *gin.Context) {
 		url, err := h.userService.ChangeAvatar(req.Image, directory)
 
 		if err != nil {
-			e := apperrors.NewInternal()
-			c.JSON(e.Status(), gin.H{
-				"error": e,
+			c.JSON(apperrors.Status(err), gin.H{
+				"error": err,
 			})
 			return
 		}
@@ -149,6 +148,13 @@ func (h *Handler) Edit(c *gin.Context) {
 		return
 	}
 
+	// The new address needs to be verified
+	if emailChanged {
+		if err = h.userService.SendVerificationMail(c.Request.Context(), authUser); err != nil {
+			log.Printf("Failed to send verification mail: %v\n", err.Error())
+		}
+	}
+
 	c.JSON(http.StatusOK, authUser)
 }
 
@@ -158,6 +164,8 @@ type changeRequest struct {
 	NewPassword string `json:"newPassword"`
 	// Must be the same as the newPassword value.
 	ConfirmNewPassword string `json:"confirmNewPassword"`
+	// The current two-factor code. Required if two-factor authentication is enabled.
+	Code string `json:"code"`
 } //@name ChangePasswordRequest
 
 func (r changeRequest) validate() error {
@@ -172,6 +180,7 @@ func (r *changeRequest) sanitize() {
 	r.CurrentPassword = strings.TrimSpace(r.CurrentPassword)
 	r.NewPassword = strings.TrimSpace(r.NewPassword)
 	r.ConfirmNewPassword = strings.TrimSpace(r.ConfirmNewPassword)
+	r.Code = strings.TrimSpace(r.Code)
 }
 
 // ChangePassword handler changes the user's password
@@ -213,6 +222,10 @@ func (h *Handler) ChangePassword(c *gin.Context) {
 		return
 	}
 
+	if ok := h.checkTwoFactor(c, authUser, req.Code); !ok {
+		return
+	}
+
 	err = h.userService.ChangePassword(req.CurrentPassword, req.NewPassword, authUser)
 
 	if err != nil {
@@ -222,5 +235,55 @@ func (h *Handler) ChangePassword(c *gin.Context) {
 		return
 	}
 
+	// Log out all other devices
+	h.revokeOtherSessions(c, userId, c.GetString("sessionId"))
+
 	c.JSON(http.StatusOK, true)
 }
+
+// ResendVerification sends a new verification mail for the current email address
+// ResendVerification godoc
+// @Tags Account
+// @Summary Resend Verification Email
+// @Produce  json
+// @Success 200 {object} model.Success
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /account/resend-verification [post]
+func (h *Handler) ResendVerification(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+
+	authUser, err := h.userService.Get(userId)
+
+	if err != nil {
+		e := apperrors.NewAuthorization(apperrors.InvalidSession)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	if err = h.userService.SendVerificationMail(c.Request.Context(), authUser); err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, true)
+}
+
+// checkVerified responds with an error and returns false if the deployment
+// restricts the action to users with a verified email address and the user has not verified theirs
+func (h *Handler) checkVerified(c *gin.Context, user *model.User, action string) bool {
+	if h.restrictions.Restricts(user, action) {
+		e := apperrors.NewAuthorization(apperrors.EmailNotVerified)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return false
+	}
+
+	return true
+}
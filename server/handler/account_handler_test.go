@@ -12,12 +12,15 @@ import (
 	"github.com/sentrionic/valkyrie/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"image/color"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/textproto"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestHandler_GetCurrent(t *testing.T) {
@@ -266,6 +269,90 @@ func TestHandler_EditAccount(t *testing.T) {
 		mockUserService.AssertNotCalled(t, "ChangeAvatar")
 	})
 
+	t.Run("Image content does not match its type", func(t *testing.T) {
+		router := getAuthenticatedTestRouter(uid)
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", uid).Return(mockUser, nil)
+
+		NewHandler(&Config{
+			R:            router,
+			UserService:  mockUserService,
+			MaxBodyBytes: 4 * 1024 * 1024,
+		})
+
+		rr := httptest.NewRecorder()
+
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		_ = writer.WriteField("username", mockUser.Username)
+		_ = writer.WriteField("email", mockUser.Email)
+
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", `form-data; name="image"; filename="image.png"`)
+		h.Set("Content-Type", "image/png")
+		part, _ := writer.CreatePart(h)
+		_, _ = part.Write([]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+		_ = writer.Close()
+
+		request, _ := http.NewRequest(http.MethodPut, "/api/account", body)
+		request.Header.Set("Content-Type", writer.FormDataContentType())
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(getTestFieldErrorResponse("Image", apperrors.FileTypeMismatchError))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+
+		mockUserService.AssertNotCalled(t, "ChangeAvatar", mock.Anything, mock.Anything)
+		mockUserService.AssertNotCalled(t, "UpdateAccount", mock.Anything)
+	})
+
+	t.Run("Animation gets rejected by the storage", func(t *testing.T) {
+		router := getAuthenticatedTestRouter(uid)
+
+		mockError := apperrors.NewBadRequest(apperrors.AnimationTooLarge)
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", uid).Return(mockUser, nil)
+		mockUserService.On("ChangeAvatar", mock.AnythingOfType("*multipart.FileHeader"), "valkyrie/users/"+uid).Return("", mockError)
+
+		NewHandler(&Config{
+			R:            router,
+			UserService:  mockUserService,
+			MaxBodyBytes: 4 * 1024 * 1024,
+		})
+
+		rr := httptest.NewRecorder()
+
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		_ = writer.WriteField("username", mockUser.Username)
+		_ = writer.WriteField("email", mockUser.Email)
+
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", `form-data; name="image"; filename="avatar.gif"`)
+		h.Set("Content-Type", "image/gif")
+		part, _ := writer.CreatePart(h)
+		_, _ = part.Write(fixture.NewAnimatedGif(10, 10, color.White, color.Black))
+		_ = writer.Close()
+
+		request, _ := http.NewRequest(http.MethodPut, "/api/account", body)
+		request.Header.Set("Content-Type", writer.FormDataContentType())
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(gin.H{
+			"error": mockError,
+		})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+
+		mockUserService.AssertExpectations(t)
+		mockUserService.AssertNotCalled(t, "UpdateAccount", mock.Anything)
+	})
+
 	t.Run("Email already in use", func(t *testing.T) {
 		router := getAuthenticatedTestRouter(uid)
 
@@ -301,6 +388,54 @@ func TestHandler_EditAccount(t *testing.T) {
 		assert.Equal(t, respBody, rr.Body.Bytes())
 		mockUserService.AssertNotCalled(t, "UpdateAccount", mockUser)
 	})
+
+	t.Run("Email change requires a new verification", func(t *testing.T) {
+		router := getAuthenticatedTestRouter(uid)
+
+		verifiedAt := time.Now()
+		authUser := fixture.GetMockUser()
+		authUser.ID = uid
+		authUser.EmailVerifiedAt = &verifiedAt
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", uid).Return(authUser, nil)
+
+		NewHandler(&Config{
+			R:            router,
+			UserService:  mockUserService,
+			MaxBodyBytes: 4 * 1024 * 1024,
+		})
+
+		rr := httptest.NewRecorder()
+
+		newEmail := fixture.Email()
+
+		form := url.Values{}
+		form.Add("username", authUser.Username)
+		form.Add("email", newEmail)
+
+		request, _ := http.NewRequest(http.MethodPut, "/api/account", strings.NewReader(form.Encode()))
+		request.Form = form
+
+		mockUserService.
+			On("IsEmailAlreadyInUse", newEmail).
+			Return(false)
+
+		mockUserService.
+			On("UpdateAccount", authUser).
+			Return(nil)
+
+		mockUserService.
+			On("SendVerificationMail", mock.Anything, authUser).
+			Return(nil)
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, newEmail, authUser.Email)
+		assert.Nil(t, authUser.EmailVerifiedAt)
+		mockUserService.AssertExpectations(t)
+	})
 }
 
 func TestHandler_ChangePassword(t *testing.T) {
@@ -433,6 +568,47 @@ func TestHandler_ChangePassword(t *testing.T) {
 		assert.Equal(t, respBody, rr.Body.Bytes())
 		mockUserService.AssertCalled(t, "ChangePassword", ChangePasswordArgs...)
 	})
+
+	t.Run("Two-factor code required", func(t *testing.T) {
+		router := getAuthenticatedTestRouter(uid)
+
+		authUser := fixture.GetMockUser()
+		authUser.ID = uid
+		authUser.TwoFactorEnabled = true
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", uid).Return(authUser, nil)
+		mockUserService.
+			On("VerifyTwoFactor", authUser, "").
+			Return(apperrors.NewBadRequest(apperrors.InvalidTwoFactor))
+
+		NewHandler(&Config{
+			R:            router,
+			UserService:  mockUserService,
+			MaxBodyBytes: 4 * 1024 * 1024,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, err := json.Marshal(gin.H{
+			"currentPassword":    authUser.Password,
+			"newPassword":        "password!",
+			"confirmNewPassword": "password!",
+		})
+		assert.NoError(t, err)
+
+		request, _ := http.NewRequest(http.MethodPut, "/api/account/change-password", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(getTestFieldErrorResponse("Code", apperrors.InvalidTwoFactor))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockUserService.AssertExpectations(t)
+		mockUserService.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything, mock.Anything)
+	})
 }
 
 func TestHandler_ChangePassword_BadRequest(t *testing.T) {
@@ -546,3 +722,85 @@ func TestHandler_ChangePassword_BadRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_ResendVerification(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	uid := service.GenerateId()
+	mockUser := fixture.GetMockUser()
+	mockUser.ID = uid
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		router := getTestRouter()
+		mockUserService := new(mocks.UserService)
+
+		NewHandler(&Config{
+			R:           router,
+			UserService: mockUserService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		request, _ := http.NewRequest(http.MethodPost, "/api/account/resend-verification", nil)
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		mockUserService.AssertNotCalled(t, "SendVerificationMail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		router := getAuthenticatedTestRouter(uid)
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", uid).Return(mockUser, nil)
+		mockUserService.On("SendVerificationMail", mock.Anything, mockUser).Return(nil)
+
+		NewHandler(&Config{
+			R:           router,
+			UserService: mockUserService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		request, _ := http.NewRequest(http.MethodPost, "/api/account/resend-verification", nil)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(true)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("Already verified", func(t *testing.T) {
+		router := getAuthenticatedTestRouter(uid)
+
+		mockError := apperrors.NewBadRequest(apperrors.AlreadyVerified)
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", uid).Return(mockUser, nil)
+		mockUserService.On("SendVerificationMail", mock.Anything, mockUser).Return(mockError)
+
+		NewHandler(&Config{
+			R:           router,
+			UserService: mockUserService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		request, _ := http.NewRequest(http.MethodPost, "/api/account/resend-verification", nil)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(gin.H{
+			"error": mockError,
+		})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockUserService.AssertExpectations(t)
+	})
+}
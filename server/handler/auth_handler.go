@@ -81,7 +81,12 @@ func (h *Handler) Register(c *gin.Context) {
 		return
 	}
 
-	setUserSession(c, user.ID)
+	// The account works without a verified email, so a failed mail only gets logged
+	if err = h.userService.SendVerificationMail(c.Request.Context(), user); err != nil {
+		log.Printf("Failed to send verification mail: %v\n", err.Error())
+	}
+
+	h.setUserSession(c, user.ID)
 
 	c.JSON(http.StatusCreated, user)
 }
@@ -114,6 +119,7 @@ func (r *loginReq) sanitize() {
 // @Produce  json
 // @Param account body loginReq true "Login account"
 // @Success 200 {object} model.User
+// @Success 202 {object} model.TwoFactorChallenge
 // @Failure 400 {object} model.ErrorsResponse
 // @Failure 401 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
@@ -136,7 +142,25 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
-	setUserSession(c, user.ID)
+	// The session only gets created after the second step
+	if user.TwoFactorEnabled {
+		ticket, err := h.userService.StartTwoFactorLogin(c.Request.Context(), user)
+
+		if err != nil {
+			c.JSON(apperrors.Status(err), gin.H{
+				"error": err,
+			})
+			return
+		}
+
+		c.JSON(http.StatusAccepted, model.TwoFactorChallenge{
+			TwoFactorRequired: true,
+			Ticket:            ticket,
+		})
+		return
+	}
+
+	h.setUserSession(c, user.ID)
 
 	c.JSON(http.StatusOK, user)
 }
@@ -154,15 +178,18 @@ func (h *Handler) Logout(c *gin.Context) {
 	c.Set("user", nil)
 
 	session := sessions.Default(c)
-	session.Set("userId", "")
-	session.Clear()
-	session.Options(sessions.Options{Path: "/", MaxAge: -1})
-	err := session.Save()
+	userId, _ := session.Get("userId").(string)
+	sessionId, _ := session.Get("sessionId").(string)
 
-	if err != nil {
-		log.Printf("error clearing session: %v\n", err.Error())
+	// Remove the session from the user's sessions
+	if h.sessionService != nil && userId != "" && sessionId != "" {
+		if err := h.sessionService.RevokeSession(c.Request.Context(), userId, sessionId); err != nil {
+			log.Printf("error revoking session: %v\n", err.Error())
+		}
 	}
 
+	clearUserSession(c)
+
 	c.JSON(http.StatusOK, true)
 }
 
@@ -253,7 +280,8 @@ func (r *resetRequest) sanitize() {
 	r.ConfirmPassword = strings.TrimSpace(r.ConfirmPassword)
 }
 
-// ResetPassword resets the user's password with the provided token
+// ResetPassword resets the user's password with the provided token.
+// Users with two-factor authentication still need to log in with their code afterwards.
 // ResetPassword godoc
 // @Tags Account
 // @Summary Reset Password
@@ -261,6 +289,7 @@ func (r *resetRequest) sanitize() {
 // @Produce  json
 // @Param request body resetRequest true "Reset Password"
 // @Success 200 {object} model.User
+// @Success 202 {object} model.TwoFactorChallenge
 // @Failure 400 {object} model.ErrorsResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Router /account/reset-password [post]
@@ -293,7 +322,80 @@ func (h *Handler) ResetPassword(c *gin.Context) {
 		return
 	}
 
-	setUserSession(c, user.ID)
+	// Log out everywhere, the old password might be known to someone else
+	h.revokeOtherSessions(c, user.ID, "")
+
+	// Access to the mailbox alone must not skip the second step
+	if user.TwoFactorEnabled {
+		ticket, err := h.userService.StartTwoFactorLogin(ctx, user)
+
+		if err != nil {
+			c.JSON(apperrors.Status(err), gin.H{
+				"error": err,
+			})
+			return
+		}
+
+		c.JSON(http.StatusAccepted, model.TwoFactorChallenge{
+			TwoFactorRequired: true,
+			Ticket:            ticket,
+		})
+		return
+	}
+
+	h.setUserSession(c, user.ID)
+
+	c.JSON(http.StatusOK, user)
+}
+
+type verifyRequest struct {
+	// The token the user got from the email.
+	Token string `json:"token"`
+} //@name VerifyEmailRequest
+
+func (r verifyRequest) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Token, validation.Required),
+	)
+}
+
+func (r *verifyRequest) sanitize() {
+	r.Token = strings.TrimSpace(r.Token)
+}
+
+// VerifyEmail marks the email address the token got sent to as verified
+// VerifyEmail godoc
+// @Tags Account
+// @Summary Verify Email
+// @Accept  json
+// @Produce  json
+// @Param request body verifyRequest true "Verify Email"
+// @Success 200 {object} model.User
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /account/verify-email [post]
+func (h *Handler) VerifyEmail(c *gin.Context) {
+	var req verifyRequest
+
+	if valid := bindData(c, &req); !valid {
+		return
+	}
+
+	req.sanitize()
+
+	ctx := c.Request.Context()
+	user, err := h.userService.VerifyEmail(ctx, req.Token)
+
+	if err != nil {
+		if err.Error() == apperrors.NewBadRequest(apperrors.InvalidVerifyToken).Error() {
+			toFieldErrorResponse(c, "Token", apperrors.InvalidVerifyToken)
+			return
+		}
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
 
 	c.JSON(http.StatusOK, user)
 }
@@ -15,6 +15,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestHandler_Register(t *testing.T) {
@@ -260,6 +261,9 @@ func TestHandler_Register(t *testing.T) {
 		mockUserService.
 			On("Register", u).
 			Return(reqUser, nil)
+		mockUserService.
+			On("SendVerificationMail", mock.Anything, reqUser).
+			Return(nil)
 
 		// a response recorder for getting written http response
 		rr := httptest.NewRecorder()
@@ -399,6 +403,45 @@ func TestHandler_Login(t *testing.T) {
 
 		mockUserService.AssertCalled(t, "Login", mockUSArgs...)
 	})
+
+	t.Run("Two-factor authentication required", func(t *testing.T) {
+		user := fixture.GetMockUser()
+		user.TwoFactorEnabled = true
+
+		mockUSArgs := mock.Arguments{
+			user.Email,
+			user.Password,
+		}
+
+		mockUserService.On("Login", mockUSArgs...).Return(user, nil)
+		mockUserService.On("StartTwoFactorLogin", mock.Anything, user).Return("ticket", nil)
+
+		rr := httptest.NewRecorder()
+
+		reqBody, err := json.Marshal(gin.H{
+			"email":    user.Email,
+			"password": user.Password,
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/api/account/login", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(model.TwoFactorChallenge{
+			TwoFactorRequired: true,
+			Ticket:            "ticket",
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusAccepted, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		assert.Empty(t, rr.Header().Get("Set-Cookie"))
+
+		mockUserService.AssertCalled(t, "StartTwoFactorLogin", mock.Anything, user)
+	})
 }
 
 func TestHandler_Logout(t *testing.T) {
@@ -717,6 +760,47 @@ func TestHandler_ResetPassword(t *testing.T) {
 		assert.Equal(t, respBody, rr.Body.Bytes())
 		mockUserService.AssertCalled(t, "ResetPassword", ResetPasswordArgs...)
 	})
+
+	t.Run("Two-factor authentication required", func(t *testing.T) {
+		user := fixture.GetMockUser()
+		user.TwoFactorEnabled = true
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("ResetPassword", mock.Anything, user.Password, token).Return(user, nil)
+		mockUserService.On("StartTwoFactorLogin", mock.Anything, user).Return("ticket", nil)
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:            router,
+			UserService:  mockUserService,
+			MaxBodyBytes: 4 * 1024 * 1024,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, err := json.Marshal(gin.H{
+			"token":              token,
+			"newPassword":        user.Password,
+			"confirmNewPassword": user.Password,
+		})
+		assert.NoError(t, err)
+
+		request, _ := http.NewRequest(http.MethodPost, "/api/account/reset-password", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(model.TwoFactorChallenge{
+			TwoFactorRequired: true,
+			Ticket:            "ticket",
+		})
+
+		assert.Equal(t, http.StatusAccepted, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		assert.Empty(t, rr.Header().Get("Set-Cookie"))
+		mockUserService.AssertExpectations(t)
+	})
 }
 
 func TestHandler_ResetPassword_BadRequest(t *testing.T) {
@@ -824,3 +908,106 @@ func TestHandler_ResetPassword_BadRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_VerifyEmail(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Token required", func(t *testing.T) {
+		mockUserService := new(mocks.UserService)
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:           router,
+			UserService: mockUserService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, err := json.Marshal(gin.H{})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/api/account/verify-email", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockUserService.AssertNotCalled(t, "VerifyEmail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		mockUserService := new(mocks.UserService)
+		mockUserService.
+			On("VerifyEmail", mock.Anything, "token").
+			Return(nil, apperrors.NewBadRequest(apperrors.InvalidVerifyToken))
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:           router,
+			UserService: mockUserService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, err := json.Marshal(gin.H{
+			"token": "token",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/api/account/verify-email", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(getTestFieldErrorResponse("Token", apperrors.InvalidVerifyToken))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		user := fixture.GetMockUser()
+		verifiedAt := time.Now()
+		user.EmailVerifiedAt = &verifiedAt
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.
+			On("VerifyEmail", mock.Anything, "token").
+			Return(user, nil)
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:           router,
+			UserService: mockUserService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, err := json.Marshal(gin.H{
+			"token": " token ",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/api/account/verify-email", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(user)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockUserService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,367 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+/*
+ * BotHandler contains all routes related to bot accounts (/api/bots)
+ */
+
+// GetBots returns the bots of the current user
+// GetBots godoc
+// @Tags Bots
+// @Summary Get Current User's Bots
+// @Produce  json
+// @Success 200 {array} model.BotResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /bots [get]
+func (h *Handler) GetBots(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+
+	bots, err := h.botService.GetBots(userId)
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	response := make([]model.BotResponse, 0)
+	for i := range *bots {
+		response = append(response, model.NewBotResponse(&(*bots)[i], ""))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+type botReq struct {
+	// Min 3, max 30 characters.
+	Username string `json:"username"`
+	// Optional url that receives the interactions instead of the gateway. Ignored when creating
+	InteractionsUrl *string `json:"interactionsUrl"`
+} //@name BotRequest
+
+func (r botReq) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Username, validation.Required, validation.Length(3, 30)),
+		validation.Field(&r.InteractionsUrl, is.RequestURL, validation.Length(0, 2000), validation.By(isHttpsUrl)),
+	)
+}
+
+// isHttpsUrl checks that the interactions get sent encrypted
+func isHttpsUrl(value interface{}) error {
+	value, _ = validation.Indirect(value)
+	rawUrl, _ := value.(string)
+
+	if strings.TrimSpace(rawUrl) == "" {
+		return nil
+	}
+
+	parsed, err := url.Parse(strings.TrimSpace(rawUrl))
+
+	if err != nil || parsed.Scheme != "https" {
+		return errors.New(apperrors.InteractionsUrlScheme)
+	}
+
+	return nil
+}
+
+func (r *botReq) sanitize() {
+	r.Username = strings.TrimSpace(r.Username)
+
+	if r.InteractionsUrl != nil {
+		url := strings.TrimSpace(*r.InteractionsUrl)
+		r.InteractionsUrl = &url
+
+		// An empty url sends the interactions over the gateway again
+		if url == "" {
+			r.InteractionsUrl = nil
+		}
+	}
+}
+
+// CreateBot creates a bot owned by the current user.
+// The bot token is only returned in this response.
+// CreateBot godoc
+// @Tags Bots
+// @Summary Create Bot
+// @Accept  json
+// @Produce  json
+// @Param request body botReq true "Create Bot"
+// @Success 201 {object} model.BotResponse
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /bots [post]
+func (h *Handler) CreateBot(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+	var req botReq
+
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	req.sanitize()
+
+	authUser, err := h.userService.Get(userId)
+
+	if err != nil {
+		e := apperrors.NewAuthorization(apperrors.InvalidSession)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	bot, token, err := h.botService.CreateBot(authUser, req.Username)
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.NewBotResponse(bot, token))
+}
+
+// EditBot changes the username and the interactions url of the given bot
+// EditBot godoc
+// @Tags Bots
+// @Summary Edit Bot
+// @Accept  json
+// @Produce  json
+// @Param botId path string true "Bot ID"
+// @Param request body botReq true "Edit Bot"
+// @Success 200 {object} model.BotResponse
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /bots/{botId} [put]
+func (h *Handler) EditBot(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+	botId := c.Param("botId")
+	var req botReq
+
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	req.sanitize()
+
+	bot, err := h.botService.GetBot(userId, botId)
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	bot.Username = req.Username
+	bot.InteractionsUrl = req.InteractionsUrl
+
+	if err = h.botService.UpdateBot(bot); err != nil {
+		log.Printf("Failed to update bot: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.NewBotResponse(bot, ""))
+}
+
+// ResetBotToken revokes the token of the given bot and returns a new one
+// ResetBotToken godoc
+// @Tags Bots
+// @Summary Reset Bot Token
+// @Produce  json
+// @Param botId path string true "Bot ID"
+// @Success 200 {object} model.BotResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /bots/{botId}/token [post]
+func (h *Handler) ResetBotToken(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+	botId := c.Param("botId")
+
+	bot, err := h.botService.GetBot(userId, botId)
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	token, err := h.botService.ResetBotToken(bot)
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	h.disconnectBot(bot.ID)
+
+	c.JSON(http.StatusOK, model.NewBotResponse(bot, token))
+}
+
+// DeleteBot deletes the given bot together with its messages
+// DeleteBot godoc
+// @Tags Bots
+// @Summary Delete Bot
+// @Produce  json
+// @Param botId path string true "Bot ID"
+// @Success 200 {object} model.Success
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /bots/{botId} [delete]
+func (h *Handler) DeleteBot(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+	botId := c.Param("botId")
+
+	bot, err := h.botService.GetBot(userId, botId)
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if err = h.botService.DeleteBot(bot); err != nil {
+		log.Printf("Failed to delete bot: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	h.disconnectBot(bot.ID)
+
+	c.JSON(http.StatusOK, true)
+}
+
+// disconnectBot closes the websocket connections of the bot after its token got revoked.
+// Bots connect with their token, so their connections do not belong to a session.
+func (h *Handler) disconnectBot(botId string) {
+	h.socketService.DisconnectSessions(botId, []string{""})
+}
+
+// addBotReq contains the ID of the bot that gets added
+type addBotReq struct {
+	BotId string `json:"botId"`
+} //@name AddBotRequest
+
+func (r addBotReq) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.BotId, validation.Required, is.UTFDigit),
+	)
+}
+
+// AddBot adds the given bot to the given guild.
+// The user needs the Manage Server permission but does not have to own the bot.
+// AddBot godoc
+// @Tags Guilds
+// @Summary Add Bot
+// @Accept  json
+// @Produce  json
+// @Param guildId path string true "Guild ID"
+// @Param request body addBotReq true "Add Bot"
+// @Success 201 {object} model.Success
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /guilds/{guildId}/bots [post]
+func (h *Handler) AddBot(c *gin.Context) {
+	var req addBotReq
+
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	guildId := c.Param("guildId")
+	guild, err := h.guildService.GetGuild(guildId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("guild", guildId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	userId := c.MustGet("userId").(string)
+
+	if !h.guildService.HasPermission(userId, guild.ID, model.PermissionManageGuild) {
+		e := apperrors.NewAuthorization(apperrors.MissingPermissions)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	bot, err := h.botService.GetBotById(req.BotId)
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if isBanned(guild, bot.ID) {
+		e := apperrors.NewBadRequest(apperrors.BannedFromServer)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	if isMember(guild, bot.ID) {
+		e := apperrors.NewBadRequest(apperrors.AlreadyMember)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	guild.Members = append(guild.Members, *bot)
+
+	if err = h.guildService.UpdateGuild(guild); err != nil {
+		log.Printf("Failed to add bot: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	channel, _ := h.guildService.GetDefaultChannel(guildId)
+	response := guild.SerializeGuild(channel.ID)
+
+	// Emit the new member to the guild and the guild to the bot
+	h.socketService.EmitAddMember(guild.ID, bot)
+	h.socketService.EmitAddToGuild(bot.ID, &response)
+
+	c.JSON(http.StatusCreated, true)
+}
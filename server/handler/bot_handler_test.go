@@ -0,0 +1,402 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_CreateBot(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authUser := fixture.GetMockUser()
+
+	t.Run("Success", func(t *testing.T) {
+		mockBot := fixture.GetMockBot(authUser.ID)
+		mockBot.Username = "Helper"
+		token := model.AccessTokenPrefix + "abcdef"
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
+
+		mockBotService := new(mocks.BotService)
+		mockBotService.On("CreateBot", authUser, "Helper").Return(mockBot, token, nil)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:           router,
+			UserService: mockUserService,
+			BotService:  mockBotService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"username": " Helper ",
+		})
+
+		request, _ := http.NewRequest(http.MethodPost, "/api/bots", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(model.NewBotResponse(mockBot, token))
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockBotService.AssertExpectations(t)
+	})
+
+	t.Run("Username too short", func(t *testing.T) {
+		mockBotService := new(mocks.BotService)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:          router,
+			BotService: mockBotService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"username": "ab",
+		})
+
+		request, _ := http.NewRequest(http.MethodPost, "/api/bots", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockBotService.AssertNotCalled(t, "CreateBot", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Bot tokens cannot manage bots", func(t *testing.T) {
+		mockTokenService := new(mocks.AccessTokenService)
+		mockBotService := new(mocks.BotService)
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:            router,
+			TokenService: mockTokenService,
+			BotService:   mockBotService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		request, _ := http.NewRequest(http.MethodGet, "/api/bots", nil)
+		request.Header.Set("Authorization", "Bearer "+model.AccessTokenPrefix+"abcdef")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		mockBotService.AssertNotCalled(t, "GetBots", mock.Anything)
+	})
+}
+
+func TestHandler_EditBot(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authUser := fixture.GetMockUser()
+
+	t.Run("Interactions url without https", func(t *testing.T) {
+		mockBot := fixture.GetMockBot(authUser.ID)
+
+		mockBotService := new(mocks.BotService)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:          router,
+			BotService: mockBotService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"username":        "Helper",
+			"interactionsUrl": "http://169.254.169.254/latest/meta-data",
+		})
+
+		request, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/api/bots/%s", mockBot.ID), bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(getTestFieldErrorResponse("interactionsUrl", apperrors.InteractionsUrlScheme+"."))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockBotService.AssertNotCalled(t, "GetBot", mock.Anything, mock.Anything)
+		mockBotService.AssertNotCalled(t, "UpdateBot", mock.Anything)
+	})
+}
+
+func TestHandler_ResetBotToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authUser := fixture.GetMockUser()
+	mockBot := fixture.GetMockBot(authUser.ID)
+	token := model.AccessTokenPrefix + "abcdef"
+
+	mockBotService := new(mocks.BotService)
+	mockBotService.On("GetBot", authUser.ID, mockBot.ID).Return(mockBot, nil)
+	mockBotService.On("ResetBotToken", mockBot).Return(token, nil)
+
+	mockSocketService := new(mocks.SocketService)
+	mockSocketService.On("DisconnectSessions", mockBot.ID, []string{""}).Return()
+
+	router := getAuthenticatedTestRouter(authUser.ID)
+
+	NewHandler(&Config{
+		R:             router,
+		BotService:    mockBotService,
+		SocketService: mockSocketService,
+	})
+
+	rr := httptest.NewRecorder()
+
+	request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/bots/%s/token", mockBot.ID), nil)
+
+	router.ServeHTTP(rr, request)
+
+	respBody, _ := json.Marshal(model.NewBotResponse(mockBot, token))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, respBody, rr.Body.Bytes())
+	mockBotService.AssertExpectations(t)
+	mockSocketService.AssertExpectations(t)
+}
+
+func TestHandler_AddBot(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authUser := fixture.GetMockUser()
+
+	t.Run("Success", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild(authUser.ID)
+		mockBot := fixture.GetMockBot(authUser.ID)
+		mockChannel := fixture.GetMockChannel(mockGuild.ID)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageGuild).Return(true)
+		mockGuildService.On("UpdateGuild", mockGuild).Return(nil)
+		mockGuildService.On("GetDefaultChannel", mockGuild.ID).Return(mockChannel, nil)
+
+		mockBotService := new(mocks.BotService)
+		mockBotService.On("GetBotById", mockBot.ID).Return(mockBot, nil)
+
+		response := mockGuild.SerializeGuild(mockChannel.ID)
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitAddMember", mockGuild.ID, mockBot).Return()
+		mockSocketService.On("EmitAddToGuild", mockBot.ID, &response).Return()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:             router,
+			GuildService:  mockGuildService,
+			BotService:    mockBotService,
+			SocketService: mockSocketService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"botId": mockBot.ID,
+		})
+
+		request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/guilds/%s/bots", mockGuild.ID), bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(true)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		assert.Len(t, mockGuild.Members, 1)
+		mockGuildService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
+	t.Run("Missing permission", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockBot := fixture.GetMockBot(authUser.ID)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageGuild).Return(false)
+
+		mockBotService := new(mocks.BotService)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:            router,
+			GuildService: mockGuildService,
+			BotService:   mockBotService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"botId": mockBot.ID,
+		})
+
+		request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/guilds/%s/bots", mockGuild.ID), bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(gin.H{
+			"error": apperrors.NewAuthorization(apperrors.MissingPermissions),
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockBotService.AssertNotCalled(t, "GetBotById", mock.Anything)
+		mockGuildService.AssertNotCalled(t, "UpdateGuild", mock.Anything)
+	})
+
+	t.Run("Not the bot's owner", func(t *testing.T) {
+		// The guild member manages the guild but the bot belongs to someone else
+		member := fixture.GetMockUser()
+		mockGuild := fixture.GetMockGuild(authUser.ID)
+		mockBot := fixture.GetMockBot(authUser.ID)
+		mockChannel := fixture.GetMockChannel(mockGuild.ID)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", member.ID, mockGuild.ID, model.PermissionManageGuild).Return(true)
+		mockGuildService.On("UpdateGuild", mockGuild).Return(nil)
+		mockGuildService.On("GetDefaultChannel", mockGuild.ID).Return(mockChannel, nil)
+
+		mockBotService := new(mocks.BotService)
+		mockBotService.On("GetBotById", mockBot.ID).Return(mockBot, nil)
+
+		response := mockGuild.SerializeGuild(mockChannel.ID)
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitAddMember", mockGuild.ID, mockBot).Return()
+		mockSocketService.On("EmitAddToGuild", mockBot.ID, &response).Return()
+
+		router := getAuthenticatedTestRouter(member.ID)
+
+		NewHandler(&Config{
+			R:             router,
+			GuildService:  mockGuildService,
+			BotService:    mockBotService,
+			SocketService: mockSocketService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"botId": mockBot.ID,
+		})
+
+		request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/guilds/%s/bots", mockGuild.ID), bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(true)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		assert.Len(t, mockGuild.Members, 1)
+		mockGuildService.AssertExpectations(t)
+		mockBotService.AssertNotCalled(t, "GetBot", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown bot", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild(authUser.ID)
+		botId := fixture.RandID()
+		mockError := apperrors.NewNotFound("bot", botId)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageGuild).Return(true)
+
+		mockBotService := new(mocks.BotService)
+		mockBotService.On("GetBotById", botId).Return(nil, mockError)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:            router,
+			GuildService: mockGuildService,
+			BotService:   mockBotService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"botId": botId,
+		})
+
+		request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/guilds/%s/bots", mockGuild.ID), bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(gin.H{
+			"error": mockError,
+		})
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertNotCalled(t, "UpdateGuild", mock.Anything)
+	})
+}
+
+func TestHandler_JoinGuild_Bot(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockBot := fixture.GetMockBot(fixture.RandID())
+
+	mockGuildService := new(mocks.GuildService)
+	mockGuildService.On("GetUser", mockBot.ID).Return(mockBot, nil)
+
+	router := getAuthenticatedTestRouter(mockBot.ID)
+
+	NewHandler(&Config{
+		R:            router,
+		GuildService: mockGuildService,
+	})
+
+	rr := httptest.NewRecorder()
+
+	reqBody, _ := json.Marshal(gin.H{
+		"link": "abcdefgh",
+	})
+
+	request, _ := http.NewRequest(http.MethodPost, "/api/guilds/join", bytes.NewBuffer(reqBody))
+	request.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(rr, request)
+
+	respBody, _ := json.Marshal(gin.H{
+		"error": apperrors.NewBadRequest(apperrors.BotInviteError),
+	})
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, respBody, rr.Body.Bytes())
+	mockGuildService.AssertNotCalled(t, "GetGuildIdFromInvite", mock.Anything, mock.Anything)
+}
@@ -124,8 +124,8 @@ func (h *Handler) CreateChannel(c *gin.Context) {
 		return
 	}
 
-	if guild.OwnerId != userId {
-		e := apperrors.NewAuthorization(apperrors.MustBeOwner)
+	if !h.guildService.HasPermission(userId, guild.ID, model.PermissionManageChannels) {
+		e := apperrors.NewAuthorization(apperrors.MissingPermissions)
 
 		c.JSON(e.Status(), gin.H{
 			"error": e,
@@ -250,8 +250,8 @@ func (h *Handler) PrivateChannelMembers(c *gin.Context) {
 		return
 	}
 
-	if guild.OwnerId != userId {
-		e := apperrors.NewAuthorization(apperrors.MustBeOwner)
+	if !h.guildService.HasPermission(userId, guild.ID, model.PermissionManageChannels) {
+		e := apperrors.NewAuthorization(apperrors.MissingPermissions)
 		c.JSON(e.Status(), gin.H{
 			"error": e,
 		})
@@ -456,6 +456,16 @@ func (h *Handler) EditChannel(c *gin.Context) {
 		return
 	}
 
+	// Threads are managed through the thread routes
+	if channel.IsThread() {
+		e := apperrors.NewBadRequest(apperrors.ThreadChannelError)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
 	guild, err := h.guildService.GetGuild(*channel.GuildID)
 
 	if err != nil {
@@ -467,8 +477,8 @@ func (h *Handler) EditChannel(c *gin.Context) {
 		return
 	}
 
-	if guild.OwnerId != userId {
-		e := apperrors.NewAuthorization(apperrors.MustBeOwner)
+	if !h.guildService.HasPermission(userId, guild.ID, model.PermissionManageChannels) {
+		e := apperrors.NewAuthorization(apperrors.MissingPermissions)
 		c.JSON(e.Status(), gin.H{
 			"error": e,
 		})
@@ -582,6 +592,16 @@ func (h *Handler) DeleteChannel(c *gin.Context) {
 		return
 	}
 
+	// Threads are managed through the thread routes
+	if channel.IsThread() {
+		e := apperrors.NewBadRequest(apperrors.ThreadChannelError)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
 	guild, err := h.guildService.GetGuild(*channel.GuildID)
 
 	if err != nil {
@@ -593,8 +613,8 @@ func (h *Handler) DeleteChannel(c *gin.Context) {
 		return
 	}
 
-	if guild.OwnerId != userId {
-		e := apperrors.NewAuthorization(apperrors.MustBeOwner)
+	if !h.guildService.HasPermission(userId, guild.ID, model.PermissionManageChannels) {
+		e := apperrors.NewAuthorization(apperrors.MissingPermissions)
 		c.JSON(e.Status(), gin.H{
 			"error": e,
 		})
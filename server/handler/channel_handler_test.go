@@ -220,6 +220,7 @@ func TestHandler_CreateChannel(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(true)
 		mockGuildService.On("UpdateGuild", mockGuild).Return(nil)
 
 		mockChannelService := new(mocks.ChannelService)
@@ -330,6 +331,7 @@ func TestHandler_CreateChannel(t *testing.T) {
 		mockGuildService := new(mocks.GuildService)
 
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(true)
 
 		mockChannelService := new(mocks.ChannelService)
 		mockSocketService := new(mocks.SocketService)
@@ -378,6 +380,7 @@ func TestHandler_CreateChannel(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(false)
 
 		mockChannelService := new(mocks.ChannelService)
 		mockSocketService := new(mocks.SocketService)
@@ -405,7 +408,7 @@ func TestHandler_CreateChannel(t *testing.T) {
 
 		request.Header.Set("Content-Type", "application/json")
 
-		mockError := apperrors.NewAuthorization(apperrors.MustBeOwner)
+		mockError := apperrors.NewAuthorization(apperrors.MissingPermissions)
 		respBody, _ := json.Marshal(gin.H{
 			"error": mockError,
 		})
@@ -474,6 +477,7 @@ func TestHandler_CreateChannel(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(true)
 
 		mockChannelService := new(mocks.ChannelService)
 
@@ -534,6 +538,7 @@ func TestHandler_CreateChannel(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(true)
 		mockGuildService.On("UpdateGuild", mockGuild).Return(nil)
 		mockGuildService.On("FindUsersByIds", reqMembers, mockGuild.ID).Return(&members, nil)
 
@@ -663,6 +668,7 @@ func TestHandler_PrivateChannelMembers(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(true)
 
 		mockChannelService := new(mocks.ChannelService)
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
@@ -784,6 +790,7 @@ func TestHandler_PrivateChannelMembers(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(false)
 
 		mockChannelService := new(mocks.ChannelService)
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
@@ -802,7 +809,7 @@ func TestHandler_PrivateChannelMembers(t *testing.T) {
 		request, err := http.NewRequest(http.MethodGet, url, nil)
 		assert.NoError(t, err)
 
-		e := apperrors.NewAuthorization(apperrors.MustBeOwner)
+		e := apperrors.NewAuthorization(apperrors.MissingPermissions)
 		respBody, err := json.Marshal(gin.H{
 			"error": e,
 		})
@@ -862,6 +869,7 @@ func TestHandler_PrivateChannelMembers(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(true)
 
 		mockChannelService := new(mocks.ChannelService)
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
@@ -1270,6 +1278,7 @@ func TestHandler_EditChannel(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(true)
 
 		mockChannelService := new(mocks.ChannelService)
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
@@ -1418,6 +1427,7 @@ func TestHandler_EditChannel(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(false)
 
 		mockChannelService := new(mocks.ChannelService)
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
@@ -1447,7 +1457,7 @@ func TestHandler_EditChannel(t *testing.T) {
 
 		request.Header.Set("Content-Type", "application/json")
 
-		e := apperrors.NewAuthorization(apperrors.MustBeOwner)
+		e := apperrors.NewAuthorization(apperrors.MissingPermissions)
 		respBody, _ := json.Marshal(gin.H{
 			"error": e,
 		})
@@ -1517,6 +1527,7 @@ func TestHandler_EditChannel(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(true)
 
 		mockChannelService := new(mocks.ChannelService)
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
@@ -1571,6 +1582,7 @@ func TestHandler_EditChannel(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(true)
 
 		mockChannelService := new(mocks.ChannelService)
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
@@ -1629,6 +1641,7 @@ func TestHandler_EditChannel(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(true)
 
 		mockChannelService := new(mocks.ChannelService)
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
@@ -1757,6 +1770,7 @@ func TestHandler_DeleteChannel(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(true)
 
 		mockChannelService := new(mocks.ChannelService)
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
@@ -1887,6 +1901,7 @@ func TestHandler_DeleteChannel(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", *mockChannel.GuildID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(false)
 
 		mockChannelService := new(mocks.ChannelService)
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
@@ -1911,7 +1926,7 @@ func TestHandler_DeleteChannel(t *testing.T) {
 
 		request.Header.Set("Content-Type", "application/json")
 
-		e := apperrors.NewAuthorization(apperrors.MustBeOwner)
+		e := apperrors.NewAuthorization(apperrors.MissingPermissions)
 		respBody, _ := json.Marshal(gin.H{
 			"error": e,
 		})
@@ -1933,6 +1948,7 @@ func TestHandler_DeleteChannel(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", *mockChannel.GuildID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(true)
 
 		mockChannelService := new(mocks.ChannelService)
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
@@ -2022,6 +2038,7 @@ func TestHandler_DeleteChannel(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(true)
 
 		mockChannelService := new(mocks.ChannelService)
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
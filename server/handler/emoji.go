@@ -0,0 +1,39 @@
+package handler
+
+import "unicode"
+
+const (
+	zeroWidthJoiner   = '\u200d'
+	variationSelector = '\ufe0f'
+	keycapCombiner    = '\u20e3'
+	maxEmojiLength    = 32
+)
+
+// isEmoji determines if the value is a unicode emoji.
+// Sequences using zero width joiners, skin tones, keycaps and flags are allowed.
+func isEmoji(value string) bool {
+	if value == "" || len(value) > maxEmojiLength {
+		return false
+	}
+
+	symbols := 0
+	for _, r := range value {
+		switch {
+		case r == keycapCombiner:
+			symbols++
+		case r == zeroWidthJoiner || r == variationSelector:
+		// Skin tone modifiers
+		case r >= 0x1F3FB && r <= 0x1F3FF:
+		// Tags used in subdivision flags
+		case r >= 0xE0020 && r <= 0xE007F:
+		// Keycap bases
+		case r == '#' || r == '*' || (r >= '0' && r <= '9'):
+		case unicode.Is(unicode.So, r):
+			symbols++
+		default:
+			return false
+		}
+	}
+
+	return symbols > 0
+}
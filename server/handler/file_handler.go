@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// GetImage redirects to the requested size of an avatar or guild icon
+// GetImage godoc
+// @Tags Files
+// @Summary Get Image
+// @Param filepath path string true "Image Path"
+// @Param size query int false "Size of the image (64, 128, 256 or 512), defaults to 128"
+// @Success 302
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /images/{filepath} [get]
+func (h *Handler) GetImage(c *gin.Context) {
+	size := model.DefaultImageSize
+
+	if value := c.Query("size"); value != "" {
+		parsed, err := strconv.Atoi(value)
+
+		if err != nil || !model.IsImageSize(parsed) {
+			toFieldErrorResponse(c, "size", apperrors.InvalidImageSize)
+			return
+		}
+
+		size = parsed
+	}
+
+	url, err := h.userService.GetImageUrl(strings.TrimPrefix(c.Param("filepath"), "/"), size)
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Redirect(http.StatusFound, url)
+}
+
+// GetFile serves a file stored by the local storage driver with the mimetype it got stored with.
+// Only images, videos and audio files are shown in the browser, all other files get downloaded.
+// GetFile godoc
+// @Tags Files
+// @Summary Get File
+// @Produce octet-stream
+// @Param filepath path string true "File Path"
+// @Param signature query string true "Signature of the file url"
+// @Success 200 {file} binary
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /files/{filepath} [get]
+func (h *Handler) GetFile(c *gin.Context) {
+	key := "files" + c.Param("filepath")
+
+	file, mimetype, err := h.fileServer.Open(key, c.Query("signature"))
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+
+	if err != nil {
+		log.Printf("Failed to stat file: %v\n", err.Error())
+		e := apperrors.NewInternal()
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	if info.IsDir() {
+		e := apperrors.NewNotFound("file", key)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// The extension of the key is chosen by the uploader, so it must not decide the content type
+	contentType := model.NormalizeFileType(mimetype)
+
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("X-Content-Type-Options", "nosniff")
+
+	if !model.IsInlineFileType(contentType) {
+		c.Header("Content-Disposition", "attachment")
+	}
+
+	// Stored files never change as every upload gets a new key
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	http.ServeContent(c.Writer, c.Request, info.Name(), info.ModTime(), file)
+}
+
+// PutFile stores a file uploaded to a presigned url of the local storage driver
+// PutFile godoc
+// @Tags Files
+// @Summary Upload File
+// @Accept octet-stream
+// @Param filepath path string true "File Path"
+// @Param expires query int true "Expiration time of the upload url"
+// @Param signature query string true "Signature of the upload url"
+// @Success 200
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /files/{filepath} [put]
+func (h *Handler) PutFile(c *gin.Context) {
+	key := "files" + c.Param("filepath")
+	expires, _ := strconv.ParseInt(c.Query("expires"), 10, 64)
+
+	err := h.fileServer.Put(
+		key,
+		c.GetHeader("Content-Type"),
+		c.Request.ContentLength,
+		expires,
+		c.Query("signature"),
+		c.Request.Body,
+	)
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,330 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHandler_GetFile(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	key := "files/channels/abcde/abcde-file.txt"
+	content := []byte("Hello World")
+
+	t.Run("Successful Fetch", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "abcde-file.txt")
+		assert.NoError(t, os.WriteFile(name, content, 0644))
+
+		file, err := os.Open(name)
+		assert.NoError(t, err)
+
+		mockFileServer := new(mocks.FileServer)
+		mockFileServer.On("Open", key, "signature").Return(file, "text/plain", nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:          router,
+			FileServer: mockFileServer,
+		})
+
+		request, err := http.NewRequest(http.MethodGet, "/"+key+"?signature=signature", nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, content, rr.Body.Bytes())
+		assert.Equal(t, "text/plain", rr.Header().Get("Content-Type"))
+		assert.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "attachment", rr.Header().Get("Content-Disposition"))
+		assert.Contains(t, rr.Header().Get("Cache-Control"), "immutable")
+		mockFileServer.AssertExpectations(t)
+	})
+
+	t.Run("Stored type instead of the extension", func(t *testing.T) {
+		htmlKey := "files/channels/abcde/abcde-page.html"
+		name := filepath.Join(t.TempDir(), "abcde-page.html")
+		assert.NoError(t, os.WriteFile(name, []byte("<script>alert(1)</script>"), 0644))
+
+		file, err := os.Open(name)
+		assert.NoError(t, err)
+
+		mockFileServer := new(mocks.FileServer)
+		mockFileServer.On("Open", htmlKey, "signature").Return(file, "text/plain", nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:          router,
+			FileServer: mockFileServer,
+		})
+
+		request, err := http.NewRequest(http.MethodGet, "/"+htmlKey+"?signature=signature", nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "text/plain", rr.Header().Get("Content-Type"))
+		assert.Equal(t, "attachment", rr.Header().Get("Content-Disposition"))
+	})
+
+	t.Run("Images are shown inline", func(t *testing.T) {
+		imageKey := "files/channels/abcde/abcde-image.png"
+		name := filepath.Join(t.TempDir(), "abcde-image.png")
+		assert.NoError(t, os.WriteFile(name, content, 0644))
+
+		file, err := os.Open(name)
+		assert.NoError(t, err)
+
+		mockFileServer := new(mocks.FileServer)
+		mockFileServer.On("Open", imageKey, "signature").Return(file, "image/png", nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:          router,
+			FileServer: mockFileServer,
+		})
+
+		request, err := http.NewRequest(http.MethodGet, "/"+imageKey+"?signature=signature", nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "image/png", rr.Header().Get("Content-Type"))
+		assert.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+		assert.Empty(t, rr.Header().Get("Content-Disposition"))
+	})
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		mockFileServer := new(mocks.FileServer)
+		mockError := apperrors.NewNotFound("file", key)
+		mockFileServer.On("Open", key, "invalid").Return(nil, "", mockError)
+
+		rr := httptest.NewRecorder()
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:          router,
+			FileServer: mockFileServer,
+		})
+
+		request, err := http.NewRequest(http.MethodGet, "/"+key+"?signature=invalid", nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockFileServer.AssertExpectations(t)
+	})
+
+	t.Run("No route without a file server", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R: router,
+		})
+
+		request, err := http.NewRequest(http.MethodGet, "/"+key+"?signature=signature", nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestHandler_PutFile(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	key := "files/channels/abcde/abcde-file.txt"
+	content := "Hello World"
+
+	t.Run("Successful upload", func(t *testing.T) {
+		mockFileServer := new(mocks.FileServer)
+		mockFileServer.
+			On("Put", key, "text/plain", int64(len(content)), int64(1700000000), "signature", mock.Anything).
+			Return(nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:          router,
+			FileServer: mockFileServer,
+		})
+
+		request, err := http.NewRequest(http.MethodPut, "/"+key+"?expires=1700000000&signature=signature", strings.NewReader(content))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "text/plain")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockFileServer.AssertExpectations(t)
+	})
+
+	t.Run("Expired upload url", func(t *testing.T) {
+		mockFileServer := new(mocks.FileServer)
+		mockError := apperrors.NewAuthorization(apperrors.UploadUrlError)
+		mockFileServer.
+			On("Put", key, "text/plain", int64(len(content)), int64(0), "signature", mock.Anything).
+			Return(mockError)
+
+		rr := httptest.NewRecorder()
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:          router,
+			FileServer: mockFileServer,
+		})
+
+		request, err := http.NewRequest(http.MethodPut, "/"+key+"?signature=signature", strings.NewReader(content))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "text/plain")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockFileServer.AssertExpectations(t)
+	})
+}
+
+func TestHandler_GetImage(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	path := "valkyrie/users/abcde/abcde.gif"
+
+	testCases := []struct {
+		name  string
+		query string
+		size  int
+	}{
+		{name: "Default size", query: "", size: model.DefaultImageSize},
+		{name: "Requested size", query: "?size=512", size: 512},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			sizeUrl := "https://bucket.s3.amazonaws.com/files/valkyrie/users/abcde/abcde/" + fmt.Sprint(tc.size) + ".gif"
+
+			mockUserService := new(mocks.UserService)
+			mockUserService.On("GetImageUrl", path, tc.size).Return(sizeUrl, nil)
+
+			rr := httptest.NewRecorder()
+
+			router := getTestRouter()
+
+			NewHandler(&Config{
+				R:           router,
+				UserService: mockUserService,
+			})
+
+			request, err := http.NewRequest(http.MethodGet, "/images/"+path+tc.query, nil)
+			assert.NoError(t, err)
+
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, http.StatusFound, rr.Code)
+			assert.Equal(t, sizeUrl, rr.Header().Get("Location"))
+			assert.NotEmpty(t, rr.Header().Get("Cache-Control"))
+			mockUserService.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Invalid size", func(t *testing.T) {
+		mockUserService := new(mocks.UserService)
+
+		rr := httptest.NewRecorder()
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:           router,
+			UserService: mockUserService,
+		})
+
+		request, err := http.NewRequest(http.MethodGet, "/images/"+path+"?size=100", nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(getTestFieldErrorResponse("size", apperrors.InvalidImageSize))
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockUserService.AssertNotCalled(t, "GetImageUrl", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown image", func(t *testing.T) {
+		mockError := apperrors.NewNotFound("image", "abcde.txt")
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("GetImageUrl", "abcde.txt", model.DefaultImageSize).Return("", mockError)
+
+		rr := httptest.NewRecorder()
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:           router,
+			UserService: mockUserService,
+		})
+
+		request, err := http.NewRequest(http.MethodGet, "/images/abcde.txt", nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+	})
+}
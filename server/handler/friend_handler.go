@@ -99,6 +99,10 @@ func (h *Handler) SendFriendRequest(c *gin.Context) {
 		return
 	}
 
+	if ok := h.checkVerified(c, authUser, model.RestrictFriendRequests); !ok {
+		return
+	}
+
 	member, err := h.friendService.GetMemberById(memberId)
 
 	if err != nil {
@@ -111,6 +115,14 @@ func (h *Handler) SendFriendRequest(c *gin.Context) {
 		return
 	}
 
+	if member.Bot {
+		e := apperrors.NewBadRequest(apperrors.BotFriendError)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
 	// Check if they are already friends and no request exists
 	if !isFriend(authUser, member.ID) && !containsRequest(authUser, member) {
 		authUser.Requests = append(authUser.Requests, *member)
@@ -726,6 +726,44 @@ func TestHandler_SendFriendRequest(t *testing.T) {
 		mockFriendService.AssertExpectations(t)
 		mockSocketService.AssertNotCalled(t, "EmitAddFriendRequest")
 	})
+
+	t.Run("Unverified email is restricted", func(t *testing.T) {
+		mockUser := fixture.GetMockUser()
+		unverified := fixture.GetMockUser()
+
+		mockFriendService := new(mocks.FriendService)
+		mockFriendService.On("GetMemberById", unverified.ID).Return(unverified, nil)
+
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(unverified.ID)
+
+		NewHandler(&Config{
+			R:             router,
+			FriendService: mockFriendService,
+			SocketService: mockSocketService,
+			Restrictions:  model.UnverifiedRestrictions{model.RestrictFriendRequests: true},
+		})
+
+		url := fmt.Sprintf("/api/account/%s/friend", mockUser.ID)
+		request, err := http.NewRequest(http.MethodPost, url, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"error": apperrors.NewAuthorization(apperrors.EmailNotVerified),
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+
+		mockFriendService.AssertNotCalled(t, "SaveRequests", mock.Anything)
+		mockSocketService.AssertNotCalled(t, "EmitAddFriendRequest")
+	})
 }
 
 func TestHandler_RemoveFriend(t *testing.T) {
@@ -199,6 +199,15 @@ func (h *Handler) CreateGuild(c *gin.Context) {
 		return
 	}
 
+	// Guilds need an owner that outlives the bot
+	if authUser.Bot {
+		e := apperrors.NewBadRequest(apperrors.BotGuildError)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
 	// Check if the user is already in 100 guilds
 	if len(authUser.Guilds) >= model.MaximumGuilds {
 		e := apperrors.NewBadRequest(apperrors.GuildLimitReached)
@@ -307,8 +316,8 @@ func (h *Handler) EditGuild(c *gin.Context) {
 		return
 	}
 
-	if guild.OwnerId != userId {
-		e := apperrors.NewAuthorization(apperrors.MustBeOwner)
+	if !h.guildService.HasPermission(userId, guild.ID, model.PermissionManageGuild) {
+		e := apperrors.NewAuthorization(apperrors.MissingPermissions)
 		c.JSON(e.Status(), gin.H{
 			"error": e,
 		})
@@ -319,11 +328,8 @@ func (h *Handler) EditGuild(c *gin.Context) {
 
 	// Guild icon got changed
 	if req.Image != nil {
-		// Validate image mime-type is allowable
-		mimeType := req.Image.Header.Get("Content-Type")
-
-		if valid := isAllowedImageType(mimeType); !valid {
-			toFieldErrorResponse(c, "Image", apperrors.InvalidImageType)
+		// Validate image mime-type, size and content are allowable
+		if ok := h.checkFile(c, "Image", req.Image, true); !ok {
 			return
 		}
 
@@ -331,9 +337,8 @@ func (h *Handler) EditGuild(c *gin.Context) {
 		url, err := h.userService.ChangeAvatar(req.Image, directory)
 
 		if err != nil {
-			e := apperrors.NewInternal()
-			c.JSON(e.Status(), gin.H{
-				"error": e,
+			c.JSON(apperrors.Status(err), gin.H{
+				"error": err,
 			})
 			return
 		}
@@ -465,8 +470,8 @@ func (h *Handler) DeleteGuildInvites(c *gin.Context) {
 		return
 	}
 
-	if guild.OwnerId != userId {
-		e := apperrors.NewAuthorization(apperrors.InvalidateInvitesError)
+	if !h.guildService.HasPermission(userId, guild.ID, model.PermissionManageGuild) {
+		e := apperrors.NewAuthorization(apperrors.MissingPermissions)
 		c.JSON(e.Status(), gin.H{
 			"error": e,
 		})
@@ -536,6 +541,19 @@ func (h *Handler) JoinGuild(c *gin.Context) {
 		return
 	}
 
+	// Bots cannot use invites
+	if authUser.Bot {
+		e := apperrors.NewBadRequest(apperrors.BotInviteError)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	if ok := h.checkVerified(c, authUser, model.RestrictJoinGuilds); !ok {
+		return
+	}
+
 	// Check if the user has reached the guild limit
 	if len(authUser.Guilds) >= model.MaximumGuilds {
 		e := apperrors.NewBadRequest(apperrors.GuildLimitReached)
@@ -661,8 +679,10 @@ func (h *Handler) LeaveGuild(c *gin.Context) {
 // DeleteGuild godoc
 // @Tags Guilds
 // @Summary Delete Guild
+// @Accept  json
 // @Produce  json
 // @Param guildId path string true "Guild ID"
+// @Param request body twoFactorCodeReq false "Required if the owner has two-factor authentication enabled"
 // @Success 200 {object} model.Success
 // @Failure 401 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
@@ -691,6 +711,31 @@ func (h *Handler) DeleteGuild(c *gin.Context) {
 		return
 	}
 
+	authUser, err := h.userService.Get(userId)
+
+	if err != nil {
+		e := apperrors.NewAuthorization(apperrors.InvalidSession)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// Owners with two-factor authentication confirm the deletion with a code
+	if authUser.TwoFactorEnabled {
+		var req twoFactorCodeReq
+
+		if ok := bindData(c, &req); !ok {
+			return
+		}
+
+		req.sanitize()
+
+		if ok := h.checkTwoFactor(c, authUser, req.Code); !ok {
+			return
+		}
+	}
+
 	// Get the ID of all members to emit the deletion to
 	members := make([]string, 0)
 	for _, member := range guild.Members {
@@ -786,6 +786,7 @@ func TestHandler_UpdateGuild(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageGuild).Return(true)
 
 		name := fixture.RandStringRunes(8)
 		form := url.Values{}
@@ -829,6 +830,7 @@ func TestHandler_UpdateGuild(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageGuild).Return(true)
 
 		name := fixture.RandStringRunes(8)
 		form := url.Values{}
@@ -873,6 +875,7 @@ func TestHandler_UpdateGuild(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageGuild).Return(false)
 
 		name := fixture.RandStringRunes(8)
 		form := url.Values{}
@@ -893,7 +896,7 @@ func TestHandler_UpdateGuild(t *testing.T) {
 		assert.NoError(t, err)
 		request.Form = form
 
-		mockError := apperrors.NewAuthorization(apperrors.MustBeOwner)
+		mockError := apperrors.NewAuthorization(apperrors.MissingPermissions)
 		respBody, _ := json.Marshal(gin.H{
 			"error": mockError,
 		})
@@ -1330,6 +1333,7 @@ func TestHandler_DeleteGuildInvites(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageGuild).Return(true)
 
 		mockArgs := mock.Arguments{
 			mock.AnythingOfType("*context.emptyCtx"),
@@ -1396,6 +1400,7 @@ func TestHandler_DeleteGuildInvites(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageGuild).Return(true)
 
 		mockArgs := mock.Arguments{
 			mock.AnythingOfType("*context.emptyCtx"),
@@ -1438,6 +1443,7 @@ func TestHandler_DeleteGuildInvites(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageGuild).Return(false)
 
 		// a response recorder for getting written http response
 		rr := httptest.NewRecorder()
@@ -1455,7 +1461,7 @@ func TestHandler_DeleteGuildInvites(t *testing.T) {
 
 		router.ServeHTTP(rr, request)
 
-		mockError := apperrors.NewAuthorization(apperrors.InvalidateInvitesError)
+		mockError := apperrors.NewAuthorization(apperrors.MissingPermissions)
 		respBody, err := json.Marshal(gin.H{
 			"error": mockError,
 		})
@@ -1896,6 +1902,44 @@ func TestHandler_JoinGuild(t *testing.T) {
 		mockGuildService.AssertNotCalled(t, "UpdateGuild")
 		mockSocketService.AssertNotCalled(t, "EmitAddMember")
 	})
+
+	t.Run("Unverified email is restricted", func(t *testing.T) {
+		unverified := fixture.GetMockUser()
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetUser", unverified.ID).Return(unverified, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(unverified.ID)
+
+		NewHandler(&Config{
+			R:            router,
+			GuildService: mockGuildService,
+			Restrictions: model.UnverifiedRestrictions{model.RestrictJoinGuilds: true},
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"link": fixture.RandID(),
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/api/guilds/join", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"error": apperrors.NewAuthorization(apperrors.EmailNotVerified),
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertNotCalled(t, "GetGuildIdFromInvite", mock.Anything, mock.Anything)
+	})
 }
 
 func TestHandler_LeaveGuild(t *testing.T) {
@@ -2116,8 +2160,12 @@ func TestHandler_DeleteGuild(t *testing.T) {
 
 		router := getAuthenticatedTestRouter(authUser.ID)
 
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
+
 		NewHandler(&Config{
 			R:             router,
+			UserService:   mockUserService,
 			GuildService:  mockGuildService,
 			SocketService: mockSocketService,
 		})
@@ -2270,8 +2318,12 @@ func TestHandler_DeleteGuild(t *testing.T) {
 
 		router := getAuthenticatedTestRouter(authUser.ID)
 
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
+
 		NewHandler(&Config{
 			R:             router,
+			UserService:   mockUserService,
 			GuildService:  mockGuildService,
 			SocketService: mockSocketService,
 		})
@@ -2293,4 +2345,105 @@ func TestHandler_DeleteGuild(t *testing.T) {
 
 		mockSocketService.AssertNotCalled(t, "EmitDeleteGuild")
 	})
+
+	t.Run("Two-factor code required", func(t *testing.T) {
+		owner := fixture.GetMockUser()
+		owner.TwoFactorEnabled = true
+		mockGuild := fixture.GetMockGuild(owner.ID)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", owner.ID).Return(owner, nil)
+		mockUserService.
+			On("VerifyTwoFactor", owner, "123456").
+			Return(apperrors.NewBadRequest(apperrors.InvalidTwoFactor))
+
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(owner.ID)
+
+		NewHandler(&Config{
+			R:             router,
+			UserService:   mockUserService,
+			GuildService:  mockGuildService,
+			SocketService: mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"code": "123456",
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/delete", mockGuild.ID)
+		request, err := http.NewRequest(http.MethodDelete, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(getTestFieldErrorResponse("Code", apperrors.InvalidTwoFactor))
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+
+		mockUserService.AssertExpectations(t)
+		mockGuildService.AssertNotCalled(t, "DeleteGuild", mock.Anything)
+		mockSocketService.AssertNotCalled(t, "EmitDeleteGuild")
+	})
+
+	t.Run("Deleted with a two-factor code", func(t *testing.T) {
+		owner := fixture.GetMockUser()
+		owner.TwoFactorEnabled = true
+		mockGuild := fixture.GetMockGuild(owner.ID)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("DeleteGuild", mockGuild.ID).Return(nil)
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", owner.ID).Return(owner, nil)
+		mockUserService.On("VerifyTwoFactor", owner, "123456").Return(nil)
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitDeleteGuild", mockGuild.ID, make([]string, 0)).Return()
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(owner.ID)
+
+		NewHandler(&Config{
+			R:             router,
+			UserService:   mockUserService,
+			GuildService:  mockGuildService,
+			SocketService: mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"code": "123456",
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/delete", mockGuild.ID)
+		request, err := http.NewRequest(http.MethodDelete, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockUserService.AssertExpectations(t)
+		mockGuildService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
 }
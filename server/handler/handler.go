@@ -19,27 +19,43 @@ import (
 
 // Handler struct holds required services for handler to function
 type Handler struct {
-	userService    model.UserService
-	friendService  model.FriendService
-	guildService   model.GuildService
-	channelService model.ChannelService
-	messageService model.MessageService
-	socketService  model.SocketService
-	MaxBodyBytes   int64
+	userService        model.UserService
+	sessionService     model.SessionService
+	tokenService       model.AccessTokenService
+	botService         model.BotService
+	interactionService model.InteractionService
+	friendService      model.FriendService
+	guildService       model.GuildService
+	channelService     model.ChannelService
+	messageService     model.MessageService
+	webhookService     model.WebhookService
+	socketService      model.SocketService
+	fileServer         model.FileServer
+	fileTypes          model.FileTypes
+	restrictions       model.UnverifiedRestrictions
+	MaxBodyBytes       int64
 }
 
 // Config will hold services that will eventually be injected into this
 // handler layer on handler initialization
 type Config struct {
-	R               *gin.Engine
-	UserService     model.UserService
-	FriendService   model.FriendService
-	GuildService    model.GuildService
-	ChannelService  model.ChannelService
-	MessageService  model.MessageService
-	SocketService   model.SocketService
-	TimeoutDuration time.Duration
-	MaxBodyBytes    int64
+	R                  *gin.Engine
+	UserService        model.UserService
+	SessionService     model.SessionService
+	TokenService       model.AccessTokenService
+	BotService         model.BotService
+	InteractionService model.InteractionService
+	FriendService      model.FriendService
+	GuildService       model.GuildService
+	ChannelService     model.ChannelService
+	MessageService     model.MessageService
+	WebhookService     model.WebhookService
+	SocketService      model.SocketService
+	FileServer         model.FileServer
+	FileTypes          model.FileTypes
+	Restrictions       model.UnverifiedRestrictions
+	TimeoutDuration    time.Duration
+	MaxBodyBytes       int64
 }
 
 // NewHandler initializes the handler with required injected services along with http routes
@@ -48,13 +64,25 @@ func NewHandler(c *Config) {
 
 	// Create a handler (which will later have injected services)
 	h := &Handler{
-		userService:    c.UserService,
-		friendService:  c.FriendService,
-		guildService:   c.GuildService,
-		channelService: c.ChannelService,
-		messageService: c.MessageService,
-		socketService:  c.SocketService,
-		MaxBodyBytes:   c.MaxBodyBytes,
+		userService:        c.UserService,
+		sessionService:     c.SessionService,
+		tokenService:       c.TokenService,
+		botService:         c.BotService,
+		interactionService: c.InteractionService,
+		friendService:      c.FriendService,
+		guildService:       c.GuildService,
+		channelService:     c.ChannelService,
+		messageService:     c.MessageService,
+		webhookService:     c.WebhookService,
+		socketService:      c.SocketService,
+		fileServer:         c.FileServer,
+		fileTypes:          c.FileTypes,
+		restrictions:       c.Restrictions,
+		MaxBodyBytes:       c.MaxBodyBytes,
+	}
+
+	if h.fileTypes == nil {
+		h.fileTypes = model.DefaultFileTypes()
 	}
 
 	c.R.NoRoute(func(c *gin.Context) {
@@ -65,6 +93,28 @@ func NewHandler(c *Config) {
 
 	c.R.Use(static.Serve("/", static.LocalFile("./static", true)))
 
+	// Serve the files of storage drivers without their own file hosting.
+	// Registered before the timeout so large uploads do not get cut off.
+	if h.fileServer != nil {
+		c.R.GET("/files/*filepath", h.GetFile)
+		c.R.PUT("/files/*filepath", h.PutFile)
+	}
+
+	// Avatars and guild icons redirect to the requested size
+	c.R.GET("/images/*filepath", h.GetImage)
+
+	// Resumable uploads are registered before the timeout as well
+	c.R.OPTIONS("api/uploads", middleware.TusResumable(), h.GetTusOptions)
+
+	ug := c.R.Group("api/uploads")
+	ug.Use(middleware.TusResumable())
+	ug.Use(h.authUser(&middleware.TokenScopes{Read: model.ScopeSendMessages, Write: model.ScopeSendMessages}))
+
+	ug.POST("", h.CreateResumableUpload)
+	ug.HEAD("/:id", h.GetUploadOffset)
+	ug.PATCH("/:id", h.AppendUploadChunk)
+	ug.DELETE("/:id", h.TerminateUpload)
+
 	if gin.Mode() != gin.TestMode {
 		c.R.Use(middleware.Timeout(c.TimeoutDuration, apperrors.NewServiceUnavailable()))
 	}
@@ -79,14 +129,28 @@ func NewHandler(c *Config) {
 	ag.POST("/logout", h.Logout)
 	ag.POST("/forgot-password", h.ForgotPassword)
 	ag.POST("/reset-password", h.ResetPassword)
+	ag.POST("/verify-email", h.VerifyEmail)
+	ag.POST("/two-factor/login", h.TwoFactorLogin)
 
-	ag.Use(middleware.AuthUser())
+	// Access tokens cannot manage the account
+	ag.Use(h.authUser(nil))
 	ag.GET("", h.GetCurrent)
 	ag.PUT("", h.Edit)
 	ag.PUT("/change-password", h.ChangePassword)
+	ag.POST("/resend-verification", h.ResendVerification)
+	ag.POST("/two-factor/enable", h.EnableTwoFactor)
+	ag.POST("/two-factor/confirm", h.ConfirmTwoFactor)
+	ag.POST("/two-factor/disable", h.DisableTwoFactor)
+	ag.GET("/sessions", h.GetSessions)
+	ag.DELETE("/sessions", h.RevokeAllSessions)
+	ag.DELETE("/sessions/:sessionId", h.RevokeSession)
+	ag.GET("/tokens", h.GetAccessTokens)
+	ag.POST("/tokens", h.CreateAccessToken)
+	ag.DELETE("/tokens/:tokenId", h.DeleteAccessToken)
 
 	ag.GET("/me/friends", h.GetUserFriends)
 	ag.GET("/me/pending", h.GetUserRequests)
+	ag.GET("/me/mentions", h.GetUserMentions)
 	ag.POST("/:memberId/friend", h.SendFriendRequest)
 	ag.DELETE("/:memberId/friend", h.RemoveFriend)
 	ag.POST("/:memberId/friend/accept", h.AcceptFriendRequest)
@@ -94,7 +158,7 @@ func NewHandler(c *Config) {
 
 	// Create a guild group
 	gg := c.R.Group("api/guilds")
-	gg.Use(middleware.AuthUser())
+	gg.Use(h.authUser(&middleware.TokenScopes{Read: model.ScopeReadMessages, Write: model.ScopeManageGuilds}))
 
 	gg.GET("/:guildId/members", h.GetGuildMembers)
 	gg.GET("/:guildId/vcmembers", h.GetVCMembers)
@@ -112,10 +176,42 @@ func NewHandler(c *Config) {
 	gg.POST("/:guildId/bans", h.BanMember)
 	gg.DELETE("/:guildId/bans", h.UnbanMember)
 	gg.POST("/:guildId/kick", h.KickMember)
+	gg.GET("/:guildId/roles", h.GetGuildRoles)
+	gg.POST("/:guildId/roles", h.CreateRole)
+	gg.PUT("/:guildId/roles/:roleId", h.EditRole)
+	gg.DELETE("/:guildId/roles/:roleId", h.DeleteRole)
+	gg.POST("/:guildId/roles/:roleId/members", h.AddMemberRole)
+	gg.DELETE("/:guildId/roles/:roleId/members", h.RemoveMemberRole)
+	gg.GET("/:guildId/messages/search", h.SearchGuildMessages)
+	gg.POST("/:guildId/bots", h.AddBot)
+	gg.GET("/:guildId/commands", h.GetGuildCommands)
+
+	// Create a bots group. Bots are managed by their owner and not with tokens
+	bg := c.R.Group("api/bots")
+	bg.Use(h.authUser(nil))
+
+	bg.GET("", h.GetBots)
+	bg.POST("", h.CreateBot)
+	bg.PUT("/:botId", h.EditBot)
+	bg.POST("/:botId/token", h.ResetBotToken)
+	bg.DELETE("/:botId", h.DeleteBot)
+
+	// Create a commands group. Only bots can register commands
+	cmg := c.R.Group("api/commands")
+	cmg.Use(h.authUser(&middleware.TokenScopes{Read: model.ScopeReadMessages, Write: model.ScopeSendMessages}))
+
+	cmg.GET("", h.GetCommands)
+	cmg.POST("", h.CreateCommand)
+	cmg.PUT("/:commandId", h.EditCommand)
+	cmg.DELETE("/:commandId", h.DeleteCommand)
+
+	// The interaction token authenticates the response of the bot
+	ig := c.R.Group("api/interactions")
+	ig.POST("/:interactionId/:token/callback", h.RespondToInteraction)
 
 	// Create a channels group
 	cg := c.R.Group("api/channels")
-	cg.Use(middleware.AuthUser())
+	cg.Use(h.authUser(&middleware.TokenScopes{Read: model.ScopeReadMessages, Write: model.ScopeManageGuilds}))
 
 	// Route parameters cause conflicts so they have to use the same parameter name
 	cg.GET("/:id", h.GuildChannels)                 // id -> guildId
@@ -123,29 +219,97 @@ func NewHandler(c *Config) {
 	cg.GET("/:id/members", h.PrivateChannelMembers) // id -> channelId
 	cg.POST("/:id/dm", h.GetOrCreateDM)             // id -> memberId
 	cg.GET("/me/dm", h.DirectMessages)              //
+	cg.GET("/me/dm/search", h.SearchDirectMessages) //
 	cg.PUT("/:id", h.EditChannel)                   // id -> channelId
 	cg.DELETE("/:id", h.DeleteChannel)              // id -> channelId
 	cg.DELETE("/:id/dm", h.CloseDM)                 // id -> channelId
 
+	cg.GET("/:id/permissions", h.GetPermissionOverwrites)                // id -> channelId
+	cg.PUT("/:id/permissions/:targetId", h.SetPermissionOverwrite)       // id -> channelId
+	cg.DELETE("/:id/permissions/:targetId", h.DeletePermissionOverwrite) // id -> channelId
+
+	cg.GET("/:id/threads", h.GetThreads)    // id -> channelId
+	cg.POST("/:id/threads", h.CreateThread) // id -> channelId
+
+	cg.GET("/:id/pins", h.GetPinnedMessages)          // id -> channelId
+	cg.PUT("/:id/pins/:messageId", h.PinMessage)      // id -> channelId
+	cg.DELETE("/:id/pins/:messageId", h.UnpinMessage) // id -> channelId
+
+	cg.GET("/:id/webhooks", h.GetWebhooks)                         // id -> channelId
+	cg.POST("/:id/webhooks", h.CreateWebhook)                      // id -> channelId
+	cg.PUT("/:id/webhooks/:webhookId", h.EditWebhook)              // id -> channelId
+	cg.POST("/:id/webhooks/:webhookId/token", h.ResetWebhookToken) // id -> channelId
+	cg.DELETE("/:id/webhooks/:webhookId", h.DeleteWebhook)         // id -> channelId
+
+	// The webhook token authenticates the posted messages
+	wg := c.R.Group("api/webhooks")
+	wg.POST("/:id/:token", h.ExecuteWebhook)
+
+	// Create a threads group
+	tg := c.R.Group("api/threads")
+	tg.Use(h.authUser(&middleware.TokenScopes{Read: model.ScopeReadMessages, Write: model.ScopeSendMessages}))
+
+	tg.PUT("/:threadId", h.EditThread)
+	tg.DELETE("/:threadId", h.DeleteThread)
+	tg.GET("/:threadId/members", h.GetThreadMembers)
+	tg.POST("/:threadId/members", h.JoinThread)
+	tg.DELETE("/:threadId/members", h.LeaveThread)
+
 	// Create a messages group
 	mg := c.R.Group("api/messages")
-	mg.Use(middleware.AuthUser())
+	mg.Use(h.authUser(&middleware.TokenScopes{Read: model.ScopeReadMessages, Write: model.ScopeSendMessages}))
 
 	mg.GET("/:channelId", h.GetMessages)
 	mg.POST("/:channelId", h.CreateMessage)
+	mg.POST("/:channelId/uploads", h.CreateUpload)
+	mg.POST("/:channelId/interactions", h.CreateInteraction)
 	mg.PUT("/:messageId", h.EditMessage)
 	mg.DELETE("/:messageId", h.DeleteMessage)
+	mg.GET("/:channelId/history", h.GetMessageHistory) // channelId -> messageId
+
+	mg.GET("/:channelId/reactions/:emoji", h.GetReactions)      // channelId -> messageId
+	mg.PUT("/:messageId/reactions/:emoji", h.AddReaction)       //
+	mg.DELETE("/:messageId/reactions/:emoji", h.RemoveReaction) //
 }
 
-// setUserSession saves the users ID in the session
-func setUserSession(c *gin.Context, id string) {
+// authUser returns the authentication middleware of a route group.
+// Access tokens need the given scopes and nil scopes only allow sessions.
+func (h *Handler) authUser(scopes *middleware.TokenScopes) gin.HandlerFunc {
+	return middleware.AuthUser(h.sessionService, h.tokenService, scopes)
+}
+
+// setUserSession logs the user in and tracks the new session if sessions are tracked
+func (h *Handler) setUserSession(c *gin.Context, id string) {
 	session := sessions.Default(c)
 	session.Set("userId", id)
+
+	if h.sessionService != nil {
+		tracked, err := h.sessionService.CreateSession(c.Request.Context(), id, c.Request.UserAgent(), c.ClientIP())
+
+		if err != nil {
+			log.Printf("error tracking the session: %v\n", err.Error())
+		} else {
+			session.Set("sessionId", tracked.Id)
+		}
+	}
+
 	if err := session.Save(); err != nil {
 		log.Printf("error setting the session: %v\n", err.Error())
 	}
 }
 
+// clearUserSession logs the user out by removing the session cookie
+func clearUserSession(c *gin.Context) {
+	session := sessions.Default(c)
+	session.Set("userId", "")
+	session.Clear()
+	session.Options(sessions.Options{Path: "/", MaxAge: -1})
+
+	if err := session.Save(); err != nil {
+		log.Printf("error clearing session: %v\n", err.Error())
+	}
+}
+
 func toFieldErrorResponse(c *gin.Context, field, message string) {
 	c.JSON(http.StatusBadRequest, gin.H{
 		"errors": []model.FieldError{
@@ -0,0 +1,681 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+/*
+ * InteractionHandler contains all routes related to the commands of bots (/api/commands)
+ * and their interactions (/api/interactions)
+ */
+
+// commandNameRegex matches the names of commands and their options
+var commandNameRegex = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// GetCommands returns the global and guild commands of the current bot
+// GetCommands godoc
+// @Tags Commands
+// @Summary Get Current Bot's Commands
+// @Produce  json
+// @Success 200 {array} model.Command
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /commands [get]
+func (h *Handler) GetCommands(c *gin.Context) {
+	bot, ok := h.getCurrentBot(c)
+
+	if !ok {
+		return
+	}
+
+	commands, err := h.interactionService.GetCommands(bot.ID)
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, commands)
+}
+
+type commandReq struct {
+	// Lowercase letters, numbers, - and _. Max 32 characters
+	Name string `json:"name"`
+	// Max 100 characters
+	Description string `json:"description"`
+	// Up to 25 options with unique names. Required options have to come first
+	Options []model.CommandOption `json:"options"`
+	// Registers the command only for the given guild. Ignored when editing
+	GuildId *string `json:"guildId"`
+} //@name CommandRequest
+
+func (r commandReq) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Name,
+			validation.Required,
+			validation.Match(commandNameRegex).Error(apperrors.InvalidCommandName),
+		),
+		validation.Field(&r.Description, validation.Required, validation.Length(1, 100)),
+		validation.Field(&r.Options,
+			validation.Length(0, model.CommandOptionLimit),
+			validation.By(areCommandOptions),
+		),
+		validation.Field(&r.GuildId, validation.NilOrNotEmpty, is.UTFDigit),
+	)
+}
+
+func (r *commandReq) sanitize() {
+	r.Description = strings.TrimSpace(r.Description)
+
+	if r.Options == nil {
+		r.Options = make([]model.CommandOption, 0)
+	}
+
+	for i := range r.Options {
+		r.Options[i].Description = strings.TrimSpace(r.Options[i].Description)
+	}
+}
+
+// areCommandOptions checks that the options have unique valid names, a description and a known type.
+// Required options have to come before the optional ones.
+func areCommandOptions(value interface{}) error {
+	options, _ := value.([]model.CommandOption)
+
+	names := make(map[string]bool)
+	optional := false
+
+	for _, option := range options {
+		description := strings.TrimSpace(option.Description)
+
+		if !commandNameRegex.MatchString(option.Name) || names[option.Name] ||
+			description == "" || len([]rune(description)) > 100 ||
+			!isCommandOptionType(option.Type) ||
+			(option.Required && optional) {
+			return errors.New(apperrors.InvalidCommandOptions)
+		}
+
+		names[option.Name] = true
+		optional = optional || !option.Required
+	}
+
+	return nil
+}
+
+// isCommandOptionType reports whether the type is one of the CommandOptionTypes
+func isCommandOptionType(optionType string) bool {
+	for _, t := range model.CommandOptionTypes {
+		if t == optionType {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateCommand registers a command for the current bot.
+// Guild commands can only be registered for guilds the bot is a member of.
+// CreateCommand godoc
+// @Tags Commands
+// @Summary Create Command
+// @Accept  json
+// @Produce  json
+// @Param request body commandReq true "Create Command"
+// @Success 201 {object} model.Command
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /commands [post]
+func (h *Handler) CreateCommand(c *gin.Context) {
+	var req commandReq
+
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	req.sanitize()
+
+	bot, ok := h.getCurrentBot(c)
+
+	if !ok {
+		return
+	}
+
+	if req.GuildId != nil {
+		guild, err := h.guildService.GetGuild(*req.GuildId)
+
+		if err != nil {
+			e := apperrors.NewNotFound("guild", *req.GuildId)
+			c.JSON(e.Status(), gin.H{
+				"error": e,
+			})
+			return
+		}
+
+		if !isMember(guild, bot.ID) {
+			e := apperrors.NewAuthorization(apperrors.NotAMember)
+			c.JSON(e.Status(), gin.H{
+				"error": e,
+			})
+			return
+		}
+	}
+
+	command := model.Command{
+		BotId:       bot.ID,
+		GuildId:     req.GuildId,
+		Name:        req.Name,
+		Description: req.Description,
+		Options:     req.Options,
+	}
+
+	if err := h.interactionService.CreateCommand(&command); err != nil {
+		log.Printf("Failed to create command: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, command)
+}
+
+// EditCommand changes the name, description and options of a command of the current bot
+// EditCommand godoc
+// @Tags Commands
+// @Summary Edit Command
+// @Accept  json
+// @Produce  json
+// @Param commandId path string true "Command ID"
+// @Param request body commandReq true "Edit Command"
+// @Success 200 {object} model.Command
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /commands/{commandId} [put]
+func (h *Handler) EditCommand(c *gin.Context) {
+	var req commandReq
+
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	req.sanitize()
+
+	command, ok := h.getBotCommand(c)
+
+	if !ok {
+		return
+	}
+
+	command.Name = req.Name
+	command.Description = req.Description
+	command.Options = req.Options
+
+	if err := h.interactionService.UpdateCommand(command); err != nil {
+		log.Printf("Failed to update command: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, command)
+}
+
+// DeleteCommand removes a command of the current bot
+// DeleteCommand godoc
+// @Tags Commands
+// @Summary Delete Command
+// @Produce  json
+// @Param commandId path string true "Command ID"
+// @Success 200 {object} model.Success
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /commands/{commandId} [delete]
+func (h *Handler) DeleteCommand(c *gin.Context) {
+	command, ok := h.getBotCommand(c)
+
+	if !ok {
+		return
+	}
+
+	if err := h.interactionService.DeleteCommand(command); err != nil {
+		log.Printf("Failed to delete command: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, true)
+}
+
+// getCurrentBot returns the current user if it is a bot.
+// Otherwise, it writes the error response and returns false.
+func (h *Handler) getCurrentBot(c *gin.Context) (*model.User, bool) {
+	userId := c.MustGet("userId").(string)
+
+	bot, err := h.userService.Get(userId)
+
+	if err != nil || !bot.Bot {
+		e := apperrors.NewAuthorization(apperrors.BotOnlyError)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, false
+	}
+
+	return bot, true
+}
+
+// getBotCommand returns the command of the commandId param if it belongs to the current bot.
+// Otherwise, it writes the error response and returns false.
+func (h *Handler) getBotCommand(c *gin.Context) (*model.Command, bool) {
+	bot, ok := h.getCurrentBot(c)
+
+	if !ok {
+		return nil, false
+	}
+
+	commandId := c.Param("commandId")
+	command, err := h.interactionService.GetCommand(commandId)
+
+	if err != nil || command.BotId != bot.ID {
+		e := apperrors.NewNotFound("command", commandId)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, false
+	}
+
+	return command, true
+}
+
+// GetGuildCommands returns the commands the members of the guild can use for autocompletion
+// GetGuildCommands godoc
+// @Tags Guilds
+// @Summary Get Guild Commands
+// @Produce  json
+// @Param guildId path string true "Guild ID"
+// @Success 200 {array} model.Command
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /guilds/{guildId}/commands [get]
+func (h *Handler) GetGuildCommands(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+	guildId := c.Param("guildId")
+
+	guild, err := h.guildService.GetGuild(guildId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("guild", guildId)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	if !isMember(guild, userId) {
+		e := apperrors.NewAuthorization(apperrors.NotAMember)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	commands, err := h.interactionService.GetGuildCommands(guild.ID)
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, commands)
+}
+
+type interactionReq struct {
+	// ID of the invoked command
+	CommandId string `json:"commandId"`
+	// Values of the command options
+	Options []model.InteractionOption `json:"options"`
+} //@name InteractionRequest
+
+func (r interactionReq) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.CommandId, validation.Required, is.UTFDigit),
+	)
+}
+
+// CreateInteraction invokes a command in the given channel.
+// The bot receives the interaction over the gateway or on its interactions url.
+// CreateInteraction godoc
+// @Tags Messages
+// @Summary Invoke Command
+// @Accept  json
+// @Produce  json
+// @Param channelId path string true "Channel ID"
+// @Param request body interactionReq true "Invoke Command"
+// @Success 201 {object} model.Interaction
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /messages/{channelId}/interactions [post]
+func (h *Handler) CreateInteraction(c *gin.Context) {
+	var req interactionReq
+
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	channelId := c.Param("channelId")
+	userId := c.MustGet("userId").(string)
+
+	channel, err := h.channelService.Get(channelId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("channel", channelId)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	if channel.IsDM || channel.GuildID == nil {
+		e := apperrors.NewBadRequest(apperrors.CommandChannelError)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	if err = h.channelService.IsChannelMember(channel, userId); err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if !h.channelService.HasPermission(userId, channel, model.PermissionSendMessages) {
+		e := apperrors.NewAuthorization(apperrors.SendMessagesError)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// The bot of the command has to be able to see the channel
+	command, err := h.interactionService.GetCommand(req.CommandId)
+
+	if err != nil || !command.IsAvailableIn(*channel.GuildID) ||
+		h.channelService.IsChannelMember(channel, command.BotId) != nil {
+		e := apperrors.NewNotFound("command", req.CommandId)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	bot, err := h.userService.Get(command.BotId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("command", req.CommandId)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	author, err := h.userService.Get(userId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("user", userId)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	member := model.MemberResponse{
+		Id:        author.ID,
+		Username:  author.Username,
+		Image:     author.Image,
+		IsOnline:  author.IsOnline,
+		CreatedAt: author.CreatedAt,
+		UpdatedAt: author.UpdatedAt,
+		Bot:       author.Bot,
+	}
+
+	settings, _ := h.guildService.GetMemberSettings(userId, *channel.GuildID)
+	if settings != nil {
+		member.Nickname = settings.Nickname
+		member.Color = settings.Color
+	}
+
+	interaction, err := h.interactionService.CreateInteraction(c.Request.Context(), command, channel, &member, req.Options)
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	// Bots with an interactions url receive signed requests instead of gateway events
+	if bot.InteractionsUrl != nil {
+		if err = h.interactionService.DeliverInteraction(bot, interaction); err != nil {
+			_ = h.interactionService.CompleteInteraction(c.Request.Context(), interaction)
+			c.JSON(apperrors.Status(err), gin.H{
+				"error": err,
+			})
+			return
+		}
+	} else {
+		h.socketService.EmitInteractionCreate(bot.ID, interaction)
+	}
+
+	// Only the bot gets the token
+	c.JSON(http.StatusCreated, interaction.WithoutToken())
+}
+
+type interactionResponseReq struct {
+	// Either message or deferred
+	Type string `json:"type"`
+	// Text of the message. Required for message responses. Maximum 2000 characters
+	Text *string `json:"text"`
+} //@name InteractionResponseRequest
+
+func (r interactionResponseReq) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Type,
+			validation.Required,
+			validation.In(model.ResponseTypeMessage, model.ResponseTypeDeferred),
+		),
+		validation.Field(&r.Text,
+			validation.Required.When(r.Type == model.ResponseTypeMessage).Error(apperrors.InteractionResponseText),
+			validation.Length(1, 2000),
+		),
+	)
+}
+
+func (r *interactionResponseReq) sanitize() {
+	if r.Text != nil {
+		text := strings.TrimSpace(*r.Text)
+		r.Text = &text
+	}
+}
+
+// RespondToInteraction lets the bot respond to an interaction with its token.
+// A deferred response tells the channel that the bot is working on it and
+// a message response posts the message of the bot and invalidates the token.
+// RespondToInteraction godoc
+// @Tags Commands
+// @Summary Respond To Interaction
+// @Accept  json
+// @Produce  json
+// @Param interactionId path string true "Interaction ID"
+// @Param token path string true "Interaction Token"
+// @Param request body interactionResponseReq true "Interaction Response"
+// @Success 200 {object} model.Success
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /interactions/{interactionId}/{token}/callback [post]
+func (h *Handler) RespondToInteraction(c *gin.Context) {
+	var req interactionResponseReq
+
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	req.sanitize()
+
+	ctx := c.Request.Context()
+	interaction, err := h.interactionService.GetInteraction(ctx, c.Param("interactionId"), c.Param("token"))
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if req.Type == model.ResponseTypeDeferred {
+		if err = h.interactionService.DeferInteraction(ctx, interaction); err != nil {
+			c.JSON(apperrors.Status(err), gin.H{
+				"error": err,
+			})
+			return
+		}
+
+		h.socketService.EmitInteractionDeferred(interaction.ChannelId, interaction.WithoutToken())
+
+		c.JSON(http.StatusOK, true)
+		return
+	}
+
+	channel, err := h.channelService.Get(interaction.ChannelId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("channel", interaction.ChannelId)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	bot, err := h.userService.Get(interaction.BotId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("user", interaction.BotId)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	if !h.channelService.HasPermission(bot.ID, channel, model.PermissionSendMessages) {
+		e := apperrors.NewAuthorization(apperrors.SendMessagesError)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// Use up the token first, so concurrent responses cannot post more than one message
+	if err = h.interactionService.CompleteInteraction(ctx, interaction); err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	message, err := h.messageService.CreateMessage(&model.Message{
+		UserId:    bot.ID,
+		ChannelId: channel.ID,
+		Text:      req.Text,
+	})
+
+	if err != nil {
+		log.Printf("Failed to create message: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	h.publishMessage(channel, bot, message)
+
+	c.JSON(http.StatusOK, true)
+}
+
+// publishMessage emits a guild channel message that was not sent through CreateMessage,
+// like the response of a bot, and notifies the guild about it
+func (h *Handler) publishMessage(channel *model.Channel, author *model.User, message *model.Message) {
+	response := model.MessageResponse{
+		Id:          message.ID,
+		Text:        message.Text,
+		CreatedAt:   message.CreatedAt,
+		UpdatedAt:   message.UpdatedAt,
+		Attachments: message.Attachments,
+		User: model.MemberResponse{
+			Id:        author.ID,
+			Username:  author.Username,
+			Image:     author.Image,
+			IsOnline:  author.IsOnline,
+			CreatedAt: author.CreatedAt,
+			UpdatedAt: author.UpdatedAt,
+			Bot:       author.Bot,
+		},
+		Reactions: make([]model.ReactionResponse, 0),
+		Mentions:  h.getMessageMentions(channel, message),
+	}
+
+	if response.Attachments == nil {
+		response.Attachments = make([]model.Attachment, 0)
+	}
+
+	settings, _ := h.guildService.GetMemberSettings(author.ID, *channel.GuildID)
+	if settings != nil {
+		response.User.Nickname = settings.Nickname
+		response.User.Color = settings.Color
+	}
+
+	h.socketService.EmitNewMessage(channel.ID, &response)
+
+	if len(message.Mentions) > 0 {
+		if users, err := h.messageService.GetMentionedUsers(channel, message); err == nil {
+			h.emitMentions(channel, author.ID, users, &response)
+		}
+	}
+
+	// Update last activity in the channel and post a notification
+	channel.LastActivity = time.Now()
+	_ = h.channelService.UpdateChannel(channel)
+
+	if channel.IsThread() {
+		h.socketService.EmitNewNotification(*channel.GuildID, *channel.ParentID)
+	} else {
+		h.socketService.EmitNewNotification(*channel.GuildID, channel.ID)
+	}
+}
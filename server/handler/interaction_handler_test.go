@@ -0,0 +1,725 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler_CreateCommand(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockBot := fixture.GetMockBot(fixture.RandID())
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", mockBot.ID).Return(mockBot, nil)
+
+		mockInteractionService := new(mocks.InteractionService)
+		mockInteractionService.
+			On("CreateCommand", mock.AnythingOfType("*model.Command")).
+			Run(func(args mock.Arguments) { args.Get(0).(*model.Command).ID = fixture.RandID() }).
+			Return(nil)
+
+		router := getAuthenticatedTestRouter(mockBot.ID)
+
+		NewHandler(&Config{
+			R:                  router,
+			UserService:        mockUserService,
+			InteractionService: mockInteractionService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"name":        "roll",
+			"description": " Rolls the dice ",
+			"options": []gin.H{
+				{"name": "dice", "description": "Dice to roll", "type": model.OptionTypeString, "required": true},
+			},
+		})
+
+		request, _ := http.NewRequest(http.MethodPost, "/api/commands", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		var command model.Command
+		_ = json.Unmarshal(rr.Body.Bytes(), &command)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.NotEmpty(t, command.ID)
+		assert.Equal(t, mockBot.ID, command.BotId)
+		assert.Nil(t, command.GuildId)
+		assert.Equal(t, "Rolls the dice", command.Description)
+		assert.Len(t, command.Options, 1)
+		mockInteractionService.AssertExpectations(t)
+	})
+
+	t.Run("Users cannot register commands", func(t *testing.T) {
+		authUser := fixture.GetMockUser()
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
+
+		mockInteractionService := new(mocks.InteractionService)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:                  router,
+			UserService:        mockUserService,
+			InteractionService: mockInteractionService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"name":        "roll",
+			"description": "Rolls the dice",
+		})
+
+		request, _ := http.NewRequest(http.MethodPost, "/api/commands", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(gin.H{
+			"error": apperrors.NewAuthorization(apperrors.BotOnlyError),
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockInteractionService.AssertNotCalled(t, "CreateCommand", mock.Anything)
+	})
+
+	t.Run("Guild of another bot", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", mockBot.ID).Return(mockBot, nil)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+
+		mockInteractionService := new(mocks.InteractionService)
+
+		router := getAuthenticatedTestRouter(mockBot.ID)
+
+		NewHandler(&Config{
+			R:                  router,
+			UserService:        mockUserService,
+			GuildService:       mockGuildService,
+			InteractionService: mockInteractionService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"name":        "roll",
+			"description": "Rolls the dice",
+			"guildId":     mockGuild.ID,
+		})
+
+		request, _ := http.NewRequest(http.MethodPost, "/api/commands", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(gin.H{
+			"error": apperrors.NewAuthorization(apperrors.NotAMember),
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockInteractionService.AssertNotCalled(t, "CreateCommand", mock.Anything)
+	})
+}
+
+func TestHandler_CreateCommand_BadRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockBot := fixture.GetMockBot(fixture.RandID())
+	router := getAuthenticatedTestRouter(mockBot.ID)
+
+	mockInteractionService := new(mocks.InteractionService)
+
+	NewHandler(&Config{
+		R:                  router,
+		InteractionService: mockInteractionService,
+	})
+
+	testCases := []struct {
+		name string
+		body gin.H
+	}{
+		{
+			name: "Name required",
+			body: gin.H{"description": "Rolls the dice"},
+		},
+		{
+			name: "Name with uppercase letters",
+			body: gin.H{"name": "Roll", "description": "Rolls the dice"},
+		},
+		{
+			name: "Name with spaces",
+			body: gin.H{"name": "roll dice", "description": "Rolls the dice"},
+		},
+		{
+			name: "Description required",
+			body: gin.H{"name": "roll"},
+		},
+		{
+			name: "Unknown option type",
+			body: gin.H{"name": "roll", "description": "Rolls the dice", "options": []gin.H{
+				{"name": "dice", "description": "Dice to roll", "type": "float"},
+			}},
+		},
+		{
+			name: "Duplicate option names",
+			body: gin.H{"name": "roll", "description": "Rolls the dice", "options": []gin.H{
+				{"name": "dice", "description": "Dice to roll", "type": model.OptionTypeString},
+				{"name": "dice", "description": "Dice to roll", "type": model.OptionTypeString},
+			}},
+		},
+		{
+			name: "Required option after an optional one",
+			body: gin.H{"name": "roll", "description": "Rolls the dice", "options": []gin.H{
+				{"name": "times", "description": "Number of rolls", "type": model.OptionTypeInteger},
+				{"name": "dice", "description": "Dice to roll", "type": model.OptionTypeString, "required": true},
+			}},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			reqBody, _ := json.Marshal(tc.body)
+
+			request, _ := http.NewRequest(http.MethodPost, "/api/commands", bytes.NewBuffer(reqBody))
+			request.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			mockInteractionService.AssertNotCalled(t, "CreateCommand", mock.Anything)
+		})
+	}
+}
+
+func TestHandler_GetGuildCommands(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authUser := fixture.GetMockUser()
+
+	t.Run("Success", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild(authUser.ID)
+		mockGuild.Members = append(mockGuild.Members, *authUser)
+		commands := []model.Command{*fixture.GetMockCommand(fixture.RandID())}
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+
+		mockInteractionService := new(mocks.InteractionService)
+		mockInteractionService.On("GetGuildCommands", mockGuild.ID).Return(&commands, nil)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:                  router,
+			GuildService:       mockGuildService,
+			InteractionService: mockInteractionService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/guilds/%s/commands", mockGuild.ID), nil)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(commands)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockInteractionService.AssertExpectations(t)
+	})
+
+	t.Run("Not a member", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+
+		mockInteractionService := new(mocks.InteractionService)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:                  router,
+			GuildService:       mockGuildService,
+			InteractionService: mockInteractionService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/guilds/%s/commands", mockGuild.ID), nil)
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		mockInteractionService.AssertNotCalled(t, "GetGuildCommands", mock.Anything)
+	})
+}
+
+func TestHandler_CreateInteraction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authUser := fixture.GetMockUser()
+
+	// getMocks returns the services of a successful invocation
+	getMocks := func(bot *model.User, command *model.Command, channel *model.Channel, interaction *model.Interaction) (*mocks.ChannelService, *mocks.UserService, *mocks.GuildService, *mocks.InteractionService) {
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", channel.ID).Return(channel, nil)
+		mockChannelService.On("IsChannelMember", channel, authUser.ID).Return(nil)
+		mockChannelService.On("IsChannelMember", channel, bot.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, channel, model.PermissionSendMessages).Return(true)
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
+		mockUserService.On("Get", bot.ID).Return(bot, nil)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetMemberSettings", authUser.ID, *channel.GuildID).Return(&model.MemberSettings{}, nil)
+
+		options := []model.InteractionOption{{Name: "dice", Value: "d20"}}
+
+		mockInteractionService := new(mocks.InteractionService)
+		mockInteractionService.On("GetCommand", command.ID).Return(command, nil)
+		mockInteractionService.
+			On("CreateInteraction", mock.Anything, command, channel, mock.AnythingOfType("*model.MemberResponse"), options).
+			Return(interaction, nil)
+
+		return mockChannelService, mockUserService, mockGuildService, mockInteractionService
+	}
+
+	invoke := func(router *gin.Engine, channelId string, commandId string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"commandId": commandId,
+			"options":   []gin.H{{"name": "dice", "value": "d20"}},
+		})
+
+		request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/messages/%s/interactions", channelId), bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		return rr
+	}
+
+	t.Run("Delivered over the gateway", func(t *testing.T) {
+		mockBot := fixture.GetMockBot(fixture.RandID())
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockCommand := fixture.GetMockCommand(mockBot.ID)
+		interaction := &model.Interaction{
+			Id:        fixture.RandID(),
+			Token:     "token",
+			BotId:     mockBot.ID,
+			CommandId: mockCommand.ID,
+			ChannelId: mockChannel.ID,
+			GuildId:   *mockChannel.GuildID,
+			Options:   []model.InteractionOption{{Name: "dice", Value: "d20"}},
+			ExpiresAt: time.Now().Add(model.InteractionTokenExpiry),
+		}
+
+		mockChannelService, mockUserService, mockGuildService, mockInteractionService := getMocks(mockBot, mockCommand, mockChannel, interaction)
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitInteractionCreate", mockBot.ID, interaction).Return()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:                  router,
+			ChannelService:     mockChannelService,
+			UserService:        mockUserService,
+			GuildService:       mockGuildService,
+			InteractionService: mockInteractionService,
+			SocketService:      mockSocketService,
+		})
+
+		rr := invoke(router, mockChannel.ID, mockCommand.ID)
+
+		// The user does not get the token
+		respBody, _ := json.Marshal(interaction.WithoutToken())
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockSocketService.AssertExpectations(t)
+		mockInteractionService.AssertNotCalled(t, "DeliverInteraction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Delivered to the interactions url", func(t *testing.T) {
+		mockBot := fixture.GetMockBot(fixture.RandID())
+		interactionsUrl := "https://bot.example.com/interactions"
+		mockBot.InteractionsUrl = &interactionsUrl
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockCommand := fixture.GetMockCommand(mockBot.ID)
+		interaction := &model.Interaction{
+			Id:        fixture.RandID(),
+			Token:     "token",
+			BotId:     mockBot.ID,
+			CommandId: mockCommand.ID,
+		}
+
+		mockChannelService, mockUserService, mockGuildService, mockInteractionService := getMocks(mockBot, mockCommand, mockChannel, interaction)
+		mockInteractionService.On("DeliverInteraction", mockBot, interaction).Return(nil)
+
+		mockSocketService := new(mocks.SocketService)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:                  router,
+			ChannelService:     mockChannelService,
+			UserService:        mockUserService,
+			GuildService:       mockGuildService,
+			InteractionService: mockInteractionService,
+			SocketService:      mockSocketService,
+		})
+
+		rr := invoke(router, mockChannel.ID, mockCommand.ID)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		mockInteractionService.AssertExpectations(t)
+		mockSocketService.AssertNotCalled(t, "EmitInteractionCreate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Bot rejected the interaction", func(t *testing.T) {
+		mockBot := fixture.GetMockBot(fixture.RandID())
+		interactionsUrl := "https://bot.example.com/interactions"
+		mockBot.InteractionsUrl = &interactionsUrl
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockCommand := fixture.GetMockCommand(mockBot.ID)
+		interaction := &model.Interaction{
+			Id:    fixture.RandID(),
+			Token: "token",
+		}
+
+		mockError := apperrors.NewBadRequest(apperrors.InteractionDeliveryError)
+
+		mockChannelService, mockUserService, mockGuildService, mockInteractionService := getMocks(mockBot, mockCommand, mockChannel, interaction)
+		mockInteractionService.On("DeliverInteraction", mockBot, interaction).Return(mockError)
+		mockInteractionService.On("CompleteInteraction", mock.Anything, interaction).Return(nil)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:                  router,
+			ChannelService:     mockChannelService,
+			UserService:        mockUserService,
+			GuildService:       mockGuildService,
+			InteractionService: mockInteractionService,
+		})
+
+		rr := invoke(router, mockChannel.ID, mockCommand.ID)
+
+		respBody, _ := json.Marshal(gin.H{
+			"error": mockError,
+		})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockInteractionService.AssertExpectations(t)
+	})
+
+	t.Run("Guild command of another guild", func(t *testing.T) {
+		mockBot := fixture.GetMockBot(fixture.RandID())
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockCommand := fixture.GetMockCommand(mockBot.ID)
+		otherGuild := fixture.RandID()
+		mockCommand.GuildId = &otherGuild
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(true)
+
+		mockInteractionService := new(mocks.InteractionService)
+		mockInteractionService.On("GetCommand", mockCommand.ID).Return(mockCommand, nil)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:                  router,
+			ChannelService:     mockChannelService,
+			InteractionService: mockInteractionService,
+		})
+
+		rr := invoke(router, mockChannel.ID, mockCommand.ID)
+
+		respBody, _ := json.Marshal(gin.H{
+			"error": apperrors.NewNotFound("command", mockCommand.ID),
+		})
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockInteractionService.AssertNotCalled(t, "CreateInteraction", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("DM channel", func(t *testing.T) {
+		mockChannel := fixture.GetMockDMChannel()
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+
+		mockInteractionService := new(mocks.InteractionService)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:                  router,
+			ChannelService:     mockChannelService,
+			InteractionService: mockInteractionService,
+		})
+
+		rr := invoke(router, mockChannel.ID, fixture.RandID())
+
+		respBody, _ := json.Marshal(gin.H{
+			"error": apperrors.NewBadRequest(apperrors.CommandChannelError),
+		})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockInteractionService.AssertNotCalled(t, "GetCommand", mock.Anything)
+	})
+}
+
+func TestHandler_RespondToInteraction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockBot := fixture.GetMockBot(fixture.RandID())
+	mockChannel := fixture.GetMockChannel(fixture.RandID())
+
+	getInteraction := func() *model.Interaction {
+		return &model.Interaction{
+			Id:        fixture.RandID(),
+			Token:     "token",
+			BotId:     mockBot.ID,
+			ChannelId: mockChannel.ID,
+			GuildId:   *mockChannel.GuildID,
+		}
+	}
+
+	respond := func(router *gin.Engine, interaction *model.Interaction, body gin.H) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(body)
+
+		request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/interactions/%s/%s/callback", interaction.Id, interaction.Token), bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		return rr
+	}
+
+	t.Run("Deferred", func(t *testing.T) {
+		interaction := getInteraction()
+
+		mockInteractionService := new(mocks.InteractionService)
+		mockInteractionService.On("GetInteraction", mock.Anything, interaction.Id, interaction.Token).Return(interaction, nil)
+		mockInteractionService.On("DeferInteraction", mock.Anything, interaction).Return(nil)
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitInteractionDeferred", mockChannel.ID, interaction.WithoutToken()).Return()
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:                  router,
+			InteractionService: mockInteractionService,
+			SocketService:      mockSocketService,
+		})
+
+		rr := respond(router, interaction, gin.H{"type": model.ResponseTypeDeferred})
+
+		respBody, _ := json.Marshal(true)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockInteractionService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
+	t.Run("Message", func(t *testing.T) {
+		interaction := getInteraction()
+		mockMessage := fixture.GetMockMessage(mockBot.ID, mockChannel.ID)
+
+		mockInteractionService := new(mocks.InteractionService)
+		mockInteractionService.On("GetInteraction", mock.Anything, interaction.Id, interaction.Token).Return(interaction, nil)
+		mockInteractionService.On("CompleteInteraction", mock.Anything, interaction).Return(nil)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("HasPermission", mockBot.ID, mockChannel, model.PermissionSendMessages).Return(true)
+		mockChannelService.On("UpdateChannel", mockChannel).Return(nil)
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", mockBot.ID).Return(mockBot, nil)
+
+		params := model.Message{
+			UserId:    mockBot.ID,
+			ChannelId: mockChannel.ID,
+			Text:      mockMessage.Text,
+		}
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("CreateMessage", &params).Return(mockMessage, nil)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetMemberSettings", mockBot.ID, *mockChannel.GuildID).Return(&model.MemberSettings{}, nil)
+
+		response := model.MessageResponse{
+			Id:          mockMessage.ID,
+			Text:        mockMessage.Text,
+			CreatedAt:   mockMessage.CreatedAt,
+			UpdatedAt:   mockMessage.UpdatedAt,
+			Attachments: make([]model.Attachment, 0),
+			User: model.MemberResponse{
+				Id:        mockBot.ID,
+				Username:  mockBot.Username,
+				Image:     mockBot.Image,
+				IsOnline:  mockBot.IsOnline,
+				CreatedAt: mockBot.CreatedAt,
+				UpdatedAt: mockBot.UpdatedAt,
+				Bot:       true,
+			},
+			Reactions: make([]model.ReactionResponse, 0),
+			Mentions:  model.NewMessageMentions(),
+		}
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitNewMessage", mockChannel.ID, &response).Return()
+		mockSocketService.On("EmitNewNotification", *mockChannel.GuildID, mockChannel.ID).Return()
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:                  router,
+			InteractionService: mockInteractionService,
+			ChannelService:     mockChannelService,
+			UserService:        mockUserService,
+			MessageService:     mockMessageService,
+			GuildService:       mockGuildService,
+			SocketService:      mockSocketService,
+		})
+
+		rr := respond(router, interaction, gin.H{"type": model.ResponseTypeMessage, "text": *mockMessage.Text})
+
+		respBody, _ := json.Marshal(true)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockMessageService.AssertExpectations(t)
+		mockInteractionService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
+	t.Run("Token already used", func(t *testing.T) {
+		interaction := getInteraction()
+		mockError := apperrors.NewAuthorization(apperrors.InvalidInteractionToken)
+
+		mockInteractionService := new(mocks.InteractionService)
+		mockInteractionService.On("GetInteraction", mock.Anything, interaction.Id, interaction.Token).Return(interaction, nil)
+		mockInteractionService.On("CompleteInteraction", mock.Anything, interaction).Return(mockError)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("HasPermission", mockBot.ID, mockChannel, model.PermissionSendMessages).Return(true)
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", mockBot.ID).Return(mockBot, nil)
+
+		mockMessageService := new(mocks.MessageService)
+		mockSocketService := new(mocks.SocketService)
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:                  router,
+			InteractionService: mockInteractionService,
+			ChannelService:     mockChannelService,
+			UserService:        mockUserService,
+			MessageService:     mockMessageService,
+			SocketService:      mockSocketService,
+		})
+
+		rr := respond(router, interaction, gin.H{"type": model.ResponseTypeMessage, "text": "Pong"})
+
+		respBody, _ := json.Marshal(gin.H{
+			"error": mockError,
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockMessageService.AssertNotCalled(t, "CreateMessage", mock.Anything)
+		mockSocketService.AssertNotCalled(t, "EmitNewMessage", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Message without text", func(t *testing.T) {
+		interaction := getInteraction()
+
+		mockInteractionService := new(mocks.InteractionService)
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:                  router,
+			InteractionService: mockInteractionService,
+		})
+
+		rr := respond(router, interaction, gin.H{"type": model.ResponseTypeMessage})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockInteractionService.AssertNotCalled(t, "GetInteraction", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		interaction := getInteraction()
+		mockError := apperrors.NewAuthorization(apperrors.InvalidInteractionToken)
+
+		mockInteractionService := new(mocks.InteractionService)
+		mockInteractionService.On("GetInteraction", mock.Anything, interaction.Id, interaction.Token).Return(nil, mockError)
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:                  router,
+			InteractionService: mockInteractionService,
+		})
+
+		rr := respond(router, interaction, gin.H{"type": model.ResponseTypeDeferred})
+
+		respBody, _ := json.Marshal(gin.H{
+			"error": mockError,
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+	})
+}
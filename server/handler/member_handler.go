@@ -178,8 +178,8 @@ func (h *Handler) GetBanList(c *gin.Context) {
 
 	userId := c.MustGet("userId").(string)
 
-	if guild.OwnerId != userId {
-		e := apperrors.NewAuthorization(apperrors.MustBeOwner)
+	if !h.guildService.HasPermission(userId, guild.ID, model.PermissionBanMembers) {
+		e := apperrors.NewAuthorization(apperrors.MissingPermissions)
 		c.JSON(e.Status(), gin.H{
 			"error": e,
 		})
@@ -239,8 +239,8 @@ func (h *Handler) BanMember(c *gin.Context) {
 
 	userId := c.MustGet("userId").(string)
 
-	if guild.OwnerId != userId {
-		e := apperrors.NewAuthorization(apperrors.MustBeOwner)
+	if !h.guildService.HasPermission(userId, guild.ID, model.PermissionBanMembers) {
+		e := apperrors.NewAuthorization(apperrors.MissingPermissions)
 		c.JSON(e.Status(), gin.H{
 			"error": e,
 		})
@@ -267,6 +267,23 @@ func (h *Handler) BanMember(c *gin.Context) {
 		return
 	}
 
+	if member.ID == guild.OwnerId {
+		e := apperrors.NewBadRequest(apperrors.BanOwnerError)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// Members can only ban members ranked below them
+	if !h.guildService.OutranksMember(userId, member.ID, guild.ID) {
+		e := apperrors.NewAuthorization(apperrors.RoleHierarchyError)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
 	guild.Bans = append(guild.Bans, *member)
 
 	if err = h.guildService.UpdateGuild(guild); err != nil {
@@ -327,8 +344,8 @@ func (h *Handler) UnbanMember(c *gin.Context) {
 
 	userId := c.MustGet("userId").(string)
 
-	if guild.OwnerId != userId {
-		e := apperrors.NewAuthorization(apperrors.MustBeOwner)
+	if !h.guildService.HasPermission(userId, guild.ID, model.PermissionBanMembers) {
+		e := apperrors.NewAuthorization(apperrors.MissingPermissions)
 		c.JSON(e.Status(), gin.H{
 			"error": e,
 		})
@@ -388,8 +405,8 @@ func (h *Handler) KickMember(c *gin.Context) {
 
 	userId := c.MustGet("userId").(string)
 
-	if guild.OwnerId != userId {
-		e := apperrors.NewAuthorization(apperrors.MustBeOwner)
+	if !h.guildService.HasPermission(userId, guild.ID, model.PermissionKickMembers) {
+		e := apperrors.NewAuthorization(apperrors.MissingPermissions)
 		c.JSON(e.Status(), gin.H{
 			"error": e,
 		})
@@ -416,6 +433,23 @@ func (h *Handler) KickMember(c *gin.Context) {
 		return
 	}
 
+	if member.ID == guild.OwnerId {
+		e := apperrors.NewBadRequest(apperrors.KickOwnerError)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// Members can only kick members ranked below them
+	if !h.guildService.OutranksMember(userId, member.ID, guild.ID) {
+		e := apperrors.NewAuthorization(apperrors.RoleHierarchyError)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
 	err = h.guildService.RemoveMember(req.MemberId, guildId)
 
 	if err != nil {
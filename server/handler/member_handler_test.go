@@ -517,6 +517,7 @@ func TestHandler_GetBanList(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionBanMembers).Return(true)
 		mockGuildService.On("GetBanList", mockGuild.ID).Return(&response, nil)
 
 		rr := httptest.NewRecorder()
@@ -547,6 +548,7 @@ func TestHandler_GetBanList(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionBanMembers).Return(false)
 
 		rr := httptest.NewRecorder()
 
@@ -563,7 +565,7 @@ func TestHandler_GetBanList(t *testing.T) {
 
 		router.ServeHTTP(rr, request)
 
-		mockError := apperrors.NewAuthorization(apperrors.MustBeOwner)
+		mockError := apperrors.NewAuthorization(apperrors.MissingPermissions)
 		respBody, err := json.Marshal(gin.H{
 			"error": mockError,
 		})
@@ -613,6 +615,7 @@ func TestHandler_GetBanList(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionBanMembers).Return(true)
 
 		mockError := apperrors.NewInternal()
 		mockGuildService.On("GetBanList", mockGuild.ID).Return(nil, mockError)
@@ -654,7 +657,9 @@ func TestHandler_BanMember(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionBanMembers).Return(true)
 		mockGuildService.On("GetUser", mockMember.ID).Return(mockMember, nil)
+		mockGuildService.On("OutranksMember", authUser.ID, mockMember.ID, mockGuild.ID).Return(true)
 		mockGuildService.On("UpdateGuild", mockGuild).Return(nil)
 
 		args := mock.Arguments{
@@ -704,6 +709,7 @@ func TestHandler_BanMember(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionBanMembers).Return(false)
 
 		mockSocketService := new(mocks.SocketService)
 
@@ -729,7 +735,7 @@ func TestHandler_BanMember(t *testing.T) {
 		request.Header.Set("Content-Type", "application/json")
 		router.ServeHTTP(rr, request)
 
-		mockError := apperrors.NewAuthorization(apperrors.MustBeOwner)
+		mockError := apperrors.NewAuthorization(apperrors.MissingPermissions)
 		respBody, err := json.Marshal(gin.H{
 			"error": mockError,
 		})
@@ -798,7 +804,9 @@ func TestHandler_BanMember(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionBanMembers).Return(true)
 		mockGuildService.On("GetUser", mockMember.ID).Return(mockMember, nil)
+		mockGuildService.On("OutranksMember", authUser.ID, mockMember.ID, mockGuild.ID).Return(true)
 		mockGuildService.On("UpdateGuild", mockGuild).Return(nil)
 
 		mockError := apperrors.NewInternal()
@@ -899,6 +907,7 @@ func TestHandler_BanMember(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionBanMembers).Return(true)
 		mockError := apperrors.NewNotFound("user", mockMember.ID)
 		mockGuildService.On("GetUser", mockMember.ID).Return(nil, mockError)
 
@@ -983,6 +992,7 @@ func TestHandler_BanMember(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionBanMembers).Return(true)
 		mockGuildService.On("GetUser", authUser.ID).Return(authUser, nil)
 
 		mockSocketService := new(mocks.SocketService)
@@ -1024,6 +1034,105 @@ func TestHandler_BanMember(t *testing.T) {
 		mockSocketService.AssertNotCalled(t, "EmitRemoveMember")
 		mockSocketService.AssertNotCalled(t, "EmitRemoveFromGuild")
 	})
+
+	t.Run("Member is the owner", func(t *testing.T) {
+		mockOwner := fixture.GetMockUser()
+		mockGuild := fixture.GetMockGuild(mockOwner.ID)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionBanMembers).Return(true)
+		mockGuildService.On("GetUser", mockOwner.ID).Return(mockOwner, nil)
+
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:             router,
+			GuildService:  mockGuildService,
+			SocketService: mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"memberId": mockOwner.ID,
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/bans", mockGuild.ID)
+		request, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewBadRequest(apperrors.BanOwnerError)
+		respBody, _ := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+
+		mockGuildService.AssertCalled(t, "GetGuild", mockGuild.ID)
+		mockGuildService.AssertCalled(t, "GetUser", mockOwner.ID)
+		mockGuildService.AssertNotCalled(t, "RemoveMember")
+		mockSocketService.AssertNotCalled(t, "EmitRemoveMember")
+		mockSocketService.AssertNotCalled(t, "EmitRemoveFromGuild")
+	})
+
+	t.Run("Member ranks above the user", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockMember := fixture.GetMockUser()
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionBanMembers).Return(true)
+		mockGuildService.On("GetUser", mockMember.ID).Return(mockMember, nil)
+		mockGuildService.On("OutranksMember", authUser.ID, mockMember.ID, mockGuild.ID).Return(false)
+
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:             router,
+			GuildService:  mockGuildService,
+			SocketService: mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"memberId": mockMember.ID,
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/bans", mockGuild.ID)
+		request, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewAuthorization(apperrors.RoleHierarchyError)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+
+		mockGuildService.AssertExpectations(t)
+		mockGuildService.AssertNotCalled(t, "UpdateGuild")
+		mockGuildService.AssertNotCalled(t, "RemoveMember")
+		mockSocketService.AssertNotCalled(t, "EmitRemoveMember")
+		mockSocketService.AssertNotCalled(t, "EmitRemoveFromGuild")
+	})
 }
 
 func TestHandler_KickMember(t *testing.T) {
@@ -1037,7 +1146,9 @@ func TestHandler_KickMember(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionKickMembers).Return(true)
 		mockGuildService.On("GetUser", mockMember.ID).Return(mockMember, nil)
+		mockGuildService.On("OutranksMember", authUser.ID, mockMember.ID, mockGuild.ID).Return(true)
 
 		args := mock.Arguments{
 			mockMember.ID,
@@ -1086,6 +1197,7 @@ func TestHandler_KickMember(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionKickMembers).Return(false)
 
 		mockSocketService := new(mocks.SocketService)
 
@@ -1111,7 +1223,7 @@ func TestHandler_KickMember(t *testing.T) {
 		request.Header.Set("Content-Type", "application/json")
 		router.ServeHTTP(rr, request)
 
-		mockError := apperrors.NewAuthorization(apperrors.MustBeOwner)
+		mockError := apperrors.NewAuthorization(apperrors.MissingPermissions)
 		respBody, err := json.Marshal(gin.H{
 			"error": mockError,
 		})
@@ -1178,7 +1290,9 @@ func TestHandler_KickMember(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionKickMembers).Return(true)
 		mockGuildService.On("GetUser", mockMember.ID).Return(mockMember, nil)
+		mockGuildService.On("OutranksMember", authUser.ID, mockMember.ID, mockGuild.ID).Return(true)
 
 		mockError := apperrors.NewInternal()
 		args := mock.Arguments{
@@ -1277,6 +1391,7 @@ func TestHandler_KickMember(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionKickMembers).Return(true)
 		mockError := apperrors.NewNotFound("user", mockMember.ID)
 		mockGuildService.On("GetUser", mockMember.ID).Return(nil, mockError)
 
@@ -1359,6 +1474,7 @@ func TestHandler_KickMember(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionKickMembers).Return(true)
 		mockGuildService.On("GetUser", authUser.ID).Return(authUser, nil)
 
 		mockSocketService := new(mocks.SocketService)
@@ -1400,6 +1516,104 @@ func TestHandler_KickMember(t *testing.T) {
 		mockSocketService.AssertNotCalled(t, "EmitRemoveMember")
 		mockSocketService.AssertNotCalled(t, "EmitRemoveFromGuild")
 	})
+
+	t.Run("Member is the owner", func(t *testing.T) {
+		mockOwner := fixture.GetMockUser()
+		mockGuild := fixture.GetMockGuild(mockOwner.ID)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionKickMembers).Return(true)
+		mockGuildService.On("GetUser", mockOwner.ID).Return(mockOwner, nil)
+
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:             router,
+			GuildService:  mockGuildService,
+			SocketService: mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"memberId": mockOwner.ID,
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/kick", mockGuild.ID)
+		request, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewBadRequest(apperrors.KickOwnerError)
+		respBody, _ := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+
+		mockGuildService.AssertCalled(t, "GetGuild", mockGuild.ID)
+		mockGuildService.AssertCalled(t, "GetUser", mockOwner.ID)
+		mockGuildService.AssertNotCalled(t, "RemoveMember")
+		mockSocketService.AssertNotCalled(t, "EmitRemoveMember")
+		mockSocketService.AssertNotCalled(t, "EmitRemoveFromGuild")
+	})
+
+	t.Run("Member ranks above the user", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockMember := fixture.GetMockUser()
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionKickMembers).Return(true)
+		mockGuildService.On("GetUser", mockMember.ID).Return(mockMember, nil)
+		mockGuildService.On("OutranksMember", authUser.ID, mockMember.ID, mockGuild.ID).Return(false)
+
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:             router,
+			GuildService:  mockGuildService,
+			SocketService: mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"memberId": mockMember.ID,
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/kick", mockGuild.ID)
+		request, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewAuthorization(apperrors.RoleHierarchyError)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+
+		mockGuildService.AssertExpectations(t)
+		mockGuildService.AssertNotCalled(t, "RemoveMember")
+		mockSocketService.AssertNotCalled(t, "EmitRemoveMember")
+		mockSocketService.AssertNotCalled(t, "EmitRemoveFromGuild")
+	})
 }
 
 func TestHandler_UnbanMember(t *testing.T) {
@@ -1413,6 +1627,7 @@ func TestHandler_UnbanMember(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionBanMembers).Return(true)
 
 		args := mock.Arguments{
 			mockMember.ID,
@@ -1455,6 +1670,7 @@ func TestHandler_UnbanMember(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionBanMembers).Return(false)
 
 		rr := httptest.NewRecorder()
 
@@ -1477,7 +1693,7 @@ func TestHandler_UnbanMember(t *testing.T) {
 		request.Header.Set("Content-Type", "application/json")
 		router.ServeHTTP(rr, request)
 
-		mockError := apperrors.NewAuthorization(apperrors.MustBeOwner)
+		mockError := apperrors.NewAuthorization(apperrors.MissingPermissions)
 		respBody, err := json.Marshal(gin.H{
 			"error": mockError,
 		})
@@ -1536,6 +1752,7 @@ func TestHandler_UnbanMember(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionBanMembers).Return(true)
 
 		mockError := apperrors.NewInternal()
 		args := mock.Arguments{
@@ -1654,6 +1871,7 @@ func TestHandler_UnbanMember(t *testing.T) {
 
 		mockGuildService := new(mocks.GuildService)
 		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionBanMembers).Return(true)
 
 		rr := httptest.NewRecorder()
 
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"log"
+	"net/http"
+)
+
+/*
+ * MentionHandler contains all routes related to mentions (/api/account)
+ */
+
+// GetUserMentions returns the most recent messages the current user got mentioned in.
+// Messages in channels the user can no longer see are left out.
+// GetUserMentions godoc
+// @Tags Account
+// @Summary Get Current User's Mentions
+// @Produce  json
+// @Success 200 {array} model.MentionNotification
+// @Failure 404 {object} model.ErrorResponse
+// @Router /account/me/mentions [get]
+func (h *Handler) GetUserMentions(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+
+	mentions, err := h.messageService.GetUserMentions(userId)
+
+	if err != nil {
+		log.Printf("Unable to find mentions for id: %v\n%v", userId, err)
+		e := apperrors.NewNotFound("mentions", userId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// Check the access once per channel
+	access := make(map[string]bool)
+	result := make([]model.MentionNotification, 0)
+
+	for _, mention := range *mentions {
+		allowed, ok := access[mention.ChannelId]
+
+		if !ok {
+			channel, err := h.channelService.Get(mention.ChannelId)
+			allowed = err == nil && h.channelService.IsChannelMember(channel, userId) == nil
+			access[mention.ChannelId] = allowed
+		}
+
+		if allowed {
+			result = append(result, mention)
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_GetUserMentions(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Mentions in hidden channels are left out", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		visibleChannel := fixture.GetMockChannel(mockGuild.ID)
+		hiddenChannel := fixture.GetMockChannel(mockGuild.ID)
+
+		mentions := make([]model.MentionNotification, 0)
+		for _, channel := range []*model.Channel{visibleChannel, hiddenChannel, visibleChannel} {
+			mentions = append(mentions, model.MentionNotification{
+				GuildId:   &mockGuild.ID,
+				ChannelId: channel.ID,
+				Message:   fixture.GetMockMessageResponse("", channel.ID),
+			})
+		}
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("GetUserMentions", authUser.ID).Return(&mentions, nil)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", visibleChannel.ID).Return(visibleChannel, nil).Once()
+		mockChannelService.On("Get", hiddenChannel.ID).Return(hiddenChannel, nil).Once()
+		mockChannelService.On("IsChannelMember", visibleChannel, authUser.ID).Return(nil)
+		mockChannelService.On("IsChannelMember", hiddenChannel, authUser.ID).Return(apperrors.NewAuthorization(apperrors.Unauthorized))
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		request, err := http.NewRequest(http.MethodGet, "/api/account/me/mentions", nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal([]model.MentionNotification{mentions[0], mentions[2]})
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+		mockMessageService.AssertExpectations(t)
+	})
+
+	t.Run("No mentions", func(t *testing.T) {
+		mentions := make([]model.MentionNotification, 0)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("GetUserMentions", authUser.ID).Return(&mentions, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			MessageService: mockMessageService,
+		})
+
+		request, err := http.NewRequest(http.MethodGet, "/api/account/me/mentions", nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, []byte("[]"), rr.Body.Bytes())
+		mockMessageService.AssertExpectations(t)
+	})
+}
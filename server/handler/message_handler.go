@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
@@ -18,15 +19,80 @@ import (
  * MessageHandler contains all routes related to message actions (/api/messages)
  */
 
-// GetMessages returns messages for the given channel
-// It returns the most recent 35 or the ones after the given cursor
+// messagesReq contains the pagination options for fetching messages.
+// Before, After and Around take the ID of a message in the channel.
+type messagesReq struct {
+	// Fetch the messages older than the given message
+	Before string `form:"before"`
+	// Fetch the messages newer than the given message
+	After string `form:"after"`
+	// Fetch the given message and the messages surrounding it
+	Around string `form:"around"`
+	// Number of messages. Default 35, maximum 100
+	Limit int `form:"limit"`
+} //@name MessagesRequest
+
+func (r messagesReq) validate() error {
+	cursors := 0
+	for _, cursor := range []string{r.Before, r.After, r.Around} {
+		if cursor != "" {
+			cursors++
+		}
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Before, validation.By(func(interface{}) error {
+			if cursors > 1 {
+				return errors.New(apperrors.MultipleCursorsError)
+			}
+			return nil
+		})),
+		validation.Field(&r.Limit, validation.Min(1), validation.Max(model.MaximumMessagePageSize)),
+	)
+}
+
+func (r *messagesReq) sanitize() {
+	r.Before = strings.TrimSpace(r.Before)
+	r.After = strings.TrimSpace(r.After)
+	r.Around = strings.TrimSpace(r.Around)
+
+	if r.Limit == 0 {
+		r.Limit = model.MessagePageSize
+	}
+}
+
+// toPage returns the pagination options and the ID of the cursor message if set
+func (r *messagesReq) toPage() (*model.MessagePage, string) {
+	page := &model.MessagePage{Limit: r.Limit}
+
+	switch {
+	case r.Before != "":
+		page.Before = &r.Before
+		return page, r.Before
+	case r.After != "":
+		page.After = &r.After
+		return page, r.After
+	case r.Around != "":
+		page.Around = &r.Around
+		return page, r.Around
+	}
+
+	return page, ""
+}
+
+// GetMessages returns messages for the given channel, newest first.
+// It returns the most recent 35 or the ones before, after or around the given message
 // GetMessages godoc
 // @Tags Messages
 // @Summary Get Channel Messages
 // @Produce  json
 // @Param channelId path string true "Channel ID"
-// @Param cursor query string false "Cursor Pagination using the createdAt field"
+// @Param before query string false "Fetch the messages before this message ID"
+// @Param after query string false "Fetch the messages after this message ID"
+// @Param around query string false "Fetch the messages around this message ID"
+// @Param limit query int false "Number of messages, default 35 and maximum 100"
 // @Success 200 {array} model.MessageResponse
+// @Failure 400 {object} model.ErrorsResponse
 // @Failure 401 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Router /messages/{channelId} [get]
@@ -34,6 +100,13 @@ func (h *Handler) GetMessages(c *gin.Context) {
 	channelId := c.Param("channelId")
 	userId := c.MustGet("userId").(string)
 
+	var req messagesReq
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	req.sanitize()
+
 	channel, err := h.channelService.Get(channelId)
 
 	if err != nil {
@@ -55,10 +128,23 @@ func (h *Handler) GetMessages(c *gin.Context) {
 		return
 	}
 
-	// Cursor is based on the created_at field of the message
-	cursor := c.Query("cursor")
+	page, cursor := req.toPage()
+
+	// The cursor must be a message of the channel
+	if cursor != "" {
+		message, err := h.messageService.Get(cursor)
 
-	messages, err := h.messageService.GetMessages(userId, channel, cursor)
+		if err != nil || message.ChannelId != channelId {
+			e := apperrors.NewNotFound("message", cursor)
+
+			c.JSON(e.Status(), gin.H{
+				"error": e,
+			})
+			return
+		}
+	}
+
+	messages, err := h.messageService.GetMessages(userId, channel, page)
 
 	if err != nil {
 		e := apperrors.NewNotFound("messages", channelId)
@@ -80,22 +166,33 @@ func (h *Handler) GetMessages(c *gin.Context) {
 }
 
 // messageRequest contains all field required to create a message.
-// Either text or file must be provided
+// Either text or files must be provided
 type messageRequest struct {
 	// Maximum 2000 characters
 	Text *string `form:"text"`
-	// image/* or audio/*
-	File *multipart.FileHeader `form:"file" swaggertype:"string" format:"binary"`
+	// One of the allowed file types. Repeat the field for up to 10 files
+	Files []*multipart.FileHeader `form:"file" swaggertype:"array,string" format:"binary"`
+	// IDs of finalized direct uploads. Repeat the field for up to 10 files including the sent files
+	UploadIds []string `form:"uploadId"`
+	// ID of the message in the same channel this message replies to. Ignored when editing
+	ReplyToId *string `form:"replyToId"`
+	// Notify the author of the replied to message. Ignored when editing
+	MentionReply bool `form:"mentionReply"`
 } //@name MessageRequest
 
 func (r messageRequest) validate() error {
 	return validation.ValidateStruct(&r,
 		validation.Field(&r.Text,
 			validation.NilOrNotEmpty,
-			validation.Required.When(r.File == nil).
+			validation.Required.When(len(r.Files) == 0 && len(r.UploadIds) == 0).
 				Error(apperrors.MessageOrFileRequired),
 			validation.Length(1, 2000),
 		),
+		validation.Field(&r.Files, validation.Length(0, model.MaximumAttachments).
+			Error(apperrors.AttachmentLimitError)),
+		validation.Field(&r.UploadIds, validation.Length(0, model.MaximumAttachments-len(r.Files)).
+			Error(apperrors.AttachmentLimitError)),
+		validation.Field(&r.ReplyToId, validation.NilOrNotEmpty),
 	)
 }
 
@@ -104,6 +201,17 @@ func (r *messageRequest) sanitize() {
 		text := strings.TrimSpace(*r.Text)
 		r.Text = &text
 	}
+
+	// Every upload can only be attached once
+	seen := make(map[string]bool)
+	uploadIds := make([]string, 0)
+	for _, id := range r.UploadIds {
+		if !seen[id] {
+			seen[id] = true
+			uploadIds = append(uploadIds, id)
+		}
+	}
+	r.UploadIds = uploadIds
 }
 
 // CreateMessage creates a message in the given channel
@@ -152,6 +260,15 @@ func (h *Handler) CreateMessage(c *gin.Context) {
 		return
 	}
 
+	// Check if the channel is read only for the user
+	if !h.channelService.HasPermission(userId, channel, model.PermissionSendMessages) {
+		e := apperrors.NewAuthorization(apperrors.SendMessagesError)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
 	author, err := h.userService.Get(userId)
 
 	if err != nil {
@@ -169,29 +286,39 @@ func (h *Handler) CreateMessage(c *gin.Context) {
 
 	params.Text = req.Text
 
-	if req.File != nil {
-		mimeType := req.File.Header.Get("Content-Type")
+	// Replies can only reference messages in the same channel
+	var reference *model.Message
+	if req.ReplyToId != nil {
+		reference, err = h.messageService.Get(*req.ReplyToId)
 
-		if valid := isAllowedFileType(mimeType); !valid {
-			toFieldErrorResponse(c, "File", apperrors.InvalidImageType)
+		if err != nil || reference.ChannelId != channel.ID {
+			e := apperrors.NewNotFound("message", *req.ReplyToId)
+			c.JSON(e.Status(), gin.H{
+				"error": e,
+			})
 			return
 		}
 
-		// Prevent file upload on the live server.
-		// Remove the if part if you do want upload
-		var attachment *model.Attachment
-		if gin.Mode() == gin.ReleaseMode {
-			id, _ := gonanoid.Nanoid(20)
-
-			// Random image to test files in the app
-			attachment = &model.Attachment{
-				ID:       id,
-				Url:      fmt.Sprintf("https://picsum.photos/seed/%s/600", id),
-				FileType: "image/jpeg",
-				Filename: id,
+		params.ReplyToId = &reference.ID
+	}
+
+	if len(req.Files) > 0 || len(req.UploadIds) > 0 {
+		for _, file := range req.Files {
+			if ok := h.checkFile(c, "File", file, false); !ok {
+				return
 			}
-		} else {
-			attachment, err = h.messageService.UploadFile(req.File, channel.ID)
+		}
+
+		if !h.channelService.HasPermission(userId, channel, model.PermissionAttachFiles) {
+			e := apperrors.NewAuthorization(apperrors.AttachFilesError)
+			c.JSON(e.Status(), gin.H{
+				"error": e,
+			})
+			return
+		}
+
+		for i, file := range req.Files {
+			attachment, err := h.uploadAttachment(file, channel.ID)
 
 			if err != nil {
 				c.JSON(apperrors.Status(err), gin.H{
@@ -199,9 +326,35 @@ func (h *Handler) CreateMessage(c *gin.Context) {
 				})
 				return
 			}
+
+			attachment.Position = i
+			params.Attachments = append(params.Attachments, *attachment)
 		}
 
-		params.Attachment = attachment
+		// Direct uploads follow the sent files
+		for i, uploadId := range req.UploadIds {
+			upload, err := h.messageService.GetUpload(uploadId)
+
+			if err != nil || upload.UserId != userId || upload.ChannelId != channel.ID {
+				e := apperrors.NewNotFound("upload", uploadId)
+				c.JSON(e.Status(), gin.H{
+					"error": e,
+				})
+				return
+			}
+
+			attachment, err := h.messageService.FinalizeUpload(upload)
+
+			if err != nil {
+				c.JSON(apperrors.Status(err), gin.H{
+					"error": err,
+				})
+				return
+			}
+
+			attachment.Position = len(req.Files) + i
+			params.Attachments = append(params.Attachments, *attachment)
+		}
 	}
 
 	message, err := h.messageService.CreateMessage(&params)
@@ -215,11 +368,11 @@ func (h *Handler) CreateMessage(c *gin.Context) {
 	}
 
 	response := model.MessageResponse{
-		Id:         message.ID,
-		Text:       message.Text,
-		CreatedAt:  message.CreatedAt,
-		UpdatedAt:  message.UpdatedAt,
-		Attachment: message.Attachment,
+		Id:          message.ID,
+		Text:        message.Text,
+		CreatedAt:   message.CreatedAt,
+		UpdatedAt:   message.UpdatedAt,
+		Attachments: message.Attachments,
 		User: model.MemberResponse{
 			Id:        author.ID,
 			Username:  author.Username,
@@ -228,7 +381,14 @@ func (h *Handler) CreateMessage(c *gin.Context) {
 			CreatedAt: author.CreatedAt,
 			UpdatedAt: author.UpdatedAt,
 			IsFriend:  false,
+			Bot:       author.Bot,
 		},
+		Reactions: make([]model.ReactionResponse, 0),
+		Mentions:  h.getMessageMentions(channel, message),
+	}
+
+	if response.Attachments == nil {
+		response.Attachments = make([]model.Attachment, 0)
 	}
 
 	// Get member settings if it is not a DM
@@ -238,14 +398,50 @@ func (h *Handler) CreateMessage(c *gin.Context) {
 		response.User.Color = settings.Color
 	}
 
+	if reference != nil {
+		response.ReplyTo = h.getMessageReference(reference, channel)
+	}
+
 	// Emit new message to the channel
 	h.socketService.EmitNewMessage(channelId, &response)
 
+	// Get the users that got pinged in the message
+	mentioned := make([]string, 0)
+	if len(message.Mentions) > 0 {
+		if users, err := h.messageService.GetMentionedUsers(channel, message); err == nil {
+			mentioned = users
+		}
+	}
+
+	// Also notify the replied to author if they got pinged
+	if reference != nil && req.MentionReply {
+		mentioned = append(mentioned, reference.UserId)
+	}
+
+	h.emitMentions(channel, userId, mentioned, &response)
+
 	if channel.IsDM {
 		// Open the DM and push it to the top
 		_ = h.channelService.OpenDMForAll(channelId)
 		// Post a notification
 		h.socketService.EmitNewDMNotification(channelId, author)
+	} else if channel.IsThread() {
+		// Posting in an archived thread unarchives it
+		wasArchived := channel.IsArchived
+		channel.IsArchived = false
+		// Update last activity in the thread
+		channel.LastActivity = time.Now()
+		_ = h.channelService.UpdateChannel(channel)
+		// Members automatically join the threads they post in
+		_ = h.channelService.AddThreadMember(channelId, userId)
+
+		if wasArchived {
+			thread := channel.SerializeThread()
+			h.socketService.EmitEditThread(&thread)
+		}
+
+		// Post a notification for the parent channel
+		h.socketService.EmitNewNotification(*channel.GuildID, *channel.ParentID)
 	} else {
 		// Update last activity in channel
 		channel.LastActivity = time.Now()
@@ -257,6 +453,25 @@ func (h *Handler) CreateMessage(c *gin.Context) {
 	c.JSON(http.StatusCreated, true)
 }
 
+// uploadAttachment uploads the given file as an attachment of the given channel
+func (h *Handler) uploadAttachment(file *multipart.FileHeader, channelId string) (*model.Attachment, error) {
+	// Prevent file upload on the live server.
+	// Remove the if part if you do want upload
+	if gin.Mode() == gin.ReleaseMode {
+		id, _ := gonanoid.Nanoid(20)
+
+		// Random image to test files in the app
+		return &model.Attachment{
+			ID:       id,
+			Url:      fmt.Sprintf("https://picsum.photos/seed/%s/600", id),
+			FileType: "image/jpeg",
+			Filename: id,
+		}, nil
+	}
+
+	return h.messageService.UploadFile(file, channelId)
+}
+
 // EditMessage edits the given message with the given text
 // EditMessage godoc
 // @Tags Messages
@@ -311,14 +526,42 @@ func (h *Handler) EditMessage(c *gin.Context) {
 	}
 
 	response := model.MessageResponse{
-		Id:         message.ID,
-		Text:       message.Text,
-		CreatedAt:  message.CreatedAt,
-		UpdatedAt:  message.UpdatedAt,
-		Attachment: message.Attachment,
+		Id:          message.ID,
+		Text:        message.Text,
+		CreatedAt:   message.CreatedAt,
+		UpdatedAt:   message.UpdatedAt,
+		Attachments: message.Attachments,
 		User: model.MemberResponse{
 			Id: userId,
 		},
+		Reactions: make([]model.ReactionResponse, 0),
+		Mentions:  model.NewMessageMentions(),
+		Edited:    message.EditCount > 0,
+		EditCount: message.EditCount,
+	}
+
+	if response.Attachments == nil {
+		response.Attachments = make([]model.Attachment, 0)
+	}
+
+	// Editing keeps the reactions of the message
+	if reactions, err := h.messageService.GetMessageReactions(message.ID); err == nil {
+		response.Reactions = reactions
+	}
+
+	// Resolve the mentions of the edited text and the message it replies to
+	if len(message.Mentions) > 0 || message.ReplyToId != nil {
+		if channel, err := h.channelService.Get(message.ChannelId); err == nil {
+			response.Mentions = h.getMessageMentions(channel, message)
+
+			if message.ReplyToId != nil {
+				response.ReplyTo = model.DeletedMessageReference(*message.ReplyToId)
+
+				if reference, err := h.messageService.Get(*message.ReplyToId); err == nil {
+					response.ReplyTo = h.getMessageReference(reference, channel)
+				}
+			}
+		}
 	}
 
 	// Emit edited message to the channel
@@ -363,20 +606,9 @@ func (h *Handler) DeleteMessage(c *gin.Context) {
 		return
 	}
 
-	// Check if message author or guild owner
+	// Check if message author or allowed to manage messages
 	if !channel.IsDM {
-		guild, err := h.guildService.GetGuild(*channel.GuildID)
-
-		if err != nil {
-			e := apperrors.NewNotFound("message", messageId)
-
-			c.JSON(e.Status(), gin.H{
-				"error": e,
-			})
-			return
-		}
-
-		if message.UserId != userId && guild.OwnerId != userId {
+		if message.UserId != userId && !h.channelService.HasPermission(userId, channel, model.PermissionManageMessages) {
 			e := apperrors.NewAuthorization(apperrors.DeleteMessageError)
 			c.JSON(e.Status(), gin.H{
 				"error": e,
@@ -407,3 +639,133 @@ func (h *Handler) DeleteMessage(c *gin.Context) {
 
 	c.JSON(http.StatusOK, true)
 }
+
+// GetMessageHistory returns the previous texts of the given message
+// GetMessageHistory godoc
+// @Tags Messages
+// @Summary Get Message Edit History
+// @Produce  json
+// @Param messageId path string true "Message ID"
+// @Success 200 {array} model.MessageRevision
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /messages/{messageId}/history [get]
+func (h *Handler) GetMessageHistory(c *gin.Context) {
+	// The route shares the channelId parameter with GetMessages
+	messageId := c.Param("channelId")
+	userId := c.MustGet("userId").(string)
+	message, err := h.messageService.Get(messageId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("message", messageId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	channel, err := h.channelService.Get(message.ChannelId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("message", messageId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// Check if the user has access to said channel
+	if err = h.channelService.IsChannelMember(channel, userId); err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	revisions, err := h.messageService.GetMessageRevisions(message.ID)
+
+	if err != nil {
+		log.Printf("Unable to find revisions for message: %v\n%v", message.ID, err)
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	// If the message was never edited, return an empty array
+	if len(*revisions) == 0 {
+		var empty = make([]model.MessageRevision, 0)
+		c.JSON(http.StatusOK, empty)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// getMessageReference returns the snapshot of the given message including its author's settings
+func (h *Handler) getMessageReference(message *model.Message, channel *model.Channel) *model.MessageReference {
+	author, err := h.userService.Get(message.UserId)
+
+	// The author's account no longer exists
+	if err != nil {
+		return model.NewMessageReference(message.ID, message.Text, len(message.Attachments) > 0, nil)
+	}
+
+	user := &model.ReferenceAuthor{
+		Id:       author.ID,
+		Username: author.Username,
+		Image:    author.Image,
+	}
+
+	// Get member settings if it is not a DM
+	if !channel.IsDM {
+		if settings, err := h.guildService.GetMemberSettings(author.ID, *channel.GuildID); err == nil {
+			user.Nickname = settings.Nickname
+			user.Color = settings.Color
+		}
+	}
+
+	return model.NewMessageReference(message.ID, message.Text, len(message.Attachments) > 0, user)
+}
+
+// getMessageMentions returns the resolved mentions of the given message
+func (h *Handler) getMessageMentions(channel *model.Channel, message *model.Message) model.MessageMentions {
+	if len(message.Mentions) == 0 {
+		return model.NewMessageMentions()
+	}
+
+	mentions, err := h.messageService.GetMessageMentions(channel, message.ID)
+
+	if err != nil {
+		log.Printf("Unable to resolve the mentions of message %s: %v\n", message.ID, err)
+		return model.NewMessageMentions()
+	}
+
+	return *mentions
+}
+
+// emitMentions notifies the given users that they got pinged in the message.
+// The author and users that cannot see the channel do not get notified.
+func (h *Handler) emitMentions(channel *model.Channel, authorId string, users []string, message *model.MessageResponse) {
+	notified := map[string]bool{authorId: true}
+
+	for _, id := range users {
+		if notified[id] {
+			continue
+		}
+		notified[id] = true
+
+		if err := h.channelService.IsChannelMember(channel, id); err != nil {
+			continue
+		}
+
+		h.socketService.EmitNewMention(id, &model.MentionNotification{
+			GuildId:   channel.GuildID,
+			ChannelId: channel.ID,
+			Message:   message,
+		})
+	}
+}
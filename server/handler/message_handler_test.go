@@ -10,6 +10,7 @@ import (
 	"github.com/sentrionic/valkyrie/model/fixture"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -34,7 +35,7 @@ func TestHandler_GetMessages(t *testing.T) {
 		args := mock.Arguments{
 			authUser.ID,
 			mockChannel,
-			"",
+			&model.MessagePage{Limit: model.MessagePageSize},
 		}
 
 		response := make([]model.MessageResponse, 0)
@@ -70,6 +71,91 @@ func TestHandler_GetMessages(t *testing.T) {
 		mockMessageService.AssertExpectations(t)
 	})
 
+	t.Run("Successful fetch around a message", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockMessage := fixture.GetMockMessage("", mockChannel.ID)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+
+		response := []model.MessageResponse{
+			*fixture.GetMockMessageResponse("", mockChannel.ID),
+			*fixture.GetMockMessageResponse("", mockChannel.ID),
+		}
+
+		page := &model.MessagePage{
+			Around: &mockMessage.ID,
+			Limit:  50,
+		}
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+		mockMessageService.On("GetMessages", authUser.ID, mockChannel, page).Return(&response, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		reqUrl := "/api/messages/" + mockChannel.ID + "?limit=50&around=" + mockMessage.ID
+		request, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+		mockMessageService.AssertExpectations(t)
+	})
+
+	t.Run("Cursor is not a message of the channel", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockMessage := fixture.GetMockMessage("", fixture.RandID())
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		reqUrl := "/api/messages/" + mockChannel.ID + "?before=" + mockMessage.ID
+		request, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewNotFound("message", mockMessage.ID)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockMessageService.AssertNotCalled(t, "GetMessages", mock.Anything, mock.Anything, mock.Anything)
+	})
+
 	t.Run("No channel found", func(t *testing.T) {
 		id := fixture.RandID()
 		mockError := apperrors.NewNotFound("channel", id)
@@ -192,7 +278,7 @@ func TestHandler_GetMessages(t *testing.T) {
 		args := mock.Arguments{
 			authUser.ID,
 			mockChannel,
-			"",
+			&model.MessagePage{Limit: model.MessagePageSize},
 		}
 		mockError := apperrors.NewNotFound("messages", mockChannel.ID)
 		mockMessageService.On("GetMessages", args...).Return(nil, mockError)
@@ -225,6 +311,62 @@ func TestHandler_GetMessages(t *testing.T) {
 	})
 }
 
+func TestHandler_GetMessages_BadRequest(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+	channelId := fixture.RandID()
+
+	mockChannelService := new(mocks.ChannelService)
+	mockMessageService := new(mocks.MessageService)
+
+	router := getAuthenticatedTestRouter(authUser.ID)
+
+	NewHandler(&Config{
+		R:              router,
+		ChannelService: mockChannelService,
+		MessageService: mockMessageService,
+	})
+
+	testCases := []struct {
+		name  string
+		query url.Values
+	}{
+		{
+			name:  "Multiple cursors",
+			query: url.Values{"before": {fixture.RandID()}, "after": {fixture.RandID()}},
+		},
+		{
+			name:  "After and around",
+			query: url.Values{"after": {fixture.RandID()}, "around": {fixture.RandID()}},
+		},
+		{
+			name:  "Negative limit",
+			query: url.Values{"limit": {"-1"}},
+		},
+		{
+			name:  "Limit too large",
+			query: url.Values{"limit": {"101"}},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/messages/"+channelId+"?"+tc.query.Encode(), nil)
+			assert.NoError(t, err)
+
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			mockChannelService.AssertNotCalled(t, "Get", mock.Anything)
+			mockMessageService.AssertNotCalled(t, "GetMessages", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
 func TestHandler_CreateMessage(t *testing.T) {
 	// Setup
 	gin.SetMode(gin.TestMode)
@@ -238,6 +380,7 @@ func TestHandler_CreateMessage(t *testing.T) {
 		mockChannelService := new(mocks.ChannelService)
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
 		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(true)
 
 		mockUserService := new(mocks.UserService)
 		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
@@ -255,11 +398,11 @@ func TestHandler_CreateMessage(t *testing.T) {
 
 		mockSocketService := new(mocks.SocketService)
 		response := model.MessageResponse{
-			Id:         mockMessage.ID,
-			Text:       mockMessage.Text,
-			CreatedAt:  mockMessage.CreatedAt,
-			UpdatedAt:  mockMessage.UpdatedAt,
-			Attachment: mockMessage.Attachment,
+			Id:          mockMessage.ID,
+			Text:        mockMessage.Text,
+			CreatedAt:   mockMessage.CreatedAt,
+			UpdatedAt:   mockMessage.UpdatedAt,
+			Attachments: make([]model.Attachment, 0),
 			User: model.MemberResponse{
 				Id:        authUser.ID,
 				Username:  authUser.Username,
@@ -269,6 +412,8 @@ func TestHandler_CreateMessage(t *testing.T) {
 				UpdatedAt: authUser.UpdatedAt,
 				IsFriend:  false,
 			},
+			Reactions: make([]model.ReactionResponse, 0),
+			Mentions:  model.NewMessageMentions(),
 		}
 
 		mockSocketService.On("EmitNewMessage", mockChannel.ID, &response).Return()
@@ -409,24 +554,27 @@ func TestHandler_CreateMessage(t *testing.T) {
 		mockSocketService.AssertNotCalled(t, "EmitNewMessage")
 	})
 
-	t.Run("Unauthorized", func(t *testing.T) {
-		id := fixture.RandID()
+	t.Run("Read only channel", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockError := apperrors.NewAuthorization(apperrors.SendMessagesError)
 
 		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(false)
+
 		mockUserService := new(mocks.UserService)
 		mockMessageService := new(mocks.MessageService)
-		mockGuildService := new(mocks.GuildService)
 		mockSocketService := new(mocks.SocketService)
 
 		rr := httptest.NewRecorder()
 
-		router := getTestRouter()
+		router := getAuthenticatedTestRouter(authUser.ID)
 
 		NewHandler(&Config{
 			R:              router,
 			ChannelService: mockChannelService,
 			MessageService: mockMessageService,
-			GuildService:   mockGuildService,
 			SocketService:  mockSocketService,
 			UserService:    mockUserService,
 		})
@@ -434,14 +582,13 @@ func TestHandler_CreateMessage(t *testing.T) {
 		form := url.Values{}
 		form.Add("text", fixture.RandStringRunes(8))
 
-		request, err := http.NewRequest(http.MethodPost, "/api/messages/"+id, strings.NewReader(form.Encode()))
+		request, err := http.NewRequest(http.MethodPost, "/api/messages/"+mockChannel.ID, strings.NewReader(form.Encode()))
 		assert.NoError(t, err)
 		request.Form = form
 
 		router.ServeHTTP(rr, request)
 
-		mockError := apperrors.NewAuthorization(apperrors.InvalidSession)
-		respBody, _ := json.Marshal(gin.H{
+		respBody, err := json.Marshal(gin.H{
 			"error": mockError,
 		})
 		assert.NoError(t, err)
@@ -449,35 +596,26 @@ func TestHandler_CreateMessage(t *testing.T) {
 		assert.Equal(t, mockError.Status(), rr.Code)
 		assert.Equal(t, respBody, rr.Body.Bytes())
 
-		mockChannelService.AssertNotCalled(t, "Get")
-		mockChannelService.AssertNotCalled(t, "IsChannelMember")
+		mockChannelService.AssertExpectations(t)
 		mockUserService.AssertNotCalled(t, "Get")
 		mockMessageService.AssertNotCalled(t, "CreateMessage")
 		mockSocketService.AssertNotCalled(t, "EmitNewMessage")
 	})
 
-	t.Run("Text Message Creation failure", func(t *testing.T) {
-		mockGuild := fixture.GetMockGuild("")
-		mockChannel := fixture.GetMockChannel(mockGuild.ID)
-		mockMessage := fixture.GetMockMessage(authUser.ID, mockChannel.ID)
+	t.Run("Not allowed to attach files", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockError := apperrors.NewAuthorization(apperrors.AttachFilesError)
 
 		mockChannelService := new(mocks.ChannelService)
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
 		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(true)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionAttachFiles).Return(false)
 
 		mockUserService := new(mocks.UserService)
 		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
 
-		params := model.Message{
-			UserId:    mockMessage.UserId,
-			ChannelId: mockMessage.ChannelId,
-			Text:      mockMessage.Text,
-		}
-		mockError := apperrors.NewInternal()
 		mockMessageService := new(mocks.MessageService)
-		mockMessageService.On("CreateMessage", &params).Return(nil, mockError)
-
-		mockGuildService := new(mocks.GuildService)
 		mockSocketService := new(mocks.SocketService)
 
 		rr := httptest.NewRecorder()
@@ -488,17 +626,17 @@ func TestHandler_CreateMessage(t *testing.T) {
 			R:              router,
 			ChannelService: mockChannelService,
 			MessageService: mockMessageService,
-			GuildService:   mockGuildService,
 			SocketService:  mockSocketService,
 			UserService:    mockUserService,
 		})
 
-		form := url.Values{}
-		form.Add("text", *mockMessage.Text)
+		multipartImageFixture := fixture.NewMultipartImage("image.png", "image/png")
+		defer multipartImageFixture.Close()
 
-		request, err := http.NewRequest(http.MethodPost, "/api/messages/"+mockChannel.ID, strings.NewReader(form.Encode()))
+		request, err := http.NewRequest(http.MethodPost, "/api/messages/"+mockChannel.ID, multipartImageFixture.MultipartBody)
 		assert.NoError(t, err)
-		request.Form = form
+
+		request.Header.Set("Content-Type", multipartImageFixture.ContentType)
 
 		router.ServeHTTP(rr, request)
 
@@ -510,27 +648,24 @@ func TestHandler_CreateMessage(t *testing.T) {
 		assert.Equal(t, mockError.Status(), rr.Code)
 		assert.Equal(t, respBody, rr.Body.Bytes())
 
-		mockChannelService.AssertCalled(t, "Get", mockChannel.ID)
-		mockChannelService.AssertCalled(t, "IsChannelMember", mockChannel, authUser.ID)
-		mockMessageService.AssertCalled(t, "CreateMessage", &params)
-		mockUserService.AssertCalled(t, "Get", authUser.ID)
-		mockChannelService.AssertNotCalled(t, "UpdateChannel")
+		mockChannelService.AssertExpectations(t)
+		mockMessageService.AssertNotCalled(t, "UploadFile")
+		mockMessageService.AssertNotCalled(t, "CreateMessage")
 		mockSocketService.AssertNotCalled(t, "EmitNewMessage")
 	})
 
-	t.Run("Disallowed mimetype", func(t *testing.T) {
-		mockGuild := fixture.GetMockGuild("")
-		mockChannel := fixture.GetMockChannel(mockGuild.ID)
+	t.Run("File content does not match its type", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
 
 		mockChannelService := new(mocks.ChannelService)
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
 		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(true)
 
 		mockUserService := new(mocks.UserService)
 		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
 
 		mockMessageService := new(mocks.MessageService)
-		mockGuildService := new(mocks.GuildService)
 		mockSocketService := new(mocks.SocketService)
 
 		rr := httptest.NewRecorder()
@@ -541,12 +676,12 @@ func TestHandler_CreateMessage(t *testing.T) {
 			R:              router,
 			ChannelService: mockChannelService,
 			MessageService: mockMessageService,
-			GuildService:   mockGuildService,
 			SocketService:  mockSocketService,
 			UserService:    mockUserService,
 		})
 
-		multipartImageFixture := fixture.NewMultipartImage("image.txt", "image/txt")
+		// The fixture contains a png
+		multipartImageFixture := fixture.NewMultipartImage("document.pdf", "application/pdf")
 		defer multipartImageFixture.Close()
 
 		request, err := http.NewRequest(http.MethodPost, "/api/messages/"+mockChannel.ID, multipartImageFixture.MultipartBody)
@@ -556,79 +691,30 @@ func TestHandler_CreateMessage(t *testing.T) {
 
 		router.ServeHTTP(rr, request)
 
+		respBody, err := json.Marshal(getTestFieldErrorResponse("File", apperrors.FileTypeMismatchError))
 		assert.NoError(t, err)
+
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
 
-		mockChannelService.AssertCalled(t, "Get", mockChannel.ID)
-		mockChannelService.AssertCalled(t, "IsChannelMember", mockChannel, authUser.ID)
-		mockUserService.AssertCalled(t, "Get", authUser.ID)
-		mockMessageService.AssertNotCalled(t, "UploadFile")
-		mockMessageService.AssertNotCalled(t, "CreateMessage")
-		mockChannelService.AssertNotCalled(t, "UpdateChannel")
-		mockSocketService.AssertNotCalled(t, "EmitNewMessage")
+		mockMessageService.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything)
+		mockMessageService.AssertNotCalled(t, "CreateMessage", mock.Anything)
+		mockSocketService.AssertNotCalled(t, "EmitNewMessage", mock.Anything, mock.Anything)
 	})
 
-	t.Run("Image Message Creation Success", func(t *testing.T) {
-		mockGuild := fixture.GetMockGuild("")
-		mockChannel := fixture.GetMockChannel(mockGuild.ID)
-		mockMessage := fixture.GetMockMessage(authUser.ID, mockChannel.ID)
-		mockMessage.Text = nil
-
-		uploadImageFixture := fixture.NewMultipartImage("image.png", "image/png")
-		defer uploadImageFixture.Close()
-		formFile := uploadImageFixture.GetFormFile()
-
-		attachment := &model.Attachment{
-			ID:        fixture.RandID(),
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-			Url:       fixture.RandStringRunes(8),
-			FileType:  "image/png",
-			Filename:  fixture.RandStringRunes(8),
-			MessageId: mockMessage.ID,
-		}
-		mockMessage.Attachment = attachment
+	t.Run("File exceeds the size limit of its type", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
 
 		mockChannelService := new(mocks.ChannelService)
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
 		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(true)
 
 		mockUserService := new(mocks.UserService)
 		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
 
-		params := model.Message{
-			UserId:     mockMessage.UserId,
-			ChannelId:  mockMessage.ChannelId,
-			Attachment: attachment,
-		}
 		mockMessageService := new(mocks.MessageService)
-		mockMessageService.On("UploadFile", formFile, mockChannel.ID).Return(attachment, nil)
-		mockMessageService.On("CreateMessage", &params).Return(mockMessage, nil)
-
-		mockGuildService := new(mocks.GuildService)
-		mockGuildService.On("GetMemberSettings", authUser.ID, mockGuild.ID).Return(&model.MemberSettings{}, nil)
-
 		mockSocketService := new(mocks.SocketService)
-		response := model.MessageResponse{
-			Id:         mockMessage.ID,
-			Text:       nil,
-			CreatedAt:  mockMessage.CreatedAt,
-			UpdatedAt:  mockMessage.UpdatedAt,
-			Attachment: attachment,
-			User: model.MemberResponse{
-				Id:        authUser.ID,
-				Username:  authUser.Username,
-				Image:     authUser.Image,
-				IsOnline:  authUser.IsOnline,
-				CreatedAt: authUser.CreatedAt,
-				UpdatedAt: authUser.UpdatedAt,
-				IsFriend:  false,
-			},
-		}
-
-		mockSocketService.On("EmitNewMessage", mockChannel.ID, &response).Return()
-		mockChannelService.On("UpdateChannel", mockChannel).Return(nil)
-		mockSocketService.On("EmitNewNotification", mockGuild.ID, mockChannel.ID)
 
 		rr := httptest.NewRecorder()
 
@@ -638,9 +724,9 @@ func TestHandler_CreateMessage(t *testing.T) {
 			R:              router,
 			ChannelService: mockChannelService,
 			MessageService: mockMessageService,
-			GuildService:   mockGuildService,
 			SocketService:  mockSocketService,
 			UserService:    mockUserService,
+			FileTypes:      model.FileTypes{"image/png": 10},
 		})
 
 		multipartImageFixture := fixture.NewMultipartImage("image.png", "image/png")
@@ -653,60 +739,782 @@ func TestHandler_CreateMessage(t *testing.T) {
 
 		router.ServeHTTP(rr, request)
 
-		respBody, err := json.Marshal(true)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+
+		mockMessageService.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything)
+		mockMessageService.AssertNotCalled(t, "CreateMessage", mock.Anything)
+		mockSocketService.AssertNotCalled(t, "EmitNewMessage", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		id := fixture.RandID()
+
+		mockChannelService := new(mocks.ChannelService)
+		mockUserService := new(mocks.UserService)
+		mockMessageService := new(mocks.MessageService)
+		mockGuildService := new(mocks.GuildService)
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+			GuildService:   mockGuildService,
+			SocketService:  mockSocketService,
+			UserService:    mockUserService,
+		})
+
+		form := url.Values{}
+		form.Add("text", fixture.RandStringRunes(8))
+
+		request, err := http.NewRequest(http.MethodPost, "/api/messages/"+id, strings.NewReader(form.Encode()))
 		assert.NoError(t, err)
+		request.Form = form
 
-		assert.Equal(t, http.StatusCreated, rr.Code)
-		assert.Equal(t, respBody, rr.Body.Bytes())
+		router.ServeHTTP(rr, request)
 
-		mockChannelService.AssertExpectations(t)
-		mockMessageService.AssertExpectations(t)
-		mockGuildService.AssertExpectations(t)
-		mockSocketService.AssertExpectations(t)
-		mockUserService.AssertExpectations(t)
+		mockError := apperrors.NewAuthorization(apperrors.InvalidSession)
+		respBody, _ := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+
+		mockChannelService.AssertNotCalled(t, "Get")
+		mockChannelService.AssertNotCalled(t, "IsChannelMember")
+		mockUserService.AssertNotCalled(t, "Get")
+		mockMessageService.AssertNotCalled(t, "CreateMessage")
+		mockSocketService.AssertNotCalled(t, "EmitNewMessage")
+	})
+
+	t.Run("Text Message Creation failure", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockChannel := fixture.GetMockChannel(mockGuild.ID)
+		mockMessage := fixture.GetMockMessage(authUser.ID, mockChannel.ID)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(true)
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
+
+		params := model.Message{
+			UserId:    mockMessage.UserId,
+			ChannelId: mockMessage.ChannelId,
+			Text:      mockMessage.Text,
+		}
+		mockError := apperrors.NewInternal()
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("CreateMessage", &params).Return(nil, mockError)
+
+		mockGuildService := new(mocks.GuildService)
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+			GuildService:   mockGuildService,
+			SocketService:  mockSocketService,
+			UserService:    mockUserService,
+		})
+
+		form := url.Values{}
+		form.Add("text", *mockMessage.Text)
+
+		request, err := http.NewRequest(http.MethodPost, "/api/messages/"+mockChannel.ID, strings.NewReader(form.Encode()))
+		assert.NoError(t, err)
+		request.Form = form
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+
+		mockChannelService.AssertCalled(t, "Get", mockChannel.ID)
+		mockChannelService.AssertCalled(t, "IsChannelMember", mockChannel, authUser.ID)
+		mockMessageService.AssertCalled(t, "CreateMessage", &params)
+		mockUserService.AssertCalled(t, "Get", authUser.ID)
+		mockChannelService.AssertNotCalled(t, "UpdateChannel")
+		mockSocketService.AssertNotCalled(t, "EmitNewMessage")
+	})
+
+	t.Run("Disallowed mimetype", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockChannel := fixture.GetMockChannel(mockGuild.ID)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(true)
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
+
+		mockMessageService := new(mocks.MessageService)
+		mockGuildService := new(mocks.GuildService)
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+			GuildService:   mockGuildService,
+			SocketService:  mockSocketService,
+			UserService:    mockUserService,
+		})
+
+		multipartImageFixture := fixture.NewMultipartImage("image.txt", "image/txt")
+		defer multipartImageFixture.Close()
+
+		request, err := http.NewRequest(http.MethodPost, "/api/messages/"+mockChannel.ID, multipartImageFixture.MultipartBody)
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", multipartImageFixture.ContentType)
+
+		router.ServeHTTP(rr, request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+		mockChannelService.AssertCalled(t, "Get", mockChannel.ID)
+		mockChannelService.AssertCalled(t, "IsChannelMember", mockChannel, authUser.ID)
+		mockUserService.AssertCalled(t, "Get", authUser.ID)
+		mockMessageService.AssertNotCalled(t, "UploadFile")
+		mockMessageService.AssertNotCalled(t, "CreateMessage")
+		mockChannelService.AssertNotCalled(t, "UpdateChannel")
+		mockSocketService.AssertNotCalled(t, "EmitNewMessage")
+	})
+
+	t.Run("Too many attachments", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel("")
+
+		mockChannelService := new(mocks.ChannelService)
+		mockMessageService := new(mocks.MessageService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		for i := 0; i <= model.MaximumAttachments; i++ {
+			part, err := writer.CreateFormFile("file", "image.png")
+			assert.NoError(t, err)
+			_, err = part.Write([]byte(fixture.RandStringRunes(8)))
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, writer.Close())
+
+		request, err := http.NewRequest(http.MethodPost, "/api/messages/"+mockChannel.ID, body)
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", writer.FormDataContentType())
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"errors": []model.FieldError{
+				{
+					Field:   "Files",
+					Message: apperrors.AttachmentLimitError + ".",
+				},
+			},
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+
+		mockChannelService.AssertNotCalled(t, "Get", mock.Anything)
+		mockMessageService.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything)
+		mockMessageService.AssertNotCalled(t, "CreateMessage", mock.Anything)
+	})
+
+	t.Run("Image Message Creation Success", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockChannel := fixture.GetMockChannel(mockGuild.ID)
+		mockMessage := fixture.GetMockMessage(authUser.ID, mockChannel.ID)
+		mockMessage.Text = nil
+
+		uploadImageFixture := fixture.NewMultipartImage("image.png", "image/png")
+		defer uploadImageFixture.Close()
+		formFile := uploadImageFixture.GetFormFile()
+
+		attachment := &model.Attachment{
+			ID:        fixture.RandID(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Url:       fixture.RandStringRunes(8),
+			FileType:  "image/png",
+			Filename:  fixture.RandStringRunes(8),
+			MessageId: mockMessage.ID,
+		}
+		mockMessage.Attachments = []model.Attachment{*attachment}
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(true)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionAttachFiles).Return(true)
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
+
+		params := model.Message{
+			UserId:      mockMessage.UserId,
+			ChannelId:   mockMessage.ChannelId,
+			Attachments: []model.Attachment{*attachment},
+		}
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("UploadFile", formFile, mockChannel.ID).Return(attachment, nil)
+		mockMessageService.On("CreateMessage", &params).Return(mockMessage, nil)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetMemberSettings", authUser.ID, mockGuild.ID).Return(&model.MemberSettings{}, nil)
+
+		mockSocketService := new(mocks.SocketService)
+		response := model.MessageResponse{
+			Id:          mockMessage.ID,
+			Text:        nil,
+			CreatedAt:   mockMessage.CreatedAt,
+			UpdatedAt:   mockMessage.UpdatedAt,
+			Attachments: []model.Attachment{*attachment},
+			User: model.MemberResponse{
+				Id:        authUser.ID,
+				Username:  authUser.Username,
+				Image:     authUser.Image,
+				IsOnline:  authUser.IsOnline,
+				CreatedAt: authUser.CreatedAt,
+				UpdatedAt: authUser.UpdatedAt,
+				IsFriend:  false,
+			},
+			Reactions: make([]model.ReactionResponse, 0),
+			Mentions:  model.NewMessageMentions(),
+		}
+
+		mockSocketService.On("EmitNewMessage", mockChannel.ID, &response).Return()
+		mockChannelService.On("UpdateChannel", mockChannel).Return(nil)
+		mockSocketService.On("EmitNewNotification", mockGuild.ID, mockChannel.ID)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+			GuildService:   mockGuildService,
+			SocketService:  mockSocketService,
+			UserService:    mockUserService,
+		})
+
+		multipartImageFixture := fixture.NewMultipartImage("image.png", "image/png")
+		defer multipartImageFixture.Close()
+
+		request, err := http.NewRequest(http.MethodPost, "/api/messages/"+mockChannel.ID, multipartImageFixture.MultipartBody)
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", multipartImageFixture.ContentType)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+
+		mockChannelService.AssertExpectations(t)
+		mockMessageService.AssertExpectations(t)
+		mockGuildService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("DM channel message success", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel("")
+		mockChannel.IsDM = true
+		mockMessage := fixture.GetMockMessage(authUser.ID, mockChannel.ID)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(true)
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
+
+		params := model.Message{
+			UserId:    mockMessage.UserId,
+			ChannelId: mockMessage.ChannelId,
+			Text:      mockMessage.Text,
+		}
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("CreateMessage", &params).Return(mockMessage, nil)
+
+		mockSocketService := new(mocks.SocketService)
+		response := model.MessageResponse{
+			Id:          mockMessage.ID,
+			Text:        mockMessage.Text,
+			CreatedAt:   mockMessage.CreatedAt,
+			UpdatedAt:   mockMessage.UpdatedAt,
+			Attachments: make([]model.Attachment, 0),
+			User: model.MemberResponse{
+				Id:        authUser.ID,
+				Username:  authUser.Username,
+				Image:     authUser.Image,
+				IsOnline:  authUser.IsOnline,
+				CreatedAt: authUser.CreatedAt,
+				UpdatedAt: authUser.UpdatedAt,
+				IsFriend:  false,
+			},
+			Reactions: make([]model.ReactionResponse, 0),
+			Mentions:  model.NewMessageMentions(),
+		}
+
+		mockSocketService.On("EmitNewMessage", mockChannel.ID, &response).Return()
+		mockSocketService.On("EmitNewDMNotification", mockChannel.ID, authUser).Return()
+		mockChannelService.On("OpenDMForAll", mockChannel.ID).Return(nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+			SocketService:  mockSocketService,
+			UserService:    mockUserService,
+		})
+
+		form := url.Values{}
+		form.Add("text", *mockMessage.Text)
+
+		request, err := http.NewRequest(http.MethodPost, "/api/messages/"+mockChannel.ID, strings.NewReader(form.Encode()))
+		assert.NoError(t, err)
+		request.Form = form
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+
+		mockChannelService.AssertExpectations(t)
+		mockMessageService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("Reply with mention", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockChannel := fixture.GetMockChannel(mockGuild.ID)
+		mockMessage := fixture.GetMockMessage(authUser.ID, mockChannel.ID)
+		replyAuthor := fixture.GetMockUser()
+		mockReference := fixture.GetMockMessage(replyAuthor.ID, mockChannel.ID)
+		referenceText := fixture.RandStringRunes(model.ReferenceTextLength * 2)
+		mockReference.Text = &referenceText
+		mockMessage.ReplyToId = &mockReference.ID
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("IsChannelMember", mockChannel, replyAuthor.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(true)
+		mockChannelService.On("UpdateChannel", mockChannel).Return(nil)
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
+		mockUserService.On("Get", replyAuthor.ID).Return(replyAuthor, nil)
+
+		params := model.Message{
+			UserId:    mockMessage.UserId,
+			ChannelId: mockMessage.ChannelId,
+			Text:      mockMessage.Text,
+			ReplyToId: &mockReference.ID,
+		}
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockReference.ID).Return(mockReference, nil)
+		mockMessageService.On("CreateMessage", &params).Return(mockMessage, nil)
+
+		nickname := fixture.RandStringRunes(8)
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetMemberSettings", authUser.ID, mockGuild.ID).Return(&model.MemberSettings{}, nil)
+		mockGuildService.On("GetMemberSettings", replyAuthor.ID, mockGuild.ID).Return(&model.MemberSettings{Nickname: &nickname}, nil)
+
+		response := model.MessageResponse{
+			Id:          mockMessage.ID,
+			Text:        mockMessage.Text,
+			CreatedAt:   mockMessage.CreatedAt,
+			UpdatedAt:   mockMessage.UpdatedAt,
+			Attachments: make([]model.Attachment, 0),
+			User: model.MemberResponse{
+				Id:        authUser.ID,
+				Username:  authUser.Username,
+				Image:     authUser.Image,
+				IsOnline:  authUser.IsOnline,
+				CreatedAt: authUser.CreatedAt,
+				UpdatedAt: authUser.UpdatedAt,
+				IsFriend:  false,
+			},
+			Reactions: make([]model.ReactionResponse, 0),
+			Mentions:  model.NewMessageMentions(),
+			ReplyTo: model.NewMessageReference(mockReference.ID, mockReference.Text, false, &model.ReferenceAuthor{
+				Id:       replyAuthor.ID,
+				Username: replyAuthor.Username,
+				Image:    replyAuthor.Image,
+				Nickname: &nickname,
+			}),
+		}
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitNewMessage", mockChannel.ID, &response).Return()
+		mockSocketService.On("EmitNewNotification", mockGuild.ID, mockChannel.ID)
+		mockSocketService.On("EmitNewMention", replyAuthor.ID, &model.MentionNotification{
+			GuildId:   &mockGuild.ID,
+			ChannelId: mockChannel.ID,
+			Message:   &response,
+		})
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+			GuildService:   mockGuildService,
+			SocketService:  mockSocketService,
+			UserService:    mockUserService,
+		})
+
+		form := url.Values{}
+		form.Add("text", *mockMessage.Text)
+		form.Add("replyToId", mockReference.ID)
+		form.Add("mentionReply", "true")
+
+		request, err := http.NewRequest(http.MethodPost, "/api/messages/"+mockChannel.ID, strings.NewReader(form.Encode()))
+		assert.NoError(t, err)
+		request.Form = form
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		assert.Len(t, []rune(*response.ReplyTo.Text), model.ReferenceTextLength+1)
+
+		mockChannelService.AssertExpectations(t)
+		mockMessageService.AssertExpectations(t)
+		mockGuildService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("Mentions notify members that can see the channel", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockChannel := fixture.GetMockChannel(mockGuild.ID)
+		mentioned := fixture.GetMockUser()
+		hidden := fixture.GetMockUser()
+		mockRole := fixture.GetMockRole(mockGuild.ID)
+
+		text := "<@" + mentioned.ID + "> <@&" + mockRole.ID + ">"
+		mockMessage := fixture.GetMockMessage(authUser.ID, mockChannel.ID)
+		mockMessage.Text = &text
+		mockMessage.Mentions = []model.Mention{
+			{MessageId: mockMessage.ID, Type: model.MentionTypeUser, TargetId: mentioned.ID},
+			{MessageId: mockMessage.ID, Type: model.MentionTypeRole, TargetId: mockRole.ID},
+		}
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("IsChannelMember", mockChannel, mentioned.ID).Return(nil)
+		mockChannelService.On("IsChannelMember", mockChannel, hidden.ID).Return(apperrors.NewAuthorization(apperrors.Unauthorized))
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(true)
+		mockChannelService.On("UpdateChannel", mockChannel).Return(nil)
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
+
+		mentions := model.NewMessageMentions()
+		mentions.Users = append(mentions.Users, model.MentionedUser{Id: mentioned.ID, Username: mentioned.Username})
+		mentions.Roles = append(mentions.Roles, model.MentionedRole{Id: mockRole.ID, Name: mockRole.Name, Color: mockRole.Color})
+
+		params := model.Message{
+			UserId:    authUser.ID,
+			ChannelId: mockChannel.ID,
+			Text:      &text,
+		}
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("CreateMessage", &params).Return(mockMessage, nil)
+		mockMessageService.On("GetMessageMentions", mockChannel, mockMessage.ID).Return(&mentions, nil)
+		// The author has the mentioned role as well
+		mockMessageService.On("GetMentionedUsers", mockChannel, mockMessage).
+			Return([]string{mentioned.ID, authUser.ID, hidden.ID}, nil)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetMemberSettings", authUser.ID, mockGuild.ID).Return(&model.MemberSettings{}, nil)
+
+		response := model.MessageResponse{
+			Id:          mockMessage.ID,
+			Text:        mockMessage.Text,
+			CreatedAt:   mockMessage.CreatedAt,
+			UpdatedAt:   mockMessage.UpdatedAt,
+			Attachments: make([]model.Attachment, 0),
+			User: model.MemberResponse{
+				Id:        authUser.ID,
+				Username:  authUser.Username,
+				Image:     authUser.Image,
+				IsOnline:  authUser.IsOnline,
+				CreatedAt: authUser.CreatedAt,
+				UpdatedAt: authUser.UpdatedAt,
+				IsFriend:  false,
+			},
+			Reactions: make([]model.ReactionResponse, 0),
+			Mentions:  mentions,
+		}
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitNewMessage", mockChannel.ID, &response).Return()
+		mockSocketService.On("EmitNewNotification", mockGuild.ID, mockChannel.ID)
+		mockSocketService.On("EmitNewMention", mentioned.ID, &model.MentionNotification{
+			GuildId:   &mockGuild.ID,
+			ChannelId: mockChannel.ID,
+			Message:   &response,
+		})
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+			GuildService:   mockGuildService,
+			SocketService:  mockSocketService,
+			UserService:    mockUserService,
+		})
+
+		form := url.Values{}
+		form.Add("text", text)
+
+		request, err := http.NewRequest(http.MethodPost, "/api/messages/"+mockChannel.ID, strings.NewReader(form.Encode()))
+		assert.NoError(t, err)
+		request.Form = form
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+
+		mockChannelService.AssertExpectations(t)
+		mockMessageService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+		mockSocketService.AssertNumberOfCalls(t, "EmitNewMention", 1)
+	})
+
+	t.Run("Reply to a message in another channel", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockChannel := fixture.GetMockChannel(mockGuild.ID)
+		mockReference := fixture.GetMockMessage("", fixture.RandID())
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(true)
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockReference.ID).Return(mockReference, nil)
+
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+			SocketService:  mockSocketService,
+			UserService:    mockUserService,
+		})
+
+		form := url.Values{}
+		form.Add("text", fixture.RandStringRunes(10))
+		form.Add("replyToId", mockReference.ID)
+
+		request, err := http.NewRequest(http.MethodPost, "/api/messages/"+mockChannel.ID, strings.NewReader(form.Encode()))
+		assert.NoError(t, err)
+		request.Form = form
+
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewNotFound("message", mockReference.ID)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+
+		mockMessageService.AssertNotCalled(t, "CreateMessage", mock.Anything)
+		mockSocketService.AssertNotCalled(t, "EmitNewMessage", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Message with a direct upload", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockChannel := fixture.GetMockChannel(mockGuild.ID)
+		mockMessage := fixture.GetMockMessage(authUser.ID, mockChannel.ID)
+		mockMessage.Text = nil
+
+		upload := &model.Upload{
+			ID:        fixture.RandID(),
+			UserId:    authUser.ID,
+			ChannelId: mockChannel.ID,
+			Filename:  "abcde-audio.mp3",
+			FileType:  "audio/mp3",
+			Size:      1024,
+			Url:       "https://imageurl.com/abcde-audio.mp3",
+		}
+		attachment := &model.Attachment{
+			ID:       upload.ID,
+			Url:      upload.Url,
+			FileType: upload.FileType,
+			Filename: upload.Filename,
+			Size:     upload.Size,
+		}
+		mockMessage.Attachments = []model.Attachment{*attachment}
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(true)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionAttachFiles).Return(true)
+		mockChannelService.On("UpdateChannel", mockChannel).Return(nil)
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
+
+		params := model.Message{
+			UserId:      authUser.ID,
+			ChannelId:   mockChannel.ID,
+			Attachments: []model.Attachment{*attachment},
+		}
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("GetUpload", upload.ID).Return(upload, nil)
+		mockMessageService.On("FinalizeUpload", upload).Return(attachment, nil)
+		mockMessageService.On("CreateMessage", &params).Return(mockMessage, nil)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetMemberSettings", authUser.ID, mockGuild.ID).Return(&model.MemberSettings{}, nil)
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitNewMessage", mockChannel.ID, mock.MatchedBy(func(response *model.MessageResponse) bool {
+			return len(response.Attachments) == 1 && response.Attachments[0].Url == upload.Url
+		})).Return()
+		mockSocketService.On("EmitNewNotification", mockGuild.ID, mockChannel.ID)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+			GuildService:   mockGuildService,
+			SocketService:  mockSocketService,
+			UserService:    mockUserService,
+		})
+
+		form := url.Values{}
+		form.Add("uploadId", upload.ID)
+		form.Add("uploadId", upload.ID)
+
+		request, err := http.NewRequest(http.MethodPost, "/api/messages/"+mockChannel.ID, strings.NewReader(form.Encode()))
+		assert.NoError(t, err)
+		request.Form = form
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+
+		// Duplicate upload IDs only get attached once
+		mockMessageService.AssertNumberOfCalls(t, "FinalizeUpload", 1)
+		mockChannelService.AssertExpectations(t)
+		mockMessageService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
 	})
 
-	t.Run("DM channel message success", func(t *testing.T) {
-		mockChannel := fixture.GetMockChannel("")
-		mockChannel.IsDM = true
-		mockMessage := fixture.GetMockMessage(authUser.ID, mockChannel.ID)
+	t.Run("Upload of another user", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockChannel := fixture.GetMockChannel(mockGuild.ID)
+
+		upload := &model.Upload{
+			ID:        fixture.RandID(),
+			UserId:    fixture.RandID(),
+			ChannelId: mockChannel.ID,
+		}
 
 		mockChannelService := new(mocks.ChannelService)
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
 		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(true)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionAttachFiles).Return(true)
 
 		mockUserService := new(mocks.UserService)
 		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
 
-		params := model.Message{
-			UserId:    mockMessage.UserId,
-			ChannelId: mockMessage.ChannelId,
-			Text:      mockMessage.Text,
-		}
 		mockMessageService := new(mocks.MessageService)
-		mockMessageService.On("CreateMessage", &params).Return(mockMessage, nil)
+		mockMessageService.On("GetUpload", upload.ID).Return(upload, nil)
 
 		mockSocketService := new(mocks.SocketService)
-		response := model.MessageResponse{
-			Id:         mockMessage.ID,
-			Text:       mockMessage.Text,
-			CreatedAt:  mockMessage.CreatedAt,
-			UpdatedAt:  mockMessage.UpdatedAt,
-			Attachment: mockMessage.Attachment,
-			User: model.MemberResponse{
-				Id:        authUser.ID,
-				Username:  authUser.Username,
-				Image:     authUser.Image,
-				IsOnline:  authUser.IsOnline,
-				CreatedAt: authUser.CreatedAt,
-				UpdatedAt: authUser.UpdatedAt,
-				IsFriend:  false,
-			},
-		}
-
-		mockSocketService.On("EmitNewMessage", mockChannel.ID, &response).Return()
-		mockSocketService.On("EmitNewDMNotification", mockChannel.ID, authUser).Return()
-		mockChannelService.On("OpenDMForAll", mockChannel.ID).Return(nil)
 
 		rr := httptest.NewRecorder()
 
@@ -721,7 +1529,7 @@ func TestHandler_CreateMessage(t *testing.T) {
 		})
 
 		form := url.Values{}
-		form.Add("text", *mockMessage.Text)
+		form.Add("uploadId", upload.ID)
 
 		request, err := http.NewRequest(http.MethodPost, "/api/messages/"+mockChannel.ID, strings.NewReader(form.Encode()))
 		assert.NoError(t, err)
@@ -729,16 +1537,17 @@ func TestHandler_CreateMessage(t *testing.T) {
 
 		router.ServeHTTP(rr, request)
 
-		respBody, err := json.Marshal(true)
+		mockError := apperrors.NewNotFound("upload", upload.ID)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
 		assert.NoError(t, err)
 
-		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, mockError.Status(), rr.Code)
 		assert.Equal(t, respBody, rr.Body.Bytes())
 
-		mockChannelService.AssertExpectations(t)
-		mockMessageService.AssertExpectations(t)
-		mockSocketService.AssertExpectations(t)
-		mockUserService.AssertExpectations(t)
+		mockMessageService.AssertNotCalled(t, "FinalizeUpload", mock.Anything)
+		mockMessageService.AssertNotCalled(t, "CreateMessage", mock.Anything)
 	})
 }
 
@@ -810,15 +1619,20 @@ func TestHandler_UpdateMessage(t *testing.T) {
 		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
 		mockMessageService.On("UpdateMessage", mockMessage).Return(nil)
 
+		reactions := []model.ReactionResponse{{Emoji: "👍", Count: 2}}
+		mockMessageService.On("GetMessageReactions", mockMessage.ID).Return(reactions, nil)
+
 		response := model.MessageResponse{
-			Id:         mockMessage.ID,
-			Text:       mockMessage.Text,
-			CreatedAt:  mockMessage.CreatedAt,
-			UpdatedAt:  mockMessage.UpdatedAt,
-			Attachment: mockMessage.Attachment,
+			Id:          mockMessage.ID,
+			Text:        mockMessage.Text,
+			CreatedAt:   mockMessage.CreatedAt,
+			UpdatedAt:   mockMessage.UpdatedAt,
+			Attachments: make([]model.Attachment, 0),
 			User: model.MemberResponse{
 				Id: authUser.ID,
 			},
+			Reactions: reactions,
+			Mentions:  model.NewMessageMentions(),
 		}
 
 		mockSocketService := new(mocks.SocketService)
@@ -856,6 +1670,125 @@ func TestHandler_UpdateMessage(t *testing.T) {
 		mockSocketService.AssertExpectations(t)
 	})
 
+	t.Run("Edited text is marked as edited", func(t *testing.T) {
+		mockMessage := fixture.GetMockMessage(authUser.ID, "")
+		text := fixture.RandStringRunes(20)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+		mockMessageService.
+			On("UpdateMessage", mockMessage).
+			Run(func(args mock.Arguments) {
+				mockMessage.EditCount++
+			}).
+			Return(nil)
+		mockMessageService.On("GetMessageReactions", mockMessage.ID).Return(make([]model.ReactionResponse, 0), nil)
+
+		response := model.MessageResponse{
+			Id:          mockMessage.ID,
+			Text:        &text,
+			CreatedAt:   mockMessage.CreatedAt,
+			UpdatedAt:   mockMessage.UpdatedAt,
+			Attachments: make([]model.Attachment, 0),
+			User: model.MemberResponse{
+				Id: authUser.ID,
+			},
+			Reactions: make([]model.ReactionResponse, 0),
+			Mentions:  model.NewMessageMentions(),
+			Edited:    true,
+			EditCount: 1,
+		}
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitEditMessage", mockMessage.ChannelId, &response).Return()
+
+		reqBody, err := json.Marshal(gin.H{
+			"text": text,
+		})
+		assert.NoError(t, err)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			MessageService: mockMessageService,
+			SocketService:  mockSocketService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		request, err := http.NewRequest(http.MethodPut, "/api/messages/"+mockMessage.ID, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockMessageService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
+	t.Run("Reply keeps its reference", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		repliedTo := fixture.GetMockMessage(fixture.RandID(), mockChannel.ID)
+		mockMessage := fixture.GetMockMessage(authUser.ID, mockChannel.ID)
+		mockMessage.ReplyToId = &repliedTo.ID
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+		mockMessageService.On("Get", repliedTo.ID).Return(nil, apperrors.NewNotFound("message", repliedTo.ID))
+		mockMessageService.On("UpdateMessage", mockMessage).Return(nil)
+		mockMessageService.On("GetMessageReactions", mockMessage.ID).Return(make([]model.ReactionResponse, 0), nil)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+
+		response := model.MessageResponse{
+			Id:          mockMessage.ID,
+			Text:        mockMessage.Text,
+			CreatedAt:   mockMessage.CreatedAt,
+			UpdatedAt:   mockMessage.UpdatedAt,
+			Attachments: make([]model.Attachment, 0),
+			User: model.MemberResponse{
+				Id: authUser.ID,
+			},
+			Reactions: make([]model.ReactionResponse, 0),
+			ReplyTo:   model.DeletedMessageReference(repliedTo.ID),
+			Mentions:  model.NewMessageMentions(),
+		}
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitEditMessage", mockMessage.ChannelId, &response).Return()
+
+		reqBody, err := json.Marshal(gin.H{
+			"text": *mockMessage.Text,
+		})
+		assert.NoError(t, err)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			MessageService: mockMessageService,
+			ChannelService: mockChannelService,
+			SocketService:  mockSocketService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		request, err := http.NewRequest(http.MethodPut, "/api/messages/"+mockMessage.ID, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockMessageService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
 	t.Run("Message not found", func(t *testing.T) {
 		id := fixture.RandID()
 		mockError := apperrors.NewNotFound("message", id)
@@ -1107,7 +2040,6 @@ func TestHandler_Delete_Message(t *testing.T) {
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
 
 		mockGuildService := new(mocks.GuildService)
-		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
 
 		mockSocketService := new(mocks.SocketService)
 		mockSocketService.On("EmitDeleteMessage", mockChannel.ID, mockMessage.ID)
@@ -1242,8 +2174,8 @@ func TestHandler_Delete_Message(t *testing.T) {
 		mockSocketService.AssertNotCalled(t, "EmitDeleteMessage")
 	})
 
-	t.Run("Delete in guild - guild owner", func(t *testing.T) {
-		mockGuild := fixture.GetMockGuild(authUser.ID)
+	t.Run("Delete in guild - manage messages permission", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
 		mockChannel := fixture.GetMockChannel(mockGuild.ID)
 		mockMessage := fixture.GetMockMessage("", mockChannel.ID)
 
@@ -1253,9 +2185,9 @@ func TestHandler_Delete_Message(t *testing.T) {
 
 		mockChannelService := new(mocks.ChannelService)
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionManageMessages).Return(true)
 
 		mockGuildService := new(mocks.GuildService)
-		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
 
 		mockSocketService := new(mocks.SocketService)
 		mockSocketService.On("EmitDeleteMessage", mockChannel.ID, mockMessage.ID)
@@ -1293,7 +2225,7 @@ func TestHandler_Delete_Message(t *testing.T) {
 		mockSocketService.AssertExpectations(t)
 	})
 
-	t.Run("Delete in guild - not the guild owner", func(t *testing.T) {
+	t.Run("Delete in guild - missing permission", func(t *testing.T) {
 		mockGuild := fixture.GetMockGuild("")
 		mockChannel := fixture.GetMockChannel(mockGuild.ID)
 		mockMessage := fixture.GetMockMessage("", mockChannel.ID)
@@ -1303,9 +2235,9 @@ func TestHandler_Delete_Message(t *testing.T) {
 
 		mockChannelService := new(mocks.ChannelService)
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionManageMessages).Return(false)
 
 		mockGuildService := new(mocks.GuildService)
-		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
 
 		mockSocketService := new(mocks.SocketService)
 
@@ -1341,7 +2273,7 @@ func TestHandler_Delete_Message(t *testing.T) {
 
 		mockMessageService.AssertCalled(t, "Get", mockMessage.ID)
 		mockChannelService.AssertCalled(t, "Get", mockChannel.ID)
-		mockGuildService.AssertCalled(t, "GetGuild", mockGuild.ID)
+		mockChannelService.AssertCalled(t, "HasPermission", authUser.ID, mockChannel, model.PermissionManageMessages)
 		mockMessageService.AssertNotCalled(t, "DeleteMessage")
 		mockSocketService.AssertNotCalled(t, "EmitDeleteMessage")
 	})
@@ -1493,7 +2425,6 @@ func TestHandler_Delete_Message(t *testing.T) {
 		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
 
 		mockGuildService := new(mocks.GuildService)
-		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
 
 		mockSocketService := new(mocks.SocketService)
 
@@ -1532,3 +2463,169 @@ func TestHandler_Delete_Message(t *testing.T) {
 		mockSocketService.AssertNotCalled(t, "EmitDeleteMessage")
 	})
 }
+
+func TestHandler_GetMessageHistory(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successful fetch", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockMessage := fixture.GetMockMessage("", mockChannel.ID)
+		mockMessage.EditCount = 2
+
+		original := fixture.RandStringRunes(20)
+		edited := fixture.RandStringRunes(20)
+		revisions := []model.MessageRevision{
+			{
+				MessageId: mockMessage.ID,
+				Revision:  1,
+				Text:      &original,
+				CreatedAt: mockMessage.CreatedAt,
+				EditedAt:  mockMessage.CreatedAt.Add(time.Minute),
+			},
+			{
+				MessageId: mockMessage.ID,
+				Revision:  2,
+				Text:      &edited,
+				CreatedAt: mockMessage.CreatedAt.Add(time.Minute),
+				EditedAt:  mockMessage.UpdatedAt,
+			},
+		}
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+		mockMessageService.On("GetMessageRevisions", mockMessage.ID).Return(&revisions, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		reqUrl := "/api/messages/" + mockMessage.ID + "/history"
+		request, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(revisions)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+		mockMessageService.AssertExpectations(t)
+	})
+
+	t.Run("Message was never edited", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockMessage := fixture.GetMockMessage("", mockChannel.ID)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+		mockMessageService.On("GetMessageRevisions", mockMessage.ID).Return(&[]model.MessageRevision{}, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		reqUrl := "/api/messages/" + mockMessage.ID + "/history"
+		request, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, []byte("[]"), rr.Body.Bytes())
+		mockMessageService.AssertExpectations(t)
+	})
+
+	t.Run("Not a channel member", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockMessage := fixture.GetMockMessage("", mockChannel.ID)
+		mockError := apperrors.NewAuthorization(apperrors.Unauthorized)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(mockError)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		reqUrl := "/api/messages/" + mockMessage.ID + "/history"
+		request, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockMessageService.AssertNotCalled(t, "GetMessageRevisions", mock.Anything)
+	})
+
+	t.Run("Message not found", func(t *testing.T) {
+		messageId := fixture.RandID()
+		mockError := apperrors.NewNotFound("message", messageId)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", messageId).Return(nil, mockError)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			MessageService: mockMessageService,
+		})
+
+		reqUrl := "/api/messages/" + messageId + "/history"
+		request, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockMessageService.AssertNotCalled(t, "GetMessageRevisions", mock.Anything)
+	})
+}
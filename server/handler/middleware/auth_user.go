@@ -3,14 +3,47 @@ package middleware
 import (
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/model"
 	"github.com/sentrionic/valkyrie/model/apperrors"
 	"log"
+	"net/http"
+	"strings"
 )
 
+// TokenScopes are the scopes an access token needs for the routes of a group.
+// Read is required for GET and HEAD requests and Write for all other methods.
+type TokenScopes struct {
+	Read  string
+	Write string
+}
+
+// scope returns the scope the request needs
+func (s *TokenScopes) scope(method string) string {
+	if method == http.MethodGet || method == http.MethodHead {
+		return s.Read
+	}
+	return s.Write
+}
+
 // AuthUser checks if the request contains a valid session
-// and saves the session's userId in the context
-func AuthUser() gin.HandlerFunc {
+// and saves the session's userId in the context.
+// If a SessionService is given, the session also has to be one of the user's
+// tracked sessions and its id gets saved as sessionId in the context.
+// Requests with an "Authorization: Bearer <token>" header get authenticated with
+// the access token instead, which needs the given scopes. Without scopes or an
+// AccessTokenService access tokens are rejected.
+func AuthUser(sessionService model.SessionService, tokenService model.AccessTokenService, scopes *TokenScopes) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if token, ok := bearerToken(c); ok {
+			if ok = authAccessToken(c, tokenService, scopes, token); !ok {
+				c.Abort()
+				return
+			}
+
+			c.Next()
+			return
+		}
+
 		session := sessions.Default(c)
 		id := session.Get("userId")
 
@@ -27,6 +60,13 @@ func AuthUser() gin.HandlerFunc {
 
 		c.Set("userId", userId)
 
+		if sessionService != nil {
+			if ok := trackSession(c, session, sessionService, userId); !ok {
+				c.Abort()
+				return
+			}
+		}
+
 		// Recreate session to extend its lifetime
 		session.Set("userId", id)
 		if err := session.Save(); err != nil {
@@ -36,3 +76,96 @@ func AuthUser() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// trackSession updates the last use of the tracked session and responds with an error
+// and returns false if the session got revoked
+func trackSession(c *gin.Context, session sessions.Session, sessionService model.SessionService, userId string) bool {
+	ctx := c.Request.Context()
+	userAgent := c.Request.UserAgent()
+	ip := c.ClientIP()
+
+	sessionId, _ := session.Get("sessionId").(string)
+
+	// Sessions created before they were tracked get tracked from now on
+	if sessionId == "" {
+		tracked, err := sessionService.CreateSession(ctx, userId, userAgent, ip)
+
+		if err != nil {
+			log.Printf("Failed to track the session: %v\n", err.Error())
+			return true
+		}
+
+		session.Set("sessionId", tracked.Id)
+		c.Set("sessionId", tracked.Id)
+		return true
+	}
+
+	if err := sessionService.TouchSession(ctx, userId, sessionId, userAgent, ip); err != nil {
+		if apperrors.Status(err) != http.StatusNotFound {
+			c.JSON(apperrors.Status(err), gin.H{
+				"error": err,
+			})
+			return false
+		}
+
+		// The session got revoked on another device
+		session.Clear()
+		session.Options(sessions.Options{Path: "/", MaxAge: -1})
+		if err = session.Save(); err != nil {
+			log.Printf("Failed to clear the session: %v\n", err.Error())
+		}
+
+		e := apperrors.NewAuthorization(apperrors.InvalidSession)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return false
+	}
+
+	c.Set("sessionId", sessionId)
+	return true
+}
+
+// bearerToken returns the token of the Authorization header if it uses the Bearer scheme
+func bearerToken(c *gin.Context) (string, bool) {
+	scheme, token, found := strings.Cut(c.GetHeader("Authorization"), " ")
+
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	return strings.TrimSpace(token), true
+}
+
+// authAccessToken saves the userId and accessTokenId of the access token in the context
+// or responds with an error and returns false if the token is invalid or misses the scope
+func authAccessToken(c *gin.Context, tokenService model.AccessTokenService, scopes *TokenScopes, token string) bool {
+	if tokenService == nil || scopes == nil {
+		e := apperrors.NewAuthorization(apperrors.AccessTokenNotAllowed)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return false
+	}
+
+	accessToken, err := tokenService.Authenticate(token)
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return false
+	}
+
+	if !accessToken.HasScope(scopes.scope(c.Request.Method)) {
+		e := apperrors.NewAuthorization(apperrors.MissingTokenScope)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return false
+	}
+
+	c.Set("userId", accessToken.UserId)
+	c.Set("accessTokenId", accessToken.ID)
+	return true
+}
@@ -3,8 +3,11 @@ package middleware
 import (
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-contrib/sessions/cookie"
+	"github.com/sentrionic/valkyrie/mocks"
 	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
 	"github.com/sentrionic/valkyrie/service"
+	"github.com/stretchr/testify/mock"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -32,7 +35,7 @@ func TestAuthUser(t *testing.T) {
 
 		var contextUserId string
 
-		r.GET("/api/accounts", AuthUser(), func(c *gin.Context) {
+		r.GET("/api/accounts", AuthUser(nil, nil, nil), func(c *gin.Context) {
 			contextKeyVal, _ := c.Get("userId")
 			contextUserId = contextKeyVal.(string)
 		})
@@ -52,7 +55,7 @@ func TestAuthUser(t *testing.T) {
 		store := cookie.NewStore([]byte("secret"))
 		r.Use(sessions.Sessions(model.CookieName, store))
 
-		r.GET("/api/accounts", AuthUser())
+		r.GET("/api/accounts", AuthUser(nil, nil, nil))
 
 		request, _ := http.NewRequest(http.MethodGet, "/api/accounts", http.NoBody)
 
@@ -60,4 +63,203 @@ func TestAuthUser(t *testing.T) {
 
 		assert.Equal(t, http.StatusUnauthorized, rr.Code)
 	})
+
+	t.Run("Touches the tracked session", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		_, r := gin.CreateTestContext(rr)
+		store := cookie.NewStore([]byte("secret"))
+		r.Use(sessions.Sessions(model.CookieName, store))
+
+		r.Use(func(c *gin.Context) {
+			session := sessions.Default(c)
+			session.Set("userId", uid)
+			session.Set("sessionId", "session")
+		})
+
+		mockSessionService := new(mocks.SessionService)
+		mockSessionService.On("TouchSession", mock.Anything, uid, "session", "Firefox", mock.Anything).Return(nil)
+
+		var contextSessionId string
+
+		r.GET("/api/accounts", AuthUser(mockSessionService, nil, nil), func(c *gin.Context) {
+			contextSessionId = c.GetString("sessionId")
+		})
+
+		request, _ := http.NewRequest(http.MethodGet, "/api/accounts", http.NoBody)
+		request.Header.Set("User-Agent", "Firefox")
+		r.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "session", contextSessionId)
+		mockSessionService.AssertExpectations(t)
+	})
+
+	t.Run("Revoked session", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		_, r := gin.CreateTestContext(rr)
+		store := cookie.NewStore([]byte("secret"))
+		r.Use(sessions.Sessions(model.CookieName, store))
+
+		r.Use(func(c *gin.Context) {
+			session := sessions.Default(c)
+			session.Set("userId", uid)
+			session.Set("sessionId", "session")
+		})
+
+		mockSessionService := new(mocks.SessionService)
+		mockSessionService.
+			On("TouchSession", mock.Anything, uid, "session", mock.Anything, mock.Anything).
+			Return(apperrors.NewNotFound("session", "session"))
+
+		called := false
+
+		r.GET("/api/accounts", AuthUser(mockSessionService, nil, nil), func(c *gin.Context) {
+			called = true
+		})
+
+		request, _ := http.NewRequest(http.MethodGet, "/api/accounts", http.NoBody)
+		r.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.False(t, called)
+		assert.Contains(t, rr.Header().Get("Set-Cookie"), "Max-Age=0")
+	})
+
+	t.Run("Tracks sessions created before tracking", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		_, r := gin.CreateTestContext(rr)
+		store := cookie.NewStore([]byte("secret"))
+		r.Use(sessions.Sessions(model.CookieName, store))
+
+		r.Use(func(c *gin.Context) {
+			session := sessions.Default(c)
+			session.Set("userId", uid)
+		})
+
+		mockSessionService := new(mocks.SessionService)
+		mockSessionService.
+			On("CreateSession", mock.Anything, uid, mock.Anything, mock.Anything).
+			Return(&model.Session{Id: "session"}, nil)
+
+		var contextSessionId string
+
+		r.GET("/api/accounts", AuthUser(mockSessionService, nil, nil), func(c *gin.Context) {
+			contextSessionId = c.GetString("sessionId")
+		})
+
+		request, _ := http.NewRequest(http.MethodGet, "/api/accounts", http.NoBody)
+		r.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "session", contextSessionId)
+		mockSessionService.AssertNotCalled(t, "TouchSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthUser_AccessToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	uid := service.GenerateId()
+	scopes := &TokenScopes{Read: model.ScopeReadMessages, Write: model.ScopeSendMessages}
+	accessToken := &model.AccessToken{
+		BaseModel: model.BaseModel{ID: "token"},
+		UserId:    uid,
+		Scopes:    []string{model.ScopeReadMessages},
+	}
+
+	getTokenRouter := func(tokenService model.AccessTokenService, scopes *TokenScopes, handler gin.HandlerFunc) *gin.Engine {
+		_, r := gin.CreateTestContext(httptest.NewRecorder())
+		store := cookie.NewStore([]byte("secret"))
+		r.Use(sessions.Sessions(model.CookieName, store))
+
+		r.GET("/api/messages", AuthUser(nil, tokenService, scopes), handler)
+		r.POST("/api/messages", AuthUser(nil, tokenService, scopes), handler)
+		return r
+	}
+
+	t.Run("Adds the token's userId to context", func(t *testing.T) {
+		mockTokenService := new(mocks.AccessTokenService)
+		mockTokenService.On("Authenticate", "vlk_token").Return(accessToken, nil)
+
+		var contextUserId, contextTokenId string
+
+		r := getTokenRouter(mockTokenService, scopes, func(c *gin.Context) {
+			contextUserId = c.GetString("userId")
+			contextTokenId = c.GetString("accessTokenId")
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodGet, "/api/messages", http.NoBody)
+		request.Header.Set("Authorization", "Bearer vlk_token")
+		r.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, uid, contextUserId)
+		assert.Equal(t, "token", contextTokenId)
+		mockTokenService.AssertExpectations(t)
+	})
+
+	t.Run("Missing scope", func(t *testing.T) {
+		mockTokenService := new(mocks.AccessTokenService)
+		mockTokenService.On("Authenticate", "vlk_token").Return(accessToken, nil)
+
+		called := false
+
+		r := getTokenRouter(mockTokenService, scopes, func(c *gin.Context) {
+			called = true
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, "/api/messages", http.NoBody)
+		request.Header.Set("Authorization", "Bearer vlk_token")
+		r.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Contains(t, rr.Body.String(), apperrors.MissingTokenScope)
+		assert.False(t, called)
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		mockTokenService := new(mocks.AccessTokenService)
+		mockTokenService.
+			On("Authenticate", "vlk_invalid").
+			Return(nil, apperrors.NewAuthorization(apperrors.InvalidAccessToken))
+
+		called := false
+
+		r := getTokenRouter(mockTokenService, scopes, func(c *gin.Context) {
+			called = true
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodGet, "/api/messages", http.NoBody)
+		request.Header.Set("Authorization", "Bearer vlk_invalid")
+		r.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.False(t, called)
+	})
+
+	t.Run("Group without scopes", func(t *testing.T) {
+		mockTokenService := new(mocks.AccessTokenService)
+
+		called := false
+
+		r := getTokenRouter(mockTokenService, nil, func(c *gin.Context) {
+			called = true
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodGet, "/api/messages", http.NoBody)
+		request.Header.Set("Authorization", "Bearer vlk_token")
+		r.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Contains(t, rr.Body.String(), apperrors.AccessTokenNotAllowed)
+		assert.False(t, called)
+		mockTokenService.AssertNotCalled(t, "Authenticate", mock.Anything)
+	})
 }
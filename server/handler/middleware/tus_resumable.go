@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"net/http"
+)
+
+// TusResumable sets the Tus-Resumable header on every response and rejects
+// requests that use another version of the tus protocol.
+// OPTIONS requests discover the protocol and do not need to specify the version.
+func TusResumable() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Tus-Resumable", model.TusVersion)
+
+		if c.Request.Method != http.MethodOptions && c.GetHeader("Tus-Resumable") != model.TusVersion {
+			c.Header("Tus-Version", model.TusVersion)
+			e := apperrors.NewPreconditionFailed(apperrors.TusVersionError)
+			c.JSON(e.Status(), gin.H{
+				"error": e,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
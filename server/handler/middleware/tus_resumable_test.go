@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"github.com/sentrionic/valkyrie/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTusResumable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Supported version", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		_, r := gin.CreateTestContext(rr)
+
+		called := false
+		r.HEAD("/api/uploads/:id", TusResumable(), func(c *gin.Context) {
+			called = true
+			c.Status(http.StatusOK)
+		})
+
+		request, _ := http.NewRequest(http.MethodHead, "/api/uploads/1", http.NoBody)
+		request.Header.Set("Tus-Resumable", model.TusVersion)
+		r.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, model.TusVersion, rr.Header().Get("Tus-Resumable"))
+		assert.True(t, called)
+	})
+
+	t.Run("Unsupported version", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		_, r := gin.CreateTestContext(rr)
+
+		called := false
+		r.POST("/api/uploads", TusResumable(), func(c *gin.Context) {
+			called = true
+		})
+
+		request, _ := http.NewRequest(http.MethodPost, "/api/uploads", http.NoBody)
+		request.Header.Set("Tus-Resumable", "0.2.2")
+		r.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+		assert.Equal(t, model.TusVersion, rr.Header().Get("Tus-Version"))
+		assert.False(t, called)
+	})
+
+	t.Run("OPTIONS without version", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		_, r := gin.CreateTestContext(rr)
+
+		r.OPTIONS("/api/uploads", TusResumable(), func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+
+		request, _ := http.NewRequest(http.MethodOptions, "/api/uploads", http.NoBody)
+		r.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		assert.Equal(t, model.TusVersion, rr.Header().Get("Tus-Resumable"))
+	})
+}
@@ -1,29 +1,106 @@
 package handler
 
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"log"
+	"mime/multipart"
+)
+
 var validImageTypes = map[string]bool{
 	"image/jpeg": true,
 	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
 }
 
 // IsAllowedImageType determines if image is among types defined
 // in map of allowed images
 func isAllowedImageType(mimeType string) bool {
-	_, exists := validImageTypes[mimeType]
+	_, exists := validImageTypes[model.NormalizeFileType(mimeType)]
 
 	return exists
 }
 
-var validFileTypes = map[string]bool{
-	"image/jpeg": true,
-	"image/png":  true,
-	"audio/mp3":  true,
-	"audio/wave": true,
+// checkFile validates the declared Content-Type and the size of the file against the
+// allowlist and detects the real type of the file from its content to reject mismatches.
+// Avatars and icons (imageOnly) must additionally be one of the valid image types.
+// On success the Content-Type of the file gets normalized. Otherwise, it writes the error response and returns false.
+func (h *Handler) checkFile(c *gin.Context, field string, header *multipart.FileHeader, imageOnly bool) bool {
+	fileType := model.NormalizeFileType(header.Header.Get("Content-Type"))
+	maxSize, allowed := h.fileTypes.MaxSize(fileType)
+
+	if imageOnly && (!allowed || !isAllowedImageType(fileType)) {
+		toFieldErrorResponse(c, field, apperrors.InvalidImageType)
+		return false
+	}
+
+	if !allowed {
+		toFieldErrorResponse(c, field, apperrors.InvalidFileType)
+		return false
+	}
+
+	if header.Size > maxSize {
+		e := apperrors.NewPayloadTooLarge(maxSize, header.Size)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return false
+	}
+
+	file, err := header.Open()
+
+	if err != nil {
+		log.Printf("Failed to open header: %v\n", err.Error())
+		e := apperrors.NewInternal()
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return false
+	}
+
+	defer file.Close()
+
+	matches, err := model.MatchesFileType(file, fileType)
+
+	if err != nil {
+		log.Printf("Failed to detect the file type: %v\n", err.Error())
+		e := apperrors.NewInternal()
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return false
+	}
+
+	if !matches {
+		toFieldErrorResponse(c, field, apperrors.FileTypeMismatchError)
+		return false
+	}
+
+	header.Header.Set("Content-Type", fileType)
+	return true
 }
 
-// isAllowedFileType determines if the file is among types defined
-// in map of allowed file types
-func isAllowedFileType(mimeType string) bool {
-	_, exists := validFileTypes[mimeType]
+// checkUploadType validates the declared type and size of a direct upload against the allowlist.
+// The content of the file gets checked once the upload is finalized.
+// It returns the normalized type or writes the error response and returns false.
+func (h *Handler) checkUploadType(c *gin.Context, field, fileType string, size int64) (string, bool) {
+	fileType = model.NormalizeFileType(fileType)
+	maxSize, allowed := h.fileTypes.MaxSize(fileType)
 
-	return exists
+	if !allowed {
+		toFieldErrorResponse(c, field, apperrors.InvalidFileType)
+		return "", false
+	}
+
+	if size > maxSize {
+		e := apperrors.NewPayloadTooLarge(maxSize, size)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return "", false
+	}
+
+	return fileType, true
 }
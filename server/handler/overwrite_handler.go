@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"log"
+	"net/http"
+)
+
+/*
+ * OverwriteHandler contains all routes related to channel permission overwrites (/api/channels)
+ */
+
+// GetPermissionOverwrites returns the permission overwrites of the given channel
+// GetPermissionOverwrites godoc
+// @Tags Channels
+// @Summary Get Channel Permission Overwrites
+// @Produce  json
+// @Param channelId path string true "Channel ID"
+// @Success 200 {array} model.PermissionOverwrite
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /channels/{channelId}/permissions [get]
+func (h *Handler) GetPermissionOverwrites(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+
+	channel, ok := h.getManageableChannel(c, userId, apperrors.OverwriteDMError)
+
+	if !ok {
+		return
+	}
+
+	overwrites, err := h.channelService.GetPermissionOverwrites(channel.ID)
+
+	if err != nil {
+		log.Printf("Unable to find overwrites for channel: %v\n%v", channel.ID, err)
+		e := apperrors.NewNotFound("channel", channel.ID)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// If the channel does not have any overwrites, return an empty array
+	if len(*overwrites) == 0 {
+		empty := make([]model.PermissionOverwrite, 0)
+		c.JSON(http.StatusOK, empty)
+		return
+	}
+
+	c.JSON(http.StatusOK, overwrites)
+}
+
+// overwriteReq specifies the permissions that get allowed or denied
+// for the target of the overwrite
+type overwriteReq struct {
+	// role or member. Use role with the guild ID to target every member
+	Type model.OverwriteType `json:"type"`
+	// Bitset of the allowed channel permissions
+	Allow model.Permission `json:"allow"`
+	// Bitset of the denied channel permissions
+	Deny model.Permission `json:"deny"`
+} //@name PermissionOverwriteRequest
+
+func (r overwriteReq) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Type, validation.Required, validation.In(model.OverwriteTypeRole, model.OverwriteTypeMember)),
+		validation.Field(&r.Allow, validation.By(isChannelPermission)),
+		validation.Field(&r.Deny, validation.By(isChannelPermission)),
+	)
+}
+
+// isChannelPermission checks that the value only contains ChannelPermissions
+func isChannelPermission(value interface{}) error {
+	permissions, _ := value.(model.Permission)
+	if permissions < 0 || permissions&^model.ChannelPermissions != 0 {
+		return errors.New(apperrors.InvalidOverwritePermission)
+	}
+	return nil
+}
+
+// SetPermissionOverwrite creates or replaces the overwrite for the given role or member
+// SetPermissionOverwrite godoc
+// @Tags Channels
+// @Summary Set Channel Permission Overwrite
+// @Accepts json
+// @Produce  json
+// @Param channelId path string true "Channel ID"
+// @Param targetId path string true "Role, Member or Guild ID"
+// @Param request body overwriteReq true "Permission Overwrite"
+// @Success 200 {object} model.Success
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /channels/{channelId}/permissions/{targetId} [put]
+func (h *Handler) SetPermissionOverwrite(c *gin.Context) {
+	var req overwriteReq
+
+	// Bind incoming json to struct and check for validation errors
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	userId := c.MustGet("userId").(string)
+	targetId := c.Param("targetId")
+
+	channel, ok := h.getManageableChannel(c, userId, apperrors.OverwriteDMError)
+
+	if !ok {
+		return
+	}
+
+	guildId := *channel.GuildID
+
+	// Members cannot grant or deny permissions they do not have themselves
+	if !h.guildService.HasPermission(userId, guildId, req.Allow|req.Deny) {
+		e := apperrors.NewAuthorization(apperrors.GrantPermissionsError)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	if req.Type == model.OverwriteTypeMember {
+		guild, err := h.guildService.GetGuild(guildId)
+
+		if err != nil || !isMember(guild, targetId) {
+			e := apperrors.NewNotFound("member", targetId)
+
+			c.JSON(e.Status(), gin.H{
+				"error": e,
+			})
+			return
+		}
+		// The guild ID targets every member and does not belong to a role
+	} else if targetId != guildId {
+		role, err := h.guildService.GetRole(targetId)
+
+		if err != nil || role.GuildId != guildId {
+			e := apperrors.NewNotFound("role", targetId)
+
+			c.JSON(e.Status(), gin.H{
+				"error": e,
+			})
+			return
+		}
+	}
+
+	overwrite := model.PermissionOverwrite{
+		ChannelID: channel.ID,
+		TargetID:  targetId,
+		Type:      req.Type,
+		Allow:     req.Allow,
+		Deny:      req.Deny,
+	}
+
+	if err := h.channelService.SetPermissionOverwrite(&overwrite); err != nil {
+		log.Printf("Failed to set overwrite: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	// Emit the channel changes so the members refetch their channels
+	response := channel.SerializeChannel()
+	h.socketService.EmitEditChannel(guildId, &response)
+
+	c.JSON(http.StatusOK, true)
+}
+
+// DeletePermissionOverwrite removes the overwrite for the given role or member
+// DeletePermissionOverwrite godoc
+// @Tags Channels
+// @Summary Delete Channel Permission Overwrite
+// @Produce  json
+// @Param channelId path string true "Channel ID"
+// @Param targetId path string true "Role, Member or Guild ID"
+// @Success 200 {object} model.Success
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /channels/{channelId}/permissions/{targetId} [delete]
+func (h *Handler) DeletePermissionOverwrite(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+	targetId := c.Param("targetId")
+
+	channel, ok := h.getManageableChannel(c, userId, apperrors.OverwriteDMError)
+
+	if !ok {
+		return
+	}
+
+	if err := h.channelService.DeletePermissionOverwrite(channel.ID, targetId); err != nil {
+		log.Printf("Failed to delete overwrite: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	// Emit the channel changes so the members refetch their channels
+	response := channel.SerializeChannel()
+	h.socketService.EmitEditChannel(*channel.GuildID, &response)
+
+	c.JSON(http.StatusOK, true)
+}
+
+// getManageableChannel returns the guild channel of the id param if the user
+// is allowed to manage channels in its guild.
+// Otherwise, it writes the error response and returns false.
+// DM channels get rejected with the given reason.
+func (h *Handler) getManageableChannel(c *gin.Context, userId string, dmError string) (*model.Channel, bool) {
+	channelId := c.Param("id")
+
+	channel, err := h.channelService.Get(channelId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("channel", channelId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, false
+	}
+
+	if channel.IsDM {
+		e := apperrors.NewBadRequest(dmError)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, false
+	}
+
+	// Threads inherit the overwrites of their parent
+	if channel.IsThread() {
+		e := apperrors.NewBadRequest(apperrors.ThreadChannelError)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, false
+	}
+
+	if !h.guildService.HasPermission(userId, *channel.GuildID, model.PermissionManageChannels) {
+		e := apperrors.NewAuthorization(apperrors.MissingPermissions)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, false
+	}
+
+	return channel, true
+}
@@ -0,0 +1,507 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_GetPermissionOverwrites(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successful Fetch", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockChannel := fixture.GetMockChannel(mockGuild.ID)
+
+		overwrites := []model.PermissionOverwrite{
+			{
+				ChannelID: mockChannel.ID,
+				TargetID:  mockGuild.ID,
+				Type:      model.OverwriteTypeRole,
+				Deny:      model.PermissionSendMessages,
+			},
+		}
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("GetPermissionOverwrites", mockChannel.ID).Return(&overwrites, nil)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(true)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			GuildService:   mockGuildService,
+			ChannelService: mockChannelService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/permissions", mockChannel.ID)
+		request, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(overwrites)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+		mockGuildService.AssertExpectations(t)
+	})
+
+	t.Run("DM channel", func(t *testing.T) {
+		mockChannel := fixture.GetMockDMChannel()
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+
+		mockGuildService := new(mocks.GuildService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			GuildService:   mockGuildService,
+			ChannelService: mockChannelService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/permissions", mockChannel.ID)
+		request, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewBadRequest(apperrors.OverwriteDMError)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertNotCalled(t, "HasPermission", mock.Anything, mock.Anything, mock.Anything)
+		mockChannelService.AssertNotCalled(t, "GetPermissionOverwrites", mock.Anything)
+	})
+
+	t.Run("Missing permissions", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockChannel := fixture.GetMockChannel(mockGuild.ID)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(false)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			GuildService:   mockGuildService,
+			ChannelService: mockChannelService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/permissions", mockChannel.ID)
+		request, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewAuthorization(apperrors.MissingPermissions)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertNotCalled(t, "GetPermissionOverwrites", mock.Anything)
+	})
+}
+
+func TestHandler_SetPermissionOverwrite(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Read only channel for everyone", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockChannel := fixture.GetMockChannel(mockGuild.ID)
+
+		overwrite := &model.PermissionOverwrite{
+			ChannelID: mockChannel.ID,
+			TargetID:  mockGuild.ID,
+			Type:      model.OverwriteTypeRole,
+			Deny:      model.PermissionSendMessages,
+		}
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("SetPermissionOverwrite", overwrite).Return(nil)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(true)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionSendMessages).Return(true)
+
+		response := mockChannel.SerializeChannel()
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitEditChannel", mockGuild.ID, &response)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			GuildService:   mockGuildService,
+			ChannelService: mockChannelService,
+			SocketService:  mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"type": model.OverwriteTypeRole,
+			"deny": model.PermissionSendMessages,
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/permissions/%s", mockChannel.ID, mockGuild.ID)
+		request, err := http.NewRequest(http.MethodPut, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+		mockGuildService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
+	t.Run("Role overwrite", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockChannel := fixture.GetMockChannel(mockGuild.ID)
+		mockRole := fixture.GetMockRole(mockGuild.ID)
+
+		overwrite := &model.PermissionOverwrite{
+			ChannelID: mockChannel.ID,
+			TargetID:  mockRole.ID,
+			Type:      model.OverwriteTypeRole,
+			Allow:     model.PermissionSendMessages,
+		}
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("SetPermissionOverwrite", overwrite).Return(nil)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(true)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionSendMessages).Return(true)
+		mockGuildService.On("GetRole", mockRole.ID).Return(mockRole, nil)
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitEditChannel", mockGuild.ID, mock.AnythingOfType("*model.ChannelResponse"))
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			GuildService:   mockGuildService,
+			ChannelService: mockChannelService,
+			SocketService:  mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"type":  model.OverwriteTypeRole,
+			"allow": model.PermissionSendMessages,
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/permissions/%s", mockChannel.ID, mockRole.ID)
+		request, err := http.NewRequest(http.MethodPut, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+		mockGuildService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
+	t.Run("Member is not part of the guild", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockChannel := fixture.GetMockChannel(mockGuild.ID)
+		memberId := fixture.RandID()
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, mock.AnythingOfType("model.Permission")).Return(true)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			GuildService:   mockGuildService,
+			ChannelService: mockChannelService,
+			SocketService:  mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"type":  model.OverwriteTypeMember,
+			"allow": model.PermissionViewChannel,
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/permissions/%s", mockChannel.ID, memberId)
+		request, err := http.NewRequest(http.MethodPut, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewNotFound("member", memberId)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertNotCalled(t, "SetPermissionOverwrite", mock.Anything)
+		mockSocketService.AssertNotCalled(t, "EmitEditChannel", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Cannot overwrite permissions the user does not have", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockChannel := fixture.GetMockChannel(mockGuild.ID)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(true)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageMessages).Return(false)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			GuildService:   mockGuildService,
+			ChannelService: mockChannelService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"type":  model.OverwriteTypeRole,
+			"allow": model.PermissionManageMessages,
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/permissions/%s", mockChannel.ID, mockGuild.ID)
+		request, err := http.NewRequest(http.MethodPut, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewAuthorization(apperrors.GrantPermissionsError)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertNotCalled(t, "SetPermissionOverwrite", mock.Anything)
+	})
+}
+
+func TestHandler_SetPermissionOverwrite_BadRequest(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	mockUser := fixture.GetMockUser()
+	router := getAuthenticatedTestRouter(mockUser.ID)
+
+	mockChannelService := new(mocks.ChannelService)
+
+	NewHandler(&Config{
+		R:              router,
+		ChannelService: mockChannelService,
+	})
+
+	testCases := []struct {
+		name string
+		body gin.H
+	}{
+		{
+			name: "Type required",
+			body: gin.H{
+				"allow": model.PermissionViewChannel,
+			},
+		},
+		{
+			name: "Invalid type",
+			body: gin.H{
+				"type": fixture.RandStringRunes(6),
+			},
+		},
+		{
+			name: "Allow contains guild permissions",
+			body: gin.H{
+				"type":  model.OverwriteTypeRole,
+				"allow": model.PermissionBanMembers,
+			},
+		},
+		{
+			name: "Deny contains guild permissions",
+			body: gin.H{
+				"type": model.OverwriteTypeRole,
+				"deny": model.PermissionAdministrator,
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			reqBody, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			reqUrl := fmt.Sprintf("/api/channels/%s/permissions/%s", fixture.RandID(), fixture.RandID())
+			request, err := http.NewRequest(http.MethodPut, reqUrl, bytes.NewBuffer(reqBody))
+			assert.NoError(t, err)
+
+			request.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			mockChannelService.AssertNotCalled(t, "SetPermissionOverwrite")
+		})
+	}
+}
+
+func TestHandler_DeletePermissionOverwrite(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successfully deleted", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockChannel := fixture.GetMockChannel(mockGuild.ID)
+		targetId := fixture.RandID()
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("DeletePermissionOverwrite", mockChannel.ID, targetId).Return(nil)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageChannels).Return(true)
+
+		response := mockChannel.SerializeChannel()
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitEditChannel", mockGuild.ID, &response)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			GuildService:   mockGuildService,
+			ChannelService: mockChannelService,
+			SocketService:  mockSocketService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/permissions/%s", mockChannel.ID, targetId)
+		request, err := http.NewRequest(http.MethodDelete, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+		mockGuildService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
+	t.Run("Channel not found", func(t *testing.T) {
+		id := fixture.RandID()
+		mockError := apperrors.NewNotFound("channel", id)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", id).Return(nil, mockError)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/permissions/%s", id, fixture.RandID())
+		request, err := http.NewRequest(http.MethodDelete, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertNotCalled(t, "DeletePermissionOverwrite", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"log"
+	"net/http"
+)
+
+/*
+ * PinHandler contains all routes related to pinned messages (/api/channels)
+ */
+
+// GetPinnedMessages returns the pinned messages of the given channel
+// GetPinnedMessages godoc
+// @Tags Channels
+// @Summary Get Pinned Messages
+// @Produce  json
+// @Param channelId path string true "Channel ID"
+// @Success 200 {array} model.MessageResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /channels/{channelId}/pins [get]
+func (h *Handler) GetPinnedMessages(c *gin.Context) {
+	channelId := c.Param("id")
+	userId := c.MustGet("userId").(string)
+
+	channel, err := h.channelService.Get(channelId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("channel", channelId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// Check if the user has access to said channel
+	if err = h.channelService.IsChannelMember(channel, userId); err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	messages, err := h.messageService.GetPinnedMessages(userId, channel)
+
+	if err != nil {
+		e := apperrors.NewNotFound("messages", channelId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// If the channel does not have any pins, return an empty array
+	if len(*messages) == 0 {
+		var empty = make([]model.MessageResponse, 0)
+		c.JSON(http.StatusOK, empty)
+		return
+	}
+
+	c.JSON(http.StatusOK, messages)
+}
+
+// PinMessage pins the given message to the channel
+// PinMessage godoc
+// @Tags Channels
+// @Summary Pin Message
+// @Produce  json
+// @Param channelId path string true "Channel ID"
+// @Param messageId path string true "Message ID"
+// @Success 200 {object} model.Success
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /channels/{channelId}/pins/{messageId} [put]
+func (h *Handler) PinMessage(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+
+	message, ok := h.getPinnableMessage(c, userId)
+
+	if !ok {
+		return
+	}
+
+	if message.PinnedAt != nil {
+		e := apperrors.NewBadRequest(apperrors.AlreadyPinnedError)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	if err := h.messageService.PinMessage(message); err != nil {
+		log.Printf("Failed to pin message: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	// Emit the pin to the channel
+	h.socketService.EmitPinMessage(message.ChannelId, &model.PinEvent{
+		MessageId: message.ID,
+		ChannelId: message.ChannelId,
+		UserId:    userId,
+	})
+
+	c.JSON(http.StatusOK, true)
+}
+
+// UnpinMessage removes the given message from the channel's pins
+// UnpinMessage godoc
+// @Tags Channels
+// @Summary Unpin Message
+// @Produce  json
+// @Param channelId path string true "Channel ID"
+// @Param messageId path string true "Message ID"
+// @Success 200 {object} model.Success
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /channels/{channelId}/pins/{messageId} [delete]
+func (h *Handler) UnpinMessage(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+
+	message, ok := h.getPinnableMessage(c, userId)
+
+	if !ok {
+		return
+	}
+
+	if message.PinnedAt == nil {
+		e := apperrors.NewBadRequest(apperrors.NotPinnedError)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	if err := h.messageService.UnpinMessage(message); err != nil {
+		log.Printf("Failed to unpin message: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	// Emit the removed pin to the channel
+	h.socketService.EmitUnpinMessage(message.ChannelId, &model.PinEvent{
+		MessageId: message.ID,
+		ChannelId: message.ChannelId,
+		UserId:    userId,
+	})
+
+	c.JSON(http.StatusOK, true)
+}
+
+// getPinnableMessage returns the message of the id and messageId params
+// if the user is allowed to manage the channel's pins.
+// Otherwise, it writes the error response and returns false.
+func (h *Handler) getPinnableMessage(c *gin.Context, userId string) (*model.Message, bool) {
+	channelId := c.Param("id")
+	messageId := c.Param("messageId")
+
+	channel, err := h.channelService.Get(channelId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("channel", channelId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, false
+	}
+
+	// Check if the user has access to said channel
+	if err = h.channelService.IsChannelMember(channel, userId); err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return nil, false
+	}
+
+	// Both members of a DM may pin messages
+	if !h.channelService.HasPermission(userId, channel, model.PermissionManageMessages) {
+		e := apperrors.NewAuthorization(apperrors.PinMessagesError)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, false
+	}
+
+	message, err := h.messageService.Get(messageId)
+
+	if err != nil || message.ChannelId != channel.ID {
+		e := apperrors.NewNotFound("message", messageId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, false
+	}
+
+	return message, true
+}
@@ -0,0 +1,410 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler_GetPinnedMessages(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successful fetch", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+
+		response := make([]model.MessageResponse, 0)
+		for i := 0; i < 3; i++ {
+			message := fixture.GetMockMessageResponse("", mockChannel.ID)
+			message.Pinned = true
+			response = append(response, *message)
+		}
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("GetPinnedMessages", authUser.ID, mockChannel).Return(&response, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/channels/%s/pins", mockChannel.ID), nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+		mockMessageService.AssertExpectations(t)
+	})
+
+	t.Run("Not a channel member", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockError := apperrors.NewAuthorization(apperrors.Unauthorized)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(mockError)
+
+		mockMessageService := new(mocks.MessageService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/channels/%s/pins", mockChannel.ID), nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockMessageService.AssertNotCalled(t, "GetPinnedMessages", mock.Anything, mock.Anything)
+	})
+}
+
+func TestHandler_PinMessage(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successfully pinned", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockMessage := fixture.GetMockMessage("", mockChannel.ID)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionManageMessages).Return(true)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+		mockMessageService.On("PinMessage", mockMessage).Return(nil)
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitPinMessage", mockChannel.ID, &model.PinEvent{
+			MessageId: mockMessage.ID,
+			ChannelId: mockChannel.ID,
+			UserId:    authUser.ID,
+		})
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+			SocketService:  mockSocketService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/pins/%s", mockChannel.ID, mockMessage.ID)
+		request, err := http.NewRequest(http.MethodPut, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+		mockMessageService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
+	t.Run("Missing manage messages permission", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockMessage := fixture.GetMockMessage("", mockChannel.ID)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionManageMessages).Return(false)
+
+		mockMessageService := new(mocks.MessageService)
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+			SocketService:  mockSocketService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/pins/%s", mockChannel.ID, mockMessage.ID)
+		request, err := http.NewRequest(http.MethodPut, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewAuthorization(apperrors.PinMessagesError)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockMessageService.AssertNotCalled(t, "PinMessage", mock.Anything)
+		mockSocketService.AssertNotCalled(t, "EmitPinMessage", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Message is in another channel", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockMessage := fixture.GetMockMessage("", fixture.RandID())
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionManageMessages).Return(true)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/pins/%s", mockChannel.ID, mockMessage.ID)
+		request, err := http.NewRequest(http.MethodPut, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewNotFound("message", mockMessage.ID)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockMessageService.AssertNotCalled(t, "PinMessage", mock.Anything)
+	})
+
+	t.Run("Message is already pinned", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockMessage := fixture.GetMockMessage("", mockChannel.ID)
+		pinnedAt := time.Now()
+		mockMessage.PinnedAt = &pinnedAt
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionManageMessages).Return(true)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/pins/%s", mockChannel.ID, mockMessage.ID)
+		request, err := http.NewRequest(http.MethodPut, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewBadRequest(apperrors.AlreadyPinnedError)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockMessageService.AssertNotCalled(t, "PinMessage", mock.Anything)
+	})
+
+	t.Run("Pin limit reached", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockMessage := fixture.GetMockMessage("", mockChannel.ID)
+		mockError := apperrors.NewBadRequest(apperrors.PinLimitError)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionManageMessages).Return(true)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+		mockMessageService.On("PinMessage", mockMessage).Return(mockError)
+
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+			SocketService:  mockSocketService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/pins/%s", mockChannel.ID, mockMessage.ID)
+		request, err := http.NewRequest(http.MethodPut, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockSocketService.AssertNotCalled(t, "EmitPinMessage", mock.Anything, mock.Anything)
+	})
+}
+
+func TestHandler_UnpinMessage(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successfully unpinned", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockMessage := fixture.GetMockMessage("", mockChannel.ID)
+		pinnedAt := time.Now()
+		mockMessage.PinnedAt = &pinnedAt
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionManageMessages).Return(true)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+		mockMessageService.On("UnpinMessage", mockMessage).Return(nil)
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitUnpinMessage", mockChannel.ID, &model.PinEvent{
+			MessageId: mockMessage.ID,
+			ChannelId: mockChannel.ID,
+			UserId:    authUser.ID,
+		})
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+			SocketService:  mockSocketService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/pins/%s", mockChannel.ID, mockMessage.ID)
+		request, err := http.NewRequest(http.MethodDelete, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+		mockMessageService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
+	t.Run("Message is not pinned", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockMessage := fixture.GetMockMessage("", mockChannel.ID)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionManageMessages).Return(true)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/pins/%s", mockChannel.ID, mockMessage.ID)
+		request, err := http.NewRequest(http.MethodDelete, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewBadRequest(apperrors.NotPinnedError)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockMessageService.AssertNotCalled(t, "UnpinMessage", mock.Anything)
+	})
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"log"
+	"net/http"
+)
+
+/*
+ * ReactionHandler contains all routes related to message reactions (/api/messages)
+ */
+
+// GetReactions returns the users that reacted to the given message with the given emoji
+// GetReactions godoc
+// @Tags Messages
+// @Summary Get Message Reactions
+// @Produce  json
+// @Param messageId path string true "Message ID"
+// @Param emoji path string true "Unicode Emoji"
+// @Success 200 {array} model.MemberResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /messages/{messageId}/reactions/{emoji} [get]
+func (h *Handler) GetReactions(c *gin.Context) {
+	// The route shares the channelId parameter with GetMessages
+	messageId := c.Param("channelId")
+	userId := c.MustGet("userId").(string)
+	emoji := c.Param("emoji")
+
+	message, channel, ok := h.getReactableMessage(c, messageId, userId, emoji)
+
+	if !ok {
+		return
+	}
+
+	users, err := h.messageService.GetReactionUsers(channel, message.ID, emoji)
+
+	if err != nil {
+		log.Printf("Unable to find reactions for message: %v\n%v", message.ID, err)
+		e := apperrors.NewNotFound("reactions", message.ID)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// If nobody reacted with the emoji, return an empty array
+	if len(*users) == 0 {
+		var empty = make([]model.MemberResponse, 0)
+		c.JSON(http.StatusOK, empty)
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+// AddReaction adds the current user's reaction with the given emoji to the message
+// AddReaction godoc
+// @Tags Messages
+// @Summary Add Message Reaction
+// @Produce  json
+// @Param messageId path string true "Message ID"
+// @Param emoji path string true "Unicode Emoji"
+// @Success 200 {object} model.Success
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /messages/{messageId}/reactions/{emoji} [put]
+func (h *Handler) AddReaction(c *gin.Context) {
+	messageId := c.Param("messageId")
+	userId := c.MustGet("userId").(string)
+	emoji := c.Param("emoji")
+
+	message, _, ok := h.getReactableMessage(c, messageId, userId, emoji)
+
+	if !ok {
+		return
+	}
+
+	reaction := model.Reaction{
+		MessageId: message.ID,
+		UserId:    userId,
+		Emoji:     emoji,
+	}
+
+	if err := h.messageService.AddReaction(&reaction); err != nil {
+		log.Printf("Failed to add reaction: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	// Emit the reaction to the channel
+	h.socketService.EmitAddReaction(message.ChannelId, &model.ReactionEvent{
+		MessageId: message.ID,
+		UserId:    userId,
+		Emoji:     emoji,
+	})
+
+	c.JSON(http.StatusOK, true)
+}
+
+// RemoveReaction removes the current user's reaction with the given emoji from the message
+// RemoveReaction godoc
+// @Tags Messages
+// @Summary Remove Message Reaction
+// @Produce  json
+// @Param messageId path string true "Message ID"
+// @Param emoji path string true "Unicode Emoji"
+// @Success 200 {object} model.Success
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /messages/{messageId}/reactions/{emoji} [delete]
+func (h *Handler) RemoveReaction(c *gin.Context) {
+	messageId := c.Param("messageId")
+	userId := c.MustGet("userId").(string)
+	emoji := c.Param("emoji")
+
+	message, _, ok := h.getReactableMessage(c, messageId, userId, emoji)
+
+	if !ok {
+		return
+	}
+
+	if err := h.messageService.RemoveReaction(message.ID, userId, emoji); err != nil {
+		log.Printf("Failed to remove reaction: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	// Emit the removed reaction to the channel
+	h.socketService.EmitRemoveReaction(message.ChannelId, &model.ReactionEvent{
+		MessageId: message.ID,
+		UserId:    userId,
+		Emoji:     emoji,
+	})
+
+	c.JSON(http.StatusOK, true)
+}
+
+// getReactableMessage returns the message and its channel if the emoji is valid
+// and the user has access to the channel.
+// Otherwise, it writes the error response and returns false.
+func (h *Handler) getReactableMessage(c *gin.Context, messageId, userId, emoji string) (*model.Message, *model.Channel, bool) {
+	if !isEmoji(emoji) {
+		e := apperrors.NewBadRequest(apperrors.InvalidEmojiError)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, nil, false
+	}
+
+	message, err := h.messageService.Get(messageId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("message", messageId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, nil, false
+	}
+
+	channel, err := h.channelService.Get(message.ChannelId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("message", messageId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, nil, false
+	}
+
+	// Check if the user has access to said channel
+	if err = h.channelService.IsChannelMember(channel, userId); err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return nil, nil, false
+	}
+
+	return message, channel, true
+}
@@ -0,0 +1,369 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestHandler_GetReactions(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+	emoji := "👍"
+
+	t.Run("Successful fetch", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockMessage := fixture.GetMockMessage("", mockChannel.ID)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+
+		users := []model.MemberResponse{
+			{
+				Id:       authUser.ID,
+				Username: authUser.Username,
+				Image:    authUser.Image,
+			},
+		}
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+		mockMessageService.On("GetReactionUsers", mockChannel, mockMessage.ID, emoji).Return(&users, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/messages/%s/reactions/%s", mockMessage.ID, url.PathEscape(emoji))
+		request, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(users)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+		mockMessageService.AssertExpectations(t)
+	})
+
+	t.Run("Not a channel member", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockMessage := fixture.GetMockMessage("", mockChannel.ID)
+		mockError := apperrors.NewAuthorization(apperrors.Unauthorized)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(mockError)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/messages/%s/reactions/%s", mockMessage.ID, url.PathEscape(emoji))
+		request, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockMessageService.AssertNotCalled(t, "GetReactionUsers", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestHandler_AddReaction(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successfully reacted", func(t *testing.T) {
+		emoji := "👍🏽"
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockMessage := fixture.GetMockMessage("", mockChannel.ID)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+
+		reaction := &model.Reaction{
+			MessageId: mockMessage.ID,
+			UserId:    authUser.ID,
+			Emoji:     emoji,
+		}
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+		mockMessageService.On("AddReaction", reaction).Return(nil)
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitAddReaction", mockChannel.ID, &model.ReactionEvent{
+			MessageId: mockMessage.ID,
+			UserId:    authUser.ID,
+			Emoji:     emoji,
+		})
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+			SocketService:  mockSocketService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/messages/%s/reactions/%s", mockMessage.ID, url.PathEscape(emoji))
+		request, err := http.NewRequest(http.MethodPut, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+		mockMessageService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
+	t.Run("Already reacted", func(t *testing.T) {
+		emoji := "🔥"
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockMessage := fixture.GetMockMessage("", mockChannel.ID)
+		mockError := apperrors.NewBadRequest(apperrors.AlreadyReactedError)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+		mockMessageService.On("AddReaction", mock.AnythingOfType("*model.Reaction")).Return(mockError)
+
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+			SocketService:  mockSocketService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/messages/%s/reactions/%s", mockMessage.ID, url.PathEscape(emoji))
+		request, err := http.NewRequest(http.MethodPut, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockSocketService.AssertNotCalled(t, "EmitAddReaction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Message not found", func(t *testing.T) {
+		id := fixture.RandID()
+		mockError := apperrors.NewNotFound("message", id)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", id).Return(nil, mockError)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			MessageService: mockMessageService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/messages/%s/reactions/%s", id, url.PathEscape("🎉"))
+		request, err := http.NewRequest(http.MethodPut, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockMessageService.AssertNotCalled(t, "AddReaction", mock.Anything)
+	})
+
+	invalidEmojis := []string{"abc", "<script>", fixture.RandStringRunes(40)}
+
+	for _, emoji := range invalidEmojis {
+		t.Run("Invalid emoji "+emoji, func(t *testing.T) {
+			mockMessageService := new(mocks.MessageService)
+
+			rr := httptest.NewRecorder()
+
+			router := getAuthenticatedTestRouter(authUser.ID)
+
+			NewHandler(&Config{
+				R:              router,
+				MessageService: mockMessageService,
+			})
+
+			reqUrl := fmt.Sprintf("/api/messages/%s/reactions/%s", fixture.RandID(), url.PathEscape(emoji))
+			request, err := http.NewRequest(http.MethodPut, reqUrl, nil)
+			assert.NoError(t, err)
+
+			router.ServeHTTP(rr, request)
+
+			mockError := apperrors.NewBadRequest(apperrors.InvalidEmojiError)
+			respBody, err := json.Marshal(gin.H{
+				"error": mockError,
+			})
+			assert.NoError(t, err)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.Equal(t, respBody, rr.Body.Bytes())
+			mockMessageService.AssertNotCalled(t, "Get", mock.Anything)
+		})
+	}
+}
+
+func TestHandler_RemoveReaction(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+	emoji := "❤️"
+
+	t.Run("Successfully removed", func(t *testing.T) {
+		mockChannel := fixture.GetMockDMChannel()
+		mockMessage := fixture.GetMockMessage("", mockChannel.ID)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+		mockMessageService.On("RemoveReaction", mockMessage.ID, authUser.ID, emoji).Return(nil)
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitRemoveReaction", mockChannel.ID, &model.ReactionEvent{
+			MessageId: mockMessage.ID,
+			UserId:    authUser.ID,
+			Emoji:     emoji,
+		})
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+			SocketService:  mockSocketService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/messages/%s/reactions/%s", mockMessage.ID, url.PathEscape(emoji))
+		request, err := http.NewRequest(http.MethodDelete, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+		mockMessageService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
+	t.Run("Reaction not found", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockMessage := fixture.GetMockMessage("", mockChannel.ID)
+		mockError := apperrors.NewNotFound("reaction", emoji)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+		mockMessageService.On("RemoveReaction", mockMessage.ID, authUser.ID, emoji).Return(mockError)
+
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+			SocketService:  mockSocketService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/messages/%s/reactions/%s", mockMessage.ID, url.PathEscape(emoji))
+		request, err := http.NewRequest(http.MethodDelete, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockSocketService.AssertNotCalled(t, "EmitRemoveReaction", mock.Anything, mock.Anything)
+	})
+}
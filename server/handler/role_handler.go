@@ -0,0 +1,467 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"log"
+	"net/http"
+	"strings"
+)
+
+/*
+ * RoleHandler contains all routes related to role actions (/api/guilds)
+ */
+
+// GetGuildRoles returns the roles of the given guild
+// GetGuildRoles godoc
+// @Tags Roles
+// @Summary Get Guild Roles
+// @Produce  json
+// @Param guildId path string true "Guild ID"
+// @Success 200 {array} model.RoleResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /guilds/{guildId}/roles [get]
+func (h *Handler) GetGuildRoles(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+	guildId := c.Param("guildId")
+
+	guild, err := h.guildService.GetGuild(guildId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("guild", guildId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// Check if a member
+	if !isMember(guild, userId) {
+		e := apperrors.NewAuthorization(apperrors.NotAMember)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	roles, err := h.guildService.GetRoles(guildId)
+
+	if err != nil {
+		log.Printf("Unable to find roles for guild: %v\n%v", guildId, err)
+		e := apperrors.NewNotFound("guild", guildId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// If the guild does not have any roles, return an empty array
+	if len(*roles) == 0 {
+		empty := make([]model.RoleResponse, 0)
+		c.JSON(http.StatusOK, empty)
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
+}
+
+// roleReq specifies the input form for creating and editing a role
+type roleReq struct {
+	// Role Name. 3 to 30 characters
+	Name string `json:"name"`
+	// Hex color of the role. Set to null to use the default color
+	Color *string `json:"color"`
+	// Roles with a higher position are displayed first. Default is 0
+	Position *int `json:"position"`
+	// Bitset of the role's permissions
+	Permissions model.Permission `json:"permissions"`
+} //@name RoleRequest
+
+func (r roleReq) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Name, validation.Required, validation.Length(3, 30)),
+		validation.Field(&r.Color, validation.NilOrNotEmpty, is.HexColor),
+		validation.Field(&r.Position, validation.Min(0)),
+		validation.Field(&r.Permissions, validation.Min(model.Permission(0)), validation.Max(model.AllPermissions)),
+	)
+}
+
+func (r *roleReq) sanitize() {
+	r.Name = strings.TrimSpace(r.Name)
+}
+
+// CreateRole creates a role for the given guild
+// CreateRole godoc
+// @Tags Roles
+// @Summary Create Role
+// @Accepts json
+// @Produce  json
+// @Param request body roleReq true "Create Role"
+// @Param guildId path string true "Guild ID"
+// @Success 201 {object} model.RoleResponse
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /guilds/{guildId}/roles [post]
+func (h *Handler) CreateRole(c *gin.Context) {
+	var req roleReq
+
+	// Bind incoming json to struct and check for validation errors
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	req.sanitize()
+
+	userId := c.MustGet("userId").(string)
+	guildId := c.Param("guildId")
+
+	guild, err := h.guildService.GetGuild(guildId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("guild", guildId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	if !h.guildService.HasPermission(userId, guild.ID, model.PermissionManageRoles) {
+		e := apperrors.NewAuthorization(apperrors.MissingPermissions)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// Members cannot create roles that are more powerful than themselves
+	if !h.guildService.HasPermission(userId, guild.ID, req.Permissions) {
+		e := apperrors.NewAuthorization(apperrors.GrantPermissionsError)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// Check if the guild already has 250 roles
+	if len(guild.Roles) >= model.MaximumRoles {
+		e := apperrors.NewBadRequest(apperrors.RoleLimitError)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	params := model.Role{
+		GuildId:     guild.ID,
+		Name:        req.Name,
+		Color:       req.Color,
+		Permissions: req.Permissions,
+	}
+
+	if req.Position != nil {
+		params.Position = *req.Position
+	}
+
+	role, err := h.guildService.CreateRole(&params)
+
+	if err != nil {
+		log.Printf("Failed to create role: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	response := role.SerializeRole()
+
+	// Emit the new role to the guild members
+	h.socketService.EmitAddRole(guild.ID, &response)
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// EditRole edits the given role
+// EditRole godoc
+// @Tags Roles
+// @Summary Edit Role
+// @Accepts json
+// @Produce  json
+// @Param request body roleReq true "Edit Role"
+// @Param guildId path string true "Guild ID"
+// @Param roleId path string true "Role ID"
+// @Success 200 {object} model.Success
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /guilds/{guildId}/roles/{roleId} [put]
+func (h *Handler) EditRole(c *gin.Context) {
+	var req roleReq
+
+	// Bind incoming json to struct and check for validation errors
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	req.sanitize()
+
+	userId := c.MustGet("userId").(string)
+
+	role, ok := h.getManageableRole(c, userId)
+
+	if !ok {
+		return
+	}
+
+	// Members cannot grant permissions they do not have themselves
+	if !h.guildService.HasPermission(userId, role.GuildId, req.Permissions) {
+		e := apperrors.NewAuthorization(apperrors.GrantPermissionsError)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	role.Name = req.Name
+	role.Color = req.Color
+	role.Permissions = req.Permissions
+
+	if req.Position != nil {
+		role.Position = *req.Position
+	}
+
+	if err := h.guildService.UpdateRole(role); err != nil {
+		log.Printf("Failed to update role: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	response := role.SerializeRole()
+
+	// Emit the role changes to the guild members
+	h.socketService.EmitEditRole(role.GuildId, &response)
+
+	c.JSON(http.StatusOK, true)
+}
+
+// DeleteRole deletes the given role and removes it from all members
+// DeleteRole godoc
+// @Tags Roles
+// @Summary Delete Role
+// @Produce  json
+// @Param guildId path string true "Guild ID"
+// @Param roleId path string true "Role ID"
+// @Success 200 {object} model.Success
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /guilds/{guildId}/roles/{roleId} [delete]
+func (h *Handler) DeleteRole(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+
+	role, ok := h.getManageableRole(c, userId)
+
+	if !ok {
+		return
+	}
+
+	if err := h.guildService.DeleteRole(role.ID); err != nil {
+		log.Printf("Failed to delete role: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	// Emit the deleted role to the guild members
+	h.socketService.EmitDeleteRole(role.GuildId, role.ID)
+
+	c.JSON(http.StatusOK, true)
+}
+
+// AddMemberRole assigns the given role to the member
+// AddMemberRole godoc
+// @Tags Roles
+// @Summary Add Role to Member
+// @Accepts json
+// @Produce  json
+// @Param request body memberReq true "Member ID"
+// @Param guildId path string true "Guild ID"
+// @Param roleId path string true "Role ID"
+// @Success 200 {object} model.Success
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /guilds/{guildId}/roles/{roleId}/members [post]
+func (h *Handler) AddMemberRole(c *gin.Context) {
+	var req memberReq
+
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	userId := c.MustGet("userId").(string)
+
+	role, ok := h.getManageableRole(c, userId)
+
+	if !ok {
+		return
+	}
+
+	guild, err := h.guildService.GetGuild(role.GuildId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("guild", role.GuildId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// Only members of the guild can be assigned roles
+	if !isMember(guild, req.MemberId) {
+		e := apperrors.NewNotFound("member", req.MemberId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// Members can assign roles below their highest role to themselves,
+	// but only to members ranked below them
+	if req.MemberId != userId && !h.guildService.OutranksMember(userId, req.MemberId, guild.ID) {
+		e := apperrors.NewAuthorization(apperrors.RoleHierarchyError)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	if err := h.guildService.AddMemberRole(req.MemberId, role.GuildId, role.ID); err != nil {
+		log.Printf("Failed to add role to member: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	// Emit the role change to the guild members
+	h.socketService.EmitAddMemberRole(role.GuildId, req.MemberId, role.ID)
+
+	c.JSON(http.StatusOK, true)
+}
+
+// RemoveMemberRole removes the given role from the member
+// RemoveMemberRole godoc
+// @Tags Roles
+// @Summary Remove Role from Member
+// @Accepts json
+// @Produce  json
+// @Param request body memberReq true "Member ID"
+// @Param guildId path string true "Guild ID"
+// @Param roleId path string true "Role ID"
+// @Success 200 {object} model.Success
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /guilds/{guildId}/roles/{roleId}/members [delete]
+func (h *Handler) RemoveMemberRole(c *gin.Context) {
+	var req memberReq
+
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	userId := c.MustGet("userId").(string)
+
+	role, ok := h.getManageableRole(c, userId)
+
+	if !ok {
+		return
+	}
+
+	if err := h.guildService.RemoveMemberRole(req.MemberId, role.GuildId, role.ID); err != nil {
+		log.Printf("Failed to remove role from member: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	// Emit the role change to the guild members
+	h.socketService.EmitRemoveMemberRole(role.GuildId, req.MemberId, role.ID)
+
+	c.JSON(http.StatusOK, true)
+}
+
+// getManageableRole returns the role of the guildId and roleId params if the user
+// is allowed to manage roles, has at least the permissions of said role and outranks it.
+// Otherwise, it writes the error response and returns false.
+func (h *Handler) getManageableRole(c *gin.Context, userId string) (*model.Role, bool) {
+	guildId := c.Param("guildId")
+	roleId := c.Param("roleId")
+
+	role, err := h.guildService.GetRole(roleId)
+
+	// Roles of other guilds should not be found
+	if err != nil || role.GuildId != guildId {
+		e := apperrors.NewNotFound("role", roleId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, false
+	}
+
+	if !h.guildService.HasPermission(userId, guildId, model.PermissionManageRoles) {
+		e := apperrors.NewAuthorization(apperrors.MissingPermissions)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, false
+	}
+
+	// Members cannot manage roles that are more powerful than themselves
+	if !h.guildService.HasPermission(userId, guildId, role.Permissions) {
+		e := apperrors.NewAuthorization(apperrors.GrantPermissionsError)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, false
+	}
+
+	// Members can only manage roles below their highest role
+	if !h.guildService.OutranksRole(userId, role) {
+		e := apperrors.NewAuthorization(apperrors.RoleHierarchyError)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, false
+	}
+
+	return role, true
+}
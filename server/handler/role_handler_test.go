@@ -0,0 +1,1002 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_GetGuildRoles(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successful Fetch", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockGuild.Members = append(mockGuild.Members, *authUser)
+
+		role := fixture.GetMockRole(mockGuild.ID).SerializeRole()
+		response := []model.RoleResponse{role}
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("GetRoles", mockGuild.ID).Return(&response, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:            router,
+			GuildService: mockGuildService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles", mockGuild.ID)
+		request, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertExpectations(t)
+	})
+
+	t.Run("No roles returns an empty array", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockGuild.Members = append(mockGuild.Members, *authUser)
+
+		response := make([]model.RoleResponse, 0)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("GetRoles", mockGuild.ID).Return(&response, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:            router,
+			GuildService: mockGuildService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles", mockGuild.ID)
+		request, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertExpectations(t)
+	})
+
+	t.Run("Not a member", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:            router,
+			GuildService: mockGuildService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles", mockGuild.ID)
+		request, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewAuthorization(apperrors.NotAMember)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertNotCalled(t, "GetRoles", mockGuild.ID)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		mockGuildService := new(mocks.GuildService)
+
+		rr := httptest.NewRecorder()
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:            router,
+			GuildService: mockGuildService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles", fixture.RandID())
+		request, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewAuthorization(apperrors.InvalidSession)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertNotCalled(t, "GetGuild")
+		mockGuildService.AssertNotCalled(t, "GetRoles")
+	})
+}
+
+func TestHandler_CreateRole(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successfully created", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockRole := fixture.GetMockRole(mockGuild.ID)
+		mockRole.Permissions = model.PermissionKickMembers
+
+		params := &model.Role{
+			GuildId:     mockGuild.ID,
+			Name:        mockRole.Name,
+			Permissions: mockRole.Permissions,
+		}
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageRoles).Return(true)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionKickMembers).Return(true)
+		mockGuildService.On("CreateRole", params).Return(mockRole, nil)
+
+		response := mockRole.SerializeRole()
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitAddRole", mockGuild.ID, &response)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:             router,
+			GuildService:  mockGuildService,
+			SocketService: mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"name":        mockRole.Name,
+			"permissions": mockRole.Permissions,
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles", mockGuild.ID)
+		request, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
+	t.Run("Missing permissions", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageRoles).Return(false)
+
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:             router,
+			GuildService:  mockGuildService,
+			SocketService: mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"name": fixture.RandStr(8),
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles", mockGuild.ID)
+		request, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewAuthorization(apperrors.MissingPermissions)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertNotCalled(t, "CreateRole", mock.Anything)
+		mockSocketService.AssertNotCalled(t, "EmitAddRole", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Cannot grant permissions the user does not have", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageRoles).Return(true)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionAdministrator).Return(false)
+
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:             router,
+			GuildService:  mockGuildService,
+			SocketService: mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"name":        fixture.RandStr(8),
+			"permissions": model.PermissionAdministrator,
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles", mockGuild.ID)
+		request, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewAuthorization(apperrors.GrantPermissionsError)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertNotCalled(t, "CreateRole", mock.Anything)
+		mockSocketService.AssertNotCalled(t, "EmitAddRole", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Guild already has the maximum number of roles", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		for i := 0; i < model.MaximumRoles; i++ {
+			mockGuild.Roles = append(mockGuild.Roles, *fixture.GetMockRole(mockGuild.ID))
+		}
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, mock.AnythingOfType("model.Permission")).Return(true)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:            router,
+			GuildService: mockGuildService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"name": fixture.RandStr(8),
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles", mockGuild.ID)
+		request, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewBadRequest(apperrors.RoleLimitError)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertNotCalled(t, "CreateRole", mock.Anything)
+	})
+
+	t.Run("Guild not found", func(t *testing.T) {
+		id := fixture.RandID()
+		mockError := apperrors.NewNotFound("guild", id)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", id).Return(nil, mockError)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:            router,
+			GuildService: mockGuildService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"name": fixture.RandStr(8),
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles", id)
+		request, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertNotCalled(t, "CreateRole", mock.Anything)
+	})
+}
+
+func TestHandler_CreateRole_BadRequest(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	mockUser := fixture.GetMockUser()
+	router := getAuthenticatedTestRouter(mockUser.ID)
+
+	mockGuildService := new(mocks.GuildService)
+
+	NewHandler(&Config{
+		R:            router,
+		GuildService: mockGuildService,
+	})
+
+	testCases := []struct {
+		name string
+		body gin.H
+	}{
+		{
+			name: "Name required",
+			body: gin.H{},
+		},
+		{
+			name: "Name too short",
+			body: gin.H{
+				"name": fixture.RandStringRunes(2),
+			},
+		},
+		{
+			name: "Name too long",
+			body: gin.H{
+				"name": fixture.RandStringRunes(32),
+			},
+		},
+		{
+			name: "Color not a hex color",
+			body: gin.H{
+				"name":  fixture.RandStringRunes(8),
+				"color": fixture.RandStringRunes(6),
+			},
+		},
+		{
+			name: "Negative position",
+			body: gin.H{
+				"name":     fixture.RandStringRunes(8),
+				"position": -1,
+			},
+		},
+		{
+			name: "Unknown permissions",
+			body: gin.H{
+				"name":        fixture.RandStringRunes(8),
+				"permissions": model.AllPermissions + 1,
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			reqBody, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			reqUrl := fmt.Sprintf("/api/guilds/%s/roles", fixture.RandID())
+			request, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(reqBody))
+			assert.NoError(t, err)
+
+			request.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			mockGuildService.AssertNotCalled(t, "CreateRole")
+		})
+	}
+}
+
+func TestHandler_EditRole(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successfully edited", func(t *testing.T) {
+		mockRole := fixture.GetMockRole(fixture.RandID())
+		name := fixture.RandStr(8)
+		color := "#ff0000"
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetRole", mockRole.ID).Return(mockRole, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockRole.GuildId, model.PermissionManageRoles).Return(true)
+		mockGuildService.On("HasPermission", authUser.ID, mockRole.GuildId, model.Permission(0)).Return(true)
+		mockGuildService.On("OutranksRole", authUser.ID, mockRole).Return(true)
+		mockGuildService.On("UpdateRole", mockRole).Return(nil)
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitEditRole", mockRole.GuildId, mock.AnythingOfType("*model.RoleResponse"))
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:             router,
+			GuildService:  mockGuildService,
+			SocketService: mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"name":     name,
+			"color":    color,
+			"position": 2,
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles/%s", mockRole.GuildId, mockRole.ID)
+		request, err := http.NewRequest(http.MethodPut, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		assert.Equal(t, name, mockRole.Name)
+		assert.Equal(t, color, *mockRole.Color)
+		assert.Equal(t, 2, mockRole.Position)
+		mockGuildService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
+	t.Run("Role belongs to another guild", func(t *testing.T) {
+		mockRole := fixture.GetMockRole(fixture.RandID())
+		guildId := fixture.RandID()
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetRole", mockRole.ID).Return(mockRole, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:            router,
+			GuildService: mockGuildService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"name": fixture.RandStr(8),
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles/%s", guildId, mockRole.ID)
+		request, err := http.NewRequest(http.MethodPut, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewNotFound("role", mockRole.ID)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertNotCalled(t, "HasPermission", mock.Anything, mock.Anything, mock.Anything)
+		mockGuildService.AssertNotCalled(t, "UpdateRole", mock.Anything)
+	})
+
+	t.Run("Cannot edit a more powerful role", func(t *testing.T) {
+		mockRole := fixture.GetMockRole(fixture.RandID())
+		mockRole.Permissions = model.PermissionBanMembers
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetRole", mockRole.ID).Return(mockRole, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockRole.GuildId, model.PermissionManageRoles).Return(true)
+		mockGuildService.On("HasPermission", authUser.ID, mockRole.GuildId, model.PermissionBanMembers).Return(false)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:            router,
+			GuildService: mockGuildService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"name": fixture.RandStr(8),
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles/%s", mockRole.GuildId, mockRole.ID)
+		request, err := http.NewRequest(http.MethodPut, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewAuthorization(apperrors.GrantPermissionsError)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertNotCalled(t, "UpdateRole", mock.Anything)
+	})
+
+	t.Run("Cannot edit a role ranked above the user", func(t *testing.T) {
+		mockRole := fixture.GetMockRole(fixture.RandID())
+		mockRole.Position = 5
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetRole", mockRole.ID).Return(mockRole, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockRole.GuildId, mock.AnythingOfType("model.Permission")).Return(true)
+		mockGuildService.On("OutranksRole", authUser.ID, mockRole).Return(false)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:            router,
+			GuildService: mockGuildService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"name": fixture.RandStr(8),
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles/%s", mockRole.GuildId, mockRole.ID)
+		request, err := http.NewRequest(http.MethodPut, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewAuthorization(apperrors.RoleHierarchyError)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertNotCalled(t, "UpdateRole", mock.Anything)
+	})
+}
+
+func TestHandler_DeleteRole(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successfully deleted", func(t *testing.T) {
+		mockRole := fixture.GetMockRole(fixture.RandID())
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetRole", mockRole.ID).Return(mockRole, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockRole.GuildId, model.PermissionManageRoles).Return(true)
+		mockGuildService.On("HasPermission", authUser.ID, mockRole.GuildId, model.Permission(0)).Return(true)
+		mockGuildService.On("OutranksRole", authUser.ID, mockRole).Return(true)
+		mockGuildService.On("DeleteRole", mockRole.ID).Return(nil)
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitDeleteRole", mockRole.GuildId, mockRole.ID)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:             router,
+			GuildService:  mockGuildService,
+			SocketService: mockSocketService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles/%s", mockRole.GuildId, mockRole.ID)
+		request, err := http.NewRequest(http.MethodDelete, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
+	t.Run("Missing permissions", func(t *testing.T) {
+		mockRole := fixture.GetMockRole(fixture.RandID())
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetRole", mockRole.ID).Return(mockRole, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockRole.GuildId, model.PermissionManageRoles).Return(false)
+
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:             router,
+			GuildService:  mockGuildService,
+			SocketService: mockSocketService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles/%s", mockRole.GuildId, mockRole.ID)
+		request, err := http.NewRequest(http.MethodDelete, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewAuthorization(apperrors.MissingPermissions)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertNotCalled(t, "DeleteRole", mock.Anything)
+		mockSocketService.AssertNotCalled(t, "EmitDeleteRole", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Role not found", func(t *testing.T) {
+		guildId := fixture.RandID()
+		roleId := fixture.RandID()
+		mockError := apperrors.NewNotFound("role", roleId)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetRole", roleId).Return(nil, mockError)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:            router,
+			GuildService: mockGuildService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles/%s", guildId, roleId)
+		request, err := http.NewRequest(http.MethodDelete, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertNotCalled(t, "DeleteRole", mock.Anything)
+	})
+}
+
+func TestHandler_AddMemberRole(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successfully added", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild(authUser.ID)
+		mockMember := fixture.GetMockUser()
+		mockGuild.Members = append(mockGuild.Members, *authUser, *mockMember)
+		mockRole := fixture.GetMockRole(mockGuild.ID)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetRole", mockRole.ID).Return(mockRole, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.PermissionManageRoles).Return(true)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, model.Permission(0)).Return(true)
+		mockGuildService.On("OutranksRole", authUser.ID, mockRole).Return(true)
+		mockGuildService.On("OutranksMember", authUser.ID, mockMember.ID, mockGuild.ID).Return(true)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("AddMemberRole", mockMember.ID, mockGuild.ID, mockRole.ID).Return(nil)
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitAddMemberRole", mockGuild.ID, mockMember.ID, mockRole.ID)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:             router,
+			GuildService:  mockGuildService,
+			SocketService: mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"memberId": mockMember.ID,
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles/%s/members", mockGuild.ID, mockRole.ID)
+		request, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
+	t.Run("Not a member of the guild", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild(authUser.ID)
+		mockGuild.Members = append(mockGuild.Members, *authUser)
+		mockRole := fixture.GetMockRole(mockGuild.ID)
+		memberId := fixture.RandID()
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetRole", mockRole.ID).Return(mockRole, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, mock.AnythingOfType("model.Permission")).Return(true)
+		mockGuildService.On("OutranksRole", authUser.ID, mockRole).Return(true)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:             router,
+			GuildService:  mockGuildService,
+			SocketService: mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"memberId": memberId,
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles/%s/members", mockGuild.ID, mockRole.ID)
+		request, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewNotFound("member", memberId)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertNotCalled(t, "AddMemberRole", mock.Anything, mock.Anything, mock.Anything)
+		mockSocketService.AssertNotCalled(t, "EmitAddMemberRole", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Member ranks above the user", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild(fixture.RandID())
+		mockMember := fixture.GetMockUser()
+		mockGuild.Members = append(mockGuild.Members, *authUser, *mockMember)
+		mockRole := fixture.GetMockRole(mockGuild.ID)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetRole", mockRole.ID).Return(mockRole, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockGuild.ID, mock.AnythingOfType("model.Permission")).Return(true)
+		mockGuildService.On("OutranksRole", authUser.ID, mockRole).Return(true)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+		mockGuildService.On("OutranksMember", authUser.ID, mockMember.ID, mockGuild.ID).Return(false)
+
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:             router,
+			GuildService:  mockGuildService,
+			SocketService: mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"memberId": mockMember.ID,
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles/%s/members", mockGuild.ID, mockRole.ID)
+		request, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewAuthorization(apperrors.RoleHierarchyError)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertExpectations(t)
+		mockGuildService.AssertNotCalled(t, "AddMemberRole", mock.Anything, mock.Anything, mock.Anything)
+		mockSocketService.AssertNotCalled(t, "EmitAddMemberRole", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestHandler_RemoveMemberRole(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successfully removed", func(t *testing.T) {
+		mockRole := fixture.GetMockRole(fixture.RandID())
+		memberId := fixture.RandID()
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetRole", mockRole.ID).Return(mockRole, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockRole.GuildId, model.PermissionManageRoles).Return(true)
+		mockGuildService.On("HasPermission", authUser.ID, mockRole.GuildId, model.Permission(0)).Return(true)
+		mockGuildService.On("OutranksRole", authUser.ID, mockRole).Return(true)
+		mockGuildService.On("RemoveMemberRole", memberId, mockRole.GuildId, mockRole.ID).Return(nil)
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitRemoveMemberRole", mockRole.GuildId, memberId, mockRole.ID)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:             router,
+			GuildService:  mockGuildService,
+			SocketService: mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"memberId": memberId,
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles/%s/members", mockRole.GuildId, mockRole.ID)
+		request, err := http.NewRequest(http.MethodDelete, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
+	t.Run("Server Error", func(t *testing.T) {
+		mockRole := fixture.GetMockRole(fixture.RandID())
+		memberId := fixture.RandID()
+		mockError := apperrors.NewInternal()
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetRole", mockRole.ID).Return(mockRole, nil)
+		mockGuildService.On("HasPermission", authUser.ID, mockRole.GuildId, mock.AnythingOfType("model.Permission")).Return(true)
+		mockGuildService.On("OutranksRole", authUser.ID, mockRole).Return(true)
+		mockGuildService.On("RemoveMemberRole", memberId, mockRole.GuildId, mockRole.ID).Return(mockError)
+
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:             router,
+			GuildService:  mockGuildService,
+			SocketService: mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"memberId": memberId,
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/roles/%s/members", mockRole.GuildId, mockRole.ID)
+		request, err := http.NewRequest(http.MethodDelete, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockSocketService.AssertNotCalled(t, "EmitRemoveMemberRole", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+/*
+ * SearchHandler contains all routes related to message search (/api/guilds, /api/channels)
+ */
+
+// searchReq contains the search query and the page offset
+type searchReq struct {
+	// Free text combined with the filters from:userId, in:channelId,
+	// has:attachment, before:YYYY-MM-DD and after:YYYY-MM-DD
+	Query string `form:"q"`
+	// Number of results to skip. Maximum 5000
+	Offset int `form:"offset"`
+} //@name SearchRequest
+
+func (r searchReq) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Query, validation.Required, validation.Length(1, 512)),
+		validation.Field(&r.Offset, validation.Min(0), validation.Max(model.MaximumSearchOffset)),
+	)
+}
+
+func (r *searchReq) sanitize() {
+	r.Query = strings.TrimSpace(r.Query)
+}
+
+// SearchGuildMessages searches the messages of all guild channels the user can see
+// SearchGuildMessages godoc
+// @Tags Guilds
+// @Summary Search Guild Messages
+// @Produce  json
+// @Param guildId path string true "Guild ID"
+// @Param q query string true "Search text and filters"
+// @Param offset query int false "Number of results to skip"
+// @Success 200 {object} model.MessageSearchResponse
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /guilds/{guildId}/messages/search [get]
+func (h *Handler) SearchGuildMessages(c *gin.Context) {
+	guildId := c.Param("guildId")
+	userId := c.MustGet("userId").(string)
+
+	var req searchReq
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	req.sanitize()
+
+	guild, err := h.guildService.GetGuild(guildId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("guild", guildId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// Only search the guild if the user is a member
+	if !isMember(guild, userId) {
+		e := apperrors.NewNotFound("guild", guildId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	search, err := parseSearchQuery(req.Query)
+
+	if err != nil {
+		toFieldErrorResponse(c, "Query", err.Error())
+		return
+	}
+
+	search.Offset = req.Offset
+
+	// Only search the channels the user can see
+	channelIds, err := h.channelService.GetAccessibleChannelIds(userId, guildId)
+
+	if err != nil {
+		log.Printf("Unable to find channels for guild id: %v\n%v", guildId, err)
+		e := apperrors.NewNotFound("channels", guildId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	h.searchMessages(c, userId, channelIds, search)
+}
+
+// SearchDirectMessages searches the messages of all DMs of the current user
+// SearchDirectMessages godoc
+// @Tags Channels
+// @Summary Search Direct Messages
+// @Produce  json
+// @Param q query string true "Search text and filters"
+// @Param offset query int false "Number of results to skip"
+// @Success 200 {object} model.MessageSearchResponse
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /channels/me/dm/search [get]
+func (h *Handler) SearchDirectMessages(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+
+	var req searchReq
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	req.sanitize()
+
+	search, err := parseSearchQuery(req.Query)
+
+	if err != nil {
+		toFieldErrorResponse(c, "Query", err.Error())
+		return
+	}
+
+	search.Offset = req.Offset
+
+	channelIds, err := h.channelService.GetDMChannelIds(userId)
+
+	if err != nil {
+		log.Printf("Unable to find dms for id: %v\n%v", userId, err)
+		e := apperrors.NewNotFound("dms", userId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	h.searchMessages(c, userId, channelIds, search)
+}
+
+// searchMessages writes the messages in the given channels that match the search
+func (h *Handler) searchMessages(c *gin.Context, userId string, channelIds []string, search *model.MessageSearch) {
+	result, err := h.messageService.SearchMessages(userId, channelIds, search)
+
+	if err != nil {
+		log.Printf("Failed to search messages: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// searchDateLayout is the format of the before and after filters
+const searchDateLayout = "2006-01-02"
+
+// parseSearchQuery splits the query into the free text and the filters.
+// Words with an unknown prefix are part of the text, so links still get matched.
+func parseSearchQuery(query string) (*model.MessageSearch, error) {
+	search := &model.MessageSearch{}
+	var text []string
+
+	for _, word := range strings.Fields(query) {
+		key, value, found := strings.Cut(word, ":")
+
+		if !found || value == "" {
+			text = append(text, word)
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "from":
+			// Also accept user mentions
+			id := strings.TrimSuffix(strings.TrimPrefix(value, "<@"), ">")
+			search.AuthorId = &id
+		case "in":
+			// Also accept channel links
+			id := strings.TrimSuffix(strings.TrimPrefix(value, "<#"), ">")
+			search.ChannelId = &id
+		case "has":
+			if strings.ToLower(value) != "attachment" {
+				return nil, errors.New(apperrors.InvalidSearchFilter)
+			}
+			search.HasAttachment = true
+		case "before", "after":
+			date, err := time.Parse(searchDateLayout, value)
+
+			if err != nil {
+				return nil, errors.New(apperrors.InvalidSearchDate)
+			}
+
+			// before excludes the given day while after includes it
+			if strings.ToLower(key) == "before" {
+				search.Before = &date
+			} else {
+				search.After = &date
+			}
+		default:
+			text = append(text, word)
+		}
+	}
+
+	search.Text = strings.Join(text, " ")
+
+	if search.Text == "" && search.AuthorId == nil && search.ChannelId == nil &&
+		!search.HasAttachment && search.Before == nil && search.After == nil {
+		return nil, errors.New(apperrors.SearchRequired)
+	}
+
+	return search, nil
+}
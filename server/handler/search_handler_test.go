@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestHandler_SearchGuildMessages(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successful search", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockGuild.Members = append(mockGuild.Members, *authUser)
+		mockChannel := fixture.GetMockChannel(mockGuild.ID)
+		authorId := fixture.RandID()
+		channelIds := []string{mockChannel.ID, fixture.RandID()}
+
+		after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		search := &model.MessageSearch{
+			Text:          "hello world",
+			AuthorId:      &authorId,
+			ChannelId:     &mockChannel.ID,
+			HasAttachment: true,
+			After:         &after,
+			Offset:        25,
+		}
+
+		response := &model.MessageSearchResponse{
+			Total: 26,
+			Messages: []model.MessageSearchResult{
+				{
+					ChannelId: mockChannel.ID,
+					Message:   *fixture.GetMockMessageResponse(authorId, mockChannel.ID),
+				},
+			},
+		}
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("GetAccessibleChannelIds", authUser.ID, mockGuild.ID).Return(channelIds, nil)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("SearchMessages", authUser.ID, channelIds, search).Return(response, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			GuildService:   mockGuildService,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		query := url.Values{}
+		query.Add("q", fmt.Sprintf("hello from:<@%s> in:%s has:attachment after:2024-01-01 world", authorId, mockChannel.ID))
+		query.Add("offset", "25")
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/messages/search?%s", mockGuild.ID, query.Encode())
+		request, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockGuildService.AssertExpectations(t)
+		mockChannelService.AssertExpectations(t)
+		mockMessageService.AssertExpectations(t)
+	})
+
+	t.Run("Not a member of the guild", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+
+		mockMessageService := new(mocks.MessageService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			GuildService:   mockGuildService,
+			MessageService: mockMessageService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/guilds/%s/messages/search?q=hello", mockGuild.ID)
+		request, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewNotFound("guild", mockGuild.ID)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockMessageService.AssertNotCalled(t, "SearchMessages", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestHandler_SearchGuildMessages_BadRequest(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+	mockGuild := fixture.GetMockGuild("")
+	mockGuild.Members = append(mockGuild.Members, *authUser)
+
+	mockGuildService := new(mocks.GuildService)
+	mockGuildService.On("GetGuild", mockGuild.ID).Return(mockGuild, nil)
+
+	mockMessageService := new(mocks.MessageService)
+
+	router := getAuthenticatedTestRouter(authUser.ID)
+
+	NewHandler(&Config{
+		R:              router,
+		GuildService:   mockGuildService,
+		MessageService: mockMessageService,
+	})
+
+	testCases := []struct {
+		name   string
+		query  url.Values
+		errMsg string
+	}{
+		{
+			name:  "Query required",
+			query: url.Values{},
+		},
+		{
+			name:  "Negative offset",
+			query: url.Values{"q": {"hello"}, "offset": {"-1"}},
+		},
+		{
+			name:  "Offset too large",
+			query: url.Values{"q": {"hello"}, "offset": {fmt.Sprint(model.MaximumSearchOffset + 1)}},
+		},
+		{
+			name:   "Unsupported has filter",
+			query:  url.Values{"q": {"has:link"}},
+			errMsg: apperrors.InvalidSearchFilter,
+		},
+		{
+			name:   "Invalid date",
+			query:  url.Values{"q": {"before:yesterday"}},
+			errMsg: apperrors.InvalidSearchDate,
+		},
+		{
+			name:   "Blank query",
+			query:  url.Values{"q": {"   "}},
+			errMsg: apperrors.SearchRequired,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			reqUrl := fmt.Sprintf("/api/guilds/%s/messages/search?%s", mockGuild.ID, tc.query.Encode())
+			request, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+			assert.NoError(t, err)
+
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+			if tc.errMsg != "" {
+				respBody, err := json.Marshal(gin.H{
+					"errors": []model.FieldError{
+						{
+							Field:   "Query",
+							Message: tc.errMsg,
+						},
+					},
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, respBody, rr.Body.Bytes())
+			}
+
+			mockMessageService.AssertNotCalled(t, "SearchMessages", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestHandler_SearchDirectMessages(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successful search", func(t *testing.T) {
+		channelIds := []string{fixture.RandID(), fixture.RandID()}
+		before := time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC)
+		search := &model.MessageSearch{
+			Text:   `"exact phrase"`,
+			Before: &before,
+		}
+
+		response := &model.MessageSearchResponse{
+			Messages: make([]model.MessageSearchResult, 0),
+		}
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("GetDMChannelIds", authUser.ID).Return(channelIds, nil)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("SearchMessages", authUser.ID, channelIds, search).Return(response, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		query := url.Values{}
+		query.Add("q", `"exact phrase" before:2023-06-15`)
+
+		request, err := http.NewRequest(http.MethodGet, "/api/channels/me/dm/search?"+query.Encode(), nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+		mockMessageService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"log"
+	"net/http"
+)
+
+/*
+ * SessionHandler contains all routes related to the user's sessions (/api/account/sessions)
+ */
+
+// GetSessions returns the active sessions of the current user
+// GetSessions godoc
+// @Tags Account
+// @Summary Get Current User's Sessions
+// @Produce  json
+// @Success 200 {array} model.Session
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /account/sessions [get]
+func (h *Handler) GetSessions(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+	currentId := c.GetString("sessionId")
+
+	sessions, err := h.sessionService.GetSessions(c.Request.Context(), userId)
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].Id == currentId
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// RevokeSession logs the given session out and closes its websocket connections
+// RevokeSession godoc
+// @Tags Account
+// @Summary Revoke Session
+// @Produce  json
+// @Param sessionId path string true "Session ID"
+// @Success 200 {object} model.Success
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /account/sessions/{sessionId} [delete]
+func (h *Handler) RevokeSession(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+	sessionId := c.Param("sessionId")
+
+	if err := h.sessionService.RevokeSession(c.Request.Context(), userId, sessionId); err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	h.socketService.DisconnectSessions(userId, []string{sessionId})
+
+	if sessionId == c.GetString("sessionId") {
+		clearUserSession(c)
+	}
+
+	c.JSON(http.StatusOK, true)
+}
+
+// RevokeAllSessions logs the current user out on every device including the current one
+// RevokeAllSessions godoc
+// @Tags Account
+// @Summary Log Out Everywhere
+// @Produce  json
+// @Success 200 {object} model.Success
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /account/sessions [delete]
+func (h *Handler) RevokeAllSessions(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+
+	ids, err := h.sessionService.RevokeOtherSessions(c.Request.Context(), userId, "")
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	h.socketService.DisconnectSessions(userId, ids)
+	clearUserSession(c)
+
+	c.JSON(http.StatusOK, true)
+}
+
+// revokeOtherSessions logs the user out everywhere except the given session
+// after their password changed. An empty currentId revokes every session.
+func (h *Handler) revokeOtherSessions(c *gin.Context, userId string, currentId string) {
+	if h.sessionService == nil {
+		return
+	}
+
+	ids, err := h.sessionService.RevokeOtherSessions(c.Request.Context(), userId, currentId)
+
+	if err != nil {
+		log.Printf("Failed to revoke the sessions of user %s: %v\n", userId, err.Error())
+		return
+	}
+
+	h.socketService.DisconnectSessions(userId, ids)
+}
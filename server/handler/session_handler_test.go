@@ -0,0 +1,299 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// getSessionTestRouter returns a router whose requests belong to the tracked session of the user
+func getSessionTestRouter(uid string, sessionId string) *gin.Engine {
+	router := getTestRouter()
+
+	router.Use(func(c *gin.Context) {
+		session := sessions.Default(c)
+		session.Set("userId", uid)
+		session.Set("sessionId", sessionId)
+	})
+
+	return router
+}
+
+// getSessionServiceMock returns a SessionService mock that accepts the tracked session
+func getSessionServiceMock(uid string, sessionId string) *mocks.SessionService {
+	mockSessionService := new(mocks.SessionService)
+	mockSessionService.On("TouchSession", mock.Anything, uid, sessionId, mock.Anything, mock.Anything).Return(nil)
+	return mockSessionService
+}
+
+func TestHandler_GetSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	uid := fixture.RandID()
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		router := getTestRouter()
+		mockSessionService := new(mocks.SessionService)
+
+		NewHandler(&Config{
+			R:              router,
+			SessionService: mockSessionService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		request, _ := http.NewRequest(http.MethodGet, "/api/account/sessions", nil)
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		mockSessionService.AssertNotCalled(t, "GetSessions", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Marks the current session", func(t *testing.T) {
+		router := getSessionTestRouter(uid, "current")
+		mockSessionService := getSessionServiceMock(uid, "current")
+
+		now := time.Now().UTC()
+		sessions := []model.Session{
+			{Id: "current", Device: "Firefox on Windows", LastUsedAt: now},
+			{Id: "other", Device: "Chrome on Android", LastUsedAt: now.Add(-time.Hour)},
+		}
+		mockSessionService.On("GetSessions", mock.Anything, uid).Return(sessions, nil)
+
+		NewHandler(&Config{
+			R:              router,
+			SessionService: mockSessionService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		request, _ := http.NewRequest(http.MethodGet, "/api/account/sessions", nil)
+
+		router.ServeHTTP(rr, request)
+
+		sessions[0].Current = true
+		respBody, _ := json.Marshal(sessions)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockSessionService.AssertExpectations(t)
+	})
+}
+
+func TestHandler_RevokeSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	uid := fixture.RandID()
+
+	t.Run("Revokes another session", func(t *testing.T) {
+		router := getSessionTestRouter(uid, "current")
+		mockSessionService := getSessionServiceMock(uid, "current")
+		mockSessionService.On("RevokeSession", mock.Anything, uid, "other").Return(nil)
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("DisconnectSessions", uid, []string{"other"}).Return()
+
+		NewHandler(&Config{
+			R:              router,
+			SessionService: mockSessionService,
+			SocketService:  mockSocketService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		request, _ := http.NewRequest(http.MethodDelete, "/api/account/sessions/other", nil)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(true)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		assert.NotContains(t, strings.Join(rr.Header().Values("Set-Cookie"), "\n"), "Max-Age=0")
+		mockSessionService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
+	t.Run("Revoking the current session logs out", func(t *testing.T) {
+		router := getSessionTestRouter(uid, "current")
+		mockSessionService := getSessionServiceMock(uid, "current")
+		mockSessionService.On("RevokeSession", mock.Anything, uid, "current").Return(nil)
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("DisconnectSessions", uid, []string{"current"}).Return()
+
+		NewHandler(&Config{
+			R:              router,
+			SessionService: mockSessionService,
+			SocketService:  mockSocketService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		request, _ := http.NewRequest(http.MethodDelete, "/api/account/sessions/current", nil)
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, strings.Join(rr.Header().Values("Set-Cookie"), "\n"), "Max-Age=0")
+		mockSessionService.AssertExpectations(t)
+	})
+
+	t.Run("Session not found", func(t *testing.T) {
+		router := getSessionTestRouter(uid, "current")
+		mockSessionService := getSessionServiceMock(uid, "current")
+
+		mockError := apperrors.NewNotFound("session", "unknown")
+		mockSessionService.On("RevokeSession", mock.Anything, uid, "unknown").Return(mockError)
+
+		mockSocketService := new(mocks.SocketService)
+
+		NewHandler(&Config{
+			R:              router,
+			SessionService: mockSessionService,
+			SocketService:  mockSocketService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		request, _ := http.NewRequest(http.MethodDelete, "/api/account/sessions/unknown", nil)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(gin.H{
+			"error": mockError,
+		})
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockSocketService.AssertNotCalled(t, "DisconnectSessions", mock.Anything, mock.Anything)
+	})
+}
+
+func TestHandler_RevokeAllSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	uid := fixture.RandID()
+
+	router := getSessionTestRouter(uid, "current")
+	mockSessionService := getSessionServiceMock(uid, "current")
+	mockSessionService.On("RevokeOtherSessions", mock.Anything, uid, "").Return([]string{"current", "other"}, nil)
+
+	mockSocketService := new(mocks.SocketService)
+	mockSocketService.On("DisconnectSessions", uid, []string{"current", "other"}).Return()
+
+	NewHandler(&Config{
+		R:              router,
+		SessionService: mockSessionService,
+		SocketService:  mockSocketService,
+	})
+
+	rr := httptest.NewRecorder()
+
+	request, _ := http.NewRequest(http.MethodDelete, "/api/account/sessions", nil)
+
+	router.ServeHTTP(rr, request)
+
+	respBody, _ := json.Marshal(true)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, respBody, rr.Body.Bytes())
+	assert.Contains(t, strings.Join(rr.Header().Values("Set-Cookie"), "\n"), "Max-Age=0")
+	mockSessionService.AssertExpectations(t)
+	mockSocketService.AssertExpectations(t)
+}
+
+func TestHandler_ChangePassword_RevokesOtherSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUser := fixture.GetMockUser()
+	newPassword := "password!"
+
+	router := getSessionTestRouter(mockUser.ID, "current")
+	mockSessionService := getSessionServiceMock(mockUser.ID, "current")
+	mockSessionService.On("RevokeOtherSessions", mock.Anything, mockUser.ID, "current").Return([]string{"other"}, nil)
+
+	mockUserService := new(mocks.UserService)
+	mockUserService.On("Get", mockUser.ID).Return(mockUser, nil)
+	mockUserService.On("ChangePassword", mockUser.Password, newPassword, mockUser).Return(nil)
+
+	mockSocketService := new(mocks.SocketService)
+	mockSocketService.On("DisconnectSessions", mockUser.ID, []string{"other"}).Return()
+
+	NewHandler(&Config{
+		R:              router,
+		UserService:    mockUserService,
+		SessionService: mockSessionService,
+		SocketService:  mockSocketService,
+	})
+
+	rr := httptest.NewRecorder()
+
+	reqBody, _ := json.Marshal(gin.H{
+		"currentPassword":    mockUser.Password,
+		"newPassword":        newPassword,
+		"confirmNewPassword": newPassword,
+	})
+
+	request, _ := http.NewRequest(http.MethodPut, "/api/account/change-password", bytes.NewBuffer(reqBody))
+	request.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(rr, request)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockUserService.AssertExpectations(t)
+	mockSessionService.AssertExpectations(t)
+	mockSocketService.AssertExpectations(t)
+}
+
+func TestHandler_Login_TracksSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	user := fixture.GetMockUser()
+
+	mockUserService := new(mocks.UserService)
+	mockUserService.On("Login", user.Email, user.Password).Return(user, nil)
+
+	mockSessionService := new(mocks.SessionService)
+	mockSessionService.
+		On("CreateSession", mock.Anything, user.ID, "Firefox", mock.Anything).
+		Return(&model.Session{Id: "session"}, nil)
+
+	router := getTestRouter()
+
+	NewHandler(&Config{
+		R:              router,
+		UserService:    mockUserService,
+		SessionService: mockSessionService,
+	})
+
+	rr := httptest.NewRecorder()
+
+	reqBody, _ := json.Marshal(gin.H{
+		"email":    user.Email,
+		"password": user.Password,
+	})
+
+	request, _ := http.NewRequest(http.MethodPost, "/api/account/login", bytes.NewBuffer(reqBody))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "Firefox")
+
+	router.ServeHTTP(rr, request)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotEmpty(t, rr.Header().Get("Set-Cookie"))
+	mockSessionService.AssertExpectations(t)
+}
@@ -0,0 +1,474 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+/*
+ * ThreadHandler contains all routes related to thread actions (/api/channels and /api/threads)
+ */
+
+// GetThreads returns the threads of the given channel
+// GetThreads godoc
+// @Tags Threads
+// @Summary Get Channel Threads
+// @Produce  json
+// @Param channelId path string true "Channel ID"
+// @Param archived query bool false "Return the archived threads instead of the active ones"
+// @Success 200 {array} model.ThreadResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /channels/{channelId}/threads [get]
+func (h *Handler) GetThreads(c *gin.Context) {
+	channelId := c.Param("id")
+	userId := c.MustGet("userId").(string)
+
+	channel, ok := h.getThreadParent(c, channelId, userId)
+
+	if !ok {
+		return
+	}
+
+	archived := c.Query("archived") == "true"
+
+	threads, err := h.channelService.GetThreads(channel.ID, archived)
+
+	if err != nil {
+		log.Printf("Unable to find threads for channel: %v\n%v", channelId, err)
+		e := apperrors.NewNotFound("threads", channelId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, threads)
+}
+
+// createThreadReq specifies the message to start the thread from
+type createThreadReq struct {
+	// ID of a message in the channel
+	MessageId string `json:"messageId"`
+	// Thread Name. 1 to 100 characters
+	Name string `json:"name"`
+	// Minutes without activity until the thread gets archived.
+	// One of 60, 1440, 4320 or 10080. Default is 1440
+	AutoArchiveDuration int `json:"autoArchiveDuration"`
+} //@name CreateThreadRequest
+
+func (r createThreadReq) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.MessageId, validation.Required),
+		validation.Field(&r.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&r.AutoArchiveDuration, isArchiveDuration),
+	)
+}
+
+func (r *createThreadReq) sanitize() {
+	r.Name = strings.TrimSpace(r.Name)
+
+	if r.AutoArchiveDuration == 0 {
+		r.AutoArchiveDuration = model.ArchiveAfterDay
+	}
+}
+
+var isArchiveDuration = validation.In(
+	model.ArchiveAfterHour,
+	model.ArchiveAfterDay,
+	model.ArchiveAfterThreeDays,
+	model.ArchiveAfterWeek,
+)
+
+// CreateThread starts a thread from the given message
+// CreateThread godoc
+// @Tags Threads
+// @Summary Create Thread
+// @Accepts json
+// @Produce  json
+// @Param channelId path string true "Channel ID"
+// @Param request body createThreadReq true "Create Thread"
+// @Success 201 {object} model.ThreadResponse
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /channels/{channelId}/threads [post]
+func (h *Handler) CreateThread(c *gin.Context) {
+	var req createThreadReq
+
+	// Bind incoming json to struct and check for validation errors
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	req.sanitize()
+
+	channelId := c.Param("id")
+	userId := c.MustGet("userId").(string)
+
+	channel, ok := h.getThreadParent(c, channelId, userId)
+
+	if !ok {
+		return
+	}
+
+	// Starting a thread requires the same permissions as sending a message
+	if !h.channelService.HasPermission(userId, channel, model.PermissionSendMessages) {
+		e := apperrors.NewAuthorization(apperrors.SendMessagesError)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	message, err := h.messageService.Get(req.MessageId)
+
+	if err != nil || message.ChannelId != channel.ID {
+		e := apperrors.NewNotFound("message", req.MessageId)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// A message can only have one thread
+	if _, err = h.channelService.GetThreadByMessage(message.ID); err == nil {
+		e := apperrors.NewBadRequest(apperrors.ThreadExistsError)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	params := model.Channel{
+		Name:                req.Name,
+		GuildID:             channel.GuildID,
+		ParentID:            &channel.ID,
+		ParentMessageID:     &message.ID,
+		OwnerID:             &userId,
+		AutoArchiveDuration: req.AutoArchiveDuration,
+	}
+
+	thread, err := h.channelService.CreateThread(&params)
+
+	if err != nil {
+		log.Printf("Failed to create thread: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	response := thread.SerializeThread()
+
+	// Emit the new thread to the members in the parent channel
+	h.socketService.EmitNewThread(&response)
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// editThreadReq specifies the thread settings that can be changed
+type editThreadReq struct {
+	// Thread Name. 1 to 100 characters
+	Name string `json:"name"`
+	// Archive or unarchive the thread. Unchanged if not specified
+	IsArchived *bool `json:"isArchived"`
+	// One of 60, 1440, 4320 or 10080. Unchanged if not specified
+	AutoArchiveDuration int `json:"autoArchiveDuration"`
+} //@name EditThreadRequest
+
+func (r editThreadReq) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&r.AutoArchiveDuration, isArchiveDuration),
+	)
+}
+
+func (r *editThreadReq) sanitize() {
+	r.Name = strings.TrimSpace(r.Name)
+}
+
+// EditThread edits the given thread
+// EditThread godoc
+// @Tags Threads
+// @Summary Edit Thread
+// @Accepts json
+// @Produce  json
+// @Param threadId path string true "Thread ID"
+// @Param request body editThreadReq true "Edit Thread"
+// @Success 200 {object} model.Success
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /threads/{threadId} [put]
+func (h *Handler) EditThread(c *gin.Context) {
+	var req editThreadReq
+
+	// Bind incoming json to struct and check for validation errors
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	req.sanitize()
+
+	userId := c.MustGet("userId").(string)
+
+	thread, ok := h.getThread(c, userId)
+
+	if !ok {
+		return
+	}
+
+	isOwner := thread.OwnerID != nil && *thread.OwnerID == userId
+	if !isOwner && !h.channelService.HasPermission(userId, thread, model.PermissionManageMessages) {
+		e := apperrors.NewAuthorization(apperrors.EditThreadError)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	thread.Name = req.Name
+
+	if req.AutoArchiveDuration != 0 {
+		thread.AutoArchiveDuration = req.AutoArchiveDuration
+	}
+
+	if req.IsArchived != nil {
+		// Reset the activity so the thread does not get archived right away
+		if thread.IsArchived && !*req.IsArchived {
+			thread.LastActivity = time.Now()
+		}
+		thread.IsArchived = *req.IsArchived
+	}
+
+	if err := h.channelService.UpdateChannel(thread); err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	// Emit the thread changes
+	response := thread.SerializeThread()
+	h.socketService.EmitEditThread(&response)
+
+	c.JSON(http.StatusOK, true)
+}
+
+// DeleteThread deletes the given thread and all of its messages
+// DeleteThread godoc
+// @Tags Threads
+// @Summary Delete Thread
+// @Produce  json
+// @Param threadId path string true "Thread ID"
+// @Success 200 {object} model.Success
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /threads/{threadId} [delete]
+func (h *Handler) DeleteThread(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+
+	thread, ok := h.getThread(c, userId)
+
+	if !ok {
+		return
+	}
+
+	if !h.channelService.HasPermission(userId, thread, model.PermissionManageMessages) {
+		e := apperrors.NewAuthorization(apperrors.MissingPermissions)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	if err := h.channelService.DeleteChannel(thread); err != nil {
+		log.Printf("Failed to delete thread: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	// Emit the deleted thread
+	response := thread.SerializeThread()
+	h.socketService.EmitDeleteThread(&response)
+
+	c.JSON(http.StatusOK, true)
+}
+
+// GetThreadMembers returns the ids of all members that joined the thread
+// GetThreadMembers godoc
+// @Tags Threads
+// @Summary Get Thread Members
+// @Produce  json
+// @Param threadId path string true "Thread ID"
+// @Success 200 {array} string
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /threads/{threadId}/members [get]
+func (h *Handler) GetThreadMembers(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+
+	thread, ok := h.getThread(c, userId)
+
+	if !ok {
+		return
+	}
+
+	members, err := h.channelService.GetThreadMembers(thread.ID)
+
+	if err != nil {
+		log.Printf("Unable to find members for thread: %v\n%v", thread.ID, err)
+		e := apperrors.NewNotFound("members", thread.ID)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	// If nobody joined the thread, return an empty array
+	if len(*members) == 0 {
+		var empty = make([]string, 0)
+		c.JSON(http.StatusOK, empty)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// JoinThread adds the current user to the members of the thread
+// JoinThread godoc
+// @Tags Threads
+// @Summary Join Thread
+// @Produce  json
+// @Param threadId path string true "Thread ID"
+// @Success 200 {object} model.Success
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /threads/{threadId}/members [post]
+func (h *Handler) JoinThread(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+
+	thread, ok := h.getThread(c, userId)
+
+	if !ok {
+		return
+	}
+
+	if err := h.channelService.AddThreadMember(thread.ID, userId); err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, true)
+}
+
+// LeaveThread removes the current user from the members of the thread
+// LeaveThread godoc
+// @Tags Threads
+// @Summary Leave Thread
+// @Produce  json
+// @Param threadId path string true "Thread ID"
+// @Success 200 {object} model.Success
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /threads/{threadId}/members [delete]
+func (h *Handler) LeaveThread(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+
+	thread, ok := h.getThread(c, userId)
+
+	if !ok {
+		return
+	}
+
+	if err := h.channelService.RemoveThreadMember(thread.ID, userId); err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, true)
+}
+
+// getThreadParent returns the guild channel for the given id if the user has access to it
+// and threads can be started in it.
+// Otherwise, it writes the error response and returns false.
+func (h *Handler) getThreadParent(c *gin.Context, channelId, userId string) (*model.Channel, bool) {
+	channel, err := h.channelService.Get(channelId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("channel", channelId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, false
+	}
+
+	// Check if the user has access to said channel
+	if err = h.channelService.IsChannelMember(channel, userId); err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return nil, false
+	}
+
+	// Threads cannot be nested and DMs do not have threads
+	if channel.IsDM || channel.IsThread() {
+		e := apperrors.NewBadRequest(apperrors.ThreadParentError)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, false
+	}
+
+	return channel, true
+}
+
+// getThread returns the thread of the threadId param if the user has access to it.
+// Otherwise, it writes the error response and returns false.
+func (h *Handler) getThread(c *gin.Context, userId string) (*model.Channel, bool) {
+	threadId := c.Param("threadId")
+
+	thread, err := h.channelService.Get(threadId)
+
+	if err != nil || !thread.IsThread() {
+		e := apperrors.NewNotFound("thread", threadId)
+
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, false
+	}
+
+	// Check if the user has access to the parent channel
+	if err = h.channelService.IsChannelMember(thread, userId); err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return nil, false
+	}
+
+	return thread, true
+}
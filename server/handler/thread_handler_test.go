@@ -0,0 +1,690 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_GetThreads(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successful fetch", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+
+		response := make([]model.ThreadResponse, 0)
+		for i := 0; i < 3; i++ {
+			response = append(response, fixture.GetMockThread(mockChannel, authUser.ID).SerializeThread())
+		}
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("GetThreads", mockChannel.ID, true).Return(&response, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/threads?archived=true", mockChannel.ID)
+		request, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+	})
+
+	t.Run("DM channels do not have threads", func(t *testing.T) {
+		mockChannel := fixture.GetMockDMChannel()
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+		})
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/threads", mockChannel.ID)
+		request, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewBadRequest(apperrors.ThreadParentError)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertNotCalled(t, "GetThreads", mock.Anything, mock.Anything)
+	})
+}
+
+func TestHandler_CreateThread(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successfully created", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockMessage := fixture.GetMockMessage("", mockChannel.ID)
+		name := fixture.RandStringRunes(10)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(true)
+		mockChannelService.On("GetThreadByMessage", mockMessage.ID).Return(nil, errors.New("record not found"))
+
+		params := &model.Channel{
+			Name:                name,
+			GuildID:             mockChannel.GuildID,
+			ParentID:            &mockChannel.ID,
+			ParentMessageID:     &mockMessage.ID,
+			OwnerID:             &authUser.ID,
+			AutoArchiveDuration: model.ArchiveAfterHour,
+		}
+
+		mockThread := fixture.GetMockThread(mockChannel, authUser.ID)
+		mockThread.Name = name
+		mockThread.ParentMessageID = &mockMessage.ID
+		mockThread.AutoArchiveDuration = model.ArchiveAfterHour
+		mockChannelService.On("CreateThread", params).Return(mockThread, nil)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+
+		response := mockThread.SerializeThread()
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitNewThread", &response)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+			SocketService:  mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"messageId":           mockMessage.ID,
+			"name":                name,
+			"autoArchiveDuration": model.ArchiveAfterHour,
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/threads", mockChannel.ID)
+		request, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+		mockMessageService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
+	t.Run("Message already has a thread", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockMessage := fixture.GetMockMessage("", mockChannel.ID)
+		mockThread := fixture.GetMockThread(mockChannel, fixture.RandID())
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(true)
+		mockChannelService.On("GetThreadByMessage", mockMessage.ID).Return(mockThread, nil)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"messageId": mockMessage.ID,
+			"name":      fixture.RandStringRunes(10),
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/threads", mockChannel.ID)
+		request, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewBadRequest(apperrors.ThreadExistsError)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertNotCalled(t, "CreateThread", mock.Anything)
+	})
+
+	t.Run("Message is in another channel", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockMessage := fixture.GetMockMessage("", fixture.RandID())
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(true)
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("Get", mockMessage.ID).Return(mockMessage, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"messageId": mockMessage.ID,
+			"name":      fixture.RandStringRunes(10),
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/threads", mockChannel.ID)
+		request, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewNotFound("message", mockMessage.ID)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertNotCalled(t, "CreateThread", mock.Anything)
+	})
+
+	t.Run("Threads cannot be nested", func(t *testing.T) {
+		mockThread := fixture.GetMockThread(fixture.GetMockChannel(fixture.RandID()), authUser.ID)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockThread.ID).Return(mockThread, nil)
+		mockChannelService.On("IsChannelMember", mockThread, authUser.ID).Return(nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"messageId": fixture.RandID(),
+			"name":      fixture.RandStringRunes(10),
+		})
+		assert.NoError(t, err)
+
+		reqUrl := fmt.Sprintf("/api/channels/%s/threads", mockThread.ID)
+		request, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewBadRequest(apperrors.ThreadParentError)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertNotCalled(t, "CreateThread", mock.Anything)
+	})
+}
+
+func TestHandler_CreateThread_BadRequest(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	mockUser := fixture.GetMockUser()
+	router := getAuthenticatedTestRouter(mockUser.ID)
+
+	mockChannelService := new(mocks.ChannelService)
+
+	NewHandler(&Config{
+		R:              router,
+		ChannelService: mockChannelService,
+	})
+
+	testCases := []struct {
+		name string
+		body gin.H
+	}{
+		{
+			name: "Message required",
+			body: gin.H{
+				"name": fixture.RandStringRunes(10),
+			},
+		},
+		{
+			name: "Name required",
+			body: gin.H{
+				"messageId": fixture.RandID(),
+			},
+		},
+		{
+			name: "Name too long",
+			body: gin.H{
+				"messageId": fixture.RandID(),
+				"name":      fixture.RandStringRunes(101),
+			},
+		},
+		{
+			name: "Invalid archive duration",
+			body: gin.H{
+				"messageId":           fixture.RandID(),
+				"name":                fixture.RandStringRunes(10),
+				"autoArchiveDuration": 5,
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			reqBody, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			reqUrl := fmt.Sprintf("/api/channels/%s/threads", fixture.RandID())
+			request, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(reqBody))
+			assert.NoError(t, err)
+
+			request.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			mockChannelService.AssertNotCalled(t, "CreateThread")
+		})
+	}
+}
+
+func TestHandler_EditThread(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Owner unarchives the thread", func(t *testing.T) {
+		mockThread := fixture.GetMockThread(fixture.GetMockChannel(fixture.RandID()), authUser.ID)
+		mockThread.IsArchived = true
+		name := fixture.RandStringRunes(10)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockThread.ID).Return(mockThread, nil)
+		mockChannelService.On("IsChannelMember", mockThread, authUser.ID).Return(nil)
+		mockChannelService.
+			On("UpdateChannel", mock.MatchedBy(func(thread *model.Channel) bool {
+				return thread.Name == name && !thread.IsArchived
+			})).
+			Return(nil)
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitEditThread", mock.AnythingOfType("*model.ThreadResponse"))
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			SocketService:  mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"name":       name,
+			"isArchived": false,
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPut, "/api/threads/"+mockThread.ID, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+		mockChannelService.AssertNotCalled(t, "HasPermission", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Not the owner or a moderator", func(t *testing.T) {
+		mockThread := fixture.GetMockThread(fixture.GetMockChannel(fixture.RandID()), fixture.RandID())
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockThread.ID).Return(mockThread, nil)
+		mockChannelService.On("IsChannelMember", mockThread, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockThread, model.PermissionManageMessages).Return(false)
+
+		mockSocketService := new(mocks.SocketService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			SocketService:  mockSocketService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"name": fixture.RandStringRunes(10),
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPut, "/api/threads/"+mockThread.ID, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewAuthorization(apperrors.EditThreadError)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertNotCalled(t, "UpdateChannel", mock.Anything)
+		mockSocketService.AssertNotCalled(t, "EmitEditThread", mock.Anything)
+	})
+
+	t.Run("Channel is not a thread", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"name": fixture.RandStringRunes(10),
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPut, "/api/threads/"+mockChannel.ID, bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewNotFound("thread", mockChannel.ID)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertNotCalled(t, "UpdateChannel", mock.Anything)
+	})
+}
+
+func TestHandler_DeleteThread(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successfully deleted", func(t *testing.T) {
+		mockThread := fixture.GetMockThread(fixture.GetMockChannel(fixture.RandID()), fixture.RandID())
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockThread.ID).Return(mockThread, nil)
+		mockChannelService.On("IsChannelMember", mockThread, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockThread, model.PermissionManageMessages).Return(true)
+		mockChannelService.On("DeleteChannel", mockThread).Return(nil)
+
+		response := mockThread.SerializeThread()
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitDeleteThread", &response)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			SocketService:  mockSocketService,
+		})
+
+		request, err := http.NewRequest(http.MethodDelete, "/api/threads/"+mockThread.ID, nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+}
+
+func TestHandler_JoinThread(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successfully joined", func(t *testing.T) {
+		mockThread := fixture.GetMockThread(fixture.GetMockChannel(fixture.RandID()), fixture.RandID())
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockThread.ID).Return(mockThread, nil)
+		mockChannelService.On("IsChannelMember", mockThread, authUser.ID).Return(nil)
+		mockChannelService.On("AddThreadMember", mockThread.ID, authUser.ID).Return(nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+		})
+
+		request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/threads/%s/members", mockThread.ID), nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+	})
+
+	t.Run("No access to the parent channel", func(t *testing.T) {
+		mockThread := fixture.GetMockThread(fixture.GetMockChannel(fixture.RandID()), fixture.RandID())
+		mockError := apperrors.NewAuthorization(apperrors.Unauthorized)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockThread.ID).Return(mockThread, nil)
+		mockChannelService.On("IsChannelMember", mockThread, authUser.ID).Return(mockError)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+		})
+
+		request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/threads/%s/members", mockThread.ID), nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertNotCalled(t, "AddThreadMember", mock.Anything, mock.Anything)
+	})
+}
+
+func TestHandler_GetThreadMembers(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successful fetch", func(t *testing.T) {
+		mockThread := fixture.GetMockThread(fixture.GetMockChannel(fixture.RandID()), authUser.ID)
+		members := []string{authUser.ID, fixture.RandID()}
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockThread.ID).Return(mockThread, nil)
+		mockChannelService.On("IsChannelMember", mockThread, authUser.ID).Return(nil)
+		mockChannelService.On("GetThreadMembers", mockThread.ID).Return(&members, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+		})
+
+		request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/threads/%s/members", mockThread.ID), nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(members)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+	})
+}
+
+func TestHandler_LeaveThread(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successfully left", func(t *testing.T) {
+		mockThread := fixture.GetMockThread(fixture.GetMockChannel(fixture.RandID()), authUser.ID)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockThread.ID).Return(mockThread, nil)
+		mockChannelService.On("IsChannelMember", mockThread, authUser.ID).Return(nil)
+		mockChannelService.On("RemoveThreadMember", mockThread.ID, authUser.ID).Return(nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+		})
+
+		request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/api/threads/%s/members", mockThread.ID), nil)
+		assert.NoError(t, err)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockChannelService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,288 @@
+package handler
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+ * TusHandler contains all routes related to resumable uploads
+ * using the tus protocol (/api/uploads). See https://tus.io/protocols/resumable-upload
+ */
+
+// tusExtensions are the supported extensions of the tus protocol
+const tusExtensions = "creation,expiration,termination"
+
+// GetTusOptions returns the supported version, extensions and maximum size of the tus protocol
+// GetTusOptions godoc
+// @Tags Uploads
+// @Summary Get Resumable Upload Options
+// @Success 204
+// @Router /uploads [options]
+func (h *Handler) GetTusOptions(c *gin.Context) {
+	c.Header("Tus-Version", model.TusVersion)
+	c.Header("Tus-Extension", tusExtensions)
+	c.Header("Tus-Max-Size", fmt.Sprint(model.MaximumUploadSize))
+	c.Status(http.StatusNoContent)
+}
+
+// CreateResumableUpload creates a resumable upload for the file described by the
+// Upload-Length and Upload-Metadata headers. The metadata must contain the channelId,
+// filename and filetype. Send a message with the ID of the upload once it is complete.
+// CreateResumableUpload godoc
+// @Tags Uploads
+// @Summary Create Resumable Upload
+// @Param Tus-Resumable header string true "1.0.0"
+// @Param Upload-Length header int true "Size of the file in bytes"
+// @Param Upload-Metadata header string true "Base64 encoded channelId, filename and filetype"
+// @Success 201
+// @Header 201 {string} Location "URL of the upload"
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 412 {object} model.ErrorResponse
+// @Failure 413 {object} model.ErrorResponse
+// @Router /uploads [post]
+func (h *Handler) CreateResumableUpload(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+
+	size, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+
+	if err != nil || size < 1 {
+		e := apperrors.NewBadRequest(apperrors.UploadLengthError)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	if size > model.MaximumUploadSize {
+		e := apperrors.NewPayloadTooLarge(model.MaximumUploadSize, size)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	metadata := parseUploadMetadata(c.GetHeader("Upload-Metadata"))
+	channelId, filename, filetype := metadata["channelId"], metadata["filename"], metadata["filetype"]
+
+	if channelId == "" || filename == "" || filetype == "" {
+		e := apperrors.NewBadRequest(apperrors.UploadMetadataError)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	if !isPlainFilename(filename) {
+		e := apperrors.NewBadRequest(apperrors.InvalidFilename)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	filetype, ok := h.checkUploadType(c, "filetype", filetype, size)
+
+	if !ok {
+		return
+	}
+
+	channel, ok := h.getUploadChannel(c, channelId, userId)
+
+	if !ok {
+		return
+	}
+
+	upload := model.Upload{
+		UserId:    userId,
+		ChannelId: channel.ID,
+		Filename:  filename,
+		FileType:  filetype,
+		Size:      size,
+	}
+
+	if err = h.messageService.CreateResumableUpload(&upload); err != nil {
+		log.Printf("Failed to create resumable upload: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.Header("Location", "/api/uploads/"+upload.ID)
+	c.Header("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.Status(http.StatusCreated)
+}
+
+// GetUploadOffset returns the number of received bytes of the resumable upload
+// in the Upload-Offset header to resume the upload from there
+// GetUploadOffset godoc
+// @Tags Uploads
+// @Summary Get Resumable Upload Offset
+// @Param Tus-Resumable header string true "1.0.0"
+// @Param id path string true "Upload ID"
+// @Success 200
+// @Header 200 {int} Upload-Offset "Received bytes"
+// @Header 200 {int} Upload-Length "Size of the file in bytes"
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 412 {object} model.ErrorResponse
+// @Router /uploads/{id} [head]
+func (h *Handler) GetUploadOffset(c *gin.Context) {
+	upload, ok := h.getResumableUpload(c)
+
+	if !ok {
+		return
+	}
+
+	c.Header("Upload-Offset", fmt.Sprint(upload.Offset))
+	c.Header("Upload-Length", fmt.Sprint(upload.Size))
+	c.Header("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+}
+
+// AppendUploadChunk appends the request body to the resumable upload at the given Upload-Offset
+// AppendUploadChunk godoc
+// @Tags Uploads
+// @Summary Upload Chunk
+// @Accept octet-stream
+// @Param Tus-Resumable header string true "1.0.0"
+// @Param Upload-Offset header int true "Offset the chunk starts at"
+// @Param id path string true "Upload ID"
+// @Success 204
+// @Header 204 {int} Upload-Offset "Received bytes"
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 412 {object} model.ErrorResponse
+// @Failure 415 {object} model.ErrorResponse
+// @Router /uploads/{id} [patch]
+func (h *Handler) AppendUploadChunk(c *gin.Context) {
+	if c.GetHeader("Content-Type") != "application/offset+octet-stream" {
+		e := apperrors.NewUnsupportedMediaType(apperrors.UploadContentTypeError)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	upload, ok := h.getResumableUpload(c)
+
+	if !ok {
+		return
+	}
+
+	// The chunk must start where the previous one ended
+	offset := c.GetHeader("Upload-Offset")
+	if offset != fmt.Sprint(upload.Offset) {
+		e := apperrors.NewConflict("offset", offset)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	if err := h.messageService.AppendUploadChunk(upload, c.Request.Body); err != nil {
+		log.Printf("Failed to append chunk to upload %s: %v\n", upload.ID, err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.Header("Upload-Offset", fmt.Sprint(upload.Offset))
+	c.Header("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.Status(http.StatusNoContent)
+}
+
+// TerminateUpload deletes the resumable upload and its received chunks
+// TerminateUpload godoc
+// @Tags Uploads
+// @Summary Terminate Resumable Upload
+// @Param Tus-Resumable header string true "1.0.0"
+// @Param id path string true "Upload ID"
+// @Success 204
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 412 {object} model.ErrorResponse
+// @Router /uploads/{id} [delete]
+func (h *Handler) TerminateUpload(c *gin.Context) {
+	upload, ok := h.getResumableUpload(c)
+
+	if !ok {
+		return
+	}
+
+	if err := h.messageService.TerminateUpload(upload); err != nil {
+		log.Printf("Failed to terminate upload %s: %v\n", upload.ID, err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// getResumableUpload returns the resumable upload of the id param if it belongs to the user.
+// Otherwise, it writes the error response and returns false.
+func (h *Handler) getResumableUpload(c *gin.Context) (*model.Upload, bool) {
+	uploadId := c.Param("id")
+	userId := c.MustGet("userId").(string)
+
+	upload, err := h.messageService.GetUpload(uploadId)
+
+	if err != nil || !upload.Resumable || upload.UserId != userId {
+		e := apperrors.NewNotFound("upload", uploadId)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, false
+	}
+
+	return upload, true
+}
+
+// parseUploadMetadata decodes the comma separated key value pairs
+// of the Upload-Metadata header. The values are base64 encoded.
+// isPlainFilename checks that the filename has at most 255 characters
+// and does not contain any directories
+func isPlainFilename(filename string) bool {
+	return utf8.RuneCountInString(filename) <= 255 &&
+		!strings.ContainsAny(filename, `/\`) &&
+		filename != "." && filename != ".."
+}
+
+func parseUploadMetadata(header string) map[string]string {
+	metadata := make(map[string]string)
+
+	for _, pair := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(pair), " ")
+
+		if key == "" {
+			continue
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(value)
+
+		if err != nil {
+			continue
+		}
+
+		metadata[key] = strings.TrimSpace(string(decoded))
+	}
+
+	return metadata
+}
@@ -0,0 +1,515 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// encodeUploadMetadata returns the Upload-Metadata header of the given pairs
+func encodeUploadMetadata(pairs ...string) string {
+	values := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		values = append(values, pairs[i]+" "+base64.StdEncoding.EncodeToString([]byte(pairs[i+1])))
+	}
+	return strings.Join(values, ",")
+}
+
+func TestHandler_GetTusOptions(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	rr := httptest.NewRecorder()
+
+	router := getTestRouter()
+
+	NewHandler(&Config{
+		R: router,
+	})
+
+	request, err := http.NewRequest(http.MethodOptions, "/api/uploads", nil)
+	assert.NoError(t, err)
+
+	router.ServeHTTP(rr, request)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, model.TusVersion, rr.Header().Get("Tus-Resumable"))
+	assert.Equal(t, model.TusVersion, rr.Header().Get("Tus-Version"))
+	assert.Equal(t, tusExtensions, rr.Header().Get("Tus-Extension"))
+	assert.Equal(t, fmt.Sprint(model.MaximumUploadSize), rr.Header().Get("Tus-Max-Size"))
+}
+
+func TestHandler_CreateResumableUpload(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successfully created upload", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel("")
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(true)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionAttachFiles).Return(true)
+
+		params := &model.Upload{
+			UserId:    authUser.ID,
+			ChannelId: mockChannel.ID,
+			Filename:  "video.mp4",
+			FileType:  "video/mp4",
+			Size:      80 << 20,
+		}
+
+		uploadId := fixture.RandID()
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.
+			On("CreateResumableUpload", params).
+			Run(func(args mock.Arguments) {
+				upload := args.Get(0).(*model.Upload)
+				upload.ID = uploadId
+				upload.ExpiresAt = time.Now().Add(model.UploadExpiration)
+			}).
+			Return(nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		request, err := http.NewRequest(http.MethodPost, "/api/uploads", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Tus-Resumable", model.TusVersion)
+		request.Header.Set("Upload-Length", fmt.Sprint(80<<20))
+		request.Header.Set("Upload-Metadata", encodeUploadMetadata(
+			"channelId", mockChannel.ID,
+			"filename", "video.mp4",
+			"filetype", "video/mp4",
+		))
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, "/api/uploads/"+uploadId, rr.Header().Get("Location"))
+		assert.NotEmpty(t, rr.Header().Get("Upload-Expires"))
+		assert.Equal(t, model.TusVersion, rr.Header().Get("Tus-Resumable"))
+
+		mockChannelService.AssertExpectations(t)
+		mockMessageService.AssertExpectations(t)
+	})
+
+	t.Run("Unsupported tus version", func(t *testing.T) {
+		mockMessageService := new(mocks.MessageService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			MessageService: mockMessageService,
+		})
+
+		request, err := http.NewRequest(http.MethodPost, "/api/uploads", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Upload-Length", "1024")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+		assert.Equal(t, model.TusVersion, rr.Header().Get("Tus-Version"))
+
+		mockMessageService.AssertNotCalled(t, "CreateResumableUpload", mock.Anything)
+	})
+
+	testCases := []struct {
+		name     string
+		length   string
+		metadata string
+		status   int
+	}{
+		{
+			name:     "Missing Upload-Length",
+			length:   "",
+			metadata: encodeUploadMetadata("channelId", fixture.RandID(), "filename", "audio.mp3", "filetype", "audio/mp3"),
+			status:   http.StatusBadRequest,
+		},
+		{
+			name:     "Empty upload",
+			length:   "0",
+			metadata: encodeUploadMetadata("channelId", fixture.RandID(), "filename", "audio.mp3", "filetype", "audio/mp3"),
+			status:   http.StatusBadRequest,
+		},
+		{
+			name:     "Upload too large",
+			length:   fmt.Sprint(model.MaximumUploadSize + 1),
+			metadata: encodeUploadMetadata("channelId", fixture.RandID(), "filename", "audio.mp3", "filetype", "audio/mp3"),
+			status:   http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:     "Missing filename",
+			length:   "1024",
+			metadata: encodeUploadMetadata("channelId", fixture.RandID(), "filetype", "audio/mp3"),
+			status:   http.StatusBadRequest,
+		},
+		{
+			name:     "Filename with directories",
+			length:   "1024",
+			metadata: encodeUploadMetadata("channelId", fixture.RandID(), "filename", "../../../../tmp/pwned.txt", "filetype", "text/plain"),
+			status:   http.StatusBadRequest,
+		},
+		{
+			name:     "Filename with Windows directories",
+			length:   "1024",
+			metadata: encodeUploadMetadata("channelId", fixture.RandID(), "filename", `..\pwned.txt`, "filetype", "text/plain"),
+			status:   http.StatusBadRequest,
+		},
+		{
+			name:     "Filename too long",
+			length:   "1024",
+			metadata: encodeUploadMetadata("channelId", fixture.RandID(), "filename", strings.Repeat("a", 252)+".mp3", "filetype", "audio/mp3"),
+			status:   http.StatusBadRequest,
+		},
+		{
+			name:     "Invalid metadata encoding",
+			length:   "1024",
+			metadata: "channelId abc,filename ###,filetype ###",
+			status:   http.StatusBadRequest,
+		},
+		{
+			name:     "Unsupported file type",
+			length:   "1024",
+			metadata: encodeUploadMetadata("channelId", fixture.RandID(), "filename", "script.exe", "filetype", "application/x-msdownload"),
+			status:   http.StatusBadRequest,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			mockChannelService := new(mocks.ChannelService)
+			mockMessageService := new(mocks.MessageService)
+
+			rr := httptest.NewRecorder()
+
+			router := getAuthenticatedTestRouter(authUser.ID)
+
+			NewHandler(&Config{
+				R:              router,
+				ChannelService: mockChannelService,
+				MessageService: mockMessageService,
+			})
+
+			request, err := http.NewRequest(http.MethodPost, "/api/uploads", nil)
+			assert.NoError(t, err)
+			request.Header.Set("Tus-Resumable", model.TusVersion)
+			request.Header.Set("Upload-Length", tc.length)
+			request.Header.Set("Upload-Metadata", tc.metadata)
+
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, tc.status, rr.Code)
+
+			mockChannelService.AssertNotCalled(t, "Get", mock.Anything)
+			mockMessageService.AssertNotCalled(t, "CreateResumableUpload", mock.Anything)
+		})
+	}
+}
+
+func TestHandler_GetUploadOffset(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successfully returned offset", func(t *testing.T) {
+		upload := &model.Upload{
+			ID:        fixture.RandID(),
+			UserId:    authUser.ID,
+			Size:      1024,
+			Offset:    512,
+			Resumable: true,
+			ExpiresAt: time.Now().Add(model.UploadExpiration),
+		}
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("GetUpload", upload.ID).Return(upload, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			MessageService: mockMessageService,
+		})
+
+		request, err := http.NewRequest(http.MethodHead, "/api/uploads/"+upload.ID, nil)
+		assert.NoError(t, err)
+		request.Header.Set("Tus-Resumable", model.TusVersion)
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "512", rr.Header().Get("Upload-Offset"))
+		assert.Equal(t, "1024", rr.Header().Get("Upload-Length"))
+		assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+
+		mockMessageService.AssertExpectations(t)
+	})
+
+	t.Run("Upload of another user", func(t *testing.T) {
+		upload := &model.Upload{
+			ID:        fixture.RandID(),
+			UserId:    fixture.RandID(),
+			Size:      1024,
+			Resumable: true,
+		}
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("GetUpload", upload.ID).Return(upload, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			MessageService: mockMessageService,
+		})
+
+		request, err := http.NewRequest(http.MethodHead, "/api/uploads/"+upload.ID, nil)
+		assert.NoError(t, err)
+		request.Header.Set("Tus-Resumable", model.TusVersion)
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		mockMessageService.AssertExpectations(t)
+	})
+
+	t.Run("Presigned upload", func(t *testing.T) {
+		upload := &model.Upload{
+			ID:     fixture.RandID(),
+			UserId: authUser.ID,
+			Size:   1024,
+		}
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("GetUpload", upload.ID).Return(upload, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			MessageService: mockMessageService,
+		})
+
+		request, err := http.NewRequest(http.MethodHead, "/api/uploads/"+upload.ID, nil)
+		assert.NoError(t, err)
+		request.Header.Set("Tus-Resumable", model.TusVersion)
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		mockMessageService.AssertExpectations(t)
+	})
+}
+
+func TestHandler_AppendUploadChunk(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successfully appended chunk", func(t *testing.T) {
+		upload := &model.Upload{
+			ID:        fixture.RandID(),
+			UserId:    authUser.ID,
+			Size:      1024,
+			Offset:    512,
+			Resumable: true,
+		}
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("GetUpload", upload.ID).Return(upload, nil)
+		mockMessageService.
+			On("AppendUploadChunk", upload, mock.Anything).
+			Run(func(args mock.Arguments) {
+				args.Get(0).(*model.Upload).Offset += 5
+			}).
+			Return(nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			MessageService: mockMessageService,
+		})
+
+		request, err := http.NewRequest(http.MethodPatch, "/api/uploads/"+upload.ID, bytes.NewBufferString("Hello"))
+		assert.NoError(t, err)
+		request.Header.Set("Tus-Resumable", model.TusVersion)
+		request.Header.Set("Upload-Offset", "512")
+		request.Header.Set("Content-Type", "application/offset+octet-stream")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		assert.Equal(t, "517", rr.Header().Get("Upload-Offset"))
+
+		mockMessageService.AssertExpectations(t)
+	})
+
+	t.Run("Invalid content type", func(t *testing.T) {
+		mockMessageService := new(mocks.MessageService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			MessageService: mockMessageService,
+		})
+
+		request, err := http.NewRequest(http.MethodPatch, "/api/uploads/"+fixture.RandID(), bytes.NewBufferString("Hello"))
+		assert.NoError(t, err)
+		request.Header.Set("Tus-Resumable", model.TusVersion)
+		request.Header.Set("Upload-Offset", "0")
+		request.Header.Set("Content-Type", "application/octet-stream")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+
+		mockMessageService.AssertNotCalled(t, "GetUpload", mock.Anything)
+		mockMessageService.AssertNotCalled(t, "AppendUploadChunk", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Offset mismatch", func(t *testing.T) {
+		upload := &model.Upload{
+			ID:        fixture.RandID(),
+			UserId:    authUser.ID,
+			Size:      1024,
+			Offset:    512,
+			Resumable: true,
+		}
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("GetUpload", upload.ID).Return(upload, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			MessageService: mockMessageService,
+		})
+
+		request, err := http.NewRequest(http.MethodPatch, "/api/uploads/"+upload.ID, bytes.NewBufferString("Hello"))
+		assert.NoError(t, err)
+		request.Header.Set("Tus-Resumable", model.TusVersion)
+		request.Header.Set("Upload-Offset", "0")
+		request.Header.Set("Content-Type", "application/offset+octet-stream")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+
+		mockMessageService.AssertExpectations(t)
+		mockMessageService.AssertNotCalled(t, "AppendUploadChunk", mock.Anything, mock.Anything)
+	})
+}
+
+func TestHandler_TerminateUpload(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successfully terminated upload", func(t *testing.T) {
+		upload := &model.Upload{
+			ID:        fixture.RandID(),
+			UserId:    authUser.ID,
+			Size:      1024,
+			Resumable: true,
+		}
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("GetUpload", upload.ID).Return(upload, nil)
+		mockMessageService.On("TerminateUpload", upload).Return(nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			MessageService: mockMessageService,
+		})
+
+		request, err := http.NewRequest(http.MethodDelete, "/api/uploads/"+upload.ID, nil)
+		assert.NoError(t, err)
+		request.Header.Set("Tus-Resumable", model.TusVersion)
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+
+		mockMessageService.AssertExpectations(t)
+	})
+
+	t.Run("Upload of another user", func(t *testing.T) {
+		upload := &model.Upload{
+			ID:        fixture.RandID(),
+			UserId:    fixture.RandID(),
+			Size:      1024,
+			Resumable: true,
+		}
+
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("GetUpload", upload.ID).Return(upload, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			MessageService: mockMessageService,
+		})
+
+		request, err := http.NewRequest(http.MethodDelete, "/api/uploads/"+upload.ID, nil)
+		assert.NoError(t, err)
+		request.Header.Set("Tus-Resumable", model.TusVersion)
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		mockMessageService.AssertExpectations(t)
+		mockMessageService.AssertNotCalled(t, "TerminateUpload", mock.Anything)
+	})
+}
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"net/http"
+	"strings"
+)
+
+/*
+ * TwoFactorHandler contains all routes related to two-factor authentication (/api/account/two-factor)
+ */
+
+type twoFactorLoginReq struct {
+	// The ticket returned by the login.
+	Ticket string `json:"ticket"`
+	// A TOTP code or one of the recovery codes.
+	Code string `json:"code"`
+} //@name TwoFactorLoginRequest
+
+func (r twoFactorLoginReq) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Ticket, validation.Required),
+		validation.Field(&r.Code, validation.Required),
+	)
+}
+
+func (r *twoFactorLoginReq) sanitize() {
+	r.Ticket = strings.TrimSpace(r.Ticket)
+	r.Code = strings.TrimSpace(r.Code)
+}
+
+// TwoFactorLogin completes the login of an account with two-factor authentication
+// TwoFactorLogin godoc
+// @Tags Account
+// @Summary Two-Factor Login
+// @Accept  json
+// @Produce  json
+// @Param request body twoFactorLoginReq true "Two-Factor Login"
+// @Success 200 {object} model.User
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /account/two-factor/login [post]
+func (h *Handler) TwoFactorLogin(c *gin.Context) {
+	var req twoFactorLoginReq
+
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	req.sanitize()
+
+	user, err := h.userService.CompleteTwoFactorLogin(c.Request.Context(), req.Ticket, req.Code)
+
+	if err != nil {
+		switch err.Error() {
+		case apperrors.NewBadRequest(apperrors.InvalidLoginTicket).Error():
+			toFieldErrorResponse(c, "Ticket", apperrors.InvalidLoginTicket)
+		case apperrors.NewBadRequest(apperrors.InvalidTwoFactor).Error():
+			toFieldErrorResponse(c, "Code", apperrors.InvalidTwoFactor)
+		default:
+			c.JSON(apperrors.Status(err), gin.H{
+				"error": err,
+			})
+		}
+		return
+	}
+
+	h.setUserSession(c, user.ID)
+
+	c.JSON(http.StatusOK, user)
+}
+
+// EnableTwoFactor starts the enrollment of two-factor authentication
+// EnableTwoFactor godoc
+// @Tags Account
+// @Summary Enable Two-Factor Authentication
+// @Produce  json
+// @Success 200 {object} model.TwoFactorSetup
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /account/two-factor/enable [post]
+func (h *Handler) EnableTwoFactor(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+
+	authUser, err := h.userService.Get(userId)
+
+	if err != nil {
+		e := apperrors.NewAuthorization(apperrors.InvalidSession)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	setup, err := h.userService.EnableTwoFactor(authUser)
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, setup)
+}
+
+type twoFactorCodeReq struct {
+	// The current code of the authenticator app.
+	Code string `json:"code"`
+} //@name TwoFactorCodeRequest
+
+func (r twoFactorCodeReq) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Code, validation.Required),
+	)
+}
+
+func (r *twoFactorCodeReq) sanitize() {
+	r.Code = strings.TrimSpace(r.Code)
+}
+
+// ConfirmTwoFactor enables two-factor authentication once the user entered
+// a code of the new secret and returns the recovery codes
+// ConfirmTwoFactor godoc
+// @Tags Account
+// @Summary Confirm Two-Factor Authentication
+// @Accept  json
+// @Produce  json
+// @Param request body twoFactorCodeReq true "Confirm Two-Factor Authentication"
+// @Success 200 {object} model.RecoveryCodes
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /account/two-factor/confirm [post]
+func (h *Handler) ConfirmTwoFactor(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+	var req twoFactorCodeReq
+
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	req.sanitize()
+
+	authUser, err := h.userService.Get(userId)
+
+	if err != nil {
+		e := apperrors.NewAuthorization(apperrors.InvalidSession)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	codes, err := h.userService.ConfirmTwoFactor(authUser, req.Code)
+
+	if err != nil {
+		if err.Error() == apperrors.NewBadRequest(apperrors.InvalidTwoFactor).Error() {
+			toFieldErrorResponse(c, "Code", apperrors.InvalidTwoFactor)
+			return
+		}
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.RecoveryCodes{Codes: codes})
+}
+
+type disableTwoFactorReq struct {
+	Password string `json:"password"`
+} //@name DisableTwoFactorRequest
+
+func (r disableTwoFactorReq) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Password, validation.Required, validation.Length(6, 150)),
+	)
+}
+
+func (r *disableTwoFactorReq) sanitize() {
+	r.Password = strings.TrimSpace(r.Password)
+}
+
+// DisableTwoFactor disables two-factor authentication after the user re-entered their password
+// DisableTwoFactor godoc
+// @Tags Account
+// @Summary Disable Two-Factor Authentication
+// @Accept  json
+// @Produce  json
+// @Param request body disableTwoFactorReq true "Disable Two-Factor Authentication"
+// @Success 200 {object} model.Success
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /account/two-factor/disable [post]
+func (h *Handler) DisableTwoFactor(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+	var req disableTwoFactorReq
+
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	req.sanitize()
+
+	authUser, err := h.userService.Get(userId)
+
+	if err != nil {
+		e := apperrors.NewAuthorization(apperrors.InvalidSession)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	if err = h.userService.DisableTwoFactor(authUser, req.Password); err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, true)
+}
+
+// checkTwoFactor responds with an error and returns false if the user
+// has two-factor authentication enabled and the code is not valid
+func (h *Handler) checkTwoFactor(c *gin.Context, user *model.User, code string) bool {
+	if !user.TwoFactorEnabled {
+		return true
+	}
+
+	if err := h.userService.VerifyTwoFactor(user, code); err != nil {
+		if err.Error() == apperrors.NewBadRequest(apperrors.InvalidTwoFactor).Error() {
+			toFieldErrorResponse(c, "Code", apperrors.InvalidTwoFactor)
+			return false
+		}
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,374 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_TwoFactorLogin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Ticket and code required", func(t *testing.T) {
+		mockUserService := new(mocks.UserService)
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:           router,
+			UserService: mockUserService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, err := json.Marshal(gin.H{})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/api/account/two-factor/login", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockUserService.AssertNotCalled(t, "CompleteTwoFactorLogin", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid code", func(t *testing.T) {
+		mockUserService := new(mocks.UserService)
+		mockUserService.
+			On("CompleteTwoFactorLogin", mock.Anything, "ticket", "123456").
+			Return(nil, apperrors.NewBadRequest(apperrors.InvalidTwoFactor))
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:           router,
+			UserService: mockUserService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, err := json.Marshal(gin.H{
+			"ticket": "ticket",
+			"code":   "123456",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/api/account/two-factor/login", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(getTestFieldErrorResponse("Code", apperrors.InvalidTwoFactor))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("Expired ticket", func(t *testing.T) {
+		mockUserService := new(mocks.UserService)
+		mockUserService.
+			On("CompleteTwoFactorLogin", mock.Anything, "ticket", "123456").
+			Return(nil, apperrors.NewBadRequest(apperrors.InvalidLoginTicket))
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:           router,
+			UserService: mockUserService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, err := json.Marshal(gin.H{
+			"ticket": "ticket",
+			"code":   "123456",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/api/account/two-factor/login", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(getTestFieldErrorResponse("Ticket", apperrors.InvalidLoginTicket))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		user := fixture.GetMockUser()
+		user.TwoFactorEnabled = true
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.
+			On("CompleteTwoFactorLogin", mock.Anything, "ticket", "abcde-fghij").
+			Return(user, nil)
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:           router,
+			UserService: mockUserService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, err := json.Marshal(gin.H{
+			"ticket": "ticket",
+			"code":   "abcde-fghij",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/api/account/two-factor/login", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(user)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		assert.NotEmpty(t, rr.Header().Get("Set-Cookie"))
+		mockUserService.AssertExpectations(t)
+	})
+}
+
+func TestHandler_EnableTwoFactor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authUser := fixture.GetMockUser()
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		mockUserService := new(mocks.UserService)
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:           router,
+			UserService: mockUserService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		request, _ := http.NewRequest(http.MethodPost, "/api/account/two-factor/enable", nil)
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		mockUserService.AssertNotCalled(t, "EnableTwoFactor", mock.Anything)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		setup := &model.TwoFactorSetup{
+			Secret: "JBSWY3DPEHPK3PXP",
+			Uri:    "otpauth://totp/Valkyrie:test%40example.com?secret=JBSWY3DPEHPK3PXP",
+		}
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
+		mockUserService.On("EnableTwoFactor", authUser).Return(setup, nil)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:           router,
+			UserService: mockUserService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		request, _ := http.NewRequest(http.MethodPost, "/api/account/two-factor/enable", nil)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(setup)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockUserService.AssertExpectations(t)
+	})
+}
+
+func TestHandler_ConfirmTwoFactor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authUser := fixture.GetMockUser()
+
+	t.Run("Invalid code", func(t *testing.T) {
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
+		mockUserService.
+			On("ConfirmTwoFactor", authUser, "123456").
+			Return(nil, apperrors.NewBadRequest(apperrors.InvalidTwoFactor))
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:           router,
+			UserService: mockUserService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"code": "123456",
+		})
+
+		request, _ := http.NewRequest(http.MethodPost, "/api/account/two-factor/confirm", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(getTestFieldErrorResponse("Code", apperrors.InvalidTwoFactor))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		codes := []string{"abcde-fghij", "klmno-pqrst"}
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
+		mockUserService.On("ConfirmTwoFactor", authUser, "123456").Return(codes, nil)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:           router,
+			UserService: mockUserService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"code": " 123456 ",
+		})
+
+		request, _ := http.NewRequest(http.MethodPost, "/api/account/two-factor/confirm", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(model.RecoveryCodes{Codes: codes})
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockUserService.AssertExpectations(t)
+	})
+}
+
+func TestHandler_DisableTwoFactor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authUser := fixture.GetMockUser()
+
+	t.Run("Password required", func(t *testing.T) {
+		mockUserService := new(mocks.UserService)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:           router,
+			UserService: mockUserService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{})
+
+		request, _ := http.NewRequest(http.MethodPost, "/api/account/two-factor/disable", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockUserService.AssertNotCalled(t, "DisableTwoFactor", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Wrong password", func(t *testing.T) {
+		mockError := apperrors.NewAuthorization(apperrors.InvalidPassword)
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
+		mockUserService.On("DisableTwoFactor", authUser, "password").Return(mockError)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:           router,
+			UserService: mockUserService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"password": "password",
+		})
+
+		request, _ := http.NewRequest(http.MethodPost, "/api/account/two-factor/disable", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(gin.H{
+			"error": mockError,
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", authUser.ID).Return(authUser, nil)
+		mockUserService.On("DisableTwoFactor", authUser, "password").Return(nil)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:           router,
+			UserService: mockUserService,
+		})
+
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"password": "password",
+		})
+
+		request, _ := http.NewRequest(http.MethodPost, "/api/account/two-factor/disable", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(true)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockUserService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+/*
+ * UploadHandler contains all routes related to direct uploads (/api/messages)
+ */
+
+type uploadReq struct {
+	// Name of the file. 1 to 255 characters
+	Filename string `json:"filename"`
+	// One of the allowed file types
+	FileType string `json:"fileType"`
+	// Size of the file in bytes. At most 100 MiB
+	Size int64 `json:"size"`
+} //@name UploadRequest
+
+func (r uploadReq) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Filename, validation.Required, validation.Length(1, 255)),
+		validation.Field(&r.FileType, validation.Required),
+		validation.Field(&r.Size, validation.Required.Error(apperrors.UploadSizeError),
+			validation.Min(int64(1)).Error(apperrors.UploadSizeError),
+			validation.Max(int64(model.MaximumUploadSize)).Error(apperrors.UploadSizeError)),
+	)
+}
+
+func (r *uploadReq) sanitize() {
+	r.Filename = strings.TrimSpace(r.Filename)
+	r.FileType = strings.TrimSpace(r.FileType)
+}
+
+// CreateUpload creates an upload slot for a file that gets uploaded directly to the storage.
+// Send a message with the ID of the upload once the file got uploaded to the presigned url.
+// CreateUpload godoc
+// @Tags Messages
+// @Summary Create Upload
+// @Accepts  json
+// @Produce  json
+// @Param channelId path string true "Channel ID"
+// @Param request body uploadReq true "Create Upload"
+// @Success 201 {object} model.UploadResponse
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /messages/{channelId}/uploads [post]
+func (h *Handler) CreateUpload(c *gin.Context) {
+	channelId := c.Param("channelId")
+	userId := c.MustGet("userId").(string)
+
+	var req uploadReq
+	// Bind incoming json to struct and check for validation errors
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	req.sanitize()
+
+	fileType, ok := h.checkUploadType(c, "fileType", req.FileType, req.Size)
+
+	if !ok {
+		return
+	}
+
+	channel, ok := h.getUploadChannel(c, channelId, userId)
+
+	if !ok {
+		return
+	}
+
+	upload := model.Upload{
+		UserId:    userId,
+		ChannelId: channel.ID,
+		Filename:  req.Filename,
+		FileType:  fileType,
+		Size:      req.Size,
+	}
+
+	uploadUrl, err := h.messageService.CreateUpload(&upload)
+
+	if err != nil {
+		log.Printf("Failed to create upload: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.UploadResponse{
+		Id:        upload.ID,
+		UploadUrl: uploadUrl,
+		Method:    http.MethodPut,
+		Headers:   map[string]string{"Content-Type": upload.FileType},
+		ExpiresAt: time.Now().Add(model.UploadUrlExpiration),
+	})
+}
+
+// getUploadChannel returns the channel if the user is allowed to attach files in it.
+// Otherwise, it writes the error response and returns false.
+func (h *Handler) getUploadChannel(c *gin.Context, channelId, userId string) (*model.Channel, bool) {
+	channel, err := h.channelService.Get(channelId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("channel", channelId)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, false
+	}
+
+	// Check if the user has access to said channel
+	if err = h.channelService.IsChannelMember(channel, userId); err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return nil, false
+	}
+
+	// Check if the channel is read only for the user
+	if !h.channelService.HasPermission(userId, channel, model.PermissionSendMessages) {
+		e := apperrors.NewAuthorization(apperrors.SendMessagesError)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, false
+	}
+
+	if !h.channelService.HasPermission(userId, channel, model.PermissionAttachFiles) {
+		e := apperrors.NewAuthorization(apperrors.AttachFilesError)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return nil, false
+	}
+
+	return channel, true
+}
@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_CreateUpload(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+
+	t.Run("Successfully created upload", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel("")
+		uploadUrl := "https://imageurl.com/upload?signature=abcde"
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(true)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionAttachFiles).Return(true)
+
+		params := &model.Upload{
+			UserId:    authUser.ID,
+			ChannelId: mockChannel.ID,
+			Filename:  "video.mp4",
+			FileType:  "video/mp4",
+			Size:      50 << 20,
+		}
+
+		uploadId := fixture.RandID()
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.
+			On("CreateUpload", params).
+			Run(func(args mock.Arguments) {
+				args.Get(0).(*model.Upload).ID = uploadId
+			}).
+			Return(uploadUrl, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"filename": " video.mp4 ",
+			"fileType": "video/mp4",
+			"size":     50 << 20,
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/messages/%s/uploads", mockChannel.ID), bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		var response model.UploadResponse
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, uploadId, response.Id)
+		assert.Equal(t, uploadUrl, response.UploadUrl)
+		assert.Equal(t, http.MethodPut, response.Method)
+		assert.Equal(t, map[string]string{"Content-Type": "video/mp4"}, response.Headers)
+
+		mockChannelService.AssertExpectations(t)
+		mockMessageService.AssertExpectations(t)
+	})
+
+	t.Run("Not allowed to attach files", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel("")
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(nil)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionSendMessages).Return(true)
+		mockChannelService.On("HasPermission", authUser.ID, mockChannel, model.PermissionAttachFiles).Return(false)
+
+		mockMessageService := new(mocks.MessageService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"filename": "audio.mp3",
+			"fileType": "audio/mp3",
+			"size":     1024,
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/messages/%s/uploads", mockChannel.ID), bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		mockError := apperrors.NewAuthorization(apperrors.AttachFilesError)
+		respBody, err := json.Marshal(gin.H{
+			"error": mockError,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+
+		mockMessageService.AssertNotCalled(t, "CreateUpload", mock.Anything)
+	})
+
+	t.Run("Not a member of the channel", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel("")
+		mockError := apperrors.NewNotFound("channel", mockChannel.ID)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("IsChannelMember", mockChannel, authUser.ID).Return(mockError)
+
+		mockMessageService := new(mocks.MessageService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"filename": "audio.mp3",
+			"fileType": "audio/mp3",
+			"size":     1024,
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/messages/%s/uploads", mockChannel.ID), bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, mockError.Status(), rr.Code)
+		mockMessageService.AssertNotCalled(t, "CreateUpload", mock.Anything)
+	})
+
+	t.Run("File exceeds the size limit of its type", func(t *testing.T) {
+		mockChannelService := new(mocks.ChannelService)
+		mockMessageService := new(mocks.MessageService)
+
+		rr := httptest.NewRecorder()
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			MessageService: mockMessageService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"filename": "image.png",
+			"fileType": "image/png",
+			"size":     50 << 20,
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/messages/%s/uploads", fixture.RandID()), bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+
+		mockChannelService.AssertNotCalled(t, "Get", mock.Anything)
+		mockMessageService.AssertNotCalled(t, "CreateUpload", mock.Anything)
+	})
+}
+
+func TestHandler_CreateUpload_BadRequest(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	authUser := fixture.GetMockUser()
+	channelId := fixture.RandID()
+
+	mockChannelService := new(mocks.ChannelService)
+	mockMessageService := new(mocks.MessageService)
+
+	router := getAuthenticatedTestRouter(authUser.ID)
+
+	NewHandler(&Config{
+		R:              router,
+		ChannelService: mockChannelService,
+		MessageService: mockMessageService,
+	})
+
+	testCases := []struct {
+		name   string
+		body   gin.H
+		field  string
+		reason string
+	}{
+		{
+			name:   "Filename required",
+			body:   gin.H{"fileType": "image/png", "size": 1024},
+			field:  "filename",
+			reason: "cannot be blank.",
+		},
+		{
+			name:   "Empty file",
+			body:   gin.H{"filename": "image.png", "fileType": "image/png", "size": 0},
+			field:  "size",
+			reason: apperrors.UploadSizeError + ".",
+		},
+		{
+			name:   "File too large",
+			body:   gin.H{"filename": "image.png", "fileType": "image/png", "size": model.MaximumUploadSize + 1},
+			field:  "size",
+			reason: apperrors.UploadSizeError + ".",
+		},
+		{
+			name:   "Disallowed mimetype",
+			body:   gin.H{"filename": "setup.exe", "fileType": "application/x-msdownload", "size": 1024},
+			field:  "fileType",
+			reason: apperrors.InvalidFileType,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			reqBody, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/messages/%s/uploads", channelId), bytes.NewBuffer(reqBody))
+			assert.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(rr, request)
+
+			respBody, err := json.Marshal(getTestFieldErrorResponse(tc.field, tc.reason))
+			assert.NoError(t, err)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.Equal(t, respBody, rr.Body.Bytes())
+
+			mockChannelService.AssertNotCalled(t, "Get", mock.Anything)
+			mockMessageService.AssertNotCalled(t, "CreateUpload", mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,414 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+/*
+ * WebhookHandler contains all routes related to the incoming webhooks of
+ * guild channels (/api/channels) and their execution (/api/webhooks)
+ */
+
+// GetWebhooks returns the webhooks of the given channel
+// GetWebhooks godoc
+// @Tags Webhooks
+// @Summary Get Channel Webhooks
+// @Produce  json
+// @Param channelId path string true "Channel ID"
+// @Success 200 {array} model.Webhook
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /channels/{channelId}/webhooks [get]
+func (h *Handler) GetWebhooks(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+
+	channel, ok := h.getManageableChannel(c, userId, apperrors.WebhookChannelError)
+
+	if !ok {
+		return
+	}
+
+	webhooks, err := h.webhookService.GetWebhooks(channel.ID)
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	// If the channel does not have any webhooks, return an empty array
+	if len(*webhooks) == 0 {
+		empty := make([]model.Webhook, 0)
+		c.JSON(http.StatusOK, empty)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+type createWebhookReq struct {
+	// 1 to 80 characters
+	Name string `json:"name"`
+} //@name CreateWebhookRequest
+
+func (r createWebhookReq) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Name, validation.Required, validation.Length(1, 80)),
+	)
+}
+
+func (r *createWebhookReq) sanitize() {
+	r.Name = strings.TrimSpace(r.Name)
+}
+
+// CreateWebhook creates a webhook for the given channel.
+// The webhook token is only returned in this response.
+// CreateWebhook godoc
+// @Tags Webhooks
+// @Summary Create Webhook
+// @Accept  json
+// @Produce  json
+// @Param channelId path string true "Channel ID"
+// @Param request body createWebhookReq true "Create Webhook"
+// @Success 201 {object} model.CreatedWebhook
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /channels/{channelId}/webhooks [post]
+func (h *Handler) CreateWebhook(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+	var req createWebhookReq
+
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	req.sanitize()
+
+	channel, ok := h.getManageableChannel(c, userId, apperrors.WebhookChannelError)
+
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(channel, userId, req.Name)
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhook)
+}
+
+type editWebhookReq struct {
+	// 1 to 80 characters
+	Name string `form:"name"`
+	// image/png or image/jpeg. Keeps the current image if not provided
+	Image *multipart.FileHeader `form:"image" swaggertype:"string" format:"binary"`
+} //@name EditWebhookRequest
+
+func (r editWebhookReq) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Name, validation.Required, validation.Length(1, 80)),
+	)
+}
+
+func (r *editWebhookReq) sanitize() {
+	r.Name = strings.TrimSpace(r.Name)
+}
+
+// EditWebhook changes the name and the image of the given webhook.
+// Its past messages show the new name and image as well.
+// EditWebhook godoc
+// @Tags Webhooks
+// @Summary Edit Webhook
+// @Accepts  mpfd
+// @Produce  json
+// @Param channelId path string true "Channel ID"
+// @Param webhookId path string true "Webhook ID"
+// @Param request body editWebhookReq true "Edit Webhook"
+// @Success 200 {object} model.Webhook
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /channels/{channelId}/webhooks/{webhookId} [put]
+func (h *Handler) EditWebhook(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.MaxBodyBytes)
+
+	var req editWebhookReq
+
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	req.sanitize()
+
+	webhook, ok := h.getManageableWebhook(c, userId)
+
+	if !ok {
+		return
+	}
+
+	webhook.Name = req.Name
+
+	if req.Image != nil {
+		// Validate image mime-type, size and content are allowable
+		if ok := h.checkFile(c, "Image", req.Image, true); !ok {
+			return
+		}
+
+		directory := fmt.Sprintf("valkyrie/webhooks/%s", webhook.ID)
+		url, err := h.userService.ChangeAvatar(req.Image, directory)
+
+		if err != nil {
+			c.JSON(apperrors.Status(err), gin.H{
+				"error": err,
+			})
+			return
+		}
+
+		_ = h.userService.DeleteImage(webhook.Image)
+
+		webhook.Image = url
+	}
+
+	if err := h.webhookService.UpdateWebhook(webhook); err != nil {
+		log.Printf("Failed to update webhook: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// ResetWebhookToken revokes the token of the given webhook and returns a new one
+// ResetWebhookToken godoc
+// @Tags Webhooks
+// @Summary Reset Webhook Token
+// @Produce  json
+// @Param channelId path string true "Channel ID"
+// @Param webhookId path string true "Webhook ID"
+// @Success 200 {object} model.CreatedWebhook
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /channels/{channelId}/webhooks/{webhookId}/token [post]
+func (h *Handler) ResetWebhookToken(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+
+	webhook, ok := h.getManageableWebhook(c, userId)
+
+	if !ok {
+		return
+	}
+
+	created, err := h.webhookService.ResetWebhookToken(webhook)
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, created)
+}
+
+// DeleteWebhook deletes the given webhook together with its messages
+// DeleteWebhook godoc
+// @Tags Webhooks
+// @Summary Delete Webhook
+// @Produce  json
+// @Param channelId path string true "Channel ID"
+// @Param webhookId path string true "Webhook ID"
+// @Success 200 {object} model.Success
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /channels/{channelId}/webhooks/{webhookId} [delete]
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	userId := c.MustGet("userId").(string)
+
+	webhook, ok := h.getManageableWebhook(c, userId)
+
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(webhook); err != nil {
+		log.Printf("Failed to delete webhook: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, true)
+}
+
+// getManageableWebhook returns the webhook of the webhookId param if it belongs to
+// the channel of the id param and the user is allowed to manage that channel.
+// Otherwise, it writes the error response and returns false.
+func (h *Handler) getManageableWebhook(c *gin.Context, userId string) (*model.Webhook, bool) {
+	channel, ok := h.getManageableChannel(c, userId, apperrors.WebhookChannelError)
+
+	if !ok {
+		return nil, false
+	}
+
+	webhook, err := h.webhookService.GetWebhook(channel.ID, c.Param("webhookId"))
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return nil, false
+	}
+
+	return webhook, true
+}
+
+// executeWebhookReq contains the message the webhook posts.
+// Either text or files must be provided
+type executeWebhookReq struct {
+	// Maximum 2000 characters
+	Text *string `json:"text" form:"text"`
+	// One of the allowed file types. Only available for multipart requests. Repeat the field for up to 10 files
+	Files []*multipart.FileHeader `json:"-" form:"file" swaggertype:"array,string" format:"binary"`
+} //@name ExecuteWebhookRequest
+
+func (r executeWebhookReq) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Text,
+			validation.NilOrNotEmpty,
+			validation.Required.When(len(r.Files) == 0).Error(apperrors.MessageOrFileRequired),
+			validation.Length(1, 2000),
+		),
+		validation.Field(&r.Files, validation.Length(0, model.MaximumAttachments).
+			Error(apperrors.AttachmentLimitError)),
+	)
+}
+
+func (r *executeWebhookReq) sanitize() {
+	if r.Text != nil {
+		text := strings.TrimSpace(*r.Text)
+		r.Text = &text
+	}
+}
+
+// ExecuteWebhook posts a message into the channel of the webhook.
+// The webhook token authenticates the request, so it does not need a user session.
+// ExecuteWebhook godoc
+// @Tags Webhooks
+// @Summary Execute Webhook
+// @Accepts  json,mpfd
+// @Produce  json
+// @Param webhookId path string true "Webhook ID"
+// @Param token path string true "Webhook Token"
+// @Param request body executeWebhookReq true "Execute Webhook"
+// @Success 201 {object} model.Success
+// @Failure 400 {object} model.ErrorsResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /webhooks/{webhookId}/{token} [post]
+func (h *Handler) ExecuteWebhook(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.MaxBodyBytes)
+
+	var req executeWebhookReq
+
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	req.sanitize()
+
+	webhook, err := h.webhookService.Authenticate(c.Param("id"), c.Param("token"))
+
+	if err != nil {
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	channel, err := h.channelService.Get(webhook.ChannelId)
+
+	if err != nil {
+		e := apperrors.NewNotFound("channel", webhook.ChannelId)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	author, err := h.userService.Get(webhook.ID)
+
+	if err != nil {
+		e := apperrors.NewNotFound("user", webhook.ID)
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	params := model.Message{
+		UserId:    webhook.ID,
+		ChannelId: channel.ID,
+		Text:      req.Text,
+	}
+
+	for _, file := range req.Files {
+		if ok := h.checkFile(c, "File", file, false); !ok {
+			return
+		}
+	}
+
+	for i, file := range req.Files {
+		attachment, err := h.uploadAttachment(file, channel.ID)
+
+		if err != nil {
+			c.JSON(apperrors.Status(err), gin.H{
+				"error": err,
+			})
+			return
+		}
+
+		attachment.Position = i
+		params.Attachments = append(params.Attachments, *attachment)
+	}
+
+	message, err := h.messageService.CreateMessage(&params)
+
+	if err != nil {
+		log.Printf("Failed to create message: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	h.publishMessage(channel, author, message)
+
+	c.JSON(http.StatusCreated, true)
+}
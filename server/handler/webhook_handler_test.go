@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_CreateWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authUser := fixture.GetMockUser()
+
+	createWebhook := func(router *gin.Engine, channelId string, body gin.H) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(body)
+
+		request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/channels/%s/webhooks", channelId), bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		return rr
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+		mockWebhook, _ := fixture.GetMockWebhook(mockChannel)
+		created := &model.CreatedWebhook{Webhook: *mockWebhook, Token: "token"}
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("HasPermission", authUser.ID, *mockChannel.GuildID, model.PermissionManageChannels).Return(true)
+
+		mockWebhookService := new(mocks.WebhookService)
+		mockWebhookService.On("CreateWebhook", mockChannel, authUser.ID, mockWebhook.Name).Return(created, nil)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			GuildService:   mockGuildService,
+			WebhookService: mockWebhookService,
+		})
+
+		rr := createWebhook(router, mockChannel.ID, gin.H{"name": " " + mockWebhook.Name + " "})
+
+		respBody, _ := json.Marshal(created)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		assert.Contains(t, rr.Body.String(), `"token":"token"`)
+		assert.NotContains(t, rr.Body.String(), mockWebhook.TokenHash)
+		mockWebhookService.AssertExpectations(t)
+	})
+
+	t.Run("DM channel", func(t *testing.T) {
+		mockChannel := fixture.GetMockDMChannel()
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+
+		mockWebhookService := new(mocks.WebhookService)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			WebhookService: mockWebhookService,
+		})
+
+		rr := createWebhook(router, mockChannel.ID, gin.H{"name": "CI"})
+
+		respBody, _ := json.Marshal(gin.H{
+			"error": apperrors.NewBadRequest(apperrors.WebhookChannelError),
+		})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockWebhookService.AssertNotCalled(t, "CreateWebhook", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Missing permissions", func(t *testing.T) {
+		mockChannel := fixture.GetMockChannel(fixture.RandID())
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("HasPermission", authUser.ID, *mockChannel.GuildID, model.PermissionManageChannels).Return(false)
+
+		mockWebhookService := new(mocks.WebhookService)
+
+		router := getAuthenticatedTestRouter(authUser.ID)
+
+		NewHandler(&Config{
+			R:              router,
+			ChannelService: mockChannelService,
+			GuildService:   mockGuildService,
+			WebhookService: mockWebhookService,
+		})
+
+		rr := createWebhook(router, mockChannel.ID, gin.H{"name": "CI"})
+
+		respBody, _ := json.Marshal(gin.H{
+			"error": apperrors.NewAuthorization(apperrors.MissingPermissions),
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockWebhookService.AssertNotCalled(t, "CreateWebhook", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestHandler_DeleteWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authUser := fixture.GetMockUser()
+	mockChannel := fixture.GetMockChannel(fixture.RandID())
+	mockWebhook, _ := fixture.GetMockWebhook(mockChannel)
+
+	mockChannelService := new(mocks.ChannelService)
+	mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+
+	mockGuildService := new(mocks.GuildService)
+	mockGuildService.On("HasPermission", authUser.ID, *mockChannel.GuildID, model.PermissionManageChannels).Return(true)
+
+	mockWebhookService := new(mocks.WebhookService)
+	mockWebhookService.On("GetWebhook", mockChannel.ID, mockWebhook.ID).Return(mockWebhook, nil)
+	mockWebhookService.On("DeleteWebhook", mockWebhook).Return(nil)
+
+	router := getAuthenticatedTestRouter(authUser.ID)
+
+	NewHandler(&Config{
+		R:              router,
+		ChannelService: mockChannelService,
+		GuildService:   mockGuildService,
+		WebhookService: mockWebhookService,
+	})
+
+	rr := httptest.NewRecorder()
+
+	reqUrl := fmt.Sprintf("/api/channels/%s/webhooks/%s", mockChannel.ID, mockWebhook.ID)
+	request, _ := http.NewRequest(http.MethodDelete, reqUrl, nil)
+
+	router.ServeHTTP(rr, request)
+
+	respBody, _ := json.Marshal(true)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, respBody, rr.Body.Bytes())
+	mockWebhookService.AssertExpectations(t)
+}
+
+func TestHandler_ExecuteWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockChannel := fixture.GetMockChannel(fixture.RandID())
+	mockWebhook, mockUser := fixture.GetMockWebhook(mockChannel)
+	token := "token"
+
+	execute := func(router *gin.Engine, body gin.H) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(body)
+
+		request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/webhooks/%s/%s", mockWebhook.ID, token), bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		return rr
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockMessage := fixture.GetMockMessage(mockWebhook.ID, mockChannel.ID)
+
+		mockWebhookService := new(mocks.WebhookService)
+		mockWebhookService.On("Authenticate", mockWebhook.ID, token).Return(mockWebhook, nil)
+
+		mockChannelService := new(mocks.ChannelService)
+		mockChannelService.On("Get", mockChannel.ID).Return(mockChannel, nil)
+		mockChannelService.On("UpdateChannel", mockChannel).Return(nil)
+
+		mockUserService := new(mocks.UserService)
+		mockUserService.On("Get", mockWebhook.ID).Return(mockUser, nil)
+
+		params := model.Message{
+			UserId:    mockWebhook.ID,
+			ChannelId: mockChannel.ID,
+			Text:      mockMessage.Text,
+		}
+		mockMessageService := new(mocks.MessageService)
+		mockMessageService.On("CreateMessage", &params).Return(mockMessage, nil)
+
+		// Webhooks are not members of the guild
+		mockGuildService := new(mocks.GuildService)
+		mockGuildService.On("GetMemberSettings", mockWebhook.ID, *mockChannel.GuildID).Return(nil, apperrors.NewNotFound("member", mockWebhook.ID))
+
+		response := model.MessageResponse{
+			Id:          mockMessage.ID,
+			Text:        mockMessage.Text,
+			CreatedAt:   mockMessage.CreatedAt,
+			UpdatedAt:   mockMessage.UpdatedAt,
+			Attachments: make([]model.Attachment, 0),
+			User: model.MemberResponse{
+				Id:        mockUser.ID,
+				Username:  mockWebhook.Name,
+				Image:     mockWebhook.Image,
+				CreatedAt: mockUser.CreatedAt,
+				UpdatedAt: mockUser.UpdatedAt,
+				Bot:       true,
+			},
+			Reactions: make([]model.ReactionResponse, 0),
+			Mentions:  model.NewMessageMentions(),
+		}
+
+		mockSocketService := new(mocks.SocketService)
+		mockSocketService.On("EmitNewMessage", mockChannel.ID, &response).Return()
+		mockSocketService.On("EmitNewNotification", *mockChannel.GuildID, mockChannel.ID).Return()
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:              router,
+			WebhookService: mockWebhookService,
+			ChannelService: mockChannelService,
+			UserService:    mockUserService,
+			MessageService: mockMessageService,
+			GuildService:   mockGuildService,
+			SocketService:  mockSocketService,
+			MaxBodyBytes:   4 * 1024 * 1024,
+		})
+
+		rr := execute(router, gin.H{"text": *mockMessage.Text})
+
+		respBody, _ := json.Marshal(true)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockMessageService.AssertExpectations(t)
+		mockSocketService.AssertExpectations(t)
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		mockWebhookService := new(mocks.WebhookService)
+		mockWebhookService.
+			On("Authenticate", mockWebhook.ID, token).
+			Return(nil, apperrors.NewAuthorization(apperrors.InvalidWebhookToken))
+
+		mockMessageService := new(mocks.MessageService)
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:              router,
+			WebhookService: mockWebhookService,
+			MessageService: mockMessageService,
+			MaxBodyBytes:   4 * 1024 * 1024,
+		})
+
+		rr := execute(router, gin.H{"text": "Build passed"})
+
+		respBody, _ := json.Marshal(gin.H{
+			"error": apperrors.NewAuthorization(apperrors.InvalidWebhookToken),
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockMessageService.AssertNotCalled(t, "CreateMessage", mock.Anything)
+	})
+
+	t.Run("Missing text", func(t *testing.T) {
+		mockWebhookService := new(mocks.WebhookService)
+
+		router := getTestRouter()
+
+		NewHandler(&Config{
+			R:              router,
+			WebhookService: mockWebhookService,
+			MaxBodyBytes:   4 * 1024 * 1024,
+		})
+
+		rr := execute(router, gin.H{})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), apperrors.MessageOrFileRequired)
+		mockWebhookService.AssertNotCalled(t, "Authenticate", mock.Anything, mock.Anything)
+	})
+}
@@ -18,6 +18,7 @@ import (
 	sredis "github.com/ulule/limiter/v3/drivers/store/redis"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -30,11 +31,38 @@ func inject(d *dataSources, cfg config.Config) (*gin.Engine, error) {
 	guildRepository := repository.NewGuildRepository(d.DB)
 	channelRepository := repository.NewChannelRepository(d.DB)
 	messageRepository := repository.NewMessageRepository(d.DB)
+	accessTokenRepository := repository.NewAccessTokenRepository(d.DB)
+	commandRepository := repository.NewCommandRepository(d.DB)
+	webhookRepository := repository.NewWebhookRepository(d.DB)
+
+	var fileRepository model.FileRepository
+	var fileServer model.FileServer
+
+	if cfg.StorageDriver == model.StorageDriverLocal {
+		fileRepository = repository.NewLocalFileRepository(cfg.StoragePath, cfg.PublicUrl, cfg.SessionSecret)
+		fileServer = repository.NewLocalFileServer(cfg.StoragePath, cfg.SessionSecret)
+	} else {
+		fileRepository = repository.NewS3FileRepository(d.S3Session, cfg.BucketName, cfg.PublicUrl)
+	}
 
-	fileRepository := repository.NewFileRepository(d.S3Session, cfg.BucketName)
 	redisRepository := repository.NewRedisRepository(d.RedisClient)
 
-	mailRepository := repository.NewMailRepository(cfg.GmailUser, cfg.GmailPassword, cfg.CorsOrigin)
+	// Fall back to the deprecated Gmail credentials
+	if cfg.SMTPUsername == "" {
+		cfg.SMTPUsername, cfg.SMTPPassword = cfg.GmailUser, cfg.GmailPassword
+	}
+
+	if cfg.MailFrom == "" {
+		cfg.MailFrom = cfg.SMTPUsername
+	}
+
+	mailTransport, err := newMailTransport(cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mailRepository := repository.NewMailRepository(mailTransport, cfg.MailFrom, cfg.CorsOrigin)
 
 	// Service Layer
 	userService := service.NewUserService(&service.USConfig{
@@ -44,6 +72,25 @@ func inject(d *dataSources, cfg config.Config) (*gin.Engine, error) {
 		MailRepository:  mailRepository,
 	})
 
+	sessionService := service.NewSessionService(&service.SeSConfig{
+		RedisRepository: redisRepository,
+	})
+
+	tokenService := service.NewAccessTokenService(&service.ATSConfig{
+		AccessTokenRepository: accessTokenRepository,
+	})
+
+	botService := service.NewBotService(&service.BSConfig{
+		UserRepository:        userRepository,
+		AccessTokenRepository: accessTokenRepository,
+	})
+
+	interactionService := service.NewInteractionService(&service.ISConfig{
+		CommandRepository: commandRepository,
+		RedisRepository:   redisRepository,
+		HttpClient:        service.NewInteractionHttpClient(),
+	})
+
 	friendService := service.NewFriendService(&service.FSConfig{
 		UserRepository:   userRepository,
 		FriendRepository: friendRepository,
@@ -65,6 +112,11 @@ func inject(d *dataSources, cfg config.Config) (*gin.Engine, error) {
 	messageService := service.NewMessageService(&service.MSConfig{
 		MessageRepository: messageRepository,
 		FileRepository:    fileRepository,
+		KeepOriginals:     strings.Split(cfg.KeepOriginals, ","),
+	})
+
+	webhookService := service.NewWebhookService(&service.WHSConfig{
+		WebhookRepository: webhookRepository,
 	})
 
 	// initialize gin.Engine
@@ -74,7 +126,16 @@ func inject(d *dataSources, cfg config.Config) (*gin.Engine, error) {
 	c := cors.New(cors.Options{
 		AllowedOrigins:   []string{cfg.CorsOrigin},
 		AllowCredentials: true,
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "HEAD", "DELETE"},
+		// Headers of the tus resumable upload protocol
+		AllowedHeaders: []string{
+			"Origin", "Accept", "Content-Type", "X-Requested-With",
+			"Tus-Resumable", "Upload-Length", "Upload-Metadata", "Upload-Offset",
+		},
+		ExposedHeaders: []string{
+			"Location", "Tus-Resumable", "Tus-Version", "Tus-Extension", "Tus-Max-Size",
+			"Upload-Offset", "Upload-Length", "Upload-Expires",
+		},
 	})
 	router.Use(c)
 
@@ -90,7 +151,7 @@ func inject(d *dataSources, cfg config.Config) (*gin.Engine, error) {
 
 	store.Options(sessions.Options{
 		Domain:   cfg.Domain,
-		MaxAge:   60 * 60 * 24 * 7, // 7 days
+		MaxAge:   model.SessionMaxAge,
 		Secure:   gin.Mode() == gin.ReleaseMode,
 		HttpOnly: true,
 		Path:     "/",
@@ -119,7 +180,9 @@ func inject(d *dataSources, cfg config.Config) (*gin.Engine, error) {
 	})
 	go hub.Run()
 
-	router.GET("/ws", middleware.AuthUser(), func(c *gin.Context) {
+	router.GET("/ws", middleware.AuthUser(sessionService, tokenService, &middleware.TokenScopes{
+		Read: model.ScopeReadMessages,
+	}), func(c *gin.Context) {
 		ws.ServeWs(hub, c)
 	})
 
@@ -129,17 +192,102 @@ func inject(d *dataSources, cfg config.Config) (*gin.Engine, error) {
 		ChannelRepository: channelRepository,
 	})
 
+	// Archive inactive threads in the background
+	go archiveInactiveThreads(channelService, socketService)
+
+	// Delete uploads that did not get attached in the background
+	go deleteExpiredUploads(messageService)
+
+	fileTypes, err := model.ParseFileTypes(cfg.FileTypes)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the allowed file types: %w", err)
+	}
+
+	restrictions, err := model.ParseUnverifiedRestrictions(cfg.Restrictions)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the unverified restrictions: %w", err)
+	}
+
 	handler.NewHandler(&handler.Config{
-		R:               router,
-		UserService:     userService,
-		FriendService:   friendService,
-		GuildService:    guildService,
-		ChannelService:  channelService,
-		MessageService:  messageService,
-		SocketService:   socketService,
-		TimeoutDuration: time.Duration(cfg.HandlerTimeOut) * time.Second,
-		MaxBodyBytes:    cfg.MaxBodyBytes,
+		R:                  router,
+		UserService:        userService,
+		SessionService:     sessionService,
+		TokenService:       tokenService,
+		BotService:         botService,
+		InteractionService: interactionService,
+		FriendService:      friendService,
+		GuildService:       guildService,
+		ChannelService:     channelService,
+		MessageService:     messageService,
+		WebhookService:     webhookService,
+		SocketService:      socketService,
+		FileServer:         fileServer,
+		FileTypes:          fileTypes,
+		Restrictions:       restrictions,
+		TimeoutDuration:    time.Duration(cfg.HandlerTimeOut) * time.Second,
+		MaxBodyBytes:       cfg.MaxBodyBytes,
 	})
 
 	return router, nil
 }
+
+// archiveInactiveThreads periodically archives the threads that did not have
+// any recent activity and emits the changes to their members
+func archiveInactiveThreads(channelService model.ChannelService, socketService model.SocketService) {
+	ticker := time.NewTicker(model.ThreadArchiveInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		threads, err := channelService.ArchiveInactiveThreads()
+
+		if err != nil {
+			log.Printf("error archiving threads: %v\n", err)
+			continue
+		}
+
+		for _, thread := range *threads {
+			response := thread.SerializeThread()
+			socketService.EmitEditThread(&response)
+		}
+	}
+}
+
+// deleteExpiredUploads periodically deletes uploads that did not get attached to a message in time
+func deleteExpiredUploads(messageService model.MessageService) {
+	ticker := time.NewTicker(model.UploadCleanupInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := messageService.DeleteExpiredUploads(); err != nil {
+			log.Printf("error deleting expired uploads: %v\n", err)
+		}
+	}
+}
+
+// newMailTransport returns the transport of the configured mail driver
+func newMailTransport(cfg config.Config) (model.MailTransport, error) {
+	switch cfg.MailDriver {
+	case model.MailDriverSMTP:
+		switch cfg.SMTPSecurity {
+		case model.SMTPSecurityStartTLS, model.SMTPSecurityTLS, model.SMTPSecurityNone:
+		default:
+			return nil, fmt.Errorf("unknown smtp security: %s", cfg.SMTPSecurity)
+		}
+
+		return repository.NewSMTPTransport(&repository.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Security: cfg.SMTPSecurity,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+		}), nil
+	case model.MailDriverFile:
+		return repository.NewFileTransport(cfg.MailPath), nil
+	case model.MailDriverLog:
+		return repository.NewLogTransport(), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver: %s", cfg.MailDriver)
+	}
+}
@@ -0,0 +1,150 @@
+// Code generated by mockery v2.12.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/sentrionic/valkyrie/model"
+	mock "github.com/stretchr/testify/mock"
+
+	testing "testing"
+
+	time "time"
+)
+
+// AccessTokenRepository is an autogenerated mock type for the AccessTokenRepository type
+type AccessTokenRepository struct {
+	mock.Mock
+}
+
+// CountByUser provides a mock function with given fields: userId
+func (_m *AccessTokenRepository) CountByUser(userId string) (int64, error) {
+	ret := _m.Called(userId)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(string) int64); ok {
+		r0 = rf(userId)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: token
+func (_m *AccessTokenRepository) Create(token *model.AccessToken) error {
+	ret := _m.Called(token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.AccessToken) error); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: userId, id
+func (_m *AccessTokenRepository) Delete(userId string, id string) error {
+	ret := _m.Called(userId, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(userId, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteByUser provides a mock function with given fields: userId
+func (_m *AccessTokenRepository) DeleteByUser(userId string) error {
+	ret := _m.Called(userId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindByHash provides a mock function with given fields: hash
+func (_m *AccessTokenRepository) FindByHash(hash string) (*model.AccessToken, error) {
+	ret := _m.Called(hash)
+
+	var r0 *model.AccessToken
+	if rf, ok := ret.Get(0).(func(string) *model.AccessToken); ok {
+		r0 = rf(hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AccessToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByUser provides a mock function with given fields: userId
+func (_m *AccessTokenRepository) FindByUser(userId string) (*[]model.AccessToken, error) {
+	ret := _m.Called(userId)
+
+	var r0 *[]model.AccessToken
+	if rf, ok := ret.Get(0).(func(string) *[]model.AccessToken); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.AccessToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateLastUsed provides a mock function with given fields: id, lastUsedAt
+func (_m *AccessTokenRepository) UpdateLastUsed(id string, lastUsedAt time.Time) error {
+	ret := _m.Called(id, lastUsedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) error); ok {
+		r0 = rf(id, lastUsedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAccessTokenRepository creates a new instance of AccessTokenRepository. It also registers the testing.TB interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAccessTokenRepository(t testing.TB) *AccessTokenRepository {
+	mock := &AccessTokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
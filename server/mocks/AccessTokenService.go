@@ -0,0 +1,110 @@
+// Code generated by mockery v2.12.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/sentrionic/valkyrie/model"
+	mock "github.com/stretchr/testify/mock"
+
+	testing "testing"
+
+	time "time"
+)
+
+// AccessTokenService is an autogenerated mock type for the AccessTokenService type
+type AccessTokenService struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: token
+func (_m *AccessTokenService) Authenticate(token string) (*model.AccessToken, error) {
+	ret := _m.Called(token)
+
+	var r0 *model.AccessToken
+	if rf, ok := ret.Get(0).(func(string) *model.AccessToken); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AccessToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAccessToken provides a mock function with given fields: userId, name, scopes, expiresAt
+func (_m *AccessTokenService) CreateAccessToken(userId string, name string, scopes []string, expiresAt *time.Time) (*model.CreatedAccessToken, error) {
+	ret := _m.Called(userId, name, scopes, expiresAt)
+
+	var r0 *model.CreatedAccessToken
+	if rf, ok := ret.Get(0).(func(string, string, []string, *time.Time) *model.CreatedAccessToken); ok {
+		r0 = rf(userId, name, scopes, expiresAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.CreatedAccessToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, []string, *time.Time) error); ok {
+		r1 = rf(userId, name, scopes, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteAccessToken provides a mock function with given fields: userId, id
+func (_m *AccessTokenService) DeleteAccessToken(userId string, id string) error {
+	ret := _m.Called(userId, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(userId, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAccessTokens provides a mock function with given fields: userId
+func (_m *AccessTokenService) GetAccessTokens(userId string) (*[]model.AccessToken, error) {
+	ret := _m.Called(userId)
+
+	var r0 *[]model.AccessToken
+	if rf, ok := ret.Get(0).(func(string) *[]model.AccessToken); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.AccessToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAccessTokenService creates a new instance of AccessTokenService. It also registers the testing.TB interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAccessTokenService(t testing.TB) *AccessTokenService {
+	mock := &AccessTokenService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,173 @@
+// Code generated by mockery v2.12.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/sentrionic/valkyrie/model"
+	mock "github.com/stretchr/testify/mock"
+
+	testing "testing"
+)
+
+// BotService is an autogenerated mock type for the BotService type
+type BotService struct {
+	mock.Mock
+}
+
+// CreateBot provides a mock function with given fields: owner, username
+func (_m *BotService) CreateBot(owner *model.User, username string) (*model.User, string, error) {
+	ret := _m.Called(owner, username)
+
+	var r0 *model.User
+	if rf, ok := ret.Get(0).(func(*model.User, string) *model.User); ok {
+		r0 = rf(owner, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(*model.User, string) string); ok {
+		r1 = rf(owner, username)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(*model.User, string) error); ok {
+		r2 = rf(owner, username)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// DeleteBot provides a mock function with given fields: bot
+func (_m *BotService) DeleteBot(bot *model.User) error {
+	ret := _m.Called(bot)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.User) error); ok {
+		r0 = rf(bot)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetBot provides a mock function with given fields: ownerId, botId
+func (_m *BotService) GetBot(ownerId string, botId string) (*model.User, error) {
+	ret := _m.Called(ownerId, botId)
+
+	var r0 *model.User
+	if rf, ok := ret.Get(0).(func(string, string) *model.User); ok {
+		r0 = rf(ownerId, botId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(ownerId, botId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBotById provides a mock function with given fields: botId
+func (_m *BotService) GetBotById(botId string) (*model.User, error) {
+	ret := _m.Called(botId)
+
+	var r0 *model.User
+	if rf, ok := ret.Get(0).(func(string) *model.User); ok {
+		r0 = rf(botId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(botId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBots provides a mock function with given fields: ownerId
+func (_m *BotService) GetBots(ownerId string) (*[]model.User, error) {
+	ret := _m.Called(ownerId)
+
+	var r0 *[]model.User
+	if rf, ok := ret.Get(0).(func(string) *[]model.User); ok {
+		r0 = rf(ownerId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(ownerId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetBotToken provides a mock function with given fields: bot
+func (_m *BotService) ResetBotToken(bot *model.User) (string, error) {
+	ret := _m.Called(bot)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(*model.User) string); ok {
+		r0 = rf(bot)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.User) error); ok {
+		r1 = rf(bot)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateBot provides a mock function with given fields: bot
+func (_m *BotService) UpdateBot(bot *model.User) error {
+	ret := _m.Called(bot)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.User) error); ok {
+		r0 = rf(bot)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewBotService creates a new instance of BotService. It also registers the testing.TB interface on the mock and a cleanup function to assert the mocks expectations.
+func NewBotService(t testing.TB) *BotService {
+	mock := &BotService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
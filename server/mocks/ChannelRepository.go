@@ -42,6 +42,43 @@ func (_m *ChannelRepository) AddPrivateChannelMembers(memberIds []string, channe
 	return r0
 }
 
+// AddThreadMember provides a mock function with given fields: member
+func (_m *ChannelRepository) AddThreadMember(member *model.ThreadMember) error {
+	ret := _m.Called(member)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.ThreadMember) error); ok {
+		r0 = rf(member)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ArchiveInactiveThreads provides a mock function with given fields:
+func (_m *ChannelRepository) ArchiveInactiveThreads() (*[]model.Channel, error) {
+	ret := _m.Called()
+
+	var r0 *[]model.Channel
+	if rf, ok := ret.Get(0).(func() *[]model.Channel); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.Channel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CleanPCMembers provides a mock function with given fields: channelId
 func (_m *ChannelRepository) CleanPCMembers(channelId string) error {
 	ret := _m.Called(channelId)
@@ -93,6 +130,20 @@ func (_m *ChannelRepository) DeleteChannel(channel *model.Channel) error {
 	return r0
 }
 
+// DeletePermissionOverwrite provides a mock function with given fields: channelId, targetId
+func (_m *ChannelRepository) DeletePermissionOverwrite(channelId string, targetId string) error {
+	ret := _m.Called(channelId, targetId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(channelId, targetId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindDMByUserAndChannelId provides a mock function with given fields: channelId, userId
 func (_m *ChannelRepository) FindDMByUserAndChannelId(channelId string, userId string) (string, error) {
 	ret := _m.Called(channelId, userId)
@@ -160,6 +211,29 @@ func (_m *ChannelRepository) GetById(channelId string) (*model.Channel, error) {
 	return r0, r1
 }
 
+// GetDMChannelIds provides a mock function with given fields: userId
+func (_m *ChannelRepository) GetDMChannelIds(userId string) ([]string, error) {
+	ret := _m.Called(userId)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDMMemberIds provides a mock function with given fields: channelId
 func (_m *ChannelRepository) GetDMMemberIds(channelId string) (*[]string, error) {
 	ret := _m.Called(channelId)
@@ -229,6 +303,29 @@ func (_m *ChannelRepository) GetDirectMessages(userId string) (*[]model.DirectMe
 	return r0, r1
 }
 
+// GetGuildChannels provides a mock function with given fields: guildId
+func (_m *ChannelRepository) GetGuildChannels(guildId string) (*[]model.Channel, error) {
+	ret := _m.Called(guildId)
+
+	var r0 *[]model.Channel
+	if rf, ok := ret.Get(0).(func(string) *[]model.Channel); ok {
+		r0 = rf(guildId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.Channel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(guildId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetGuildDefault provides a mock function with given fields: guildId
 func (_m *ChannelRepository) GetGuildDefault(guildId string) (*model.Channel, error) {
 	ret := _m.Called(guildId)
@@ -252,6 +349,75 @@ func (_m *ChannelRepository) GetGuildDefault(guildId string) (*model.Channel, er
 	return r0, r1
 }
 
+// GetGuildMemberPermissionOverwrites provides a mock function with given fields: userId, guildId
+func (_m *ChannelRepository) GetGuildMemberPermissionOverwrites(userId string, guildId string) (map[string][]model.PermissionOverwrite, error) {
+	ret := _m.Called(userId, guildId)
+
+	var r0 map[string][]model.PermissionOverwrite
+	if rf, ok := ret.Get(0).(func(string, string) map[string][]model.PermissionOverwrite); ok {
+		r0 = rf(userId, guildId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]model.PermissionOverwrite)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(userId, guildId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMemberPermissionOverwrites provides a mock function with given fields: userId, guildId, channelId
+func (_m *ChannelRepository) GetMemberPermissionOverwrites(userId string, guildId string, channelId string) (*[]model.PermissionOverwrite, error) {
+	ret := _m.Called(userId, guildId, channelId)
+
+	var r0 *[]model.PermissionOverwrite
+	if rf, ok := ret.Get(0).(func(string, string, string) *[]model.PermissionOverwrite); ok {
+		r0 = rf(userId, guildId, channelId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.PermissionOverwrite)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(userId, guildId, channelId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPermissionOverwrites provides a mock function with given fields: channelId
+func (_m *ChannelRepository) GetPermissionOverwrites(channelId string) (*[]model.PermissionOverwrite, error) {
+	ret := _m.Called(channelId)
+
+	var r0 *[]model.PermissionOverwrite
+	if rf, ok := ret.Get(0).(func(string) *[]model.PermissionOverwrite); ok {
+		r0 = rf(channelId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.PermissionOverwrite)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(channelId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPrivateChannelMembers provides a mock function with given fields: channelId
 func (_m *ChannelRepository) GetPrivateChannelMembers(channelId string) (*[]string, error) {
 	ret := _m.Called(channelId)
@@ -275,6 +441,75 @@ func (_m *ChannelRepository) GetPrivateChannelMembers(channelId string) (*[]stri
 	return r0, r1
 }
 
+// GetThreadByMessageId provides a mock function with given fields: messageId
+func (_m *ChannelRepository) GetThreadByMessageId(messageId string) (*model.Channel, error) {
+	ret := _m.Called(messageId)
+
+	var r0 *model.Channel
+	if rf, ok := ret.Get(0).(func(string) *model.Channel); ok {
+		r0 = rf(messageId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Channel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(messageId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetThreadMembers provides a mock function with given fields: threadId
+func (_m *ChannelRepository) GetThreadMembers(threadId string) (*[]string, error) {
+	ret := _m.Called(threadId)
+
+	var r0 *[]string
+	if rf, ok := ret.Get(0).(func(string) *[]string); ok {
+		r0 = rf(threadId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(threadId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetThreads provides a mock function with given fields: channelId, archived
+func (_m *ChannelRepository) GetThreads(channelId string, archived bool) (*[]model.ThreadResponse, error) {
+	ret := _m.Called(channelId, archived)
+
+	var r0 *[]model.ThreadResponse
+	if rf, ok := ret.Get(0).(func(string, bool) *[]model.ThreadResponse); ok {
+		r0 = rf(channelId, archived)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.ThreadResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, bool) error); ok {
+		r1 = rf(channelId, archived)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // OpenDMForAll provides a mock function with given fields: dmId
 func (_m *ChannelRepository) OpenDMForAll(dmId string) error {
 	ret := _m.Called(dmId)
@@ -303,6 +538,34 @@ func (_m *ChannelRepository) RemovePrivateChannelMembers(memberIds []string, cha
 	return r0
 }
 
+// RemoveThreadMember provides a mock function with given fields: threadId, userId
+func (_m *ChannelRepository) RemoveThreadMember(threadId string, userId string) error {
+	ret := _m.Called(threadId, userId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(threadId, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SavePermissionOverwrite provides a mock function with given fields: overwrite
+func (_m *ChannelRepository) SavePermissionOverwrite(overwrite *model.PermissionOverwrite) error {
+	ret := _m.Called(overwrite)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.PermissionOverwrite) error); ok {
+		r0 = rf(overwrite)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SetDirectMessageStatus provides a mock function with given fields: dmId, userId, isOpen
 func (_m *ChannelRepository) SetDirectMessageStatus(dmId string, userId string, isOpen bool) error {
 	ret := _m.Called(dmId, userId, isOpen)
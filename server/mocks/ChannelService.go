@@ -42,6 +42,43 @@ func (_m *ChannelService) AddPrivateChannelMembers(memberIds []string, channelId
 	return r0
 }
 
+// AddThreadMember provides a mock function with given fields: threadId, userId
+func (_m *ChannelService) AddThreadMember(threadId string, userId string) error {
+	ret := _m.Called(threadId, userId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(threadId, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ArchiveInactiveThreads provides a mock function with given fields:
+func (_m *ChannelService) ArchiveInactiveThreads() (*[]model.Channel, error) {
+	ret := _m.Called()
+
+	var r0 *[]model.Channel
+	if rf, ok := ret.Get(0).(func() *[]model.Channel); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.Channel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CleanPCMembers provides a mock function with given fields: channelId
 func (_m *ChannelService) CleanPCMembers(channelId string) error {
 	ret := _m.Called(channelId)
@@ -79,6 +116,29 @@ func (_m *ChannelService) CreateChannel(channel *model.Channel) (*model.Channel,
 	return r0, r1
 }
 
+// CreateThread provides a mock function with given fields: thread
+func (_m *ChannelService) CreateThread(thread *model.Channel) (*model.Channel, error) {
+	ret := _m.Called(thread)
+
+	var r0 *model.Channel
+	if rf, ok := ret.Get(0).(func(*model.Channel) *model.Channel); ok {
+		r0 = rf(thread)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Channel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.Channel) error); ok {
+		r1 = rf(thread)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteChannel provides a mock function with given fields: channel
 func (_m *ChannelService) DeleteChannel(channel *model.Channel) error {
 	ret := _m.Called(channel)
@@ -93,6 +153,20 @@ func (_m *ChannelService) DeleteChannel(channel *model.Channel) error {
 	return r0
 }
 
+// DeletePermissionOverwrite provides a mock function with given fields: channelId, targetId
+func (_m *ChannelService) DeletePermissionOverwrite(channelId string, targetId string) error {
+	ret := _m.Called(channelId, targetId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(channelId, targetId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Get provides a mock function with given fields: channelId
 func (_m *ChannelService) Get(channelId string) (*model.Channel, error) {
 	ret := _m.Called(channelId)
@@ -116,6 +190,29 @@ func (_m *ChannelService) Get(channelId string) (*model.Channel, error) {
 	return r0, r1
 }
 
+// GetAccessibleChannelIds provides a mock function with given fields: userId, guildId
+func (_m *ChannelService) GetAccessibleChannelIds(userId string, guildId string) ([]string, error) {
+	ret := _m.Called(userId, guildId)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string, string) []string); ok {
+		r0 = rf(userId, guildId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(userId, guildId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetChannels provides a mock function with given fields: userId, guildId
 func (_m *ChannelService) GetChannels(userId string, guildId string) (*[]model.ChannelResponse, error) {
 	ret := _m.Called(userId, guildId)
@@ -160,6 +257,29 @@ func (_m *ChannelService) GetDMByUserAndChannel(userId string, channelId string)
 	return r0, r1
 }
 
+// GetDMChannelIds provides a mock function with given fields: userId
+func (_m *ChannelService) GetDMChannelIds(userId string) ([]string, error) {
+	ret := _m.Called(userId)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDirectMessageChannel provides a mock function with given fields: userId, memberId
 func (_m *ChannelService) GetDirectMessageChannel(userId string, memberId string) (*string, error) {
 	ret := _m.Called(userId, memberId)
@@ -206,6 +326,29 @@ func (_m *ChannelService) GetDirectMessages(userId string) (*[]model.DirectMessa
 	return r0, r1
 }
 
+// GetPermissionOverwrites provides a mock function with given fields: channelId
+func (_m *ChannelService) GetPermissionOverwrites(channelId string) (*[]model.PermissionOverwrite, error) {
+	ret := _m.Called(channelId)
+
+	var r0 *[]model.PermissionOverwrite
+	if rf, ok := ret.Get(0).(func(string) *[]model.PermissionOverwrite); ok {
+		r0 = rf(channelId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.PermissionOverwrite)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(channelId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPrivateChannelMembers provides a mock function with given fields: channelId
 func (_m *ChannelService) GetPrivateChannelMembers(channelId string) (*[]string, error) {
 	ret := _m.Called(channelId)
@@ -229,6 +372,89 @@ func (_m *ChannelService) GetPrivateChannelMembers(channelId string) (*[]string,
 	return r0, r1
 }
 
+// GetThreadByMessage provides a mock function with given fields: messageId
+func (_m *ChannelService) GetThreadByMessage(messageId string) (*model.Channel, error) {
+	ret := _m.Called(messageId)
+
+	var r0 *model.Channel
+	if rf, ok := ret.Get(0).(func(string) *model.Channel); ok {
+		r0 = rf(messageId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Channel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(messageId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetThreadMembers provides a mock function with given fields: threadId
+func (_m *ChannelService) GetThreadMembers(threadId string) (*[]string, error) {
+	ret := _m.Called(threadId)
+
+	var r0 *[]string
+	if rf, ok := ret.Get(0).(func(string) *[]string); ok {
+		r0 = rf(threadId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(threadId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetThreads provides a mock function with given fields: channelId, archived
+func (_m *ChannelService) GetThreads(channelId string, archived bool) (*[]model.ThreadResponse, error) {
+	ret := _m.Called(channelId, archived)
+
+	var r0 *[]model.ThreadResponse
+	if rf, ok := ret.Get(0).(func(string, bool) *[]model.ThreadResponse); ok {
+		r0 = rf(channelId, archived)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.ThreadResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, bool) error); ok {
+		r1 = rf(channelId, archived)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HasPermission provides a mock function with given fields: userId, channel, permission
+func (_m *ChannelService) HasPermission(userId string, channel *model.Channel, permission model.Permission) bool {
+	ret := _m.Called(userId, channel, permission)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, *model.Channel, model.Permission) bool); ok {
+		r0 = rf(userId, channel, permission)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // IsChannelMember provides a mock function with given fields: channel, userId
 func (_m *ChannelService) IsChannelMember(channel *model.Channel, userId string) error {
 	ret := _m.Called(channel, userId)
@@ -271,6 +497,20 @@ func (_m *ChannelService) RemovePrivateChannelMembers(memberIds []string, channe
 	return r0
 }
 
+// RemoveThreadMember provides a mock function with given fields: threadId, userId
+func (_m *ChannelService) RemoveThreadMember(threadId string, userId string) error {
+	ret := _m.Called(threadId, userId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(threadId, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SetDirectMessageStatus provides a mock function with given fields: dmId, userId, isOpen
 func (_m *ChannelService) SetDirectMessageStatus(dmId string, userId string, isOpen bool) error {
 	ret := _m.Called(dmId, userId, isOpen)
@@ -285,6 +525,20 @@ func (_m *ChannelService) SetDirectMessageStatus(dmId string, userId string, isO
 	return r0
 }
 
+// SetPermissionOverwrite provides a mock function with given fields: overwrite
+func (_m *ChannelService) SetPermissionOverwrite(overwrite *model.PermissionOverwrite) error {
+	ret := _m.Called(overwrite)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.PermissionOverwrite) error); ok {
+		r0 = rf(overwrite)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateChannel provides a mock function with given fields: channel
 func (_m *ChannelService) UpdateChannel(channel *model.Channel) error {
 	ret := _m.Called(channel)
@@ -0,0 +1,180 @@
+// Code generated by mockery v2.12.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/sentrionic/valkyrie/model"
+	mock "github.com/stretchr/testify/mock"
+
+	testing "testing"
+)
+
+// CommandRepository is an autogenerated mock type for the CommandRepository type
+type CommandRepository struct {
+	mock.Mock
+}
+
+// CountByBot provides a mock function with given fields: botId
+func (_m *CommandRepository) CountByBot(botId string) (int64, error) {
+	ret := _m.Called(botId)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(string) int64); ok {
+		r0 = rf(botId)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(botId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: command
+func (_m *CommandRepository) Create(command *model.Command) error {
+	ret := _m.Called(command)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Command) error); ok {
+		r0 = rf(command)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: commandId
+func (_m *CommandRepository) Delete(commandId string) error {
+	ret := _m.Called(commandId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(commandId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindByBot provides a mock function with given fields: botId
+func (_m *CommandRepository) FindByBot(botId string) (*[]model.Command, error) {
+	ret := _m.Called(botId)
+
+	var r0 *[]model.Command
+	if rf, ok := ret.Get(0).(func(string) *[]model.Command); ok {
+		r0 = rf(botId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.Command)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(botId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByGuild provides a mock function with given fields: guildId
+func (_m *CommandRepository) FindByGuild(guildId string) (*[]model.Command, error) {
+	ret := _m.Called(guildId)
+
+	var r0 *[]model.Command
+	if rf, ok := ret.Get(0).(func(string) *[]model.Command); ok {
+		r0 = rf(guildId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.Command)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(guildId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByID provides a mock function with given fields: id
+func (_m *CommandRepository) FindByID(id string) (*model.Command, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.Command
+	if rf, ok := ret.Get(0).(func(string) *model.Command); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Command)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByName provides a mock function with given fields: botId, guildId, name
+func (_m *CommandRepository) FindByName(botId string, guildId *string, name string) (*model.Command, error) {
+	ret := _m.Called(botId, guildId, name)
+
+	var r0 *model.Command
+	if rf, ok := ret.Get(0).(func(string, *string, string) *model.Command); ok {
+		r0 = rf(botId, guildId, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Command)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *string, string) error); ok {
+		r1 = rf(botId, guildId, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: command
+func (_m *CommandRepository) Update(command *model.Command) error {
+	ret := _m.Called(command)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Command) error); ok {
+		r0 = rf(command)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCommandRepository creates a new instance of CommandRepository. It also registers the testing.TB interface on the mock and a cleanup function to assert the mocks expectations.
+func NewCommandRepository(t testing.TB) *CommandRepository {
+	mock := &CommandRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
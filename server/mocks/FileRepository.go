@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	io "io"
+
 	mock "github.com/stretchr/testify/mock"
 
 	multipart "mime/multipart"
@@ -15,13 +17,13 @@ type FileRepository struct {
 	mock.Mock
 }
 
-// DeleteImage provides a mock function with given fields: key
-func (_m *FileRepository) DeleteImage(key string) error {
-	ret := _m.Called(key)
+// DeleteImage provides a mock function with given fields: url
+func (_m *FileRepository) DeleteImage(url string) error {
+	ret := _m.Called(url)
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(key)
+		r0 = rf(url)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -29,6 +31,106 @@ func (_m *FileRepository) DeleteImage(key string) error {
 	return r0
 }
 
+// ImageUrl provides a mock function with given fields: path, size
+func (_m *FileRepository) ImageUrl(path string, size int) (string, error) {
+	ret := _m.Called(path, size)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, int) string); ok {
+		r0 = rf(path, size)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(path, size)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MergeChunks provides a mock function with given fields: urls, directory, filename, mimetype
+func (_m *FileRepository) MergeChunks(urls []string, directory string, filename string, mimetype string) (string, error) {
+	ret := _m.Called(urls, directory, filename, mimetype)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func([]string, string, string, string) string); ok {
+		r0 = rf(urls, directory, filename, mimetype)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string, string, string, string) error); ok {
+		r1 = rf(urls, directory, filename, mimetype)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OpenFile provides a mock function with given fields: url
+func (_m *FileRepository) OpenFile(url string) (io.ReadCloser, int64, error) {
+	ret := _m.Called(url)
+
+	var r0 io.ReadCloser
+	if rf, ok := ret.Get(0).(func(string) io.ReadCloser); ok {
+		r0 = rf(url)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(string) int64); ok {
+		r1 = rf(url)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(url)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// PresignUpload provides a mock function with given fields: directory, filename, mimetype, size
+func (_m *FileRepository) PresignUpload(directory string, filename string, mimetype string, size int64) (string, string, error) {
+	ret := _m.Called(directory, filename, mimetype, size)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string, string, int64) string); ok {
+		r0 = rf(directory, filename, mimetype, size)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(string, string, string, int64) string); ok {
+		r1 = rf(directory, filename, mimetype, size)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, string, string, int64) error); ok {
+		r2 = rf(directory, filename, mimetype, size)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // UploadAvatar provides a mock function with given fields: header, directory
 func (_m *FileRepository) UploadAvatar(header *multipart.FileHeader, directory string) (string, error) {
 	ret := _m.Called(header, directory)
@@ -50,20 +152,69 @@ func (_m *FileRepository) UploadAvatar(header *multipart.FileHeader, directory s
 	return r0, r1
 }
 
-// UploadFile provides a mock function with given fields: header, directory, filename, mimetype
-func (_m *FileRepository) UploadFile(header *multipart.FileHeader, directory string, filename string, mimetype string) (string, error) {
-	ret := _m.Called(header, directory, filename, mimetype)
+// UploadChunk provides a mock function with given fields: directory, filename, chunk
+func (_m *FileRepository) UploadChunk(directory string, filename string, chunk io.Reader) (string, int64, error) {
+	ret := _m.Called(directory, filename, chunk)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string, io.Reader) string); ok {
+		r0 = rf(directory, filename, chunk)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(string, string, io.Reader) int64); ok {
+		r1 = rf(directory, filename, chunk)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, string, io.Reader) error); ok {
+		r2 = rf(directory, filename, chunk)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// UploadFile provides a mock function with given fields: file, directory, filename, mimetype
+func (_m *FileRepository) UploadFile(file io.Reader, directory string, filename string, mimetype string) (string, error) {
+	ret := _m.Called(file, directory, filename, mimetype)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(io.Reader, string, string, string) string); ok {
+		r0 = rf(file, directory, filename, mimetype)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(io.Reader, string, string, string) error); ok {
+		r1 = rf(file, directory, filename, mimetype)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UploadThumbnail provides a mock function with given fields: file, directory, filename
+func (_m *FileRepository) UploadThumbnail(file io.Reader, directory string, filename string) (string, error) {
+	ret := _m.Called(file, directory, filename)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(*multipart.FileHeader, string, string, string) string); ok {
-		r0 = rf(header, directory, filename, mimetype)
+	if rf, ok := ret.Get(0).(func(io.Reader, string, string) string); ok {
+		r0 = rf(file, directory, filename)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*multipart.FileHeader, string, string, string) error); ok {
-		r1 = rf(header, directory, filename, mimetype)
+	if rf, ok := ret.Get(1).(func(io.Reader, string, string) error); ok {
+		r1 = rf(file, directory, filename)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,72 @@
+// Code generated by mockery v2.12.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+
+	os "os"
+
+	testing "testing"
+)
+
+// FileServer is an autogenerated mock type for the FileServer type
+type FileServer struct {
+	mock.Mock
+}
+
+// Open provides a mock function with given fields: key, signature
+func (_m *FileServer) Open(key string, signature string) (*os.File, string, error) {
+	ret := _m.Called(key, signature)
+
+	var r0 *os.File
+	if rf, ok := ret.Get(0).(func(string, string) *os.File); ok {
+		r0 = rf(key, signature)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*os.File)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(string, string) string); ok {
+		r1 = rf(key, signature)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, string) error); ok {
+		r2 = rf(key, signature)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Put provides a mock function with given fields: key, mimetype, size, expires, signature, body
+func (_m *FileServer) Put(key string, mimetype string, size int64, expires int64, signature string, body io.Reader) error {
+	ret := _m.Called(key, mimetype, size, expires, signature, body)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int64, int64, string, io.Reader) error); ok {
+		r0 = rf(key, mimetype, size, expires, signature, body)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewFileServer creates a new instance of FileServer. It also registers the testing.TB interface on the mock and a cleanup function to assert the mocks expectations.
+func NewFileServer(t testing.TB) *FileServer {
+	mock := &FileServer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
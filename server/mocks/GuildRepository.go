@@ -14,6 +14,20 @@ type GuildRepository struct {
 	mock.Mock
 }
 
+// AddMemberRole provides a mock function with given fields: memberRole
+func (_m *GuildRepository) AddMemberRole(memberRole *model.MemberRole) error {
+	ret := _m.Called(memberRole)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.MemberRole) error); ok {
+		r0 = rf(memberRole)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: guild
 func (_m *GuildRepository) Create(guild *model.Guild) (*model.Guild, error) {
 	ret := _m.Called(guild)
@@ -37,6 +51,29 @@ func (_m *GuildRepository) Create(guild *model.Guild) (*model.Guild, error) {
 	return r0, r1
 }
 
+// CreateRole provides a mock function with given fields: role
+func (_m *GuildRepository) CreateRole(role *model.Role) (*model.Role, error) {
+	ret := _m.Called(role)
+
+	var r0 *model.Role
+	if rf, ok := ret.Get(0).(func(*model.Role) *model.Role); ok {
+		r0 = rf(role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Role)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.Role) error); ok {
+		r1 = rf(role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: guildId
 func (_m *GuildRepository) Delete(guildId string) error {
 	ret := _m.Called(guildId)
@@ -51,6 +88,20 @@ func (_m *GuildRepository) Delete(guildId string) error {
 	return r0
 }
 
+// DeleteRole provides a mock function with given fields: roleId
+func (_m *GuildRepository) DeleteRole(roleId string) error {
+	ret := _m.Called(roleId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(roleId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindByID provides a mock function with given fields: id
 func (_m *GuildRepository) FindByID(id string) (*model.Guild, error) {
 	ret := _m.Called(id)
@@ -74,6 +125,29 @@ func (_m *GuildRepository) FindByID(id string) (*model.Guild, error) {
 	return r0, r1
 }
 
+// FindRoleByID provides a mock function with given fields: roleId
+func (_m *GuildRepository) FindRoleByID(roleId string) (*model.Role, error) {
+	ret := _m.Called(roleId)
+
+	var r0 *model.Role
+	if rf, ok := ret.Get(0).(func(string) *model.Role); ok {
+		r0 = rf(roleId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Role)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(roleId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindUserByID provides a mock function with given fields: uid
 func (_m *GuildRepository) FindUserByID(uid string) (*model.User, error) {
 	ret := _m.Called(uid)
@@ -189,6 +263,48 @@ func (_m *GuildRepository) GetMemberIds(guildId string) (*[]string, error) {
 	return r0, r1
 }
 
+// GetMemberPermissions provides a mock function with given fields: userId, guildId
+func (_m *GuildRepository) GetMemberPermissions(userId string, guildId string) (model.Permission, error) {
+	ret := _m.Called(userId, guildId)
+
+	var r0 model.Permission
+	if rf, ok := ret.Get(0).(func(string, string) model.Permission); ok {
+		r0 = rf(userId, guildId)
+	} else {
+		r0 = ret.Get(0).(model.Permission)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(userId, guildId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMemberPosition provides a mock function with given fields: userId, guildId
+func (_m *GuildRepository) GetMemberPosition(userId string, guildId string) (int, error) {
+	ret := _m.Called(userId, guildId)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string, string) int); ok {
+		r0 = rf(userId, guildId)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(userId, guildId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMemberSettings provides a mock function with given fields: userId, guildId
 func (_m *GuildRepository) GetMemberSettings(userId string, guildId string) (*model.MemberSettings, error) {
 	ret := _m.Called(userId, guildId)
@@ -212,6 +328,29 @@ func (_m *GuildRepository) GetMemberSettings(userId string, guildId string) (*mo
 	return r0, r1
 }
 
+// GetRoles provides a mock function with given fields: guildId
+func (_m *GuildRepository) GetRoles(guildId string) (*[]model.RoleResponse, error) {
+	ret := _m.Called(guildId)
+
+	var r0 *[]model.RoleResponse
+	if rf, ok := ret.Get(0).(func(string) *[]model.RoleResponse); ok {
+		r0 = rf(guildId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.RoleResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(guildId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetVCMember provides a mock function with given fields: userId, guildId
 func (_m *GuildRepository) GetVCMember(userId string, guildId string) (*model.VCMember, error) {
 	ret := _m.Called(userId, guildId)
@@ -295,6 +434,20 @@ func (_m *GuildRepository) RemoveMember(userId string, guildId string) error {
 	return r0
 }
 
+// RemoveMemberRole provides a mock function with given fields: memberRole
+func (_m *GuildRepository) RemoveMemberRole(memberRole *model.MemberRole) error {
+	ret := _m.Called(memberRole)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.MemberRole) error); ok {
+		r0 = rf(memberRole)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RemoveVCMember provides a mock function with given fields: userId, guildId
 func (_m *GuildRepository) RemoveVCMember(userId string, guildId string) error {
 	ret := _m.Called(userId, guildId)
@@ -323,6 +476,20 @@ func (_m *GuildRepository) Save(guild *model.Guild) error {
 	return r0
 }
 
+// SaveRole provides a mock function with given fields: role
+func (_m *GuildRepository) SaveRole(role *model.Role) error {
+	ret := _m.Called(role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Role) error); ok {
+		r0 = rf(role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UnbanMember provides a mock function with given fields: userId, guildId
 func (_m *GuildRepository) UnbanMember(userId string, guildId string) error {
 	ret := _m.Called(userId, guildId)
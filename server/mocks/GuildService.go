@@ -16,6 +16,20 @@ type GuildService struct {
 	mock.Mock
 }
 
+// AddMemberRole provides a mock function with given fields: userId, guildId, roleId
+func (_m *GuildService) AddMemberRole(userId string, guildId string, roleId string) error {
+	ret := _m.Called(userId, guildId, roleId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(userId, guildId, roleId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateGuild provides a mock function with given fields: guild
 func (_m *GuildService) CreateGuild(guild *model.Guild) (*model.Guild, error) {
 	ret := _m.Called(guild)
@@ -39,6 +53,29 @@ func (_m *GuildService) CreateGuild(guild *model.Guild) (*model.Guild, error) {
 	return r0, r1
 }
 
+// CreateRole provides a mock function with given fields: role
+func (_m *GuildService) CreateRole(role *model.Role) (*model.Role, error) {
+	ret := _m.Called(role)
+
+	var r0 *model.Role
+	if rf, ok := ret.Get(0).(func(*model.Role) *model.Role); ok {
+		r0 = rf(role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Role)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.Role) error); ok {
+		r1 = rf(role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteGuild provides a mock function with given fields: guildId
 func (_m *GuildService) DeleteGuild(guildId string) error {
 	ret := _m.Called(guildId)
@@ -53,6 +90,20 @@ func (_m *GuildService) DeleteGuild(guildId string) error {
 	return r0
 }
 
+// DeleteRole provides a mock function with given fields: roleId
+func (_m *GuildService) DeleteRole(roleId string) error {
+	ret := _m.Called(roleId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(roleId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindUsersByIds provides a mock function with given fields: ids, guildId
 func (_m *GuildService) FindUsersByIds(ids []string, guildId string) (*[]model.User, error) {
 	ret := _m.Called(ids, guildId)
@@ -233,6 +284,52 @@ func (_m *GuildService) GetMemberSettings(userId string, guildId string) (*model
 	return r0, r1
 }
 
+// GetRole provides a mock function with given fields: roleId
+func (_m *GuildService) GetRole(roleId string) (*model.Role, error) {
+	ret := _m.Called(roleId)
+
+	var r0 *model.Role
+	if rf, ok := ret.Get(0).(func(string) *model.Role); ok {
+		r0 = rf(roleId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Role)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(roleId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRoles provides a mock function with given fields: guildId
+func (_m *GuildService) GetRoles(guildId string) (*[]model.RoleResponse, error) {
+	ret := _m.Called(guildId)
+
+	var r0 *[]model.RoleResponse
+	if rf, ok := ret.Get(0).(func(string) *[]model.RoleResponse); ok {
+		r0 = rf(guildId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.RoleResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(guildId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUser provides a mock function with given fields: uid
 func (_m *GuildService) GetUser(uid string) (*model.User, error) {
 	ret := _m.Called(uid)
@@ -325,11 +422,53 @@ func (_m *GuildService) GetVCMembers(guildId string) (*[]model.VCMemberResponse,
 	return r0, r1
 }
 
+// HasPermission provides a mock function with given fields: userId, guildId, permission
+func (_m *GuildService) HasPermission(userId string, guildId string, permission model.Permission) bool {
+	ret := _m.Called(userId, guildId, permission)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string, model.Permission) bool); ok {
+		r0 = rf(userId, guildId, permission)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // InvalidateInvites provides a mock function with given fields: ctx, guild
 func (_m *GuildService) InvalidateInvites(ctx context.Context, guild *model.Guild) {
 	_m.Called(ctx, guild)
 }
 
+// OutranksMember provides a mock function with given fields: userId, memberId, guildId
+func (_m *GuildService) OutranksMember(userId string, memberId string, guildId string) bool {
+	ret := _m.Called(userId, memberId, guildId)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string, string) bool); ok {
+		r0 = rf(userId, memberId, guildId)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// OutranksRole provides a mock function with given fields: userId, role
+func (_m *GuildService) OutranksRole(userId string, role *model.Role) bool {
+	ret := _m.Called(userId, role)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, *model.Role) bool); ok {
+		r0 = rf(userId, role)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // RemoveMember provides a mock function with given fields: userId, guildId
 func (_m *GuildService) RemoveMember(userId string, guildId string) error {
 	ret := _m.Called(userId, guildId)
@@ -344,6 +483,20 @@ func (_m *GuildService) RemoveMember(userId string, guildId string) error {
 	return r0
 }
 
+// RemoveMemberRole provides a mock function with given fields: userId, guildId, roleId
+func (_m *GuildService) RemoveMemberRole(userId string, guildId string, roleId string) error {
+	ret := _m.Called(userId, guildId, roleId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(userId, guildId, roleId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RemoveVCMember provides a mock function with given fields: userId, guildId
 func (_m *GuildService) RemoveVCMember(userId string, guildId string) error {
 	ret := _m.Called(userId, guildId)
@@ -414,6 +567,20 @@ func (_m *GuildService) UpdateMemberSettings(settings *model.MemberSettings, use
 	return r0
 }
 
+// UpdateRole provides a mock function with given fields: role
+func (_m *GuildService) UpdateRole(role *model.Role) error {
+	ret := _m.Called(role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Role) error); ok {
+		r0 = rf(role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateVCMember provides a mock function with given fields: isMuted, isDeafened, userId, guildId
 func (_m *GuildService) UpdateVCMember(isMuted bool, isDeafened bool, userId string, guildId string) error {
 	ret := _m.Called(isMuted, isDeafened, userId, guildId)
@@ -0,0 +1,226 @@
+// Code generated by mockery v2.12.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/sentrionic/valkyrie/model"
+	mock "github.com/stretchr/testify/mock"
+
+	testing "testing"
+)
+
+// InteractionService is an autogenerated mock type for the InteractionService type
+type InteractionService struct {
+	mock.Mock
+}
+
+// CompleteInteraction provides a mock function with given fields: ctx, interaction
+func (_m *InteractionService) CompleteInteraction(ctx context.Context, interaction *model.Interaction) error {
+	ret := _m.Called(ctx, interaction)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Interaction) error); ok {
+		r0 = rf(ctx, interaction)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateCommand provides a mock function with given fields: command
+func (_m *InteractionService) CreateCommand(command *model.Command) error {
+	ret := _m.Called(command)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Command) error); ok {
+		r0 = rf(command)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateInteraction provides a mock function with given fields: ctx, command, channel, user, options
+func (_m *InteractionService) CreateInteraction(ctx context.Context, command *model.Command, channel *model.Channel, user *model.MemberResponse, options []model.InteractionOption) (*model.Interaction, error) {
+	ret := _m.Called(ctx, command, channel, user, options)
+
+	var r0 *model.Interaction
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Command, *model.Channel, *model.MemberResponse, []model.InteractionOption) *model.Interaction); ok {
+		r0 = rf(ctx, command, channel, user, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Interaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Command, *model.Channel, *model.MemberResponse, []model.InteractionOption) error); ok {
+		r1 = rf(ctx, command, channel, user, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeferInteraction provides a mock function with given fields: ctx, interaction
+func (_m *InteractionService) DeferInteraction(ctx context.Context, interaction *model.Interaction) error {
+	ret := _m.Called(ctx, interaction)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Interaction) error); ok {
+		r0 = rf(ctx, interaction)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteCommand provides a mock function with given fields: command
+func (_m *InteractionService) DeleteCommand(command *model.Command) error {
+	ret := _m.Called(command)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Command) error); ok {
+		r0 = rf(command)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeliverInteraction provides a mock function with given fields: bot, interaction
+func (_m *InteractionService) DeliverInteraction(bot *model.User, interaction *model.Interaction) error {
+	ret := _m.Called(bot, interaction)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.User, *model.Interaction) error); ok {
+		r0 = rf(bot, interaction)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetCommand provides a mock function with given fields: commandId
+func (_m *InteractionService) GetCommand(commandId string) (*model.Command, error) {
+	ret := _m.Called(commandId)
+
+	var r0 *model.Command
+	if rf, ok := ret.Get(0).(func(string) *model.Command); ok {
+		r0 = rf(commandId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Command)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(commandId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCommands provides a mock function with given fields: botId
+func (_m *InteractionService) GetCommands(botId string) (*[]model.Command, error) {
+	ret := _m.Called(botId)
+
+	var r0 *[]model.Command
+	if rf, ok := ret.Get(0).(func(string) *[]model.Command); ok {
+		r0 = rf(botId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.Command)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(botId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetGuildCommands provides a mock function with given fields: guildId
+func (_m *InteractionService) GetGuildCommands(guildId string) (*[]model.Command, error) {
+	ret := _m.Called(guildId)
+
+	var r0 *[]model.Command
+	if rf, ok := ret.Get(0).(func(string) *[]model.Command); ok {
+		r0 = rf(guildId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.Command)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(guildId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetInteraction provides a mock function with given fields: ctx, interactionId, token
+func (_m *InteractionService) GetInteraction(ctx context.Context, interactionId string, token string) (*model.Interaction, error) {
+	ret := _m.Called(ctx, interactionId, token)
+
+	var r0 *model.Interaction
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.Interaction); ok {
+		r0 = rf(ctx, interactionId, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Interaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, interactionId, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateCommand provides a mock function with given fields: command
+func (_m *InteractionService) UpdateCommand(command *model.Command) error {
+	ret := _m.Called(command)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Command) error); ok {
+		r0 = rf(command)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewInteractionService creates a new instance of InteractionService. It also registers the testing.TB interface on the mock and a cleanup function to assert the mocks expectations.
+func NewInteractionService(t testing.TB) *InteractionService {
+	mock := &InteractionService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -13,13 +13,27 @@ type MailRepository struct {
 	mock.Mock
 }
 
-// SendResetMail provides a mock function with given fields: email, html
-func (_m *MailRepository) SendResetMail(email string, html string) error {
-	ret := _m.Called(email, html)
+// SendResetMail provides a mock function with given fields: email, token
+func (_m *MailRepository) SendResetMail(email string, token string) error {
+	ret := _m.Called(email, token)
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(email, html)
+		r0 = rf(email, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendVerificationMail provides a mock function with given fields: email, token
+func (_m *MailRepository) SendVerificationMail(email string, token string) error {
+	ret := _m.Called(email, token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(email, token)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -0,0 +1,39 @@
+// Code generated by mockery v2.12.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/sentrionic/valkyrie/model"
+	mock "github.com/stretchr/testify/mock"
+
+	testing "testing"
+)
+
+// MailTransport is an autogenerated mock type for the MailTransport type
+type MailTransport struct {
+	mock.Mock
+}
+
+// Send provides a mock function with given fields: mail
+func (_m *MailTransport) Send(mail *model.Mail) error {
+	ret := _m.Called(mail)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Mail) error); ok {
+		r0 = rf(mail)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMailTransport creates a new instance of MailTransport. It also registers the testing.TB interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMailTransport(t testing.TB) *MailTransport {
+	mock := &MailTransport{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
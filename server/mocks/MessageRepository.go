@@ -7,6 +7,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	testing "testing"
+
+	time "time"
 )
 
 // MessageRepository is an autogenerated mock type for the MessageRepository type
@@ -14,6 +16,34 @@ type MessageRepository struct {
 	mock.Mock
 }
 
+// AddReaction provides a mock function with given fields: reaction
+func (_m *MessageRepository) AddReaction(reaction *model.Reaction) error {
+	ret := _m.Called(reaction)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Reaction) error); ok {
+		r0 = rf(reaction)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddUploadChunk provides a mock function with given fields: upload, chunk
+func (_m *MessageRepository) AddUploadChunk(upload *model.Upload, chunk *model.UploadChunk) error {
+	ret := _m.Called(upload, chunk)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Upload, *model.UploadChunk) error); ok {
+		r0 = rf(upload, chunk)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateMessage provides a mock function with given fields: params
 func (_m *MessageRepository) CreateMessage(params *model.Message) (*model.Message, error) {
 	ret := _m.Called(params)
@@ -37,6 +67,20 @@ func (_m *MessageRepository) CreateMessage(params *model.Message) (*model.Messag
 	return r0, r1
 }
 
+// CreateUpload provides a mock function with given fields: upload
+func (_m *MessageRepository) CreateUpload(upload *model.Upload) error {
+	ret := _m.Called(upload)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Upload) error); ok {
+		r0 = rf(upload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteMessage provides a mock function with given fields: message
 func (_m *MessageRepository) DeleteMessage(message *model.Message) error {
 	ret := _m.Called(message)
@@ -51,6 +95,20 @@ func (_m *MessageRepository) DeleteMessage(message *model.Message) error {
 	return r0
 }
 
+// DeleteUploads provides a mock function with given fields: uploadIds
+func (_m *MessageRepository) DeleteUploads(uploadIds []string) error {
+	ret := _m.Called(uploadIds)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]string) error); ok {
+		r0 = rf(uploadIds)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetById provides a mock function with given fields: messageId
 func (_m *MessageRepository) GetById(messageId string) (*model.Message, error) {
 	ret := _m.Called(messageId)
@@ -74,13 +132,128 @@ func (_m *MessageRepository) GetById(messageId string) (*model.Message, error) {
 	return r0, r1
 }
 
-// GetMessages provides a mock function with given fields: userId, channel, cursor
-func (_m *MessageRepository) GetMessages(userId string, channel *model.Channel, cursor string) (*[]model.MessageResponse, error) {
-	ret := _m.Called(userId, channel, cursor)
+// GetExpiredUploads provides a mock function with given fields:
+func (_m *MessageRepository) GetExpiredUploads() (*[]model.Upload, error) {
+	ret := _m.Called()
+
+	var r0 *[]model.Upload
+	if rf, ok := ret.Get(0).(func() *[]model.Upload); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.Upload)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMentionedMembers provides a mock function with given fields: guildId, message
+func (_m *MessageRepository) GetMentionedMembers(guildId string, message *model.Message) ([]string, error) {
+	ret := _m.Called(guildId, message)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string, *model.Message) []string); ok {
+		r0 = rf(guildId, message)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *model.Message) error); ok {
+		r1 = rf(guildId, message)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMessageMentions provides a mock function with given fields: channel, messageId
+func (_m *MessageRepository) GetMessageMentions(channel *model.Channel, messageId string) (*model.MessageMentions, error) {
+	ret := _m.Called(channel, messageId)
+
+	var r0 *model.MessageMentions
+	if rf, ok := ret.Get(0).(func(*model.Channel, string) *model.MessageMentions); ok {
+		r0 = rf(channel, messageId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.MessageMentions)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.Channel, string) error); ok {
+		r1 = rf(channel, messageId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMessageReactions provides a mock function with given fields: messageId
+func (_m *MessageRepository) GetMessageReactions(messageId string) ([]model.ReactionResponse, error) {
+	ret := _m.Called(messageId)
+
+	var r0 []model.ReactionResponse
+	if rf, ok := ret.Get(0).(func(string) []model.ReactionResponse); ok {
+		r0 = rf(messageId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ReactionResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(messageId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMessageRevisions provides a mock function with given fields: messageId
+func (_m *MessageRepository) GetMessageRevisions(messageId string) (*[]model.MessageRevision, error) {
+	ret := _m.Called(messageId)
+
+	var r0 *[]model.MessageRevision
+	if rf, ok := ret.Get(0).(func(string) *[]model.MessageRevision); ok {
+		r0 = rf(messageId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.MessageRevision)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(messageId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMessages provides a mock function with given fields: userId, channel, page
+func (_m *MessageRepository) GetMessages(userId string, channel *model.Channel, page *model.MessagePage) (*[]model.MessageResponse, error) {
+	ret := _m.Called(userId, channel, page)
 
 	var r0 *[]model.MessageResponse
-	if rf, ok := ret.Get(0).(func(string, *model.Channel, string) *[]model.MessageResponse); ok {
-		r0 = rf(userId, channel, cursor)
+	if rf, ok := ret.Get(0).(func(string, *model.Channel, *model.MessagePage) *[]model.MessageResponse); ok {
+		r0 = rf(userId, channel, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*[]model.MessageResponse)
@@ -88,8 +261,8 @@ func (_m *MessageRepository) GetMessages(userId string, channel *model.Channel,
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, *model.Channel, string) error); ok {
-		r1 = rf(userId, channel, cursor)
+	if rf, ok := ret.Get(1).(func(string, *model.Channel, *model.MessagePage) error); ok {
+		r1 = rf(userId, channel, page)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -97,6 +270,200 @@ func (_m *MessageRepository) GetMessages(userId string, channel *model.Channel,
 	return r0, r1
 }
 
+// GetPinnedMessages provides a mock function with given fields: userId, channel
+func (_m *MessageRepository) GetPinnedMessages(userId string, channel *model.Channel) (*[]model.MessageResponse, error) {
+	ret := _m.Called(userId, channel)
+
+	var r0 *[]model.MessageResponse
+	if rf, ok := ret.Get(0).(func(string, *model.Channel) *[]model.MessageResponse); ok {
+		r0 = rf(userId, channel)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.MessageResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *model.Channel) error); ok {
+		r1 = rf(userId, channel)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReactionEmojis provides a mock function with given fields: messageId
+func (_m *MessageRepository) GetReactionEmojis(messageId string) ([]string, error) {
+	ret := _m.Called(messageId)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(messageId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(messageId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReactionUsers provides a mock function with given fields: channel, messageId, emoji
+func (_m *MessageRepository) GetReactionUsers(channel *model.Channel, messageId string, emoji string) (*[]model.MemberResponse, error) {
+	ret := _m.Called(channel, messageId, emoji)
+
+	var r0 *[]model.MemberResponse
+	if rf, ok := ret.Get(0).(func(*model.Channel, string, string) *[]model.MemberResponse); ok {
+		r0 = rf(channel, messageId, emoji)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.MemberResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.Channel, string, string) error); ok {
+		r1 = rf(channel, messageId, emoji)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUpload provides a mock function with given fields: uploadId
+func (_m *MessageRepository) GetUpload(uploadId string) (*model.Upload, error) {
+	ret := _m.Called(uploadId)
+
+	var r0 *model.Upload
+	if rf, ok := ret.Get(0).(func(string) *model.Upload); ok {
+		r0 = rf(uploadId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Upload)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(uploadId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserMentions provides a mock function with given fields: userId
+func (_m *MessageRepository) GetUserMentions(userId string) (*[]model.MentionNotification, error) {
+	ret := _m.Called(userId)
+
+	var r0 *[]model.MentionNotification
+	if rf, ok := ret.Get(0).(func(string) *[]model.MentionNotification); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.MentionNotification)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PinMessage provides a mock function with given fields: message, pinnedAt, limit
+func (_m *MessageRepository) PinMessage(message *model.Message, pinnedAt time.Time, limit int) error {
+	ret := _m.Called(message, pinnedAt, limit)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Message, time.Time, int) error); ok {
+		r0 = rf(message, pinnedAt, limit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveReaction provides a mock function with given fields: messageId, userId, emoji
+func (_m *MessageRepository) RemoveReaction(messageId string, userId string, emoji string) error {
+	ret := _m.Called(messageId, userId, emoji)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(messageId, userId, emoji)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SearchMessages provides a mock function with given fields: userId, channelIds, search
+func (_m *MessageRepository) SearchMessages(userId string, channelIds []string, search *model.MessageSearch) (*model.MessageSearchResponse, error) {
+	ret := _m.Called(userId, channelIds, search)
+
+	var r0 *model.MessageSearchResponse
+	if rf, ok := ret.Get(0).(func(string, []string, *model.MessageSearch) *model.MessageSearchResponse); ok {
+		r0 = rf(userId, channelIds, search)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.MessageSearchResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, []string, *model.MessageSearch) error); ok {
+		r1 = rf(userId, channelIds, search)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetPinned provides a mock function with given fields: messageId, pinnedAt
+func (_m *MessageRepository) SetPinned(messageId string, pinnedAt *time.Time) error {
+	ret := _m.Called(messageId, pinnedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *time.Time) error); ok {
+		r0 = rf(messageId, pinnedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUploadUrl provides a mock function with given fields: uploadId, url
+func (_m *MessageRepository) SetUploadUrl(uploadId string, url string) error {
+	ret := _m.Called(uploadId, url)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(uploadId, url)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateMessage provides a mock function with given fields: message
 func (_m *MessageRepository) UpdateMessage(message *model.Message) error {
 	ret := _m.Called(message)
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	io "io"
+
 	model "github.com/sentrionic/valkyrie/model"
 	mock "github.com/stretchr/testify/mock"
 
@@ -16,6 +18,34 @@ type MessageService struct {
 	mock.Mock
 }
 
+// AddReaction provides a mock function with given fields: reaction
+func (_m *MessageService) AddReaction(reaction *model.Reaction) error {
+	ret := _m.Called(reaction)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Reaction) error); ok {
+		r0 = rf(reaction)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AppendUploadChunk provides a mock function with given fields: upload, chunk
+func (_m *MessageService) AppendUploadChunk(upload *model.Upload, chunk io.Reader) error {
+	ret := _m.Called(upload, chunk)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Upload, io.Reader) error); ok {
+		r0 = rf(upload, chunk)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateMessage provides a mock function with given fields: params
 func (_m *MessageService) CreateMessage(params *model.Message) (*model.Message, error) {
 	ret := _m.Called(params)
@@ -39,6 +69,55 @@ func (_m *MessageService) CreateMessage(params *model.Message) (*model.Message,
 	return r0, r1
 }
 
+// CreateResumableUpload provides a mock function with given fields: upload
+func (_m *MessageService) CreateResumableUpload(upload *model.Upload) error {
+	ret := _m.Called(upload)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Upload) error); ok {
+		r0 = rf(upload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateUpload provides a mock function with given fields: upload
+func (_m *MessageService) CreateUpload(upload *model.Upload) (string, error) {
+	ret := _m.Called(upload)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(*model.Upload) string); ok {
+		r0 = rf(upload)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.Upload) error); ok {
+		r1 = rf(upload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteExpiredUploads provides a mock function with given fields:
+func (_m *MessageService) DeleteExpiredUploads() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteMessage provides a mock function with given fields: message
 func (_m *MessageService) DeleteMessage(message *model.Message) error {
 	ret := _m.Called(message)
@@ -53,6 +132,29 @@ func (_m *MessageService) DeleteMessage(message *model.Message) error {
 	return r0
 }
 
+// FinalizeUpload provides a mock function with given fields: upload
+func (_m *MessageService) FinalizeUpload(upload *model.Upload) (*model.Attachment, error) {
+	ret := _m.Called(upload)
+
+	var r0 *model.Attachment
+	if rf, ok := ret.Get(0).(func(*model.Upload) *model.Attachment); ok {
+		r0 = rf(upload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Attachment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.Upload) error); ok {
+		r1 = rf(upload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Get provides a mock function with given fields: messageId
 func (_m *MessageService) Get(messageId string) (*model.Message, error) {
 	ret := _m.Called(messageId)
@@ -76,13 +178,105 @@ func (_m *MessageService) Get(messageId string) (*model.Message, error) {
 	return r0, r1
 }
 
-// GetMessages provides a mock function with given fields: userId, channel, cursor
-func (_m *MessageService) GetMessages(userId string, channel *model.Channel, cursor string) (*[]model.MessageResponse, error) {
-	ret := _m.Called(userId, channel, cursor)
+// GetMentionedUsers provides a mock function with given fields: channel, message
+func (_m *MessageService) GetMentionedUsers(channel *model.Channel, message *model.Message) ([]string, error) {
+	ret := _m.Called(channel, message)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(*model.Channel, *model.Message) []string); ok {
+		r0 = rf(channel, message)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.Channel, *model.Message) error); ok {
+		r1 = rf(channel, message)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMessageMentions provides a mock function with given fields: channel, messageId
+func (_m *MessageService) GetMessageMentions(channel *model.Channel, messageId string) (*model.MessageMentions, error) {
+	ret := _m.Called(channel, messageId)
+
+	var r0 *model.MessageMentions
+	if rf, ok := ret.Get(0).(func(*model.Channel, string) *model.MessageMentions); ok {
+		r0 = rf(channel, messageId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.MessageMentions)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.Channel, string) error); ok {
+		r1 = rf(channel, messageId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMessageReactions provides a mock function with given fields: messageId
+func (_m *MessageService) GetMessageReactions(messageId string) ([]model.ReactionResponse, error) {
+	ret := _m.Called(messageId)
+
+	var r0 []model.ReactionResponse
+	if rf, ok := ret.Get(0).(func(string) []model.ReactionResponse); ok {
+		r0 = rf(messageId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ReactionResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(messageId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMessageRevisions provides a mock function with given fields: messageId
+func (_m *MessageService) GetMessageRevisions(messageId string) (*[]model.MessageRevision, error) {
+	ret := _m.Called(messageId)
+
+	var r0 *[]model.MessageRevision
+	if rf, ok := ret.Get(0).(func(string) *[]model.MessageRevision); ok {
+		r0 = rf(messageId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.MessageRevision)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(messageId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMessages provides a mock function with given fields: userId, channel, page
+func (_m *MessageService) GetMessages(userId string, channel *model.Channel, page *model.MessagePage) (*[]model.MessageResponse, error) {
+	ret := _m.Called(userId, channel, page)
 
 	var r0 *[]model.MessageResponse
-	if rf, ok := ret.Get(0).(func(string, *model.Channel, string) *[]model.MessageResponse); ok {
-		r0 = rf(userId, channel, cursor)
+	if rf, ok := ret.Get(0).(func(string, *model.Channel, *model.MessagePage) *[]model.MessageResponse); ok {
+		r0 = rf(userId, channel, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*[]model.MessageResponse)
@@ -90,8 +284,100 @@ func (_m *MessageService) GetMessages(userId string, channel *model.Channel, cur
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, *model.Channel, string) error); ok {
-		r1 = rf(userId, channel, cursor)
+	if rf, ok := ret.Get(1).(func(string, *model.Channel, *model.MessagePage) error); ok {
+		r1 = rf(userId, channel, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPinnedMessages provides a mock function with given fields: userId, channel
+func (_m *MessageService) GetPinnedMessages(userId string, channel *model.Channel) (*[]model.MessageResponse, error) {
+	ret := _m.Called(userId, channel)
+
+	var r0 *[]model.MessageResponse
+	if rf, ok := ret.Get(0).(func(string, *model.Channel) *[]model.MessageResponse); ok {
+		r0 = rf(userId, channel)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.MessageResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *model.Channel) error); ok {
+		r1 = rf(userId, channel)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReactionUsers provides a mock function with given fields: channel, messageId, emoji
+func (_m *MessageService) GetReactionUsers(channel *model.Channel, messageId string, emoji string) (*[]model.MemberResponse, error) {
+	ret := _m.Called(channel, messageId, emoji)
+
+	var r0 *[]model.MemberResponse
+	if rf, ok := ret.Get(0).(func(*model.Channel, string, string) *[]model.MemberResponse); ok {
+		r0 = rf(channel, messageId, emoji)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.MemberResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.Channel, string, string) error); ok {
+		r1 = rf(channel, messageId, emoji)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUpload provides a mock function with given fields: uploadId
+func (_m *MessageService) GetUpload(uploadId string) (*model.Upload, error) {
+	ret := _m.Called(uploadId)
+
+	var r0 *model.Upload
+	if rf, ok := ret.Get(0).(func(string) *model.Upload); ok {
+		r0 = rf(uploadId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Upload)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(uploadId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserMentions provides a mock function with given fields: userId
+func (_m *MessageService) GetUserMentions(userId string) (*[]model.MentionNotification, error) {
+	ret := _m.Called(userId)
+
+	var r0 *[]model.MentionNotification
+	if rf, ok := ret.Get(0).(func(string) *[]model.MentionNotification); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.MentionNotification)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -99,6 +385,85 @@ func (_m *MessageService) GetMessages(userId string, channel *model.Channel, cur
 	return r0, r1
 }
 
+// PinMessage provides a mock function with given fields: message
+func (_m *MessageService) PinMessage(message *model.Message) error {
+	ret := _m.Called(message)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Message) error); ok {
+		r0 = rf(message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveReaction provides a mock function with given fields: messageId, userId, emoji
+func (_m *MessageService) RemoveReaction(messageId string, userId string, emoji string) error {
+	ret := _m.Called(messageId, userId, emoji)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(messageId, userId, emoji)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SearchMessages provides a mock function with given fields: userId, channelIds, search
+func (_m *MessageService) SearchMessages(userId string, channelIds []string, search *model.MessageSearch) (*model.MessageSearchResponse, error) {
+	ret := _m.Called(userId, channelIds, search)
+
+	var r0 *model.MessageSearchResponse
+	if rf, ok := ret.Get(0).(func(string, []string, *model.MessageSearch) *model.MessageSearchResponse); ok {
+		r0 = rf(userId, channelIds, search)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.MessageSearchResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, []string, *model.MessageSearch) error); ok {
+		r1 = rf(userId, channelIds, search)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TerminateUpload provides a mock function with given fields: upload
+func (_m *MessageService) TerminateUpload(upload *model.Upload) error {
+	ret := _m.Called(upload)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Upload) error); ok {
+		r0 = rf(upload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnpinMessage provides a mock function with given fields: message
+func (_m *MessageService) UnpinMessage(message *model.Message) error {
+	ret := _m.Called(message)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Message) error); ok {
+		r0 = rf(message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateMessage provides a mock function with given fields: message
 func (_m *MessageService) UpdateMessage(message *model.Message) error {
 	ret := _m.Called(message)
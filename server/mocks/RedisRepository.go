@@ -16,6 +16,50 @@ type RedisRepository struct {
 	mock.Mock
 }
 
+// ConsumeInteraction provides a mock function with given fields: ctx, token
+func (_m *RedisRepository) ConsumeInteraction(ctx context.Context, token string) (*model.Interaction, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 *model.Interaction
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Interaction); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Interaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteSessions provides a mock function with given fields: ctx, userId, ids
+func (_m *RedisRepository) DeleteSessions(ctx context.Context, userId string, ids ...string) error {
+	_va := make([]interface{}, len(ids))
+	for _i := range ids {
+		_va[_i] = ids[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, userId)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...string) error); ok {
+		r0 = rf(ctx, userId, ids...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetIdFromToken provides a mock function with given fields: ctx, token
 func (_m *RedisRepository) GetIdFromToken(ctx context.Context, token string) (string, error) {
 	ret := _m.Called(ctx, token)
@@ -37,6 +81,29 @@ func (_m *RedisRepository) GetIdFromToken(ctx context.Context, token string) (st
 	return r0, r1
 }
 
+// GetInteraction provides a mock function with given fields: ctx, token
+func (_m *RedisRepository) GetInteraction(ctx context.Context, token string) (*model.Interaction, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 *model.Interaction
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Interaction); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Interaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetInvite provides a mock function with given fields: ctx, token
 func (_m *RedisRepository) GetInvite(ctx context.Context, token string) (string, error) {
 	ret := _m.Called(ctx, token)
@@ -58,11 +125,115 @@ func (_m *RedisRepository) GetInvite(ctx context.Context, token string) (string,
 	return r0, r1
 }
 
+// GetPendingLogin provides a mock function with given fields: ctx, ticket
+func (_m *RedisRepository) GetPendingLogin(ctx context.Context, ticket string) (string, error) {
+	ret := _m.Called(ctx, ticket)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, ticket)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ticket)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSession provides a mock function with given fields: ctx, userId, id
+func (_m *RedisRepository) GetSession(ctx context.Context, userId string, id string) (*model.Session, error) {
+	ret := _m.Called(ctx, userId, id)
+
+	var r0 *model.Session
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.Session); ok {
+		r0 = rf(ctx, userId, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Session)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userId, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSessions provides a mock function with given fields: ctx, userId
+func (_m *RedisRepository) GetSessions(ctx context.Context, userId string) ([]model.Session, error) {
+	ret := _m.Called(ctx, userId)
+
+	var r0 []model.Session
+	if rf, ok := ret.Get(0).(func(context.Context, string) []model.Session); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Session)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVerificationToken provides a mock function with given fields: ctx, token
+func (_m *RedisRepository) GetVerificationToken(ctx context.Context, token string) (*model.EmailVerification, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 *model.EmailVerification
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.EmailVerification); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.EmailVerification)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // InvalidateInvites provides a mock function with given fields: ctx, guild
 func (_m *RedisRepository) InvalidateInvites(ctx context.Context, guild *model.Guild) {
 	_m.Called(ctx, guild)
 }
 
+// SaveInteraction provides a mock function with given fields: ctx, interaction
+func (_m *RedisRepository) SaveInteraction(ctx context.Context, interaction *model.Interaction) error {
+	ret := _m.Called(ctx, interaction)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Interaction) error); ok {
+		r0 = rf(ctx, interaction)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SaveInvite provides a mock function with given fields: ctx, guildId, id, isPermanent
 func (_m *RedisRepository) SaveInvite(ctx context.Context, guildId string, id string, isPermanent bool) error {
 	ret := _m.Called(ctx, guildId, id, isPermanent)
@@ -77,6 +248,41 @@ func (_m *RedisRepository) SaveInvite(ctx context.Context, guildId string, id st
 	return r0
 }
 
+// SaveSession provides a mock function with given fields: ctx, userId, session
+func (_m *RedisRepository) SaveSession(ctx context.Context, userId string, session *model.Session) error {
+	ret := _m.Called(ctx, userId, session)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *model.Session) error); ok {
+		r0 = rf(ctx, userId, session)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPendingLogin provides a mock function with given fields: ctx, id
+func (_m *RedisRepository) SetPendingLogin(ctx context.Context, id string) (string, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetResetToken provides a mock function with given fields: ctx, id
 func (_m *RedisRepository) SetResetToken(ctx context.Context, id string) (string, error) {
 	ret := _m.Called(ctx, id)
@@ -98,6 +304,41 @@ func (_m *RedisRepository) SetResetToken(ctx context.Context, id string) (string
 	return r0, r1
 }
 
+// SetVerificationToken provides a mock function with given fields: ctx, id, email
+func (_m *RedisRepository) SetVerificationToken(ctx context.Context, id string, email string) (string, error) {
+	ret := _m.Called(ctx, id, email)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(ctx, id, email)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, id, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateInteraction provides a mock function with given fields: ctx, interaction
+func (_m *RedisRepository) UpdateInteraction(ctx context.Context, interaction *model.Interaction) error {
+	ret := _m.Called(ctx, interaction)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Interaction) error); ok {
+		r0 = rf(ctx, interaction)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewRedisRepository creates a new instance of RedisRepository. It also registers the testing.TB interface on the mock and a cleanup function to assert the mocks expectations.
 func NewRedisRepository(t testing.TB) *RedisRepository {
 	mock := &RedisRepository{}
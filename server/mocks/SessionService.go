@@ -0,0 +1,124 @@
+// Code generated by mockery v2.12.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/sentrionic/valkyrie/model"
+	mock "github.com/stretchr/testify/mock"
+
+	testing "testing"
+)
+
+// SessionService is an autogenerated mock type for the SessionService type
+type SessionService struct {
+	mock.Mock
+}
+
+// CreateSession provides a mock function with given fields: ctx, userId, userAgent, ip
+func (_m *SessionService) CreateSession(ctx context.Context, userId string, userAgent string, ip string) (*model.Session, error) {
+	ret := _m.Called(ctx, userId, userAgent, ip)
+
+	var r0 *model.Session
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *model.Session); ok {
+		r0 = rf(ctx, userId, userAgent, ip)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Session)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, userId, userAgent, ip)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSessions provides a mock function with given fields: ctx, userId
+func (_m *SessionService) GetSessions(ctx context.Context, userId string) ([]model.Session, error) {
+	ret := _m.Called(ctx, userId)
+
+	var r0 []model.Session
+	if rf, ok := ret.Get(0).(func(context.Context, string) []model.Session); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Session)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeOtherSessions provides a mock function with given fields: ctx, userId, currentId
+func (_m *SessionService) RevokeOtherSessions(ctx context.Context, userId string, currentId string) ([]string, error) {
+	ret := _m.Called(ctx, userId, currentId)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []string); ok {
+		r0 = rf(ctx, userId, currentId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userId, currentId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeSession provides a mock function with given fields: ctx, userId, id
+func (_m *SessionService) RevokeSession(ctx context.Context, userId string, id string) error {
+	ret := _m.Called(ctx, userId, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userId, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TouchSession provides a mock function with given fields: ctx, userId, id, userAgent, ip
+func (_m *SessionService) TouchSession(ctx context.Context, userId string, id string, userAgent string, ip string) error {
+	ret := _m.Called(ctx, userId, id, userAgent, ip)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) error); ok {
+		r0 = rf(ctx, userId, id, userAgent, ip)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSessionService creates a new instance of SessionService. It also registers the testing.TB interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSessionService(t testing.TB) *SessionService {
+	mock := &SessionService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
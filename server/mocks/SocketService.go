@@ -14,6 +14,16 @@ type SocketService struct {
 	mock.Mock
 }
 
+// DisconnectAccessTokens provides a mock function with given fields: userId, accessTokenIds
+func (_m *SocketService) DisconnectAccessTokens(userId string, accessTokenIds []string) {
+	_m.Called(userId, accessTokenIds)
+}
+
+// DisconnectSessions provides a mock function with given fields: userId, sessionIds
+func (_m *SocketService) DisconnectSessions(userId string, sessionIds []string) {
+	_m.Called(userId, sessionIds)
+}
+
 // EmitAddFriend provides a mock function with given fields: user, member
 func (_m *SocketService) EmitAddFriend(user *model.User, member *model.User) {
 	_m.Called(user, member)
@@ -29,6 +39,26 @@ func (_m *SocketService) EmitAddMember(room string, member *model.User) {
 	_m.Called(room, member)
 }
 
+// EmitAddMemberRole provides a mock function with given fields: guildId, memberId, roleId
+func (_m *SocketService) EmitAddMemberRole(guildId string, memberId string, roleId string) {
+	_m.Called(guildId, memberId, roleId)
+}
+
+// EmitAddReaction provides a mock function with given fields: room, reaction
+func (_m *SocketService) EmitAddReaction(room string, reaction *model.ReactionEvent) {
+	_m.Called(room, reaction)
+}
+
+// EmitAddRole provides a mock function with given fields: guildId, role
+func (_m *SocketService) EmitAddRole(guildId string, role *model.RoleResponse) {
+	_m.Called(guildId, role)
+}
+
+// EmitAddToGuild provides a mock function with given fields: memberId, guild
+func (_m *SocketService) EmitAddToGuild(memberId string, guild *model.GuildResponse) {
+	_m.Called(memberId, guild)
+}
+
 // EmitDeleteChannel provides a mock function with given fields: channel
 func (_m *SocketService) EmitDeleteChannel(channel *model.Channel) {
 	_m.Called(channel)
@@ -44,6 +74,16 @@ func (_m *SocketService) EmitDeleteMessage(room string, messageId string) {
 	_m.Called(room, messageId)
 }
 
+// EmitDeleteRole provides a mock function with given fields: guildId, roleId
+func (_m *SocketService) EmitDeleteRole(guildId string, roleId string) {
+	_m.Called(guildId, roleId)
+}
+
+// EmitDeleteThread provides a mock function with given fields: thread
+func (_m *SocketService) EmitDeleteThread(thread *model.ThreadResponse) {
+	_m.Called(thread)
+}
+
 // EmitEditChannel provides a mock function with given fields: room, channel
 func (_m *SocketService) EmitEditChannel(room string, channel *model.ChannelResponse) {
 	_m.Called(room, channel)
@@ -59,6 +99,26 @@ func (_m *SocketService) EmitEditMessage(room string, message *model.MessageResp
 	_m.Called(room, message)
 }
 
+// EmitEditRole provides a mock function with given fields: guildId, role
+func (_m *SocketService) EmitEditRole(guildId string, role *model.RoleResponse) {
+	_m.Called(guildId, role)
+}
+
+// EmitEditThread provides a mock function with given fields: thread
+func (_m *SocketService) EmitEditThread(thread *model.ThreadResponse) {
+	_m.Called(thread)
+}
+
+// EmitInteractionCreate provides a mock function with given fields: botId, interaction
+func (_m *SocketService) EmitInteractionCreate(botId string, interaction *model.Interaction) {
+	_m.Called(botId, interaction)
+}
+
+// EmitInteractionDeferred provides a mock function with given fields: room, interaction
+func (_m *SocketService) EmitInteractionDeferred(room string, interaction *model.Interaction) {
+	_m.Called(room, interaction)
+}
+
 // EmitNewChannel provides a mock function with given fields: room, channel
 func (_m *SocketService) EmitNewChannel(room string, channel *model.ChannelResponse) {
 	_m.Called(room, channel)
@@ -69,6 +129,11 @@ func (_m *SocketService) EmitNewDMNotification(channelId string, user *model.Use
 	_m.Called(channelId, user)
 }
 
+// EmitNewMention provides a mock function with given fields: userId, notification
+func (_m *SocketService) EmitNewMention(userId string, notification *model.MentionNotification) {
+	_m.Called(userId, notification)
+}
+
 // EmitNewMessage provides a mock function with given fields: room, message
 func (_m *SocketService) EmitNewMessage(room string, message *model.MessageResponse) {
 	_m.Called(room, message)
@@ -84,6 +149,16 @@ func (_m *SocketService) EmitNewPrivateChannel(members []string, channel *model.
 	_m.Called(members, channel)
 }
 
+// EmitNewThread provides a mock function with given fields: thread
+func (_m *SocketService) EmitNewThread(thread *model.ThreadResponse) {
+	_m.Called(thread)
+}
+
+// EmitPinMessage provides a mock function with given fields: room, pin
+func (_m *SocketService) EmitPinMessage(room string, pin *model.PinEvent) {
+	_m.Called(room, pin)
+}
+
 // EmitRemoveFriend provides a mock function with given fields: userId, memberId
 func (_m *SocketService) EmitRemoveFriend(userId string, memberId string) {
 	_m.Called(userId, memberId)
@@ -99,11 +174,26 @@ func (_m *SocketService) EmitRemoveMember(room string, memberId string) {
 	_m.Called(room, memberId)
 }
 
+// EmitRemoveMemberRole provides a mock function with given fields: guildId, memberId, roleId
+func (_m *SocketService) EmitRemoveMemberRole(guildId string, memberId string, roleId string) {
+	_m.Called(guildId, memberId, roleId)
+}
+
+// EmitRemoveReaction provides a mock function with given fields: room, reaction
+func (_m *SocketService) EmitRemoveReaction(room string, reaction *model.ReactionEvent) {
+	_m.Called(room, reaction)
+}
+
 // EmitSendRequest provides a mock function with given fields: room
 func (_m *SocketService) EmitSendRequest(room string) {
 	_m.Called(room)
 }
 
+// EmitUnpinMessage provides a mock function with given fields: room, pin
+func (_m *SocketService) EmitUnpinMessage(room string, pin *model.PinEvent) {
+	_m.Called(room, pin)
+}
+
 // NewSocketService creates a new instance of SocketService. It also registers the testing.TB interface on the mock and a cleanup function to assert the mocks expectations.
 func NewSocketService(t testing.TB) *SocketService {
 	mock := &SocketService{}
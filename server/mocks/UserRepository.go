@@ -37,6 +37,43 @@ func (_m *UserRepository) Create(user *model.User) (*model.User, error) {
 	return r0, r1
 }
 
+// Delete provides a mock function with given fields: userId
+func (_m *UserRepository) Delete(userId string) error {
+	ret := _m.Called(userId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindBots provides a mock function with given fields: ownerId
+func (_m *UserRepository) FindBots(ownerId string) (*[]model.User, error) {
+	ret := _m.Called(ownerId)
+
+	var r0 *[]model.User
+	if rf, ok := ret.Get(0).(func(string) *[]model.User); ok {
+		r0 = rf(ownerId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(ownerId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByEmail provides a mock function with given fields: email
 func (_m *UserRepository) FindByEmail(email string) (*model.User, error) {
 	ret := _m.Called(email)
@@ -53,13 +53,59 @@ func (_m *UserService) ChangePassword(currentPassword string, newPassword string
 	return r0
 }
 
-// DeleteImage provides a mock function with given fields: key
-func (_m *UserService) DeleteImage(key string) error {
-	ret := _m.Called(key)
+// CompleteTwoFactorLogin provides a mock function with given fields: ctx, ticket, code
+func (_m *UserService) CompleteTwoFactorLogin(ctx context.Context, ticket string, code string) (*model.User, error) {
+	ret := _m.Called(ctx, ticket, code)
+
+	var r0 *model.User
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.User); ok {
+		r0 = rf(ctx, ticket, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, ticket, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConfirmTwoFactor provides a mock function with given fields: user, code
+func (_m *UserService) ConfirmTwoFactor(user *model.User, code string) ([]string, error) {
+	ret := _m.Called(user, code)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(*model.User, string) []string); ok {
+		r0 = rf(user, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.User, string) error); ok {
+		r1 = rf(user, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteImage provides a mock function with given fields: url
+func (_m *UserService) DeleteImage(url string) error {
+	ret := _m.Called(url)
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(key)
+		r0 = rf(url)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -67,6 +113,43 @@ func (_m *UserService) DeleteImage(key string) error {
 	return r0
 }
 
+// DisableTwoFactor provides a mock function with given fields: user, password
+func (_m *UserService) DisableTwoFactor(user *model.User, password string) error {
+	ret := _m.Called(user, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.User, string) error); ok {
+		r0 = rf(user, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnableTwoFactor provides a mock function with given fields: user
+func (_m *UserService) EnableTwoFactor(user *model.User) (*model.TwoFactorSetup, error) {
+	ret := _m.Called(user)
+
+	var r0 *model.TwoFactorSetup
+	if rf, ok := ret.Get(0).(func(*model.User) *model.TwoFactorSetup); ok {
+		r0 = rf(user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TwoFactorSetup)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.User) error); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ForgotPassword provides a mock function with given fields: ctx, user
 func (_m *UserService) ForgotPassword(ctx context.Context, user *model.User) error {
 	ret := _m.Called(ctx, user)
@@ -150,6 +233,27 @@ func (_m *UserService) GetFriendAndGuildIds(userId string) (*[]string, error) {
 	return r0, r1
 }
 
+// GetImageUrl provides a mock function with given fields: path, size
+func (_m *UserService) GetImageUrl(path string, size int) (string, error) {
+	ret := _m.Called(path, size)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, int) string); ok {
+		r0 = rf(path, size)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(path, size)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRequestCount provides a mock function with given fields: userId
 func (_m *UserService) GetRequestCount(userId string) (*int64, error) {
 	ret := _m.Called(userId)
@@ -256,6 +360,41 @@ func (_m *UserService) ResetPassword(ctx context.Context, password string, token
 	return r0, r1
 }
 
+// SendVerificationMail provides a mock function with given fields: ctx, user
+func (_m *UserService) SendVerificationMail(ctx context.Context, user *model.User) error {
+	ret := _m.Called(ctx, user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StartTwoFactorLogin provides a mock function with given fields: ctx, user
+func (_m *UserService) StartTwoFactorLogin(ctx context.Context, user *model.User) (string, error) {
+	ret := _m.Called(ctx, user)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User) string); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *model.User) error); ok {
+		r1 = rf(ctx, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateAccount provides a mock function with given fields: user
 func (_m *UserService) UpdateAccount(user *model.User) error {
 	ret := _m.Called(user)
@@ -270,6 +409,43 @@ func (_m *UserService) UpdateAccount(user *model.User) error {
 	return r0
 }
 
+// VerifyEmail provides a mock function with given fields: ctx, token
+func (_m *UserService) VerifyEmail(ctx context.Context, token string) (*model.User, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 *model.User
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.User); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyTwoFactor provides a mock function with given fields: user, code
+func (_m *UserService) VerifyTwoFactor(user *model.User, code string) error {
+	ret := _m.Called(user, code)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.User, string) error); ok {
+		r0 = rf(user, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUserService creates a new instance of UserService. It also registers the testing.TB interface on the mock and a cleanup function to assert the mocks expectations.
 func NewUserService(t testing.TB) *UserService {
 	mock := &UserService{}
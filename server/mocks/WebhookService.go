@@ -0,0 +1,168 @@
+// Code generated by mockery v2.12.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/sentrionic/valkyrie/model"
+	mock "github.com/stretchr/testify/mock"
+
+	testing "testing"
+)
+
+// WebhookService is an autogenerated mock type for the WebhookService type
+type WebhookService struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: webhookId, token
+func (_m *WebhookService) Authenticate(webhookId string, token string) (*model.Webhook, error) {
+	ret := _m.Called(webhookId, token)
+
+	var r0 *model.Webhook
+	if rf, ok := ret.Get(0).(func(string, string) *model.Webhook); ok {
+		r0 = rf(webhookId, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(webhookId, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateWebhook provides a mock function with given fields: channel, creatorId, name
+func (_m *WebhookService) CreateWebhook(channel *model.Channel, creatorId string, name string) (*model.CreatedWebhook, error) {
+	ret := _m.Called(channel, creatorId, name)
+
+	var r0 *model.CreatedWebhook
+	if rf, ok := ret.Get(0).(func(*model.Channel, string, string) *model.CreatedWebhook); ok {
+		r0 = rf(channel, creatorId, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.CreatedWebhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.Channel, string, string) error); ok {
+		r1 = rf(channel, creatorId, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteWebhook provides a mock function with given fields: webhook
+func (_m *WebhookService) DeleteWebhook(webhook *model.Webhook) error {
+	ret := _m.Called(webhook)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Webhook) error); ok {
+		r0 = rf(webhook)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetWebhook provides a mock function with given fields: channelId, webhookId
+func (_m *WebhookService) GetWebhook(channelId string, webhookId string) (*model.Webhook, error) {
+	ret := _m.Called(channelId, webhookId)
+
+	var r0 *model.Webhook
+	if rf, ok := ret.Get(0).(func(string, string) *model.Webhook); ok {
+		r0 = rf(channelId, webhookId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(channelId, webhookId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebhooks provides a mock function with given fields: channelId
+func (_m *WebhookService) GetWebhooks(channelId string) (*[]model.Webhook, error) {
+	ret := _m.Called(channelId)
+
+	var r0 *[]model.Webhook
+	if rf, ok := ret.Get(0).(func(string) *[]model.Webhook); ok {
+		r0 = rf(channelId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]model.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(channelId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetWebhookToken provides a mock function with given fields: webhook
+func (_m *WebhookService) ResetWebhookToken(webhook *model.Webhook) (*model.CreatedWebhook, error) {
+	ret := _m.Called(webhook)
+
+	var r0 *model.CreatedWebhook
+	if rf, ok := ret.Get(0).(func(*model.Webhook) *model.CreatedWebhook); ok {
+		r0 = rf(webhook)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.CreatedWebhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.Webhook) error); ok {
+		r1 = rf(webhook)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateWebhook provides a mock function with given fields: webhook
+func (_m *WebhookService) UpdateWebhook(webhook *model.Webhook) error {
+	ret := _m.Called(webhook)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Webhook) error); ok {
+		r0 = rf(webhook)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewWebhookService creates a new instance of WebhookService. It also registers the testing.TB interface on the mock and a cleanup function to assert the mocks expectations.
+func NewWebhookService(t testing.TB) *WebhookService {
+	mock := &WebhookService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,87 @@
+// Code generated by mockery v2.12.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	testing "testing"
+)
+
+// seekableFile is an autogenerated mock type for the seekableFile type
+type seekableFile struct {
+	mock.Mock
+}
+
+// Read provides a mock function with given fields: p
+func (_m *seekableFile) Read(p []byte) (int, error) {
+	ret := _m.Called(p)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func([]byte) int); ok {
+		r0 = rf(p)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]byte) error); ok {
+		r1 = rf(p)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReadAt provides a mock function with given fields: p, off
+func (_m *seekableFile) ReadAt(p []byte, off int64) (int, error) {
+	ret := _m.Called(p, off)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func([]byte, int64) int); ok {
+		r0 = rf(p, off)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]byte, int64) error); ok {
+		r1 = rf(p, off)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Seek provides a mock function with given fields: offset, whence
+func (_m *seekableFile) Seek(offset int64, whence int) (int64, error) {
+	ret := _m.Called(offset, whence)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(int64, int) int64); ok {
+		r0 = rf(offset, whence)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, int) error); ok {
+		r1 = rf(offset, whence)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// newSeekableFile creates a new instance of seekableFile. It also registers the testing.TB interface on the mock and a cleanup function to assert the mocks expectations.
+func newSeekableFile(t testing.TB) *seekableFile {
+	mock := &seekableFile{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
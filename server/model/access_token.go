@@ -0,0 +1,84 @@
+package model
+
+import (
+	"github.com/lib/pq"
+	"time"
+)
+
+// Access Token Scopes
+const (
+	// ScopeReadMessages allows reading guilds, channels and messages
+	ScopeReadMessages = "messages.read"
+	// ScopeSendMessages allows sending, editing and reacting to messages
+	ScopeSendMessages = "messages.send"
+	// ScopeManageGuilds allows creating and editing guilds, channels and roles
+	ScopeManageGuilds = "guilds.manage"
+)
+
+// AccessTokenScopes contains all scopes an access token can have
+var AccessTokenScopes = []string{ScopeReadMessages, ScopeSendMessages, ScopeManageGuilds}
+
+// Access Token Settings
+const (
+	// AccessTokenPrefix is the prefix of every access token, so leaked tokens are easy to detect
+	AccessTokenPrefix = "vlk_"
+	// AccessTokenLimit is the number of access tokens a user can have
+	AccessTokenLimit = 25
+	// AccessTokenTouchInterval limits how often the last use of a token gets written
+	AccessTokenTouchInterval = time.Minute
+)
+
+// AccessToken is a personal token the user creates to call the API from scripts.
+// Only the SHA-256 hash of the token gets stored, so the token itself is
+// only shown once when it gets created.
+type AccessToken struct {
+	BaseModel
+	UserId     string         `gorm:"not null;index" json:"-"`
+	Name       string         `gorm:"not null" json:"name"`
+	TokenHash  string         `gorm:"not null;uniqueIndex" json:"-"`
+	Scopes     pq.StringArray `gorm:"type:text[];not null" json:"scopes"`
+	ExpiresAt  *time.Time     `json:"expiresAt"`
+	LastUsedAt *time.Time     `json:"lastUsedAt"`
+} //@name AccessToken
+
+// HasScope returns true if the token has the given scope
+func (t *AccessToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpired returns true if the token has an expiry date that passed
+func (t *AccessToken) IsExpired() bool {
+	return t.ExpiresAt != nil && !t.ExpiresAt.After(time.Now())
+}
+
+// CreatedAccessToken is the newly created access token together with the token itself
+type CreatedAccessToken struct {
+	AccessToken
+	Token string `json:"token"`
+} //@name CreatedAccessToken
+
+// AccessTokenService defines methods related to access tokens the handler layer expects
+// any service it interacts with to implement
+type AccessTokenService interface {
+	CreateAccessToken(userId string, name string, scopes []string, expiresAt *time.Time) (*CreatedAccessToken, error)
+	GetAccessTokens(userId string) (*[]AccessToken, error)
+	DeleteAccessToken(userId string, id string) error
+	Authenticate(token string) (*AccessToken, error)
+}
+
+// AccessTokenRepository defines methods related to access token db operations the service layer expects
+// any repository it interacts with to implement
+type AccessTokenRepository interface {
+	Create(token *AccessToken) error
+	FindByHash(hash string) (*AccessToken, error)
+	FindByUser(userId string) (*[]AccessToken, error)
+	CountByUser(userId string) (int64, error)
+	Delete(userId string, id string) error
+	DeleteByUser(userId string) error
+	UpdateLastUsed(id string, lastUsedAt time.Time) error
+}
@@ -2,8 +2,56 @@ package model
 
 // Application Constants
 const (
-	MinimumChannels = 1
-	MaximumChannels = 50
-	MaximumGuilds   = 100
-	CookieName      = "vlk"
+	MinimumChannels        = 1
+	MaximumChannels        = 50
+	MaximumGuilds          = 100
+	MaximumRoles           = 250
+	MaximumReactions       = 20
+	MaximumPins            = 50
+	ReferenceTextLength    = 100
+	SearchPageSize         = 25
+	MaximumSearchOffset    = 5000
+	MessagePageSize        = 35
+	MaximumMessagePageSize = 100
+	MaximumAttachments     = 10
+	ThumbnailSize          = 400
+	DefaultImageSize       = 128
+	MaximumAnimationPixels = 32 << 20
+	MaximumUploadSize      = 100 << 20
+	CookieName             = "vlk"
+	// SessionMaxAge is the number of seconds a session stays valid after its last use
+	SessionMaxAge = 60 * 60 * 24 * 7 // 7 days
 )
+
+// Storage Drivers
+const (
+	StorageDriverLocal = "local"
+	StorageDriverS3    = "s3"
+)
+
+// Mail Drivers
+const (
+	MailDriverSMTP = "smtp"
+	MailDriverFile = "file"
+	MailDriverLog  = "log"
+)
+
+// SMTP Security Modes
+const (
+	SMTPSecurityStartTLS = "starttls"
+	SMTPSecurityTLS      = "tls"
+	SMTPSecurityNone     = "none"
+)
+
+// ImageSizes are the sizes avatars and guild icons get stored in
+var ImageSizes = []int{64, 128, 256, 512}
+
+// IsImageSize reports whether the given size is one of the ImageSizes
+func IsImageSize(size int) bool {
+	for _, s := range ImageSizes {
+		if s == size {
+			return true
+		}
+	}
+	return false
+}
@@ -2,24 +2,32 @@ package apperrors
 
 // Guild Errors
 const (
-	NotAMember             = "Not a member of the guild"
-	AlreadyMember          = "Already a member of the guild"
-	GuildLimitReached      = "The guild limit is 100"
-	MustBeOwner            = "Must be the owner for that"
-	InvalidImageType       = "imageFile must be 'image/jpeg' or 'image/png'"
-	MustBeMemberInvite     = "Must be a member to fetch an invite"
-	IsPermanentError       = "isPermanent is not a boolean"
-	InvalidateInvitesError = "Only the owner can invalidate invites"
-	InvalidInviteError     = "Invalid Link or the server got deleted"
-	BannedFromServer       = "You are banned from this server"
-	DeleteGuildError       = "Only the owner can delete their server"
-	OwnerCantLeave         = "The owner cannot leave their server"
-	BanYourselfError       = "You cannot ban yourself"
-	KickYourselfError      = "You cannot kick yourself"
-	UnbanYourselfError     = "You cannot unban yourself"
-	OneChannelRequired     = "A server needs at least one channel"
-	ChannelLimitError      = "The channel limit is 50"
-	DMYourselfError        = "You cannot dm yourself"
+	NotAMember         = "Not a member of the guild"
+	AlreadyMember      = "Already a member of the guild"
+	GuildLimitReached  = "The guild limit is 100"
+	InvalidImageType   = "imageFile must be 'image/jpeg', 'image/png', 'image/gif' or 'image/webp'"
+	MustBeMemberInvite = "Must be a member to fetch an invite"
+	IsPermanentError   = "isPermanent is not a boolean"
+	InvalidInviteError = "Invalid Link or the server got deleted"
+	BannedFromServer   = "You are banned from this server"
+	DeleteGuildError   = "Only the owner can delete their server"
+	OwnerCantLeave     = "The owner cannot leave their server"
+	BanYourselfError   = "You cannot ban yourself"
+	KickYourselfError  = "You cannot kick yourself"
+	UnbanYourselfError = "You cannot unban yourself"
+	OneChannelRequired = "A server needs at least one channel"
+	ChannelLimitError  = "The channel limit is 50"
+	DMYourselfError    = "You cannot dm yourself"
+	MissingPermissions = "You do not have the permissions for that"
+	KickOwnerError     = "You cannot kick the owner"
+	BanOwnerError      = "You cannot ban the owner"
+)
+
+// Role Errors
+const (
+	RoleLimitError        = "The role limit is 250"
+	GrantPermissionsError = "You cannot grant permissions you do not have"
+	RoleHierarchyError    = "You can only manage members and roles below your highest role"
 )
 
 // Account Errors
@@ -29,6 +37,58 @@ const (
 	DuplicateEmail      = "An account with that email already exists"
 	PasswordsDoNotMatch = "Passwords do not match"
 	InvalidResetToken   = "Invalid reset token"
+	InvalidVerifyToken  = "Invalid verification token"
+	AlreadyVerified     = "Your email address is already verified"
+	EmailNotVerified    = "You need to verify your email address first"
+	InvalidLoginTicket  = "The login expired. Log in again"
+	InvalidTwoFactor    = "Invalid two-factor code"
+	TwoFactorEnabled    = "Two-factor authentication is already enabled"
+	TwoFactorDisabled   = "Two-factor authentication is not enabled"
+	TwoFactorNotStarted = "Enable two-factor authentication first"
+	InvalidPassword     = "Invalid password"
+)
+
+// Access Token Errors
+const (
+	InvalidAccessToken    = "The access token is invalid or expired"
+	AccessTokenNotAllowed = "Access tokens cannot be used for this route"
+	MissingTokenScope     = "The access token does not have the required scope"
+	AccessTokenLimitError = "The access token limit is 25"
+	InvalidTokenScope     = "scopes must only contain messages.read, messages.send or guilds.manage"
+	TokenExpiryError      = "expiresAt must be in the future"
+)
+
+// Bot Errors
+const (
+	BotLimitError  = "The bot limit is 10"
+	BotInviteError = "Bots have to be added by a member with the Manage Server permission"
+	BotFriendError = "Bots cannot have friends"
+	BotOnlyError   = "Only bots can manage commands"
+	BotGuildError  = "Bots cannot create servers"
+)
+
+// Command Errors
+const (
+	CommandLimitError        = "The command limit is 100"
+	InvalidCommandName       = "must only contain lowercase letters, numbers, - and _"
+	InvalidCommandOptions    = "options need unique names, a description and a valid type and the required options come first"
+	CommandChannelError      = "Commands can only be used in guild channels"
+	UnknownCommandOption     = "The command has no option %s"
+	MissingCommandOption     = "The option %s is required"
+	DuplicateCommandOption   = "The option %s can only be given once"
+	InvalidOptionValue       = "The option %s must be of type %s"
+	InvalidInteractionToken  = "The interaction token is invalid or expired"
+	InteractionDeferredError = "The interaction has already been deferred"
+	InteractionDeliveryError = "The bot did not accept the interaction"
+	InteractionResponseText  = "text is required for message responses"
+	InteractionsUrlScheme    = "must be a https url"
+)
+
+// Webhook Errors
+const (
+	WebhookLimitError   = "The webhook limit per channel is 10"
+	WebhookChannelError = "Webhooks can only be added to guild channels"
+	InvalidWebhookToken = "The webhook token is invalid"
 )
 
 // Friend Errors
@@ -51,8 +111,46 @@ const (
 
 // Message Errors
 const (
-	MessageOrFileRequired = "Either a message or a file is required"
-	EditMessageError      = "Only the author can edit the message"
-	DeleteMessageError    = "Only the author or owner can delete the message"
-	DeleteDMMessageError  = "Only the author can delete the message"
+	MessageOrFileRequired  = "Either a message or a file is required"
+	EditMessageError       = "Only the author can edit the message"
+	DeleteMessageError     = "Only the author or a moderator can delete the message"
+	DeleteDMMessageError   = "Only the author can delete the message"
+	SendMessagesError      = "You cannot send messages in this channel"
+	AttachFilesError       = "You cannot attach files in this channel"
+	InvalidEmojiError      = "The reaction must be a single unicode emoji"
+	AlreadyReactedError    = "You already reacted with that emoji"
+	ReactionLimitError     = "The reaction limit is 20"
+	PinMessagesError       = "Only moderators can pin messages"
+	AlreadyPinnedError     = "The message is already pinned"
+	NotPinnedError         = "The message is not pinned"
+	PinLimitError          = "The pin limit is 50"
+	SearchRequired         = "A search term or filter is required"
+	InvalidSearchFilter    = "Only has:attachment is supported"
+	InvalidSearchDate      = "Dates must use the YYYY-MM-DD format"
+	MultipleCursorsError   = "Only one of before, after and around can be used"
+	AttachmentLimitError   = "A message can have at most 10 attachments"
+	UploadSizeError        = "The file size must be between 1 byte and 100 MiB"
+	UploadMismatchError    = "The uploaded file does not match the declared size"
+	InvalidFileType        = "The file type is not allowed"
+	InvalidFilename        = "The filename is invalid"
+	FileTypeMismatchError  = "The file content does not match its declared type"
+	InvalidImageError      = "The image could not be processed"
+	AnimationTooLarge      = "The animation has too many frames"
+	InvalidImageSize       = "size must be 64, 128, 256 or 512"
+	UploadUrlError         = "The upload url is invalid or expired"
+	UploadIncompleteError  = "The upload is not complete yet"
+	TusVersionError        = "Only version 1.0.0 of the tus protocol is supported"
+	UploadContentTypeError = "Chunks must use the application/offset+octet-stream content type"
+	UploadLengthError      = "Upload-Length must be between 1 and 104857600"
+	UploadMetadataError    = "Upload-Metadata requires a channelId, filename and filetype"
+)
+
+// Channel Errors
+const (
+	OverwriteDMError           = "DM channels do not have permission overwrites"
+	InvalidOverwritePermission = "Only channel permissions can be overwritten"
+	ThreadParentError          = "Threads can only be started in guild channels"
+	ThreadExistsError          = "The message already has a thread"
+	ThreadChannelError         = "Threads are managed through the thread routes"
+	EditThreadError            = "Only the thread owner or a moderator can edit the thread"
 )
@@ -17,6 +17,7 @@ const (
 	Internal             Type = "INTERNAL"             // Server (500) and fallback errors
 	NotFound             Type = "NOTFOUND"             // For not finding resource
 	PayloadTooLarge      Type = "PAYLOADTOOLARGE"      // for uploading tons of JSON, or an image over the limit - 413
+	PreconditionFailed   Type = "PRECONDITIONFAILED"   // for unsupported protocol versions - 412
 	ServiceUnavailable   Type = "SERVICE_UNAVAILABLE"  // For long running handlers
 	UnsupportedMediaType Type = "UNSUPPORTEDMEDIATYPE" // for http 415
 )
@@ -53,6 +54,8 @@ func (e *Error) Status() int {
 		return http.StatusNotFound
 	case PayloadTooLarge:
 		return http.StatusRequestEntityTooLarge
+	case PreconditionFailed:
+		return http.StatusPreconditionFailed
 	case ServiceUnavailable:
 		return http.StatusServiceUnavailable
 	case UnsupportedMediaType:
@@ -125,6 +128,14 @@ func NewPayloadTooLarge(maxBodySize int64, contentLength int64) *Error {
 	}
 }
 
+// NewPreconditionFailed to create an error for 412
+func NewPreconditionFailed(reason string) *Error {
+	return &Error{
+		Type:    PreconditionFailed,
+		Message: reason,
+	}
+}
+
 // NewServiceUnavailable to create an error for 503
 func NewServiceUnavailable() *Error {
 	return &Error{
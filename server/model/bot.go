@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// Bot Settings
+const (
+	// BotLimit is the number of bots a user can own
+	BotLimit = 10
+	// BotEmailDomain is the domain of the placeholder emails of bots
+	BotEmailDomain = "bots.invalid"
+	// BotTokenName is the name of the access token of a bot
+	BotTokenName = "Bot token"
+)
+
+// BotResponse is the API response of a bot for its owner
+type BotResponse struct {
+	Id        string    `json:"id"`
+	Username  string    `json:"username"`
+	Image     string    `json:"image"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// InteractionsUrl receives the interactions as signed requests if set,
+	// otherwise they get sent over the gateway
+	InteractionsUrl *string `json:"interactionsUrl"`
+	// InteractionsSecret is the key of the HMAC-SHA256 signature of the interactions
+	InteractionsSecret string `json:"interactionsSecret"`
+	// Token is only set when the bot gets created or its token reset
+	Token string `json:"token,omitempty"`
+} //@name Bot
+
+// NewBotResponse returns the response of the given bot user
+func NewBotResponse(bot *User, token string) BotResponse {
+	return BotResponse{
+		Id:                 bot.ID,
+		Username:           bot.Username,
+		Image:              bot.Image,
+		CreatedAt:          bot.CreatedAt,
+		UpdatedAt:          bot.UpdatedAt,
+		InteractionsUrl:    bot.InteractionsUrl,
+		InteractionsSecret: bot.InteractionsSecret,
+		Token:              token,
+	}
+}
+
+// BotService defines methods related to bot accounts the handler layer expects
+// any service it interacts with to implement.
+// The bot token is an access token of the bot user with all scopes.
+type BotService interface {
+	CreateBot(owner *User, username string) (*User, string, error)
+	GetBots(ownerId string) (*[]User, error)
+	GetBot(ownerId string, botId string) (*User, error)
+	GetBotById(botId string) (*User, error)
+	UpdateBot(bot *User) error
+	ResetBotToken(bot *User) (string, error)
+	DeleteBot(bot *User) error
+}
@@ -6,15 +6,30 @@ import "time"
 // or a text channel for DMs between users.
 // GuildID should only be nil if it is a DM channel
 // PCMembers should only be used if the channel is private.
+// PermissionOverwrites further restrict or extend the members' permissions in the channel.
+// Threads are channels with a ParentID and inherit the permissions of their parent.
 type Channel struct {
 	BaseModel
-	GuildID      *string   `gorm:"index"`
-	Name         string    `gorm:"name"`
-	IsPublic     bool      `gorm:"index"`
-	IsDM         bool      `gorm:"is_dm"`
-	LastActivity time.Time `gorm:"autoCreateTime"`
-	PCMembers    []User    `gorm:"many2many:pcmembers;constraint:OnDelete:CASCADE;"`
-	Messages     []Message `gorm:"constraint:OnDelete:CASCADE;"`
+	GuildID              *string               `gorm:"index"`
+	Name                 string                `gorm:"name"`
+	IsPublic             bool                  `gorm:"index"`
+	IsDM                 bool                  `gorm:"is_dm"`
+	LastActivity         time.Time             `gorm:"autoCreateTime"`
+	PCMembers            []User                `gorm:"many2many:pcmembers;constraint:OnDelete:CASCADE;"`
+	Messages             []Message             `gorm:"constraint:OnDelete:CASCADE;"`
+	PermissionOverwrites []PermissionOverwrite `gorm:"constraint:OnDelete:CASCADE;"`
+	ParentID             *string               `gorm:"index"`
+	ParentMessageID      *string               `gorm:"uniqueIndex"`
+	Threads              []Channel             `gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE;"`
+	ThreadMembers        []ThreadMember        `gorm:"constraint:OnDelete:CASCADE;"`
+	OwnerID              *string
+	IsArchived           bool
+	AutoArchiveDuration  int
+}
+
+// IsThread returns true if the channel is a thread of another channel
+func (c Channel) IsThread() bool {
+	return c.ParentID != nil
 }
 
 // ChannelResponse is the JSON response of the channel
@@ -58,6 +73,19 @@ type ChannelService interface {
 	RemovePrivateChannelMembers(memberIds []string, channelId string) error
 	IsChannelMember(channel *Channel, userId string) error
 	OpenDMForAll(dmId string) error
+	HasPermission(userId string, channel *Channel, permission Permission) bool
+	GetPermissionOverwrites(channelId string) (*[]PermissionOverwrite, error)
+	SetPermissionOverwrite(overwrite *PermissionOverwrite) error
+	DeletePermissionOverwrite(channelId string, targetId string) error
+	CreateThread(thread *Channel) (*Channel, error)
+	GetThreads(channelId string, archived bool) (*[]ThreadResponse, error)
+	GetThreadByMessage(messageId string) (*Channel, error)
+	GetThreadMembers(threadId string) (*[]string, error)
+	AddThreadMember(threadId string, userId string) error
+	RemoveThreadMember(threadId string, userId string) error
+	ArchiveInactiveThreads() (*[]Channel, error)
+	GetAccessibleChannelIds(userId string, guildId string) ([]string, error)
+	GetDMChannelIds(userId string) ([]string, error)
 }
 
 // ChannelRepository defines methods related to channel db operations the service layer expects
@@ -80,4 +108,17 @@ type ChannelRepository interface {
 	FindDMByUserAndChannelId(channelId, userId string) (string, error)
 	OpenDMForAll(dmId string) error
 	GetDMMemberIds(channelId string) (*[]string, error)
+	GetPermissionOverwrites(channelId string) (*[]PermissionOverwrite, error)
+	GetMemberPermissionOverwrites(userId string, guildId string, channelId string) (*[]PermissionOverwrite, error)
+	GetGuildMemberPermissionOverwrites(userId string, guildId string) (map[string][]PermissionOverwrite, error)
+	SavePermissionOverwrite(overwrite *PermissionOverwrite) error
+	DeletePermissionOverwrite(channelId string, targetId string) error
+	GetThreads(channelId string, archived bool) (*[]ThreadResponse, error)
+	GetThreadByMessageId(messageId string) (*Channel, error)
+	GetThreadMembers(threadId string) (*[]string, error)
+	AddThreadMember(member *ThreadMember) error
+	RemoveThreadMember(threadId string, userId string) error
+	ArchiveInactiveThreads() (*[]Channel, error)
+	GetGuildChannels(guildId string) (*[]Channel, error)
+	GetDMChannelIds(userId string) ([]string, error)
 }
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"context"
+	"time"
+)
+
+// Command Option Types
+const (
+	OptionTypeString  = "string"
+	OptionTypeInteger = "integer"
+	OptionTypeBoolean = "boolean"
+	OptionTypeUser    = "user"
+	OptionTypeChannel = "channel"
+)
+
+// CommandOptionTypes contains all types a command option can have
+var CommandOptionTypes = []string{OptionTypeString, OptionTypeInteger, OptionTypeBoolean, OptionTypeUser, OptionTypeChannel}
+
+// Interaction Response Types
+const (
+	// ResponseTypeMessage posts the message of the bot in the channel and completes the interaction
+	ResponseTypeMessage = "message"
+	// ResponseTypeDeferred tells the channel that the bot is working on the response
+	ResponseTypeDeferred = "deferred"
+)
+
+// Command Settings
+const (
+	// CommandLimit is the number of commands a bot can register
+	CommandLimit = 100
+	// CommandOptionLimit is the number of options a command can have
+	CommandOptionLimit = 25
+	// InteractionTokenExpiry is how long the bot can respond to an interaction
+	InteractionTokenExpiry = 15 * time.Minute
+	// InteractionDeliveryTimeout is how long the interactions url of a bot has to accept an interaction
+	InteractionDeliveryTimeout = 3 * time.Second
+	// InteractionSignatureHeader contains the hex encoded HMAC-SHA256 of the timestamp, a dot and the body
+	InteractionSignatureHeader = "X-Signature-256"
+	// InteractionTimestampHeader contains the unix time the interaction got sent at
+	InteractionTimestampHeader = "X-Signature-Timestamp"
+)
+
+// Command is an application command of a bot members invoke in guild channels.
+// Global commands can be used in every guild of the bot and
+// guild commands only in the guild they got registered for.
+type Command struct {
+	BaseModel
+	BotId       string          `gorm:"not null;index" json:"botId"`
+	GuildId     *string         `gorm:"index" json:"guildId"`
+	Name        string          `gorm:"not null" json:"name"`
+	Description string          `gorm:"not null" json:"description"`
+	Options     []CommandOption `gorm:"type:jsonb;serializer:json" json:"options"`
+} //@name Command
+
+// CommandOption is a typed argument of a command
+type CommandOption struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// One of string, integer, boolean, user or channel
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+} //@name CommandOption
+
+// IsAvailableIn reports whether the command can be used in the given guild
+func (c *Command) IsAvailableIn(guildId string) bool {
+	return c.GuildId == nil || *c.GuildId == guildId
+}
+
+// Interaction is a command invocation the bot of the command receives
+// as an interaction_create event, either over the gateway or on its interactions url.
+// The token authenticates the response of the bot and expires after InteractionTokenExpiry.
+type Interaction struct {
+	Id          string              `json:"id"`
+	Token       string              `json:"token,omitempty"`
+	BotId       string              `json:"botId"`
+	CommandId   string              `json:"commandId"`
+	CommandName string              `json:"commandName"`
+	GuildId     string              `json:"guildId"`
+	ChannelId   string              `json:"channelId"`
+	User        MemberResponse      `json:"user"`
+	Options     []InteractionOption `json:"options"`
+	Deferred    bool                `json:"deferred"`
+	CreatedAt   time.Time           `json:"createdAt"`
+	ExpiresAt   time.Time           `json:"expiresAt"`
+} //@name Interaction
+
+// InteractionOption is the value of a command option the user provided.
+// Integers are numbers, booleans are booleans and all other types strings.
+type InteractionOption struct {
+	Name  string `json:"name"`
+	Value any    `json:"value"`
+} //@name InteractionOption
+
+// WithoutToken returns a copy of the interaction that can be shown to other users
+func (i *Interaction) WithoutToken() *Interaction {
+	interaction := *i
+	interaction.Token = ""
+	return &interaction
+}
+
+// InteractionService defines methods related to commands and interactions the handler layer expects
+// any service it interacts with to implement
+type InteractionService interface {
+	GetCommands(botId string) (*[]Command, error)
+	GetGuildCommands(guildId string) (*[]Command, error)
+	GetCommand(commandId string) (*Command, error)
+	CreateCommand(command *Command) error
+	UpdateCommand(command *Command) error
+	DeleteCommand(command *Command) error
+	CreateInteraction(ctx context.Context, command *Command, channel *Channel, user *MemberResponse, options []InteractionOption) (*Interaction, error)
+	DeliverInteraction(bot *User, interaction *Interaction) error
+	GetInteraction(ctx context.Context, interactionId string, token string) (*Interaction, error)
+	DeferInteraction(ctx context.Context, interaction *Interaction) error
+	CompleteInteraction(ctx context.Context, interaction *Interaction) error
+}
+
+// CommandRepository defines methods related to command db operations the service layer expects
+// any repository it interacts with to implement
+type CommandRepository interface {
+	FindByID(id string) (*Command, error)
+	FindByBot(botId string) (*[]Command, error)
+	FindByGuild(guildId string) (*[]Command, error)
+	FindByName(botId string, guildId *string, name string) (*Command, error)
+	CountByBot(botId string) (int64, error)
+	Create(command *Command) error
+	Update(command *Command) error
+	Delete(commandId string) error
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EmailVerification is the user and the email address a verification token got sent for
+type EmailVerification struct {
+	UserId string `json:"userId"`
+	Email  string `json:"email"`
+}
+
+// Actions that deployments can restrict to users with a verified email address
+const (
+	RestrictJoinGuilds     = "guilds"
+	RestrictFriendRequests = "friends"
+)
+
+// UnverifiedRestrictions are the actions that require a verified email address
+type UnverifiedRestrictions map[string]bool
+
+// ParseUnverifiedRestrictions parses a comma separated list of restricted actions,
+// e.g. "guilds,friends". An empty list does not restrict unverified users.
+func ParseUnverifiedRestrictions(list string) (UnverifiedRestrictions, error) {
+	restrictions := make(UnverifiedRestrictions)
+
+	for _, action := range strings.Split(list, ",") {
+		action = strings.ToLower(strings.TrimSpace(action))
+
+		switch action {
+		case "":
+			continue
+		case RestrictJoinGuilds, RestrictFriendRequests:
+			restrictions[action] = true
+		default:
+			return nil, fmt.Errorf("unknown restriction: %s", action)
+		}
+	}
+
+	return restrictions, nil
+}
+
+// Restricts reports whether the user has to verify their email address before performing the action
+func (r UnverifiedRestrictions) Restricts(user *User, action string) bool {
+	return r[action] && !user.IsEmailVerified()
+}
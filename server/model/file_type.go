@@ -0,0 +1,135 @@
+package model
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// FileTypes maps the allowed mime types of uploaded files to their maximum size in bytes.
+// Use NormalizeFileType for the keys, so aliases like audio/mp3 resolve to the same type.
+type FileTypes map[string]int64
+
+// DefaultFileTypes returns the file types that are allowed
+// if the deployment does not configure its own allowlist
+func DefaultFileTypes() FileTypes {
+	return FileTypes{
+		"image/jpeg":      8 << 20,
+		"image/png":       8 << 20,
+		"image/gif":       8 << 20,
+		"image/webp":      8 << 20,
+		"audio/mpeg":      25 << 20,
+		"audio/wav":       25 << 20,
+		"video/mp4":       MaximumUploadSize,
+		"video/webm":      MaximumUploadSize,
+		"application/pdf": 25 << 20,
+		"text/plain":      8 << 20,
+	}
+}
+
+// ParseFileTypes parses a comma separated allowlist of mime types and their maximum size in bytes,
+// e.g. "image/png=8388608,application/pdf". Types without a size can be up to MaximumUploadSize.
+// An empty list returns the DefaultFileTypes.
+func ParseFileTypes(list string) (FileTypes, error) {
+	if strings.TrimSpace(list) == "" {
+		return DefaultFileTypes(), nil
+	}
+
+	fileTypes := make(FileTypes)
+
+	for _, entry := range strings.Split(list, ",") {
+		fileType, size, hasSize := strings.Cut(strings.TrimSpace(entry), "=")
+		fileType = NormalizeFileType(fileType)
+
+		if fileType == "" {
+			continue
+		}
+
+		maxSize := int64(MaximumUploadSize)
+
+		if hasSize {
+			parsed, err := strconv.ParseInt(strings.TrimSpace(size), 10, 64)
+
+			if err != nil || parsed < 1 || parsed > MaximumUploadSize {
+				return nil, fmt.Errorf("invalid size for file type %s: %s", fileType, size)
+			}
+
+			maxSize = parsed
+		}
+
+		fileTypes[fileType] = maxSize
+	}
+
+	return fileTypes, nil
+}
+
+// MaxSize returns the maximum size of the given file type
+// and whether the type is allowed at all
+func (f FileTypes) MaxSize(fileType string) (int64, bool) {
+	size, ok := f[NormalizeFileType(fileType)]
+	return size, ok
+}
+
+// NormalizeFileType strips the parameters of the given mime type
+// and resolves known aliases to their common type
+func NormalizeFileType(fileType string) string {
+	mediaType, _, err := mime.ParseMediaType(fileType)
+
+	if err != nil {
+		return ""
+	}
+
+	if known := mimetype.Lookup(mediaType); known != nil {
+		mediaType, _, _ = mime.ParseMediaType(known.String())
+	}
+
+	return mediaType
+}
+
+// IsInlineFileType reports whether files of the given type can be shown in the browser.
+// These are images, videos and audio files except for svg images, which can contain scripts.
+func IsInlineFileType(fileType string) bool {
+	fileType = NormalizeFileType(fileType)
+
+	if fileType == "image/svg+xml" {
+		return false
+	}
+
+	return strings.HasPrefix(fileType, "image/") ||
+		strings.HasPrefix(fileType, "video/") ||
+		strings.HasPrefix(fileType, "audio/")
+}
+
+// equivalentFileTypes lists the detected types that are accepted for a declared type
+// besides the type itself, e.g. csv files are plain text
+var equivalentFileTypes = map[string][]string{
+	"text/plain": {"text/csv", "text/tab-separated-values"},
+}
+
+// MatchesFileType detects the type of the file from its first bytes and reports
+// whether it is the given type or one of its listed equivalents
+func MatchesFileType(file io.Reader, fileType string) (bool, error) {
+	detected, err := mimetype.DetectReader(file)
+
+	if err != nil {
+		return false, err
+	}
+
+	fileType = NormalizeFileType(fileType)
+
+	if detected.Is(fileType) {
+		return true, nil
+	}
+
+	for _, equivalent := range equivalentFileTypes[fileType] {
+		if detected.Is(equivalent) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
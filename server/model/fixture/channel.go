@@ -39,3 +39,24 @@ func GetMockDMChannel() *model.Channel {
 		LastActivity: time.Now(),
 	}
 }
+
+// GetMockThread returns a mock thread of the given parent channel that got started by the given owner.
+func GetMockThread(parent *model.Channel, ownerId string) *model.Channel {
+	messageId := RandID()
+
+	return &model.Channel{
+		BaseModel: model.BaseModel{
+			ID:        RandID(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		GuildID:             parent.GuildID,
+		Name:                RandStr(8),
+		IsPublic:            true,
+		LastActivity:        time.Now(),
+		ParentID:            &parent.ID,
+		ParentMessageID:     &messageId,
+		OwnerID:             &ownerId,
+		AutoArchiveDuration: model.ArchiveAfterDay,
+	}
+}
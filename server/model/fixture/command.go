@@ -0,0 +1,25 @@
+package fixture
+
+import (
+	"github.com/sentrionic/valkyrie/model"
+	"time"
+)
+
+// GetMockCommand returns a global mock command of the given bot with
+// a required string option and an optional integer option
+func GetMockCommand(botId string) *model.Command {
+	return &model.Command{
+		BaseModel: model.BaseModel{
+			ID:        RandID(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		BotId:       botId,
+		Name:        "roll",
+		Description: RandStringRunes(20),
+		Options: []model.CommandOption{
+			{Name: "dice", Description: RandStringRunes(10), Type: model.OptionTypeString, Required: true},
+			{Name: "times", Description: RandStringRunes(10), Type: model.OptionTypeInteger},
+		},
+	}
+}
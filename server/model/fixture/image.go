@@ -0,0 +1,152 @@
+package fixture
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+)
+
+// ExifGPSMarker is part of the EXIF segment of NewExifJpeg to check if the metadata got removed
+const ExifGPSMarker = "GPS 52.5200N 13.4050E"
+
+// NewExifJpeg returns a jpeg image with the given dimensions and an EXIF segment
+// containing the orientation and the ExifGPSMarker
+func NewExifJpeg(width, height int, orientation uint16) []byte {
+	buf := new(bytes.Buffer)
+	_ = jpeg.Encode(buf, image.NewRGBA(image.Rect(0, 0, width, height)), nil)
+
+	// Big endian TIFF header followed by an IFD with the orientation tag
+	exif := []byte("Exif\x00\x00MM\x00\x2A\x00\x00\x00\x08")
+	exif = binary.BigEndian.AppendUint16(exif, 1)
+	exif = binary.BigEndian.AppendUint16(exif, 0x0112)
+	exif = binary.BigEndian.AppendUint16(exif, 3)
+	exif = binary.BigEndian.AppendUint32(exif, 1)
+	exif = binary.BigEndian.AppendUint16(exif, orientation)
+	exif = binary.BigEndian.AppendUint16(exif, 0)
+	exif = binary.BigEndian.AppendUint32(exif, 0)
+	exif = append(exif, ExifGPSMarker...)
+
+	// The APP1 segment follows the start of image marker
+	content := []byte{0xFF, 0xD8, 0xFF, 0xE1}
+	content = binary.BigEndian.AppendUint16(content, uint16(len(exif)+2))
+	content = append(content, exif...)
+
+	return append(content, buf.Bytes()[2:]...)
+}
+
+// NewAnimatedGif returns an animated gif image with the given dimensions
+// that shows a frame filled with each of the colors
+func NewAnimatedGif(width, height int, colors ...color.Color) []byte {
+	g := &gif.GIF{}
+
+	for _, c := range colors {
+		g.Image = append(g.Image, image.NewPaletted(image.Rect(0, 0, width, height), color.Palette{c}))
+		g.Delay = append(g.Delay, 10)
+	}
+
+	buf := new(bytes.Buffer)
+	_ = gif.EncodeAll(buf, g)
+
+	return buf.Bytes()
+}
+
+// NewAnimatedWebp returns an animated webp image with the given dimensions
+// that shows a lossless frame filled with each of the colors
+func NewAnimatedWebp(width, height int, colors ...color.NRGBA) []byte {
+	size := make([]byte, 0, 6)
+	size = appendUint24(size, width-1)
+	size = appendUint24(size, height-1)
+
+	// Animation and alpha flags followed by the canvas size
+	body := appendChunk(nil, "VP8X", append([]byte{0x12, 0, 0, 0}, size...))
+	// Transparent background color and endless loop
+	body = appendChunk(body, "ANIM", []byte{0, 0, 0, 0, 0, 0})
+
+	for _, c := range colors {
+		// Frame at 0,0 with the canvas size, a delay of 100ms and without blending
+		frame := append([]byte{0, 0, 0, 0, 0, 0}, size...)
+		frame = appendUint24(frame, 100)
+		frame = append(frame, 0x02)
+		frame = appendChunk(frame, "VP8L", solidVP8L(width, height, c))
+
+		body = appendChunk(body, "ANMF", frame)
+	}
+
+	return appendChunk(nil, "RIFF", append([]byte("WEBP"), body...))
+}
+
+// solidVP8L returns a lossless webp bitstream filled with the given color.
+// Every Huffman code only has a single symbol, so the pixels do not take up any bits.
+func solidVP8L(width, height int, c color.NRGBA) []byte {
+	w := &bitWriter{}
+	w.write(0x2F, 8)
+	w.write(uint64(width-1), 14)
+	w.write(uint64(height-1), 14)
+	w.write(1, 1) // Alpha hint
+	w.write(0, 3) // Version
+	w.write(0, 1) // No transforms
+	w.write(0, 1) // No color cache
+	w.write(0, 1) // No meta Huffman codes
+
+	// Green, red, blue and alpha use a single 8-bit symbol
+	for _, symbol := range []uint8{c.G, c.R, c.B, c.A} {
+		w.write(1, 1)
+		w.write(0, 1)
+		w.write(1, 1)
+		w.write(uint64(symbol), 8)
+	}
+
+	// Distance uses a single 1-bit symbol
+	w.write(1, 1)
+	w.write(0, 1)
+	w.write(0, 1)
+	w.write(0, 1)
+
+	return w.bytes()
+}
+
+// bitWriter writes bits starting at the least significant bit
+type bitWriter struct {
+	buf  []byte
+	acc  uint64
+	bits uint
+}
+
+func (w *bitWriter) write(value uint64, bits uint) {
+	w.acc |= value << w.bits
+	w.bits += bits
+
+	for w.bits >= 8 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.bits -= 8
+	}
+}
+
+func (w *bitWriter) bytes() []byte {
+	if w.bits > 0 {
+		return append(w.buf, byte(w.acc))
+	}
+	return w.buf
+}
+
+// appendChunk appends a RIFF chunk with its size and padding
+func appendChunk(b []byte, id string, data []byte) []byte {
+	b = append(b, id...)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(data)))
+	b = append(b, data...)
+
+	if len(data)%2 == 1 {
+		b = append(b, 0)
+	}
+
+	return b
+}
+
+// appendUint24 appends a 24-bit little-endian integer
+func appendUint24(b []byte, v int) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16))
+}
@@ -20,10 +20,9 @@ func GetMockMessage(uid, cid string) *model.Message {
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		},
-		Text:       &text,
-		UserId:     ownerId,
-		ChannelId:  cid,
-		Attachment: nil,
+		Text:      &text,
+		UserId:    ownerId,
+		ChannelId: cid,
 	}
 }
 
@@ -33,11 +32,11 @@ func GetMockMessageResponse(uid, cid string) *model.MessageResponse {
 	user := GetMockUser()
 
 	return &model.MessageResponse{
-		Id:         message.ID,
-		Text:       message.Text,
-		CreatedAt:  message.CreatedAt,
-		UpdatedAt:  message.UpdatedAt,
-		Attachment: message.Attachment,
+		Id:          message.ID,
+		Text:        message.Text,
+		CreatedAt:   message.CreatedAt,
+		UpdatedAt:   message.UpdatedAt,
+		Attachments: make([]model.Attachment, 0),
 		User: model.MemberResponse{
 			Id:        user.ID,
 			Username:  user.Username,
@@ -48,6 +47,9 @@ func GetMockMessageResponse(uid, cid string) *model.MessageResponse {
 			Nickname:  nil,
 			Color:     nil,
 			IsFriend:  false,
+			Bot:       user.Bot,
 		},
+		Reactions: make([]model.ReactionResponse, 0),
+		Mentions:  model.NewMessageMentions(),
 	}
 }
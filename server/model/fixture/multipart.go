@@ -90,6 +90,7 @@ func createImage(imagePath string) *os.File {
 
 	f, _ := os.Create(imagePath)
 	_ = png.Encode(f, img)
+	_, _ = f.Seek(0, io.SeekStart)
 
 	return f
 }
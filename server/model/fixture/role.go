@@ -0,0 +1,19 @@
+package fixture
+
+import (
+	"github.com/sentrionic/valkyrie/model"
+	"time"
+)
+
+// GetMockRole returns a mock role without any permissions for the given guild.
+func GetMockRole(guildId string) *model.Role {
+	return &model.Role{
+		BaseModel: model.BaseModel{
+			ID:        RandID(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		GuildId: guildId,
+		Name:    RandStr(8),
+	}
+}
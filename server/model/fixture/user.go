@@ -20,3 +20,11 @@ func GetMockUser() *model.User {
 		Image:    generateAvatar(email),
 	}
 }
+
+// GetMockBot returns a mock bot owned by the given user
+func GetMockBot(ownerId string) *model.User {
+	bot := GetMockUser()
+	bot.Bot = true
+	bot.OwnerId = &ownerId
+	return bot
+}
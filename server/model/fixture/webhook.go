@@ -0,0 +1,39 @@
+package fixture
+
+import (
+	"fmt"
+	"github.com/sentrionic/valkyrie/model"
+	"time"
+)
+
+// GetMockWebhook returns a mock webhook of the given channel and its bot user
+func GetMockWebhook(channel *model.Channel) (*model.Webhook, *model.User) {
+	id := RandID()
+	name := Username()
+	email := fmt.Sprintf("%s@%s", id, model.WebhookEmailDomain)
+	image := generateAvatar(email)
+
+	webhook := &model.Webhook{
+		BaseModel: model.BaseModel{
+			ID:        id,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		ChannelId: channel.ID,
+		GuildId:   *channel.GuildID,
+		CreatorId: RandID(),
+		Name:      name,
+		Image:     image,
+		TokenHash: RandStringRunes(64),
+	}
+
+	user := &model.User{
+		BaseModel: webhook.BaseModel,
+		Username:  name,
+		Email:     email,
+		Image:     image,
+		Bot:       true,
+	}
+
+	return webhook, user
+}
@@ -17,6 +17,7 @@ type Guild struct {
 	Channels    []Channel      `gorm:"constraint:OnDelete:CASCADE;"`
 	Bans        []User         `gorm:"many2many:bans;constraint:OnDelete:CASCADE;"`
 	VCMembers   []User         `gorm:"many2many:vc_members;constraint:OnDelete:CASCADE;"`
+	Roles       []Role         `gorm:"constraint:OnDelete:CASCADE;"`
 }
 
 // GuildResponse contains all info to display a guild.
@@ -73,6 +74,16 @@ type GuildService interface {
 	RemoveVCMember(userId, guildId string) error
 	UpdateVCMember(isMuted, isDeafened bool, userId, guildId string) error
 	GetVCMember(userId, guildId string) (*VCMember, error)
+	HasPermission(userId string, guildId string, permission Permission) bool
+	OutranksMember(userId, memberId, guildId string) bool
+	OutranksRole(userId string, role *Role) bool
+	GetRoles(guildId string) (*[]RoleResponse, error)
+	GetRole(roleId string) (*Role, error)
+	CreateRole(role *Role) (*Role, error)
+	UpdateRole(role *Role) error
+	DeleteRole(roleId string) error
+	AddMemberRole(userId, guildId, roleId string) error
+	RemoveMemberRole(userId, guildId, roleId string) error
 }
 
 // GuildRepository defines methods related to guild db operations the service layer expects
@@ -98,4 +109,13 @@ type GuildRepository interface {
 	GetMemberIds(guildId string) (*[]string, error)
 	UpdateVCMember(isMuted, isDeafened bool, userId, guildId string) error
 	GetVCMember(userId, guildId string) (*VCMember, error)
+	GetMemberPermissions(userId, guildId string) (Permission, error)
+	GetMemberPosition(userId, guildId string) (int, error)
+	GetRoles(guildId string) (*[]RoleResponse, error)
+	FindRoleByID(roleId string) (*Role, error)
+	CreateRole(role *Role) (*Role, error)
+	SaveRole(role *Role) error
+	DeleteRole(roleId string) error
+	AddMemberRole(memberRole *MemberRole) error
+	RemoveMemberRole(memberRole *MemberRole) error
 }
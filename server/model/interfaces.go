@@ -2,21 +2,46 @@ package model
 
 import (
 	"context"
+	"io"
 	"mime/multipart"
+	"os"
 )
 
 // FileRepository defines methods related to file upload the service layer expects
-// any repository it interacts with to implement
+// any repository it interacts with to implement.
+// The upload methods return the url of the file which DeleteImage takes to remove it again.
+// UploadAvatar stores the avatar in all the ImageSizes and ImageUrl returns the url of one of them.
+// PresignUpload returns a url the client can upload the file to directly followed by the url of the file.
+// UploadChunk returns the url and size of the stored chunk and MergeChunks concatenates chunks into a new file.
 type FileRepository interface {
 	UploadAvatar(header *multipart.FileHeader, directory string) (string, error)
-	UploadFile(header *multipart.FileHeader, directory, filename, mimetype string) (string, error)
-	DeleteImage(key string) error
+	ImageUrl(path string, size int) (string, error)
+	UploadFile(file io.Reader, directory, filename, mimetype string) (string, error)
+	UploadThumbnail(file io.Reader, directory, filename string) (string, error)
+	PresignUpload(directory, filename, mimetype string, size int64) (string, string, error)
+	OpenFile(url string) (io.ReadCloser, int64, error)
+	UploadChunk(directory, filename string, chunk io.Reader) (string, int64, error)
+	MergeChunks(urls []string, directory, filename, mimetype string) (string, error)
+	DeleteImage(url string) error
+}
+
+// FileServer defines methods related to serving stored files the handler layer expects
+// any storage driver without its own file hosting to implement
+type FileServer interface {
+	Open(key, signature string) (*os.File, string, error)
+	Put(key, mimetype string, size, expires int64, signature string, body io.Reader) error
 }
 
 // MailRepository defines methods related to mail operations the service layer expects
 // any repository it interacts with to implement
 type MailRepository interface {
-	SendResetMail(email string, html string) error
+	SendResetMail(email string, token string) error
+	SendVerificationMail(email string, token string) error
+}
+
+// MailTransport defines how the mail drivers the MailRepository uses deliver rendered mails
+type MailTransport interface {
+	Send(mail *Mail) error
 }
 
 // RedisRepository defines methods related to the redis db the service layer expects
@@ -24,7 +49,19 @@ type MailRepository interface {
 type RedisRepository interface {
 	SetResetToken(ctx context.Context, id string) (string, error)
 	GetIdFromToken(ctx context.Context, token string) (string, error)
+	SetVerificationToken(ctx context.Context, id string, email string) (string, error)
+	GetVerificationToken(ctx context.Context, token string) (*EmailVerification, error)
+	SetPendingLogin(ctx context.Context, id string) (string, error)
+	GetPendingLogin(ctx context.Context, ticket string) (string, error)
+	SaveSession(ctx context.Context, userId string, session *Session) error
+	GetSession(ctx context.Context, userId string, id string) (*Session, error)
+	GetSessions(ctx context.Context, userId string) ([]Session, error)
+	DeleteSessions(ctx context.Context, userId string, ids ...string) error
 	SaveInvite(ctx context.Context, guildId string, id string, isPermanent bool) error
 	GetInvite(ctx context.Context, token string) (string, error)
 	InvalidateInvites(ctx context.Context, guild *Guild)
+	SaveInteraction(ctx context.Context, interaction *Interaction) error
+	GetInteraction(ctx context.Context, token string) (*Interaction, error)
+	UpdateInteraction(ctx context.Context, interaction *Interaction) error
+	ConsumeInteraction(ctx context.Context, token string) (*Interaction, error)
 }
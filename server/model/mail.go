@@ -0,0 +1,10 @@
+package model
+
+// Mail is a rendered email with an HTML and a plain text version of its body
+type Mail struct {
+	From    string
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+}
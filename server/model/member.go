@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"github.com/lib/pq"
+	"time"
+)
 
 // Member represents a user in a guild and is the join table between
 // User and Guild.
@@ -22,16 +25,19 @@ type VCMember struct {
 }
 
 // MemberResponse is the API response of a member.
+// Roles contains the IDs of the member's roles and is only set for guild member lists.
 type MemberResponse struct {
-	Id        string    `json:"id"`
-	Username  string    `json:"username"`
-	Image     string    `json:"image"`
-	IsOnline  bool      `json:"isOnline"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
-	Nickname  *string   `json:"nickname"`
-	Color     *string   `json:"color"`
-	IsFriend  bool      `json:"isFriend"`
+	Id        string         `json:"id"`
+	Username  string         `json:"username"`
+	Image     string         `json:"image"`
+	IsOnline  bool           `json:"isOnline"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	Nickname  *string        `json:"nickname"`
+	Color     *string        `json:"color"`
+	IsFriend  bool           `json:"isFriend"`
+	Bot       bool           `json:"bot"`
+	Roles     pq.StringArray `gorm:"type:text[]" json:"roles,omitempty"`
 } //@name Member
 
 // BanResponse is the API response of a banned member.
@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// Mention Types
+const (
+	MentionTypeUser     = "user"
+	MentionTypeRole     = "role"
+	MentionTypeEveryone = "everyone"
+)
+
+// Mention represents a user, role or @everyone mention in a message.
+// TargetId is the ID of the user or role and empty for @everyone.
+type Mention struct {
+	MessageId string `gorm:"primaryKey;constraint:OnDelete:CASCADE;"`
+	Type      string `gorm:"primaryKey"`
+	TargetId  string `gorm:"primaryKey;index"`
+	CreatedAt time.Time
+}
+
+// MessageMentions contains the resolved mentions of a message.
+// Mentions of unknown users and of roles from other guilds are left out.
+type MessageMentions struct {
+	Users    []MentionedUser `json:"users"`
+	Roles    []MentionedRole `json:"roles"`
+	Everyone bool            `json:"everyone"`
+} //@name MessageMentions
+
+// MentionedUser is a user mentioned in a message
+type MentionedUser struct {
+	Id       string  `json:"id"`
+	Username string  `json:"username"`
+	Nickname *string `json:"nickname"`
+} //@name MentionedUser
+
+// MentionedRole is a role mentioned in a message
+type MentionedRole struct {
+	Id    string  `json:"id"`
+	Name  string  `json:"name"`
+	Color *string `json:"color"`
+} //@name MentionedRole
+
+// NewMessageMentions returns mentions without any users or roles
+func NewMessageMentions() MessageMentions {
+	return MessageMentions{
+		Users: make([]MentionedUser, 0),
+		Roles: make([]MentionedRole, 0),
+	}
+}
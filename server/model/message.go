@@ -1,59 +1,204 @@
 package model
 
 import (
+	"io"
 	"mime/multipart"
 	"time"
 )
 
 // Message represents a text message in a channel.
-// It may contain an Attachment that is displayed instead of text.
+// It may contain up to MaximumAttachments attachments that are displayed below the text.
+// Attachments of finalized uploads keep the ID of their Upload.
+// ReplyToId references the message it replies to, which may have been deleted since.
+// PinnedAt is set if the message is pinned to its channel.
+// EditCount is the number of times the text got edited, see MessageRevision.
 type Message struct {
 	BaseModel
-	Text       *string
-	UserId     string      `gorm:"index;constraint:OnDelete:CASCADE;"`
-	ChannelId  string      `gorm:"index;constraint:OnDelete:CASCADE;"`
-	Attachment *Attachment `gorm:"constraint:OnDelete:CASCADE;"`
+	Text        *string
+	UserId      string            `gorm:"index;constraint:OnDelete:CASCADE;"`
+	ChannelId   string            `gorm:"index;constraint:OnDelete:CASCADE;"`
+	ReplyToId   *string           `gorm:"index"`
+	PinnedAt    *time.Time        `gorm:"index"`
+	EditCount   int               `gorm:"not null;default:0"`
+	Attachments []Attachment      `gorm:"constraint:OnDelete:CASCADE;"`
+	Reactions   []Reaction        `gorm:"constraint:OnDelete:CASCADE;"`
+	Mentions    []Mention         `gorm:"constraint:OnDelete:CASCADE;"`
+	Revisions   []MessageRevision `gorm:"constraint:OnDelete:CASCADE;"`
+}
+
+// MessagePage contains the pagination options for the messages of a channel.
+// At most one of the Before, After and Around message IDs is set.
+// Without a cursor the most recent messages are returned.
+type MessagePage struct {
+	Before *string
+	After  *string
+	Around *string
+	Limit  int
 }
 
 // MessageResponse is the API response of a Message
 type MessageResponse struct {
-	Id         string         `json:"id"`
-	Text       *string        `json:"text"`
-	CreatedAt  time.Time      `json:"createdAt"`
-	UpdatedAt  time.Time      `json:"updatedAt"`
-	Attachment *Attachment    `json:"attachment"`
-	User       MemberResponse `json:"user"`
+	Id          string             `json:"id"`
+	Text        *string            `json:"text"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	UpdatedAt   time.Time          `json:"updatedAt"`
+	Attachments []Attachment       `json:"attachments"`
+	User        MemberResponse     `json:"user"`
+	Reactions   []ReactionResponse `json:"reactions"`
+	ReplyTo     *MessageReference  `json:"replyTo"`
+	ThreadId    *string            `json:"threadId"`
+	Pinned      bool               `json:"pinned"`
+	Mentions    MessageMentions    `json:"mentions"`
+	Edited      bool               `json:"edited"`
+	EditCount   int                `json:"editCount"`
 } //@name Message
 
-// Attachment represents a message attachment that displays
-// a file instead of text.
+// MessageRevision is a previous text of an edited message.
+// Revision counts up from 1, which is the original text of the message.
+// CreatedAt is when the text was written and EditedAt when it got replaced.
+type MessageRevision struct {
+	MessageId string    `gorm:"primaryKey;constraint:OnDelete:CASCADE;" json:"-"`
+	Revision  int       `gorm:"primaryKey;autoIncrement:false" json:"revision"`
+	Text      *string   `json:"text"`
+	CreatedAt time.Time `json:"createdAt"`
+	EditedAt  time.Time `json:"editedAt"`
+} //@name MessageRevision
+
+// PinEvent is the websocket payload sent when a message gets pinned or unpinned
+type PinEvent struct {
+	MessageId string `json:"messageId"`
+	ChannelId string `json:"channelId"`
+	UserId    string `json:"userId"`
+} //@name PinEvent
+
+// MessageReference is a compact snapshot of the message a reply refers to.
+// If the referenced message got deleted only Id and Deleted are set.
+type MessageReference struct {
+	Id            string           `json:"id"`
+	Text          *string          `json:"text"`
+	HasAttachment bool             `json:"hasAttachment"`
+	Deleted       bool             `json:"deleted"`
+	User          *ReferenceAuthor `json:"user"`
+} //@name MessageReference
+
+// ReferenceAuthor is the author of a referenced message
+type ReferenceAuthor struct {
+	Id       string  `json:"id"`
+	Username string  `json:"username"`
+	Image    string  `json:"image"`
+	Nickname *string `json:"nickname"`
+	Color    *string `json:"color"`
+} //@name ReferenceAuthor
+
+// NewMessageReference returns the snapshot of the referenced message.
+// The text gets truncated to ReferenceTextLength characters.
+func NewMessageReference(id string, text *string, hasAttachment bool, author *ReferenceAuthor) *MessageReference {
+	if text != nil {
+		runes := []rune(*text)
+		if len(runes) > ReferenceTextLength {
+			truncated := string(runes[:ReferenceTextLength]) + "…"
+			text = &truncated
+		}
+	}
+
+	return &MessageReference{
+		Id:            id,
+		Text:          text,
+		HasAttachment: hasAttachment,
+		User:          author,
+	}
+}
+
+// DeletedMessageReference returns the reference of a message that no longer exists
+func DeletedMessageReference(id string) *MessageReference {
+	return &MessageReference{
+		Id:      id,
+		Deleted: true,
+	}
+}
+
+// MentionNotification is the websocket payload sent to a user that got pinged in a message
+type MentionNotification struct {
+	GuildId   *string          `json:"guildId"`
+	ChannelId string           `json:"channelId"`
+	Message   *MessageResponse `json:"message"`
+} //@name MentionNotification
+
+// Attachment represents a file attached to a message.
+// Width, Height and ThumbnailUrl are only set for images and Duration only for audio files.
+// Hash is the hex encoded SHA-256 hash of the file content.
 type Attachment struct {
-	ID        string    `gorm:"primaryKey" json:"-"`
-	CreatedAt time.Time `json:"-"`
-	UpdatedAt time.Time `json:"-"`
-	Url       string    `json:"url"`
-	FileType  string    `json:"filetype"`
-	Filename  string    `json:"filename"`
-	MessageId string    `gorm:"index;constraint:OnDelete:CASCADE;" json:"-"`
+	ID           string    `gorm:"primaryKey" json:"-"`
+	CreatedAt    time.Time `json:"-"`
+	UpdatedAt    time.Time `json:"-"`
+	Url          string    `json:"url"`
+	FileType     string    `json:"filetype"`
+	Filename     string    `json:"filename"`
+	Size         int64     `json:"size"`
+	Width        *int      `json:"width"`
+	Height       *int      `json:"height"`
+	Duration     *float64  `json:"duration"`
+	Hash         string    `json:"hash"`
+	ThumbnailUrl *string   `json:"thumbnailUrl"`
+	Position     int       `json:"-"`
+	MessageId    string    `gorm:"index;constraint:OnDelete:CASCADE;" json:"-"`
 } //@name Attachment
 
 // MessageService defines methods related to message operations the handler layer expects
 // any service it interacts with to implement
 type MessageService interface {
-	GetMessages(userId string, channel *Channel, cursor string) (*[]MessageResponse, error)
+	GetMessages(userId string, channel *Channel, page *MessagePage) (*[]MessageResponse, error)
 	CreateMessage(params *Message) (*Message, error)
 	UpdateMessage(message *Message) error
 	DeleteMessage(message *Message) error
 	UploadFile(header *multipart.FileHeader, channelId string) (*Attachment, error)
+	CreateUpload(upload *Upload) (string, error)
+	GetUpload(uploadId string) (*Upload, error)
+	FinalizeUpload(upload *Upload) (*Attachment, error)
+	CreateResumableUpload(upload *Upload) error
+	AppendUploadChunk(upload *Upload, chunk io.Reader) error
+	TerminateUpload(upload *Upload) error
+	DeleteExpiredUploads() error
 	Get(messageId string) (*Message, error)
+	AddReaction(reaction *Reaction) error
+	RemoveReaction(messageId, userId, emoji string) error
+	GetReactionUsers(channel *Channel, messageId, emoji string) (*[]MemberResponse, error)
+	GetMessageReactions(messageId string) ([]ReactionResponse, error)
+	GetPinnedMessages(userId string, channel *Channel) (*[]MessageResponse, error)
+	PinMessage(message *Message) error
+	UnpinMessage(message *Message) error
+	GetMessageMentions(channel *Channel, messageId string) (*MessageMentions, error)
+	GetMentionedUsers(channel *Channel, message *Message) ([]string, error)
+	GetUserMentions(userId string) (*[]MentionNotification, error)
+	SearchMessages(userId string, channelIds []string, search *MessageSearch) (*MessageSearchResponse, error)
+	GetMessageRevisions(messageId string) (*[]MessageRevision, error)
 }
 
 // MessageRepository defines methods related message db operations the service layer expects
 // any repository it interacts with to implement
 type MessageRepository interface {
-	GetMessages(userId string, channel *Channel, cursor string) (*[]MessageResponse, error)
+	GetMessages(userId string, channel *Channel, page *MessagePage) (*[]MessageResponse, error)
 	CreateMessage(params *Message) (*Message, error)
 	UpdateMessage(message *Message) error
 	DeleteMessage(message *Message) error
 	GetById(messageId string) (*Message, error)
+	GetReactionEmojis(messageId string) ([]string, error)
+	AddReaction(reaction *Reaction) error
+	RemoveReaction(messageId, userId, emoji string) error
+	GetReactionUsers(channel *Channel, messageId, emoji string) (*[]MemberResponse, error)
+	GetMessageReactions(messageId string) ([]ReactionResponse, error)
+	GetPinnedMessages(userId string, channel *Channel) (*[]MessageResponse, error)
+	PinMessage(message *Message, pinnedAt time.Time, limit int) error
+	SetPinned(messageId string, pinnedAt *time.Time) error
+	GetMessageMentions(channel *Channel, messageId string) (*MessageMentions, error)
+	GetMentionedMembers(guildId string, message *Message) ([]string, error)
+	GetUserMentions(userId string) (*[]MentionNotification, error)
+	SearchMessages(userId string, channelIds []string, search *MessageSearch) (*MessageSearchResponse, error)
+	GetMessageRevisions(messageId string) (*[]MessageRevision, error)
+	CreateUpload(upload *Upload) error
+	GetUpload(uploadId string) (*Upload, error)
+	GetExpiredUploads() (*[]Upload, error)
+	DeleteUploads(uploadIds []string) error
+	AddUploadChunk(upload *Upload, chunk *UploadChunk) error
+	SetUploadUrl(uploadId, url string) error
 }
@@ -0,0 +1,57 @@
+package model
+
+// OverwriteType specifies if a PermissionOverwrite targets a role or a member
+type OverwriteType string
+
+// Overwrite Types
+const (
+	OverwriteTypeRole   OverwriteType = "role"
+	OverwriteTypeMember OverwriteType = "member"
+)
+
+// PermissionOverwrite allows or denies ChannelPermissions for a role or a member in the given channel.
+// A role overwrite with the guild's ID as the TargetID applies to every member of the guild.
+type PermissionOverwrite struct {
+	ChannelID string        `gorm:"primaryKey;constraint:OnDelete:CASCADE;" json:"channelId"`
+	TargetID  string        `gorm:"primaryKey;index" json:"targetId"`
+	Type      OverwriteType `gorm:"not null" json:"type"`
+	Allow     Permission    `gorm:"not null;default:0" json:"allow"`
+	Deny      Permission    `gorm:"not null;default:0" json:"deny"`
+} //@name PermissionOverwrite
+
+// ResolveChannelPermissions applies the given overwrites on top of the guild level permissions.
+// The overwrites are applied in the order guild wide, roles and then member, where denies are
+// applied before allows. Administrators cannot be restricted by overwrites.
+func ResolveChannelPermissions(permissions Permission, guildId string, overwrites []PermissionOverwrite) Permission {
+	if permissions&PermissionAdministrator != 0 {
+		return AllPermissions
+	}
+
+	var everyone, member *PermissionOverwrite
+	var roleAllow, roleDeny Permission
+
+	for i := range overwrites {
+		overwrite := &overwrites[i]
+		switch {
+		case overwrite.Type == OverwriteTypeMember:
+			member = overwrite
+		case overwrite.TargetID == guildId:
+			everyone = overwrite
+		default:
+			roleAllow |= overwrite.Allow
+			roleDeny |= overwrite.Deny
+		}
+	}
+
+	if everyone != nil {
+		permissions = permissions&^everyone.Deny | everyone.Allow
+	}
+
+	permissions = permissions&^roleDeny | roleAllow
+
+	if member != nil {
+		permissions = permissions&^member.Deny | member.Allow
+	}
+
+	return permissions
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Reaction represents a user reacting to a Message with a unicode emoji.
+// A user can react with every emoji only once per message.
+type Reaction struct {
+	MessageId string `gorm:"primaryKey"`
+	UserId    string `gorm:"primaryKey;constraint:OnDelete:CASCADE;"`
+	Emoji     string `gorm:"primaryKey"`
+	CreatedAt time.Time
+}
+
+// ReactionResponse is the aggregated API response of all
+// reactions with the same emoji on a message.
+// Me is true if the current user reacted with that emoji.
+type ReactionResponse struct {
+	Emoji string `json:"emoji"`
+	Count int    `json:"count"`
+	Me    bool   `json:"me"`
+} //@name Reaction
+
+// ReactionEvent is the websocket payload of an added or removed reaction
+type ReactionEvent struct {
+	MessageId string `json:"messageId"`
+	UserId    string `json:"userId"`
+	Emoji     string `json:"emoji"`
+} //@name ReactionEvent
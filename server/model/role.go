@@ -0,0 +1,104 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// Permission is a bitset of actions a member is allowed to do in a guild.
+type Permission int64
+
+// Guild Permissions
+const (
+	PermissionAdministrator Permission = 1 << iota
+	PermissionManageGuild
+	PermissionManageRoles
+	PermissionManageChannels
+	PermissionKickMembers
+	PermissionBanMembers
+	PermissionManageMessages
+	PermissionViewChannel
+	PermissionSendMessages
+	PermissionAttachFiles
+)
+
+// AllPermissions contains every permission and is granted to the guild owner
+const AllPermissions = PermissionAdministrator |
+	PermissionManageGuild |
+	PermissionManageRoles |
+	PermissionManageChannels |
+	PermissionKickMembers |
+	PermissionBanMembers |
+	PermissionManageMessages |
+	PermissionViewChannel |
+	PermissionSendMessages |
+	PermissionAttachFiles
+
+// DefaultPermissions are granted to every member of a guild
+const DefaultPermissions = PermissionViewChannel |
+	PermissionSendMessages |
+	PermissionAttachFiles
+
+// ChannelPermissions contains the permissions that can be overwritten per channel
+const ChannelPermissions = PermissionViewChannel |
+	PermissionSendMessages |
+	PermissionAttachFiles |
+	PermissionManageMessages
+
+// Has checks if the bitset contains all the given permissions.
+// Administrators implicitly have every permission.
+func (p Permission) Has(permission Permission) bool {
+	if p&PermissionAdministrator != 0 {
+		return true
+	}
+	return p&permission == permission
+}
+
+// OwnerPosition is the position of the guild owner, who outranks every role
+const OwnerPosition = math.MaxInt32
+
+// NoRolePosition is the position of members without any roles and of non members
+const NoRolePosition = -1
+
+// Role represents a named set of permissions in a guild.
+// Roles with a higher Position are displayed first.
+type Role struct {
+	BaseModel
+	GuildId     string `gorm:"index;not null;constraint:OnDelete:CASCADE;"`
+	Name        string `gorm:"not null"`
+	Color       *string
+	Position    int        `gorm:"not null;default:0"`
+	Permissions Permission `gorm:"not null;default:0"`
+}
+
+// MemberRole assigns a Role to a Member and is the join
+// table between Member and Role.
+type MemberRole struct {
+	UserID  string `gorm:"primaryKey;constraint:OnDelete:CASCADE;"`
+	GuildID string `gorm:"primaryKey;constraint:OnDelete:CASCADE;"`
+	RoleID  string `gorm:"primaryKey;index;constraint:OnDelete:CASCADE;"`
+}
+
+// RoleResponse is the API response of a role.
+type RoleResponse struct {
+	Id          string     `json:"id"`
+	Name        string     `json:"name"`
+	Color       *string    `json:"color"`
+	Position    int        `json:"position"`
+	Permissions Permission `json:"permissions"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+} //@name Role
+
+// SerializeRole returns the role API response.
+func (r Role) SerializeRole() RoleResponse {
+	return RoleResponse{
+		Id:          r.ID,
+		Name:        r.Name,
+		Color:       r.Color,
+		Position:    r.Position,
+		Permissions: r.Permissions,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// SearchConfig is the Postgres text search configuration used for the messages.search column.
+// The simple configuration does not stem words, so it works for every language.
+const SearchConfig = "simple"
+
+// MessageSearch contains the free text and the filters of a message search.
+// Text is matched using Postgres full-text search.
+type MessageSearch struct {
+	Text          string
+	AuthorId      *string
+	ChannelId     *string
+	HasAttachment bool
+	Before        *time.Time
+	After         *time.Time
+	Offset        int
+}
+
+// MessageSearchResult is a message matching the search and the channel it was sent in
+type MessageSearchResult struct {
+	ChannelId string          `json:"channelId"`
+	Message   MessageResponse `json:"message"`
+} //@name MessageSearchResult
+
+// MessageSearchResponse contains one page of search results, newest first,
+// and the total number of matching messages
+type MessageSearchResponse struct {
+	Total    int                   `json:"total"`
+	Messages []MessageSearchResult `json:"messages"`
+} //@name MessageSearchResponse
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"context"
+	"time"
+)
+
+// Session is a login of the user on a device. Sessions are tracked
+// next to the session cookie, so the user can see and revoke them.
+type Session struct {
+	Id         string    `json:"id"`
+	Device     string    `json:"device"`
+	UserAgent  string    `json:"userAgent"`
+	Ip         string    `json:"ip"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	// Current is true for the session of the request
+	Current bool `json:"current"`
+} //@name Session
+
+// SessionService defines methods related to the sessions of a user the handler layer expects
+// any service it interacts with to implement
+type SessionService interface {
+	CreateSession(ctx context.Context, userId string, userAgent string, ip string) (*Session, error)
+	TouchSession(ctx context.Context, userId string, id string, userAgent string, ip string) error
+	GetSessions(ctx context.Context, userId string) ([]Session, error)
+	RevokeSession(ctx context.Context, userId string, id string) error
+	RevokeOtherSessions(ctx context.Context, userId string, currentId string) ([]string, error)
+}
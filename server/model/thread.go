@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+// Durations in minutes without new messages after which a thread gets archived
+const (
+	ArchiveAfterHour      = 60
+	ArchiveAfterDay       = 1440
+	ArchiveAfterThreeDays = 4320
+	ArchiveAfterWeek      = 10080
+)
+
+// ThreadArchiveInterval is the interval in which inactive threads get archived
+const ThreadArchiveInterval = time.Minute
+
+// ThreadMember represents a user that joined a thread.
+// Users automatically join the threads they create or post in.
+type ThreadMember struct {
+	ChannelID string `gorm:"primaryKey"`
+	UserID    string `gorm:"primaryKey;constraint:OnDelete:CASCADE;"`
+	CreatedAt time.Time
+}
+
+// ThreadResponse is the API response of a thread
+type ThreadResponse struct {
+	Id                  string    `json:"id"`
+	Name                string    `json:"name"`
+	ParentId            string    `json:"parentId"`
+	MessageId           string    `json:"messageId"`
+	OwnerId             string    `json:"ownerId"`
+	IsArchived          bool      `json:"isArchived"`
+	AutoArchiveDuration int       `json:"autoArchiveDuration"`
+	LastActivity        time.Time `json:"lastActivity"`
+	CreatedAt           time.Time `json:"createdAt"`
+	UpdatedAt           time.Time `json:"updatedAt"`
+} //@name Thread
+
+// SerializeThread returns the thread API response.
+// Should only be called for channels where IsThread is true.
+func (c Channel) SerializeThread() ThreadResponse {
+	response := ThreadResponse{
+		Id:                  c.ID,
+		Name:                c.Name,
+		IsArchived:          c.IsArchived,
+		AutoArchiveDuration: c.AutoArchiveDuration,
+		LastActivity:        c.LastActivity,
+		CreatedAt:           c.CreatedAt,
+		UpdatedAt:           c.UpdatedAt,
+	}
+
+	if c.ParentID != nil {
+		response.ParentId = *c.ParentID
+	}
+
+	if c.ParentMessageID != nil {
+		response.MessageId = *c.ParentMessageID
+	}
+
+	if c.OwnerID != nil {
+		response.OwnerId = *c.OwnerID
+	}
+
+	return response
+}
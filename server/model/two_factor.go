@@ -0,0 +1,21 @@
+package model
+
+// TwoFactorSetup contains the secret of a new TOTP enrollment.
+// Uri is the otpauth URI that can be shown as a QR code.
+type TwoFactorSetup struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"`
+} //@name TwoFactorSetup
+
+// RecoveryCodes are the single-use codes that can replace a TOTP code when logging in.
+// They are only shown once when two-factor authentication gets enabled.
+type RecoveryCodes struct {
+	Codes []string `json:"recoveryCodes"`
+} //@name RecoveryCodes
+
+// TwoFactorChallenge is returned by the login instead of the user if the
+// account has two-factor authentication enabled. The ticket completes the login together with a code.
+type TwoFactorChallenge struct {
+	TwoFactorRequired bool   `json:"twoFactorRequired"`
+	Ticket            string `json:"ticket"`
+} //@name TwoFactorChallenge
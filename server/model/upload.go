@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+// Upload represents a slot for a file the client uploads directly to the storage
+// using a presigned url. Sending a message with the ID of the upload attaches
+// the file to the message. Uploads that did not get attached once they
+// expire get deleted together with their file.
+// Resumable uploads receive the file in chunks using the tus protocol instead.
+// Offset is the number of received bytes and the Url gets set once all chunks
+// got merged into the file.
+type Upload struct {
+	ID        string        `gorm:"primaryKey"`
+	UserId    string        `gorm:"not null;index"`
+	ChannelId string        `gorm:"not null"`
+	Filename  string        `gorm:"not null"`
+	FileType  string        `gorm:"not null"`
+	Size      int64         `gorm:"not null"`
+	Url       string        `gorm:"not null"`
+	Resumable bool          `gorm:"not null;default:false"`
+	Offset    int64         `gorm:"column:upload_offset;not null;default:0"`
+	Chunks    []UploadChunk `gorm:"constraint:OnDelete:CASCADE;"`
+	ExpiresAt time.Time     `gorm:"not null;index"`
+	CreatedAt time.Time
+}
+
+// IsComplete returns true if the file of the upload was received completely
+// and, for resumable uploads, its chunks got merged
+func (u *Upload) IsComplete() bool {
+	return !u.Resumable || (u.Offset == u.Size && u.Url != "")
+}
+
+// UploadChunk is a part of a resumable upload starting at the Offset of the file
+type UploadChunk struct {
+	UploadId string `gorm:"primaryKey"`
+	Offset   int64  `gorm:"column:chunk_offset;primaryKey;autoIncrement:false"`
+	Size     int64  `gorm:"not null"`
+	Url      string `gorm:"not null"`
+}
+
+// UploadResponse contains the presigned url the file has to be uploaded to.
+// The upload request must use the given method and headers.
+type UploadResponse struct {
+	Id        string            `json:"id"`
+	UploadUrl string            `json:"uploadUrl"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+} //@name UploadResponse
+
+// Upload Expiration
+const (
+	// UploadUrlExpiration is how long the presigned url of an upload is valid
+	UploadUrlExpiration = 15 * time.Minute
+	// UploadExpiration is how long an upload can be attached to a message
+	UploadExpiration = time.Hour
+	// UploadCleanupInterval is the interval in which expired uploads get deleted
+	UploadCleanupInterval = 10 * time.Minute
+)
+
+// TusVersion is the supported version of the tus resumable upload protocol
+const TusVersion = "1.0.0"
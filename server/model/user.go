@@ -2,23 +2,47 @@ package model
 
 import (
 	"context"
+	"github.com/lib/pq"
 	"mime/multipart"
+	"time"
 )
 
 // User represents the user of the website.
 type User struct {
 	BaseModel
-	Username string    `gorm:"not null" json:"username"`
-	Email    string    `gorm:"not null;uniqueIndex" json:"email"`
-	Password string    `gorm:"not null" json:"-"`
-	Image    string    `json:"image"`
-	IsOnline bool      `gorm:"index;default:true" json:"isOnline"`
-	Friends  []User    `gorm:"many2many:friends;" json:"-"`
-	Requests []User    `gorm:"many2many:friend_requests;joinForeignKey:sender_id;joinReferences:receiver_id" json:"-"`
-	Guilds   []Guild   `gorm:"many2many:members;" json:"-"`
-	Message  []Message `json:"-"`
+	Username string `gorm:"not null" json:"username"`
+	Email    string `gorm:"not null;uniqueIndex" json:"email"`
+	Password string `gorm:"not null" json:"-"`
+	Image    string `json:"image"`
+	// EmailVerifiedAt is nil until the user verifies their current email address
+	EmailVerifiedAt *time.Time `json:"emailVerifiedAt"`
+	// TwoFactorSecret is the TOTP secret, set once the user starts the enrollment
+	TwoFactorSecret  string `json:"-"`
+	TwoFactorEnabled bool   `gorm:"default:false" json:"twoFactorEnabled"`
+	// TwoFactorStep is the time step of the last accepted code, so codes cannot be reused
+	TwoFactorStep int64 `json:"-"`
+	// RecoveryCodes are the hashes of the unused recovery codes
+	RecoveryCodes pq.StringArray `gorm:"type:text[]" json:"-"`
+	IsOnline      bool           `gorm:"index;default:true" json:"isOnline"`
+	Friends       []User         `gorm:"many2many:friends;" json:"-"`
+	Requests      []User         `gorm:"many2many:friend_requests;joinForeignKey:sender_id;joinReferences:receiver_id" json:"-"`
+	Guilds        []Guild        `gorm:"many2many:members;" json:"-"`
+	Message       []Message      `json:"-"`
+	// Bot is true for bot accounts, which authenticate with bot tokens instead of a password
+	Bot bool `gorm:"not null;default:false" json:"bot"`
+	// OwnerId is the ID of the user that manages the bot
+	OwnerId *string `gorm:"index" json:"-"`
+	// InteractionsUrl receives the interactions of the bot instead of the gateway
+	InteractionsUrl *string `json:"-"`
+	// InteractionsSecret signs the interactions sent to the InteractionsUrl
+	InteractionsSecret string `json:"-"`
 } //@name User
 
+// IsEmailVerified reports whether the user verified their current email address
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
 // UserService defines methods related to account operations the handler layer expects
 // any service it interacts with to implement
 type UserService interface {
@@ -29,7 +53,16 @@ type UserService interface {
 	UpdateAccount(user *User) error
 	IsEmailAlreadyInUse(email string) bool
 	ChangeAvatar(header *multipart.FileHeader, directory string) (string, error)
-	DeleteImage(key string) error
+	DeleteImage(url string) error
+	GetImageUrl(path string, size int) (string, error)
+	SendVerificationMail(ctx context.Context, user *User) error
+	VerifyEmail(ctx context.Context, token string) (*User, error)
+	EnableTwoFactor(user *User) (*TwoFactorSetup, error)
+	ConfirmTwoFactor(user *User, code string) ([]string, error)
+	DisableTwoFactor(user *User, password string) error
+	VerifyTwoFactor(user *User, code string) error
+	StartTwoFactorLogin(ctx context.Context, user *User) (string, error)
+	CompleteTwoFactorLogin(ctx context.Context, ticket string, code string) (*User, error)
 	ChangePassword(currentPassword, newPassword string, user *User) error
 	ForgotPassword(ctx context.Context, user *User) error
 	ResetPassword(ctx context.Context, password string, token string) (*User, error)
@@ -46,4 +79,6 @@ type UserRepository interface {
 	Update(user *User) error
 	GetFriendAndGuildIds(userId string) (*[]string, error)
 	GetRequestCount(userId string) (*int64, error)
+	FindBots(ownerId string) (*[]User, error)
+	Delete(userId string) error
 }
@@ -0,0 +1,53 @@
+package model
+
+// Webhook Settings
+const (
+	// WebhookLimit is the number of webhooks a channel can have
+	WebhookLimit = 10
+	// WebhookEmailDomain is the domain of the placeholder emails of webhook users
+	WebhookEmailDomain = "webhooks.invalid"
+)
+
+// Webhook is an incoming webhook that posts messages into its guild channel.
+// Every webhook has a bot user with the same ID as its identity, so its messages
+// show the name and image of the webhook.
+// Only the SHA-256 hash of the token gets stored.
+type Webhook struct {
+	BaseModel
+	ChannelId string `gorm:"not null;index" json:"channelId"`
+	GuildId   string `gorm:"not null;index" json:"guildId"`
+	CreatorId string `gorm:"not null" json:"creatorId"`
+	Name      string `gorm:"not null" json:"name"`
+	Image     string `json:"image"`
+	TokenHash string `gorm:"not null" json:"-"`
+} //@name Webhook
+
+// CreatedWebhook is a webhook together with its token.
+// The token is only returned when the webhook gets created or its token reset.
+type CreatedWebhook struct {
+	Webhook
+	Token string `json:"token"`
+} //@name CreatedWebhook
+
+// WebhookService defines methods related to webhooks the handler layer expects
+// any service it interacts with to implement
+type WebhookService interface {
+	CreateWebhook(channel *Channel, creatorId string, name string) (*CreatedWebhook, error)
+	GetWebhooks(channelId string) (*[]Webhook, error)
+	GetWebhook(channelId string, webhookId string) (*Webhook, error)
+	UpdateWebhook(webhook *Webhook) error
+	ResetWebhookToken(webhook *Webhook) (*CreatedWebhook, error)
+	DeleteWebhook(webhook *Webhook) error
+	Authenticate(webhookId string, token string) (*Webhook, error)
+}
+
+// WebhookRepository defines methods related to webhook db operations the service layer expects
+// any repository it interacts with to implement
+type WebhookRepository interface {
+	Create(webhook *Webhook, user *User) error
+	FindByID(id string) (*Webhook, error)
+	FindByChannel(channelId string) (*[]Webhook, error)
+	CountByChannel(channelId string) (int64, error)
+	Update(webhook *Webhook) error
+	Delete(id string) error
+}
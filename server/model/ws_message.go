@@ -34,24 +34,46 @@ type SocketService interface {
 	EmitNewMessage(room string, message *MessageResponse)
 	EmitEditMessage(room string, message *MessageResponse)
 	EmitDeleteMessage(room, messageId string)
+	EmitAddReaction(room string, reaction *ReactionEvent)
+	EmitRemoveReaction(room string, reaction *ReactionEvent)
+	EmitPinMessage(room string, pin *PinEvent)
+	EmitUnpinMessage(room string, pin *PinEvent)
 
 	EmitNewChannel(room string, channel *ChannelResponse)
 	EmitNewPrivateChannel(members []string, channel *ChannelResponse)
 	EmitEditChannel(room string, channel *ChannelResponse)
 	EmitDeleteChannel(channel *Channel)
 
+	EmitNewThread(thread *ThreadResponse)
+	EmitEditThread(thread *ThreadResponse)
+	EmitDeleteThread(thread *ThreadResponse)
+
 	EmitEditGuild(guild *Guild)
 	EmitDeleteGuild(guildId string, members []string)
 	EmitRemoveFromGuild(memberId, guildId string)
+	EmitAddToGuild(memberId string, guild *GuildResponse)
 
 	EmitAddMember(room string, member *User)
 	EmitRemoveMember(room, memberId string)
 
+	EmitAddRole(guildId string, role *RoleResponse)
+	EmitEditRole(guildId string, role *RoleResponse)
+	EmitDeleteRole(guildId, roleId string)
+	EmitAddMemberRole(guildId, memberId, roleId string)
+	EmitRemoveMemberRole(guildId, memberId, roleId string)
+
 	EmitNewDMNotification(channelId string, user *User)
 	EmitNewNotification(guildId, channelId string)
+	EmitNewMention(userId string, notification *MentionNotification)
 
 	EmitSendRequest(room string)
 	EmitAddFriendRequest(room string, request *FriendRequest)
 	EmitAddFriend(user, member *User)
 	EmitRemoveFriend(userId, memberId string)
+
+	EmitInteractionCreate(botId string, interaction *Interaction)
+	EmitInteractionDeferred(room string, interaction *Interaction)
+
+	DisconnectSessions(userId string, sessionIds []string)
+	DisconnectAccessTokens(userId string, accessTokenIds []string)
 }
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"errors"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"gorm.io/gorm"
+	"log"
+	"time"
+)
+
+// accessTokenRepository is data/repository implementation
+// of service layer AccessTokenRepository
+type accessTokenRepository struct {
+	DB *gorm.DB
+}
+
+// NewAccessTokenRepository is a factory for initializing Access Token Repositories
+func NewAccessTokenRepository(db *gorm.DB) model.AccessTokenRepository {
+	return &accessTokenRepository{
+		DB: db,
+	}
+}
+
+// Create inserts the access token in the DB
+func (r *accessTokenRepository) Create(token *model.AccessToken) error {
+	if err := r.DB.Create(token).Error; err != nil {
+		log.Printf("Could not create an access token for user: %v. Reason: %v\n", token.UserId, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// FindByHash fetches the access token with the given token hash
+func (r *accessTokenRepository) FindByHash(hash string) (*model.AccessToken, error) {
+	var token model.AccessToken
+
+	if err := r.DB.Where("token_hash = ?", hash).Take(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFound("token", "")
+		}
+
+		log.Printf("Could not get the access token. Reason: %v\n", err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return &token, nil
+}
+
+// FindByUser returns the access tokens of the given user, the newest first
+func (r *accessTokenRepository) FindByUser(userId string) (*[]model.AccessToken, error) {
+	var tokens []model.AccessToken
+
+	if err := r.DB.
+		Where("user_id = ?", userId).
+		Order("created_at DESC").
+		Find(&tokens).Error; err != nil {
+		log.Printf("Could not get the access tokens of user: %v. Reason: %v\n", userId, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return &tokens, nil
+}
+
+// CountByUser returns the number of access tokens of the given user
+func (r *accessTokenRepository) CountByUser(userId string) (int64, error) {
+	var count int64
+
+	if err := r.DB.
+		Model(&model.AccessToken{}).
+		Where("user_id = ?", userId).
+		Count(&count).Error; err != nil {
+		log.Printf("Could not count the access tokens of user: %v. Reason: %v\n", userId, err)
+		return 0, apperrors.NewInternal()
+	}
+
+	return count, nil
+}
+
+// Delete removes the access token of the given user from the DB
+func (r *accessTokenRepository) Delete(userId string, id string) error {
+	result := r.DB.
+		Where("id = ? AND user_id = ?", id, userId).
+		Delete(&model.AccessToken{})
+
+	if result.Error != nil {
+		log.Printf("Could not delete the access token with id: %v. Reason: %v\n", id, result.Error)
+		return apperrors.NewInternal()
+	}
+
+	if result.RowsAffected == 0 {
+		return apperrors.NewNotFound("token", id)
+	}
+
+	return nil
+}
+
+// DeleteByUser removes all access tokens of the given user from the DB
+func (r *accessTokenRepository) DeleteByUser(userId string) error {
+	if err := r.DB.Where("user_id = ?", userId).Delete(&model.AccessToken{}).Error; err != nil {
+		log.Printf("Could not delete the access tokens of user: %v. Reason: %v\n", userId, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// UpdateLastUsed sets the last use of the access token
+func (r *accessTokenRepository) UpdateLastUsed(id string, lastUsedAt time.Time) error {
+	if err := r.DB.
+		Model(&model.AccessToken{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", lastUsedAt).Error; err != nil {
+		log.Printf("Could not update the access token with id: %v. Reason: %v\n", id, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
@@ -0,0 +1,702 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/disintegration/imaging"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"golang.org/x/image/webp"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"log"
+	"mime/multipart"
+	"path"
+	"strings"
+)
+
+// avatarFormat is the format all sizes of an avatar or icon get stored in
+type avatarFormat struct {
+	Extension   string
+	ContentType string
+}
+
+var (
+	avatarJpeg = avatarFormat{Extension: ".jpeg", ContentType: "image/jpeg"}
+	avatarPng  = avatarFormat{Extension: ".png", ContentType: "image/png"}
+	avatarGif  = avatarFormat{Extension: ".gif", ContentType: "image/gif"}
+	avatarWebp = avatarFormat{Extension: ".webp", ContentType: "image/webp"}
+)
+
+// avatarExtensions are the extensions of the formats avatars get stored in
+var avatarExtensions = map[string]bool{
+	avatarJpeg.Extension: true,
+	avatarPng.Extension:  true,
+	avatarGif.Extension:  true,
+	avatarWebp.Extension: true,
+}
+
+var errInvalidWebp = errors.New("invalid webp container")
+
+// imageUrl returns the url the sizes of the avatar at the given path get requested from
+func imageUrl(publicUrl, imagePath string) string {
+	return fmt.Sprintf("%s/images/%s", strings.TrimSuffix(publicUrl, "/"), imagePath)
+}
+
+// imagePath returns the path of an url returned by imageUrl
+func imagePath(publicUrl, url string) (string, bool) {
+	return keyFromUrl(strings.TrimSuffix(publicUrl, "/")+"/images", url)
+}
+
+// imageKey returns the key the given size of the avatar at the path is stored at.
+// The path is the directory and id of the avatar followed by the extension of its format,
+// e.g. valkyrie/users/<id>/<avatarId>.png with the 128px size stored at
+// files/valkyrie/users/<id>/<avatarId>/128.png
+func imageKey(imagePath string, size int) (string, bool) {
+	ext := path.Ext(imagePath)
+	name := strings.TrimSuffix(imagePath, ext)
+
+	if !avatarExtensions[ext] || name == "" || !model.IsImageSize(size) {
+		return "", false
+	}
+
+	return cleanKey(fmt.Sprintf("files/%s/%d%s", name, size, ext))
+}
+
+// imageKeys returns the keys of all sizes of the avatar at the given path
+func imageKeys(imagePath string) []string {
+	keys := make([]string, 0, len(model.ImageSizes))
+
+	for _, size := range model.ImageSizes {
+		if key, ok := imageKey(imagePath, size); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// avatarImages decodes the given avatar or icon and encodes it in all the ImageSizes.
+// Animated gif and webp images stay animated in their format,
+// static images with transparent pixels turn into png images and all others into jpeg images.
+func avatarImages(header *multipart.FileHeader) (avatarFormat, map[int]*bytes.Buffer, error) {
+	file, err := header.Open()
+
+	if err != nil {
+		log.Printf("Failed to open header: %v\n", err.Error())
+		return avatarFormat{}, nil, apperrors.NewInternal()
+	}
+
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+
+	if err != nil {
+		log.Printf("Failed to read file: %v\n", err.Error())
+		return avatarFormat{}, nil, apperrors.NewInternal()
+	}
+
+	anim, err := decodeAnimation(content)
+
+	if err != nil {
+		return avatarFormat{}, nil, err
+	}
+
+	if anim != nil {
+		images, err := encodeAnimation(anim)
+		return anim.Format, images, err
+	}
+
+	src, err := imaging.Decode(bytes.NewReader(content), imaging.AutoOrientation(true))
+
+	if err != nil {
+		return avatarFormat{}, nil, invalidImage(err)
+	}
+
+	format, encode := avatarJpeg, encodeJpeg
+
+	if !isOpaque(src) {
+		format, encode = avatarPng, encodePng
+	}
+
+	images := make(map[int]*bytes.Buffer)
+
+	for _, size := range model.ImageSizes {
+		buf, err := encode(imaging.Fit(src, size, size, imaging.Lanczos))
+
+		if err != nil {
+			return avatarFormat{}, nil, err
+		}
+
+		images[size] = buf
+	}
+
+	return format, images, nil
+}
+
+// isOpaque reports whether the image does not have any transparent pixels
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+
+	return imaging.Clone(img).Opaque()
+}
+
+// invalidImage logs why the image could not be decoded and returns the error for the client
+func invalidImage(err error) error {
+	log.Printf("Failed to decode image: %v\n", err.Error())
+	return apperrors.NewBadRequest(apperrors.InvalidImageError)
+}
+
+// encodePng encodes the given image as a png
+func encodePng(img image.Image) (*bytes.Buffer, error) {
+	buf := new(bytes.Buffer)
+
+	if err := png.Encode(buf, img); err != nil {
+		log.Printf("Failed to encode image: %v\n", err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	return buf, nil
+}
+
+// animation is an animated image whose frames get drawn onto a canvas one after another.
+// Format is the format the animation gets encoded in again.
+type animation struct {
+	Format    avatarFormat
+	Width     int
+	Height    int
+	LoopCount int
+	Frames    []animationFrame
+}
+
+// animationFrame is a frame of an animation.
+// Bounds is the area of the canvas the frame gets drawn to and Delay the time
+// it is shown for in milliseconds. Disposal uses the gif disposal methods
+// and Blend draws the frame over the canvas instead of replacing the area.
+// Palette is the palette of gif frames and nil for frames with true colors.
+type animationFrame struct {
+	Decode   func() (image.Image, error)
+	Bounds   image.Rectangle
+	Delay    int
+	Disposal byte
+	Blend    bool
+	Palette  color.Palette
+}
+
+// decodeAnimation decodes the frames of animated gif and webp images.
+// It returns nil for all other images.
+func decodeAnimation(content []byte) (*animation, error) {
+	_, format, err := image.DecodeConfig(bytes.NewReader(content))
+
+	if err != nil {
+		return nil, invalidImage(err)
+	}
+
+	var anim *animation
+
+	switch format {
+	case "gif":
+		anim, err = decodeGifAnimation(content)
+	case "webp":
+		anim, err = decodeWebpAnimation(content)
+	}
+
+	if err != nil || anim == nil {
+		return nil, err
+	}
+
+	if anim.Width < 1 || anim.Height < 1 {
+		return nil, invalidImage(errors.New("animation without a canvas"))
+	}
+
+	if len(anim.Frames)*anim.Width*anim.Height > model.MaximumAnimationPixels {
+		return nil, apperrors.NewBadRequest(apperrors.AnimationTooLarge)
+	}
+
+	return anim, nil
+}
+
+// decodeGifAnimation decodes the frames of an animated gif image.
+// The frames get counted first, so large animations get rejected before decoding them.
+func decodeGifAnimation(content []byte) (*animation, error) {
+	config, err := gif.DecodeConfig(bytes.NewReader(content))
+
+	if err != nil {
+		return nil, invalidImage(err)
+	}
+
+	frames, err := countGifFrames(content)
+
+	if err != nil {
+		return nil, invalidImage(err)
+	}
+
+	if frames < 2 {
+		return nil, nil
+	}
+
+	if frames*config.Width*config.Height > model.MaximumAnimationPixels {
+		return nil, apperrors.NewBadRequest(apperrors.AnimationTooLarge)
+	}
+
+	g, err := gif.DecodeAll(bytes.NewReader(content))
+
+	if err != nil {
+		return nil, invalidImage(err)
+	}
+
+	anim := &animation{
+		Format:    avatarGif,
+		Width:     g.Config.Width,
+		Height:    g.Config.Height,
+		LoopCount: g.LoopCount,
+	}
+
+	for i, frame := range g.Image {
+		frame := frame
+		anim.Frames = append(anim.Frames, animationFrame{
+			Decode:   func() (image.Image, error) { return frame, nil },
+			Bounds:   frame.Bounds(),
+			Delay:    g.Delay[i] * 10,
+			Disposal: g.Disposal[i],
+			Blend:    true,
+			Palette:  frame.Palette,
+		})
+	}
+
+	return anim, nil
+}
+
+// countGifFrames counts the image descriptors of the gif image without decoding them
+func countGifFrames(content []byte) (int, error) {
+	r := bytes.NewReader(content)
+
+	// Header and logical screen descriptor
+	header := make([]byte, 13)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, err
+	}
+
+	if err := skipColorTable(r, header[10]); err != nil {
+		return 0, err
+	}
+
+	frames := 0
+
+	for {
+		block, err := r.ReadByte()
+
+		if err != nil {
+			return 0, err
+		}
+
+		switch block {
+		case 0x21: // Extension
+			if _, err = r.ReadByte(); err != nil {
+				return 0, err
+			}
+		case 0x2C: // Image descriptor
+			descriptor := make([]byte, 9)
+			if _, err = io.ReadFull(r, descriptor); err != nil {
+				return 0, err
+			}
+
+			if err = skipColorTable(r, descriptor[8]); err != nil {
+				return 0, err
+			}
+
+			// LZW minimum code size
+			if _, err = r.ReadByte(); err != nil {
+				return 0, err
+			}
+
+			frames++
+		case 0x3B: // Trailer
+			return frames, nil
+		default:
+			return 0, fmt.Errorf("gif: unknown block type: 0x%.2x", block)
+		}
+
+		if err = skipSubBlocks(r); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// skipColorTable skips the color table described by the given packed fields
+func skipColorTable(r *bytes.Reader, fields byte) error {
+	if fields&0x80 == 0 {
+		return nil
+	}
+
+	_, err := r.Seek(int64(3*(1<<(1+fields&0x07))), io.SeekCurrent)
+	return err
+}
+
+// skipSubBlocks skips the data sub-blocks up to the block terminator
+func skipSubBlocks(r *bytes.Reader) error {
+	for {
+		size, err := r.ReadByte()
+
+		if err != nil {
+			return err
+		}
+
+		if size == 0 {
+			return nil
+		}
+
+		if _, err = r.Seek(int64(size), io.SeekCurrent); err != nil {
+			return err
+		}
+	}
+}
+
+// riffChunk is a chunk of a RIFF container like webp
+type riffChunk struct {
+	ID   string
+	Data []byte
+}
+
+// riffChunks splits the given data into its chunks
+func riffChunks(data []byte) ([]riffChunk, error) {
+	chunks := make([]riffChunk, 0)
+
+	for len(data) >= 8 {
+		id := string(data[:4])
+		size := binary.LittleEndian.Uint32(data[4:8])
+		data = data[8:]
+
+		if uint64(size) > uint64(len(data)) {
+			return nil, errInvalidWebp
+		}
+
+		chunks = append(chunks, riffChunk{ID: id, Data: data[:size]})
+		data = data[size:]
+
+		// Chunks are padded to an even size
+		if size%2 == 1 && len(data) > 0 {
+			data = data[1:]
+		}
+	}
+
+	return chunks, nil
+}
+
+// uint24 reads a 24-bit little-endian integer
+func uint24(b []byte) int {
+	return int(b[0]) | int(b[1])<<8 | int(b[2])<<16
+}
+
+// putUint24 writes a 24-bit little-endian integer
+func putUint24(b []byte, v int) {
+	b[0], b[1], b[2] = byte(v), byte(v>>8), byte(v>>16)
+}
+
+// decodeWebpAnimation parses the frames of an animated webp image.
+// It returns nil for static webp images.
+func decodeWebpAnimation(content []byte) (*animation, error) {
+	if len(content) < 12 || string(content[:4]) != "RIFF" || string(content[8:12]) != "WEBP" {
+		return nil, invalidImage(errInvalidWebp)
+	}
+
+	chunks, err := riffChunks(content[12:])
+
+	if err != nil {
+		return nil, invalidImage(err)
+	}
+
+	anim := &animation{Format: avatarWebp}
+	animated := false
+
+	for _, chunk := range chunks {
+		data := chunk.Data
+
+		switch chunk.ID {
+		case "VP8X":
+			if len(data) < 10 {
+				return nil, invalidImage(errInvalidWebp)
+			}
+
+			animated = data[0]&0x02 != 0
+			anim.Width = uint24(data[4:]) + 1
+			anim.Height = uint24(data[7:]) + 1
+		case "ANIM":
+			if len(data) < 6 {
+				return nil, invalidImage(errInvalidWebp)
+			}
+
+			anim.LoopCount = gifLoopCount(int(binary.LittleEndian.Uint16(data[4:])))
+		case "ANMF":
+			if len(data) < 16 {
+				return nil, invalidImage(errInvalidWebp)
+			}
+
+			x, y := 2*uint24(data[0:]), 2*uint24(data[3:])
+			width, height := uint24(data[6:])+1, uint24(data[9:])+1
+			frameData := data[16:]
+
+			disposal := byte(gif.DisposalNone)
+			if data[15]&0x01 != 0 {
+				disposal = gif.DisposalBackground
+			}
+
+			anim.Frames = append(anim.Frames, animationFrame{
+				Decode:   func() (image.Image, error) { return decodeWebpFrame(frameData, width, height) },
+				Bounds:   image.Rect(x, y, x+width, y+height),
+				Delay:    uint24(data[12:]),
+				Disposal: disposal,
+				Blend:    data[15]&0x02 == 0,
+			})
+		}
+	}
+
+	if !animated || len(anim.Frames) == 0 {
+		return nil, nil
+	}
+
+	return anim, nil
+}
+
+// gifLoopCount converts the number of times a webp animation plays to the gif loop count.
+// Both use 0 for endless animations.
+func gifLoopCount(loops int) int {
+	switch loops {
+	case 0:
+		return 0
+	case 1:
+		return -1
+	default:
+		return loops - 1
+	}
+}
+
+// webpLoopCount converts the gif loop count back to the number of times a webp animation plays
+func webpLoopCount(loopCount int) int {
+	switch {
+	case loopCount == 0:
+		return 0
+	case loopCount < 0:
+		return 1
+	case loopCount >= 0xFFFF:
+		return 0xFFFF
+	default:
+		return loopCount + 1
+	}
+}
+
+// decodeWebpFrame decodes the bitstream of a frame of an animated webp image
+// by wrapping it into a webp image of its own
+func decodeWebpFrame(data []byte, width, height int) (image.Image, error) {
+	chunks, err := riffChunks(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	body := new(bytes.Buffer)
+	hasAlpha := false
+
+	for _, chunk := range chunks {
+		switch chunk.ID {
+		case "ALPH":
+			hasAlpha = true
+		case "VP8 ", "VP8L":
+		default:
+			continue
+		}
+
+		writeRiffChunk(body, chunk.ID, chunk.Data)
+	}
+
+	file := new(bytes.Buffer)
+
+	if hasAlpha {
+		header := make([]byte, 10)
+		header[0] = 0x10 // Alpha
+		putUint24(header[4:], width-1)
+		putUint24(header[7:], height-1)
+		writeRiffChunk(file, "VP8X", header)
+	}
+
+	_, _ = body.WriteTo(file)
+
+	container := new(bytes.Buffer)
+	writeRiffChunk(container, "RIFF", append([]byte("WEBP"), file.Bytes()...))
+
+	return webp.Decode(container)
+}
+
+// writeRiffChunk writes the chunk with its size and padding
+func writeRiffChunk(w *bytes.Buffer, id string, data []byte) {
+	size := make([]byte, 4)
+	binary.LittleEndian.PutUint32(size, uint32(len(data)))
+
+	w.WriteString(id)
+	w.Write(size)
+	w.Write(data)
+
+	if len(data)%2 == 1 {
+		w.WriteByte(0)
+	}
+}
+
+// encodeAnimation draws the frames of the animation onto its canvas and encodes
+// the canvas scaled down to all the ImageSizes in the format of the animation.
+// Every encoded frame contains the whole canvas and replaces the previous one.
+func encodeAnimation(anim *animation) (map[int]*bytes.Buffer, error) {
+	canvas := image.NewNRGBA(image.Rect(0, 0, anim.Width, anim.Height))
+
+	encoders := make(map[int]animationEncoder)
+	for _, size := range model.ImageSizes {
+		encoders[size] = newAnimationEncoder(anim)
+	}
+
+	for _, frame := range anim.Frames {
+		src, err := frame.Decode()
+
+		if err != nil {
+			return nil, invalidImage(err)
+		}
+
+		var previous *image.NRGBA
+		if frame.Disposal == gif.DisposalPrevious {
+			previous = imaging.Clone(canvas)
+		}
+
+		op := draw.Src
+		if frame.Blend {
+			op = draw.Over
+		}
+
+		draw.Draw(canvas, frame.Bounds, src, src.Bounds().Min, op)
+
+		for size, encoder := range encoders {
+			encoder.addFrame(imaging.Fit(canvas, size, size, imaging.Lanczos), frame)
+		}
+
+		switch frame.Disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds, image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+
+	images := make(map[int]*bytes.Buffer)
+
+	for size, encoder := range encoders {
+		buf, err := encoder.encode()
+
+		if err != nil {
+			return nil, err
+		}
+
+		images[size] = buf
+	}
+
+	return images, nil
+}
+
+// animationEncoder collects the frames of an animation scaled down to one of the ImageSizes
+type animationEncoder interface {
+	addFrame(img *image.NRGBA, frame animationFrame)
+	encode() (*bytes.Buffer, error)
+}
+
+// newAnimationEncoder returns the encoder for the format of the animation
+func newAnimationEncoder(anim *animation) animationEncoder {
+	if anim.Format == avatarWebp {
+		return &webpAnimation{LoopCount: webpLoopCount(anim.LoopCount), Frames: new(bytes.Buffer)}
+	}
+
+	return &gifAnimation{GIF: &gif.GIF{LoopCount: anim.LoopCount}}
+}
+
+// gifAnimation collects the frames of an animated gif image
+type gifAnimation struct {
+	GIF *gif.GIF
+}
+
+// addFrame adds the image quantized to the palette of the frame.
+// It gets disposed to the background, as the next frame covers the whole canvas.
+func (a *gifAnimation) addFrame(img *image.NRGBA, frame animationFrame) {
+	a.GIF.Image = append(a.GIF.Image, quantize(img, gifPalette(frame.Palette)))
+	a.GIF.Delay = append(a.GIF.Delay, (frame.Delay+5)/10)
+	a.GIF.Disposal = append(a.GIF.Disposal, gif.DisposalBackground)
+}
+
+// encode writes the animated gif image
+func (a *gifAnimation) encode() (*bytes.Buffer, error) {
+	buf := new(bytes.Buffer)
+
+	if err := gif.EncodeAll(buf, a.GIF); err != nil {
+		log.Printf("Failed to encode image: %v\n", err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	return buf, nil
+}
+
+// gifPalette returns the given palette of a frame with a transparent color.
+// Frames with true colors use the web safe palette.
+func gifPalette(p color.Palette) color.Palette {
+	if p == nil {
+		p = palette.WebSafe
+	}
+
+	for _, c := range p {
+		if _, _, _, a := c.RGBA(); a == 0 {
+			return p
+		}
+	}
+
+	colors := make(color.Palette, len(p), len(p)+1)
+	copy(colors, p)
+
+	if len(colors) == 256 {
+		colors[255] = color.Transparent
+		return colors
+	}
+
+	return append(colors, color.Transparent)
+}
+
+// quantize converts the image into a paletted image with the closest colors of the palette.
+// Gif images only support fully transparent pixels, so pixels that are more transparent
+// than opaque become fully transparent and the others fully opaque.
+func quantize(img *image.NRGBA, colors color.Palette) *image.Paletted {
+	dst := image.NewPaletted(img.Bounds(), colors)
+	indices := make(map[color.NRGBA]uint8)
+
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			c := img.NRGBAAt(x, y)
+
+			if c.A < 128 {
+				c = color.NRGBA{}
+			} else {
+				c.A = 255
+			}
+
+			index, ok := indices[c]
+			if !ok {
+				index = uint8(colors.Index(c))
+				indices[c] = index
+			}
+
+			dst.SetColorIndex(x, y, index)
+		}
+	}
+
+	return dst
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/sentrionic/valkyrie/model"
 	"github.com/sentrionic/valkyrie/model/apperrors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"log"
 	"time"
 )
@@ -36,7 +37,7 @@ func (r *channelRepository) Create(channel *model.Channel) (*model.Channel, erro
 func (r *channelRepository) GetGuildDefault(guildId string) (*model.Channel, error) {
 	channel := model.Channel{}
 	result := r.DB.
-		Where("guild_id = ?", guildId).
+		Where("guild_id = ? AND parent_id IS NULL", guildId).
 		Order("created_at ASC").
 		First(&channel)
 
@@ -44,7 +45,8 @@ func (r *channelRepository) GetGuildDefault(guildId string) (*model.Channel, err
 }
 
 // Get fetches all public channels for the given guildId
-// and the private channels the given user is part in
+// and the private channels the given user is part in.
+// Channels the user is not allowed to view and threads are excluded.
 func (r *channelRepository) Get(userId string, guildId string) (*[]model.ChannelResponse, error) {
 	var channels []model.ChannelResponse
 
@@ -58,12 +60,37 @@ func (r *channelRepository) Get(userId string, guildId string) (*[]model.Channel
 			ON c."id"::text = pc."channel_id"::text
 			LEFT OUTER JOIN members m on c."guild_id" = m."guild_id"
 			WHERE c."guild_id"::text = ?
+			AND c."parent_id" IS NULL
 			AND (c."is_public" = true or pc."user_id"::text = ?)
 			ORDER BY c."created_at"
 		`, guildId, userId).
 		Scan(&channels)
 
-	return &channels, result.Error
+	if result.Error != nil {
+		return &channels, result.Error
+	}
+
+	permissions, err := getMemberPermissions(r.DB, userId, guildId)
+
+	if err != nil {
+		return &channels, err
+	}
+
+	channelOverwrites, err := r.GetGuildMemberPermissionOverwrites(userId, guildId)
+
+	if err != nil {
+		return &channels, err
+	}
+
+	visible := make([]model.ChannelResponse, 0, len(channels))
+	for _, channel := range channels {
+		resolved := model.ResolveChannelPermissions(permissions, guildId, channelOverwrites[channel.Id])
+		if resolved.Has(model.PermissionViewChannel) {
+			visible = append(visible, channel)
+		}
+	}
+
+	return &visible, nil
 }
 
 // dmQuery represents the fetched fields for GetDirectMessages
@@ -195,6 +222,14 @@ func (r *channelRepository) OpenDMForAll(dmId string) error {
 
 // DeleteChannel deletes the given channel from the DB
 func (r *channelRepository) DeleteChannel(channel *model.Channel) error {
+	// Webhooks and their users do not reference the channel
+	if result := r.DB.
+		Exec("DELETE FROM users WHERE id IN (SELECT id FROM webhooks WHERE channel_id = ?)", channel.ID).
+		Exec("DELETE FROM webhooks WHERE channel_id = ?", channel.ID); result.Error != nil {
+		log.Printf("Could not delete the webhooks of the channel with id: %v. Reason: %v\n", channel.ID, result.Error)
+		return apperrors.NewInternal()
+	}
+
 	if result := r.DB.Delete(&channel); result.Error != nil {
 		log.Printf("Could not delete the channel with id: %v. Reason: %v\n", channel, result.Error)
 		return apperrors.NewInternal()
@@ -262,3 +297,187 @@ func (r *channelRepository) GetDMMemberIds(channelId string) (*[]string, error)
 		Scan(&members).Error
 	return &members, err
 }
+
+// memberOverwriteCondition selects the overwrites that apply to the user,
+// which are the ones for the whole guild, the user's roles and the user itself
+const memberOverwriteCondition = `(
+	po."target_id" IN (@guildId, @userId)
+	OR po."target_id" IN (
+		SELECT mr."role_id"
+		FROM member_roles mr
+		WHERE mr."user_id" = @userId
+		AND mr."guild_id" = @guildId
+	)
+)`
+
+// GetPermissionOverwrites returns all permission overwrites of the given channel
+func (r *channelRepository) GetPermissionOverwrites(channelId string) (*[]model.PermissionOverwrite, error) {
+	var overwrites []model.PermissionOverwrite
+	err := r.DB.
+		Where("channel_id = ?", channelId).
+		Find(&overwrites).Error
+	return &overwrites, err
+}
+
+// GetMemberPermissionOverwrites returns the overwrites of the given channel that apply to the given user
+func (r *channelRepository) GetMemberPermissionOverwrites(userId string, guildId string, channelId string) (*[]model.PermissionOverwrite, error) {
+	var overwrites []model.PermissionOverwrite
+	err := r.DB.
+		Raw(`
+			SELECT po.*
+			FROM permission_overwrites po
+			WHERE po."channel_id" = @channelId
+			AND `+memberOverwriteCondition,
+			sql.Named("userId", userId),
+			sql.Named("guildId", guildId),
+			sql.Named("channelId", channelId),
+		).
+		Scan(&overwrites).Error
+	return &overwrites, err
+}
+
+// GetGuildMemberPermissionOverwrites returns the overwrites of all channels in the given guild
+// that apply to the given user, grouped by their channel ID
+func (r *channelRepository) GetGuildMemberPermissionOverwrites(userId string, guildId string) (map[string][]model.PermissionOverwrite, error) {
+	var overwrites []model.PermissionOverwrite
+	err := r.DB.
+		Raw(`
+			SELECT po.*
+			FROM permission_overwrites po
+			JOIN channels c ON c.id = po."channel_id"
+			WHERE c."guild_id" = @guildId
+			AND `+memberOverwriteCondition,
+			sql.Named("userId", userId),
+			sql.Named("guildId", guildId),
+		).
+		Scan(&overwrites).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	channelOverwrites := make(map[string][]model.PermissionOverwrite)
+	for _, overwrite := range overwrites {
+		channelOverwrites[overwrite.ChannelID] = append(channelOverwrites[overwrite.ChannelID], overwrite)
+	}
+
+	return channelOverwrites, nil
+}
+
+// SavePermissionOverwrite inserts the given overwrite or updates it if it already exists
+func (r *channelRepository) SavePermissionOverwrite(overwrite *model.PermissionOverwrite) error {
+	if err := r.DB.
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&overwrite).Error; err != nil {
+		log.Printf("Could not save the overwrite for %s in channel %s. Reason: %v\n", overwrite.TargetID, overwrite.ChannelID, err)
+		return apperrors.NewInternal()
+	}
+	return nil
+}
+
+// DeletePermissionOverwrite removes the overwrite for the given target from the given channel
+func (r *channelRepository) DeletePermissionOverwrite(channelId string, targetId string) error {
+	if err := r.DB.
+		Exec("DELETE FROM permission_overwrites WHERE channel_id = ? AND target_id = ?", channelId, targetId).
+		Error; err != nil {
+		log.Printf("Could not delete the overwrite for %s in channel %s. Reason: %v\n", targetId, channelId, err)
+		return apperrors.NewInternal()
+	}
+	return nil
+}
+
+// GetThreads returns the active or archived threads of the given channel.
+// The most recently active threads are returned first.
+func (r *channelRepository) GetThreads(channelId string, archived bool) (*[]model.ThreadResponse, error) {
+	var threads []model.Channel
+
+	if err := r.DB.
+		Where("parent_id = ? AND is_archived = ?", channelId, archived).
+		Order("last_activity DESC").
+		Find(&threads).
+		Error; err != nil {
+		log.Printf("Could not get the threads of channel with id: %v. Reason: %v\n", channelId, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	response := make([]model.ThreadResponse, 0, len(threads))
+	for _, thread := range threads {
+		response = append(response, thread.SerializeThread())
+	}
+
+	return &response, nil
+}
+
+// GetThreadByMessageId returns the thread that got started from the given message
+func (r *channelRepository) GetThreadByMessageId(messageId string) (*model.Channel, error) {
+	var thread model.Channel
+	err := r.DB.Where("parent_message_id = ?", messageId).First(&thread).Error
+	return &thread, err
+}
+
+// GetThreadMembers returns the ids of all members of the given thread
+func (r *channelRepository) GetThreadMembers(threadId string) (*[]string, error) {
+	var members []string
+	err := r.DB.
+		Model(&model.ThreadMember{}).
+		Where("channel_id = ?", threadId).
+		Order("created_at").
+		Pluck("user_id", &members).
+		Error
+	return &members, err
+}
+
+// AddThreadMember adds the user to the thread if they are not a member yet
+func (r *channelRepository) AddThreadMember(member *model.ThreadMember) error {
+	if err := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(member).Error; err != nil {
+		log.Printf("Could not add member %v to thread with id: %v. Reason: %v\n", member.UserID, member.ChannelID, err)
+		return apperrors.NewInternal()
+	}
+	return nil
+}
+
+// RemoveThreadMember removes the user from the thread
+func (r *channelRepository) RemoveThreadMember(threadId string, userId string) error {
+	if err := r.DB.
+		Where("channel_id = ? AND user_id = ?", threadId, userId).
+		Delete(&model.ThreadMember{}).
+		Error; err != nil {
+		log.Printf("Could not remove member %v from thread with id: %v. Reason: %v\n", userId, threadId, err)
+		return apperrors.NewInternal()
+	}
+	return nil
+}
+
+// ArchiveInactiveThreads archives all threads that did not have any activity
+// during their auto archive duration and returns them
+func (r *channelRepository) ArchiveInactiveThreads() (*[]model.Channel, error) {
+	var threads []model.Channel
+	err := r.DB.
+		Model(&threads).
+		Clauses(clause.Returning{}).
+		Where("parent_id IS NOT NULL AND is_archived = false").
+		Where("last_activity + auto_archive_duration * interval '1 minute' < ?", time.Now()).
+		Update("is_archived", true).
+		Error
+	return &threads, err
+}
+
+// GetGuildChannels returns all channels and threads of the given guild with their PCMembers
+func (r *channelRepository) GetGuildChannels(guildId string) (*[]model.Channel, error) {
+	var channels []model.Channel
+	err := r.DB.
+		Preload("PCMembers").
+		Where("guild_id = ?", guildId).
+		Find(&channels).
+		Error
+	return &channels, err
+}
+
+// GetDMChannelIds returns the ids of all dm channels of the given user, including closed ones
+func (r *channelRepository) GetDMChannelIds(userId string) ([]string, error) {
+	var ids []string
+	err := r.DB.
+		Raw("SELECT channel_id FROM dm_members WHERE user_id = ?", userId).
+		Scan(&ids).Error
+	return ids, err
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"errors"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"gorm.io/gorm"
+	"log"
+)
+
+// commandRepository is data/repository implementation
+// of service layer CommandRepository
+type commandRepository struct {
+	DB *gorm.DB
+}
+
+// NewCommandRepository is a factory for initializing Command Repositories
+func NewCommandRepository(db *gorm.DB) model.CommandRepository {
+	return &commandRepository{
+		DB: db,
+	}
+}
+
+// FindByID fetches the command with the given id
+func (r *commandRepository) FindByID(id string) (*model.Command, error) {
+	var command model.Command
+
+	if err := r.DB.Where("id = ?", id).Take(&command).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFound("command", id)
+		}
+
+		log.Printf("Could not get the command with id: %v. Reason: %v\n", id, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return &command, nil
+}
+
+// FindByBot returns the global and guild commands of the given bot
+func (r *commandRepository) FindByBot(botId string) (*[]model.Command, error) {
+	var commands []model.Command
+
+	if err := r.DB.
+		Where("bot_id = ?", botId).
+		Order("name").
+		Find(&commands).Error; err != nil {
+		log.Printf("Could not get the commands of bot: %v. Reason: %v\n", botId, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return &commands, nil
+}
+
+// FindByGuild returns the commands that can be used in the given guild.
+// These are the global commands of the bots in the guild and the commands registered for the guild.
+func (r *commandRepository) FindByGuild(guildId string) (*[]model.Command, error) {
+	var commands []model.Command
+
+	if err := r.DB.
+		Joins("JOIN members m ON m.user_id = commands.bot_id AND m.guild_id = ?", guildId).
+		Where("commands.guild_id IS NULL OR commands.guild_id = ?", guildId).
+		Order("commands.name").
+		Find(&commands).Error; err != nil {
+		log.Printf("Could not get the commands of guild: %v. Reason: %v\n", guildId, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return &commands, nil
+}
+
+// FindByName fetches the command of the bot with the given name.
+// A nil guildId looks for a global command.
+func (r *commandRepository) FindByName(botId string, guildId *string, name string) (*model.Command, error) {
+	var command model.Command
+
+	query := r.DB.Where("bot_id = ? AND name = ?", botId, name)
+
+	if guildId == nil {
+		query = query.Where("guild_id IS NULL")
+	} else {
+		query = query.Where("guild_id = ?", *guildId)
+	}
+
+	if err := query.Take(&command).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFound("command", name)
+		}
+
+		log.Printf("Could not get the command with name: %v. Reason: %v\n", name, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return &command, nil
+}
+
+// CountByBot returns the number of commands of the given bot
+func (r *commandRepository) CountByBot(botId string) (int64, error) {
+	var count int64
+
+	if err := r.DB.
+		Model(&model.Command{}).
+		Where("bot_id = ?", botId).
+		Count(&count).Error; err != nil {
+		log.Printf("Could not count the commands of bot: %v. Reason: %v\n", botId, err)
+		return 0, apperrors.NewInternal()
+	}
+
+	return count, nil
+}
+
+// Create inserts the command in the DB
+func (r *commandRepository) Create(command *model.Command) error {
+	if err := r.DB.Create(command).Error; err != nil {
+		log.Printf("Could not create a command for bot: %v. Reason: %v\n", command.BotId, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// Update updates the command in the DB
+func (r *commandRepository) Update(command *model.Command) error {
+	if err := r.DB.Save(command).Error; err != nil {
+		log.Printf("Could not update the command with id: %v. Reason: %v\n", command.ID, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// Delete removes the command from the DB
+func (r *commandRepository) Delete(commandId string) error {
+	if err := r.DB.Where("id = ?", commandId).Delete(&model.Command{}).Error; err != nil {
+		log.Printf("Could not delete the command with id: %v. Reason: %v\n", commandId, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
@@ -13,101 +14,134 @@ import (
 	"github.com/sentrionic/valkyrie/service"
 	"image"
 	"image/jpeg"
+	"io"
 	"log"
+	"net/url"
+	"strings"
 
 	// Register accepted file type jpeg
 	_ "image/jpeg"
 	// Register accepted file type png
 	_ "image/png"
+	// Register accepted file type gif
+	_ "image/gif"
+	// Register accepted file type webp
+	_ "golang.org/x/image/webp"
 	"mime/multipart"
 )
 
-// s3FileRepository includes the S3 session and the BucketName
+// s3FileRepository includes the S3 session, the BucketName,
+// the BaseUrl the files of the bucket are reachable at
+// and the PublicUrl of the server that redirects to the sizes of avatars
 type s3FileRepository struct {
 	S3Session  *session.Session
 	BucketName string
+	BaseUrl    string
+	PublicUrl  string
 }
 
-// NewFileRepository is a factory for initializing the FileRepository
-func NewFileRepository(session *session.Session, bucketName string) model.FileRepository {
+// NewS3FileRepository is a factory for initializing a FileRepository
+// that stores files in AWS S3 or any S3-compatible storage
+func NewS3FileRepository(session *session.Session, bucketName, publicUrl string) model.FileRepository {
 	return &s3FileRepository{
 		S3Session:  session,
 		BucketName: bucketName,
+		BaseUrl:    bucketUrl(session.Config, bucketName),
+		PublicUrl:  strings.TrimSuffix(publicUrl, "/"),
 	}
 }
 
-// UploadAvatar uploads the given image to the initialized Bucket.
-// The image gets resized before being uploaded.
-// All images turn into jpeg images.
-// It returns the url of the uploaded file.
-func (s *s3FileRepository) UploadAvatar(header *multipart.FileHeader, directory string) (string, error) {
-	uploader := s3manager.NewUploader(s.S3Session)
+// bucketUrl returns the url of the bucket for the endpoint of the given config.
+// Custom endpoints use path-style or virtual-hosted-style addressing depending
+// on S3ForcePathStyle, otherwise the AWS S3 endpoint of the region is used.
+func bucketUrl(config *aws.Config, bucketName string) string {
+	endpoint := strings.TrimSuffix(aws.StringValue(config.Endpoint), "/")
+
+	if endpoint == "" {
+		return fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucketName, aws.StringValue(config.Region))
+	}
 
-	id := service.GenerateId()
-	key := fmt.Sprintf("files/%s/%s.jpeg", directory, id)
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
 
-	file, err := header.Open()
+	u, err := url.Parse(endpoint)
 
-	if err != nil {
-		log.Printf("Failed to open header: %v\n", err.Error())
-		return "", apperrors.NewInternal()
+	if aws.BoolValue(config.S3ForcePathStyle) || err != nil {
+		return endpoint + "/" + bucketName
 	}
 
-	src, _, err := image.Decode(file)
+	u.Host = bucketName + "." + u.Host
+	return u.String()
+}
+
+// UploadAvatar uploads the given image in all the ImageSizes to the initialized Bucket.
+// Animated images keep their gif or webp format, transparent images turn into png images and all others into jpeg images.
+// It returns the url of the image at the PublicUrl, which redirects to the requested size.
+func (s *s3FileRepository) UploadAvatar(header *multipart.FileHeader, directory string) (string, error) {
+	format, images, err := avatarImages(header)
 
 	if err != nil {
-		log.Printf("Failed to decode image: %v\n", err.Error())
-		return "", apperrors.NewInternal()
+		return "", err
 	}
 
-	img := imaging.Resize(src, 150, 0, imaging.Lanczos)
+	path := fmt.Sprintf("%s/%s%s", directory, service.GenerateId(), format.Extension)
+	url := imageUrl(s.PublicUrl, path)
 
-	buf := new(bytes.Buffer)
-	err = jpeg.Encode(buf, img, &jpeg.Options{Quality: 75})
+	for size, buf := range images {
+		key, _ := imageKey(path, size)
 
-	if err != nil {
-		log.Printf("Failed to encode image: %v\n", err.Error())
-		return "", apperrors.NewInternal()
+		if _, err = s.upload(buf, key, format.ContentType); err != nil {
+			_ = s.DeleteImage(url)
+			return "", err
+		}
 	}
 
-	up, err := uploader.Upload(&s3manager.UploadInput{
-		Body:        buf,
-		Bucket:      aws.String(s.BucketName),
-		ContentType: aws.String("image/jpeg"),
-		Key:         aws.String(key),
-	})
+	return url, nil
+}
 
-	if err != nil {
-		log.Printf("Failed to upload file: %v\n", err.Error())
-		return "", apperrors.NewInternal()
+// ImageUrl returns the url of the given size of the avatar at the path
+func (s *s3FileRepository) ImageUrl(path string, size int) (string, error) {
+	key, ok := imageKey(path, size)
+
+	if !ok {
+		return "", apperrors.NewNotFound("image", path)
 	}
 
-	if err = file.Close(); err != nil {
-		log.Printf("Failed to close file: %v\n", err.Error())
-		return "", apperrors.NewInternal()
+	return s.BaseUrl + "/" + key, nil
+}
+
+// UploadThumbnail uploads a preview of the given image to the initialized Bucket.
+// The image gets scaled down to fit into the thumbnail size and turns into a jpeg image.
+// It returns the url of the uploaded thumbnail.
+func (s *s3FileRepository) UploadThumbnail(file io.Reader, directory, filename string) (string, error) {
+	key := fmt.Sprintf("files/%s/%s", directory, filename)
+
+	buf, err := thumbnailImage(file)
+
+	if err != nil {
+		return "", err
 	}
 
-	return up.Location, nil
+	return s.upload(buf, key, "image/jpeg")
 }
 
 // UploadFile uploads the given file to the initialized Bucket.
 // It returns the url of the uploaded file.
-func (s *s3FileRepository) UploadFile(header *multipart.FileHeader, directory, filename, mimetype string) (string, error) {
-	uploader := s3manager.NewUploader(s.S3Session)
-
+func (s *s3FileRepository) UploadFile(file io.Reader, directory, filename, mimetype string) (string, error) {
 	key := fmt.Sprintf("files/%s/%s", directory, filename)
 
-	file, err := header.Open()
+	return s.upload(file, key, mimetype)
+}
 
-	if err != nil {
-		log.Printf("Failed to open header: %v\n", err.Error())
-		return "", apperrors.NewInternal()
-	}
+// upload stores the given body under the key and returns its url
+func (s *s3FileRepository) upload(body io.Reader, key, contentType string) (string, error) {
+	uploader := s3manager.NewUploader(s.S3Session)
 
-	up, err := uploader.Upload(&s3manager.UploadInput{
-		Body:        file,
+	_, err := uploader.Upload(&s3manager.UploadInput{
+		Body:        body,
 		Bucket:      aws.String(s.BucketName),
-		ContentType: aws.String(mimetype),
+		ContentType: aws.String(contentType),
 		Key:         aws.String(key),
 	})
 
@@ -116,26 +150,209 @@ func (s *s3FileRepository) UploadFile(header *multipart.FileHeader, directory, f
 		return "", apperrors.NewInternal()
 	}
 
-	if err = file.Close(); err != nil {
-		log.Printf("Failed to close file: %v\n", err.Error())
-		return "", apperrors.NewInternal()
+	return s.BaseUrl + "/" + key, nil
+}
+
+// PresignUpload returns a presigned url to upload a file with the given mimetype
+// to the initialized Bucket followed by the url of the uploaded file.
+// S3 does not sign the content length, so the size has to be checked after the upload.
+func (s *s3FileRepository) PresignUpload(directory, filename, mimetype string, _ int64) (string, string, error) {
+	key := fmt.Sprintf("files/%s/%s", directory, filename)
+
+	srv := s3.New(s.S3Session)
+	req, _ := srv.PutObjectRequest(&s3.PutObjectInput{
+		Bucket:      aws.String(s.BucketName),
+		ContentType: aws.String(mimetype),
+		Key:         aws.String(key),
+	})
+
+	uploadUrl, err := req.Presign(model.UploadUrlExpiration)
+
+	if err != nil {
+		log.Printf("Failed to presign upload: %v\n", err.Error())
+		return "", "", apperrors.NewInternal()
 	}
 
-	return up.Location, nil
+	return uploadUrl, s.BaseUrl + "/" + key, nil
 }
 
-// DeleteImage deletes the file from the Bucket.
-func (s *s3FileRepository) DeleteImage(key string) error {
+// OpenFile returns the content and size of the file with the given url
+func (s *s3FileRepository) OpenFile(url string) (io.ReadCloser, int64, error) {
+	key, ok := keyFromUrl(s.BaseUrl, url)
+
+	if !ok {
+		return nil, 0, apperrors.NewNotFound("file", url)
+	}
+
 	srv := s3.New(s.S3Session)
-	_, err := srv.DeleteObject(&s3.DeleteObjectInput{
+	object, err := srv.GetObject(&s3.GetObjectInput{
 		Bucket: aws.String(s.BucketName),
 		Key:    aws.String(key),
 	})
 
 	if err != nil {
-		log.Printf("Failed to delete image: %v\n", err.Error())
-		return apperrors.NewInternal()
+		if e, ok := err.(awserr.Error); ok && e.Code() == s3.ErrCodeNoSuchKey {
+			return nil, 0, apperrors.NewNotFound("file", url)
+		}
+
+		log.Printf("Failed to get file: %v\n", err.Error())
+		return nil, 0, apperrors.NewInternal()
+	}
+
+	return object.Body, aws.Int64Value(object.ContentLength), nil
+}
+
+// UploadChunk uploads a part of a resumable upload to the initialized Bucket.
+// It returns the url and size of the uploaded chunk.
+func (s *s3FileRepository) UploadChunk(directory, filename string, chunk io.Reader) (string, int64, error) {
+	key := fmt.Sprintf("files/%s/%s", directory, filename)
+
+	counter := &countingReader{Reader: chunk}
+	location, err := s.upload(counter, key, "application/octet-stream")
+
+	if err != nil {
+		return "", 0, err
+	}
+
+	return location, counter.Count, nil
+}
+
+// MergeChunks uploads the concatenation of the chunks with the given urls to the initialized Bucket.
+// It returns the url of the uploaded file.
+func (s *s3FileRepository) MergeChunks(urls []string, directory, filename, mimetype string) (string, error) {
+	key := fmt.Sprintf("files/%s/%s", directory, filename)
+
+	chunks := &chunkReader{Open: s.OpenFile, Urls: urls}
+	defer chunks.Close()
+
+	return s.upload(chunks, key, mimetype)
+}
+
+// DeleteImage deletes the file with the given url from the Bucket.
+// Avatar urls delete all sizes of the avatar.
+// Urls that do not belong to the Bucket get ignored.
+func (s *s3FileRepository) DeleteImage(url string) error {
+	keys := make([]string, 0)
+
+	if path, ok := imagePath(s.PublicUrl, url); ok {
+		keys = imageKeys(path)
+	} else if key, ok := keyFromUrl(s.BaseUrl, url); ok {
+		keys = append(keys, key)
+	}
+
+	srv := s3.New(s.S3Session)
+
+	for _, key := range keys {
+		_, err := srv.DeleteObject(&s3.DeleteObjectInput{
+			Bucket: aws.String(s.BucketName),
+			Key:    aws.String(key),
+		})
+
+		if err != nil {
+			log.Printf("Failed to delete image: %v\n", err.Error())
+			return apperrors.NewInternal()
+		}
 	}
 
 	return nil
 }
+
+// keyFromUrl returns the key of a file url that starts with the given base url
+func keyFromUrl(baseUrl, fileUrl string) (string, bool) {
+	fileUrl, _, _ = strings.Cut(fileUrl, "?")
+	key, ok := strings.CutPrefix(fileUrl, strings.TrimSuffix(baseUrl, "/")+"/")
+
+	if !ok || key == "" {
+		return "", false
+	}
+
+	return key, true
+}
+
+// countingReader counts the bytes read from the Reader
+type countingReader struct {
+	io.Reader
+	Count int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.Count += int64(n)
+	return n, err
+}
+
+// chunkReader reads the files with the given Urls one after another.
+// Every file only gets opened once the previous one got read completely.
+type chunkReader struct {
+	Open    func(url string) (io.ReadCloser, int64, error)
+	Urls    []string
+	current io.ReadCloser
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.Urls) == 0 {
+				return 0, io.EOF
+			}
+
+			body, _, err := r.Open(r.Urls[0])
+
+			if err != nil {
+				return 0, err
+			}
+
+			r.current = body
+			r.Urls = r.Urls[1:]
+		}
+
+		n, err := r.current.Read(p)
+
+		if err == io.EOF {
+			err = r.current.Close()
+			r.current = nil
+
+			if n > 0 || err != nil {
+				return n, err
+			}
+			continue
+		}
+
+		return n, err
+	}
+}
+
+// Close closes the currently opened file
+func (r *chunkReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+
+	err := r.current.Close()
+	r.current = nil
+	return err
+}
+
+// thumbnailImage scales the given image down to fit into the thumbnail size and encodes it as a jpeg
+func thumbnailImage(file io.Reader) (*bytes.Buffer, error) {
+	src, err := imaging.Decode(file, imaging.AutoOrientation(true))
+
+	if err != nil {
+		log.Printf("Failed to decode image: %v\n", err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	return encodeJpeg(imaging.Fit(src, model.ThumbnailSize, model.ThumbnailSize, imaging.Lanczos))
+}
+
+// encodeJpeg encodes the given image as a jpeg
+func encodeJpeg(img image.Image) (*bytes.Buffer, error) {
+	buf := new(bytes.Buffer)
+
+	if err := jpeg.Encode(buf, img, &jpeg.Options{Quality: 75}); err != nil {
+		log.Printf("Failed to encode image: %v\n", err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	return buf, nil
+}
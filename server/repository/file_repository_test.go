@@ -0,0 +1,581 @@
+package repository
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readFile returns the content of the stored file with the given url
+// and false if the file does not exist
+type readFile func(t *testing.T, fileUrl string) ([]byte, bool)
+
+// testFileRepository is the contract every storage driver has to fulfill
+func testFileRepository(t *testing.T, repo model.FileRepository, read readFile) {
+	directory := "test/" + fmt.Sprint(os.Getpid())
+
+	t.Run("UploadFile", func(t *testing.T) {
+		content := []byte("Hello World")
+
+		fileUrl, err := repo.UploadFile(bytes.NewReader(content), directory, "abcde-file.txt", "text/plain")
+		assert.NoError(t, err)
+		assert.Contains(t, fileUrl, "files/"+directory+"/abcde-file.txt")
+
+		stored, ok := read(t, fileUrl)
+		assert.True(t, ok)
+		assert.Equal(t, content, stored)
+
+		assert.NoError(t, repo.DeleteImage(fileUrl))
+	})
+
+	// readImage returns the given size of the avatar with the given url
+	readImage := func(t *testing.T, avatarUrl string, size int) ([]byte, bool) {
+		_, path, ok := strings.Cut(avatarUrl, "/images/")
+		assert.True(t, ok)
+
+		sizeUrl, err := repo.ImageUrl(path, size)
+		assert.NoError(t, err)
+
+		return read(t, sizeUrl)
+	}
+
+	t.Run("UploadAvatar", func(t *testing.T) {
+		avatarUrl, err := repo.UploadAvatar(newImageHeader(t, 300, 200), directory)
+		assert.NoError(t, err)
+		assert.Contains(t, avatarUrl, "/images/"+directory+"/")
+
+		// Transparent images stay png images and never get scaled up
+		sizes := map[int]image.Point{64: {64, 42}, 128: {128, 85}, 256: {256, 170}, 512: {300, 200}}
+
+		for size, want := range sizes {
+			stored, ok := readImage(t, avatarUrl, size)
+			assert.True(t, ok)
+
+			img, err := png.Decode(bytes.NewReader(stored))
+			assert.NoError(t, err)
+			assert.Equal(t, want, img.Bounds().Size())
+		}
+
+		assert.NoError(t, repo.DeleteImage(avatarUrl))
+
+		for _, size := range model.ImageSizes {
+			_, ok := readImage(t, avatarUrl, size)
+			assert.False(t, ok)
+		}
+	})
+
+	t.Run("UploadAvatar applies the orientation", func(t *testing.T) {
+		avatarUrl, err := repo.UploadAvatar(newFileHeader(t, "photo.jpg", fixture.NewExifJpeg(300, 200, 6)), directory)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasSuffix(avatarUrl, ".jpeg"))
+
+		stored, ok := readImage(t, avatarUrl, 128)
+		assert.True(t, ok)
+		assert.False(t, bytes.Contains(stored, []byte(fixture.ExifGPSMarker)))
+
+		img, err := jpeg.Decode(bytes.NewReader(stored))
+		assert.NoError(t, err)
+		assert.Equal(t, 85, img.Bounds().Dx())
+		assert.Equal(t, 128, img.Bounds().Dy())
+
+		assert.NoError(t, repo.DeleteImage(avatarUrl))
+	})
+
+	t.Run("UploadAvatar keeps gif animations", func(t *testing.T) {
+		content := fixture.NewAnimatedGif(100, 100, color.RGBA{R: 255, A: 255}, color.RGBA{B: 255, A: 255})
+		avatarUrl, err := repo.UploadAvatar(newFileHeader(t, "avatar.gif", content), directory)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasSuffix(avatarUrl, ".gif"))
+
+		for size, want := range map[int]int{64: 64, 512: 100} {
+			stored, ok := readImage(t, avatarUrl, size)
+			assert.True(t, ok)
+
+			g, err := gif.DecodeAll(bytes.NewReader(stored))
+			assert.NoError(t, err)
+			assert.Len(t, g.Image, 2)
+			assert.Equal(t, []int{10, 10}, g.Delay)
+			assert.Equal(t, image.Pt(want, want), g.Image[0].Bounds().Size())
+			assertColor(t, color.RGBA{R: 255, A: 255}, g.Image[0].At(want/2, want/2))
+			assertColor(t, color.RGBA{B: 255, A: 255}, g.Image[1].At(want/2, want/2))
+		}
+
+		assert.NoError(t, repo.DeleteImage(avatarUrl))
+	})
+
+	t.Run("UploadAvatar keeps webp animations", func(t *testing.T) {
+		content := fixture.NewAnimatedWebp(200, 100, color.NRGBA{G: 255, A: 255}, color.NRGBA{})
+		avatarUrl, err := repo.UploadAvatar(newFileHeader(t, "avatar.webp", content), directory)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasSuffix(avatarUrl, ".webp"))
+
+		stored, ok := readImage(t, avatarUrl, 128)
+		assert.True(t, ok)
+
+		anim, err := decodeWebpAnimation(stored)
+		assert.NoError(t, err)
+		assert.NotNil(t, anim)
+		assert.Equal(t, image.Pt(128, 64), image.Pt(anim.Width, anim.Height))
+		assert.Equal(t, 0, anim.LoopCount)
+		assert.Len(t, anim.Frames, 2)
+
+		for i, want := range []color.NRGBA{{G: 255, A: 255}, {}} {
+			frame := anim.Frames[i]
+			assert.Equal(t, 100, frame.Delay)
+			assert.False(t, frame.Blend)
+			assert.Equal(t, image.Rect(0, 0, 128, 64), frame.Bounds)
+
+			img, err := frame.Decode()
+			assert.NoError(t, err)
+			assert.Equal(t, want, color.NRGBAModel.Convert(img.At(64, 32)))
+		}
+
+		assert.NoError(t, repo.DeleteImage(avatarUrl))
+	})
+
+	t.Run("ImageUrl of an invalid size", func(t *testing.T) {
+		_, err := repo.ImageUrl(directory+"/abcde.png", 100)
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusNotFound, apperrors.Status(err))
+
+		_, err = repo.ImageUrl(directory+"/abcde.txt", 128)
+		assert.Error(t, err)
+	})
+
+	t.Run("UploadThumbnail", func(t *testing.T) {
+		fileUrl, err := repo.UploadThumbnail(bytes.NewReader(newImage(t, 800, 600)), directory, "thumbnail-abcde-image.jpeg")
+		assert.NoError(t, err)
+
+		stored, ok := read(t, fileUrl)
+		assert.True(t, ok)
+
+		img, err := jpeg.Decode(bytes.NewReader(stored))
+		assert.NoError(t, err)
+		assert.Equal(t, model.ThumbnailSize, img.Bounds().Dx())
+		assert.Equal(t, 300, img.Bounds().Dy())
+
+		assert.NoError(t, repo.DeleteImage(fileUrl))
+	})
+
+	t.Run("Invalid image", func(t *testing.T) {
+		_, err := repo.UploadAvatar(newFileHeader(t, "image.png", []byte("not an image")), directory)
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, apperrors.Status(err))
+	})
+
+	t.Run("Animation with too many frames", func(t *testing.T) {
+		colors := make([]color.Color, 0)
+		for i := 0; i < model.MaximumAnimationPixels/(512*512)+1; i++ {
+			colors = append(colors, color.Gray{Y: uint8(i)})
+		}
+
+		_, err := repo.UploadAvatar(newFileHeader(t, "avatar.gif", fixture.NewAnimatedGif(512, 512, colors...)), directory)
+		assert.Error(t, err)
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.AnimationTooLarge), err)
+	})
+
+	t.Run("PresignUpload", func(t *testing.T) {
+		content := []byte("Hello World")
+
+		uploadUrl, fileUrl, err := repo.PresignUpload(directory, "abcde-upload.txt", "text/plain", int64(len(content)))
+		assert.NoError(t, err)
+		assert.Contains(t, fileUrl, "files/"+directory+"/abcde-upload.txt")
+
+		request, err := http.NewRequest(http.MethodPut, uploadUrl, bytes.NewReader(content))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "text/plain")
+
+		response, err := http.DefaultClient.Do(request)
+		assert.NoError(t, err)
+		assert.NoError(t, response.Body.Close())
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+
+		stored, ok := read(t, fileUrl)
+		assert.True(t, ok)
+		assert.Equal(t, content, stored)
+
+		body, size, err := repo.OpenFile(fileUrl)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(content)), size)
+
+		opened, err := io.ReadAll(body)
+		assert.NoError(t, err)
+		assert.NoError(t, body.Close())
+		assert.Equal(t, content, opened)
+
+		assert.NoError(t, repo.DeleteImage(fileUrl))
+	})
+
+	t.Run("OpenFile of a missing file", func(t *testing.T) {
+		_, fileUrl, err := repo.PresignUpload(directory, "abcde-missing.txt", "text/plain", 5)
+		assert.NoError(t, err)
+
+		_, _, err = repo.OpenFile(fileUrl)
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusNotFound, apperrors.Status(err))
+	})
+
+	t.Run("UploadChunk and MergeChunks", func(t *testing.T) {
+		urls := make([]string, 0)
+		for i, chunk := range []string{"Hello", " ", "World"} {
+			chunkUrl, size, err := repo.UploadChunk(directory+"/chunks", fmt.Sprint(i), strings.NewReader(chunk))
+			assert.NoError(t, err)
+			assert.Equal(t, int64(len(chunk)), size)
+			urls = append(urls, chunkUrl)
+		}
+
+		fileUrl, err := repo.MergeChunks(urls, directory, "abcde-merged.txt", "text/plain")
+		assert.NoError(t, err)
+		assert.Contains(t, fileUrl, "files/"+directory+"/abcde-merged.txt")
+
+		stored, ok := read(t, fileUrl)
+		assert.True(t, ok)
+		assert.Equal(t, []byte("Hello World"), stored)
+
+		for _, chunkUrl := range append(urls, fileUrl) {
+			assert.NoError(t, repo.DeleteImage(chunkUrl))
+		}
+	})
+
+	t.Run("MergeChunks with a missing chunk", func(t *testing.T) {
+		chunkUrl, _, err := repo.UploadChunk(directory+"/chunks", "missing", strings.NewReader("Hello"))
+		assert.NoError(t, err)
+		assert.NoError(t, repo.DeleteImage(chunkUrl))
+
+		_, err = repo.MergeChunks([]string{chunkUrl}, directory, "abcde-missing-chunk.txt", "text/plain")
+		assert.Error(t, err)
+	})
+
+	t.Run("DeleteImage", func(t *testing.T) {
+		fileUrl, err := repo.UploadFile(bytes.NewReader([]byte("Hello")), directory, "abcde-delete.txt", "text/plain")
+		assert.NoError(t, err)
+
+		assert.NoError(t, repo.DeleteImage(fileUrl))
+
+		_, ok := read(t, fileUrl)
+		assert.False(t, ok)
+	})
+
+	t.Run("DeleteImage ignores foreign urls", func(t *testing.T) {
+		fileUrl, err := repo.UploadFile(bytes.NewReader([]byte("Hello")), directory, "abcde-keep.txt", "text/plain")
+		assert.NoError(t, err)
+
+		assert.NoError(t, repo.DeleteImage("https://gravatar.com/avatar/abcde?d=identicon"))
+		assert.NoError(t, repo.DeleteImage(""))
+
+		_, ok := read(t, fileUrl)
+		assert.True(t, ok)
+
+		assert.NoError(t, repo.DeleteImage(fileUrl))
+	})
+}
+
+func TestLocalFileRepository(t *testing.T) {
+	root := t.TempDir()
+	secret := "secret"
+
+	server := NewLocalFileServer(root, secret)
+
+	// Accept presigned uploads like the PUT files route
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expires, _ := strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64)
+		key := strings.TrimPrefix(r.URL.Path, "/")
+
+		err := server.Put(key, r.Header.Get("Content-Type"), r.ContentLength, expires, r.URL.Query().Get("signature"), r.Body)
+
+		if err != nil {
+			w.WriteHeader(apperrors.Status(err))
+		}
+	}))
+	defer ts.Close()
+
+	repo := NewLocalFileRepository(root, ts.URL+"/", secret)
+
+	testFileRepository(t, repo, func(t *testing.T, fileUrl string) ([]byte, bool) {
+		u, err := url.Parse(fileUrl)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(fileUrl, ts.URL))
+
+		file, _, err := server.Open(strings.TrimPrefix(u.Path, "/"), u.Query().Get("signature"))
+
+		if err != nil {
+			assert.Equal(t, apperrors.NotFound, err.(*apperrors.Error).Type)
+			return nil, false
+		}
+
+		defer file.Close()
+
+		content, err := io.ReadAll(file)
+		assert.NoError(t, err)
+
+		return content, true
+	})
+}
+
+func TestLocalFileServer(t *testing.T) {
+	root := t.TempDir()
+	secret := "secret"
+
+	repo := NewLocalFileRepository(root, "http://localhost:4000", secret)
+	server := NewLocalFileServer(root, secret)
+
+	fileUrl, err := repo.UploadFile(bytes.NewReader([]byte("Hello")), "test", "abcde-file.txt", "text/plain")
+	assert.NoError(t, err)
+
+	key := "files/test/abcde-file.txt"
+
+	t.Run("Valid signature", func(t *testing.T) {
+		u, err := url.Parse(fileUrl)
+		assert.NoError(t, err)
+
+		file, mimetype, err := server.Open(key, u.Query().Get("signature"))
+		assert.NoError(t, err)
+		assert.Equal(t, "text/plain", mimetype)
+		assert.NoError(t, file.Close())
+	})
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		_, _, err := server.Open(key, signKey("other", key))
+		assert.Error(t, err)
+		assert.Equal(t, apperrors.NotFound, err.(*apperrors.Error).Type)
+	})
+
+	t.Run("Signature of another file", func(t *testing.T) {
+		_, _, err := server.Open("files/test/other.txt", signKey(secret, key))
+		assert.Error(t, err)
+	})
+
+	t.Run("Presigned upload", func(t *testing.T) {
+		content := []byte("Hello World")
+		expires := time.Now().Add(time.Minute).Unix()
+		key := "files/test/abcde-upload.txt"
+		signature := signKey(secret, uploadPayload(key, "text/plain", int64(len(content)), expires))
+
+		err := server.Put(key, "text/plain", int64(len(content)), expires, signature, bytes.NewReader(content))
+		assert.NoError(t, err)
+
+		stored, err := os.ReadFile(filepath.Join(root, "files", "test", "abcde-upload.txt"))
+		assert.NoError(t, err)
+		assert.Equal(t, content, stored)
+
+		// The signed mimetype gets stored with the file
+		file, mimetype, err := server.Open(key, signKey(secret, key))
+		assert.NoError(t, err)
+		assert.Equal(t, "text/plain", mimetype)
+		assert.NoError(t, file.Close())
+	})
+
+	t.Run("Presigned upload with another mimetype", func(t *testing.T) {
+		expires := time.Now().Add(time.Minute).Unix()
+		key := "files/test/abcde-mimetype.txt"
+		signature := signKey(secret, uploadPayload(key, "image/png", 5, expires))
+
+		err := server.Put(key, "text/plain", 5, expires, signature, strings.NewReader("Hello"))
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusUnauthorized, apperrors.Status(err))
+	})
+
+	t.Run("Expired presigned upload", func(t *testing.T) {
+		expires := time.Now().Add(-time.Minute).Unix()
+		key := "files/test/abcde-expired.txt"
+		signature := signKey(secret, uploadPayload(key, "text/plain", 5, expires))
+
+		err := server.Put(key, "text/plain", 5, expires, signature, strings.NewReader("Hello"))
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusUnauthorized, apperrors.Status(err))
+	})
+
+	t.Run("Presigned upload larger than declared", func(t *testing.T) {
+		expires := time.Now().Add(time.Minute).Unix()
+		key := "files/test/abcde-large.txt"
+		signature := signKey(secret, uploadPayload(key, "text/plain", 5, expires))
+
+		err := server.Put(key, "text/plain", 5, expires, signature, strings.NewReader("Hello World"))
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, apperrors.Status(err))
+
+		_, err = os.Stat(filepath.Join(root, "files", "test", "abcde-large.txt"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("Writing outside of the files directory", func(t *testing.T) {
+		outside := t.TempDir()
+
+		for _, filename := range []string{"../../../" + filepath.Base(outside) + "/pwned.txt", "x/../../../pwned.txt"} {
+			_, err := repo.UploadFile(strings.NewReader("pwned"), "test", filename, "text/plain")
+			assert.Error(t, err, filename)
+			assert.Equal(t, http.StatusBadRequest, apperrors.Status(err))
+
+			_, _, err = repo.UploadChunk("test", filename, strings.NewReader("pwned"))
+			assert.Error(t, err, filename)
+		}
+
+		_, err := os.Stat(filepath.Join(root, "pwned.txt"))
+		assert.True(t, os.IsNotExist(err))
+		_, err = os.Stat(filepath.Join(outside, "pwned.txt"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("Outside of the files directory", func(t *testing.T) {
+		err := os.WriteFile(root+"/secret.txt", []byte("secret"), 0644)
+		assert.NoError(t, err)
+
+		for _, key := range []string{"files/../secret.txt", "secret.txt", "../secret.txt"} {
+			_, _, err := server.Open(key, signKey(secret, key))
+			assert.Error(t, err, key)
+		}
+	})
+}
+
+func TestS3FileRepository(t *testing.T) {
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	bucket := os.Getenv("S3_TEST_BUCKET")
+
+	if endpoint == "" || bucket == "" {
+		t.Skip("S3_TEST_ENDPOINT and S3_TEST_BUCKET are not set")
+	}
+
+	region := os.Getenv("S3_TEST_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Credentials: credentials.NewStaticCredentials(
+			os.Getenv("S3_TEST_ACCESS_KEY"),
+			os.Getenv("S3_TEST_SECRET_KEY"),
+			"",
+		),
+		Endpoint:         aws.String(endpoint),
+		Region:           aws.String(region),
+		S3ForcePathStyle: aws.Bool(true),
+	})
+	assert.NoError(t, err)
+
+	repo := NewS3FileRepository(sess, bucket, "http://localhost:4000")
+	srv := s3.New(sess)
+
+	testFileRepository(t, repo, func(t *testing.T, fileUrl string) ([]byte, bool) {
+		key, ok := keyFromUrl(bucketUrl(sess.Config, bucket), fileUrl)
+		assert.True(t, ok)
+
+		object, err := srv.GetObject(&s3.GetObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+
+		if err != nil {
+			if e, ok := err.(awserr.Error); ok && e.Code() == s3.ErrCodeNoSuchKey {
+				return nil, false
+			}
+			t.Fatal(err)
+		}
+
+		defer object.Body.Close()
+
+		content, err := io.ReadAll(object.Body)
+		assert.NoError(t, err)
+
+		return content, true
+	})
+}
+
+func TestBucketUrl(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *aws.Config
+		want   string
+	}{
+		{
+			name:   "AWS S3",
+			config: &aws.Config{Region: aws.String("eu-central-1")},
+			want:   "https://bucket.s3.eu-central-1.amazonaws.com",
+		},
+		{
+			name:   "Path-style endpoint",
+			config: &aws.Config{Endpoint: aws.String("http://localhost:9000/"), S3ForcePathStyle: aws.Bool(true)},
+			want:   "http://localhost:9000/bucket",
+		},
+		{
+			name:   "Virtual-hosted-style endpoint",
+			config: &aws.Config{Endpoint: aws.String("https://storage.example.com")},
+			want:   "https://bucket.storage.example.com",
+		},
+		{
+			name:   "Endpoint without scheme",
+			config: &aws.Config{Endpoint: aws.String("minio:9000"), S3ForcePathStyle: aws.Bool(true)},
+			want:   "https://minio:9000/bucket",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, bucketUrl(tc.config, "bucket"))
+		})
+	}
+}
+
+// newImage returns a png image with the given dimensions
+func newImage(t *testing.T, width, height int) []byte {
+	buf := new(bytes.Buffer)
+	err := png.Encode(buf, image.NewRGBA(image.Rect(0, 0, width, height)))
+	assert.NoError(t, err)
+
+	return buf.Bytes()
+}
+
+// newImageHeader returns the header of a multipart png image with the given dimensions
+func newImageHeader(t *testing.T, width, height int) *multipart.FileHeader {
+	return newFileHeader(t, "image.png", newImage(t, width, height))
+}
+
+// newFileHeader returns the header of a multipart file with the given content
+func newFileHeader(t *testing.T, filename string, content []byte) *multipart.FileHeader {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, filename))
+	part, err := writer.CreatePart(h)
+	assert.NoError(t, err)
+
+	_, err = part.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(body, writer.Boundary()).ReadForm(1 << 20)
+	assert.NoError(t, err)
+
+	return form.File["file"][0]
+}
+
+// assertColor asserts that the colors are equal once converted to the same color model
+func assertColor(t *testing.T, expected, actual color.Color) {
+	assert.Equal(t, color.RGBAModel.Convert(expected), color.RGBAModel.Convert(actual))
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"database/sql"
 	"errors"
 	"github.com/sentrionic/valkyrie/model"
 	"github.com/sentrionic/valkyrie/model/apperrors"
@@ -64,10 +65,17 @@ func (r *guildRepository) GuildMembers(userId string, guildId string) (*[]model.
 		u.username,
 		u.image,
 		u."is_online",
+		u.bot,
 		u."created_at",
 		u."updated_at",
 		m.nickname,
 		m.color,
+		ARRAY(
+			SELECT mr."role_id"
+			FROM member_roles mr
+			WHERE mr."user_id" = m."user_id"
+			AND mr."guild_id" = m."guild_id"
+		) AS roles,
 		EXISTS(
 			SELECT 1
 			FROM users
@@ -116,8 +124,10 @@ func (r *guildRepository) FindUserByID(uid string) (*model.User, error) {
 func (r *guildRepository) FindByID(id string) (*model.Guild, error) {
 	guild := &model.Guild{}
 
+	// Threads do not count as guild channels
 	if err := r.DB.
 		Preload(clause.Associations).
+		Preload("Channels", "parent_id IS NULL").
 		Where("id = ?", id).
 		First(&guild).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -142,6 +152,7 @@ func (r *guildRepository) Save(guild *model.Guild) error {
 // RemoveMember removes the given user from the given guild
 func (r *guildRepository) RemoveMember(userId string, guildId string) error {
 	if result := r.DB.
+		Exec("DELETE FROM member_roles WHERE user_id = ? AND guild_id = ?", userId, guildId).
 		Exec("DELETE FROM members WHERE user_id = ? AND guild_id = ?", userId, guildId); result.Error != nil {
 		log.Printf("Could not remove member with id: %s from the guild with id: %v. Reason: %v\n", userId, guildId, result.Error)
 		return apperrors.NewInternal()
@@ -153,8 +164,12 @@ func (r *guildRepository) RemoveMember(userId string, guildId string) error {
 // Delete removes the given guild and all its associations
 func (r *guildRepository) Delete(guildId string) error {
 	if result := r.DB.
+		Exec("DELETE FROM member_roles WHERE guild_id = ?", guildId).
 		Exec("DELETE FROM members WHERE guild_id = ?", guildId).
 		Exec("DELETE FROM bans WHERE guild_id = ?", guildId).
+		Exec("DELETE FROM commands WHERE guild_id = ?", guildId).
+		Exec("DELETE FROM users WHERE id IN (SELECT id FROM webhooks WHERE guild_id = ?)", guildId).
+		Exec("DELETE FROM webhooks WHERE guild_id = ?", guildId).
 		Exec("DELETE FROM guilds WHERE id = ?", guildId); result.Error != nil {
 		log.Printf("Could not delete the guild with id: %v. Reason: %v\n", guildId, result.Error)
 		return apperrors.NewInternal()
@@ -315,3 +330,138 @@ func (r *guildRepository) GetVCMember(userId, guildId string) (*model.VCMember,
 
 	return &user, result.Error
 }
+
+// GetMemberPermissions returns the combined permissions of all roles the given user has in the given guild.
+// The owner of the guild has all permissions and non members have none.
+func (r *guildRepository) GetMemberPermissions(userId, guildId string) (model.Permission, error) {
+	return getMemberPermissions(r.DB, userId, guildId)
+}
+
+// getMemberPermissions returns the guild level permissions of the given user.
+// Every member has the default permissions in addition to the ones of their roles.
+func getMemberPermissions(db *gorm.DB, userId, guildId string) (model.Permission, error) {
+	var permissions model.Permission
+	result := db.Raw(`
+		SELECT CASE WHEN g."owner_id" = @userId THEN @all
+		ELSE COALESCE(bit_or(r.permissions), 0) | @default END
+		FROM guilds g
+		JOIN members m ON m."guild_id" = g.id AND m."user_id" = @userId
+		LEFT JOIN member_roles mr ON mr."guild_id" = m."guild_id" AND mr."user_id" = m."user_id"
+		LEFT JOIN roles r ON r.id = mr."role_id"
+		WHERE g.id = @guildId
+		GROUP BY g."owner_id"
+	`,
+		sql.Named("userId", userId),
+		sql.Named("guildId", guildId),
+		sql.Named("all", model.AllPermissions),
+		sql.Named("default", model.DefaultPermissions),
+	).Scan(&permissions)
+
+	return permissions, result.Error
+}
+
+// GetMemberPosition returns the position of the highest role the given user has in the given guild.
+// The owner of the guild outranks every role and non members rank below every role.
+func (r *guildRepository) GetMemberPosition(userId, guildId string) (int, error) {
+	position := model.NoRolePosition
+	result := r.DB.Raw(`
+		SELECT CASE WHEN g."owner_id" = @userId THEN @owner
+		ELSE COALESCE(MAX(r.position), @none) END
+		FROM guilds g
+		JOIN members m ON m."guild_id" = g.id AND m."user_id" = @userId
+		LEFT JOIN member_roles mr ON mr."guild_id" = m."guild_id" AND mr."user_id" = m."user_id"
+		LEFT JOIN roles r ON r.id = mr."role_id"
+		WHERE g.id = @guildId
+		GROUP BY g."owner_id"
+	`,
+		sql.Named("userId", userId),
+		sql.Named("guildId", guildId),
+		sql.Named("owner", model.OwnerPosition),
+		sql.Named("none", model.NoRolePosition),
+	).Scan(&position)
+
+	return position, result.Error
+}
+
+// GetRoles returns all roles of the given guild ordered by their position
+func (r *guildRepository) GetRoles(guildId string) (*[]model.RoleResponse, error) {
+	var roles []model.RoleResponse
+	result := r.DB.
+		Table("roles").
+		Where("guild_id = ?", guildId).
+		Order("position DESC, created_at").
+		Find(&roles)
+
+	return &roles, result.Error
+}
+
+// FindRoleByID returns the role for the given id
+func (r *guildRepository) FindRoleByID(roleId string) (*model.Role, error) {
+	role := &model.Role{}
+
+	if err := r.DB.Where("id = ?", roleId).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return role, apperrors.NewNotFound("role", roleId)
+		}
+		return role, apperrors.NewInternal()
+	}
+
+	return role, nil
+}
+
+// CreateRole inserts the given role in the DB
+func (r *guildRepository) CreateRole(role *model.Role) (*model.Role, error) {
+	if result := r.DB.Create(&role); result.Error != nil {
+		log.Printf("Could not create a role for guild: %v. Reason: %v\n", role.GuildId, result.Error)
+		return nil, apperrors.NewInternal()
+	}
+
+	return role, nil
+}
+
+// SaveRole updates the given role
+func (r *guildRepository) SaveRole(role *model.Role) error {
+	if result := r.DB.Save(&role); result.Error != nil {
+		log.Printf("Could not update the role with id: %v. Reason: %v\n", role.ID, result.Error)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// DeleteRole removes the given role, its channel overwrites and unassigns it from all members
+func (r *guildRepository) DeleteRole(roleId string) error {
+	if result := r.DB.
+		Exec("DELETE FROM member_roles WHERE role_id = ?", roleId).
+		Exec("DELETE FROM permission_overwrites WHERE target_id = ?", roleId).
+		Exec("DELETE FROM roles WHERE id = ?", roleId); result.Error != nil {
+		log.Printf("Could not delete the role with id: %v. Reason: %v\n", roleId, result.Error)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// AddMemberRole assigns the role to the member
+func (r *guildRepository) AddMemberRole(memberRole *model.MemberRole) error {
+	if result := r.DB.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&memberRole); result.Error != nil {
+		log.Printf("Could not add the role with id: %v to the member with id: %v. Reason: %v\n", memberRole.RoleID, memberRole.UserID, result.Error)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// RemoveMemberRole unassigns the role from the member
+func (r *guildRepository) RemoveMemberRole(memberRole *model.MemberRole) error {
+	if result := r.DB.
+		Exec("DELETE FROM member_roles WHERE user_id = ? AND guild_id = ? AND role_id = ?",
+			memberRole.UserID, memberRole.GuildID, memberRole.RoleID); result.Error != nil {
+		log.Printf("Could not remove the role with id: %v from the member with id: %v. Reason: %v\n", memberRole.RoleID, memberRole.UserID, result.Error)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
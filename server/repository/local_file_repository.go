@@ -0,0 +1,396 @@
+package repository
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/service"
+	"io"
+	"log"
+	"mime/multipart"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// localFileRepository stores files in the Root directory.
+// The files get served by the FileServer at the PublicUrl
+// and their urls are signed with the Secret.
+// The mime type of every file gets stored at the same key in the typesDirectory.
+type localFileRepository struct {
+	Root      string
+	PublicUrl string
+	Secret    string
+}
+
+// NewLocalFileRepository is a factory for initializing a FileRepository
+// that stores files on the local disk
+func NewLocalFileRepository(root, publicUrl, secret string) model.FileRepository {
+	return &localFileRepository{
+		Root:      root,
+		PublicUrl: strings.TrimSuffix(publicUrl, "/"),
+		Secret:    secret,
+	}
+}
+
+// UploadAvatar stores the given image in all the ImageSizes in the Root directory.
+// Animated images keep their gif or webp format, transparent images turn into png images and all others into jpeg images.
+// It returns the url of the image at the PublicUrl, which redirects to the requested size.
+func (l *localFileRepository) UploadAvatar(header *multipart.FileHeader, directory string) (string, error) {
+	format, images, err := avatarImages(header)
+
+	if err != nil {
+		return "", err
+	}
+
+	path := fmt.Sprintf("%s/%s%s", directory, service.GenerateId(), format.Extension)
+	url := imageUrl(l.PublicUrl, path)
+
+	for size, buf := range images {
+		key, _ := imageKey(path, size)
+
+		if _, err = l.store(buf, key, format.ContentType); err != nil {
+			_ = l.DeleteImage(url)
+			return "", err
+		}
+	}
+
+	return url, nil
+}
+
+// ImageUrl returns the signed url of the given size of the avatar at the path
+func (l *localFileRepository) ImageUrl(path string, size int) (string, error) {
+	key, ok := imageKey(path, size)
+
+	if !ok {
+		return "", apperrors.NewNotFound("image", path)
+	}
+
+	return l.fileUrl(key), nil
+}
+
+// UploadThumbnail stores a preview of the given image in the Root directory.
+// The image gets scaled down to fit into the thumbnail size and turns into a jpeg image.
+// It returns the signed url of the stored thumbnail.
+func (l *localFileRepository) UploadThumbnail(file io.Reader, directory, filename string) (string, error) {
+	key := fmt.Sprintf("files/%s/%s", directory, filename)
+
+	buf, err := thumbnailImage(file)
+
+	if err != nil {
+		return "", err
+	}
+
+	return l.store(buf, key, "image/jpeg")
+}
+
+// UploadFile stores the given file in the Root directory.
+// It returns the signed url of the stored file.
+func (l *localFileRepository) UploadFile(file io.Reader, directory, filename, mimetype string) (string, error) {
+	key := fmt.Sprintf("files/%s/%s", directory, filename)
+
+	return l.store(file, key, mimetype)
+}
+
+// PresignUpload returns a signed url to upload a file with the given mimetype and size
+// to the FileServer followed by the signed url of the uploaded file
+func (l *localFileRepository) PresignUpload(directory, filename, mimetype string, size int64) (string, string, error) {
+	key := fmt.Sprintf("files/%s/%s", directory, filename)
+	expires := time.Now().Add(model.UploadUrlExpiration).Unix()
+
+	signature := signKey(l.Secret, uploadPayload(key, mimetype, size, expires))
+	uploadUrl := fmt.Sprintf("%s/%s?expires=%d&signature=%s", l.PublicUrl, key, expires, signature)
+
+	return uploadUrl, l.fileUrl(key), nil
+}
+
+// OpenFile returns the content and size of the file with the given url
+func (l *localFileRepository) OpenFile(url string) (io.ReadCloser, int64, error) {
+	key, ok := keyFromUrl(l.PublicUrl, url)
+
+	if ok {
+		key, ok = cleanKey(key)
+	}
+
+	if !ok {
+		return nil, 0, apperrors.NewNotFound("file", url)
+	}
+
+	file, err := os.Open(filepath.Join(l.Root, filepath.FromSlash(key)))
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, apperrors.NewNotFound("file", url)
+		}
+
+		log.Printf("Failed to open file: %v\n", err.Error())
+		return nil, 0, apperrors.NewInternal()
+	}
+
+	info, err := file.Stat()
+
+	if err != nil {
+		_ = file.Close()
+		log.Printf("Failed to stat file: %v\n", err.Error())
+		return nil, 0, apperrors.NewInternal()
+	}
+
+	return file, info.Size(), nil
+}
+
+// UploadChunk stores a part of a resumable upload in the Root directory.
+// It returns the signed url and size of the stored chunk.
+func (l *localFileRepository) UploadChunk(directory, filename string, chunk io.Reader) (string, int64, error) {
+	key := fmt.Sprintf("files/%s/%s", directory, filename)
+
+	written, err := writeFile(l.Root, key, "application/octet-stream", chunk)
+
+	if err != nil {
+		return "", 0, err
+	}
+
+	return l.fileUrl(key), written, nil
+}
+
+// MergeChunks stores the concatenation of the chunks with the given urls in the Root directory.
+// It returns the signed url of the stored file.
+func (l *localFileRepository) MergeChunks(urls []string, directory, filename, mimetype string) (string, error) {
+	key := fmt.Sprintf("files/%s/%s", directory, filename)
+
+	chunks := &chunkReader{Open: l.OpenFile, Urls: urls}
+	defer chunks.Close()
+
+	return l.store(chunks, key, mimetype)
+}
+
+// store writes the given body with its mimetype to the key and returns its signed url
+func (l *localFileRepository) store(body io.Reader, key, mimetype string) (string, error) {
+	if _, err := writeFile(l.Root, key, mimetype, body); err != nil {
+		return "", err
+	}
+
+	return l.fileUrl(key), nil
+}
+
+// fileUrl returns the signed url the FileServer serves the given key at
+func (l *localFileRepository) fileUrl(key string) string {
+	return fmt.Sprintf("%s/%s?signature=%s", l.PublicUrl, key, signKey(l.Secret, key))
+}
+
+// writeFile writes the given body to the key in the root directory
+// and its mimetype to the key in the typesDirectory.
+// Keys outside the files directory get rejected.
+// It returns the number of written bytes.
+func writeFile(root, key, mimetype string, body io.Reader) (int64, error) {
+	// Only write resolved keys, so the file cannot end up outside the files directory
+	if cleaned, ok := cleanKey(key); !ok || cleaned != key {
+		log.Printf("Refused to write a file outside the files directory: %v\n", key)
+		return 0, apperrors.NewBadRequest(apperrors.InvalidFilename)
+	}
+
+	name := filepath.Join(root, filepath.FromSlash(key))
+
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		log.Printf("Failed to create directory: %v\n", err.Error())
+		return 0, apperrors.NewInternal()
+	}
+
+	file, err := os.Create(name)
+
+	if err != nil {
+		log.Printf("Failed to create file: %v\n", err.Error())
+		return 0, apperrors.NewInternal()
+	}
+
+	written, err := io.Copy(file, body)
+
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(name)
+		log.Printf("Failed to write file: %v\n", err.Error())
+		return 0, apperrors.NewInternal()
+	}
+
+	if err = file.Close(); err != nil {
+		log.Printf("Failed to close file: %v\n", err.Error())
+		return 0, apperrors.NewInternal()
+	}
+
+	typeName := filepath.Join(root, typesDirectory, filepath.FromSlash(key))
+
+	if err = os.MkdirAll(filepath.Dir(typeName), 0755); err == nil {
+		err = os.WriteFile(typeName, []byte(mimetype), 0644)
+	}
+
+	if err != nil {
+		_ = os.Remove(name)
+		log.Printf("Failed to write file type: %v\n", err.Error())
+		return 0, apperrors.NewInternal()
+	}
+
+	return written, nil
+}
+
+// readFileType returns the mimetype stored for the key in the root directory.
+// Files stored without a mimetype return an empty string.
+func readFileType(root, key string) string {
+	content, err := os.ReadFile(filepath.Join(root, typesDirectory, filepath.FromSlash(key)))
+
+	if err != nil {
+		return ""
+	}
+
+	return string(content)
+}
+
+// removeFile deletes the file with the given key and its mimetype from the root directory
+func removeFile(root, key string) error {
+	err := os.Remove(filepath.Join(root, filepath.FromSlash(key)))
+
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	_ = os.Remove(filepath.Join(root, typesDirectory, filepath.FromSlash(key)))
+
+	return nil
+}
+
+// DeleteImage deletes the file with the given url from the Root directory.
+// Avatar urls delete all sizes of the avatar.
+// Urls that do not belong to the PublicUrl get ignored.
+func (l *localFileRepository) DeleteImage(url string) error {
+	if path, ok := imagePath(l.PublicUrl, url); ok {
+		return l.deleteImageSizes(path)
+	}
+
+	key, ok := keyFromUrl(l.PublicUrl, url)
+
+	if !ok {
+		return nil
+	}
+
+	key, ok = cleanKey(key)
+
+	if !ok {
+		return nil
+	}
+
+	if err := removeFile(l.Root, key); err != nil {
+		log.Printf("Failed to delete image: %v\n", err.Error())
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// deleteImageSizes deletes all sizes of the avatar at the given path and their directory
+func (l *localFileRepository) deleteImageSizes(path string) error {
+	keys := imageKeys(path)
+
+	for _, key := range keys {
+		if err := removeFile(l.Root, key); err != nil {
+			log.Printf("Failed to delete image: %v\n", err.Error())
+			return apperrors.NewInternal()
+		}
+	}
+
+	if len(keys) > 0 {
+		directory := filepath.Dir(filepath.FromSlash(keys[0]))
+		_ = os.Remove(filepath.Join(l.Root, directory))
+		_ = os.Remove(filepath.Join(l.Root, typesDirectory, directory))
+	}
+
+	return nil
+}
+
+// localFileServer serves the files of the Root directory
+// whose signature was created with the Secret
+type localFileServer struct {
+	Root   string
+	Secret string
+}
+
+// NewLocalFileServer is a factory for initializing the FileServer
+// for files stored by the local FileRepository
+func NewLocalFileServer(root, secret string) model.FileServer {
+	return &localFileServer{
+		Root:   root,
+		Secret: secret,
+	}
+}
+
+// Open opens the file with the given key if the signature is valid for it.
+// It also returns the mimetype the file got stored with, which is empty for unknown types.
+func (l *localFileServer) Open(key, signature string) (*os.File, string, error) {
+	key, ok := cleanKey(key)
+
+	if !ok || !hmac.Equal([]byte(signature), []byte(signKey(l.Secret, key))) {
+		return nil, "", apperrors.NewNotFound("file", key)
+	}
+
+	file, err := os.Open(filepath.Join(l.Root, filepath.FromSlash(key)))
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", apperrors.NewNotFound("file", key)
+		}
+
+		log.Printf("Failed to open file: %v\n", err.Error())
+		return nil, "", apperrors.NewInternal()
+	}
+
+	return file, readFileType(l.Root, key), nil
+}
+
+// Put stores the body at the given key if the signature of the presigned upload
+// is valid for the key, mimetype, size and expiration time of the upload
+func (l *localFileServer) Put(key, mimetype string, size, expires int64, signature string, body io.Reader) error {
+	key, ok := cleanKey(key)
+	expected := signKey(l.Secret, uploadPayload(key, mimetype, size, expires))
+
+	if !ok || time.Now().Unix() > expires || !hmac.Equal([]byte(signature), []byte(expected)) {
+		return apperrors.NewAuthorization(apperrors.UploadUrlError)
+	}
+
+	// Read one more byte than declared to detect bodies that are too large
+	written, err := writeFile(l.Root, key, mimetype, io.LimitReader(body, size+1))
+
+	if err != nil {
+		return err
+	}
+
+	if written != size {
+		_ = removeFile(l.Root, key)
+		return apperrors.NewBadRequest(apperrors.UploadMismatchError)
+	}
+
+	return nil
+}
+
+// typesDirectory is the directory in the root directory the mimetypes of the stored files get written to.
+// It is outside the files directory, so the FileServer never serves it.
+const typesDirectory = "types"
+
+// uploadPayload returns the content of the signature of a presigned upload
+func uploadPayload(key, mimetype string, size, expires int64) string {
+	return fmt.Sprintf("PUT\n%s\n%s\n%d\n%d", key, mimetype, size, expires)
+}
+
+// cleanKey resolves the given key and makes sure it stays inside the files directory
+func cleanKey(key string) (string, bool) {
+	key = strings.TrimPrefix(path.Clean("/"+key), "/")
+	return key, strings.HasPrefix(key, "files/")
+}
+
+// signKey returns the url safe HMAC-SHA256 signature of the given key
+func signKey(secret, key string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(key))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
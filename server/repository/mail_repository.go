@@ -1,39 +1,98 @@
 package repository
 
 import (
+	"bytes"
+	"embed"
 	"fmt"
 	"github.com/sentrionic/valkyrie/model"
-	"net/smtp"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	htmltemplate "html/template"
+	"log"
+	texttemplate "text/template"
 )
 
-// mailRepository contains the gmail username and password
-// as well as the frontend origin.
+//go:embed templates/mail
+var mailTemplateFiles embed.FS
+
+// Every mail has an HTML and a plain text template with the same name
+var (
+	htmlMailTemplates = htmltemplate.Must(htmltemplate.ParseFS(mailTemplateFiles, "templates/mail/*.html"))
+	textMailTemplates = texttemplate.Must(texttemplate.ParseFS(mailTemplateFiles, "templates/mail/*.txt"))
+)
+
+// mailRepository renders the mails from their templates and delivers them with the transport.
+// The mails are sent from the from address and their links point to the frontend origin.
 type mailRepository struct {
-	username string
-	password string
-	origin   string
+	transport model.MailTransport
+	from      string
+	origin    string
 }
 
 // NewMailRepository is a factory for initializing Mail Repositories
-func NewMailRepository(username string, password string, origin string) model.MailRepository {
+func NewMailRepository(transport model.MailTransport, from string, origin string) model.MailRepository {
 	return &mailRepository{
-		username: username,
-		password: password,
-		origin:   origin,
+		transport: transport,
+		from:      from,
+		origin:    origin,
 	}
 }
 
+// resetMailData is the data of the reset_password templates
+type resetMailData struct {
+	Url string
+}
+
 // SendResetMail sends a password reset email with the given reset token
 func (m *mailRepository) SendResetMail(email string, token string) error {
+	data := resetMailData{
+		Url: fmt.Sprintf("%s/reset-password/%s", m.origin, token),
+	}
 
-	msg := "From: " + m.username + "\n" +
-		"To: " + email + "\n" +
-		"Subject: Reset Email\n\n" +
-		fmt.Sprintf("<a href=\"%s/reset-password/%s\">Reset Password</a>", m.origin, token)
+	return m.send(email, "Reset your password", "reset_password", data)
+}
+
+// verifyMailData is the data of the verify_email templates
+type verifyMailData struct {
+	Url string
+}
+
+// SendVerificationMail sends an email with the link that verifies the email address
+func (m *mailRepository) SendVerificationMail(email string, token string) error {
+	data := verifyMailData{
+		Url: fmt.Sprintf("%s/verify-email/%s", m.origin, token),
+	}
 
-	err := smtp.SendMail("smtp.gmail.com:587",
-		smtp.PlainAuth("", m.username, m.password, "smtp.gmail.com"),
-		m.username, []string{email}, []byte(msg))
+	return m.send(email, "Verify your email address", "verify_email", data)
+}
+
+// send renders the templates with the given name and sends the mail to the email
+func (m *mailRepository) send(email, subject, name string, data any) error {
+	html := new(bytes.Buffer)
+
+	if err := htmlMailTemplates.ExecuteTemplate(html, name+".html", data); err != nil {
+		log.Printf("Failed to render mail: %v\n", err.Error())
+		return apperrors.NewInternal()
+	}
+
+	text := new(bytes.Buffer)
+
+	if err := textMailTemplates.ExecuteTemplate(text, name+".txt", data); err != nil {
+		log.Printf("Failed to render mail: %v\n", err.Error())
+		return apperrors.NewInternal()
+	}
+
+	err := m.transport.Send(&model.Mail{
+		From:    m.from,
+		To:      email,
+		Subject: subject,
+		HTML:    html.String(),
+		Text:    text.String(),
+	})
+
+	if err != nil {
+		log.Printf("Failed to send mail: %v\n", err.Error())
+		return apperrors.NewInternal()
+	}
 
-	return err
+	return nil
 }
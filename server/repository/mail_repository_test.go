@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// mailRecorder is a MailTransport that keeps the sent mails
+type mailRecorder struct {
+	mails []*model.Mail
+	err   error
+}
+
+func (r *mailRecorder) Send(m *model.Mail) error {
+	r.mails = append(r.mails, m)
+	return r.err
+}
+
+func TestMailRepository_SendResetMail(t *testing.T) {
+	t.Run("Renders both parts", func(t *testing.T) {
+		transport := &mailRecorder{}
+		repo := NewMailRepository(transport, "Valkyrie <noreply@valkyrie.test>", "http://localhost:3000")
+
+		err := repo.SendResetMail("test@example.com", "abcde")
+		assert.NoError(t, err)
+
+		assert.Len(t, transport.mails, 1)
+		sent := transport.mails[0]
+
+		assert.Equal(t, "Valkyrie <noreply@valkyrie.test>", sent.From)
+		assert.Equal(t, "test@example.com", sent.To)
+		assert.Equal(t, "Reset your password", sent.Subject)
+		assert.Contains(t, sent.HTML, `href="http://localhost:3000/reset-password/abcde"`)
+		assert.Contains(t, sent.Text, "http://localhost:3000/reset-password/abcde")
+		assert.NotContains(t, sent.Text, "<")
+	})
+
+	t.Run("Transport failure", func(t *testing.T) {
+		transport := &mailRecorder{err: fmt.Errorf("connection refused")}
+		repo := NewMailRepository(transport, "noreply@valkyrie.test", "http://localhost:3000")
+
+		err := repo.SendResetMail("test@example.com", "abcde")
+		assert.Error(t, err)
+		assert.Equal(t, apperrors.Internal, err.(*apperrors.Error).Type)
+	})
+}
+
+func TestComposeMail(t *testing.T) {
+	t.Run("Multipart message", func(t *testing.T) {
+		m := &model.Mail{
+			From:    "Valkyrie <noreply@valkyrie.test>",
+			To:      "test@example.com",
+			Subject: "Grüße",
+			HTML:    "<p>Hello World</p>",
+			Text:    "Hello World",
+		}
+
+		content, err := composeMail(m)
+		assert.NoError(t, err)
+
+		text, html := readTestMail(t, content)
+		assert.Equal(t, "Hello World", text)
+		assert.Equal(t, "<p>Hello World</p>", html)
+	})
+
+	t.Run("Invalid recipient", func(t *testing.T) {
+		m := &model.Mail{
+			From: "noreply@valkyrie.test",
+			To:   "test@example.com\r\nBcc: other@example.com",
+		}
+
+		_, err := composeMail(m)
+		assert.Error(t, err)
+	})
+}
+
+func TestFileTransport(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mails")
+	transport := NewFileTransport(dir)
+
+	err := transport.Send(&model.Mail{
+		From:    "noreply@valkyrie.test",
+		To:      "test@example.com",
+		Subject: "Reset your password",
+		HTML:    "<p>Hello World</p>",
+		Text:    "Hello World",
+	})
+	assert.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	content, err := os.ReadFile(files[0])
+	assert.NoError(t, err)
+
+	text, html := readTestMail(t, content)
+	assert.Equal(t, "Hello World", text)
+	assert.Equal(t, "<p>Hello World</p>", html)
+}
+
+func TestSMTPTransport(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan smtpSession, 1)
+	go serveTestSMTP(listener, received)
+
+	addr := listener.Addr().(*net.TCPAddr)
+	transport := NewSMTPTransport(&SMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     addr.Port,
+		Security: model.SMTPSecurityNone,
+	})
+
+	err = transport.Send(&model.Mail{
+		From:    "Valkyrie <noreply@valkyrie.test>",
+		To:      "test@example.com",
+		Subject: "Reset your password",
+		HTML:    "<p>Hello World</p>",
+		Text:    "Hello World",
+	})
+	assert.NoError(t, err)
+
+	session := <-received
+	assert.Equal(t, "<noreply@valkyrie.test>", session.From)
+	assert.Equal(t, []string{"<test@example.com>"}, session.To)
+
+	text, html := readTestMail(t, session.Data)
+	assert.Equal(t, "Hello World", text)
+	assert.Equal(t, "<p>Hello World</p>", html)
+
+	t.Run("STARTTLS required", func(t *testing.T) {
+		go serveTestSMTP(listener, received)
+
+		transport := NewSMTPTransport(&SMTPConfig{
+			Host:     "127.0.0.1",
+			Port:     addr.Port,
+			Security: model.SMTPSecurityStartTLS,
+		})
+
+		err := transport.Send(&model.Mail{From: "noreply@valkyrie.test", To: "test@example.com"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "STARTTLS")
+	})
+}
+
+// smtpSession is the envelope and data an SMTP client sent
+type smtpSession struct {
+	From string
+	To   []string
+	Data []byte
+}
+
+// serveTestSMTP accepts a single connection and answers like an SMTP server without extensions
+func serveTestSMTP(listener net.Listener, received chan<- smtpSession) {
+	conn, err := listener.Accept()
+
+	if err != nil {
+		return
+	}
+
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	session := smtpSession{}
+
+	fmt.Fprint(conn, "220 localhost ESMTP\r\n")
+
+	for {
+		line, err := r.ReadString('\n')
+
+		if err != nil {
+			return
+		}
+
+		command := strings.ToUpper(strings.TrimSpace(line))
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			fmt.Fprint(conn, "250 localhost\r\n")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			session.From = strings.TrimSpace(line)[len("MAIL FROM:"):]
+			fmt.Fprint(conn, "250 OK\r\n")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			session.To = append(session.To, strings.TrimSpace(line)[len("RCPT TO:"):])
+			fmt.Fprint(conn, "250 OK\r\n")
+		case command == "DATA":
+			fmt.Fprint(conn, "354 End data with <CR><LF>.<CR><LF>\r\n")
+
+			data := new(bytes.Buffer)
+			for {
+				line, err := r.ReadString('\n')
+
+				if err != nil {
+					return
+				}
+
+				if line == ".\r\n" {
+					break
+				}
+
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+
+			session.Data = data.Bytes()
+			fmt.Fprint(conn, "250 OK\r\n")
+		case command == "QUIT":
+			fmt.Fprint(conn, "221 Bye\r\n")
+			received <- session
+			return
+		default:
+			fmt.Fprint(conn, "502 Command not implemented\r\n")
+		}
+	}
+}
+
+// readTestMail parses the MIME message and returns its decoded plain text and HTML parts
+func readTestMail(t *testing.T, content []byte) (string, string) {
+	msg, err := mail.ReadMessage(bytes.NewReader(content))
+	assert.NoError(t, err)
+
+	assert.NotEmpty(t, msg.Header.Get("Date"))
+	assert.NotEmpty(t, msg.Header.Get("Message-Id"))
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, subject)
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	assert.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	parts := make(map[string]string)
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+
+	for {
+		part, err := reader.NextRawPart()
+
+		if err == io.EOF {
+			break
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, "quoted-printable", part.Header.Get("Content-Transfer-Encoding"))
+
+		body, err := io.ReadAll(quotedprintable.NewReader(part))
+		assert.NoError(t, err)
+
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[contentType] = string(body)
+	}
+
+	return parts["text/plain"], parts["text/html"]
+}
+
+func TestMailRepository_SendVerificationMail(t *testing.T) {
+	transport := &mailRecorder{}
+	repo := NewMailRepository(transport, "noreply@valkyrie.test", "http://localhost:3000")
+
+	err := repo.SendVerificationMail("test@example.com", "abcde")
+	assert.NoError(t, err)
+
+	assert.Len(t, transport.mails, 1)
+	sent := transport.mails[0]
+
+	assert.Equal(t, "test@example.com", sent.To)
+	assert.Equal(t, "Verify your email address", sent.Subject)
+	assert.Contains(t, sent.HTML, `href="http://localhost:3000/verify-email/abcde"`)
+	assert.Contains(t, sent.Text, "http://localhost:3000/verify-email/abcde")
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/service"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// composeMail returns the mail as a multipart/alternative MIME message
+// with the plain text part first, so clients prefer the HTML part
+func composeMail(m *model.Mail) ([]byte, error) {
+	from, err := mail.ParseAddress(m.From)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender: %w", err)
+	}
+
+	to, err := mail.ParseAddress(m.To)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient: %w", err)
+	}
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{contentType: "text/plain; charset=utf-8", content: m.Text},
+		{contentType: "text/html; charset=utf-8", content: m.HTML},
+	}
+
+	for _, part := range parts {
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Type", part.contentType)
+		h.Set("Content-Transfer-Encoding", "quoted-printable")
+
+		w, err := writer.CreatePart(h)
+
+		if err != nil {
+			return nil, err
+		}
+
+		qp := quotedprintable.NewWriter(w)
+
+		if _, err = qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+
+		if err = qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+
+	_, domain, _ := strings.Cut(from.Address, "@")
+
+	msg := new(bytes.Buffer)
+	fmt.Fprintf(msg, "From: %s\r\n", from.String())
+	fmt.Fprintf(msg, "To: %s\r\n", to.String())
+	fmt.Fprintf(msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(msg, "Message-ID: <%s@%s>\r\n", service.GenerateId(), domain)
+	fmt.Fprintf(msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(msg, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", writer.Boundary())
+	_, _ = body.WriteTo(msg)
+
+	return msg.Bytes(), nil
+}
+
+// SMTPConfig holds the server and the credentials of the SMTP driver.
+// Security is one of the SMTP security modes and no credentials skip the authentication.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Security string
+	Username string
+	Password string
+}
+
+// smtpTransport sends mails to an SMTP server
+type smtpTransport struct {
+	SMTPConfig
+}
+
+// NewSMTPTransport is a factory for initializing a MailTransport
+// that sends the mails with the given SMTP server
+func NewSMTPTransport(c *SMTPConfig) model.MailTransport {
+	return &smtpTransport{SMTPConfig: *c}
+}
+
+// smtpTimeout limits how long the delivery of a single mail can take
+const smtpTimeout = 30 * time.Second
+
+// Send delivers the mail using implicit TLS, STARTTLS or a plain connection
+// depending on the Security of the transport
+func (t *smtpTransport) Send(m *model.Mail) error {
+	msg, err := composeMail(m)
+
+	if err != nil {
+		return err
+	}
+
+	from, _ := mail.ParseAddress(m.From)
+	to, _ := mail.ParseAddress(m.To)
+
+	addr := net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+	tlsConfig := &tls.Config{ServerName: t.Host}
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+
+	var conn net.Conn
+
+	if t.Security == model.SMTPSecurityTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+	client, err := smtp.NewClient(conn, t.Host)
+
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	defer client.Close()
+
+	if t.Security == model.SMTPSecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("smtp server %s does not support STARTTLS", addr)
+		}
+
+		if err = client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+
+	if t.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", t.Username, t.Password, t.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err = client.Mail(from.Address); err != nil {
+		return err
+	}
+
+	if err = client.Rcpt(to.Address); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.Write(msg); err != nil {
+		return err
+	}
+
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// fileTransport writes the mails as .eml files into the Dir directory
+type fileTransport struct {
+	Dir string
+}
+
+// NewFileTransport is a factory for initializing a MailTransport
+// that stores the mails in the given directory instead of sending them
+func NewFileTransport(dir string) model.MailTransport {
+	return &fileTransport{Dir: dir}
+}
+
+// Send writes the mail to a new .eml file
+func (t *fileTransport) Send(m *model.Mail) error {
+	msg, err := composeMail(m)
+
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(t.Dir, 0755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405"), service.GenerateId())
+
+	return os.WriteFile(filepath.Join(t.Dir, name), msg, 0644)
+}
+
+// logTransport prints the mails to the given Writer for development
+type logTransport struct {
+	Out io.Writer
+}
+
+// NewLogTransport is a factory for initializing a MailTransport
+// that only logs the recipient, subject and plain text of the mails
+func NewLogTransport() model.MailTransport {
+	return &logTransport{Out: log.Writer()}
+}
+
+// Send prints the mail
+func (t *logTransport) Send(m *model.Mail) error {
+	_, err := fmt.Fprintf(t.Out, "Mail to %s: %s\n%s\n", m.To, m.Subject, m.Text)
+	return err
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/sentrionic/valkyrie/model"
 	"github.com/sentrionic/valkyrie/model/apperrors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"log"
 	"time"
 )
@@ -30,42 +31,118 @@ type messageQuery struct {
 	Text          *string
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
-	FileType      *string
-	Url           *string
-	Filename      *string
-	AttachmentId  *string
 	UserId        string
 	UserCreatedAt time.Time
 	UserUpdatedAt time.Time
 	Username      string
 	Image         string
 	IsOnline      bool
+	Bot           bool
 	Nickname      *string
 	Color         *string
 	IsFriend      bool
+	ReplyToId     *string
+	ReplyId       *string
+	ReplyText     *string
+	ReplyHasFile  bool
+	ReplyUserId   *string
+	ReplyUsername *string
+	ReplyImage    *string
+	ReplyNickname *string
+	ReplyColor    *string
+	ThreadId      *string
+	Pinned        bool
+	EditCount     int
 }
 
-// GetMessages returns the 35 most recent messages for the given channel.
-// If a cursor is specified it returns the 35 messages after the cursor.
-func (r *messageRepository) GetMessages(userId string, channel *model.Channel, cursor string) (*[]model.MessageResponse, error) {
+// Orders of the message pages, ties on the creation date are broken by the ID
+const (
+	newestFirst = "ORDER BY messages.created_at DESC, messages.id DESC LIMIT @limit"
+	oldestFirst = "ORDER BY messages.created_at ASC, messages.id ASC LIMIT @limit"
+)
+
+// cursorPosition selects the position of the cursor message in the channel
+const cursorPosition = "(SELECT created_at, id FROM messages WHERE id = @cursor AND channel_id = @channelId)"
+
+// GetMessages returns a page of messages for the given channel, newest first.
+// Before and After return the messages older or newer than the cursor message.
+// Around returns the cursor message and the messages surrounding it.
+func (r *messageRepository) GetMessages(userId string, channel *model.Channel, page *model.MessagePage) (*[]model.MessageResponse, error) {
+	switch {
+	case page.Before != nil:
+		return r.findMessages(userId, channel,
+			"AND (messages.created_at, messages.id) < "+cursorPosition, newestFirst,
+			sql.Named("cursor", *page.Before), sql.Named("limit", page.Limit))
+	case page.After != nil:
+		newer, err := r.findMessages(userId, channel,
+			"AND (messages.created_at, messages.id) > "+cursorPosition, oldestFirst,
+			sql.Named("cursor", *page.After), sql.Named("limit", page.Limit))
+
+		if err != nil {
+			return nil, err
+		}
+
+		reverseMessages(*newer)
+		return newer, nil
+	case page.Around != nil:
+		// Include the cursor message in the older half
+		older, err := r.findMessages(userId, channel,
+			"AND (messages.created_at, messages.id) <= "+cursorPosition, newestFirst,
+			sql.Named("cursor", *page.Around), sql.Named("limit", (page.Limit+1)/2))
+
+		if err != nil {
+			return nil, err
+		}
+
+		newer, err := r.findMessages(userId, channel,
+			"AND (messages.created_at, messages.id) > "+cursorPosition, oldestFirst,
+			sql.Named("cursor", *page.Around), sql.Named("limit", page.Limit/2))
+
+		if err != nil {
+			return nil, err
+		}
+
+		reverseMessages(*newer)
+		messages := append(*newer, *older...)
+		return &messages, nil
+	default:
+		return r.findMessages(userId, channel, "", newestFirst, sql.Named("limit", page.Limit))
+	}
+}
+
+// reverseMessages reverses the order of the given messages in place
+func reverseMessages(messages []model.MessageResponse) {
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+}
+
+// GetPinnedMessages returns the pinned messages of the given channel, most recently pinned first
+func (r *messageRepository) GetPinnedMessages(userId string, channel *model.Channel) (*[]model.MessageResponse, error) {
+	return r.findMessages(userId, channel, "AND messages.pinned_at IS NOT NULL", "ORDER BY messages.pinned_at DESC")
+}
+
+// findMessages returns the messages of the given channel matching the filter in the given order.
+// Additional named arguments used by the filter can be passed as args.
+func (r *messageRepository) findMessages(userId string, channel *model.Channel, filter, order string, args ...interface{}) (*[]model.MessageResponse, error) {
 	var result []messageQuery
 
 	memberSelect := ""
 	memberJoin := ""
 	memberWhere := ""
 
-	// If the channel is not a DM channel, also fetch the message author's settings
+	// If the channel is not a DM channel, also fetch the message author's
+	// and the replied to author's settings
 	if !channel.IsDM {
-		memberSelect = "member.nickname, member.color,"
-		memberJoin = "LEFT JOIN members member on messages.user_id = member.user_id"
-		memberWhere = fmt.Sprintf("AND member.guild_id = %s::text", *channel.GuildID)
-	}
-
-	crs := ""
-	if cursor != "" {
-		// Remove the timezone from the string since it's stored differently in the DB
-		date := cursor[:len(cursor)-6]
-		crs = fmt.Sprintf("AND messages.created_at < '%s'", date)
+		memberSelect = `member.nickname, member.color,
+			reply_member.nickname as "reply_nickname",
+			reply_member.color    as "reply_color",`
+		memberJoin = `LEFT JOIN members member on messages.user_id = member.user_id
+		LEFT JOIN members reply_member
+		ON reply_member.user_id = reply.user_id AND reply_member.guild_id = @guildId
+		LEFT JOIN webhooks webhook ON webhook.id = messages.user_id`
+		// Webhooks are not members of the guild
+		memberWhere = "AND (member.guild_id = @guildId OR webhook.id IS NOT NULL)"
 	}
 
 	err := r.DB.
@@ -74,16 +151,26 @@ func (r *messageRepository) GetMessages(userId string, channel *model.Channel, c
 			messages.text,
 			messages.created_at,
 			messages.updated_at,
-			a.file_type,
-			a.url,
-			a.filename,
-			a.id                as "attachment_id",
 			users.id         as "user_id",
 			users.created_at as "user_created_at",
 			users.updated_at as "user_updated_at",
 			users.username,
 			users.image,
 			users.is_online,
+			users.bot,
+			messages.reply_to_id,
+			reply.id            as "reply_id",
+			reply.text          as "reply_text",
+			EXISTS(
+			  SELECT 1
+			  FROM attachments
+			  WHERE attachments.message_id = reply.id) as "reply_has_file",
+			reply_user.id       as "reply_user_id",
+			reply_user.username as "reply_username",
+			reply_user.image    as "reply_image",
+			thread.id           as "thread_id",
+			messages.pinned_at IS NOT NULL as "pinned",
+			messages.edit_count,
 			%s 
 			EXISTS(
 			  SELECT 1
@@ -94,39 +181,62 @@ func (r *messageRepository) GetMessages(userId string, channel *model.Channel, c
 		FROM messages
 		LEFT JOIN "users"
 		ON users.id = messages.user_id
-		LEFT JOIN attachments a
-		ON a.message_id = messages.id
+		LEFT JOIN messages reply
+		ON reply.id = messages.reply_to_id
+		LEFT JOIN "users" reply_user
+		ON reply_user.id = reply.user_id
+		LEFT JOIN channels thread
+		ON thread.parent_message_id = messages.id
 		%s
 		WHERE messages.channel_id = @channelId
 		%s 
 		%s 
-		ORDER BY messages.created_at DESC
-		LIMIT 35
-`, memberSelect, memberJoin, memberWhere, crs),
-			sql.Named("userId", userId),
-			sql.Named("channelId", channel.ID)).
+		%s
+`, memberSelect, memberJoin, memberWhere, filter, order),
+			append([]interface{}{
+				sql.Named("userId", userId),
+				sql.Named("channelId", channel.ID),
+				sql.Named("guildId", channel.GuildID),
+			}, args...)...).
 		Scan(&result).Error
 
+	if err != nil {
+		return nil, err
+	}
+
+	reactions, err := r.getReactions(userId, result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(result))
+	for i, m := range result {
+		ids[i] = m.Id
+	}
+
+	mentions, err := r.getMentions(channel, ids)
+
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := r.getAttachments(ids)
+
+	if err != nil {
+		return nil, err
+	}
+
 	var messages []model.MessageResponse
 
 	// Turn messageQuery results into MessageResponse
 	for _, m := range result {
-
-		var attachment *model.Attachment = nil
-		if m.AttachmentId != nil {
-			attachment = &model.Attachment{
-				Url:      *m.Url,
-				FileType: *m.FileType,
-				Filename: *m.Filename,
-			}
-		}
-
 		message := model.MessageResponse{
-			Id:         m.Id,
-			Text:       m.Text,
-			CreatedAt:  m.CreatedAt,
-			UpdatedAt:  m.UpdatedAt,
-			Attachment: attachment,
+			Id:          m.Id,
+			Text:        m.Text,
+			CreatedAt:   m.CreatedAt,
+			UpdatedAt:   m.UpdatedAt,
+			Attachments: attachments[m.Id],
 			User: model.MemberResponse{
 				Id:        m.UserId,
 				Username:  m.Username,
@@ -137,33 +247,228 @@ func (r *messageRepository) GetMessages(userId string, channel *model.Channel, c
 				Nickname:  m.Nickname,
 				Color:     m.Color,
 				IsFriend:  m.IsFriend,
+				Bot:       m.Bot,
 			},
+			Reactions: reactions[m.Id],
+			ReplyTo:   m.toReference(),
+			ThreadId:  m.ThreadId,
+			Pinned:    m.Pinned,
+			Mentions:  mentions[m.Id],
+			Edited:    m.EditCount > 0,
+			EditCount: m.EditCount,
+		}
+
+		if message.Reactions == nil {
+			message.Reactions = make([]model.ReactionResponse, 0)
+		}
+
+		if message.Attachments == nil {
+			message.Attachments = make([]model.Attachment, 0)
 		}
+
 		messages = append(messages, message)
 	}
 
-	return &messages, err
+	return &messages, nil
+}
+
+// toReference turns the replied to message fields into a MessageReference.
+// Returns nil if the message is not a reply.
+func (m *messageQuery) toReference() *model.MessageReference {
+	if m.ReplyToId == nil {
+		return nil
+	}
+
+	// The referenced message got deleted
+	if m.ReplyId == nil || m.ReplyUserId == nil {
+		return model.DeletedMessageReference(*m.ReplyToId)
+	}
+
+	return model.NewMessageReference(*m.ReplyId, m.ReplyText, m.ReplyHasFile, &model.ReferenceAuthor{
+		Id:       *m.ReplyUserId,
+		Username: *m.ReplyUsername,
+		Image:    *m.ReplyImage,
+		Nickname: m.ReplyNickname,
+		Color:    m.ReplyColor,
+	})
+}
+
+// GetMessageReactions returns the aggregated reactions of the given message.
+// Me is never set, since the result is not specific to a user.
+func (r *messageRepository) GetMessageReactions(messageId string) ([]model.ReactionResponse, error) {
+	reactions, err := r.getReactions("", []messageQuery{{Id: messageId}})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if reactions[messageId] == nil {
+		return make([]model.ReactionResponse, 0), nil
+	}
+
+	return reactions[messageId], nil
+}
+
+// reactionQuery represents the fetched fields for getReactions
+type reactionQuery struct {
+	MessageId string
+	Emoji     string
+	Count     int
+	Me        bool
+}
+
+// getReactions returns the aggregated reactions of the given messages grouped by message ID.
+// Emojis are ordered by their first use.
+func (r *messageRepository) getReactions(userId string, messages []messageQuery) (map[string][]model.ReactionResponse, error) {
+	reactions := make(map[string][]model.ReactionResponse)
+
+	if len(messages) == 0 {
+		return reactions, nil
+	}
+
+	ids := make([]string, len(messages))
+	for i, m := range messages {
+		ids[i] = m.Id
+	}
+
+	var result []reactionQuery
+
+	err := r.DB.
+		Raw(`
+		SELECT message_id,
+			emoji,
+			COUNT(*)                   as "count",
+			BOOL_OR(user_id = @userId) as "me"
+		FROM reactions
+		WHERE message_id IN @ids
+		GROUP BY message_id, emoji
+		ORDER BY MIN(created_at)
+`,
+			sql.Named("userId", userId),
+			sql.Named("ids", ids)).
+		Scan(&result).Error
+
+	if err != nil {
+		log.Printf("Could not get the reactions of the messages. Reason: %v\n", err)
+		return nil, apperrors.NewInternal()
+	}
+
+	for _, q := range result {
+		reactions[q.MessageId] = append(reactions[q.MessageId], model.ReactionResponse{
+			Emoji: q.Emoji,
+			Count: q.Count,
+			Me:    q.Me,
+		})
+	}
+
+	return reactions, nil
+}
+
+// getAttachments returns the attachments of the given messages grouped by message ID
+func (r *messageRepository) getAttachments(ids []string) (map[string][]model.Attachment, error) {
+	attachments := make(map[string][]model.Attachment)
+
+	if len(ids) == 0 {
+		return attachments, nil
+	}
+
+	var result []model.Attachment
+
+	if err := r.DB.
+		Where("message_id IN ?", ids).
+		Order("position ASC").
+		Find(&result).Error; err != nil {
+		log.Printf("Could not get the attachments of the messages. Reason: %v\n", err)
+		return nil, apperrors.NewInternal()
+	}
+
+	for _, attachment := range result {
+		attachments[attachment.MessageId] = append(attachments[attachment.MessageId], attachment)
+	}
+
+	return attachments, nil
 }
 
 // CreateMessage inserts the message in the DB
 func (r *messageRepository) CreateMessage(message *model.Message) (*model.Message, error) {
-	if result := r.DB.Create(&message); result.Error != nil {
-		log.Printf("Could not create a message for user: %v. Reason: %v\n", message.UserId, result.Error)
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&message).Error; err != nil {
+			return err
+		}
+
+		// Attached uploads must not get garbage collected
+		ids := make([]string, 0)
+		for _, attachment := range message.Attachments {
+			ids = append(ids, attachment.ID)
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		return tx.Where("id IN ?", ids).Delete(&model.Upload{}).Error
+	})
+
+	if err != nil {
+		log.Printf("Could not create a message for user: %v. Reason: %v\n", message.UserId, err)
 		return nil, apperrors.NewInternal()
 	}
 
 	return message, nil
 }
 
-// UpdateMessage updates the message in the DB
+// UpdateMessage updates the message in the DB and replaces its mentions.
+// If the text changed the previous text gets stored as a MessageRevision.
 func (r *messageRepository) UpdateMessage(message *model.Message) error {
-	if result := r.DB.Save(&message); result.Error != nil {
-		log.Printf("Could not update message with id: %v. Reason: %v\n", message.ID, result.Error)
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var previous model.Message
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "text", "updated_at", "edit_count").
+			Where("id = ?", message.ID).
+			Take(&previous).Error; err != nil {
+			return err
+		}
+
+		message.EditCount = previous.EditCount
+		if !isSameText(previous.Text, message.Text) {
+			revision := model.MessageRevision{
+				MessageId: message.ID,
+				Revision:  previous.EditCount + 1,
+				Text:      previous.Text,
+				CreatedAt: previous.UpdatedAt,
+				EditedAt:  time.Now(),
+			}
+
+			if err := tx.Create(&revision).Error; err != nil {
+				return err
+			}
+
+			message.EditCount++
+		}
+
+		if err := tx.Where("message_id = ?", message.ID).Delete(&model.Mention{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Save(&message).Error
+	})
+
+	if err != nil {
+		log.Printf("Could not update message with id: %v. Reason: %v\n", message.ID, err)
 		return apperrors.NewInternal()
 	}
 	return nil
 }
 
+// isSameText checks if both texts are nil or equal
+func isSameText(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 // DeleteMessage removes the message from the DB
 func (r *messageRepository) DeleteMessage(message *model.Message) error {
 	if result := r.DB.Delete(message); result.Error != nil {
@@ -177,7 +482,12 @@ func (r *messageRepository) DeleteMessage(message *model.Message) error {
 func (r *messageRepository) GetById(messageId string) (*model.Message, error) {
 	message := &model.Message{}
 
-	if result := r.DB.Where("id = ?", messageId).First(message); result.Error != nil {
+	if result := r.DB.
+		Preload("Attachments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Where("id = ?", messageId).
+		First(message); result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return message, apperrors.NewNotFound("message", messageId)
 		}
@@ -186,3 +496,631 @@ func (r *messageRepository) GetById(messageId string) (*model.Message, error) {
 
 	return message, nil
 }
+
+// GetReactionEmojis returns the distinct emojis the given message got reacted with
+func (r *messageRepository) GetReactionEmojis(messageId string) ([]string, error) {
+	var emojis []string
+
+	if err := r.DB.
+		Model(&model.Reaction{}).
+		Distinct("emoji").
+		Where("message_id = ?", messageId).
+		Pluck("emoji", &emojis).
+		Error; err != nil {
+		log.Printf("Could not get the reactions of message with id: %v. Reason: %v\n", messageId, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return emojis, nil
+}
+
+// AddReaction inserts the reaction in the DB.
+// Returns a BadRequest error if the user already reacted with the emoji
+func (r *messageRepository) AddReaction(reaction *model.Reaction) error {
+	result := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(reaction)
+
+	if result.Error != nil {
+		log.Printf("Could not add the reaction for message with id: %v. Reason: %v\n", reaction.MessageId, result.Error)
+		return apperrors.NewInternal()
+	}
+
+	if result.RowsAffected == 0 {
+		return apperrors.NewBadRequest(apperrors.AlreadyReactedError)
+	}
+
+	return nil
+}
+
+// RemoveReaction removes the user's reaction with the given emoji from the DB
+func (r *messageRepository) RemoveReaction(messageId, userId, emoji string) error {
+	result := r.DB.
+		Where("message_id = ? AND user_id = ? AND emoji = ?", messageId, userId, emoji).
+		Delete(&model.Reaction{})
+
+	if result.Error != nil {
+		log.Printf("Could not remove the reaction for message with id: %v. Reason: %v\n", messageId, result.Error)
+		return apperrors.NewInternal()
+	}
+
+	if result.RowsAffected == 0 {
+		return apperrors.NewNotFound("reaction", emoji)
+	}
+
+	return nil
+}
+
+// GetReactionUsers returns the first 100 users that reacted to the message with the given emoji.
+// If the channel belongs to a guild it also fetches their guild settings.
+func (r *messageRepository) GetReactionUsers(channel *model.Channel, messageId, emoji string) (*[]model.MemberResponse, error) {
+	var users []model.MemberResponse
+
+	guildId := ""
+	if channel.GuildID != nil {
+		guildId = *channel.GuildID
+	}
+
+	err := r.DB.
+		Raw(`
+		SELECT u.id,
+			u.username,
+			u.image,
+			u.is_online,
+			u.bot,
+			u.created_at,
+			u.updated_at,
+			m.nickname,
+			m.color
+		FROM reactions
+		JOIN users u ON u.id = reactions.user_id
+		LEFT JOIN members m ON m.user_id = u.id AND m.guild_id = @guildId
+		WHERE reactions.message_id = @messageId
+		AND reactions.emoji = @emoji
+		ORDER BY reactions.created_at
+		LIMIT 100
+`,
+			sql.Named("guildId", guildId),
+			sql.Named("messageId", messageId),
+			sql.Named("emoji", emoji)).
+		Scan(&users).Error
+
+	return &users, err
+}
+
+// PinMessage pins the given message unless its channel already has the given number of pins.
+// The channel stays locked between counting and pinning, so concurrent pins cannot exceed the limit.
+func (r *messageRepository) PinMessage(message *model.Message, pinnedAt time.Time, limit int) error {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id = ?", message.ChannelId).
+			Take(&model.Channel{}).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.
+			Model(&model.Message{}).
+			Where("channel_id = ? AND pinned_at IS NOT NULL", message.ChannelId).
+			Count(&count).Error; err != nil {
+			return err
+		}
+
+		if count >= int64(limit) {
+			return apperrors.NewBadRequest(apperrors.PinLimitError)
+		}
+
+		// Skip the hooks so that pinning does not mark the message as edited
+		return tx.
+			Model(&model.Message{}).
+			Where("id = ?", message.ID).
+			UpdateColumn("pinned_at", pinnedAt).Error
+	})
+
+	var e *apperrors.Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	if err != nil {
+		log.Printf("Could not pin message with id: %v. Reason: %v\n", message.ID, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// SetPinned sets the pinned date of the given message.
+// The update skips the hooks so that pinning does not mark the message as edited.
+func (r *messageRepository) SetPinned(messageId string, pinnedAt *time.Time) error {
+	if err := r.DB.
+		Model(&model.Message{}).
+		Where("id = ?", messageId).
+		UpdateColumn("pinned_at", pinnedAt).
+		Error; err != nil {
+		log.Printf("Could not update the pin of message with id: %v. Reason: %v\n", messageId, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// mentionQuery represents the fetched fields for getMentions
+type mentionQuery struct {
+	MessageId string
+	Type      string
+	TargetId  string
+	Username  *string
+	Nickname  *string
+	RoleName  *string
+	RoleColor *string
+}
+
+// getMentions returns the resolved mentions of the given messages grouped by message ID.
+// Every message ID is contained in the result, even if the message has no mentions.
+func (r *messageRepository) getMentions(channel *model.Channel, ids []string) (map[string]model.MessageMentions, error) {
+	mentions := make(map[string]model.MessageMentions)
+
+	if len(ids) == 0 {
+		return mentions, nil
+	}
+
+	for _, id := range ids {
+		mentions[id] = model.NewMessageMentions()
+	}
+
+	guildId := ""
+	if channel.GuildID != nil {
+		guildId = *channel.GuildID
+	}
+
+	var result []mentionQuery
+
+	err := r.DB.
+		Raw(`
+		SELECT mentions.message_id,
+			mentions.type,
+			mentions.target_id,
+			u.username,
+			m.nickname,
+			r.name  as "role_name",
+			r.color as "role_color"
+		FROM mentions
+		LEFT JOIN users u
+		ON mentions.type = @userType AND u.id = mentions.target_id
+		LEFT JOIN members m
+		ON m.user_id = u.id AND m.guild_id = @guildId
+		LEFT JOIN roles r
+		ON mentions.type = @roleType AND r.id = mentions.target_id AND r.guild_id = @guildId
+		WHERE mentions.message_id IN @ids
+		ORDER BY mentions.created_at
+`,
+			sql.Named("userType", model.MentionTypeUser),
+			sql.Named("roleType", model.MentionTypeRole),
+			sql.Named("guildId", guildId),
+			sql.Named("ids", ids)).
+		Scan(&result).Error
+
+	if err != nil {
+		log.Printf("Could not get the mentions of the messages. Reason: %v\n", err)
+		return nil, apperrors.NewInternal()
+	}
+
+	for _, q := range result {
+		mention := mentions[q.MessageId]
+
+		switch q.Type {
+		case model.MentionTypeUser:
+			if q.Username != nil {
+				mention.Users = append(mention.Users, model.MentionedUser{
+					Id:       q.TargetId,
+					Username: *q.Username,
+					Nickname: q.Nickname,
+				})
+			}
+		case model.MentionTypeRole:
+			if q.RoleName != nil {
+				mention.Roles = append(mention.Roles, model.MentionedRole{
+					Id:    q.TargetId,
+					Name:  *q.RoleName,
+					Color: q.RoleColor,
+				})
+			}
+		case model.MentionTypeEveryone:
+			// @everyone only exists in guilds
+			mention.Everyone = guildId != ""
+		}
+
+		mentions[q.MessageId] = mention
+	}
+
+	return mentions, nil
+}
+
+// GetMessageMentions returns the resolved mentions of the given message
+func (r *messageRepository) GetMessageMentions(channel *model.Channel, messageId string) (*model.MessageMentions, error) {
+	mentions, err := r.getMentions(channel, []string{messageId})
+
+	if err != nil {
+		return nil, err
+	}
+
+	mention := mentions[messageId]
+	return &mention, nil
+}
+
+// GetMentionedMembers returns the IDs of the guild members the message mentions
+// either directly, through one of their roles or using @everyone
+func (r *messageRepository) GetMentionedMembers(guildId string, message *model.Message) ([]string, error) {
+	users := make([]string, 0)
+	roles := make([]string, 0)
+	everyone := false
+
+	for _, mention := range message.Mentions {
+		switch mention.Type {
+		case model.MentionTypeUser:
+			users = append(users, mention.TargetId)
+		case model.MentionTypeRole:
+			roles = append(roles, mention.TargetId)
+		case model.MentionTypeEveryone:
+			everyone = true
+		}
+	}
+
+	var ids []string
+
+	if len(users) == 0 && len(roles) == 0 && !everyone {
+		return ids, nil
+	}
+
+	err := r.DB.
+		Raw(`
+		SELECT DISTINCT members.user_id
+		FROM members
+		LEFT JOIN member_roles mr
+		ON mr.user_id = members.user_id AND mr.guild_id = members.guild_id
+		WHERE members.guild_id = @guildId
+		AND (@everyone OR members.user_id IN @users OR mr.role_id IN @roles)
+`,
+			sql.Named("guildId", guildId),
+			sql.Named("everyone", everyone),
+			sql.Named("users", users),
+			sql.Named("roles", roles)).
+		Scan(&ids).Error
+
+	if err != nil {
+		log.Printf("Could not get the mentioned members of message with id: %v. Reason: %v\n", message.ID, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return ids, nil
+}
+
+// messageRef represents a message and the channel it got sent in
+type messageRef struct {
+	MessageId string
+	ChannelId string
+	GuildId   *string
+	IsDM      bool
+}
+
+// findMessagesByRefs returns the given messages grouped by their ID.
+// The messages are fetched per channel so that the member settings of the guild are used.
+func (r *messageRepository) findMessagesByRefs(userId string, refs []messageRef) (map[string]model.MessageResponse, error) {
+	channelMessages := make(map[string][]string)
+	channels := make(map[string]*model.Channel)
+	for _, ref := range refs {
+		if _, ok := channels[ref.ChannelId]; !ok {
+			channels[ref.ChannelId] = &model.Channel{
+				BaseModel: model.BaseModel{ID: ref.ChannelId},
+				GuildID:   ref.GuildId,
+				IsDM:      ref.IsDM,
+			}
+		}
+		channelMessages[ref.ChannelId] = append(channelMessages[ref.ChannelId], ref.MessageId)
+	}
+
+	messages := make(map[string]model.MessageResponse)
+	for channelId, ids := range channelMessages {
+		result, err := r.findMessages(userId, channels[channelId], "AND messages.id IN @messageIds", "", sql.Named("messageIds", ids))
+
+		if err != nil {
+			log.Printf("Could not get the messages in channel with id: %v. Reason: %v\n", channelId, err)
+			return nil, apperrors.NewInternal()
+		}
+
+		for _, message := range *result {
+			messages[message.Id] = message
+		}
+	}
+
+	return messages, nil
+}
+
+// GetUserMentions returns the 50 most recent messages of other users that mention the given user
+// directly, through one of their roles or using @everyone in one of their guilds.
+func (r *messageRepository) GetUserMentions(userId string) (*[]model.MentionNotification, error) {
+	var refs []messageRef
+
+	err := r.DB.
+		Raw(`
+		SELECT messages.id as "message_id",
+			c.id            as "channel_id",
+			c.guild_id,
+			c.is_dm
+		FROM mentions
+		JOIN messages
+		ON messages.id = mentions.message_id
+		JOIN channels c
+		ON c.id = messages.channel_id
+		WHERE messages.user_id <> @userId
+		AND (
+		  (mentions.type = @userType AND mentions.target_id = @userId)
+		  OR (mentions.type = @roleType AND mentions.target_id IN (
+		    SELECT role_id
+		    FROM member_roles
+		    WHERE user_id = @userId AND guild_id = c.guild_id))
+		  OR (mentions.type = @everyoneType AND c.guild_id IN (
+		    SELECT guild_id
+		    FROM members
+		    WHERE user_id = @userId))
+		)
+		GROUP BY messages.id, c.id
+		ORDER BY messages.created_at DESC
+		LIMIT 50
+`,
+			sql.Named("userId", userId),
+			sql.Named("userType", model.MentionTypeUser),
+			sql.Named("roleType", model.MentionTypeRole),
+			sql.Named("everyoneType", model.MentionTypeEveryone)).
+		Scan(&refs).Error
+
+	if err != nil {
+		log.Printf("Could not get the mentions of user with id: %v. Reason: %v\n", userId, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	messages, err := r.findMessagesByRefs(userId, refs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := make([]model.MentionNotification, 0)
+	for _, ref := range refs {
+		if message, ok := messages[ref.MessageId]; ok {
+			message := message
+			notifications = append(notifications, model.MentionNotification{
+				GuildId:   ref.GuildId,
+				ChannelId: ref.ChannelId,
+				Message:   &message,
+			})
+		}
+	}
+
+	return &notifications, nil
+}
+
+// searchQuery represents the fetched fields for SearchMessages
+type searchQuery struct {
+	MessageId string
+	ChannelId string
+	GuildId   *string
+	IsDM      bool
+	Total     int
+}
+
+// SearchMessages returns one page of the messages in the given channels matching the search.
+// The text is matched against the search column using websearch_to_tsquery,
+// which supports quoted phrases, OR and excluding words using -.
+func (r *messageRepository) SearchMessages(userId string, channelIds []string, search *model.MessageSearch) (*model.MessageSearchResponse, error) {
+	response := model.MessageSearchResponse{
+		Messages: make([]model.MessageSearchResult, 0),
+	}
+
+	if len(channelIds) == 0 {
+		return &response, nil
+	}
+
+	query := r.DB.
+		Table("messages").
+		Select(`messages.id as "message_id",
+			c.id as "channel_id",
+			c.guild_id,
+			c.is_dm,
+			COUNT(*) OVER() as "total"`).
+		Joins("JOIN channels c ON c.id = messages.channel_id").
+		Where("messages.channel_id IN ?", channelIds)
+
+	if search.Text != "" {
+		query = query.Where("messages.search @@ websearch_to_tsquery(?, ?)", model.SearchConfig, search.Text)
+	}
+
+	if search.AuthorId != nil {
+		query = query.Where("messages.user_id = ?", *search.AuthorId)
+	}
+
+	if search.ChannelId != nil {
+		query = query.Where("messages.channel_id = ?", *search.ChannelId)
+	}
+
+	if search.HasAttachment {
+		query = query.Where("EXISTS(SELECT 1 FROM attachments a WHERE a.message_id = messages.id)")
+	}
+
+	if search.Before != nil {
+		query = query.Where("messages.created_at < ?", *search.Before)
+	}
+
+	if search.After != nil {
+		query = query.Where("messages.created_at >= ?", *search.After)
+	}
+
+	var result []searchQuery
+
+	if err := query.
+		Order("messages.created_at DESC, messages.id DESC").
+		Limit(model.SearchPageSize).
+		Offset(search.Offset).
+		Scan(&result).
+		Error; err != nil {
+		log.Printf("Could not search the messages for user: %v. Reason: %v\n", userId, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	if len(result) == 0 {
+		return &response, nil
+	}
+
+	refs := make([]messageRef, len(result))
+	for i, q := range result {
+		refs[i] = messageRef{
+			MessageId: q.MessageId,
+			ChannelId: q.ChannelId,
+			GuildId:   q.GuildId,
+			IsDM:      q.IsDM,
+		}
+	}
+
+	messages, err := r.findMessagesByRefs(userId, refs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	response.Total = result[0].Total
+	for _, ref := range refs {
+		if message, ok := messages[ref.MessageId]; ok {
+			response.Messages = append(response.Messages, model.MessageSearchResult{
+				ChannelId: ref.ChannelId,
+				Message:   message,
+			})
+		}
+	}
+
+	return &response, nil
+}
+
+// GetMessageRevisions returns the previous texts of the given message, oldest first
+func (r *messageRepository) GetMessageRevisions(messageId string) (*[]model.MessageRevision, error) {
+	var revisions []model.MessageRevision
+
+	if err := r.DB.
+		Where("message_id = ?", messageId).
+		Order("revision ASC").
+		Find(&revisions).Error; err != nil {
+		log.Printf("Could not get the revisions of message with id: %v. Reason: %v\n", messageId, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return &revisions, nil
+}
+
+// CreateUpload inserts the upload slot in the DB
+func (r *messageRepository) CreateUpload(upload *model.Upload) error {
+	if err := r.DB.Create(upload).Error; err != nil {
+		log.Printf("Could not create an upload for user: %v. Reason: %v\n", upload.UserId, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// GetUpload fetches the upload with the given id if it has not expired yet
+func (r *messageRepository) GetUpload(uploadId string) (*model.Upload, error) {
+	var upload model.Upload
+
+	if err := r.DB.
+		Preload("Chunks", func(db *gorm.DB) *gorm.DB {
+			return db.Order("chunk_offset ASC")
+		}).
+		Where("id = ? AND expires_at > ?", uploadId, time.Now()).
+		Take(&upload).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFound("upload", uploadId)
+		}
+
+		log.Printf("Could not get the upload with id: %v. Reason: %v\n", uploadId, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return &upload, nil
+}
+
+// GetExpiredUploads returns the uploads that did not get attached to a message in time
+func (r *messageRepository) GetExpiredUploads() (*[]model.Upload, error) {
+	var uploads []model.Upload
+
+	if err := r.DB.
+		Preload("Chunks").
+		Where("expires_at <= ?", time.Now()).
+		Find(&uploads).Error; err != nil {
+		log.Printf("Could not get the expired uploads. Reason: %v\n", err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return &uploads, nil
+}
+
+// DeleteUploads removes the uploads with the given ids from the DB
+func (r *messageRepository) DeleteUploads(uploadIds []string) error {
+	if len(uploadIds) == 0 {
+		return nil
+	}
+
+	if err := r.DB.Where("id IN ?", uploadIds).Delete(&model.Upload{}).Error; err != nil {
+		log.Printf("Could not delete the uploads. Reason: %v\n", err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// AddUploadChunk stores the chunk and updates the offset and expiration of the upload.
+// The chunk gets rejected if another chunk was added at its offset in the meantime.
+func (r *messageRepository) AddUploadChunk(upload *model.Upload, chunk *model.UploadChunk) error {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&model.Upload{}).
+			Where("id = ? AND upload_offset = ?", upload.ID, chunk.Offset).
+			Updates(map[string]interface{}{
+				"upload_offset": upload.Offset,
+				"expires_at":    upload.ExpiresAt,
+			})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return apperrors.NewConflict("offset", fmt.Sprint(chunk.Offset))
+		}
+
+		return tx.Create(chunk).Error
+	})
+
+	var e *apperrors.Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	if err != nil {
+		log.Printf("Could not add a chunk to the upload with id: %v. Reason: %v\n", upload.ID, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// SetUploadUrl sets the url of the file of a completed resumable upload or a finalized upload
+func (r *messageRepository) SetUploadUrl(uploadId, url string) error {
+	if err := r.DB.
+		Model(&model.Upload{}).
+		Where("id = ?", uploadId).
+		Update("url", url).Error; err != nil {
+		log.Printf("Could not set the url of the upload with id: %v. Reason: %v\n", uploadId, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
@@ -27,6 +27,10 @@ func NewRedisRepository(rds *redis.Client) model.RedisRepository {
 const (
 	InviteLinkPrefix     = "inviteLink"
 	ForgotPasswordPrefix = "forgot-password"
+	VerifyEmailPrefix    = "verify-email"
+	PendingLoginPrefix   = "pending-login"
+	SessionsPrefix       = "sessions"
+	InteractionPrefix    = "interaction"
 )
 
 // SetResetToken inserts a password reset token in the DB and returns the generated token
@@ -122,3 +126,262 @@ func (r *redisRepository) InvalidateInvites(ctx context.Context, guild *model.Gu
 		r.rds.Del(ctx, key)
 	}
 }
+
+// SetVerificationToken stores the user and the email to verify in redis for 24 hours
+// and returns the token that verifies them
+func (r *redisRepository) SetVerificationToken(ctx context.Context, id string, email string) (string, error) {
+	token, err := gonanoid.New()
+
+	if err != nil {
+		log.Printf("Failed to generate id: %v\n", err.Error())
+		return "", apperrors.NewInternal()
+	}
+
+	value, err := json.Marshal(model.EmailVerification{UserId: id, Email: email})
+
+	if err != nil {
+		log.Printf("Failed to serialize verification: %v\n", err.Error())
+		return "", apperrors.NewInternal()
+	}
+
+	if err = r.rds.Set(ctx, fmt.Sprintf("%s:%s", VerifyEmailPrefix, token), value, 24*time.Hour).Err(); err != nil {
+		log.Printf("Failed to set verification token in redis: %v\n", err.Error())
+		return "", apperrors.NewInternal()
+	}
+
+	return token, nil
+}
+
+// GetVerificationToken returns the user and the email the token got created for.
+// A token can only be used once.
+func (r *redisRepository) GetVerificationToken(ctx context.Context, token string) (*model.EmailVerification, error) {
+	// Read and remove the token in one step, so concurrent requests cannot both use it
+	val, err := r.rds.GetDel(ctx, fmt.Sprintf("%s:%s", VerifyEmailPrefix, token)).Result()
+
+	if err == redis.Nil {
+		return nil, apperrors.NewBadRequest(apperrors.InvalidVerifyToken)
+	}
+
+	if err != nil {
+		log.Printf("Failed to get value from redis: %v\n", err)
+		return nil, apperrors.NewInternal()
+	}
+
+	var verification model.EmailVerification
+	if err = json.Unmarshal([]byte(val), &verification); err != nil {
+		log.Printf("Failed to deserialize verification: %v\n", err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return &verification, nil
+}
+
+// SetPendingLogin stores the ID of a user that still needs to enter their
+// two-factor code for 5 minutes and returns the ticket for the second login step
+func (r *redisRepository) SetPendingLogin(ctx context.Context, id string) (string, error) {
+	ticket, err := gonanoid.New()
+
+	if err != nil {
+		log.Printf("Failed to generate id: %v\n", err.Error())
+		return "", apperrors.NewInternal()
+	}
+
+	if err = r.rds.Set(ctx, fmt.Sprintf("%s:%s", PendingLoginPrefix, ticket), id, 5*time.Minute).Err(); err != nil {
+		log.Printf("Failed to set pending login in redis: %v\n", err.Error())
+		return "", apperrors.NewInternal()
+	}
+
+	return ticket, nil
+}
+
+// GetPendingLogin returns the user ID of the pending login.
+// A ticket can only be used once, so every wrong code requires the password again.
+func (r *redisRepository) GetPendingLogin(ctx context.Context, ticket string) (string, error) {
+	// Read and remove the ticket in one step, so concurrent requests cannot share it
+	val, err := r.rds.GetDel(ctx, fmt.Sprintf("%s:%s", PendingLoginPrefix, ticket)).Result()
+
+	if err == redis.Nil {
+		return "", apperrors.NewBadRequest(apperrors.InvalidLoginTicket)
+	}
+	if err != nil {
+		log.Printf("Failed to get value from redis: %v\n", err)
+		return "", apperrors.NewInternal()
+	}
+
+	return val, nil
+}
+
+// SaveSession stores the session in the hash of the user's sessions.
+// The hash expires together with the user's most recently used session.
+func (r *redisRepository) SaveSession(ctx context.Context, userId string, session *model.Session) error {
+	value, err := json.Marshal(session)
+
+	if err != nil {
+		log.Printf("Error marshalling: %v\n", err.Error())
+		return apperrors.NewInternal()
+	}
+
+	key := fmt.Sprintf("%s:%s", SessionsPrefix, userId)
+
+	_, err = r.rds.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, session.Id, value)
+		pipe.Expire(ctx, key, model.SessionMaxAge*time.Second)
+		return nil
+	})
+
+	if err != nil {
+		log.Printf("Failed to save session in redis: %v\n", err.Error())
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// GetSession returns the session with the given id of the user
+func (r *redisRepository) GetSession(ctx context.Context, userId string, id string) (*model.Session, error) {
+	val, err := r.rds.HGet(ctx, fmt.Sprintf("%s:%s", SessionsPrefix, userId), id).Result()
+
+	if err == redis.Nil {
+		return nil, apperrors.NewNotFound("session", id)
+	}
+	if err != nil {
+		log.Printf("Failed to get session from redis: %v\n", err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	var session model.Session
+	if err = json.Unmarshal([]byte(val), &session); err != nil {
+		log.Printf("Error unmarshalling: %v\n", err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	return &session, nil
+}
+
+// GetSessions returns all stored sessions of the user
+func (r *redisRepository) GetSessions(ctx context.Context, userId string) ([]model.Session, error) {
+	values, err := r.rds.HGetAll(ctx, fmt.Sprintf("%s:%s", SessionsPrefix, userId)).Result()
+
+	if err != nil {
+		log.Printf("Failed to get sessions from redis: %v\n", err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	sessions := make([]model.Session, 0, len(values))
+
+	for _, val := range values {
+		var session model.Session
+		if err = json.Unmarshal([]byte(val), &session); err != nil {
+			log.Printf("Error unmarshalling: %v\n", err.Error())
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// DeleteSessions removes the sessions with the given ids of the user
+func (r *redisRepository) DeleteSessions(ctx context.Context, userId string, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := r.rds.HDel(ctx, fmt.Sprintf("%s:%s", SessionsPrefix, userId), ids...).Err(); err != nil {
+		log.Printf("Failed to delete sessions from redis: %v\n", err.Error())
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// SaveInteraction stores the interaction under its token until the interaction expires
+func (r *redisRepository) SaveInteraction(ctx context.Context, interaction *model.Interaction) error {
+	value, expiration, err := marshalInteraction(interaction)
+
+	if err != nil {
+		return err
+	}
+
+	if err = r.rds.Set(ctx, fmt.Sprintf("%s:%s", InteractionPrefix, interaction.Token), value, expiration).Err(); err != nil {
+		log.Printf("Failed to set interaction in redis: %v\n", err.Error())
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// UpdateInteraction saves the changes of the interaction if its token has not been used yet,
+// so a late update cannot bring back a consumed token
+func (r *redisRepository) UpdateInteraction(ctx context.Context, interaction *model.Interaction) error {
+	value, expiration, err := marshalInteraction(interaction)
+
+	if err != nil {
+		return err
+	}
+
+	updated, err := r.rds.SetXX(ctx, fmt.Sprintf("%s:%s", InteractionPrefix, interaction.Token), value, expiration).Result()
+
+	if err != nil {
+		log.Printf("Failed to update interaction in redis: %v\n", err.Error())
+		return apperrors.NewInternal()
+	}
+
+	if !updated {
+		return apperrors.NewAuthorization(apperrors.InvalidInteractionToken)
+	}
+
+	return nil
+}
+
+// GetInteraction returns the interaction of the given token
+func (r *redisRepository) GetInteraction(ctx context.Context, token string) (*model.Interaction, error) {
+	val, err := r.rds.Get(ctx, fmt.Sprintf("%s:%s", InteractionPrefix, token)).Result()
+
+	return unmarshalInteraction(token, val, err)
+}
+
+// ConsumeInteraction returns the interaction of the given token and removes it in the same step,
+// so only one caller can use the token
+func (r *redisRepository) ConsumeInteraction(ctx context.Context, token string) (*model.Interaction, error) {
+	val, err := r.rds.GetDel(ctx, fmt.Sprintf("%s:%s", InteractionPrefix, token)).Result()
+
+	return unmarshalInteraction(token, val, err)
+}
+
+func marshalInteraction(interaction *model.Interaction) ([]byte, time.Duration, error) {
+	expiration := time.Until(interaction.ExpiresAt)
+
+	if expiration <= 0 {
+		return nil, 0, apperrors.NewAuthorization(apperrors.InvalidInteractionToken)
+	}
+
+	value, err := json.Marshal(interaction)
+
+	if err != nil {
+		log.Printf("Error marshalling: %v\n", err.Error())
+		return nil, 0, apperrors.NewInternal()
+	}
+
+	return value, expiration, nil
+}
+
+func unmarshalInteraction(token string, val string, err error) (*model.Interaction, error) {
+	if err == redis.Nil {
+		return nil, apperrors.NewAuthorization(apperrors.InvalidInteractionToken)
+	}
+	if err != nil {
+		log.Printf("Failed to get interaction from redis: %v\n", err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	var interaction model.Interaction
+	if err = json.Unmarshal([]byte(val), &interaction); err != nil {
+		log.Printf("Error unmarshalling: %v\n", err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	interaction.Token = token
+
+	return &interaction, nil
+}
@@ -104,6 +104,50 @@ func (r *userRepository) GetRequestCount(userId string) (*int64, error) {
 	return &count, err
 }
 
+// FindBots returns the bots of the given owner
+func (r *userRepository) FindBots(ownerId string) (*[]model.User, error) {
+	var bots []model.User
+
+	if err := r.DB.
+		Where("bot = true AND owner_id = ?", ownerId).
+		Order("created_at ASC").
+		Find(&bots).Error; err != nil {
+		log.Printf("Could not get the bots of user: %v. Reason: %v\n", ownerId, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return &bots, nil
+}
+
+// Delete removes the user together with their memberships, relations and messages from the DB
+func (r *userRepository) Delete(userId string) error {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		return tx.
+			Exec("DELETE FROM member_roles WHERE user_id = ?", userId).
+			Exec("DELETE FROM members WHERE user_id = ?", userId).
+			Exec("DELETE FROM bans WHERE user_id = ?", userId).
+			Exec("DELETE FROM vc_members WHERE user_id = ?", userId).
+			Exec("DELETE FROM dm_members WHERE user_id = ?", userId).
+			Exec("DELETE FROM pcmembers WHERE user_id = ?", userId).
+			Exec("DELETE FROM thread_members WHERE user_id = ?", userId).
+			Exec("DELETE FROM reactions WHERE user_id = ?", userId).
+			Exec("DELETE FROM mentions WHERE type = ? AND target_id = ?", model.MentionTypeUser, userId).
+			Exec("DELETE FROM friends WHERE user_id = ? OR friend_id = ?", userId, userId).
+			Exec("DELETE FROM friend_requests WHERE sender_id = ? OR receiver_id = ?", userId, userId).
+			Exec("DELETE FROM messages WHERE user_id = ?", userId).
+			Exec("DELETE FROM commands WHERE bot_id = ?", userId).
+			Exec("DELETE FROM users WHERE id = ?", userId).
+			Error
+	})
+
+	if err != nil {
+		log.Printf("Could not delete the user with id: %v. Reason: %v\n", userId, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
 // isDuplicateKeyError checks if the provided error is a PostgreSQL duplicate key error
 func isDuplicateKeyError(err error) bool {
 	duplicate := regexp.MustCompile(`\(SQLSTATE 23505\)$`)
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"errors"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"gorm.io/gorm"
+	"log"
+)
+
+// webhookRepository is data/repository implementation
+// of service layer WebhookRepository
+type webhookRepository struct {
+	DB *gorm.DB
+}
+
+// NewWebhookRepository is a factory for initializing Webhook Repositories
+func NewWebhookRepository(db *gorm.DB) model.WebhookRepository {
+	return &webhookRepository{
+		DB: db,
+	}
+}
+
+// Create inserts the webhook together with the user of its messages in the DB
+func (r *webhookRepository) Create(webhook *model.Webhook, user *model.User) error {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return tx.Create(webhook).Error
+	})
+
+	if err != nil {
+		log.Printf("Could not create a webhook for channel: %v. Reason: %v\n", webhook.ChannelId, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// FindByID fetches the webhook with the given id
+func (r *webhookRepository) FindByID(id string) (*model.Webhook, error) {
+	var webhook model.Webhook
+
+	if err := r.DB.Where("id = ?", id).Take(&webhook).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFound("webhook", id)
+		}
+
+		log.Printf("Could not get the webhook with id: %v. Reason: %v\n", id, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return &webhook, nil
+}
+
+// FindByChannel returns the webhooks of the given channel, the oldest first
+func (r *webhookRepository) FindByChannel(channelId string) (*[]model.Webhook, error) {
+	var webhooks []model.Webhook
+
+	if err := r.DB.
+		Where("channel_id = ?", channelId).
+		Order("created_at").
+		Find(&webhooks).Error; err != nil {
+		log.Printf("Could not get the webhooks of channel: %v. Reason: %v\n", channelId, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return &webhooks, nil
+}
+
+// CountByChannel returns the number of webhooks of the given channel
+func (r *webhookRepository) CountByChannel(channelId string) (int64, error) {
+	var count int64
+
+	if err := r.DB.
+		Model(&model.Webhook{}).
+		Where("channel_id = ?", channelId).
+		Count(&count).Error; err != nil {
+		log.Printf("Could not count the webhooks of channel: %v. Reason: %v\n", channelId, err)
+		return 0, apperrors.NewInternal()
+	}
+
+	return count, nil
+}
+
+// Update saves the webhook and copies its name and image to the user of its messages
+func (r *webhookRepository) Update(webhook *model.Webhook) error {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(webhook).Error; err != nil {
+			return err
+		}
+		return tx.
+			Exec("UPDATE users SET username = ?, image = ?, updated_at = ? WHERE id = ?",
+				webhook.Name, webhook.Image, webhook.UpdatedAt, webhook.ID).
+			Error
+	})
+
+	if err != nil {
+		log.Printf("Could not update the webhook with id: %v. Reason: %v\n", webhook.ID, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// Delete removes the webhook together with its user and messages from the DB
+func (r *webhookRepository) Delete(id string) error {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		return tx.
+			Exec("DELETE FROM webhooks WHERE id = ?", id).
+			Exec("DELETE FROM messages WHERE user_id = ?", id).
+			Exec("DELETE FROM users WHERE id = ?", id).
+			Error
+	})
+
+	if err != nil {
+		log.Printf("Could not delete the webhook with id: %v. Reason: %v\n", id, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
@@ -0,0 +1,483 @@
+package repository
+
+import (
+	"bytes"
+	"container/heap"
+	"encoding/binary"
+	"image"
+	"math/bits"
+	"sort"
+)
+
+// The alphabet sizes of the prefix codes of a lossless webp bitstream.
+// Green also contains the lengths of backward references.
+const (
+	vp8lGreenCodes    = 256 + 24
+	vp8lColorCodes    = 256
+	vp8lDistanceCodes = 40
+)
+
+const (
+	// vp8lMaxCodeLength is the longest code a prefix code may use
+	vp8lMaxCodeLength = 15
+	// vp8lMaxCodeLengthCodeLength is the longest code the code lengths may be encoded with
+	vp8lMaxCodeLengthCodeLength = 7
+	// vp8lMaxBackwardLength is the longest backward reference
+	vp8lMaxBackwardLength = 4096
+	// vp8lMinBackwardLength is the shortest run of pixels that gets copied instead of repeated
+	vp8lMinBackwardLength = 3
+)
+
+// vp8lCodeLengthCodeOrder is the order the code lengths of the code length code get written in
+var vp8lCodeLengthCodeOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// vp8lWriter writes the bits of a lossless webp bitstream starting at the least significant bit
+type vp8lWriter struct {
+	buf  []byte
+	acc  uint64
+	bits uint
+}
+
+func (w *vp8lWriter) write(value uint32, bits uint) {
+	w.acc |= uint64(value) << w.bits
+	w.bits += bits
+
+	for w.bits >= 8 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.bits -= 8
+	}
+}
+
+// bytes returns the written bits padded to a full byte
+func (w *vp8lWriter) bytes() []byte {
+	if w.bits > 0 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc, w.bits = 0, 0
+	}
+
+	return w.buf
+}
+
+// prefixCode is a canonical Huffman code with the length and code of every symbol.
+// The codes are stored reversed, as the bitstream starts with their most significant bit.
+type prefixCode struct {
+	Lengths []int
+	Codes   []uint32
+}
+
+// writeSymbol writes the code of the given symbol
+func (p *prefixCode) writeSymbol(w *vp8lWriter, symbol int) {
+	w.write(p.Codes[symbol], uint(p.Lengths[symbol]))
+}
+
+// vp8lSymbol is either a pixel or a backward reference copying the Length previous pixels
+// at the Distance code, with the green channel holding the length code
+type vp8lSymbol struct {
+	Pixel    [4]uint8
+	Length   int
+	Distance int
+}
+
+// encodeVP8L encodes the image as a lossless webp bitstream.
+// It subtracts the green channel from red and blue and repeats runs of the previous pixel
+// or the pixels above with backward references. Each channel gets a prefix code of its own.
+func encodeVP8L(img *image.NRGBA) []byte {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+
+	// Pixels in the order of the channels of the prefix codes, with the green channel subtracted
+	pixels := make([][4]uint8, 0, width*height)
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			c := img.NRGBAAt(x, y)
+			pixels = append(pixels, [4]uint8{c.G, c.R - c.G, c.B - c.G, c.A})
+		}
+	}
+
+	symbols := vp8lSymbols(pixels, width)
+
+	histograms := [5][]int{
+		make([]int, vp8lGreenCodes),
+		make([]int, vp8lColorCodes),
+		make([]int, vp8lColorCodes),
+		make([]int, vp8lColorCodes),
+		make([]int, vp8lDistanceCodes),
+	}
+
+	for _, s := range symbols {
+		if s.Length == 0 {
+			for i, v := range s.Pixel {
+				histograms[i][v]++
+			}
+			continue
+		}
+
+		code, _, _ := vp8lPrefix(s.Length)
+		histograms[0][256+code]++
+
+		code, _, _ = vp8lPrefix(s.Distance)
+		histograms[4][code]++
+	}
+
+	w := &vp8lWriter{}
+
+	// Signature, size and alpha hint followed by version 0
+	w.write(0x2F, 8)
+	w.write(uint32(width-1), 14)
+	w.write(uint32(height-1), 14)
+	if img.Opaque() {
+		w.write(0, 1)
+	} else {
+		w.write(1, 1)
+	}
+	w.write(0, 3)
+
+	// A single subtract green transform
+	w.write(1, 1)
+	w.write(2, 2)
+	w.write(0, 1)
+
+	// No color cache and no meta prefix codes
+	w.write(0, 1)
+	w.write(0, 1)
+
+	codes := make([]*prefixCode, len(histograms))
+	for i, histogram := range histograms {
+		codes[i] = writePrefixCode(w, histogram)
+	}
+
+	for _, s := range symbols {
+		if s.Length == 0 {
+			for i, v := range s.Pixel {
+				codes[i].writeSymbol(w, int(v))
+			}
+			continue
+		}
+
+		code, extraBits, extra := vp8lPrefix(s.Length)
+		codes[0].writeSymbol(w, 256+code)
+		w.write(extra, extraBits)
+
+		code, extraBits, extra = vp8lPrefix(s.Distance)
+		codes[4].writeSymbol(w, code)
+		w.write(extra, extraBits)
+	}
+
+	return w.bytes()
+}
+
+// vp8lSymbols replaces runs of pixels repeating the previous pixel or the pixels above
+// with backward references. The distance codes 1 and 2 copy the pixels above
+// and the previous pixel respectively.
+func vp8lSymbols(pixels [][4]uint8, width int) []vp8lSymbol {
+	symbols := make([]vp8lSymbol, 0)
+
+	// run returns the number of pixels starting at i that repeat the pixels at the distance
+	run := func(i, distance int) int {
+		n := 0
+		for i-distance >= 0 && i+n < len(pixels) && n < vp8lMaxBackwardLength && pixels[i+n] == pixels[i+n-distance] {
+			n++
+		}
+		return n
+	}
+
+	for i := 0; i < len(pixels); {
+		length, distance := run(i, 1), 2
+		if above := run(i, width); above > length {
+			length, distance = above, 1
+		}
+
+		if length < vp8lMinBackwardLength {
+			symbols = append(symbols, vp8lSymbol{Pixel: pixels[i]})
+			i++
+			continue
+		}
+
+		symbols = append(symbols, vp8lSymbol{Length: length, Distance: distance})
+		i += length
+	}
+
+	return symbols
+}
+
+// vp8lPrefix returns the prefix code and the extra bits of the given length or distance code
+func vp8lPrefix(value int) (int, uint, uint32) {
+	v := uint32(value - 1)
+
+	if v < 4 {
+		return int(v), 0, 0
+	}
+
+	highest := bits.Len32(v) - 1
+	second := int(v>>(highest-1)) & 1
+	extraBits := uint(highest - 1)
+
+	return 2*highest + second, extraBits, v & (1<<extraBits - 1)
+}
+
+// writePrefixCode writes the prefix code for the given symbol counts and returns it.
+// Codes with up to two symbols that fit into 8 bits get written as simple codes,
+// all others as canonical Huffman codes with their code lengths.
+func writePrefixCode(w *vp8lWriter, histogram []int) *prefixCode {
+	used := make([]int, 0, 2)
+	for symbol, count := range histogram {
+		if count > 0 {
+			used = append(used, symbol)
+		}
+	}
+
+	if len(used) == 0 {
+		used = append(used, 0)
+	}
+
+	code := &prefixCode{Lengths: make([]int, len(histogram)), Codes: make([]uint32, len(histogram))}
+
+	if len(used) <= 2 && used[len(used)-1] < 256 {
+		w.write(1, 1)
+		w.write(uint32(len(used)-1), 1)
+
+		if used[0] < 2 {
+			w.write(0, 1)
+			w.write(uint32(used[0]), 1)
+		} else {
+			w.write(1, 1)
+			w.write(uint32(used[0]), 8)
+		}
+
+		// A single symbol does not take up any bits
+		if len(used) == 2 {
+			w.write(uint32(used[1]), 8)
+			code.Lengths[used[0]], code.Lengths[used[1]] = 1, 1
+			code.Codes[used[1]] = 1
+		}
+
+		return code
+	}
+
+	code.Lengths = huffmanLengths(histogram, vp8lMaxCodeLength)
+	code.Codes = canonicalCodes(code.Lengths)
+
+	// The code lengths are written with a code of their own, using 17 and 18 for runs of zeros
+	type codeLength struct {
+		Symbol    int
+		Extra     uint32
+		ExtraBits uint
+	}
+
+	lengths := make([]codeLength, 0, len(histogram))
+	counts := make([]int, len(vp8lCodeLengthCodeOrder))
+
+	for i := 0; i < len(code.Lengths); {
+		zeros := 0
+		for i+zeros < len(code.Lengths) && code.Lengths[i+zeros] == 0 && zeros < 138 {
+			zeros++
+		}
+
+		switch {
+		case zeros >= 11:
+			lengths = append(lengths, codeLength{Symbol: 18, Extra: uint32(zeros - 11), ExtraBits: 7})
+			i += zeros
+		case zeros >= 3:
+			lengths = append(lengths, codeLength{Symbol: 17, Extra: uint32(zeros - 3), ExtraBits: 3})
+			i += zeros
+		default:
+			lengths = append(lengths, codeLength{Symbol: code.Lengths[i]})
+			i++
+		}
+
+		counts[lengths[len(lengths)-1].Symbol]++
+	}
+
+	lengthCode := &prefixCode{Lengths: huffmanLengths(counts, vp8lMaxCodeLengthCodeLength)}
+	lengthCode.Codes = canonicalCodes(lengthCode.Lengths)
+
+	written := len(vp8lCodeLengthCodeOrder)
+	for written > 4 && lengthCode.Lengths[vp8lCodeLengthCodeOrder[written-1]] == 0 {
+		written--
+	}
+
+	w.write(0, 1)
+	w.write(uint32(written-4), 4)
+
+	for _, symbol := range vp8lCodeLengthCodeOrder[:written] {
+		w.write(uint32(lengthCode.Lengths[symbol]), 3)
+	}
+
+	// All code lengths get written instead of stopping at the last used symbol
+	w.write(0, 1)
+
+	for _, l := range lengths {
+		lengthCode.writeSymbol(w, l.Symbol)
+		w.write(l.Extra, l.ExtraBits)
+	}
+
+	return code
+}
+
+// usedSymbols returns the symbols with a code
+func usedSymbols(lengths []int) []int {
+	used := make([]int, 0)
+	for symbol, length := range lengths {
+		if length > 0 {
+			used = append(used, symbol)
+		}
+	}
+	return used
+}
+
+// huffmanNode is a symbol or a subtree of a Huffman tree
+type huffmanNode struct {
+	Count    int
+	Symbol   int
+	Children [2]*huffmanNode
+}
+
+// huffmanQueue is a priority queue with the least frequent nodes first
+type huffmanQueue []*huffmanNode
+
+func (q huffmanQueue) Len() int            { return len(q) }
+func (q huffmanQueue) Less(i, j int) bool  { return q[i].Count < q[j].Count }
+func (q huffmanQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *huffmanQueue) Push(x interface{}) { *q = append(*q, x.(*huffmanNode)) }
+func (q *huffmanQueue) Pop() interface{} {
+	old := *q
+	node := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return node
+}
+
+// huffmanLengths returns the code lengths of a Huffman code for the given symbol counts
+// whose codes are at most maxLength bits long. Rare symbols get counted more often
+// until the code fits, which flattens the tree. Codes always have at least two symbols.
+func huffmanLengths(histogram []int, maxLength int) []int {
+	counts := make([]int, len(histogram))
+	copy(counts, histogram)
+
+	if used := usedSymbols(counts); len(used) < 2 {
+		for symbol := 0; len(used) < 2; symbol++ {
+			if counts[symbol] == 0 {
+				counts[symbol] = 1
+				used = append(used, symbol)
+			}
+		}
+	}
+
+	for minCount := 1; ; minCount *= 2 {
+		q := make(huffmanQueue, 0, len(counts))
+
+		for symbol, count := range counts {
+			if count > 0 {
+				if count < minCount {
+					count = minCount
+				}
+				q = append(q, &huffmanNode{Count: count, Symbol: symbol})
+			}
+		}
+
+		// Equal counts keep the order of their symbols, so the code does not depend on the heap
+		sort.SliceStable(q, func(i, j int) bool { return q[i].Count < q[j].Count })
+		heap.Init(&q)
+
+		for q.Len() > 1 {
+			a, b := heap.Pop(&q).(*huffmanNode), heap.Pop(&q).(*huffmanNode)
+			heap.Push(&q, &huffmanNode{Count: a.Count + b.Count, Children: [2]*huffmanNode{a, b}})
+		}
+
+		lengths := make([]int, len(counts))
+		longest := 0
+
+		var walk func(node *huffmanNode, depth int)
+		walk = func(node *huffmanNode, depth int) {
+			if node.Children[0] == nil {
+				lengths[node.Symbol] = depth
+				if depth > longest {
+					longest = depth
+				}
+				return
+			}
+
+			walk(node.Children[0], depth+1)
+			walk(node.Children[1], depth+1)
+		}
+		walk(heap.Pop(&q).(*huffmanNode), 0)
+
+		if longest <= maxLength {
+			return lengths
+		}
+	}
+}
+
+// canonicalCodes assigns the canonical codes of the given code lengths.
+// Shorter codes come first and codes of the same length are ordered by their symbols.
+func canonicalCodes(lengths []int) []uint32 {
+	counts := make([]uint32, vp8lMaxCodeLength+1)
+	for _, length := range lengths {
+		if length > 0 {
+			counts[length]++
+		}
+	}
+
+	next := make([]uint32, vp8lMaxCodeLength+1)
+	code := uint32(0)
+	for length := 1; length <= vp8lMaxCodeLength; length++ {
+		code = (code + counts[length-1]) << 1
+		next[length] = code
+	}
+
+	codes := make([]uint32, len(lengths))
+	for symbol, length := range lengths {
+		if length > 0 {
+			codes[symbol] = bits.Reverse32(next[length]) >> (32 - length)
+			next[length]++
+		}
+	}
+
+	return codes
+}
+
+// webpAnimation collects the frames of an animated webp image.
+// LoopCount is the number of times it plays with 0 for endless animations.
+type webpAnimation struct {
+	Width     int
+	Height    int
+	LoopCount int
+	Frames    *bytes.Buffer
+}
+
+// addFrame adds the image as a lossless frame that covers the whole canvas
+// and replaces the previous frame instead of getting blended with it
+func (a *webpAnimation) addFrame(img *image.NRGBA, frame animationFrame) {
+	a.Width, a.Height = img.Rect.Dx(), img.Rect.Dy()
+
+	header := make([]byte, 16)
+	putUint24(header[6:], a.Width-1)
+	putUint24(header[9:], a.Height-1)
+	putUint24(header[12:], frame.Delay)
+	header[15] = 0x02 // No blending
+
+	data := bytes.NewBuffer(header)
+	writeRiffChunk(data, "VP8L", encodeVP8L(img))
+
+	writeRiffChunk(a.Frames, "ANMF", data.Bytes())
+}
+
+// encode writes the animated webp image with a transparent background color
+func (a *webpAnimation) encode() (*bytes.Buffer, error) {
+	header := make([]byte, 10)
+	header[0] = 0x12 // Animation and alpha
+	putUint24(header[4:], a.Width-1)
+	putUint24(header[7:], a.Height-1)
+
+	options := make([]byte, 6)
+	binary.LittleEndian.PutUint16(options[4:], uint16(a.LoopCount))
+
+	body := bytes.NewBufferString("WEBP")
+	writeRiffChunk(body, "VP8X", header)
+	writeRiffChunk(body, "ANIM", options)
+	_, _ = a.Frames.WriteTo(body)
+
+	buf := new(bytes.Buffer)
+	writeRiffChunk(buf, "RIFF", body.Bytes())
+
+	return buf, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/vp8l"
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+)
+
+func TestEncodeVP8L(t *testing.T) {
+	t.Run("Lossless", func(t *testing.T) {
+		img := image.NewNRGBA(image.Rect(0, 0, 67, 41))
+		r := rand.New(rand.NewSource(1))
+
+		for y := 0; y < img.Rect.Dy(); y++ {
+			for x := 0; x < img.Rect.Dx(); x++ {
+				c := color.NRGBA{R: 200, G: 30, B: 90, A: 255}
+
+				switch {
+				case y < 10:
+					// Noise with every value of every channel
+					c = color.NRGBA{R: uint8(r.Intn(256)), G: uint8(r.Intn(256)), B: uint8(r.Intn(256)), A: uint8(r.Intn(256))}
+				case y < 20:
+					// Stripes repeating the row above
+					c = color.NRGBA{R: uint8(x * 3), G: uint8(x), B: 255, A: 128}
+				case x > 60:
+					c = color.NRGBA{}
+				}
+
+				img.SetNRGBA(x, y, c)
+			}
+		}
+
+		decoded, err := vp8l.Decode(bytes.NewReader(encodeVP8L(img)))
+		assert.NoError(t, err)
+		assert.Equal(t, img.Rect, decoded.Bounds())
+
+		for y := 0; y < img.Rect.Dy(); y++ {
+			for x := 0; x < img.Rect.Dx(); x++ {
+				assert.Equal(t, img.NRGBAAt(x, y), color.NRGBAModel.Convert(decoded.At(x, y)))
+			}
+		}
+	})
+
+	t.Run("Single color", func(t *testing.T) {
+		img := image.NewNRGBA(image.Rect(0, 0, 512, 512))
+		for i := range img.Pix {
+			img.Pix[i] = 255
+		}
+
+		content := encodeVP8L(img)
+		assert.Less(t, len(content), 1024)
+
+		decoded, err := vp8l.Decode(bytes.NewReader(content))
+		assert.NoError(t, err)
+		assert.Equal(t, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, color.NRGBAModel.Convert(decoded.At(511, 511)))
+	})
+}
+
+func TestHuffmanLengths(t *testing.T) {
+	// Fibonacci counts need a code as long as the number of symbols
+	histogram := make([]int, 30)
+	histogram[0], histogram[1] = 1, 1
+	for i := 2; i < len(histogram); i++ {
+		histogram[i] = histogram[i-1] + histogram[i-2]
+	}
+
+	lengths := huffmanLengths(histogram, vp8lMaxCodeLength)
+
+	// The code is complete and no code is longer than allowed
+	kraft := 0
+	for _, length := range lengths {
+		assert.True(t, length > 0 && length <= vp8lMaxCodeLength)
+		kraft += 1 << (vp8lMaxCodeLength - length)
+	}
+	assert.Equal(t, 1<<vp8lMaxCodeLength, kraft)
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"log"
+	"strings"
+	"time"
+)
+
+// accessTokenService acts as a struct for injecting an implementation of AccessTokenRepository
+// for use in service methods
+type accessTokenService struct {
+	AccessTokenRepository model.AccessTokenRepository
+}
+
+// ATSConfig will hold repositories that will eventually be injected into
+// this service layer
+type ATSConfig struct {
+	AccessTokenRepository model.AccessTokenRepository
+}
+
+// NewAccessTokenService is a factory function for
+// initializing an AccessTokenService with its repository layer dependencies
+func NewAccessTokenService(c *ATSConfig) model.AccessTokenService {
+	return &accessTokenService{
+		AccessTokenRepository: c.AccessTokenRepository,
+	}
+}
+
+// CreateAccessToken generates a new token for the user and stores its hash.
+// The returned token is the only time the token itself is available.
+func (s *accessTokenService) CreateAccessToken(userId string, name string, scopes []string, expiresAt *time.Time) (*model.CreatedAccessToken, error) {
+	count, err := s.AccessTokenRepository.CountByUser(userId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if count >= model.AccessTokenLimit {
+		return nil, apperrors.NewBadRequest(apperrors.AccessTokenLimitError)
+	}
+
+	token, err := generateAccessToken()
+
+	if err != nil {
+		log.Printf("Failed to generate an access token: %v\n", err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	accessToken := model.AccessToken{
+		BaseModel: model.BaseModel{
+			ID: GenerateId(),
+		},
+		UserId:    userId,
+		Name:      name,
+		TokenHash: hashAccessToken(token),
+		Scopes:    uniqueScopes(scopes),
+		ExpiresAt: expiresAt,
+	}
+
+	if err = s.AccessTokenRepository.Create(&accessToken); err != nil {
+		return nil, err
+	}
+
+	return &model.CreatedAccessToken{
+		AccessToken: accessToken,
+		Token:       token,
+	}, nil
+}
+
+func (s *accessTokenService) GetAccessTokens(userId string) (*[]model.AccessToken, error) {
+	return s.AccessTokenRepository.FindByUser(userId)
+}
+
+func (s *accessTokenService) DeleteAccessToken(userId string, id string) error {
+	return s.AccessTokenRepository.Delete(userId, id)
+}
+
+// Authenticate returns the access token for the given token and records its use.
+// Unknown and expired tokens return an Authorization error.
+func (s *accessTokenService) Authenticate(token string) (*model.AccessToken, error) {
+	if !strings.HasPrefix(token, model.AccessTokenPrefix) {
+		return nil, apperrors.NewAuthorization(apperrors.InvalidAccessToken)
+	}
+
+	accessToken, err := s.AccessTokenRepository.FindByHash(hashAccessToken(token))
+
+	if err != nil {
+		var e *apperrors.Error
+		if errors.As(err, &e) && e.Type == apperrors.NotFound {
+			return nil, apperrors.NewAuthorization(apperrors.InvalidAccessToken)
+		}
+		return nil, err
+	}
+
+	if accessToken.IsExpired() {
+		return nil, apperrors.NewAuthorization(apperrors.InvalidAccessToken)
+	}
+
+	// To avoid a write on every request the last use only gets saved once per interval
+	now := time.Now()
+	if accessToken.LastUsedAt == nil || now.Sub(*accessToken.LastUsedAt) >= model.AccessTokenTouchInterval {
+		if err = s.AccessTokenRepository.UpdateLastUsed(accessToken.ID, now); err != nil {
+			log.Printf("Failed to update the last use of access token %s: %v\n", accessToken.ID, err.Error())
+		} else {
+			accessToken.LastUsedAt = &now
+		}
+	}
+
+	return accessToken, nil
+}
+
+// generateAccessToken returns a prefixed token with 256 random bits
+func generateAccessToken() (string, error) {
+	secret := make([]byte, 32)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return model.AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// hashAccessToken returns the SHA-256 hash of the token.
+// Access tokens are random, so they do not need a slow password hash.
+func hashAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// uniqueScopes removes duplicates from the scopes while keeping their order
+func uniqueScopes(scopes []string) []string {
+	unique := make([]string, 0, len(scopes))
+
+	for _, scope := range scopes {
+		found := false
+		for _, u := range unique {
+			if u == scope {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			unique = append(unique, scope)
+		}
+	}
+
+	return unique
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAccessTokenService_CreateAccessToken(t *testing.T) {
+	userId := fixture.RandID()
+
+	t.Run("Success", func(t *testing.T) {
+		mockAccessTokenRepository := new(mocks.AccessTokenRepository)
+		ts := NewAccessTokenService(&ATSConfig{
+			AccessTokenRepository: mockAccessTokenRepository,
+		})
+
+		mockAccessTokenRepository.On("CountByUser", userId).Return(int64(0), nil)
+		mockAccessTokenRepository.On("Create", mock.AnythingOfType("*model.AccessToken")).Return(nil)
+
+		scopes := []string{model.ScopeReadMessages, model.ScopeReadMessages, model.ScopeSendMessages}
+		token, err := ts.CreateAccessToken(userId, "Backup script", scopes, nil)
+		assert.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(token.Token, model.AccessTokenPrefix))
+		assert.Equal(t, hashAccessToken(token.Token), token.TokenHash)
+		assert.NotContains(t, token.TokenHash, token.Token)
+		assert.Equal(t, []string{model.ScopeReadMessages, model.ScopeSendMessages}, []string(token.Scopes))
+		assert.Equal(t, userId, token.UserId)
+
+		mockAccessTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("Limit reached", func(t *testing.T) {
+		mockAccessTokenRepository := new(mocks.AccessTokenRepository)
+		ts := NewAccessTokenService(&ATSConfig{
+			AccessTokenRepository: mockAccessTokenRepository,
+		})
+
+		mockAccessTokenRepository.On("CountByUser", userId).Return(int64(model.AccessTokenLimit), nil)
+
+		token, err := ts.CreateAccessToken(userId, "Backup script", []string{model.ScopeReadMessages}, nil)
+		assert.Nil(t, token)
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.AccessTokenLimitError), err)
+
+		mockAccessTokenRepository.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestAccessTokenService_Authenticate(t *testing.T) {
+	userId := fixture.RandID()
+	token := model.AccessTokenPrefix + "abcdef"
+
+	t.Run("Records the use", func(t *testing.T) {
+		accessToken := &model.AccessToken{
+			BaseModel: model.BaseModel{ID: "token"},
+			UserId:    userId,
+		}
+
+		mockAccessTokenRepository := new(mocks.AccessTokenRepository)
+		ts := NewAccessTokenService(&ATSConfig{
+			AccessTokenRepository: mockAccessTokenRepository,
+		})
+
+		mockAccessTokenRepository.On("FindByHash", hashAccessToken(token)).Return(accessToken, nil)
+		mockAccessTokenRepository.On("UpdateLastUsed", "token", mock.AnythingOfType("time.Time")).Return(nil)
+
+		result, err := ts.Authenticate(token)
+		assert.NoError(t, err)
+		assert.Equal(t, userId, result.UserId)
+		assert.NotNil(t, result.LastUsedAt)
+
+		mockAccessTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("Recently used", func(t *testing.T) {
+		lastUsed := time.Now()
+		accessToken := &model.AccessToken{
+			BaseModel:  model.BaseModel{ID: "token"},
+			UserId:     userId,
+			LastUsedAt: &lastUsed,
+		}
+
+		mockAccessTokenRepository := new(mocks.AccessTokenRepository)
+		ts := NewAccessTokenService(&ATSConfig{
+			AccessTokenRepository: mockAccessTokenRepository,
+		})
+
+		mockAccessTokenRepository.On("FindByHash", hashAccessToken(token)).Return(accessToken, nil)
+
+		_, err := ts.Authenticate(token)
+		assert.NoError(t, err)
+
+		mockAccessTokenRepository.AssertNotCalled(t, "UpdateLastUsed", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Expired token", func(t *testing.T) {
+		expiresAt := time.Now().Add(-time.Hour)
+		accessToken := &model.AccessToken{
+			BaseModel: model.BaseModel{ID: "token"},
+			UserId:    userId,
+			ExpiresAt: &expiresAt,
+		}
+
+		mockAccessTokenRepository := new(mocks.AccessTokenRepository)
+		ts := NewAccessTokenService(&ATSConfig{
+			AccessTokenRepository: mockAccessTokenRepository,
+		})
+
+		mockAccessTokenRepository.On("FindByHash", hashAccessToken(token)).Return(accessToken, nil)
+
+		result, err := ts.Authenticate(token)
+		assert.Nil(t, result)
+		assert.Equal(t, apperrors.NewAuthorization(apperrors.InvalidAccessToken), err)
+
+		mockAccessTokenRepository.AssertNotCalled(t, "UpdateLastUsed", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown token", func(t *testing.T) {
+		mockAccessTokenRepository := new(mocks.AccessTokenRepository)
+		ts := NewAccessTokenService(&ATSConfig{
+			AccessTokenRepository: mockAccessTokenRepository,
+		})
+
+		mockAccessTokenRepository.On("FindByHash", hashAccessToken(token)).Return(nil, apperrors.NewNotFound("token", ""))
+
+		result, err := ts.Authenticate(token)
+		assert.Nil(t, result)
+		assert.Equal(t, apperrors.NewAuthorization(apperrors.InvalidAccessToken), err)
+	})
+
+	t.Run("Missing prefix", func(t *testing.T) {
+		mockAccessTokenRepository := new(mocks.AccessTokenRepository)
+		ts := NewAccessTokenService(&ATSConfig{
+			AccessTokenRepository: mockAccessTokenRepository,
+		})
+
+		result, err := ts.Authenticate("abcdef")
+		assert.Nil(t, result)
+		assert.Equal(t, apperrors.NewAuthorization(apperrors.InvalidAccessToken), err)
+
+		mockAccessTokenRepository.AssertNotCalled(t, "FindByHash", mock.Anything)
+	})
+}
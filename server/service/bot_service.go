@@ -0,0 +1,162 @@
+package service
+
+import (
+	"fmt"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"log"
+)
+
+// botService acts as a struct for injecting an implementation of UserRepository
+// and AccessTokenRepository for use in service methods
+type botService struct {
+	UserRepository        model.UserRepository
+	AccessTokenRepository model.AccessTokenRepository
+}
+
+// BSConfig will hold repositories that will eventually be injected into
+// this service layer
+type BSConfig struct {
+	UserRepository        model.UserRepository
+	AccessTokenRepository model.AccessTokenRepository
+}
+
+// NewBotService is a factory function for
+// initializing a BotService with its repository layer dependencies
+func NewBotService(c *BSConfig) model.BotService {
+	return &botService{
+		UserRepository:        c.UserRepository,
+		AccessTokenRepository: c.AccessTokenRepository,
+	}
+}
+
+// CreateBot creates a bot user owned by the given user and returns it with its token.
+// Bots do not have a password and get a placeholder email, so they cannot log in.
+func (s *botService) CreateBot(owner *model.User, username string) (*model.User, string, error) {
+	bots, err := s.UserRepository.FindBots(owner.ID)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(*bots) >= model.BotLimit {
+		return nil, "", apperrors.NewBadRequest(apperrors.BotLimitError)
+	}
+
+	secret, err := generateSecret()
+
+	if err != nil {
+		log.Printf("Failed to generate an interactions secret: %v\n", err.Error())
+		return nil, "", apperrors.NewInternal()
+	}
+
+	id := GenerateId()
+	email := fmt.Sprintf("%s@%s", id, model.BotEmailDomain)
+
+	bot := &model.User{
+		BaseModel: model.BaseModel{
+			ID: id,
+		},
+		Username:           username,
+		Email:              email,
+		Image:              generateAvatar(email),
+		Bot:                true,
+		OwnerId:            &owner.ID,
+		InteractionsSecret: secret,
+	}
+
+	if bot, err = s.UserRepository.Create(bot); err != nil {
+		return nil, "", err
+	}
+
+	token, err := s.ResetBotToken(bot)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return bot, token, nil
+}
+
+func (s *botService) GetBots(ownerId string) (*[]model.User, error) {
+	return s.UserRepository.FindBots(ownerId)
+}
+
+// GetBot returns the bot with the given ID if it belongs to the given owner
+func (s *botService) GetBot(ownerId string, botId string) (*model.User, error) {
+	bot, err := s.UserRepository.FindByID(botId)
+
+	if err != nil || !bot.Bot || bot.OwnerId == nil || *bot.OwnerId != ownerId {
+		return nil, apperrors.NewNotFound("bot", botId)
+	}
+
+	return bot, nil
+}
+
+// GetBotById returns the bot account with the given ID regardless of its owner
+func (s *botService) GetBotById(botId string) (*model.User, error) {
+	bot, err := s.UserRepository.FindByID(botId)
+
+	// Webhook users are bots without an owner
+	if err != nil || !bot.Bot || bot.OwnerId == nil {
+		return nil, apperrors.NewNotFound("bot", botId)
+	}
+
+	return bot, nil
+}
+
+// UpdateBot saves the changes of the bot.
+// Bots created before interactions existed get their interactions secret here.
+func (s *botService) UpdateBot(bot *model.User) error {
+	if bot.InteractionsSecret == "" {
+		secret, err := generateSecret()
+
+		if err != nil {
+			log.Printf("Failed to generate an interactions secret: %v\n", err.Error())
+			return apperrors.NewInternal()
+		}
+
+		bot.InteractionsSecret = secret
+	}
+
+	return s.UserRepository.Update(bot)
+}
+
+// ResetBotToken revokes the current token of the bot and returns a new one
+func (s *botService) ResetBotToken(bot *model.User) (string, error) {
+	if err := s.AccessTokenRepository.DeleteByUser(bot.ID); err != nil {
+		return "", err
+	}
+
+	token, err := generateAccessToken()
+
+	if err != nil {
+		log.Printf("Failed to generate a bot token: %v\n", err.Error())
+		return "", apperrors.NewInternal()
+	}
+
+	accessToken := model.AccessToken{
+		BaseModel: model.BaseModel{
+			ID: GenerateId(),
+		},
+		UserId:    bot.ID,
+		Name:      model.BotTokenName,
+		TokenHash: hashAccessToken(token),
+		Scopes:    model.AccessTokenScopes,
+	}
+
+	if err = s.AccessTokenRepository.Create(&accessToken); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// DeleteBot revokes the token of the bot and deletes it
+func (s *botService) DeleteBot(bot *model.User) error {
+	if err := s.AccessTokenRepository.DeleteByUser(bot.ID); err != nil {
+		return err
+	}
+
+	return s.UserRepository.Delete(bot.ID)
+}
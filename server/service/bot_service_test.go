@@ -0,0 +1,157 @@
+package service
+
+import (
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+)
+
+func TestBotService_CreateBot(t *testing.T) {
+	owner := fixture.GetMockUser()
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockAccessTokenRepository := new(mocks.AccessTokenRepository)
+		bs := NewBotService(&BSConfig{
+			UserRepository:        mockUserRepository,
+			AccessTokenRepository: mockAccessTokenRepository,
+		})
+
+		mockUserRepository.On("FindBots", owner.ID).Return(&[]model.User{}, nil)
+		mockUserRepository.
+			On("Create", mock.AnythingOfType("*model.User")).
+			Return(func(user *model.User) *model.User { return user }, nil)
+
+		var accessToken *model.AccessToken
+		mockAccessTokenRepository.On("DeleteByUser", mock.AnythingOfType("string")).Return(nil)
+		mockAccessTokenRepository.
+			On("Create", mock.AnythingOfType("*model.AccessToken")).
+			Run(func(args mock.Arguments) { accessToken = args.Get(0).(*model.AccessToken) }).
+			Return(nil)
+
+		bot, token, err := bs.CreateBot(owner, "Helper")
+		assert.NoError(t, err)
+
+		assert.True(t, bot.Bot)
+		assert.Equal(t, owner.ID, *bot.OwnerId)
+		assert.Equal(t, "Helper", bot.Username)
+		assert.True(t, strings.HasSuffix(bot.Email, "@"+model.BotEmailDomain))
+		assert.Empty(t, bot.Password)
+		assert.NotEmpty(t, bot.InteractionsSecret)
+
+		assert.Equal(t, bot.ID, accessToken.UserId)
+		assert.Equal(t, hashAccessToken(token), accessToken.TokenHash)
+		assert.ElementsMatch(t, model.AccessTokenScopes, accessToken.Scopes)
+		assert.Nil(t, accessToken.ExpiresAt)
+
+		mockUserRepository.AssertExpectations(t)
+		mockAccessTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("Limit reached", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		bs := NewBotService(&BSConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		bots := make([]model.User, model.BotLimit)
+		mockUserRepository.On("FindBots", owner.ID).Return(&bots, nil)
+
+		bot, _, err := bs.CreateBot(owner, "Helper")
+		assert.Nil(t, bot)
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.BotLimitError), err)
+
+		mockUserRepository.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestBotService_GetBot(t *testing.T) {
+	owner := fixture.GetMockUser()
+	bot := fixture.GetMockBot(owner.ID)
+	user := fixture.GetMockUser()
+
+	mockUserRepository := new(mocks.UserRepository)
+	bs := NewBotService(&BSConfig{
+		UserRepository: mockUserRepository,
+	})
+
+	mockUserRepository.On("FindByID", bot.ID).Return(bot, nil)
+	mockUserRepository.On("FindByID", user.ID).Return(user, nil)
+
+	t.Run("Owner", func(t *testing.T) {
+		result, err := bs.GetBot(owner.ID, bot.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, bot, result)
+	})
+
+	t.Run("Other user's bot", func(t *testing.T) {
+		result, err := bs.GetBot(user.ID, bot.ID)
+		assert.Nil(t, result)
+		assert.Equal(t, apperrors.NewNotFound("bot", bot.ID), err)
+	})
+
+	t.Run("Not a bot", func(t *testing.T) {
+		result, err := bs.GetBot(owner.ID, user.ID)
+		assert.Nil(t, result)
+		assert.Equal(t, apperrors.NewNotFound("bot", user.ID), err)
+	})
+}
+
+func TestBotService_GetBotById(t *testing.T) {
+	bot := fixture.GetMockBot(fixture.RandID())
+	user := fixture.GetMockUser()
+	webhookUser := fixture.GetMockUser()
+	webhookUser.Bot = true
+
+	mockUserRepository := new(mocks.UserRepository)
+	bs := NewBotService(&BSConfig{
+		UserRepository: mockUserRepository,
+	})
+
+	mockUserRepository.On("FindByID", bot.ID).Return(bot, nil)
+	mockUserRepository.On("FindByID", user.ID).Return(user, nil)
+	mockUserRepository.On("FindByID", webhookUser.ID).Return(webhookUser, nil)
+
+	t.Run("Any user's bot", func(t *testing.T) {
+		result, err := bs.GetBotById(bot.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, bot, result)
+	})
+
+	t.Run("Not a bot", func(t *testing.T) {
+		result, err := bs.GetBotById(user.ID)
+		assert.Nil(t, result)
+		assert.Equal(t, apperrors.NewNotFound("bot", user.ID), err)
+	})
+
+	t.Run("Webhook user", func(t *testing.T) {
+		result, err := bs.GetBotById(webhookUser.ID)
+		assert.Nil(t, result)
+		assert.Equal(t, apperrors.NewNotFound("bot", webhookUser.ID), err)
+	})
+}
+
+func TestBotService_DeleteBot(t *testing.T) {
+	bot := fixture.GetMockBot(fixture.RandID())
+
+	mockUserRepository := new(mocks.UserRepository)
+	mockAccessTokenRepository := new(mocks.AccessTokenRepository)
+	bs := NewBotService(&BSConfig{
+		UserRepository:        mockUserRepository,
+		AccessTokenRepository: mockAccessTokenRepository,
+	})
+
+	mockAccessTokenRepository.On("DeleteByUser", bot.ID).Return(nil)
+	mockUserRepository.On("Delete", bot.ID).Return(nil)
+
+	err := bs.DeleteBot(bot)
+	assert.NoError(t, err)
+
+	mockUserRepository.AssertExpectations(t)
+	mockAccessTokenRepository.AssertExpectations(t)
+}
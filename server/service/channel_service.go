@@ -3,6 +3,7 @@ package service
 import (
 	"github.com/sentrionic/valkyrie/model"
 	"github.com/sentrionic/valkyrie/model/apperrors"
+	"log"
 )
 
 // channelService acts as a struct for injecting an implementation of ChannelRepository
@@ -102,8 +103,19 @@ func (c *channelService) GetDMByUserAndChannel(userId string, channelId string)
 }
 
 // IsChannelMember checks if the user has access to the given channel.
+// Threads are accessible to everyone that has access to their parent channel.
 // Returns an error if they do not, otherwise nil
 func (c *channelService) IsChannelMember(channel *model.Channel, userId string) error {
+	if channel.IsThread() {
+		parent, err := c.ChannelRepository.GetById(*channel.ParentID)
+
+		if err != nil {
+			return apperrors.NewAuthorization(apperrors.Unauthorized)
+		}
+
+		return c.IsChannelMember(parent, userId)
+	}
+
 	// Check if user has access to the channel if it's private
 	if !channel.IsPublic {
 		// Channel is DM -> Check if one of the members
@@ -116,18 +128,176 @@ func (c *channelService) IsChannelMember(channel *model.Channel, userId string)
 			return nil
 		}
 		// Channel is private
-		for _, member := range channel.PCMembers {
-			if member.ID == userId {
-				return nil
-			}
+		if !isPCMember(channel, userId) {
+			return apperrors.NewAuthorization(apperrors.Unauthorized)
+		}
+	} else {
+		// Check if user has access to the channel
+		member, err := c.GuildRepository.GetMember(userId, *channel.GuildID)
+		if err != nil || member.ID == "" {
+			return apperrors.NewAuthorization(apperrors.Unauthorized)
 		}
-		return apperrors.NewAuthorization(apperrors.Unauthorized)
 	}
 
-	// Check if user has access to the channel
-	member, err := c.GuildRepository.GetMember(userId, *channel.GuildID)
-	if err != nil || member.ID == "" {
+	// Check if the overwrites hide the channel from the user
+	if !c.HasPermission(userId, channel, model.PermissionViewChannel) {
 		return apperrors.NewAuthorization(apperrors.Unauthorized)
 	}
 	return nil
 }
+
+// isPCMember checks if the user is a member of the given private channel
+func isPCMember(channel *model.Channel, userId string) bool {
+	for _, member := range channel.PCMembers {
+		if member.ID == userId {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission checks if the user has all the given permissions in the channel
+// after applying the channel's overwrites to their guild permissions.
+// Threads use the overwrites of their parent channel.
+// DM channels do not have permissions and only require membership.
+func (c *channelService) HasPermission(userId string, channel *model.Channel, permission model.Permission) bool {
+	if channel.GuildID == nil {
+		return true
+	}
+
+	guildId := *channel.GuildID
+	permissions, err := c.GuildRepository.GetMemberPermissions(userId, guildId)
+
+	if err != nil {
+		log.Printf("Unable to get the permissions of user %s in guild %s: %v\n", userId, guildId, err)
+		return false
+	}
+
+	// Only members have permissions
+	if permissions == 0 {
+		return false
+	}
+
+	channelId := channel.ID
+	if channel.IsThread() {
+		channelId = *channel.ParentID
+	}
+
+	overwrites, err := c.ChannelRepository.GetMemberPermissionOverwrites(userId, guildId, channelId)
+
+	if err != nil {
+		log.Printf("Unable to get the overwrites of user %s in channel %s: %v\n", userId, channelId, err)
+		return false
+	}
+
+	return model.ResolveChannelPermissions(permissions, guildId, *overwrites).Has(permission)
+}
+
+func (c *channelService) GetPermissionOverwrites(channelId string) (*[]model.PermissionOverwrite, error) {
+	return c.ChannelRepository.GetPermissionOverwrites(channelId)
+}
+
+func (c *channelService) SetPermissionOverwrite(overwrite *model.PermissionOverwrite) error {
+	return c.ChannelRepository.SavePermissionOverwrite(overwrite)
+}
+
+func (c *channelService) DeletePermissionOverwrite(channelId string, targetId string) error {
+	return c.ChannelRepository.DeletePermissionOverwrite(channelId, targetId)
+}
+
+func (c *channelService) CreateThread(thread *model.Channel) (*model.Channel, error) {
+	thread.ID = GenerateId()
+	thread.IsPublic = true
+
+	// The creator automatically joins the thread
+	if thread.OwnerID != nil {
+		thread.ThreadMembers = []model.ThreadMember{{UserID: *thread.OwnerID}}
+	}
+
+	return c.ChannelRepository.Create(thread)
+}
+
+func (c *channelService) GetThreads(channelId string, archived bool) (*[]model.ThreadResponse, error) {
+	return c.ChannelRepository.GetThreads(channelId, archived)
+}
+
+func (c *channelService) GetThreadByMessage(messageId string) (*model.Channel, error) {
+	return c.ChannelRepository.GetThreadByMessageId(messageId)
+}
+
+func (c *channelService) GetThreadMembers(threadId string) (*[]string, error) {
+	return c.ChannelRepository.GetThreadMembers(threadId)
+}
+
+func (c *channelService) AddThreadMember(threadId string, userId string) error {
+	return c.ChannelRepository.AddThreadMember(&model.ThreadMember{
+		ChannelID: threadId,
+		UserID:    userId,
+	})
+}
+
+func (c *channelService) RemoveThreadMember(threadId string, userId string) error {
+	return c.ChannelRepository.RemoveThreadMember(threadId, userId)
+}
+
+func (c *channelService) ArchiveInactiveThreads() (*[]model.Channel, error) {
+	return c.ChannelRepository.ArchiveInactiveThreads()
+}
+
+// GetAccessibleChannelIds returns the ids of the channels and threads in the guild
+// the user has access to. It resolves the member's permissions and overwrites
+// once for the whole guild instead of checking every channel separately.
+func (c *channelService) GetAccessibleChannelIds(userId string, guildId string) ([]string, error) {
+	channels, err := c.ChannelRepository.GetGuildChannels(guildId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0)
+
+	permissions, err := c.GuildRepository.GetMemberPermissions(userId, guildId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Only members have access to the channels of the guild
+	if permissions == 0 {
+		return ids, nil
+	}
+
+	overwrites, err := c.ChannelRepository.GetGuildMemberPermissionOverwrites(userId, guildId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the channels first since threads inherit the access of their parent
+	access := make(map[string]bool)
+	for i := range *channels {
+		channel := &(*channels)[i]
+		if !channel.IsThread() {
+			resolved := model.ResolveChannelPermissions(permissions, guildId, overwrites[channel.ID])
+			access[channel.ID] = (channel.IsPublic || isPCMember(channel, userId)) &&
+				resolved.Has(model.PermissionViewChannel)
+		}
+	}
+
+	for _, channel := range *channels {
+		allowed := access[channel.ID]
+		if channel.IsThread() {
+			allowed = access[*channel.ParentID]
+		}
+
+		if allowed {
+			ids = append(ids, channel.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+func (c *channelService) GetDMChannelIds(userId string) ([]string, error) {
+	return c.ChannelRepository.GetDMChannelIds(userId)
+}
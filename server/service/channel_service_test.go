@@ -180,16 +180,52 @@ func TestChannelService_IsChannelMember(t *testing.T) {
 		mockChannel := fixture.GetMockChannel(mockGuild.ID)
 
 		mockGuildRepository := new(mocks.GuildRepository)
+		mockChannelRepository := new(mocks.ChannelRepository)
 		cs := NewChannelService(&CSConfig{
-			GuildRepository: mockGuildRepository,
+			GuildRepository:   mockGuildRepository,
+			ChannelRepository: mockChannelRepository,
 		})
 
 		mockGuildRepository.On("GetMember", mockUser.ID, *mockChannel.GuildID).Return(mockUser, nil)
+		mockGuildRepository.On("GetMemberPermissions", mockUser.ID, *mockChannel.GuildID).Return(model.DefaultPermissions, nil)
+		mockChannelRepository.On("GetMemberPermissionOverwrites", mockUser.ID, *mockChannel.GuildID, mockChannel.ID).
+			Return(&[]model.PermissionOverwrite{}, nil)
 
 		err := cs.IsChannelMember(mockChannel, mockUser.ID)
 		assert.NoError(t, err)
 	})
 
+	t.Run("Channel is hidden by an overwrite", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockGuild.Members = append(mockGuild.Members, *mockUser)
+		mockChannel := fixture.GetMockChannel(mockGuild.ID)
+
+		mockGuildRepository := new(mocks.GuildRepository)
+		mockChannelRepository := new(mocks.ChannelRepository)
+		cs := NewChannelService(&CSConfig{
+			GuildRepository:   mockGuildRepository,
+			ChannelRepository: mockChannelRepository,
+		})
+
+		overwrites := []model.PermissionOverwrite{
+			{
+				ChannelID: mockChannel.ID,
+				TargetID:  mockGuild.ID,
+				Type:      model.OverwriteTypeRole,
+				Deny:      model.PermissionViewChannel,
+			},
+		}
+
+		mockGuildRepository.On("GetMember", mockUser.ID, *mockChannel.GuildID).Return(mockUser, nil)
+		mockGuildRepository.On("GetMemberPermissions", mockUser.ID, *mockChannel.GuildID).Return(model.DefaultPermissions, nil)
+		mockChannelRepository.On("GetMemberPermissionOverwrites", mockUser.ID, *mockChannel.GuildID, mockChannel.ID).
+			Return(&overwrites, nil)
+
+		err := cs.IsChannelMember(mockChannel, mockUser.ID)
+		assert.Error(t, err)
+		assert.Equal(t, err, apperrors.NewAuthorization(apperrors.Unauthorized))
+	})
+
 	t.Run("User is not a member of the guild", func(t *testing.T) {
 		mockGuild := fixture.GetMockGuild("")
 		mockChannel := fixture.GetMockChannel(mockGuild.ID)
@@ -206,4 +242,252 @@ func TestChannelService_IsChannelMember(t *testing.T) {
 		assert.Error(t, err)
 		assert.Equal(t, err, mockError)
 	})
+
+	t.Run("Thread inherits access from the parent channel", func(t *testing.T) {
+		mockGuild := fixture.GetMockGuild("")
+		mockParent := fixture.GetMockChannel(mockGuild.ID)
+		mockThread := fixture.GetMockThread(mockParent, mockUser.ID)
+
+		mockGuildRepository := new(mocks.GuildRepository)
+		mockChannelRepository := new(mocks.ChannelRepository)
+		cs := NewChannelService(&CSConfig{
+			GuildRepository:   mockGuildRepository,
+			ChannelRepository: mockChannelRepository,
+		})
+
+		overwrites := []model.PermissionOverwrite{
+			{
+				ChannelID: mockParent.ID,
+				TargetID:  mockGuild.ID,
+				Type:      model.OverwriteTypeRole,
+				Deny:      model.PermissionViewChannel,
+			},
+		}
+
+		mockChannelRepository.On("GetById", mockParent.ID).Return(mockParent, nil)
+		mockGuildRepository.On("GetMember", mockUser.ID, mockGuild.ID).Return(mockUser, nil)
+		mockGuildRepository.On("GetMemberPermissions", mockUser.ID, mockGuild.ID).Return(model.DefaultPermissions, nil)
+		mockChannelRepository.On("GetMemberPermissionOverwrites", mockUser.ID, mockGuild.ID, mockParent.ID).
+			Return(&overwrites, nil)
+
+		err := cs.IsChannelMember(mockThread, mockUser.ID)
+		assert.Error(t, err)
+		assert.Equal(t, err, apperrors.NewAuthorization(apperrors.Unauthorized))
+		mockChannelRepository.AssertExpectations(t)
+	})
+}
+
+func TestChannelService_HasPermission(t *testing.T) {
+	userId := fixture.RandID()
+	guildId := fixture.RandID()
+	roleId := fixture.RandID()
+
+	testCases := []struct {
+		name        string
+		permissions model.Permission
+		overwrites  []model.PermissionOverwrite
+		permission  model.Permission
+		expected    bool
+	}{
+		{
+			name:        "No overwrites",
+			permissions: model.DefaultPermissions,
+			permission:  model.PermissionSendMessages,
+			expected:    true,
+		},
+		{
+			name:        "Not a member",
+			permissions: 0,
+			overwrites: []model.PermissionOverwrite{
+				{TargetID: userId, Type: model.OverwriteTypeMember, Allow: model.PermissionViewChannel},
+			},
+			permission: model.PermissionViewChannel,
+			expected:   false,
+		},
+		{
+			name:        "Denied for everyone",
+			permissions: model.DefaultPermissions,
+			overwrites: []model.PermissionOverwrite{
+				{TargetID: guildId, Type: model.OverwriteTypeRole, Deny: model.PermissionSendMessages},
+			},
+			permission: model.PermissionSendMessages,
+			expected:   false,
+		},
+		{
+			name:        "Role allow overrides everyone deny",
+			permissions: model.DefaultPermissions,
+			overwrites: []model.PermissionOverwrite{
+				{TargetID: guildId, Type: model.OverwriteTypeRole, Deny: model.PermissionSendMessages},
+				{TargetID: roleId, Type: model.OverwriteTypeRole, Allow: model.PermissionSendMessages},
+			},
+			permission: model.PermissionSendMessages,
+			expected:   true,
+		},
+		{
+			name:        "Member deny overrides role allow",
+			permissions: model.DefaultPermissions,
+			overwrites: []model.PermissionOverwrite{
+				{TargetID: roleId, Type: model.OverwriteTypeRole, Allow: model.PermissionManageMessages},
+				{TargetID: userId, Type: model.OverwriteTypeMember, Deny: model.PermissionManageMessages},
+			},
+			permission: model.PermissionManageMessages,
+			expected:   false,
+		},
+		{
+			name:        "Role allows managing messages",
+			permissions: model.DefaultPermissions,
+			overwrites: []model.PermissionOverwrite{
+				{TargetID: roleId, Type: model.OverwriteTypeRole, Allow: model.PermissionManageMessages},
+			},
+			permission: model.PermissionManageMessages,
+			expected:   true,
+		},
+		{
+			name:        "Administrators ignore overwrites",
+			permissions: model.PermissionAdministrator,
+			overwrites: []model.PermissionOverwrite{
+				{TargetID: userId, Type: model.OverwriteTypeMember, Deny: model.PermissionViewChannel},
+			},
+			permission: model.PermissionViewChannel,
+			expected:   true,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			mockChannel := fixture.GetMockChannel(guildId)
+
+			mockGuildRepository := new(mocks.GuildRepository)
+			mockChannelRepository := new(mocks.ChannelRepository)
+			cs := NewChannelService(&CSConfig{
+				GuildRepository:   mockGuildRepository,
+				ChannelRepository: mockChannelRepository,
+			})
+
+			mockGuildRepository.On("GetMemberPermissions", userId, guildId).Return(tc.permissions, nil)
+			mockChannelRepository.On("GetMemberPermissionOverwrites", userId, guildId, mockChannel.ID).
+				Return(&tc.overwrites, nil)
+
+			assert.Equal(t, tc.expected, cs.HasPermission(userId, mockChannel, tc.permission))
+		})
+	}
+
+	t.Run("DM channels have no permissions", func(t *testing.T) {
+		mockChannel := fixture.GetMockDMChannel()
+
+		cs := NewChannelService(&CSConfig{})
+
+		assert.True(t, cs.HasPermission(userId, mockChannel, model.PermissionSendMessages))
+	})
+}
+
+func TestChannelService_GetAccessibleChannelIds(t *testing.T) {
+	t.Run("Threads inherit the access of their parent", func(t *testing.T) {
+		mockUser := fixture.GetMockUser()
+		mockGuild := fixture.GetMockGuild("")
+
+		public := fixture.GetMockChannel(mockGuild.ID)
+		private := fixture.GetMockChannel(mockGuild.ID)
+		private.IsPublic = false
+		publicThread := fixture.GetMockThread(public, fixture.RandID())
+		privateThread := fixture.GetMockThread(private, mockUser.ID)
+
+		channels := []model.Channel{*public, *private, *publicThread, *privateThread}
+
+		mockGuildRepository := new(mocks.GuildRepository)
+		mockChannelRepository := new(mocks.ChannelRepository)
+		cs := NewChannelService(&CSConfig{
+			GuildRepository:   mockGuildRepository,
+			ChannelRepository: mockChannelRepository,
+		})
+
+		mockChannelRepository.On("GetGuildChannels", mockGuild.ID).Return(&channels, nil)
+		mockGuildRepository.On("GetMemberPermissions", mockUser.ID, mockGuild.ID).Return(model.DefaultPermissions, nil).Once()
+		mockChannelRepository.On("GetGuildMemberPermissionOverwrites", mockUser.ID, mockGuild.ID).
+			Return(map[string][]model.PermissionOverwrite{}, nil).Once()
+
+		ids, err := cs.GetAccessibleChannelIds(mockUser.ID, mockGuild.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{public.ID, publicThread.ID}, ids)
+
+		mockChannelRepository.AssertExpectations(t)
+		mockGuildRepository.AssertExpectations(t)
+		mockChannelRepository.AssertNotCalled(t, "GetById", mock.Anything)
+		mockChannelRepository.AssertNotCalled(t, "GetMemberPermissionOverwrites", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Overwrites hide channels and their threads", func(t *testing.T) {
+		mockUser := fixture.GetMockUser()
+		mockGuild := fixture.GetMockGuild("")
+
+		visible := fixture.GetMockChannel(mockGuild.ID)
+		hidden := fixture.GetMockChannel(mockGuild.ID)
+		hiddenThread := fixture.GetMockThread(hidden, mockUser.ID)
+
+		channels := []model.Channel{*visible, *hidden, *hiddenThread}
+		overwrites := map[string][]model.PermissionOverwrite{
+			hidden.ID: {
+				{
+					ChannelID: hidden.ID,
+					TargetID:  mockGuild.ID,
+					Type:      model.OverwriteTypeRole,
+					Deny:      model.PermissionViewChannel,
+				},
+			},
+		}
+
+		mockGuildRepository := new(mocks.GuildRepository)
+		mockChannelRepository := new(mocks.ChannelRepository)
+		cs := NewChannelService(&CSConfig{
+			GuildRepository:   mockGuildRepository,
+			ChannelRepository: mockChannelRepository,
+		})
+
+		mockChannelRepository.On("GetGuildChannels", mockGuild.ID).Return(&channels, nil)
+		mockGuildRepository.On("GetMemberPermissions", mockUser.ID, mockGuild.ID).Return(model.DefaultPermissions, nil)
+		mockChannelRepository.On("GetGuildMemberPermissionOverwrites", mockUser.ID, mockGuild.ID).Return(overwrites, nil)
+
+		ids, err := cs.GetAccessibleChannelIds(mockUser.ID, mockGuild.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{visible.ID}, ids)
+	})
+
+	t.Run("Not a member", func(t *testing.T) {
+		userId := fixture.RandID()
+		mockGuild := fixture.GetMockGuild("")
+		channels := []model.Channel{*fixture.GetMockChannel(mockGuild.ID)}
+
+		mockGuildRepository := new(mocks.GuildRepository)
+		mockChannelRepository := new(mocks.ChannelRepository)
+		cs := NewChannelService(&CSConfig{
+			GuildRepository:   mockGuildRepository,
+			ChannelRepository: mockChannelRepository,
+		})
+
+		mockChannelRepository.On("GetGuildChannels", mockGuild.ID).Return(&channels, nil)
+		mockGuildRepository.On("GetMemberPermissions", userId, mockGuild.ID).Return(model.Permission(0), nil)
+
+		ids, err := cs.GetAccessibleChannelIds(userId, mockGuild.ID)
+		assert.NoError(t, err)
+		assert.Empty(t, ids)
+		mockChannelRepository.AssertNotCalled(t, "GetGuildMemberPermissionOverwrites", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		guildId := fixture.RandID()
+
+		mockChannelRepository := new(mocks.ChannelRepository)
+		cs := NewChannelService(&CSConfig{
+			ChannelRepository: mockChannelRepository,
+		})
+
+		mockError := apperrors.NewInternal()
+		mockChannelRepository.On("GetGuildChannels", guildId).Return(nil, mockError)
+
+		ids, err := cs.GetAccessibleChannelIds(fixture.RandID(), guildId)
+		assert.EqualError(t, err, mockError.Error())
+		assert.Nil(t, ids)
+	})
 }
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"github.com/disintegration/imaging"
+	"github.com/sentrionic/valkyrie/model"
+	"image"
+	"io"
+	"strings"
+
+	// Register accepted file type jpeg
+	_ "image/jpeg"
+	// Register accepted file type png
+	_ "image/png"
+	// Register accepted file type gif
+	_ "image/gif"
+	// Register accepted file type webp
+	_ "golang.org/x/image/webp"
+)
+
+// seekableFile is the content of a file that can be read from any offset,
+// like a multipart.File, an os.File or a bytes.Reader
+type seekableFile interface {
+	io.Reader
+	io.ReaderAt
+	io.Seeker
+}
+
+// readFileMetadata sets the size and content hash of the given file on the attachment.
+// Images also get their dimensions and audio files their duration if it can be determined.
+func readFileMetadata(attachment *model.Attachment, file seekableFile) error {
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+
+	if err != nil {
+		return err
+	}
+
+	attachment.Size = size
+	attachment.Hash = hex.EncodeToString(hash.Sum(nil))
+
+	switch {
+	case strings.HasPrefix(attachment.FileType, "image/"):
+		config, _, err := image.DecodeConfig(io.NewSectionReader(file, 0, size))
+
+		if err == nil {
+			attachment.Width = &config.Width
+			attachment.Height = &config.Height
+		}
+	case strings.HasPrefix(attachment.FileType, "audio/"):
+		if duration, ok := audioDuration(io.NewSectionReader(file, 0, size)); ok {
+			attachment.Duration = &duration
+		}
+	}
+
+	return nil
+}
+
+// strippedImageTypes are the image types that get re-encoded in their format to remove their metadata
+var strippedImageTypes = map[string]imaging.Format{
+	"image/jpeg": imaging.JPEG,
+	"image/png":  imaging.PNG,
+}
+
+// stripImageMetadata decodes the image with its EXIF orientation applied and encodes it again
+// in the given format. The encoders do not write any metadata, which removes e.g. the GPS coordinates of photos.
+func stripImageMetadata(file io.Reader, format imaging.Format) (*bytes.Reader, error) {
+	img, err := imaging.Decode(file, imaging.AutoOrientation(true))
+
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+
+	if err = imaging.Encode(buf, img, format, imaging.JPEGQuality(95)); err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(buf.Bytes()), nil
+}
+
+// audioDuration returns the duration in seconds of the given wav or mp3 file
+func audioDuration(file *io.SectionReader) (float64, bool) {
+	head := make([]byte, 12)
+	if _, err := file.ReadAt(head, 0); err != nil {
+		return 0, false
+	}
+
+	if bytes.Equal(head[0:4], []byte("RIFF")) && bytes.Equal(head[8:12], []byte("WAVE")) {
+		return wavDuration(file)
+	}
+
+	return mp3Duration(file)
+}
+
+// wavDuration divides the size of the data chunk by the byte rate of the fmt chunk
+func wavDuration(file *io.SectionReader) (float64, bool) {
+	var byteRate uint32
+	header := make([]byte, 8)
+
+	for offset := int64(12); offset+8 <= file.Size(); {
+		if _, err := file.ReadAt(header, offset); err != nil {
+			return 0, false
+		}
+
+		id := string(header[0:4])
+		size := int64(binary.LittleEndian.Uint32(header[4:8]))
+
+		switch id {
+		case "fmt ":
+			rate := make([]byte, 4)
+			if _, err := file.ReadAt(rate, offset+16); err != nil {
+				return 0, false
+			}
+			byteRate = binary.LittleEndian.Uint32(rate)
+		case "data":
+			if byteRate == 0 {
+				return 0, false
+			}
+			return float64(size) / float64(byteRate), true
+		}
+
+		// Chunks are padded to an even size
+		offset += 8 + size + size%2
+	}
+
+	return 0, false
+}
+
+// Bitrates in kbit/s of MPEG-1 and MPEG-2 Layer III frames by bitrate index
+var (
+	mpeg1Bitrates = [...]int{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320}
+	mpeg2Bitrates = [...]int{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160}
+)
+
+// Sample rates of MPEG-1, MPEG-2 and MPEG-2.5 frames by sample rate index
+var (
+	mpeg1SampleRates  = [...]int{44100, 48000, 32000}
+	mpeg2SampleRates  = [...]int{22050, 24000, 16000}
+	mpeg25SampleRates = [...]int{11025, 12000, 8000}
+)
+
+// mp3Duration reads the first Layer III frame after the ID3v2 tag.
+// VBR files contain the number of frames in their Xing or Info header,
+// the duration of CBR files gets calculated from their bitrate.
+func mp3Duration(file *io.SectionReader) (float64, bool) {
+	offset := int64(0)
+
+	// Skip the ID3v2 tag, its size is stored as a 28-bit syncsafe integer
+	tag := make([]byte, 10)
+	if _, err := file.ReadAt(tag, 0); err == nil && bytes.Equal(tag[0:3], []byte("ID3")) {
+		size := int64(tag[6])<<21 | int64(tag[7])<<14 | int64(tag[8])<<7 | int64(tag[9])
+		offset = 10 + size
+	}
+
+	frame := make([]byte, 4+32+8)
+	if n, err := file.ReadAt(frame, offset); n < 4 && err != nil {
+		return 0, false
+	}
+
+	// Frame sync and Layer III
+	if frame[0] != 0xFF || frame[1]&0xE0 != 0xE0 || (frame[1]>>1)&3 != 1 {
+		return 0, false
+	}
+
+	version := (frame[1] >> 3) & 3
+	bitrateIndex := frame[2] >> 4
+	sampleRateIndex := (frame[2] >> 2) & 3
+	mono := frame[3]>>6 == 3
+
+	if version == 1 || bitrateIndex == 0 || bitrateIndex == 15 || sampleRateIndex == 3 {
+		return 0, false
+	}
+
+	bitrate := mpeg2Bitrates[bitrateIndex]
+	sampleRate := mpeg2SampleRates[sampleRateIndex]
+	samplesPerFrame := 576
+	xingOffset := 4 + 17
+	if mono {
+		xingOffset = 4 + 9
+	}
+
+	switch version {
+	case 3:
+		bitrate = mpeg1Bitrates[bitrateIndex]
+		sampleRate = mpeg1SampleRates[sampleRateIndex]
+		samplesPerFrame = 1152
+		xingOffset = 4 + 32
+		if mono {
+			xingOffset = 4 + 17
+		}
+	case 0:
+		sampleRate = mpeg25SampleRates[sampleRateIndex]
+	}
+
+	xing := make([]byte, 12)
+	if _, err := file.ReadAt(xing, offset+int64(xingOffset)); err == nil {
+		id := string(xing[0:4])
+		flags := binary.BigEndian.Uint32(xing[4:8])
+
+		if (id == "Xing" || id == "Info") && flags&1 == 1 {
+			frames := binary.BigEndian.Uint32(xing[8:12])
+			return float64(frames) * float64(samplesPerFrame) / float64(sampleRate), true
+		}
+	}
+
+	return float64(file.Size()-offset) * 8 / float64(bitrate*1000), true
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"github.com/disintegration/imaging"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"testing"
+)
+
+func TestReadFileMetadata(t *testing.T) {
+	t.Run("Image dimensions", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		err := png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 3, 2)))
+		assert.NoError(t, err)
+
+		content := buf.Bytes()
+		attachment := &model.Attachment{FileType: "image/png"}
+
+		err = readFileMetadata(attachment, bytes.NewReader(content))
+		assert.NoError(t, err)
+
+		hash := sha256.Sum256(content)
+		assert.Equal(t, int64(len(content)), attachment.Size)
+		assert.Equal(t, hex.EncodeToString(hash[:]), attachment.Hash)
+		assert.Equal(t, 3, *attachment.Width)
+		assert.Equal(t, 2, *attachment.Height)
+		assert.Nil(t, attachment.Duration)
+	})
+
+	t.Run("Undecodable image", func(t *testing.T) {
+		attachment := &model.Attachment{FileType: "image/png"}
+
+		err := readFileMetadata(attachment, bytes.NewReader([]byte("not an image")))
+		assert.NoError(t, err)
+
+		assert.Equal(t, int64(12), attachment.Size)
+		assert.Nil(t, attachment.Width)
+		assert.Nil(t, attachment.Height)
+	})
+
+	t.Run("Wave duration", func(t *testing.T) {
+		// 8000 Hz, mono, 8 bit and two seconds of silence
+		content := []byte("RIFF\x00\x00\x00\x00WAVEfmt ")
+		content = binary.LittleEndian.AppendUint32(content, 16)
+		content = binary.LittleEndian.AppendUint16(content, 1)
+		content = binary.LittleEndian.AppendUint16(content, 1)
+		content = binary.LittleEndian.AppendUint32(content, 8000)
+		content = binary.LittleEndian.AppendUint32(content, 8000)
+		content = binary.LittleEndian.AppendUint16(content, 1)
+		content = binary.LittleEndian.AppendUint16(content, 8)
+		content = append(content, "data"...)
+		content = binary.LittleEndian.AppendUint32(content, 16000)
+		content = append(content, make([]byte, 16000)...)
+
+		attachment := &model.Attachment{FileType: "audio/wave"}
+
+		err := readFileMetadata(attachment, bytes.NewReader(content))
+		assert.NoError(t, err)
+
+		assert.Equal(t, 2.0, *attachment.Duration)
+		assert.Nil(t, attachment.Width)
+	})
+
+	t.Run("Constant bitrate mp3 duration", func(t *testing.T) {
+		// Empty ID3v2 tag followed by MPEG-1 Layer III frames at 128 kbit/s
+		content := []byte("ID3\x03\x00\x00\x00\x00\x00\x00")
+		frames := make([]byte, 16000)
+		copy(frames, []byte{0xFF, 0xFB, 0x90, 0x44})
+		content = append(content, frames...)
+
+		attachment := &model.Attachment{FileType: "audio/mp3"}
+
+		err := readFileMetadata(attachment, bytes.NewReader(content))
+		assert.NoError(t, err)
+
+		assert.Equal(t, 1.0, *attachment.Duration)
+	})
+
+	t.Run("Variable bitrate mp3 duration", func(t *testing.T) {
+		// Xing header of a stereo MPEG-1 frame containing the number of frames
+		content := make([]byte, 4096)
+		copy(content, []byte{0xFF, 0xFB, 0x90, 0x44})
+		copy(content[36:], "Xing")
+		binary.BigEndian.PutUint32(content[40:], 1)
+		binary.BigEndian.PutUint32(content[44:], 441)
+
+		attachment := &model.Attachment{FileType: "audio/mp3"}
+
+		err := readFileMetadata(attachment, bytes.NewReader(content))
+		assert.NoError(t, err)
+
+		assert.InDelta(t, 441*1152/44100.0, *attachment.Duration, 0.0001)
+	})
+
+	t.Run("Unknown audio format", func(t *testing.T) {
+		attachment := &model.Attachment{FileType: "audio/mp3"}
+
+		err := readFileMetadata(attachment, bytes.NewReader(make([]byte, 64)))
+		assert.NoError(t, err)
+
+		assert.Nil(t, attachment.Duration)
+	})
+}
+
+func TestStripImageMetadata(t *testing.T) {
+	t.Run("Jpeg with EXIF orientation", func(t *testing.T) {
+		content := fixture.NewExifJpeg(4, 2, 6)
+
+		stripped, err := stripImageMetadata(bytes.NewReader(content), imaging.JPEG)
+		assert.NoError(t, err)
+
+		result, err := io.ReadAll(stripped)
+		assert.NoError(t, err)
+		assert.False(t, bytes.Contains(result, []byte("Exif")))
+		assert.False(t, bytes.Contains(result, []byte(fixture.ExifGPSMarker)))
+
+		// Orientation 6 rotates the image by 90 degrees
+		config, format, err := image.DecodeConfig(bytes.NewReader(result))
+		assert.NoError(t, err)
+		assert.Equal(t, "jpeg", format)
+		assert.Equal(t, 2, config.Width)
+		assert.Equal(t, 4, config.Height)
+	})
+
+	t.Run("Png keeps its format", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		err := png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 3, 2)))
+		assert.NoError(t, err)
+
+		stripped, err := stripImageMetadata(bytes.NewReader(buf.Bytes()), imaging.PNG)
+		assert.NoError(t, err)
+
+		config, format, err := image.DecodeConfig(stripped)
+		assert.NoError(t, err)
+		assert.Equal(t, "png", format)
+		assert.Equal(t, 3, config.Width)
+		assert.Equal(t, 2, config.Height)
+	})
+
+	t.Run("Undecodable image", func(t *testing.T) {
+		_, err := stripImageMetadata(bytes.NewReader([]byte("not an image")), imaging.PNG)
+		assert.Error(t, err)
+	})
+}
+
+// newFileHeader returns the header of a multipart file with the given content
+func newFileHeader(t *testing.T, filename string, content []byte) *multipart.FileHeader {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, filename))
+	part, err := writer.CreatePart(h)
+	assert.NoError(t, err)
+
+	_, err = part.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(body, writer.Boundary()).ReadForm(1 << 20)
+	assert.NoError(t, err)
+
+	return form.File["file"][0]
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	gonanoid "github.com/matoous/go-nanoid"
 	"github.com/sentrionic/valkyrie/model"
+	"log"
 )
 
 // GuildService acts as a struct for injecting an implementation of GuildRepository
@@ -137,3 +138,87 @@ func (g *guildService) UpdateVCMember(isMuted, isDeafened bool, userId, guildId
 func (g *guildService) GetVCMember(userId, guildId string) (*model.VCMember, error) {
 	return g.GuildRepository.GetVCMember(userId, guildId)
 }
+
+// HasPermission checks if the given user has all the given permissions in the guild.
+// The guild owner and administrators have every permission.
+func (g *guildService) HasPermission(userId string, guildId string, permission model.Permission) bool {
+	permissions, err := g.GuildRepository.GetMemberPermissions(userId, guildId)
+
+	if err != nil {
+		log.Printf("Unable to get the permissions of user %s in guild %s: %v\n", userId, guildId, err)
+		return false
+	}
+
+	return permissions.Has(permission)
+}
+
+// OutranksMember checks if the highest role of the given user is positioned above
+// the highest role of the member. The guild owner outranks every member.
+func (g *guildService) OutranksMember(userId, memberId, guildId string) bool {
+	position, err := g.GuildRepository.GetMemberPosition(userId, guildId)
+
+	if err != nil {
+		log.Printf("Unable to get the position of user %s in guild %s: %v\n", userId, guildId, err)
+		return false
+	}
+
+	memberPosition, err := g.GuildRepository.GetMemberPosition(memberId, guildId)
+
+	if err != nil {
+		log.Printf("Unable to get the position of user %s in guild %s: %v\n", memberId, guildId, err)
+		return false
+	}
+
+	return position > memberPosition
+}
+
+// OutranksRole checks if the highest role of the given user is positioned above the role.
+// The guild owner outranks every role.
+func (g *guildService) OutranksRole(userId string, role *model.Role) bool {
+	position, err := g.GuildRepository.GetMemberPosition(userId, role.GuildId)
+
+	if err != nil {
+		log.Printf("Unable to get the position of user %s in guild %s: %v\n", userId, role.GuildId, err)
+		return false
+	}
+
+	return position > role.Position
+}
+
+func (g *guildService) GetRoles(guildId string) (*[]model.RoleResponse, error) {
+	return g.GuildRepository.GetRoles(guildId)
+}
+
+func (g *guildService) GetRole(roleId string) (*model.Role, error) {
+	return g.GuildRepository.FindRoleByID(roleId)
+}
+
+func (g *guildService) CreateRole(role *model.Role) (*model.Role, error) {
+	role.ID = GenerateId()
+
+	return g.GuildRepository.CreateRole(role)
+}
+
+func (g *guildService) UpdateRole(role *model.Role) error {
+	return g.GuildRepository.SaveRole(role)
+}
+
+func (g *guildService) DeleteRole(roleId string) error {
+	return g.GuildRepository.DeleteRole(roleId)
+}
+
+func (g *guildService) AddMemberRole(userId, guildId, roleId string) error {
+	return g.GuildRepository.AddMemberRole(&model.MemberRole{
+		UserID:  userId,
+		GuildID: guildId,
+		RoleID:  roleId,
+	})
+}
+
+func (g *guildService) RemoveMemberRole(userId, guildId, roleId string) error {
+	return g.GuildRepository.RemoveMemberRole(&model.MemberRole{
+		UserID:  userId,
+		GuildID: guildId,
+		RoleID:  roleId,
+	})
+}
@@ -129,3 +129,203 @@ func TestGuildService_GenerateInviteLink(t *testing.T) {
 		mockRedisRepository.AssertExpectations(t)
 	})
 }
+
+func TestGuildService_HasPermission(t *testing.T) {
+	userId := fixture.RandID()
+	guildId := fixture.RandID()
+
+	testCases := []struct {
+		name        string
+		permissions model.Permission
+		err         error
+		permission  model.Permission
+		expected    bool
+	}{
+		{
+			name:        "Has the permission",
+			permissions: model.PermissionKickMembers | model.PermissionBanMembers,
+			permission:  model.PermissionBanMembers,
+			expected:    true,
+		},
+		{
+			name:        "Missing the permission",
+			permissions: model.PermissionKickMembers,
+			permission:  model.PermissionBanMembers,
+			expected:    false,
+		},
+		{
+			name:        "Missing one of multiple permissions",
+			permissions: model.PermissionKickMembers,
+			permission:  model.PermissionKickMembers | model.PermissionBanMembers,
+			expected:    false,
+		},
+		{
+			name:        "Administrator has every permission",
+			permissions: model.PermissionAdministrator,
+			permission:  model.PermissionManageGuild,
+			expected:    true,
+		},
+		{
+			name:       "Repository error",
+			err:        apperrors.NewInternal(),
+			permission: model.PermissionManageGuild,
+			expected:   false,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			mockGuildRepository := new(mocks.GuildRepository)
+			gs := NewGuildService(&GSConfig{
+				GuildRepository: mockGuildRepository,
+			})
+
+			mockGuildRepository.
+				On("GetMemberPermissions", userId, guildId).
+				Return(tc.permissions, tc.err)
+
+			assert.Equal(t, tc.expected, gs.HasPermission(userId, guildId, tc.permission))
+
+			mockGuildRepository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGuildService_OutranksMember(t *testing.T) {
+	userId := fixture.RandID()
+	memberId := fixture.RandID()
+	guildId := fixture.RandID()
+
+	testCases := []struct {
+		name           string
+		position       int
+		memberPosition int
+		err            error
+		expected       bool
+	}{
+		{
+			name:           "Ranks above the member",
+			position:       2,
+			memberPosition: 1,
+			expected:       true,
+		},
+		{
+			name:           "Ranks equal to the member",
+			position:       1,
+			memberPosition: 1,
+			expected:       false,
+		},
+		{
+			name:           "Member without roles",
+			position:       0,
+			memberPosition: model.NoRolePosition,
+			expected:       true,
+		},
+		{
+			name:           "Owner outranks every member",
+			position:       model.OwnerPosition,
+			memberPosition: 249,
+			expected:       true,
+		},
+		{
+			name:     "Repository error",
+			err:      apperrors.NewInternal(),
+			expected: false,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			mockGuildRepository := new(mocks.GuildRepository)
+			gs := NewGuildService(&GSConfig{
+				GuildRepository: mockGuildRepository,
+			})
+
+			mockGuildRepository.On("GetMemberPosition", userId, guildId).Return(tc.position, tc.err)
+			mockGuildRepository.On("GetMemberPosition", memberId, guildId).Return(tc.memberPosition, nil)
+
+			assert.Equal(t, tc.expected, gs.OutranksMember(userId, memberId, guildId))
+		})
+	}
+}
+
+func TestGuildService_OutranksRole(t *testing.T) {
+	userId := fixture.RandID()
+	mockRole := fixture.GetMockRole(fixture.RandID())
+	mockRole.Position = 3
+
+	testCases := []struct {
+		name     string
+		position int
+		err      error
+		expected bool
+	}{
+		{
+			name:     "Ranks above the role",
+			position: 4,
+			expected: true,
+		},
+		{
+			name:     "Has the role",
+			position: 3,
+			expected: false,
+		},
+		{
+			name:     "Owner outranks every role",
+			position: model.OwnerPosition,
+			expected: true,
+		},
+		{
+			name:     "Repository error",
+			err:      apperrors.NewInternal(),
+			expected: false,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			mockGuildRepository := new(mocks.GuildRepository)
+			gs := NewGuildService(&GSConfig{
+				GuildRepository: mockGuildRepository,
+			})
+
+			mockGuildRepository.On("GetMemberPosition", userId, mockRole.GuildId).Return(tc.position, tc.err)
+
+			assert.Equal(t, tc.expected, gs.OutranksRole(userId, mockRole))
+
+			mockGuildRepository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGuildService_CreateRole(t *testing.T) {
+	mockRole := fixture.GetMockRole(fixture.RandID())
+
+	params := &model.Role{
+		GuildId: mockRole.GuildId,
+		Name:    mockRole.Name,
+	}
+
+	mockGuildRepository := new(mocks.GuildRepository)
+	gs := NewGuildService(&GSConfig{
+		GuildRepository: mockGuildRepository,
+	})
+
+	mockGuildRepository.
+		On("CreateRole", params).
+		Return(mockRole, nil)
+
+	role, err := gs.CreateRole(params)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, params.ID)
+	assert.Equal(t, mockRole, role)
+
+	mockGuildRepository.AssertExpectations(t)
+}
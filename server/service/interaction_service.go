@@ -0,0 +1,401 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/ws"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// interactionService acts as a struct for injecting an implementation of CommandRepository
+// and RedisRepository for use in service methods
+type interactionService struct {
+	CommandRepository model.CommandRepository
+	RedisRepository   model.RedisRepository
+	HttpClient        *http.Client
+}
+
+// ISConfig will hold repositories that will eventually be injected into
+// this service layer.
+// HttpClient sends the interactions to the interactions url of the bots.
+type ISConfig struct {
+	CommandRepository model.CommandRepository
+	RedisRepository   model.RedisRepository
+	HttpClient        *http.Client
+}
+
+// NewInteractionService is a factory function for
+// initializing an InteractionService with its repository layer dependencies
+func NewInteractionService(c *ISConfig) model.InteractionService {
+	return &interactionService{
+		CommandRepository: c.CommandRepository,
+		RedisRepository:   c.RedisRepository,
+		HttpClient:        c.HttpClient,
+	}
+}
+
+// NewInteractionHttpClient returns the client for sending the interactions to the bots.
+// It does not follow redirects and refuses to connect to loopback, private, link-local
+// and unspecified addresses, so bots cannot reach internal services with their interactions url.
+func NewInteractionHttpClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: model.InteractionDeliveryTimeout,
+		// The address is already resolved, so hostnames pointing to internal addresses are rejected as well
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("the address %s is not public", address)
+			}
+
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: model.InteractionDeliveryTimeout,
+		Transport: &http.Transport{
+			// A proxy would make the dialer check the address of the proxy instead
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: model.InteractionDeliveryTimeout,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// isPublicIP reports whether the ip can be reached by the interactions client
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
+
+func (s *interactionService) GetCommands(botId string) (*[]model.Command, error) {
+	return s.CommandRepository.FindByBot(botId)
+}
+
+func (s *interactionService) GetGuildCommands(guildId string) (*[]model.Command, error) {
+	return s.CommandRepository.FindByGuild(guildId)
+}
+
+func (s *interactionService) GetCommand(commandId string) (*model.Command, error) {
+	return s.CommandRepository.FindByID(commandId)
+}
+
+// CreateCommand registers the command for its bot.
+// The name has to be unique among the global or guild commands of the bot.
+func (s *interactionService) CreateCommand(command *model.Command) error {
+	count, err := s.CommandRepository.CountByBot(command.BotId)
+
+	if err != nil {
+		return err
+	}
+
+	if count >= model.CommandLimit {
+		return apperrors.NewBadRequest(apperrors.CommandLimitError)
+	}
+
+	if err = s.checkCommandName(command); err != nil {
+		return err
+	}
+
+	command.ID = GenerateId()
+
+	return s.CommandRepository.Create(command)
+}
+
+// UpdateCommand saves the changes of the command if its name is still unique
+func (s *interactionService) UpdateCommand(command *model.Command) error {
+	if err := s.checkCommandName(command); err != nil {
+		return err
+	}
+
+	return s.CommandRepository.Update(command)
+}
+
+func (s *interactionService) DeleteCommand(command *model.Command) error {
+	return s.CommandRepository.Delete(command.ID)
+}
+
+// checkCommandName returns a Conflict error if another command of the bot already uses the name
+func (s *interactionService) checkCommandName(command *model.Command) error {
+	existing, err := s.CommandRepository.FindByName(command.BotId, command.GuildId, command.Name)
+
+	if err != nil {
+		var e *apperrors.Error
+		if errors.As(err, &e) && e.Type == apperrors.NotFound {
+			return nil
+		}
+		return err
+	}
+
+	if existing.ID != command.ID {
+		return apperrors.NewConflict("command", command.Name)
+	}
+
+	return nil
+}
+
+// CreateInteraction validates the options of the invocation and stores the
+// interaction for InteractionTokenExpiry, so the bot can respond to it with its token
+func (s *interactionService) CreateInteraction(
+	ctx context.Context,
+	command *model.Command,
+	channel *model.Channel,
+	user *model.MemberResponse,
+	options []model.InteractionOption,
+) (*model.Interaction, error) {
+	values, err := validateInteractionOptions(command, options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := generateSecret()
+
+	if err != nil {
+		log.Printf("Failed to generate an interaction token: %v\n", err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	now := time.Now()
+	interaction := model.Interaction{
+		Id:          GenerateId(),
+		Token:       token,
+		BotId:       command.BotId,
+		CommandId:   command.ID,
+		CommandName: command.Name,
+		GuildId:     *channel.GuildID,
+		ChannelId:   channel.ID,
+		User:        *user,
+		Options:     values,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(model.InteractionTokenExpiry),
+	}
+
+	if err = s.RedisRepository.SaveInteraction(ctx, &interaction); err != nil {
+		return nil, err
+	}
+
+	return &interaction, nil
+}
+
+// DeliverInteraction sends the interaction_create event to the interactions url of the bot.
+// The request is signed with the interactions secret of the bot and
+// only a 2xx status within InteractionDeliveryTimeout counts as delivered.
+func (s *interactionService) DeliverInteraction(bot *model.User, interaction *model.Interaction) error {
+	body, err := json.Marshal(model.WebsocketMessage{
+		Action: ws.InteractionCreateAction,
+		Data:   interaction,
+	})
+
+	if err != nil {
+		log.Printf("error marshalling interaction: %v\n", err)
+		return apperrors.NewInternal()
+	}
+
+	req, err := http.NewRequest(http.MethodPost, *bot.InteractionsUrl, bytes.NewReader(body))
+
+	if err != nil {
+		log.Printf("Invalid interactions url of bot %s: %v\n", bot.ID, err.Error())
+		return apperrors.NewBadRequest(apperrors.InteractionDeliveryError)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(model.InteractionTimestampHeader, timestamp)
+	req.Header.Set(model.InteractionSignatureHeader, signInteraction(bot.InteractionsSecret, timestamp, body))
+
+	res, err := s.HttpClient.Do(req)
+
+	if err != nil {
+		log.Printf("Failed to deliver interaction to bot %s: %v\n", bot.ID, err.Error())
+		return apperrors.NewBadRequest(apperrors.InteractionDeliveryError)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		log.Printf("The interactions url of bot %s responded with %d\n", bot.ID, res.StatusCode)
+		return apperrors.NewBadRequest(apperrors.InteractionDeliveryError)
+	}
+
+	return nil
+}
+
+// GetInteraction returns the interaction of the token if the token belongs to the given interaction
+func (s *interactionService) GetInteraction(ctx context.Context, interactionId string, token string) (*model.Interaction, error) {
+	interaction, err := s.RedisRepository.GetInteraction(ctx, token)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if interaction.Id != interactionId {
+		return nil, apperrors.NewAuthorization(apperrors.InvalidInteractionToken)
+	}
+
+	return interaction, nil
+}
+
+// DeferInteraction marks the interaction as deferred. An interaction can only be deferred once.
+func (s *interactionService) DeferInteraction(ctx context.Context, interaction *model.Interaction) error {
+	if interaction.Deferred {
+		return apperrors.NewBadRequest(apperrors.InteractionDeferredError)
+	}
+
+	interaction.Deferred = true
+
+	return s.RedisRepository.UpdateInteraction(ctx, interaction)
+}
+
+// CompleteInteraction uses up the token of the interaction before the bot responds with a message.
+// The token gets removed in the same step it is read, so only one response succeeds.
+func (s *interactionService) CompleteInteraction(ctx context.Context, interaction *model.Interaction) error {
+	consumed, err := s.RedisRepository.ConsumeInteraction(ctx, interaction.Token)
+
+	if err != nil {
+		return err
+	}
+
+	if consumed.Id != interaction.Id {
+		return apperrors.NewAuthorization(apperrors.InvalidInteractionToken)
+	}
+
+	return nil
+}
+
+// validateInteractionOptions checks the given options against the options of the command
+// and returns them in the order of the command
+func validateInteractionOptions(command *model.Command, options []model.InteractionOption) ([]model.InteractionOption, error) {
+	known := make(map[string]bool)
+	for _, option := range command.Options {
+		known[option.Name] = true
+	}
+
+	given := make(map[string]any)
+
+	for _, option := range options {
+		if !known[option.Name] {
+			return nil, apperrors.NewBadRequest(fmt.Sprintf(apperrors.UnknownCommandOption, option.Name))
+		}
+
+		if _, ok := given[option.Name]; ok {
+			return nil, apperrors.NewBadRequest(fmt.Sprintf(apperrors.DuplicateCommandOption, option.Name))
+		}
+
+		given[option.Name] = option.Value
+	}
+
+	values := make([]model.InteractionOption, 0, len(options))
+
+	for _, option := range command.Options {
+		value := given[option.Name]
+
+		if value == nil {
+			if option.Required {
+				return nil, apperrors.NewBadRequest(fmt.Sprintf(apperrors.MissingCommandOption, option.Name))
+			}
+			continue
+		}
+
+		value, ok := optionValue(option.Type, value)
+
+		if !ok {
+			return nil, apperrors.NewBadRequest(fmt.Sprintf(apperrors.InvalidOptionValue, option.Name, option.Type))
+		}
+
+		values = append(values, model.InteractionOption{Name: option.Name, Value: value})
+	}
+
+	return values, nil
+}
+
+// optionValue returns the value converted to the given option type.
+// Users and channels are referenced by their ID.
+func optionValue(optionType string, value any) (any, bool) {
+	switch optionType {
+	case model.OptionTypeString:
+		text, ok := value.(string)
+		return text, ok
+	case model.OptionTypeInteger:
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) || math.Abs(number) > 1<<53 {
+			return nil, false
+		}
+		return int64(number), true
+	case model.OptionTypeBoolean:
+		flag, ok := value.(bool)
+		return flag, ok
+	case model.OptionTypeUser, model.OptionTypeChannel:
+		id, ok := value.(string)
+		if !ok || !isSnowflake(id) {
+			return nil, false
+		}
+		return id, true
+	}
+
+	return nil, false
+}
+
+// isSnowflake reports whether the id only consists of digits
+func isSnowflake(id string) bool {
+	if id == "" {
+		return false
+	}
+
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// generateSecret returns 256 random bits encoded as base64
+func generateSecret() (string, error) {
+	secret := make([]byte, 32)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// signInteraction returns the hex encoded HMAC-SHA256 of the timestamp and body.
+// Bots verify it to make sure the interaction came from the server.
+func signInteraction(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
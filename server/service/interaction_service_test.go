@@ -0,0 +1,379 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/sentrionic/valkyrie/ws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInteractionService_CreateCommand(t *testing.T) {
+	bot := fixture.GetMockBot(fixture.RandID())
+
+	t.Run("Success", func(t *testing.T) {
+		command := fixture.GetMockCommand(bot.ID)
+		command.ID = ""
+
+		mockCommandRepository := new(mocks.CommandRepository)
+		is := NewInteractionService(&ISConfig{
+			CommandRepository: mockCommandRepository,
+		})
+
+		mockCommandRepository.On("CountByBot", bot.ID).Return(int64(3), nil)
+		mockCommandRepository.
+			On("FindByName", bot.ID, (*string)(nil), command.Name).
+			Return(nil, apperrors.NewNotFound("command", command.Name))
+		mockCommandRepository.On("Create", command).Return(nil)
+
+		err := is.CreateCommand(command)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, command.ID)
+		mockCommandRepository.AssertExpectations(t)
+	})
+
+	t.Run("Limit reached", func(t *testing.T) {
+		command := fixture.GetMockCommand(bot.ID)
+
+		mockCommandRepository := new(mocks.CommandRepository)
+		is := NewInteractionService(&ISConfig{
+			CommandRepository: mockCommandRepository,
+		})
+
+		mockCommandRepository.On("CountByBot", bot.ID).Return(int64(model.CommandLimit), nil)
+
+		err := is.CreateCommand(command)
+
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.CommandLimitError), err)
+		mockCommandRepository.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Name already taken", func(t *testing.T) {
+		command := fixture.GetMockCommand(bot.ID)
+		existing := fixture.GetMockCommand(bot.ID)
+
+		mockCommandRepository := new(mocks.CommandRepository)
+		is := NewInteractionService(&ISConfig{
+			CommandRepository: mockCommandRepository,
+		})
+
+		mockCommandRepository.On("CountByBot", bot.ID).Return(int64(1), nil)
+		mockCommandRepository.On("FindByName", bot.ID, (*string)(nil), command.Name).Return(existing, nil)
+
+		err := is.CreateCommand(command)
+
+		assert.Equal(t, apperrors.NewConflict("command", command.Name), err)
+		mockCommandRepository.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestInteractionService_CreateInteraction(t *testing.T) {
+	bot := fixture.GetMockBot(fixture.RandID())
+	user := fixture.GetMockUser()
+	member := model.MemberResponse{Id: user.ID, Username: user.Username}
+	channel := fixture.GetMockChannel(fixture.RandID())
+	command := fixture.GetMockCommand(bot.ID)
+
+	t.Run("Success", func(t *testing.T) {
+		mockRedisRepository := new(mocks.RedisRepository)
+		is := NewInteractionService(&ISConfig{
+			RedisRepository: mockRedisRepository,
+		})
+
+		mockRedisRepository.On("SaveInteraction", mock.Anything, mock.AnythingOfType("*model.Interaction")).Return(nil)
+
+		options := []model.InteractionOption{
+			{Name: "times", Value: float64(3)},
+			{Name: "dice", Value: "d20"},
+		}
+
+		interaction, err := is.CreateInteraction(context.TODO(), command, channel, &member, options)
+		assert.NoError(t, err)
+
+		assert.NotEmpty(t, interaction.Id)
+		assert.NotEmpty(t, interaction.Token)
+		assert.Equal(t, bot.ID, interaction.BotId)
+		assert.Equal(t, command.ID, interaction.CommandId)
+		assert.Equal(t, "roll", interaction.CommandName)
+		assert.Equal(t, *channel.GuildID, interaction.GuildId)
+		assert.Equal(t, channel.ID, interaction.ChannelId)
+		assert.Equal(t, user.ID, interaction.User.Id)
+		assert.False(t, interaction.Deferred)
+		assert.WithinDuration(t, time.Now().Add(model.InteractionTokenExpiry), interaction.ExpiresAt, time.Second)
+
+		// Options follow the order of the command and integers get converted
+		assert.Equal(t, []model.InteractionOption{
+			{Name: "dice", Value: "d20"},
+			{Name: "times", Value: int64(3)},
+		}, interaction.Options)
+
+		mockRedisRepository.AssertExpectations(t)
+	})
+
+	testCases := []struct {
+		name    string
+		options []model.InteractionOption
+		reason  string
+	}{
+		{
+			name:    "Missing required option",
+			options: []model.InteractionOption{{Name: "times", Value: float64(1)}},
+			reason:  fmt.Sprintf(apperrors.MissingCommandOption, "dice"),
+		},
+		{
+			name:    "Unknown option",
+			options: []model.InteractionOption{{Name: "dice", Value: "d6"}, {Name: "sides", Value: float64(6)}},
+			reason:  fmt.Sprintf(apperrors.UnknownCommandOption, "sides"),
+		},
+		{
+			name:    "Duplicate option",
+			options: []model.InteractionOption{{Name: "dice", Value: "d6"}, {Name: "dice", Value: "d8"}},
+			reason:  fmt.Sprintf(apperrors.DuplicateCommandOption, "dice"),
+		},
+		{
+			name:    "Wrong type",
+			options: []model.InteractionOption{{Name: "dice", Value: "d6"}, {Name: "times", Value: 1.5}},
+			reason:  fmt.Sprintf(apperrors.InvalidOptionValue, "times", model.OptionTypeInteger),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRedisRepository := new(mocks.RedisRepository)
+			is := NewInteractionService(&ISConfig{
+				RedisRepository: mockRedisRepository,
+			})
+
+			interaction, err := is.CreateInteraction(context.TODO(), command, channel, &member, tc.options)
+
+			assert.Nil(t, interaction)
+			assert.Equal(t, apperrors.NewBadRequest(tc.reason), err)
+			mockRedisRepository.AssertNotCalled(t, "SaveInteraction", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestInteractionService_DeliverInteraction(t *testing.T) {
+	bot := fixture.GetMockBot(fixture.RandID())
+	bot.InteractionsSecret = "secret"
+
+	interaction := &model.Interaction{
+		Id:        fixture.RandID(),
+		Token:     "token",
+		BotId:     bot.ID,
+		CommandId: fixture.RandID(),
+		Options:   make([]model.InteractionOption, 0),
+	}
+
+	t.Run("Signed request", func(t *testing.T) {
+		var received model.WebsocketMessage
+		var signature string
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &received)
+
+			timestamp := r.Header.Get(model.InteractionTimestampHeader)
+			signature = signInteraction(bot.InteractionsSecret, timestamp, body)
+
+			assert.Equal(t, signature, r.Header.Get(model.InteractionSignatureHeader))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		url := server.URL
+		bot.InteractionsUrl = &url
+
+		is := NewInteractionService(&ISConfig{
+			HttpClient: server.Client(),
+		})
+
+		err := is.DeliverInteraction(bot, interaction)
+
+		assert.NoError(t, err)
+		assert.Equal(t, ws.InteractionCreateAction, received.Action)
+		assert.NotEmpty(t, signature)
+	})
+
+	t.Run("Rejected by the bot", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		url := server.URL
+		bot.InteractionsUrl = &url
+
+		is := NewInteractionService(&ISConfig{
+			HttpClient: server.Client(),
+		})
+
+		err := is.DeliverInteraction(bot, interaction)
+
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.InteractionDeliveryError), err)
+	})
+
+	t.Run("Internal address", func(t *testing.T) {
+		called := false
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		// Hostnames get resolved before the address is checked
+		url := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+		bot.InteractionsUrl = &url
+
+		is := NewInteractionService(&ISConfig{
+			HttpClient: NewInteractionHttpClient(),
+		})
+
+		err := is.DeliverInteraction(bot, interaction)
+
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.InteractionDeliveryError), err)
+		assert.False(t, called)
+	})
+}
+
+func TestNewInteractionHttpClient(t *testing.T) {
+	client := NewInteractionHttpClient()
+
+	t.Run("Does not follow redirects", func(t *testing.T) {
+		assert.Equal(t, http.ErrUseLastResponse, client.CheckRedirect(nil, nil))
+	})
+
+	t.Run("Rejects internal addresses", func(t *testing.T) {
+		for _, ip := range []string{"127.0.0.1", "::1", "10.0.0.1", "172.16.0.1", "192.168.1.1", "169.254.169.254", "fe80::1", "fd00::1", "0.0.0.0", "::", "224.0.0.1"} {
+			assert.False(t, isPublicIP(net.ParseIP(ip)), ip)
+		}
+	})
+
+	t.Run("Allows public addresses", func(t *testing.T) {
+		for _, ip := range []string{"1.1.1.1", "93.184.216.34", "2606:4700:4700::1111"} {
+			assert.True(t, isPublicIP(net.ParseIP(ip)), ip)
+		}
+	})
+}
+
+func TestInteractionService_GetInteraction(t *testing.T) {
+	interaction := &model.Interaction{
+		Id:    fixture.RandID(),
+		Token: "token",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRedisRepository := new(mocks.RedisRepository)
+		is := NewInteractionService(&ISConfig{
+			RedisRepository: mockRedisRepository,
+		})
+
+		mockRedisRepository.On("GetInteraction", mock.Anything, interaction.Token).Return(interaction, nil)
+
+		result, err := is.GetInteraction(context.TODO(), interaction.Id, interaction.Token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, interaction, result)
+	})
+
+	t.Run("Token of another interaction", func(t *testing.T) {
+		mockRedisRepository := new(mocks.RedisRepository)
+		is := NewInteractionService(&ISConfig{
+			RedisRepository: mockRedisRepository,
+		})
+
+		mockRedisRepository.On("GetInteraction", mock.Anything, interaction.Token).Return(interaction, nil)
+
+		result, err := is.GetInteraction(context.TODO(), fixture.RandID(), interaction.Token)
+
+		assert.Nil(t, result)
+		assert.Equal(t, apperrors.NewAuthorization(apperrors.InvalidInteractionToken), err)
+	})
+}
+
+func TestInteractionService_DeferInteraction(t *testing.T) {
+	mockRedisRepository := new(mocks.RedisRepository)
+	is := NewInteractionService(&ISConfig{
+		RedisRepository: mockRedisRepository,
+	})
+
+	interaction := &model.Interaction{
+		Id:    fixture.RandID(),
+		Token: "token",
+	}
+
+	mockRedisRepository.On("UpdateInteraction", mock.Anything, interaction).Return(nil).Once()
+
+	err := is.DeferInteraction(context.TODO(), interaction)
+	assert.NoError(t, err)
+	assert.True(t, interaction.Deferred)
+
+	// An interaction can only be deferred once
+	err = is.DeferInteraction(context.TODO(), interaction)
+	assert.Equal(t, apperrors.NewBadRequest(apperrors.InteractionDeferredError), err)
+
+	mockRedisRepository.AssertExpectations(t)
+}
+
+func TestInteractionService_CompleteInteraction(t *testing.T) {
+	interaction := &model.Interaction{
+		Id:    fixture.RandID(),
+		Token: "token",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRedisRepository := new(mocks.RedisRepository)
+		is := NewInteractionService(&ISConfig{
+			RedisRepository: mockRedisRepository,
+		})
+
+		mockRedisRepository.On("ConsumeInteraction", mock.Anything, interaction.Token).Return(interaction, nil)
+
+		err := is.CompleteInteraction(context.TODO(), interaction)
+
+		assert.NoError(t, err)
+		mockRedisRepository.AssertExpectations(t)
+	})
+
+	t.Run("Token already used", func(t *testing.T) {
+		mockRedisRepository := new(mocks.RedisRepository)
+		is := NewInteractionService(&ISConfig{
+			RedisRepository: mockRedisRepository,
+		})
+
+		mockError := apperrors.NewAuthorization(apperrors.InvalidInteractionToken)
+		mockRedisRepository.On("ConsumeInteraction", mock.Anything, interaction.Token).Return(nil, mockError)
+
+		err := is.CompleteInteraction(context.TODO(), interaction)
+
+		assert.Equal(t, mockError, err)
+	})
+
+	t.Run("Token of another interaction", func(t *testing.T) {
+		mockRedisRepository := new(mocks.RedisRepository)
+		is := NewInteractionService(&ISConfig{
+			RedisRepository: mockRedisRepository,
+		})
+
+		other := &model.Interaction{Id: fixture.RandID(), Token: interaction.Token}
+		mockRedisRepository.On("ConsumeInteraction", mock.Anything, interaction.Token).Return(other, nil)
+
+		err := is.CompleteInteraction(context.TODO(), interaction)
+
+		assert.Equal(t, apperrors.NewAuthorization(apperrors.InvalidInteractionToken), err)
+	})
+}
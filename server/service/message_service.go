@@ -1,15 +1,20 @@
 package service
 
 import (
+	"bytes"
 	"fmt"
 	gonanoid "github.com/matoous/go-nanoid"
 	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"io"
 	"log"
 	"mime/multipart"
+	"os"
 	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 )
 
 // messageService acts as a struct for injecting an implementation of MessageRepository
@@ -17,42 +22,62 @@ import (
 type messageService struct {
 	MessageRepository model.MessageRepository
 	FileRepository    model.FileRepository
+	keepOriginals     map[string]bool
 }
 
 // MSConfig will hold repositories that will eventually be injected into
-// this service layer
+// this service layer.
+// KeepOriginals contains the image types that get stored without removing their metadata.
 type MSConfig struct {
 	MessageRepository model.MessageRepository
 	FileRepository    model.FileRepository
+	KeepOriginals     []string
 }
 
 // NewMessageService is a factory function for
 // initializing a UserService with its repository layer dependencies
 func NewMessageService(c *MSConfig) model.MessageService {
+	keepOriginals := make(map[string]bool)
+	for _, fileType := range c.KeepOriginals {
+		if fileType = model.NormalizeFileType(fileType); fileType != "" {
+			keepOriginals[fileType] = true
+		}
+	}
+
 	return &messageService{
 		MessageRepository: c.MessageRepository,
 		FileRepository:    c.FileRepository,
+		keepOriginals:     keepOriginals,
 	}
 }
 
-func (m *messageService) GetMessages(userId string, channel *model.Channel, cursor string) (*[]model.MessageResponse, error) {
-	return m.MessageRepository.GetMessages(userId, channel, cursor)
+func (m *messageService) GetMessages(userId string, channel *model.Channel, page *model.MessagePage) (*[]model.MessageResponse, error) {
+	return m.MessageRepository.GetMessages(userId, channel, page)
 }
 
 func (m *messageService) CreateMessage(params *model.Message) (*model.Message, error) {
 	params.ID = GenerateId()
+	params.Mentions = parseMentions(params.ID, params.Text)
 
 	return m.MessageRepository.CreateMessage(params)
 }
 
 func (m *messageService) UpdateMessage(message *model.Message) error {
+	message.Mentions = parseMentions(message.ID, message.Text)
+
 	return m.MessageRepository.UpdateMessage(message)
 }
 
 func (m *messageService) DeleteMessage(message *model.Message) error {
-	if message.Attachment != nil {
-		if err := m.FileRepository.DeleteImage(message.Attachment.Filename); err != nil {
-			log.Printf("Error deleting file from S3: %s", err)
+	for _, attachment := range message.Attachments {
+		if err := m.FileRepository.DeleteImage(attachment.Url); err != nil {
+			log.Printf("Error deleting file from storage: %s", err)
+		}
+
+		if attachment.ThumbnailUrl != nil {
+			if err := m.FileRepository.DeleteImage(*attachment.ThumbnailUrl); err != nil {
+				log.Printf("Error deleting thumbnail from storage: %s", err)
+			}
 		}
 	}
 
@@ -71,8 +96,39 @@ func (m *messageService) UploadFile(header *multipart.FileHeader, channelId stri
 
 	attachment.ID = GenerateId()
 
+	file, err := header.Open()
+
+	if err != nil {
+		log.Printf("Failed to open header: %v\n", err)
+		return nil, apperrors.NewInternal()
+	}
+
+	defer file.Close()
+
+	var body seekableFile = file
+
+	stripped, err := m.stripMetadata(file, mimetype)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if stripped != nil {
+		body = stripped
+	}
+
+	if err = readFileMetadata(&attachment, body); err != nil {
+		log.Printf("Failed to read the file metadata: %v\n", err)
+		return nil, apperrors.NewInternal()
+	}
+
+	if _, err = body.Seek(0, io.SeekStart); err != nil {
+		log.Printf("Failed to seek file: %v\n", err)
+		return nil, apperrors.NewInternal()
+	}
+
 	directory := fmt.Sprintf("channels/%s", channelId)
-	url, err := m.FileRepository.UploadFile(header, directory, filename, mimetype)
+	url, err := m.FileRepository.UploadFile(body, directory, filename, mimetype)
 
 	if err != nil {
 		return nil, err
@@ -80,20 +136,495 @@ func (m *messageService) UploadFile(header *multipart.FileHeader, channelId stri
 
 	attachment.Url = url
 
+	// Only decodable images get a thumbnail
+	if attachment.Width != nil {
+		if _, err = body.Seek(0, io.SeekStart); err != nil {
+			log.Printf("Failed to seek file: %v\n", err)
+			return nil, apperrors.NewInternal()
+		}
+
+		thumbnail, err := m.FileRepository.UploadThumbnail(body, directory, thumbnailName(filename))
+
+		if err != nil {
+			return nil, err
+		}
+
+		attachment.ThumbnailUrl = &thumbnail
+	}
+
 	return &attachment, nil
 }
 
+// stripMetadata re-encodes jpeg and png images to remove their metadata and apply their orientation,
+// unless the originals of their type are kept. It returns nil for files that get stored unchanged.
+func (m *messageService) stripMetadata(file io.Reader, mimetype string) (*bytes.Reader, error) {
+	format, ok := strippedImageTypes[mimetype]
+
+	if !ok || m.keepOriginals[mimetype] {
+		return nil, nil
+	}
+
+	stripped, err := stripImageMetadata(file, format)
+
+	if err != nil {
+		log.Printf("Failed to re-encode image: %v\n", err)
+		return nil, apperrors.NewBadRequest(apperrors.InvalidImageError)
+	}
+
+	return stripped, nil
+}
+
+func (m *messageService) CreateUpload(upload *model.Upload) (string, error) {
+	upload.ID = GenerateId()
+	upload.Filename = formatName(upload.Filename)
+	upload.ExpiresAt = time.Now().Add(model.UploadExpiration)
+
+	// The file gets uploaded to a staging key and moved to the channel once it got checked
+	directory := fmt.Sprintf("uploads/%s", upload.ID)
+	uploadUrl, url, err := m.FileRepository.PresignUpload(directory, upload.Filename, upload.FileType, upload.Size)
+
+	if err != nil {
+		return "", err
+	}
+
+	upload.Url = url
+
+	if err = m.MessageRepository.CreateUpload(upload); err != nil {
+		return "", err
+	}
+
+	return uploadUrl, nil
+}
+
+func (m *messageService) GetUpload(uploadId string) (*model.Upload, error) {
+	return m.MessageRepository.GetUpload(uploadId)
+}
+
+// FinalizeUpload turns the uploaded file into an attachment with the ID of the upload.
+// Files that do not match the declared size or type get deleted together with their upload.
+// Images get re-encoded without their metadata like the files sent with a message.
+// The checked content gets stored in the directory of the channel and the staged file gets deleted,
+// so writing to the upload url afterwards cannot change the attachment.
+func (m *messageService) FinalizeUpload(upload *model.Upload) (*model.Attachment, error) {
+	if !upload.IsComplete() {
+		return nil, apperrors.NewBadRequest(apperrors.UploadIncompleteError)
+	}
+
+	body, size, err := m.FileRepository.OpenFile(upload.Url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer body.Close()
+
+	if size != upload.Size || size > model.MaximumUploadSize {
+		return nil, m.rejectUpload(upload, apperrors.UploadMismatchError)
+	}
+
+	// Copy the file to read its metadata and create the thumbnail
+	file, err := os.CreateTemp("", "upload-*")
+
+	if err != nil {
+		log.Printf("Failed to create temporary file: %v\n", err)
+		return nil, apperrors.NewInternal()
+	}
+
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if _, err = io.Copy(file, body); err != nil {
+		log.Printf("Failed to download the upload %s: %v\n", upload.ID, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		log.Printf("Failed to seek file: %v\n", err)
+		return nil, apperrors.NewInternal()
+	}
+
+	// The declared type got checked against the allowlist, so the content has to match it
+	matches, err := model.MatchesFileType(file, upload.FileType)
+
+	if err != nil {
+		log.Printf("Failed to detect the file type: %v\n", err)
+		return nil, apperrors.NewInternal()
+	}
+
+	if !matches {
+		return nil, m.rejectUpload(upload, apperrors.FileTypeMismatchError)
+	}
+
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		log.Printf("Failed to seek file: %v\n", err)
+		return nil, apperrors.NewInternal()
+	}
+
+	attachment := model.Attachment{
+		ID:       upload.ID,
+		Url:      upload.Url,
+		FileType: upload.FileType,
+		Filename: upload.Filename,
+	}
+
+	directory := fmt.Sprintf("channels/%s", upload.ChannelId)
+
+	var content seekableFile = file
+
+	stripped, err := m.stripMetadata(file, upload.FileType)
+
+	if err != nil {
+		return nil, m.rejectUpload(upload, apperrors.InvalidImageError)
+	}
+
+	// The stripped image replaces the uploaded file
+	if stripped != nil {
+		content = stripped
+	}
+
+	url, err := m.FileRepository.UploadFile(content, directory, upload.Filename, upload.FileType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = content.Seek(0, io.SeekStart); err != nil {
+		log.Printf("Failed to seek file: %v\n", err)
+		return nil, apperrors.NewInternal()
+	}
+
+	// Only the stored copy is used from now on
+	if url != upload.Url {
+		if err = m.MessageRepository.SetUploadUrl(upload.ID, url); err != nil {
+			_ = m.FileRepository.DeleteImage(url)
+			return nil, err
+		}
+
+		if err = m.FileRepository.DeleteImage(upload.Url); err != nil {
+			log.Printf("Error deleting file from storage: %s", err)
+		}
+
+		upload.Url = url
+	}
+
+	attachment.Url = url
+
+	if err = readFileMetadata(&attachment, content); err != nil {
+		log.Printf("Failed to read the file metadata: %v\n", err)
+		return nil, apperrors.NewInternal()
+	}
+
+	// Only decodable images get a thumbnail
+	if attachment.Width != nil {
+		if _, err = content.Seek(0, io.SeekStart); err != nil {
+			log.Printf("Failed to seek file: %v\n", err)
+			return nil, apperrors.NewInternal()
+		}
+
+		thumbnail, err := m.FileRepository.UploadThumbnail(content, directory, thumbnailName(upload.Filename))
+
+		if err != nil {
+			return nil, err
+		}
+
+		attachment.ThumbnailUrl = &thumbnail
+	}
+
+	return &attachment, nil
+}
+
+// rejectUpload deletes the uploaded file together with its upload
+// and returns the bad request error with the given reason
+func (m *messageService) rejectUpload(upload *model.Upload, reason string) error {
+	if err := m.FileRepository.DeleteImage(upload.Url); err != nil {
+		log.Printf("Error deleting file from storage: %s", err)
+	}
+	_ = m.MessageRepository.DeleteUploads([]string{upload.ID})
+	return apperrors.NewBadRequest(reason)
+}
+
+func (m *messageService) CreateResumableUpload(upload *model.Upload) error {
+	upload.ID = GenerateId()
+	upload.Filename = formatName(upload.Filename)
+	upload.Resumable = true
+	upload.ExpiresAt = time.Now().Add(model.UploadExpiration)
+
+	return m.MessageRepository.CreateUpload(upload)
+}
+
+// AppendUploadChunk stores the chunk at the current offset of the resumable upload
+// and extends its expiration. Once all bytes got received the chunks get merged
+// into the file, which gets retried by appending an empty chunk if it failed.
+func (m *messageService) AppendUploadChunk(upload *model.Upload, chunk io.Reader) error {
+	remaining := upload.Size - upload.Offset
+	directory := fmt.Sprintf("uploads/%s", upload.ID)
+
+	// Read one more byte than remaining to detect chunks exceeding the upload length
+	url, size, err := m.FileRepository.UploadChunk(directory, GenerateId(), io.LimitReader(chunk, remaining+1))
+
+	if err != nil {
+		return err
+	}
+
+	if size == 0 || size > remaining {
+		if err = m.FileRepository.DeleteImage(url); err != nil {
+			log.Printf("Error deleting chunk from storage: %s", err)
+		}
+
+		if size > remaining {
+			return apperrors.NewBadRequest(apperrors.UploadMismatchError)
+		}
+	} else {
+		part := model.UploadChunk{
+			UploadId: upload.ID,
+			Offset:   upload.Offset,
+			Size:     size,
+			Url:      url,
+		}
+
+		upload.Offset += size
+		upload.ExpiresAt = time.Now().Add(model.UploadExpiration)
+
+		if err = m.MessageRepository.AddUploadChunk(upload, &part); err != nil {
+			upload.Offset -= size
+			_ = m.FileRepository.DeleteImage(url)
+			return err
+		}
+
+		upload.Chunks = append(upload.Chunks, part)
+	}
+
+	if upload.Offset < upload.Size || upload.Url != "" {
+		return nil
+	}
+
+	urls := make([]string, 0)
+	for _, part := range upload.Chunks {
+		urls = append(urls, part.Url)
+	}
+
+	fileUrl, err := m.FileRepository.MergeChunks(urls, directory, upload.Filename, upload.FileType)
+
+	if err != nil {
+		return err
+	}
+
+	if err = m.MessageRepository.SetUploadUrl(upload.ID, fileUrl); err != nil {
+		_ = m.FileRepository.DeleteImage(fileUrl)
+		return err
+	}
+
+	upload.Url = fileUrl
+
+	// The chunks are no longer needed once they got merged
+	for _, part := range upload.Chunks {
+		if err = m.FileRepository.DeleteImage(part.Url); err != nil {
+			log.Printf("Error deleting chunk from storage: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// TerminateUpload deletes the upload together with its chunks and file
+func (m *messageService) TerminateUpload(upload *model.Upload) error {
+	if err := m.deleteUploadFiles(upload); err != nil {
+		return err
+	}
+
+	return m.MessageRepository.DeleteUploads([]string{upload.ID})
+}
+
+// DeleteExpiredUploads deletes the uploads that did not get attached in time and their files.
+// Uploads whose files could not be deleted are retried in the next run.
+func (m *messageService) DeleteExpiredUploads() error {
+	uploads, err := m.MessageRepository.GetExpiredUploads()
+
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, 0)
+	for i := range *uploads {
+		upload := (*uploads)[i]
+		if err = m.deleteUploadFiles(&upload); err != nil {
+			continue
+		}
+
+		ids = append(ids, upload.ID)
+	}
+
+	return m.MessageRepository.DeleteUploads(ids)
+}
+
+// deleteUploadFiles deletes the file and the remaining chunks of the upload from the storage
+func (m *messageService) deleteUploadFiles(upload *model.Upload) error {
+	urls := []string{upload.Url}
+	for _, part := range upload.Chunks {
+		urls = append(urls, part.Url)
+	}
+
+	for _, url := range urls {
+		if err := m.FileRepository.DeleteImage(url); err != nil {
+			log.Printf("Error deleting file from storage: %s", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *messageService) Get(messageId string) (*model.Message, error) {
 	return m.MessageRepository.GetById(messageId)
 }
 
-var re = regexp.MustCompile(`/[^a-z0-9]/g`)
+func (m *messageService) AddReaction(reaction *model.Reaction) error {
+	emojis, err := m.MessageRepository.GetReactionEmojis(reaction.MessageId)
 
+	if err != nil {
+		return err
+	}
+
+	// Only check the limit for emojis the message was not reacted with yet
+	isNew := true
+	for _, emoji := range emojis {
+		if emoji == reaction.Emoji {
+			isNew = false
+			break
+		}
+	}
+
+	if isNew && len(emojis) >= model.MaximumReactions {
+		return apperrors.NewBadRequest(apperrors.ReactionLimitError)
+	}
+
+	return m.MessageRepository.AddReaction(reaction)
+}
+
+func (m *messageService) RemoveReaction(messageId, userId, emoji string) error {
+	return m.MessageRepository.RemoveReaction(messageId, userId, emoji)
+}
+
+func (m *messageService) GetReactionUsers(channel *model.Channel, messageId, emoji string) (*[]model.MemberResponse, error) {
+	return m.MessageRepository.GetReactionUsers(channel, messageId, emoji)
+}
+
+func (m *messageService) GetMessageReactions(messageId string) ([]model.ReactionResponse, error) {
+	return m.MessageRepository.GetMessageReactions(messageId)
+}
+
+func (m *messageService) GetPinnedMessages(userId string, channel *model.Channel) (*[]model.MessageResponse, error) {
+	return m.MessageRepository.GetPinnedMessages(userId, channel)
+}
+
+func (m *messageService) PinMessage(message *model.Message) error {
+	now := time.Now()
+	if err := m.MessageRepository.PinMessage(message, now, model.MaximumPins); err != nil {
+		return err
+	}
+
+	message.PinnedAt = &now
+	return nil
+}
+
+func (m *messageService) UnpinMessage(message *model.Message) error {
+	if err := m.MessageRepository.SetPinned(message.ID, nil); err != nil {
+		return err
+	}
+
+	message.PinnedAt = nil
+	return nil
+}
+
+func (m *messageService) GetMessageMentions(channel *model.Channel, messageId string) (*model.MessageMentions, error) {
+	return m.MessageRepository.GetMessageMentions(channel, messageId)
+}
+
+func (m *messageService) GetMentionedUsers(channel *model.Channel, message *model.Message) ([]string, error) {
+	// DMs only have user mentions
+	if channel.GuildID == nil {
+		users := make([]string, 0)
+		for _, mention := range message.Mentions {
+			if mention.Type == model.MentionTypeUser {
+				users = append(users, mention.TargetId)
+			}
+		}
+		return users, nil
+	}
+
+	return m.MessageRepository.GetMentionedMembers(*channel.GuildID, message)
+}
+
+func (m *messageService) GetUserMentions(userId string) (*[]model.MentionNotification, error) {
+	return m.MessageRepository.GetUserMentions(userId)
+}
+
+func (m *messageService) SearchMessages(userId string, channelIds []string, search *model.MessageSearch) (*model.MessageSearchResponse, error) {
+	return m.MessageRepository.SearchMessages(userId, channelIds, search)
+}
+
+func (m *messageService) GetMessageRevisions(messageId string) (*[]model.MessageRevision, error) {
+	return m.MessageRepository.GetMessageRevisions(messageId)
+}
+
+// mentionRe matches user mentions (<@userId>), role mentions (<@&roleId>) and @everyone
+var mentionRe = regexp.MustCompile(`<@(&?)(\d+)>|@everyone`)
+
+// parseMentions returns the distinct mentions contained in the given text
+func parseMentions(messageId string, text *string) []model.Mention {
+	mentions := make([]model.Mention, 0)
+
+	if text == nil {
+		return mentions
+	}
+
+	seen := make(map[model.Mention]bool)
+	for _, match := range mentionRe.FindAllStringSubmatch(*text, -1) {
+		mention := model.Mention{MessageId: messageId}
+
+		switch {
+		case match[0] == "@everyone":
+			mention.Type = model.MentionTypeEveryone
+		case match[1] == "&":
+			mention.Type = model.MentionTypeRole
+			mention.TargetId = match[2]
+		default:
+			mention.Type = model.MentionTypeUser
+			mention.TargetId = match[2]
+		}
+
+		if !seen[mention] {
+			seen[mention] = true
+			mentions = append(mentions, mention)
+		}
+	}
+
+	return mentions
+}
+
+// invalidNameRe matches the characters that get replaced in the names of stored files
+var invalidNameRe = regexp.MustCompile("[^a-z0-9]")
+
+// formatName returns a unique name for the stored file that only contains lowercase letters,
+// numbers and dashes followed by its extension. Directories in the filename get removed,
+// so the file always gets stored in the directory of its channel.
 func formatName(filename string) string {
+	filename = path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	filename = strings.ToLower(filename)
+
 	ext := path.Ext(filename)
+	filename = strings.TrimSuffix(filename, ext)
+	filename = invalidNameRe.ReplaceAllString(filename, "-")
+
+	if ext = invalidNameRe.ReplaceAllString(strings.TrimPrefix(ext, "."), "-"); ext != "" {
+		ext = "." + ext
+	}
+
 	id, _ := gonanoid.Nanoid(5)
-	filename = strings.TrimSuffix(filename, filepath.Ext(filename))
-	filename = strings.ToLower(filename)
-	filename = re.ReplaceAllString(filename, "-")
 	return fmt.Sprintf("%s-%s%s", id, filename, ext)
 }
+
+// thumbnailName returns the filename of the thumbnail of the given file
+func thumbnailName(filename string) string {
+	return fmt.Sprintf("thumbnail-%s.jpeg", strings.TrimSuffix(filename, filepath.Ext(filename)))
+}
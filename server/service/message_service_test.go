@@ -1,6 +1,9 @@
 package service
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"github.com/sentrionic/valkyrie/mocks"
 	"github.com/sentrionic/valkyrie/model"
@@ -8,7 +11,12 @@ import (
 	"github.com/sentrionic/valkyrie/model/fixture"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"image"
+	"image/png"
+	"io"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestGuildService_CreateMessage(t *testing.T) {
@@ -43,6 +51,37 @@ func TestGuildService_CreateMessage(t *testing.T) {
 		mockMessageRepository.AssertExpectations(t)
 	})
 
+	t.Run("Parses mentions", func(t *testing.T) {
+		userId := fixture.RandID()
+		roleId := fixture.RandID()
+		text := fmt.Sprintf("<@%s> <@&%s> @everyone <@%s> <@abc>", userId, roleId, userId)
+
+		params := &model.Message{
+			UserId:    fixture.RandID(),
+			ChannelId: fixture.RandID(),
+			Text:      &text,
+		}
+
+		mockMessageRepository := new(mocks.MessageRepository)
+		ms := NewMessageService(&MSConfig{
+			MessageRepository: mockMessageRepository,
+		})
+
+		mockMessageRepository.On("CreateMessage", params).Return(params, nil)
+
+		message, err := ms.CreateMessage(params)
+		assert.NoError(t, err)
+
+		expected := []model.Mention{
+			{MessageId: message.ID, Type: model.MentionTypeUser, TargetId: userId},
+			{MessageId: message.ID, Type: model.MentionTypeRole, TargetId: roleId},
+			{MessageId: message.ID, Type: model.MentionTypeEveryone},
+		}
+		assert.Equal(t, expected, message.Mentions)
+
+		mockMessageRepository.AssertExpectations(t)
+	})
+
 	t.Run("Error", func(t *testing.T) {
 		mockMessage := fixture.GetMockMessage("", "")
 
@@ -91,8 +130,8 @@ func TestGuildService_DeleteMessage(t *testing.T) {
 
 	t.Run("Success with attachment", func(t *testing.T) {
 		mockMessage := fixture.GetMockMessage("", "")
-		mockMessage.Attachment = &model.Attachment{
-			Filename: fixture.RandStr(12),
+		mockMessage.Attachments = []model.Attachment{
+			{Filename: fixture.RandStr(12), Url: fixture.RandStr(24)},
 		}
 
 		mockMessageRepository := new(mocks.MessageRepository)
@@ -102,7 +141,7 @@ func TestGuildService_DeleteMessage(t *testing.T) {
 			FileRepository:    mockFileRepository,
 		})
 
-		mockFileRepository.On("DeleteImage", mockMessage.Attachment.Filename).Return(nil)
+		mockFileRepository.On("DeleteImage", mockMessage.Attachments[0].Url).Return(nil)
 
 		mockMessageRepository.
 			On("DeleteMessage", mockMessage).
@@ -116,6 +155,32 @@ func TestGuildService_DeleteMessage(t *testing.T) {
 		mockFileRepository.AssertExpectations(t)
 	})
 
+	t.Run("Success with thumbnail", func(t *testing.T) {
+		mockMessage := fixture.GetMockMessage("", "")
+		thumbnailUrl := fixture.RandStr(12)
+		mockMessage.Attachments = []model.Attachment{
+			{Filename: "abcde-image.png", Url: fixture.RandStr(24), ThumbnailUrl: &thumbnailUrl},
+		}
+
+		mockMessageRepository := new(mocks.MessageRepository)
+		mockFileRepository := new(mocks.FileRepository)
+		ms := NewMessageService(&MSConfig{
+			MessageRepository: mockMessageRepository,
+			FileRepository:    mockFileRepository,
+		})
+
+		mockFileRepository.On("DeleteImage", mockMessage.Attachments[0].Url).Return(nil)
+		mockFileRepository.On("DeleteImage", thumbnailUrl).Return(nil)
+		mockMessageRepository.On("DeleteMessage", mockMessage).Return(nil)
+
+		err := ms.DeleteMessage(mockMessage)
+
+		assert.NoError(t, err)
+
+		mockMessageRepository.AssertExpectations(t)
+		mockFileRepository.AssertExpectations(t)
+	})
+
 	t.Run("Error", func(t *testing.T) {
 		mockMessage := fixture.GetMockMessage("", "")
 
@@ -155,7 +220,7 @@ func TestMessageService_UploadFile(t *testing.T) {
 		}
 
 		uploadFileArgs := mock.Arguments{
-			imageFileHeader,
+			mock.Anything,
 			directory,
 			mock.AnythingOfType("string"),
 			attachment.FileType,
@@ -169,13 +234,18 @@ func TestMessageService_UploadFile(t *testing.T) {
 				attachment.ID = id
 			}).
 			Return(imageURL, nil)
+		mockFileRepository.
+			On("UploadThumbnail", mock.Anything, directory, mock.AnythingOfType("string")).
+			Return(imageURL+"-thumbnail", nil)
 
 		ms := NewMessageService(&MSConfig{
 			FileRepository: mockFileRepository,
 		})
 
-		_, err := ms.UploadFile(imageFileHeader, channelId)
+		result, err := ms.UploadFile(imageFileHeader, channelId)
 		assert.NoError(t, err)
+		assert.Equal(t, 1, *result.Width)
+		assert.Equal(t, imageURL+"-thumbnail", *result.ThumbnailUrl)
 
 		mockFileRepository.AssertExpectations(t)
 	})
@@ -194,7 +264,7 @@ func TestMessageService_UploadFile(t *testing.T) {
 		}
 
 		uploadFileArgs := mock.Arguments{
-			imageFileHeader,
+			mock.Anything,
 			directory,
 			mock.AnythingOfType("string"),
 			attachment.FileType,
@@ -219,3 +289,782 @@ func TestMessageService_UploadFile(t *testing.T) {
 		mockFileRepository.AssertExpectations(t)
 	})
 }
+
+func TestMessageService_UploadFile_Metadata(t *testing.T) {
+	channelId := fixture.RandID()
+	directory := fmt.Sprintf("channels/%s", channelId)
+	content := fixture.NewExifJpeg(4, 2, 6)
+
+	t.Run("Strips the metadata of images", func(t *testing.T) {
+		header := newFileHeader(t, "photo.jpg", content)
+		header.Header.Set("Content-Type", "image/jpeg")
+
+		var stored []byte
+		mockFileRepository := new(mocks.FileRepository)
+		mockFileRepository.
+			On("UploadFile", mock.Anything, directory, mock.AnythingOfType("string"), "image/jpeg").
+			Run(func(args mock.Arguments) {
+				stored, _ = io.ReadAll(args.Get(0).(io.Reader))
+			}).
+			Return("https://imageurl.com/photo.jpg", nil)
+		mockFileRepository.
+			On("UploadThumbnail", mock.Anything, directory, mock.AnythingOfType("string")).
+			Return("https://imageurl.com/thumbnail", nil)
+
+		ms := NewMessageService(&MSConfig{
+			FileRepository: mockFileRepository,
+		})
+
+		attachment, err := ms.UploadFile(header, channelId)
+		assert.NoError(t, err)
+
+		assert.False(t, bytes.Contains(stored, []byte(fixture.ExifGPSMarker)))
+		assert.Equal(t, 2, *attachment.Width)
+		assert.Equal(t, 4, *attachment.Height)
+		assert.Equal(t, int64(len(stored)), attachment.Size)
+
+		mockFileRepository.AssertExpectations(t)
+	})
+
+	t.Run("Keeps the originals of configured types", func(t *testing.T) {
+		header := newFileHeader(t, "photo.jpg", content)
+		header.Header.Set("Content-Type", "image/jpeg")
+
+		var stored []byte
+		mockFileRepository := new(mocks.FileRepository)
+		mockFileRepository.
+			On("UploadFile", mock.Anything, directory, mock.AnythingOfType("string"), "image/jpeg").
+			Run(func(args mock.Arguments) {
+				stored, _ = io.ReadAll(args.Get(0).(io.Reader))
+			}).
+			Return("https://imageurl.com/photo.jpg", nil)
+		mockFileRepository.
+			On("UploadThumbnail", mock.Anything, directory, mock.AnythingOfType("string")).
+			Return("https://imageurl.com/thumbnail", nil)
+
+		ms := NewMessageService(&MSConfig{
+			FileRepository: mockFileRepository,
+			KeepOriginals:  []string{"image/jpeg"},
+		})
+
+		attachment, err := ms.UploadFile(header, channelId)
+		assert.NoError(t, err)
+
+		assert.Equal(t, content, stored)
+		assert.Equal(t, int64(len(content)), attachment.Size)
+
+		mockFileRepository.AssertExpectations(t)
+	})
+
+	t.Run("Undecodable image", func(t *testing.T) {
+		header := newFileHeader(t, "photo.jpg", []byte("not an image"))
+		header.Header.Set("Content-Type", "image/jpeg")
+
+		mockFileRepository := new(mocks.FileRepository)
+
+		ms := NewMessageService(&MSConfig{
+			FileRepository: mockFileRepository,
+		})
+
+		attachment, err := ms.UploadFile(header, channelId)
+
+		assert.Nil(t, attachment)
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.InvalidImageError), err)
+
+		mockFileRepository.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestMessageService_UploadFile_Thumbnail(t *testing.T) {
+	channelId := fixture.RandID()
+	directory := fmt.Sprintf("channels/%s", channelId)
+
+	buf := new(bytes.Buffer)
+	err := png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 800, 600)))
+	assert.NoError(t, err)
+
+	header := newFileHeader(t, "image.png", buf.Bytes())
+	header.Header.Set("Content-Type", "image/png")
+
+	fileUrl := "https://imageurl.com/jdfkj34kljl"
+	thumbnailUrl := "https://imageurl.com/thumbnail"
+
+	// The stored file is the re-encoded image
+	var stored []byte
+	mockFileRepository := new(mocks.FileRepository)
+	mockFileRepository.
+		On("UploadFile", mock.Anything, directory, mock.AnythingOfType("string"), "image/png").
+		Run(func(args mock.Arguments) {
+			stored, _ = io.ReadAll(args.Get(0).(io.Reader))
+		}).
+		Return(fileUrl, nil)
+	mockFileRepository.
+		On("UploadThumbnail", mock.Anything, directory, mock.MatchedBy(func(filename string) bool {
+			return strings.HasPrefix(filename, "thumbnail-") && strings.HasSuffix(filename, "-image.jpeg")
+		})).
+		Return(thumbnailUrl, nil)
+
+	ms := NewMessageService(&MSConfig{
+		FileRepository: mockFileRepository,
+	})
+
+	attachment, err := ms.UploadFile(header, channelId)
+	assert.NoError(t, err)
+
+	assert.Equal(t, fileUrl, attachment.Url)
+	assert.Equal(t, thumbnailUrl, *attachment.ThumbnailUrl)
+	assert.Equal(t, 800, *attachment.Width)
+	assert.Equal(t, 600, *attachment.Height)
+	hash := sha256.Sum256(stored)
+	assert.Equal(t, int64(len(stored)), attachment.Size)
+	assert.Equal(t, hex.EncodeToString(hash[:]), attachment.Hash)
+
+	mockFileRepository.AssertExpectations(t)
+}
+
+func TestMessageService_CreateUpload(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		upload := &model.Upload{
+			UserId:    fixture.RandID(),
+			ChannelId: fixture.RandID(),
+			Filename:  "My-Image.png",
+			FileType:  "image/png",
+			Size:      1024,
+		}
+
+		uploadUrl := "https://imageurl.com/upload"
+		fileUrl := "https://imageurl.com/file"
+
+		mockMessageRepository := new(mocks.MessageRepository)
+		mockFileRepository := new(mocks.FileRepository)
+		ms := NewMessageService(&MSConfig{
+			MessageRepository: mockMessageRepository,
+			FileRepository:    mockFileRepository,
+		})
+
+		// Files get uploaded to a staging directory
+		mockFileRepository.
+			On("PresignUpload", mock.MatchedBy(func(directory string) bool {
+				return directory == fmt.Sprintf("uploads/%s", upload.ID)
+			}), mock.MatchedBy(func(filename string) bool {
+				return strings.HasSuffix(filename, "-my-image.png")
+			}), "image/png", int64(1024)).
+			Return(uploadUrl, fileUrl, nil)
+		mockMessageRepository.On("CreateUpload", upload).Return(nil)
+
+		result, err := ms.CreateUpload(upload)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uploadUrl, result)
+		assert.Equal(t, fileUrl, upload.Url)
+		assert.NotEmpty(t, upload.ID)
+		assert.WithinDuration(t, time.Now().Add(model.UploadExpiration), upload.ExpiresAt, time.Minute)
+
+		mockMessageRepository.AssertExpectations(t)
+		mockFileRepository.AssertExpectations(t)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		upload := &model.Upload{ChannelId: fixture.RandID(), Filename: "file.mp3", FileType: "audio/mp3", Size: 10}
+
+		mockMessageRepository := new(mocks.MessageRepository)
+		mockFileRepository := new(mocks.FileRepository)
+		ms := NewMessageService(&MSConfig{
+			MessageRepository: mockMessageRepository,
+			FileRepository:    mockFileRepository,
+		})
+
+		mockError := apperrors.NewInternal()
+		mockFileRepository.
+			On("PresignUpload", mock.AnythingOfType("string"), mock.AnythingOfType("string"), "audio/mp3", int64(10)).
+			Return("", "", mockError)
+
+		_, err := ms.CreateUpload(upload)
+
+		assert.Error(t, err)
+		assert.Equal(t, mockError, err)
+
+		mockMessageRepository.AssertNotCalled(t, "CreateUpload", mock.Anything)
+		mockFileRepository.AssertExpectations(t)
+	})
+}
+
+func TestMessageService_FinalizeUpload(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 800, 600)))
+	assert.NoError(t, err)
+	content := buf.Bytes()
+
+	t.Run("Success with thumbnail", func(t *testing.T) {
+		upload := &model.Upload{
+			ID:        fixture.RandID(),
+			ChannelId: fixture.RandID(),
+			Filename:  "abcde-image.png",
+			FileType:  "image/png",
+			Size:      int64(len(content)),
+			Url:       "https://imageurl.com/staged",
+		}
+		stagedUrl := upload.Url
+		fileUrl := "https://imageurl.com/file"
+		thumbnailUrl := "https://imageurl.com/thumbnail"
+
+		mockMessageRepository := new(mocks.MessageRepository)
+		mockFileRepository := new(mocks.FileRepository)
+		ms := NewMessageService(&MSConfig{
+			MessageRepository: mockMessageRepository,
+			FileRepository:    mockFileRepository,
+		})
+
+		mockFileRepository.
+			On("OpenFile", stagedUrl).
+			Return(io.NopCloser(bytes.NewReader(content)), int64(len(content)), nil)
+		// The re-encoded image gets stored in the channel instead of the staged file
+		mockFileRepository.
+			On("UploadFile", mock.Anything, fmt.Sprintf("channels/%s", upload.ChannelId), upload.Filename, upload.FileType).
+			Return(fileUrl, nil)
+		mockMessageRepository.On("SetUploadUrl", upload.ID, fileUrl).Return(nil)
+		mockFileRepository.On("DeleteImage", stagedUrl).Return(nil)
+		mockFileRepository.
+			On("UploadThumbnail", mock.Anything, fmt.Sprintf("channels/%s", upload.ChannelId), "thumbnail-abcde-image.jpeg").
+			Return(thumbnailUrl, nil)
+
+		attachment, err := ms.FinalizeUpload(upload)
+
+		assert.NoError(t, err)
+		assert.Equal(t, upload.ID, attachment.ID)
+		assert.Equal(t, fileUrl, attachment.Url)
+		assert.Equal(t, fileUrl, upload.Url)
+		assert.Equal(t, upload.Filename, attachment.Filename)
+		assert.Equal(t, thumbnailUrl, *attachment.ThumbnailUrl)
+		assert.Equal(t, 800, *attachment.Width)
+		assert.Positive(t, attachment.Size)
+		assert.Len(t, attachment.Hash, 64)
+
+		mockMessageRepository.AssertExpectations(t)
+		mockFileRepository.AssertExpectations(t)
+	})
+
+	t.Run("Files that are not re-encoded get copied as well", func(t *testing.T) {
+		text := []byte("Hello World")
+		upload := &model.Upload{
+			ID:        fixture.RandID(),
+			ChannelId: fixture.RandID(),
+			Filename:  "abcde-notes.txt",
+			FileType:  "text/plain",
+			Size:      int64(len(text)),
+			Url:       "https://imageurl.com/staged",
+		}
+		stagedUrl := upload.Url
+		fileUrl := "https://imageurl.com/file"
+
+		mockMessageRepository := new(mocks.MessageRepository)
+		mockFileRepository := new(mocks.FileRepository)
+		ms := NewMessageService(&MSConfig{
+			MessageRepository: mockMessageRepository,
+			FileRepository:    mockFileRepository,
+		})
+
+		var stored []byte
+		mockFileRepository.
+			On("OpenFile", stagedUrl).
+			Return(io.NopCloser(bytes.NewReader(text)), int64(len(text)), nil)
+		mockFileRepository.
+			On("UploadFile", mock.Anything, fmt.Sprintf("channels/%s", upload.ChannelId), upload.Filename, upload.FileType).
+			Run(func(args mock.Arguments) { stored, _ = io.ReadAll(args.Get(0).(io.Reader)) }).
+			Return(fileUrl, nil)
+		mockMessageRepository.On("SetUploadUrl", upload.ID, fileUrl).Return(nil)
+		mockFileRepository.On("DeleteImage", stagedUrl).Return(nil)
+
+		attachment, err := ms.FinalizeUpload(upload)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fileUrl, attachment.Url)
+		assert.Equal(t, text, stored)
+		assert.Equal(t, int64(len(text)), attachment.Size)
+
+		mockMessageRepository.AssertExpectations(t)
+		mockFileRepository.AssertExpectations(t)
+	})
+
+	t.Run("Size does not match", func(t *testing.T) {
+		upload := &model.Upload{
+			ID:       fixture.RandID(),
+			FileType: "image/png",
+			Size:     10,
+			Url:      "https://imageurl.com/file",
+		}
+
+		mockMessageRepository := new(mocks.MessageRepository)
+		mockFileRepository := new(mocks.FileRepository)
+		ms := NewMessageService(&MSConfig{
+			MessageRepository: mockMessageRepository,
+			FileRepository:    mockFileRepository,
+		})
+
+		mockFileRepository.
+			On("OpenFile", upload.Url).
+			Return(io.NopCloser(bytes.NewReader(content)), int64(len(content)), nil)
+		mockFileRepository.On("DeleteImage", upload.Url).Return(nil)
+		mockMessageRepository.On("DeleteUploads", []string{upload.ID}).Return(nil)
+
+		attachment, err := ms.FinalizeUpload(upload)
+
+		assert.Nil(t, attachment)
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.UploadMismatchError), err)
+
+		mockMessageRepository.AssertExpectations(t)
+		mockFileRepository.AssertExpectations(t)
+	})
+
+	t.Run("Content does not match the type", func(t *testing.T) {
+		upload := &model.Upload{
+			ID:       fixture.RandID(),
+			FileType: "application/pdf",
+			Size:     int64(len(content)),
+			Url:      "https://imageurl.com/file",
+		}
+
+		mockMessageRepository := new(mocks.MessageRepository)
+		mockFileRepository := new(mocks.FileRepository)
+		ms := NewMessageService(&MSConfig{
+			MessageRepository: mockMessageRepository,
+			FileRepository:    mockFileRepository,
+		})
+
+		mockFileRepository.
+			On("OpenFile", upload.Url).
+			Return(io.NopCloser(bytes.NewReader(content)), int64(len(content)), nil)
+		mockFileRepository.On("DeleteImage", upload.Url).Return(nil)
+		mockMessageRepository.On("DeleteUploads", []string{upload.ID}).Return(nil)
+
+		attachment, err := ms.FinalizeUpload(upload)
+
+		assert.Nil(t, attachment)
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.FileTypeMismatchError), err)
+
+		mockMessageRepository.AssertExpectations(t)
+		mockFileRepository.AssertExpectations(t)
+		mockFileRepository.AssertNotCalled(t, "UploadThumbnail", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Html declared as text", func(t *testing.T) {
+		html := []byte("<html><body><script>alert(1)</script></body></html>")
+		upload := &model.Upload{
+			ID:       fixture.RandID(),
+			FileType: "text/plain",
+			Size:     int64(len(html)),
+			Url:      "https://imageurl.com/file",
+		}
+
+		mockMessageRepository := new(mocks.MessageRepository)
+		mockFileRepository := new(mocks.FileRepository)
+		ms := NewMessageService(&MSConfig{
+			MessageRepository: mockMessageRepository,
+			FileRepository:    mockFileRepository,
+		})
+
+		mockFileRepository.
+			On("OpenFile", upload.Url).
+			Return(io.NopCloser(bytes.NewReader(html)), int64(len(html)), nil)
+		mockFileRepository.On("DeleteImage", upload.Url).Return(nil)
+		mockMessageRepository.On("DeleteUploads", []string{upload.ID}).Return(nil)
+
+		attachment, err := ms.FinalizeUpload(upload)
+
+		assert.Nil(t, attachment)
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.FileTypeMismatchError), err)
+
+		mockMessageRepository.AssertExpectations(t)
+		mockFileRepository.AssertExpectations(t)
+		mockFileRepository.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Incomplete resumable upload", func(t *testing.T) {
+		upload := &model.Upload{ID: fixture.RandID(), Size: 10, Offset: 5, Resumable: true}
+
+		mockFileRepository := new(mocks.FileRepository)
+		ms := NewMessageService(&MSConfig{
+			FileRepository: mockFileRepository,
+		})
+
+		attachment, err := ms.FinalizeUpload(upload)
+
+		assert.Nil(t, attachment)
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.UploadIncompleteError), err)
+
+		mockFileRepository.AssertNotCalled(t, "OpenFile", mock.Anything)
+	})
+
+	t.Run("File was not uploaded", func(t *testing.T) {
+		upload := &model.Upload{ID: fixture.RandID(), Url: "https://imageurl.com/file"}
+
+		mockFileRepository := new(mocks.FileRepository)
+		ms := NewMessageService(&MSConfig{
+			FileRepository: mockFileRepository,
+		})
+
+		mockError := apperrors.NewNotFound("file", upload.Url)
+		mockFileRepository.On("OpenFile", upload.Url).Return(nil, int64(0), mockError)
+
+		attachment, err := ms.FinalizeUpload(upload)
+
+		assert.Nil(t, attachment)
+		assert.Equal(t, mockError, err)
+
+		mockFileRepository.AssertExpectations(t)
+	})
+}
+
+func TestMessageService_CreateResumableUpload(t *testing.T) {
+	upload := &model.Upload{
+		UserId:    fixture.RandID(),
+		ChannelId: fixture.RandID(),
+		Filename:  "audio.mp3",
+		FileType:  "audio/mp3",
+		Size:      1024,
+	}
+
+	mockMessageRepository := new(mocks.MessageRepository)
+	ms := NewMessageService(&MSConfig{
+		MessageRepository: mockMessageRepository,
+	})
+
+	mockMessageRepository.On("CreateUpload", upload).Return(nil)
+
+	err := ms.CreateResumableUpload(upload)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, upload.ID)
+	assert.True(t, upload.Resumable)
+	assert.Empty(t, upload.Url)
+	assert.True(t, strings.HasSuffix(upload.Filename, "-audio.mp3"))
+	assert.False(t, upload.IsComplete())
+
+	mockMessageRepository.AssertExpectations(t)
+}
+
+func TestMessageService_AppendUploadChunk(t *testing.T) {
+	t.Run("Partial chunk", func(t *testing.T) {
+		upload := &model.Upload{ID: fixture.RandID(), Size: 10, Offset: 2, Resumable: true}
+		chunkUrl := "https://imageurl.com/chunk"
+
+		mockMessageRepository := new(mocks.MessageRepository)
+		mockFileRepository := new(mocks.FileRepository)
+		ms := NewMessageService(&MSConfig{
+			MessageRepository: mockMessageRepository,
+			FileRepository:    mockFileRepository,
+		})
+
+		mockFileRepository.
+			On("UploadChunk", fmt.Sprintf("uploads/%s", upload.ID), mock.AnythingOfType("string"), mock.Anything).
+			Return(chunkUrl, int64(5), nil)
+		mockMessageRepository.
+			On("AddUploadChunk", upload, &model.UploadChunk{UploadId: upload.ID, Offset: 2, Size: 5, Url: chunkUrl}).
+			Return(nil)
+
+		err := ms.AppendUploadChunk(upload, strings.NewReader("Hello"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), upload.Offset)
+		assert.Len(t, upload.Chunks, 1)
+		assert.WithinDuration(t, time.Now().Add(model.UploadExpiration), upload.ExpiresAt, time.Minute)
+
+		mockMessageRepository.AssertExpectations(t)
+		mockFileRepository.AssertExpectations(t)
+		mockFileRepository.AssertNotCalled(t, "MergeChunks", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Last chunk merges the file", func(t *testing.T) {
+		upload := &model.Upload{
+			ID:        fixture.RandID(),
+			ChannelId: fixture.RandID(),
+			Filename:  "abcde-audio.mp3",
+			FileType:  "audio/mp3",
+			Size:      10,
+			Offset:    5,
+			Resumable: true,
+			Chunks: []model.UploadChunk{
+				{Offset: 0, Size: 5, Url: "https://imageurl.com/first"},
+			},
+		}
+		fileUrl := "https://imageurl.com/abcde-audio.mp3"
+
+		mockMessageRepository := new(mocks.MessageRepository)
+		mockFileRepository := new(mocks.FileRepository)
+		ms := NewMessageService(&MSConfig{
+			MessageRepository: mockMessageRepository,
+			FileRepository:    mockFileRepository,
+		})
+
+		mockFileRepository.
+			On("UploadChunk", fmt.Sprintf("uploads/%s", upload.ID), mock.AnythingOfType("string"), mock.Anything).
+			Return("https://imageurl.com/second", int64(5), nil)
+		mockMessageRepository.On("AddUploadChunk", upload, mock.AnythingOfType("*model.UploadChunk")).Return(nil)
+		mockFileRepository.
+			On("MergeChunks", []string{"https://imageurl.com/first", "https://imageurl.com/second"},
+				fmt.Sprintf("uploads/%s", upload.ID), upload.Filename, upload.FileType).
+			Return(fileUrl, nil)
+		mockMessageRepository.On("SetUploadUrl", upload.ID, fileUrl).Return(nil)
+		mockFileRepository.On("DeleteImage", "https://imageurl.com/first").Return(nil)
+		mockFileRepository.On("DeleteImage", "https://imageurl.com/second").Return(nil)
+
+		err := ms.AppendUploadChunk(upload, strings.NewReader("World"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, fileUrl, upload.Url)
+		assert.True(t, upload.IsComplete())
+
+		mockMessageRepository.AssertExpectations(t)
+		mockFileRepository.AssertExpectations(t)
+	})
+
+	t.Run("Chunk exceeds the upload length", func(t *testing.T) {
+		upload := &model.Upload{ID: fixture.RandID(), Size: 10, Offset: 8, Resumable: true}
+		chunkUrl := "https://imageurl.com/chunk"
+
+		mockMessageRepository := new(mocks.MessageRepository)
+		mockFileRepository := new(mocks.FileRepository)
+		ms := NewMessageService(&MSConfig{
+			MessageRepository: mockMessageRepository,
+			FileRepository:    mockFileRepository,
+		})
+
+		mockFileRepository.
+			On("UploadChunk", fmt.Sprintf("uploads/%s", upload.ID), mock.AnythingOfType("string"), mock.Anything).
+			Return(chunkUrl, int64(3), nil)
+		mockFileRepository.On("DeleteImage", chunkUrl).Return(nil)
+
+		err := ms.AppendUploadChunk(upload, strings.NewReader("Hello"))
+
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.UploadMismatchError), err)
+		assert.Equal(t, int64(8), upload.Offset)
+
+		mockMessageRepository.AssertNotCalled(t, "AddUploadChunk", mock.Anything, mock.Anything)
+		mockFileRepository.AssertExpectations(t)
+	})
+
+	t.Run("Concurrent chunk at the same offset", func(t *testing.T) {
+		upload := &model.Upload{ID: fixture.RandID(), Size: 10, Offset: 0, Resumable: true}
+		chunkUrl := "https://imageurl.com/chunk"
+
+		mockMessageRepository := new(mocks.MessageRepository)
+		mockFileRepository := new(mocks.FileRepository)
+		ms := NewMessageService(&MSConfig{
+			MessageRepository: mockMessageRepository,
+			FileRepository:    mockFileRepository,
+		})
+
+		mockError := apperrors.NewConflict("offset", "0")
+		mockFileRepository.
+			On("UploadChunk", fmt.Sprintf("uploads/%s", upload.ID), mock.AnythingOfType("string"), mock.Anything).
+			Return(chunkUrl, int64(5), nil)
+		mockMessageRepository.On("AddUploadChunk", upload, mock.AnythingOfType("*model.UploadChunk")).Return(mockError)
+		mockFileRepository.On("DeleteImage", chunkUrl).Return(nil)
+
+		err := ms.AppendUploadChunk(upload, strings.NewReader("Hello"))
+
+		assert.Equal(t, mockError, err)
+		assert.Equal(t, int64(0), upload.Offset)
+		assert.Empty(t, upload.Chunks)
+
+		mockMessageRepository.AssertExpectations(t)
+		mockFileRepository.AssertExpectations(t)
+	})
+}
+
+func TestMessageService_TerminateUpload(t *testing.T) {
+	upload := &model.Upload{
+		ID:        fixture.RandID(),
+		Resumable: true,
+		Chunks: []model.UploadChunk{
+			{Offset: 0, Size: 5, Url: "https://imageurl.com/first"},
+		},
+	}
+
+	mockMessageRepository := new(mocks.MessageRepository)
+	mockFileRepository := new(mocks.FileRepository)
+	ms := NewMessageService(&MSConfig{
+		MessageRepository: mockMessageRepository,
+		FileRepository:    mockFileRepository,
+	})
+
+	mockFileRepository.On("DeleteImage", "").Return(nil)
+	mockFileRepository.On("DeleteImage", "https://imageurl.com/first").Return(nil)
+	mockMessageRepository.On("DeleteUploads", []string{upload.ID}).Return(nil)
+
+	err := ms.TerminateUpload(upload)
+
+	assert.NoError(t, err)
+
+	mockMessageRepository.AssertExpectations(t)
+	mockFileRepository.AssertExpectations(t)
+}
+
+func TestMessageService_DeleteExpiredUploads(t *testing.T) {
+	uploads := []model.Upload{
+		{ID: fixture.RandID(), Url: "https://imageurl.com/first"},
+		{ID: fixture.RandID(), Url: "https://imageurl.com/second"},
+	}
+
+	mockMessageRepository := new(mocks.MessageRepository)
+	mockFileRepository := new(mocks.FileRepository)
+	ms := NewMessageService(&MSConfig{
+		MessageRepository: mockMessageRepository,
+		FileRepository:    mockFileRepository,
+	})
+
+	mockMessageRepository.On("GetExpiredUploads").Return(&uploads, nil)
+	mockFileRepository.On("DeleteImage", uploads[0].Url).Return(nil)
+	mockFileRepository.On("DeleteImage", uploads[1].Url).Return(apperrors.NewInternal())
+	// Uploads whose file could not be deleted are kept for the next run
+	mockMessageRepository.On("DeleteUploads", []string{uploads[0].ID}).Return(nil)
+
+	err := ms.DeleteExpiredUploads()
+
+	assert.NoError(t, err)
+
+	mockMessageRepository.AssertExpectations(t)
+	mockFileRepository.AssertExpectations(t)
+}
+
+func TestMessageService_AddReaction(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		reaction := &model.Reaction{
+			MessageId: fixture.RandID(),
+			UserId:    fixture.RandID(),
+			Emoji:     "👍",
+		}
+
+		mockMessageRepository := new(mocks.MessageRepository)
+		ms := NewMessageService(&MSConfig{
+			MessageRepository: mockMessageRepository,
+		})
+
+		mockMessageRepository.On("GetReactionEmojis", reaction.MessageId).Return([]string{"🔥"}, nil)
+		mockMessageRepository.On("AddReaction", reaction).Return(nil)
+
+		err := ms.AddReaction(reaction)
+		assert.NoError(t, err)
+
+		mockMessageRepository.AssertExpectations(t)
+	})
+
+	t.Run("Reaction limit reached", func(t *testing.T) {
+		reaction := &model.Reaction{
+			MessageId: fixture.RandID(),
+			UserId:    fixture.RandID(),
+			Emoji:     "👍",
+		}
+
+		emojis := make([]string, model.MaximumReactions)
+		for i := range emojis {
+			emojis[i] = fixture.RandStringRunes(2)
+		}
+
+		mockMessageRepository := new(mocks.MessageRepository)
+		ms := NewMessageService(&MSConfig{
+			MessageRepository: mockMessageRepository,
+		})
+
+		mockMessageRepository.On("GetReactionEmojis", reaction.MessageId).Return(emojis, nil)
+
+		err := ms.AddReaction(reaction)
+
+		mockErr := apperrors.NewBadRequest(apperrors.ReactionLimitError)
+		assert.EqualError(t, err, mockErr.Error())
+		mockMessageRepository.AssertNotCalled(t, "AddReaction", mock.Anything)
+	})
+
+	t.Run("Existing emoji ignores the limit", func(t *testing.T) {
+		reaction := &model.Reaction{
+			MessageId: fixture.RandID(),
+			UserId:    fixture.RandID(),
+			Emoji:     "👍",
+		}
+
+		emojis := make([]string, model.MaximumReactions)
+		for i := range emojis {
+			emojis[i] = fixture.RandStringRunes(2)
+		}
+		emojis[0] = reaction.Emoji
+
+		mockMessageRepository := new(mocks.MessageRepository)
+		ms := NewMessageService(&MSConfig{
+			MessageRepository: mockMessageRepository,
+		})
+
+		mockMessageRepository.On("GetReactionEmojis", reaction.MessageId).Return(emojis, nil)
+		mockMessageRepository.On("AddReaction", reaction).Return(nil)
+
+		err := ms.AddReaction(reaction)
+		assert.NoError(t, err)
+
+		mockMessageRepository.AssertExpectations(t)
+	})
+}
+
+func TestMessageService_PinMessage(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockMessage := fixture.GetMockMessage("", fixture.RandID())
+
+		mockMessageRepository := new(mocks.MessageRepository)
+		ms := NewMessageService(&MSConfig{
+			MessageRepository: mockMessageRepository,
+		})
+
+		mockMessageRepository.
+			On("PinMessage", mockMessage, mock.AnythingOfType("time.Time"), model.MaximumPins).
+			Return(nil)
+
+		err := ms.PinMessage(mockMessage)
+		assert.NoError(t, err)
+		assert.NotNil(t, mockMessage.PinnedAt)
+
+		mockMessageRepository.AssertExpectations(t)
+	})
+
+	t.Run("Pin limit reached", func(t *testing.T) {
+		mockMessage := fixture.GetMockMessage("", fixture.RandID())
+
+		mockMessageRepository := new(mocks.MessageRepository)
+		ms := NewMessageService(&MSConfig{
+			MessageRepository: mockMessageRepository,
+		})
+
+		mockErr := apperrors.NewBadRequest(apperrors.PinLimitError)
+		mockMessageRepository.
+			On("PinMessage", mockMessage, mock.AnythingOfType("time.Time"), model.MaximumPins).
+			Return(mockErr)
+
+		err := ms.PinMessage(mockMessage)
+
+		assert.EqualError(t, err, mockErr.Error())
+		assert.Nil(t, mockMessage.PinnedAt)
+	})
+}
+
+func TestFormatName(t *testing.T) {
+	testCases := []struct {
+		filename string
+		suffix   string
+	}{
+		{filename: "My Image.PNG", suffix: "-my-image.png"},
+		{filename: "x/../../../../../../tmp/pwned.txt", suffix: "-pwned.txt"},
+		{filename: `..\..\windows\evil.exe`, suffix: "-evil.exe"},
+		{filename: "../..", suffix: "--"},
+		{filename: "archive.tar/gz", suffix: "-gz"},
+		{filename: "notes.t/x.t", suffix: "-x.t"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.filename, func(t *testing.T) {
+			name := formatName(tc.filename)
+
+			assert.True(t, strings.HasSuffix(name, tc.suffix), name)
+			assert.Len(t, name, 5+len(tc.suffix))
+			assert.NotContains(t, name, "/")
+			assert.NotContains(t, name, `\`)
+			assert.NotContains(t, name, "..")
+		})
+	}
+}
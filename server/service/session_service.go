@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"github.com/sentrionic/valkyrie/model"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sessionTouchInterval limits how often the last use of a session gets written
+const sessionTouchInterval = time.Minute
+
+// sessionService acts as a struct for injecting an implementation of RedisRepository
+// for use in service methods
+type sessionService struct {
+	RedisRepository model.RedisRepository
+}
+
+// SeSConfig will hold repositories that will eventually be injected into
+// this service layer
+type SeSConfig struct {
+	RedisRepository model.RedisRepository
+}
+
+// NewSessionService is a factory function for
+// initializing a SessionService with its repository layer dependencies
+func NewSessionService(c *SeSConfig) model.SessionService {
+	return &sessionService{
+		RedisRepository: c.RedisRepository,
+	}
+}
+
+func (s *sessionService) CreateSession(ctx context.Context, userId string, userAgent string, ip string) (*model.Session, error) {
+	now := time.Now()
+
+	session := &model.Session{
+		Id:         GenerateId(),
+		Device:     describeDevice(userAgent),
+		UserAgent:  userAgent,
+		Ip:         ip,
+		CreatedAt:  now,
+		LastUsedAt: now,
+	}
+
+	if err := s.RedisRepository.SaveSession(ctx, userId, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// TouchSession updates the last use of the session and returns a NotFound error if it got revoked.
+// To avoid a write on every request the session only gets saved once per sessionTouchInterval
+// or when the device changed.
+func (s *sessionService) TouchSession(ctx context.Context, userId string, id string, userAgent string, ip string) error {
+	session, err := s.RedisRepository.GetSession(ctx, userId, id)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	if now.Sub(session.LastUsedAt) < sessionTouchInterval && session.UserAgent == userAgent && session.Ip == ip {
+		return nil
+	}
+
+	session.LastUsedAt = now
+	session.UserAgent = userAgent
+	session.Device = describeDevice(userAgent)
+	session.Ip = ip
+
+	return s.RedisRepository.SaveSession(ctx, userId, session)
+}
+
+// GetSessions returns the sessions of the user that did not expire, most recently used first
+func (s *sessionService) GetSessions(ctx context.Context, userId string) ([]model.Session, error) {
+	sessions, err := s.RedisRepository.GetSessions(ctx, userId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]model.Session, 0, len(sessions))
+	expired := make([]string, 0)
+	deadline := time.Now().Add(-model.SessionMaxAge * time.Second)
+
+	for _, session := range sessions {
+		if session.LastUsedAt.Before(deadline) {
+			expired = append(expired, session.Id)
+			continue
+		}
+		active = append(active, session)
+	}
+
+	// The cookies of these sessions expired already
+	if err = s.RedisRepository.DeleteSessions(ctx, userId, expired...); err != nil {
+		log.Printf("Failed to delete expired sessions: %v\n", err.Error())
+	}
+
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].LastUsedAt.After(active[j].LastUsedAt)
+	})
+
+	return active, nil
+}
+
+func (s *sessionService) RevokeSession(ctx context.Context, userId string, id string) error {
+	if _, err := s.RedisRepository.GetSession(ctx, userId, id); err != nil {
+		return err
+	}
+
+	return s.RedisRepository.DeleteSessions(ctx, userId, id)
+}
+
+// RevokeOtherSessions revokes all sessions of the user except the current one and returns their ids.
+// An empty currentId revokes every session.
+func (s *sessionService) RevokeOtherSessions(ctx context.Context, userId string, currentId string) ([]string, error) {
+	sessions, err := s.RedisRepository.GetSessions(ctx, userId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(sessions))
+
+	for _, session := range sessions {
+		if session.Id != currentId {
+			ids = append(ids, session.Id)
+		}
+	}
+
+	if err = s.RedisRepository.DeleteSessions(ctx, userId, ids...); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// describeDevice returns a readable name like "Firefox on Windows" for the user agent
+func describeDevice(userAgent string) string {
+	browsers := []struct{ token, name string }{
+		{"Electron/", "Desktop App"},
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+	}
+
+	systems := []struct{ token, name string }{
+		{"Windows", "Windows"},
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	}
+
+	browser := ""
+	for _, b := range browsers {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+
+	system := ""
+	for _, os := range systems {
+		if strings.Contains(userAgent, os.token) {
+			system = os.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " on " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	default:
+		return "Unknown device"
+	}
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+const firefoxUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:109.0) Gecko/20100101 Firefox/115.0"
+
+func TestSessionService_CreateSession(t *testing.T) {
+	userId := fixture.RandID()
+
+	mockRedisRepository := new(mocks.RedisRepository)
+	ss := NewSessionService(&SeSConfig{
+		RedisRepository: mockRedisRepository,
+	})
+
+	mockRedisRepository.On("SaveSession", mock.Anything, userId, mock.AnythingOfType("*model.Session")).Return(nil)
+
+	session, err := ss.CreateSession(context.TODO(), userId, firefoxUserAgent, "127.0.0.1")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, session.Id)
+	assert.Equal(t, "Firefox on Windows", session.Device)
+	assert.Equal(t, "127.0.0.1", session.Ip)
+
+	mockRedisRepository.AssertExpectations(t)
+}
+
+func TestSessionService_TouchSession(t *testing.T) {
+	userId := fixture.RandID()
+
+	t.Run("Recently used", func(t *testing.T) {
+		session := &model.Session{Id: "session", UserAgent: firefoxUserAgent, Ip: "127.0.0.1", LastUsedAt: time.Now()}
+
+		mockRedisRepository := new(mocks.RedisRepository)
+		ss := NewSessionService(&SeSConfig{
+			RedisRepository: mockRedisRepository,
+		})
+
+		mockRedisRepository.On("GetSession", mock.Anything, userId, session.Id).Return(session, nil)
+
+		err := ss.TouchSession(context.TODO(), userId, session.Id, firefoxUserAgent, "127.0.0.1")
+		assert.NoError(t, err)
+
+		mockRedisRepository.AssertNotCalled(t, "SaveSession", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Updates the last use", func(t *testing.T) {
+		lastUsed := time.Now().Add(-time.Hour)
+		session := &model.Session{Id: "session", UserAgent: firefoxUserAgent, Ip: "127.0.0.1", LastUsedAt: lastUsed}
+
+		mockRedisRepository := new(mocks.RedisRepository)
+		ss := NewSessionService(&SeSConfig{
+			RedisRepository: mockRedisRepository,
+		})
+
+		mockRedisRepository.On("GetSession", mock.Anything, userId, session.Id).Return(session, nil)
+		mockRedisRepository.On("SaveSession", mock.Anything, userId, session).Return(nil)
+
+		err := ss.TouchSession(context.TODO(), userId, session.Id, firefoxUserAgent, "10.0.0.1")
+		assert.NoError(t, err)
+		assert.True(t, session.LastUsedAt.After(lastUsed))
+		assert.Equal(t, "10.0.0.1", session.Ip)
+
+		mockRedisRepository.AssertExpectations(t)
+	})
+
+	t.Run("Revoked session", func(t *testing.T) {
+		mockRedisRepository := new(mocks.RedisRepository)
+		ss := NewSessionService(&SeSConfig{
+			RedisRepository: mockRedisRepository,
+		})
+
+		mockError := apperrors.NewNotFound("session", "session")
+		mockRedisRepository.On("GetSession", mock.Anything, userId, "session").Return(nil, mockError)
+
+		err := ss.TouchSession(context.TODO(), userId, "session", firefoxUserAgent, "127.0.0.1")
+		assert.Equal(t, mockError, err)
+	})
+}
+
+func TestSessionService_GetSessions(t *testing.T) {
+	userId := fixture.RandID()
+	now := time.Now()
+
+	older := model.Session{Id: "older", LastUsedAt: now.Add(-time.Hour)}
+	newer := model.Session{Id: "newer", LastUsedAt: now}
+	expired := model.Session{Id: "expired", LastUsedAt: now.Add(-8 * 24 * time.Hour)}
+
+	mockRedisRepository := new(mocks.RedisRepository)
+	ss := NewSessionService(&SeSConfig{
+		RedisRepository: mockRedisRepository,
+	})
+
+	mockRedisRepository.On("GetSessions", mock.Anything, userId).Return([]model.Session{older, expired, newer}, nil)
+	mockRedisRepository.On("DeleteSessions", mock.Anything, userId, "expired").Return(nil)
+
+	sessions, err := ss.GetSessions(context.TODO(), userId)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Session{newer, older}, sessions)
+
+	mockRedisRepository.AssertExpectations(t)
+}
+
+func TestSessionService_RevokeSession(t *testing.T) {
+	userId := fixture.RandID()
+
+	t.Run("Success", func(t *testing.T) {
+		mockRedisRepository := new(mocks.RedisRepository)
+		ss := NewSessionService(&SeSConfig{
+			RedisRepository: mockRedisRepository,
+		})
+
+		mockRedisRepository.On("GetSession", mock.Anything, userId, "session").Return(&model.Session{Id: "session"}, nil)
+		mockRedisRepository.On("DeleteSessions", mock.Anything, userId, "session").Return(nil)
+
+		err := ss.RevokeSession(context.TODO(), userId, "session")
+		assert.NoError(t, err)
+
+		mockRedisRepository.AssertExpectations(t)
+	})
+
+	t.Run("Unknown session", func(t *testing.T) {
+		mockRedisRepository := new(mocks.RedisRepository)
+		ss := NewSessionService(&SeSConfig{
+			RedisRepository: mockRedisRepository,
+		})
+
+		mockError := apperrors.NewNotFound("session", "session")
+		mockRedisRepository.On("GetSession", mock.Anything, userId, "session").Return(nil, mockError)
+
+		err := ss.RevokeSession(context.TODO(), userId, "session")
+		assert.Equal(t, mockError, err)
+
+		mockRedisRepository.AssertNotCalled(t, "DeleteSessions", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestSessionService_RevokeOtherSessions(t *testing.T) {
+	userId := fixture.RandID()
+	sessions := []model.Session{{Id: "current"}, {Id: "first"}, {Id: "second"}}
+
+	t.Run("Keeps the current session", func(t *testing.T) {
+		mockRedisRepository := new(mocks.RedisRepository)
+		ss := NewSessionService(&SeSConfig{
+			RedisRepository: mockRedisRepository,
+		})
+
+		mockRedisRepository.On("GetSessions", mock.Anything, userId).Return(sessions, nil)
+		mockRedisRepository.On("DeleteSessions", mock.Anything, userId, "first", "second").Return(nil)
+
+		ids, err := ss.RevokeOtherSessions(context.TODO(), userId, "current")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"first", "second"}, ids)
+
+		mockRedisRepository.AssertExpectations(t)
+	})
+
+	t.Run("Revokes every session", func(t *testing.T) {
+		mockRedisRepository := new(mocks.RedisRepository)
+		ss := NewSessionService(&SeSConfig{
+			RedisRepository: mockRedisRepository,
+		})
+
+		mockRedisRepository.On("GetSessions", mock.Anything, userId).Return(sessions, nil)
+		mockRedisRepository.On("DeleteSessions", mock.Anything, userId, "current", "first", "second").Return(nil)
+
+		ids, err := ss.RevokeOtherSessions(context.TODO(), userId, "")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"current", "first", "second"}, ids)
+
+		mockRedisRepository.AssertExpectations(t)
+	})
+}
+
+func TestDescribeDevice(t *testing.T) {
+	testCases := []struct {
+		userAgent string
+		device    string
+	}{
+		{userAgent: firefoxUserAgent, device: "Firefox on Windows"},
+		{userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.5 Safari/605.1.15", device: "Safari on macOS"},
+		{userAgent: "Mozilla/5.0 (Linux; Android 13) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/114.0.0.0 Mobile Safari/537.36", device: "Chrome on Android"},
+		{userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/114.0.0.0 Safari/537.36 Edg/114.0.1823.67", device: "Edge on Windows"},
+		{userAgent: "curl/8.1.2", device: "Unknown device"},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		assert.Equal(t, tc.device, describeDevice(tc.userAgent))
+	}
+}
@@ -70,6 +70,58 @@ func (s *socketService) EmitDeleteMessage(room, messageId string) {
 	s.Hub.BroadcastToRoom(data, room)
 }
 
+func (s *socketService) EmitAddReaction(room string, reaction *model.ReactionEvent) {
+	data, err := json.Marshal(model.WebsocketMessage{
+		Action: ws.AddReactionAction,
+		Data:   reaction,
+	})
+
+	if err != nil {
+		log.Printf("error marshalling response: %v\n", err)
+	}
+
+	s.Hub.BroadcastToRoom(data, room)
+}
+
+func (s *socketService) EmitRemoveReaction(room string, reaction *model.ReactionEvent) {
+	data, err := json.Marshal(model.WebsocketMessage{
+		Action: ws.RemoveReactionAction,
+		Data:   reaction,
+	})
+
+	if err != nil {
+		log.Printf("error marshalling response: %v\n", err)
+	}
+
+	s.Hub.BroadcastToRoom(data, room)
+}
+
+func (s *socketService) EmitPinMessage(room string, pin *model.PinEvent) {
+	data, err := json.Marshal(model.WebsocketMessage{
+		Action: ws.PinMessageAction,
+		Data:   pin,
+	})
+
+	if err != nil {
+		log.Printf("error marshalling response: %v\n", err)
+	}
+
+	s.Hub.BroadcastToRoom(data, room)
+}
+
+func (s *socketService) EmitUnpinMessage(room string, pin *model.PinEvent) {
+	data, err := json.Marshal(model.WebsocketMessage{
+		Action: ws.UnpinMessageAction,
+		Data:   pin,
+	})
+
+	if err != nil {
+		log.Printf("error marshalling response: %v\n", err)
+	}
+
+	s.Hub.BroadcastToRoom(data, room)
+}
+
 func (s *socketService) EmitNewChannel(room string, channel *model.ChannelResponse) {
 	data, err := json.Marshal(model.WebsocketMessage{
 		Action: ws.AddChannelAction,
@@ -124,6 +176,47 @@ func (s *socketService) EmitDeleteChannel(channel *model.Channel) {
 	s.Hub.BroadcastToRoom(data, *channel.GuildID)
 }
 
+func (s *socketService) EmitNewThread(thread *model.ThreadResponse) {
+	data, err := json.Marshal(model.WebsocketMessage{
+		Action: ws.ThreadCreateAction,
+		Data:   thread,
+	})
+
+	if err != nil {
+		log.Printf("error marshalling response: %v\n", err)
+	}
+
+	s.Hub.BroadcastToRoom(data, thread.ParentId)
+}
+
+func (s *socketService) EmitEditThread(thread *model.ThreadResponse) {
+	data, err := json.Marshal(model.WebsocketMessage{
+		Action: ws.ThreadUpdateAction,
+		Data:   thread,
+	})
+
+	if err != nil {
+		log.Printf("error marshalling response: %v\n", err)
+	}
+
+	s.Hub.BroadcastToRoom(data, thread.ParentId)
+	s.Hub.BroadcastToRoom(data, thread.Id)
+}
+
+func (s *socketService) EmitDeleteThread(thread *model.ThreadResponse) {
+	data, err := json.Marshal(model.WebsocketMessage{
+		Action: ws.ThreadDeleteAction,
+		Data:   thread,
+	})
+
+	if err != nil {
+		log.Printf("error marshalling response: %v\n", err)
+	}
+
+	s.Hub.BroadcastToRoom(data, thread.ParentId)
+	s.Hub.BroadcastToRoom(data, thread.Id)
+}
+
 func (s *socketService) EmitEditGuild(guild *model.Guild) {
 
 	response := guild.SerializeGuild("")
@@ -176,6 +269,20 @@ func (s *socketService) EmitRemoveFromGuild(memberId, guildId string) {
 	s.Hub.BroadcastToRoom(data, memberId)
 }
 
+// EmitAddToGuild notifies a member that got added to the guild without an invite, like a bot
+func (s *socketService) EmitAddToGuild(memberId string, guild *model.GuildResponse) {
+	data, err := json.Marshal(model.WebsocketMessage{
+		Action: ws.AddToGuildAction,
+		Data:   guild,
+	})
+
+	if err != nil {
+		log.Printf("error marshalling response: %v\n", err)
+	}
+
+	s.Hub.BroadcastToRoom(data, memberId)
+}
+
 func (s *socketService) EmitAddMember(room string, member *model.User) {
 
 	response := model.MemberResponse{
@@ -186,6 +293,7 @@ func (s *socketService) EmitAddMember(room string, member *model.User) {
 		CreatedAt: member.CreatedAt,
 		UpdatedAt: member.UpdatedAt,
 		IsFriend:  false,
+		Bot:       member.Bot,
 	}
 
 	data, err := json.Marshal(model.WebsocketMessage{
@@ -213,6 +321,77 @@ func (s *socketService) EmitRemoveMember(room, memberId string) {
 	s.Hub.BroadcastToRoom(data, room)
 }
 
+func (s *socketService) EmitAddRole(guildId string, role *model.RoleResponse) {
+	data, err := json.Marshal(model.WebsocketMessage{
+		Action: ws.AddRoleAction,
+		Data:   role,
+	})
+
+	if err != nil {
+		log.Printf("error marshalling response: %v\n", err)
+	}
+
+	s.Hub.BroadcastToRoom(data, guildId)
+}
+
+func (s *socketService) EmitEditRole(guildId string, role *model.RoleResponse) {
+	data, err := json.Marshal(model.WebsocketMessage{
+		Action: ws.EditRoleAction,
+		Data:   role,
+	})
+
+	if err != nil {
+		log.Printf("error marshalling response: %v\n", err)
+	}
+
+	s.Hub.BroadcastToRoom(data, guildId)
+}
+
+func (s *socketService) EmitDeleteRole(guildId, roleId string) {
+	data, err := json.Marshal(model.WebsocketMessage{
+		Action: ws.DeleteRoleAction,
+		Data:   roleId,
+	})
+
+	if err != nil {
+		log.Printf("error marshalling response: %v\n", err)
+	}
+
+	s.Hub.BroadcastToRoom(data, guildId)
+}
+
+func (s *socketService) EmitAddMemberRole(guildId, memberId, roleId string) {
+	data, err := json.Marshal(model.WebsocketMessage{
+		Action: ws.AddMemberRoleAction,
+		Data: map[string]string{
+			"memberId": memberId,
+			"roleId":   roleId,
+		},
+	})
+
+	if err != nil {
+		log.Printf("error marshalling response: %v\n", err)
+	}
+
+	s.Hub.BroadcastToRoom(data, guildId)
+}
+
+func (s *socketService) EmitRemoveMemberRole(guildId, memberId, roleId string) {
+	data, err := json.Marshal(model.WebsocketMessage{
+		Action: ws.RemoveMemberRoleAction,
+		Data: map[string]string{
+			"memberId": memberId,
+			"roleId":   roleId,
+		},
+	})
+
+	if err != nil {
+		log.Printf("error marshalling response: %v\n", err)
+	}
+
+	s.Hub.BroadcastToRoom(data, guildId)
+}
+
 func (s *socketService) EmitNewDMNotification(channelId string, user *model.User) {
 
 	response := model.DirectMessage{
@@ -290,6 +469,19 @@ func (s *socketService) EmitNewNotification(guildId, channelId string) {
 	s.Hub.BroadcastToRoom(notification, guildId)
 }
 
+func (s *socketService) EmitNewMention(userId string, notification *model.MentionNotification) {
+	data, err := json.Marshal(model.WebsocketMessage{
+		Action: ws.NewMentionAction,
+		Data:   notification,
+	})
+
+	if err != nil {
+		log.Printf("error marshalling response: %v\n", err)
+	}
+
+	s.Hub.BroadcastToRoom(data, userId)
+}
+
 func (s *socketService) EmitSendRequest(room string) {
 	data, err := json.Marshal(model.WebsocketMessage{
 		Action: ws.SendRequestAction,
@@ -379,3 +571,41 @@ func (s *socketService) EmitRemoveFriend(userId, memberId string) {
 
 	s.Hub.BroadcastToRoom(data, memberId)
 }
+
+// EmitInteractionCreate sends the interaction to the bot of the invoked command
+func (s *socketService) EmitInteractionCreate(botId string, interaction *model.Interaction) {
+	data, err := json.Marshal(model.WebsocketMessage{
+		Action: ws.InteractionCreateAction,
+		Data:   interaction,
+	})
+
+	if err != nil {
+		log.Printf("error marshalling response: %v\n", err)
+	}
+
+	s.Hub.BroadcastToRoom(data, botId)
+}
+
+// EmitInteractionDeferred tells the channel that the bot is working on the response of the interaction
+func (s *socketService) EmitInteractionDeferred(room string, interaction *model.Interaction) {
+	data, err := json.Marshal(model.WebsocketMessage{
+		Action: ws.InteractionDeferAction,
+		Data:   interaction,
+	})
+
+	if err != nil {
+		log.Printf("error marshalling response: %v\n", err)
+	}
+
+	s.Hub.BroadcastToRoom(data, room)
+}
+
+// DisconnectSessions closes the websocket connections of the revoked sessions
+func (s *socketService) DisconnectSessions(userId string, sessionIds []string) {
+	s.Hub.DisconnectSessions(userId, sessionIds)
+}
+
+// DisconnectAccessTokens closes the websocket connections opened with the revoked access tokens
+func (s *socketService) DisconnectAccessTokens(userId string, accessTokenIds []string) {
+	s.Hub.DisconnectAccessTokens(userId, accessTokenIds)
+}
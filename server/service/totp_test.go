@@ -0,0 +1,86 @@
+package service
+
+import (
+	"encoding/base32"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTotpCode(t *testing.T) {
+	// SHA1 test vectors of RFC 6238 Appendix B, truncated to 6 digits
+	key := []byte("12345678901234567890")
+
+	testCases := []struct {
+		time int64
+		code string
+	}{
+		{time: 59, code: "287082"},
+		{time: 1111111109, code: "081804"},
+		{time: 1111111111, code: "050471"},
+		{time: 1234567890, code: "005924"},
+		{time: 2000000000, code: "279037"},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		assert.Equal(t, tc.code, totpCode(key, tc.time/totpPeriod))
+	}
+}
+
+func TestValidateTotp(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(1111111109, 0)
+
+	t.Run("Current code", func(t *testing.T) {
+		step, ok := validateTotp(secret, "081804", now)
+		assert.True(t, ok)
+		assert.Equal(t, int64(1111111109/totpPeriod), step)
+	})
+
+	t.Run("Previous period is accepted", func(t *testing.T) {
+		_, ok := validateTotp(secret, "081804", now.Add(totpPeriod*time.Second))
+		assert.True(t, ok)
+	})
+
+	t.Run("Expired code", func(t *testing.T) {
+		_, ok := validateTotp(secret, "081804", now.Add(3*totpPeriod*time.Second))
+		assert.False(t, ok)
+	})
+
+	t.Run("Wrong code", func(t *testing.T) {
+		_, ok := validateTotp(secret, "123456", now)
+		assert.False(t, ok)
+	})
+
+	t.Run("Lowercase secret", func(t *testing.T) {
+		_, ok := validateTotp(strings.ToLower(secret), "081804", now)
+		assert.True(t, ok)
+	})
+}
+
+func TestTotpUri(t *testing.T) {
+	uri, err := url.Parse(totpUri("test@example.com", "JBSWY3DPEHPK3PXP"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Valkyrie:test@example.com", uri.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", uri.Query().Get("secret"))
+	assert.Equal(t, "Valkyrie", uri.Query().Get("issuer"))
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, hashes, err := generateRecoveryCodes()
+	assert.NoError(t, err)
+	assert.Len(t, codes, recoveryCodeCount)
+	assert.Len(t, hashes, recoveryCodeCount)
+
+	for i, code := range codes {
+		assert.Regexp(t, "^[0-9a-z]{5}-[0-9a-z]{5}$", code)
+		assert.Equal(t, hashes[i], hashRecoveryCode(code))
+		assert.Equal(t, hashes[i], hashRecoveryCode(" "+strings.ToUpper(strings.ReplaceAll(code, "-", ""))+" "))
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"log"
 	"mime/multipart"
 	"strings"
+	"time"
 )
 
 // UserService acts as a struct for injecting an implementation of UserRepository
@@ -79,8 +80,9 @@ func (s *userService) Register(user *model.User) (*model.User, error) {
 func (s *userService) Login(email, password string) (*model.User, error) {
 	user, err := s.UserRepository.FindByEmail(email)
 
-	// Will return NotAuthorized to client to omit details of why
-	if err != nil {
+	// Will return NotAuthorized to client to omit details of why.
+	// Bots do not have a password and only authenticate with their token.
+	if err != nil || user.Bot {
 		return nil, apperrors.NewAuthorization(apperrors.InvalidCredentials)
 	}
 
@@ -116,8 +118,13 @@ func (s *userService) ChangeAvatar(header *multipart.FileHeader, directory strin
 	return s.FileRepository.UploadAvatar(header, directory)
 }
 
-func (s *userService) DeleteImage(key string) error {
-	return s.FileRepository.DeleteImage(key)
+func (s *userService) DeleteImage(url string) error {
+	return s.FileRepository.DeleteImage(url)
+}
+
+// GetImageUrl returns the url of the given size of the avatar or icon at the path
+func (s *userService) GetImageUrl(path string, size int) (string, error) {
+	return s.FileRepository.ImageUrl(path, size)
 }
 
 func (s *userService) ChangePassword(currentPassword, newPassword string, user *model.User) error {
@@ -183,6 +190,193 @@ func (s *userService) ResetPassword(ctx context.Context, password string, token
 	return user, nil
 }
 
+func (s *userService) SendVerificationMail(ctx context.Context, user *model.User) error {
+	if user.IsEmailVerified() {
+		return apperrors.NewBadRequest(apperrors.AlreadyVerified)
+	}
+
+	token, err := s.RedisRepository.SetVerificationToken(ctx, user.ID, user.Email)
+
+	if err != nil {
+		return err
+	}
+
+	return s.MailRepository.SendVerificationMail(user.Email, token)
+}
+
+func (s *userService) VerifyEmail(ctx context.Context, token string) (*model.User, error) {
+	verification, err := s.RedisRepository.GetVerificationToken(ctx, token)
+
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.UserRepository.FindByID(verification.UserId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The user changed their email after the token got sent
+	if user.Email != verification.Email {
+		return nil, apperrors.NewBadRequest(apperrors.InvalidVerifyToken)
+	}
+
+	if user.IsEmailVerified() {
+		return user, nil
+	}
+
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+
+	if err = s.UserRepository.Update(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *userService) EnableTwoFactor(user *model.User) (*model.TwoFactorSetup, error) {
+	if user.TwoFactorEnabled {
+		return nil, apperrors.NewBadRequest(apperrors.TwoFactorEnabled)
+	}
+
+	secret, err := generateTotpSecret()
+
+	if err != nil {
+		log.Printf("Unable to generate a totp secret: %v\n", err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	// Stored now, but only enabled after the user confirmed a code
+	user.TwoFactorSecret = secret
+
+	if err = s.UserRepository.Update(user); err != nil {
+		return nil, err
+	}
+
+	return &model.TwoFactorSetup{
+		Secret: secret,
+		Uri:    totpUri(user.Email, secret),
+	}, nil
+}
+
+func (s *userService) ConfirmTwoFactor(user *model.User, code string) ([]string, error) {
+	if user.TwoFactorEnabled {
+		return nil, apperrors.NewBadRequest(apperrors.TwoFactorEnabled)
+	}
+
+	if user.TwoFactorSecret == "" {
+		return nil, apperrors.NewBadRequest(apperrors.TwoFactorNotStarted)
+	}
+
+	step, ok := validateTotp(user.TwoFactorSecret, code, time.Now())
+
+	if !ok {
+		return nil, apperrors.NewBadRequest(apperrors.InvalidTwoFactor)
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+
+	if err != nil {
+		log.Printf("Unable to generate recovery codes: %v\n", err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	user.TwoFactorEnabled = true
+	user.TwoFactorStep = step
+	user.RecoveryCodes = hashes
+
+	if err = s.UserRepository.Update(user); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (s *userService) DisableTwoFactor(user *model.User, password string) error {
+	if !user.TwoFactorEnabled {
+		return apperrors.NewBadRequest(apperrors.TwoFactorDisabled)
+	}
+
+	match, err := comparePasswords(user.Password, password)
+
+	if err != nil {
+		return apperrors.NewInternal()
+	}
+
+	if !match {
+		return apperrors.NewAuthorization(apperrors.InvalidPassword)
+	}
+
+	user.TwoFactorEnabled = false
+	user.TwoFactorSecret = ""
+	user.TwoFactorStep = 0
+	user.RecoveryCodes = nil
+
+	return s.UserRepository.Update(user)
+}
+
+// VerifyTwoFactor checks the TOTP code of a user with two-factor authentication.
+// Each code is only accepted once. Recovery codes are only accepted when logging in.
+func (s *userService) VerifyTwoFactor(user *model.User, code string) error {
+	if !user.TwoFactorEnabled {
+		return nil
+	}
+
+	step, ok := validateTotp(user.TwoFactorSecret, code, time.Now())
+
+	if !ok || step <= user.TwoFactorStep {
+		return apperrors.NewBadRequest(apperrors.InvalidTwoFactor)
+	}
+
+	user.TwoFactorStep = step
+
+	return s.UserRepository.Update(user)
+}
+
+func (s *userService) StartTwoFactorLogin(ctx context.Context, user *model.User) (string, error) {
+	return s.RedisRepository.SetPendingLogin(ctx, user.ID)
+}
+
+// CompleteTwoFactorLogin returns the user of the ticket if the code is either
+// a valid TOTP code or one of the unused recovery codes
+func (s *userService) CompleteTwoFactorLogin(ctx context.Context, ticket string, code string) (*model.User, error) {
+	id, err := s.RedisRepository.GetPendingLogin(ctx, ticket)
+
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.UserRepository.FindByID(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.VerifyTwoFactor(user, code); err == nil {
+		return user, nil
+	}
+
+	hash := hashRecoveryCode(code)
+
+	for i, recoveryCode := range user.RecoveryCodes {
+		if recoveryCode != hash {
+			continue
+		}
+
+		user.RecoveryCodes = append(user.RecoveryCodes[:i:i], user.RecoveryCodes[i+1:]...)
+
+		if err = s.UserRepository.Update(user); err != nil {
+			return nil, err
+		}
+
+		return user, nil
+	}
+
+	return nil, apperrors.NewBadRequest(apperrors.InvalidTwoFactor)
+}
+
 func (s *userService) GetFriendAndGuildIds(userId string) (*[]string, error) {
 	return s.UserRepository.GetFriendAndGuildIds(userId)
 }
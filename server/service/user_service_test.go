@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
+	"time"
 )
 
 func TestGet(t *testing.T) {
@@ -198,6 +199,19 @@ func TestLogin(t *testing.T) {
 		assert.Nil(t, user)
 		mockUserRepository.AssertCalled(t, "FindByEmail", mockArgs...)
 	})
+
+	t.Run("Bots cannot log in", func(t *testing.T) {
+		mockBot := fixture.GetMockBot(GenerateId())
+		mockBot.Password = ""
+
+		mockUserRepository.
+			On("FindByEmail", mockBot.Email).Return(mockBot, nil)
+
+		user, err := us.Login(mockBot.Email, "")
+
+		assert.EqualError(t, err, apperrors.InvalidCredentials)
+		assert.Nil(t, user)
+	})
 }
 
 func TestUpdateDetails(t *testing.T) {
@@ -461,6 +475,23 @@ func TestUserService_ChangeAvatar(t *testing.T) {
 	})
 }
 
+func TestUserService_GetImageUrl(t *testing.T) {
+	mockFileRepository := new(mocks.FileRepository)
+	us := NewUserService(&USConfig{
+		FileRepository: mockFileRepository,
+	})
+
+	path := "valkyrie/users/abcde/abcde.png"
+	sizeUrl := "https://imageurl.com/files/valkyrie/users/abcde/abcde/256.png"
+	mockFileRepository.On("ImageUrl", path, 256).Return(sizeUrl, nil)
+
+	url, err := us.GetImageUrl(path, 256)
+
+	assert.NoError(t, err)
+	assert.Equal(t, sizeUrl, url)
+	mockFileRepository.AssertExpectations(t)
+}
+
 func TestUserService_ChangePassword(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockUser := fixture.GetMockUser()
@@ -678,3 +709,428 @@ func TestUserService_ResetPassword(t *testing.T) {
 		mockRedisRepository.AssertExpectations(t)
 	})
 }
+
+func TestUserService_SendVerificationMail(t *testing.T) {
+	token := fixture.RandStringRunes(10)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUser := fixture.GetMockUser()
+		mockRedisRepository := new(mocks.RedisRepository)
+		mockMailRepository := new(mocks.MailRepository)
+
+		us := NewUserService(&USConfig{
+			RedisRepository: mockRedisRepository,
+			MailRepository:  mockMailRepository,
+		})
+
+		mockRedisRepository.On("SetVerificationToken", mock.Anything, mockUser.ID, mockUser.Email).Return(token, nil)
+		mockMailRepository.On("SendVerificationMail", mockUser.Email, token).Return(nil)
+
+		err := us.SendVerificationMail(context.TODO(), mockUser)
+		assert.NoError(t, err)
+
+		mockRedisRepository.AssertExpectations(t)
+		mockMailRepository.AssertExpectations(t)
+	})
+
+	t.Run("Already verified", func(t *testing.T) {
+		mockUser := fixture.GetMockUser()
+		verifiedAt := time.Now()
+		mockUser.EmailVerifiedAt = &verifiedAt
+
+		mockRedisRepository := new(mocks.RedisRepository)
+		mockMailRepository := new(mocks.MailRepository)
+
+		us := NewUserService(&USConfig{
+			RedisRepository: mockRedisRepository,
+			MailRepository:  mockMailRepository,
+		})
+
+		err := us.SendVerificationMail(context.TODO(), mockUser)
+		assert.Error(t, err)
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.AlreadyVerified), err)
+
+		mockRedisRepository.AssertNotCalled(t, "SetVerificationToken", mock.Anything, mock.Anything, mock.Anything)
+		mockMailRepository.AssertNotCalled(t, "SendVerificationMail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockUser := fixture.GetMockUser()
+		mockRedisRepository := new(mocks.RedisRepository)
+		mockMailRepository := new(mocks.MailRepository)
+
+		us := NewUserService(&USConfig{
+			RedisRepository: mockRedisRepository,
+			MailRepository:  mockMailRepository,
+		})
+
+		mockError := apperrors.NewInternal()
+		mockRedisRepository.On("SetVerificationToken", mock.Anything, mockUser.ID, mockUser.Email).Return("", mockError)
+
+		err := us.SendVerificationMail(context.TODO(), mockUser)
+		assert.Error(t, err)
+
+		mockRedisRepository.AssertExpectations(t)
+		mockMailRepository.AssertNotCalled(t, "SendVerificationMail", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserService_VerifyEmail(t *testing.T) {
+	token := fixture.RandStr(10)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUser := fixture.GetMockUser()
+		mockUserRepository := new(mocks.UserRepository)
+		mockRedisRepository := new(mocks.RedisRepository)
+
+		us := NewUserService(&USConfig{
+			UserRepository:  mockUserRepository,
+			RedisRepository: mockRedisRepository,
+		})
+
+		verification := &model.EmailVerification{UserId: mockUser.ID, Email: mockUser.Email}
+		mockRedisRepository.On("GetVerificationToken", mock.Anything, token).Return(verification, nil)
+		mockUserRepository.On("FindByID", mockUser.ID).Return(mockUser, nil)
+		mockUserRepository.On("Update", mockUser).Return(nil)
+
+		user, err := us.VerifyEmail(context.TODO(), token)
+		assert.NoError(t, err)
+		assert.True(t, user.IsEmailVerified())
+
+		mockUserRepository.AssertExpectations(t)
+		mockRedisRepository.AssertExpectations(t)
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockRedisRepository := new(mocks.RedisRepository)
+
+		us := NewUserService(&USConfig{
+			UserRepository:  mockUserRepository,
+			RedisRepository: mockRedisRepository,
+		})
+
+		mockError := apperrors.NewBadRequest(apperrors.InvalidVerifyToken)
+		mockRedisRepository.On("GetVerificationToken", mock.Anything, token).Return(nil, mockError)
+
+		user, err := us.VerifyEmail(context.TODO(), token)
+		assert.Equal(t, mockError, err)
+		assert.Nil(t, user)
+
+		mockUserRepository.AssertNotCalled(t, "FindByID", mock.Anything)
+		mockUserRepository.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("Email changed after the token got sent", func(t *testing.T) {
+		mockUser := fixture.GetMockUser()
+		mockUserRepository := new(mocks.UserRepository)
+		mockRedisRepository := new(mocks.RedisRepository)
+
+		us := NewUserService(&USConfig{
+			UserRepository:  mockUserRepository,
+			RedisRepository: mockRedisRepository,
+		})
+
+		verification := &model.EmailVerification{UserId: mockUser.ID, Email: fixture.Email()}
+		mockRedisRepository.On("GetVerificationToken", mock.Anything, token).Return(verification, nil)
+		mockUserRepository.On("FindByID", mockUser.ID).Return(mockUser, nil)
+
+		user, err := us.VerifyEmail(context.TODO(), token)
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.InvalidVerifyToken), err)
+		assert.Nil(t, user)
+		assert.False(t, mockUser.IsEmailVerified())
+
+		mockUserRepository.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
+
+// currentTotp returns the current code and time step of the secret
+func currentTotp(t *testing.T, secret string) (string, int64) {
+	key, err := totpEncoding.DecodeString(secret)
+	assert.NoError(t, err)
+
+	step := time.Now().Unix() / totpPeriod
+
+	return totpCode(key, step), step
+}
+
+func TestUserService_EnableTwoFactor(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUser := fixture.GetMockUser()
+		mockUserRepository := new(mocks.UserRepository)
+
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockUserRepository.On("Update", mockUser).Return(nil)
+
+		setup, err := us.EnableTwoFactor(mockUser)
+		assert.NoError(t, err)
+		assert.Equal(t, mockUser.TwoFactorSecret, setup.Secret)
+		assert.Contains(t, setup.Uri, "secret="+setup.Secret)
+		assert.False(t, mockUser.TwoFactorEnabled)
+
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("Already enabled", func(t *testing.T) {
+		mockUser := fixture.GetMockUser()
+		mockUser.TwoFactorEnabled = true
+		mockUserRepository := new(mocks.UserRepository)
+
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		setup, err := us.EnableTwoFactor(mockUser)
+		assert.Nil(t, setup)
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.TwoFactorEnabled), err)
+
+		mockUserRepository.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
+
+func TestUserService_ConfirmTwoFactor(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUser := fixture.GetMockUser()
+		mockUser.TwoFactorSecret, _ = generateTotpSecret()
+		mockUserRepository := new(mocks.UserRepository)
+
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockUserRepository.On("Update", mockUser).Return(nil)
+
+		code, step := currentTotp(t, mockUser.TwoFactorSecret)
+
+		codes, err := us.ConfirmTwoFactor(mockUser, code)
+		assert.NoError(t, err)
+		assert.Len(t, codes, recoveryCodeCount)
+		assert.True(t, mockUser.TwoFactorEnabled)
+		assert.Equal(t, step, mockUser.TwoFactorStep)
+		assert.Len(t, mockUser.RecoveryCodes, recoveryCodeCount)
+		assert.NotContains(t, mockUser.RecoveryCodes, codes[0])
+
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("Enrollment not started", func(t *testing.T) {
+		mockUser := fixture.GetMockUser()
+		mockUserRepository := new(mocks.UserRepository)
+
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		codes, err := us.ConfirmTwoFactor(mockUser, "123456")
+		assert.Nil(t, codes)
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.TwoFactorNotStarted), err)
+
+		mockUserRepository.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("Invalid code", func(t *testing.T) {
+		mockUser := fixture.GetMockUser()
+		mockUser.TwoFactorSecret, _ = generateTotpSecret()
+		mockUserRepository := new(mocks.UserRepository)
+
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		codes, err := us.ConfirmTwoFactor(mockUser, "abcdef")
+		assert.Nil(t, codes)
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.InvalidTwoFactor), err)
+		assert.False(t, mockUser.TwoFactorEnabled)
+
+		mockUserRepository.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
+
+func TestUserService_DisableTwoFactor(t *testing.T) {
+	password := fixture.RandStr(10)
+	hashedPassword, _ := hashPassword(password)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUser := fixture.GetMockUser()
+		mockUser.Password = hashedPassword
+		mockUser.TwoFactorEnabled = true
+		mockUser.TwoFactorSecret, _ = generateTotpSecret()
+		mockUser.RecoveryCodes = []string{hashRecoveryCode("abcde-fghij")}
+		mockUserRepository := new(mocks.UserRepository)
+
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockUserRepository.On("Update", mockUser).Return(nil)
+
+		err := us.DisableTwoFactor(mockUser, password)
+		assert.NoError(t, err)
+		assert.False(t, mockUser.TwoFactorEnabled)
+		assert.Empty(t, mockUser.TwoFactorSecret)
+		assert.Empty(t, mockUser.RecoveryCodes)
+
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("Wrong password", func(t *testing.T) {
+		mockUser := fixture.GetMockUser()
+		mockUser.Password = hashedPassword
+		mockUser.TwoFactorEnabled = true
+		mockUserRepository := new(mocks.UserRepository)
+
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		err := us.DisableTwoFactor(mockUser, fixture.RandStr(10))
+		assert.Equal(t, apperrors.NewAuthorization(apperrors.InvalidPassword), err)
+		assert.True(t, mockUser.TwoFactorEnabled)
+
+		mockUserRepository.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
+
+func TestUserService_VerifyTwoFactor(t *testing.T) {
+	t.Run("Code can only be used once", func(t *testing.T) {
+		mockUser := fixture.GetMockUser()
+		mockUser.TwoFactorEnabled = true
+		mockUser.TwoFactorSecret, _ = generateTotpSecret()
+		mockUserRepository := new(mocks.UserRepository)
+
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockUserRepository.On("Update", mockUser).Return(nil)
+
+		code, step := currentTotp(t, mockUser.TwoFactorSecret)
+
+		err := us.VerifyTwoFactor(mockUser, code)
+		assert.NoError(t, err)
+		assert.Equal(t, step, mockUser.TwoFactorStep)
+
+		err = us.VerifyTwoFactor(mockUser, code)
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.InvalidTwoFactor), err)
+
+		mockUserRepository.AssertNumberOfCalls(t, "Update", 1)
+	})
+
+	t.Run("Recovery codes are not accepted", func(t *testing.T) {
+		mockUser := fixture.GetMockUser()
+		mockUser.TwoFactorEnabled = true
+		mockUser.TwoFactorSecret, _ = generateTotpSecret()
+		mockUser.RecoveryCodes = []string{hashRecoveryCode("abcde-fghij")}
+		mockUserRepository := new(mocks.UserRepository)
+
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		err := us.VerifyTwoFactor(mockUser, "abcde-fghij")
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.InvalidTwoFactor), err)
+
+		mockUserRepository.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
+
+func TestUserService_CompleteTwoFactorLogin(t *testing.T) {
+	ticket := fixture.RandStr(10)
+
+	t.Run("TOTP code", func(t *testing.T) {
+		mockUser := fixture.GetMockUser()
+		mockUser.TwoFactorEnabled = true
+		mockUser.TwoFactorSecret, _ = generateTotpSecret()
+		mockUserRepository := new(mocks.UserRepository)
+		mockRedisRepository := new(mocks.RedisRepository)
+
+		us := NewUserService(&USConfig{
+			UserRepository:  mockUserRepository,
+			RedisRepository: mockRedisRepository,
+		})
+
+		mockRedisRepository.On("GetPendingLogin", mock.Anything, ticket).Return(mockUser.ID, nil)
+		mockUserRepository.On("FindByID", mockUser.ID).Return(mockUser, nil)
+		mockUserRepository.On("Update", mockUser).Return(nil)
+
+		code, _ := currentTotp(t, mockUser.TwoFactorSecret)
+
+		user, err := us.CompleteTwoFactorLogin(context.TODO(), ticket, code)
+		assert.NoError(t, err)
+		assert.Equal(t, mockUser, user)
+
+		mockRedisRepository.AssertExpectations(t)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("Recovery code is used up", func(t *testing.T) {
+		mockUser := fixture.GetMockUser()
+		mockUser.TwoFactorEnabled = true
+		mockUser.TwoFactorSecret, _ = generateTotpSecret()
+		mockUser.RecoveryCodes = []string{hashRecoveryCode("abcde-fghij"), hashRecoveryCode("klmno-pqrst")}
+		mockUserRepository := new(mocks.UserRepository)
+		mockRedisRepository := new(mocks.RedisRepository)
+
+		us := NewUserService(&USConfig{
+			UserRepository:  mockUserRepository,
+			RedisRepository: mockRedisRepository,
+		})
+
+		mockRedisRepository.On("GetPendingLogin", mock.Anything, ticket).Return(mockUser.ID, nil)
+		mockUserRepository.On("FindByID", mockUser.ID).Return(mockUser, nil)
+		mockUserRepository.On("Update", mockUser).Return(nil)
+
+		user, err := us.CompleteTwoFactorLogin(context.TODO(), ticket, "ABCDE-FGHIJ")
+		assert.NoError(t, err)
+		assert.Equal(t, mockUser, user)
+		assert.Equal(t, []string{hashRecoveryCode("klmno-pqrst")}, []string(mockUser.RecoveryCodes))
+
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("Invalid code", func(t *testing.T) {
+		mockUser := fixture.GetMockUser()
+		mockUser.TwoFactorEnabled = true
+		mockUser.TwoFactorSecret, _ = generateTotpSecret()
+		mockUser.RecoveryCodes = []string{hashRecoveryCode("abcde-fghij")}
+		mockUserRepository := new(mocks.UserRepository)
+		mockRedisRepository := new(mocks.RedisRepository)
+
+		us := NewUserService(&USConfig{
+			UserRepository:  mockUserRepository,
+			RedisRepository: mockRedisRepository,
+		})
+
+		mockRedisRepository.On("GetPendingLogin", mock.Anything, ticket).Return(mockUser.ID, nil)
+		mockUserRepository.On("FindByID", mockUser.ID).Return(mockUser, nil)
+
+		user, err := us.CompleteTwoFactorLogin(context.TODO(), ticket, "zzzzz-zzzzz")
+		assert.Nil(t, user)
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.InvalidTwoFactor), err)
+		assert.Len(t, mockUser.RecoveryCodes, 1)
+
+		mockUserRepository.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("Expired ticket", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockRedisRepository := new(mocks.RedisRepository)
+
+		us := NewUserService(&USConfig{
+			UserRepository:  mockUserRepository,
+			RedisRepository: mockRedisRepository,
+		})
+
+		mockError := apperrors.NewBadRequest(apperrors.InvalidLoginTicket)
+		mockRedisRepository.On("GetPendingLogin", mock.Anything, ticket).Return("", mockError)
+
+		user, err := us.CompleteTwoFactorLogin(context.TODO(), ticket, "123456")
+		assert.Nil(t, user)
+		assert.Equal(t, mockError, err)
+
+		mockUserRepository.AssertNotCalled(t, "FindByID", mock.Anything)
+	})
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"log"
+)
+
+// webhookService acts as a struct for injecting an implementation of WebhookRepository
+// for use in service methods
+type webhookService struct {
+	WebhookRepository model.WebhookRepository
+}
+
+// WHSConfig will hold repositories that will eventually be injected into
+// this service layer
+type WHSConfig struct {
+	WebhookRepository model.WebhookRepository
+}
+
+// NewWebhookService is a factory function for
+// initializing a WebhookService with its repository layer dependencies
+func NewWebhookService(c *WHSConfig) model.WebhookService {
+	return &webhookService{
+		WebhookRepository: c.WebhookRepository,
+	}
+}
+
+// CreateWebhook creates a webhook for the given guild channel together with its user
+// and returns it with its token.
+// The user is a bot without a password, so it cannot log in.
+func (s *webhookService) CreateWebhook(channel *model.Channel, creatorId string, name string) (*model.CreatedWebhook, error) {
+	count, err := s.WebhookRepository.CountByChannel(channel.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if count >= model.WebhookLimit {
+		return nil, apperrors.NewBadRequest(apperrors.WebhookLimitError)
+	}
+
+	token, err := generateSecret()
+
+	if err != nil {
+		log.Printf("Failed to generate a webhook token: %v\n", err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	id := GenerateId()
+	email := fmt.Sprintf("%s@%s", id, model.WebhookEmailDomain)
+
+	user := model.User{
+		BaseModel: model.BaseModel{
+			ID: id,
+		},
+		Username: name,
+		Email:    email,
+		Image:    generateAvatar(email),
+		Bot:      true,
+	}
+
+	webhook := model.Webhook{
+		BaseModel: model.BaseModel{
+			ID: id,
+		},
+		ChannelId: channel.ID,
+		GuildId:   *channel.GuildID,
+		CreatorId: creatorId,
+		Name:      name,
+		Image:     user.Image,
+		TokenHash: hashAccessToken(token),
+	}
+
+	if err = s.WebhookRepository.Create(&webhook, &user); err != nil {
+		return nil, err
+	}
+
+	return &model.CreatedWebhook{
+		Webhook: webhook,
+		Token:   token,
+	}, nil
+}
+
+func (s *webhookService) GetWebhooks(channelId string) (*[]model.Webhook, error) {
+	return s.WebhookRepository.FindByChannel(channelId)
+}
+
+// GetWebhook returns the webhook with the given ID if it belongs to the given channel
+func (s *webhookService) GetWebhook(channelId string, webhookId string) (*model.Webhook, error) {
+	webhook, err := s.WebhookRepository.FindByID(webhookId)
+
+	if err != nil || webhook.ChannelId != channelId {
+		return nil, apperrors.NewNotFound("webhook", webhookId)
+	}
+
+	return webhook, nil
+}
+
+func (s *webhookService) UpdateWebhook(webhook *model.Webhook) error {
+	return s.WebhookRepository.Update(webhook)
+}
+
+// ResetWebhookToken replaces the token of the webhook, so the old one stops working
+func (s *webhookService) ResetWebhookToken(webhook *model.Webhook) (*model.CreatedWebhook, error) {
+	token, err := generateSecret()
+
+	if err != nil {
+		log.Printf("Failed to generate a webhook token: %v\n", err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	webhook.TokenHash = hashAccessToken(token)
+
+	if err = s.WebhookRepository.Update(webhook); err != nil {
+		return nil, err
+	}
+
+	return &model.CreatedWebhook{
+		Webhook: *webhook,
+		Token:   token,
+	}, nil
+}
+
+func (s *webhookService) DeleteWebhook(webhook *model.Webhook) error {
+	return s.WebhookRepository.Delete(webhook.ID)
+}
+
+// Authenticate returns the webhook if the token belongs to it.
+// Unknown webhooks and wrong tokens return the same Authorization error.
+func (s *webhookService) Authenticate(webhookId string, token string) (*model.Webhook, error) {
+	webhook, err := s.WebhookRepository.FindByID(webhookId)
+
+	if err != nil {
+		var e *apperrors.Error
+		if errors.As(err, &e) && e.Type == apperrors.NotFound {
+			return nil, apperrors.NewAuthorization(apperrors.InvalidWebhookToken)
+		}
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(webhook.TokenHash), []byte(hashAccessToken(token))) != 1 {
+		return nil, apperrors.NewAuthorization(apperrors.InvalidWebhookToken)
+	}
+
+	return webhook, nil
+}
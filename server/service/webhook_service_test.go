@@ -0,0 +1,170 @@
+package service
+
+import (
+	"fmt"
+	"github.com/sentrionic/valkyrie/mocks"
+	"github.com/sentrionic/valkyrie/model"
+	"github.com/sentrionic/valkyrie/model/apperrors"
+	"github.com/sentrionic/valkyrie/model/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestWebhookService_CreateWebhook(t *testing.T) {
+	channel := fixture.GetMockChannel(fixture.RandID())
+	creatorId := fixture.RandID()
+
+	t.Run("Success", func(t *testing.T) {
+		mockWebhookRepository := new(mocks.WebhookRepository)
+		ws := NewWebhookService(&WHSConfig{
+			WebhookRepository: mockWebhookRepository,
+		})
+
+		mockWebhookRepository.On("CountByChannel", channel.ID).Return(int64(2), nil)
+		mockWebhookRepository.
+			On("Create", mock.AnythingOfType("*model.Webhook"), mock.AnythingOfType("*model.User")).
+			Return(nil)
+
+		created, err := ws.CreateWebhook(channel, creatorId, "CI")
+		assert.NoError(t, err)
+
+		assert.NotEmpty(t, created.ID)
+		assert.NotEmpty(t, created.Token)
+		assert.Equal(t, channel.ID, created.ChannelId)
+		assert.Equal(t, *channel.GuildID, created.GuildId)
+		assert.Equal(t, creatorId, created.CreatorId)
+		assert.Equal(t, "CI", created.Name)
+		assert.Equal(t, hashAccessToken(created.Token), created.TokenHash)
+
+		// The user of the webhook shares its ID, name and image
+		user := mockWebhookRepository.Calls[1].Arguments.Get(1).(*model.User)
+		assert.Equal(t, created.ID, user.ID)
+		assert.Equal(t, "CI", user.Username)
+		assert.Equal(t, created.Image, user.Image)
+		assert.Equal(t, fmt.Sprintf("%s@%s", created.ID, model.WebhookEmailDomain), user.Email)
+		assert.Empty(t, user.Password)
+		assert.True(t, user.Bot)
+
+		mockWebhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Limit reached", func(t *testing.T) {
+		mockWebhookRepository := new(mocks.WebhookRepository)
+		ws := NewWebhookService(&WHSConfig{
+			WebhookRepository: mockWebhookRepository,
+		})
+
+		mockWebhookRepository.On("CountByChannel", channel.ID).Return(int64(model.WebhookLimit), nil)
+
+		created, err := ws.CreateWebhook(channel, creatorId, "CI")
+
+		assert.Nil(t, created)
+		assert.Equal(t, apperrors.NewBadRequest(apperrors.WebhookLimitError), err)
+		mockWebhookRepository.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestWebhookService_GetWebhook(t *testing.T) {
+	channel := fixture.GetMockChannel(fixture.RandID())
+	webhook, _ := fixture.GetMockWebhook(channel)
+
+	t.Run("Success", func(t *testing.T) {
+		mockWebhookRepository := new(mocks.WebhookRepository)
+		ws := NewWebhookService(&WHSConfig{
+			WebhookRepository: mockWebhookRepository,
+		})
+
+		mockWebhookRepository.On("FindByID", webhook.ID).Return(webhook, nil)
+
+		result, err := ws.GetWebhook(channel.ID, webhook.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, webhook, result)
+	})
+
+	t.Run("Webhook of another channel", func(t *testing.T) {
+		mockWebhookRepository := new(mocks.WebhookRepository)
+		ws := NewWebhookService(&WHSConfig{
+			WebhookRepository: mockWebhookRepository,
+		})
+
+		mockWebhookRepository.On("FindByID", webhook.ID).Return(webhook, nil)
+
+		result, err := ws.GetWebhook(fixture.RandID(), webhook.ID)
+
+		assert.Nil(t, result)
+		assert.Equal(t, apperrors.NewNotFound("webhook", webhook.ID), err)
+	})
+}
+
+func TestWebhookService_ResetWebhookToken(t *testing.T) {
+	channel := fixture.GetMockChannel(fixture.RandID())
+	webhook, _ := fixture.GetMockWebhook(channel)
+	oldHash := webhook.TokenHash
+
+	mockWebhookRepository := new(mocks.WebhookRepository)
+	ws := NewWebhookService(&WHSConfig{
+		WebhookRepository: mockWebhookRepository,
+	})
+
+	mockWebhookRepository.On("Update", webhook).Return(nil)
+
+	created, err := ws.ResetWebhookToken(webhook)
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, oldHash, webhook.TokenHash)
+	assert.Equal(t, hashAccessToken(created.Token), webhook.TokenHash)
+	mockWebhookRepository.AssertExpectations(t)
+}
+
+func TestWebhookService_Authenticate(t *testing.T) {
+	channel := fixture.GetMockChannel(fixture.RandID())
+	webhook, _ := fixture.GetMockWebhook(channel)
+	token := "token"
+	webhook.TokenHash = hashAccessToken(token)
+
+	t.Run("Success", func(t *testing.T) {
+		mockWebhookRepository := new(mocks.WebhookRepository)
+		ws := NewWebhookService(&WHSConfig{
+			WebhookRepository: mockWebhookRepository,
+		})
+
+		mockWebhookRepository.On("FindByID", webhook.ID).Return(webhook, nil)
+
+		result, err := ws.Authenticate(webhook.ID, token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, webhook, result)
+	})
+
+	t.Run("Wrong token", func(t *testing.T) {
+		mockWebhookRepository := new(mocks.WebhookRepository)
+		ws := NewWebhookService(&WHSConfig{
+			WebhookRepository: mockWebhookRepository,
+		})
+
+		mockWebhookRepository.On("FindByID", webhook.ID).Return(webhook, nil)
+
+		result, err := ws.Authenticate(webhook.ID, "wrong")
+
+		assert.Nil(t, result)
+		assert.Equal(t, apperrors.NewAuthorization(apperrors.InvalidWebhookToken), err)
+	})
+
+	t.Run("Unknown webhook", func(t *testing.T) {
+		id := fixture.RandID()
+
+		mockWebhookRepository := new(mocks.WebhookRepository)
+		ws := NewWebhookService(&WHSConfig{
+			WebhookRepository: mockWebhookRepository,
+		})
+
+		mockWebhookRepository.On("FindByID", id).Return(nil, apperrors.NewNotFound("webhook", id))
+
+		result, err := ws.Authenticate(id, token)
+
+		assert.Nil(t, result)
+		assert.Equal(t, apperrors.NewAuthorization(apperrors.InvalidWebhookToken), err)
+	})
+}
@@ -21,17 +21,31 @@ const (
 	NewMessageAction        = "new_message"
 	EditMessageAction       = "edit_message"
 	DeleteMessageAction     = "delete_message"
+	AddReactionAction       = "add_reaction"
+	RemoveReactionAction    = "remove_reaction"
+	PinMessageAction        = "pin_message"
+	UnpinMessageAction      = "unpin_message"
 	AddChannelAction        = "add_channel"
 	AddPrivateChannelAction = "add_private_channel"
 	EditChannelAction       = "edit_channel"
 	DeleteChannelAction     = "delete_channel"
+	ThreadCreateAction      = "thread_create"
+	ThreadUpdateAction      = "thread_update"
+	ThreadDeleteAction      = "thread_delete"
 	EditGuildAction         = "edit_guild"
 	DeleteGuildAction       = "delete_guild"
 	RemoveFromGuildAction   = "remove_from_guild"
+	AddToGuildAction        = "add_to_guild"
 	AddMemberAction         = "add_member"
 	RemoveMemberAction      = "remove_member"
+	AddRoleAction           = "add_role"
+	EditRoleAction          = "edit_role"
+	DeleteRoleAction        = "delete_role"
+	AddMemberRoleAction     = "add_member_role"
+	RemoveMemberRoleAction  = "remove_member_role"
 	NewDMNotificationAction = "new_dm_notification"
 	NewNotificationAction   = "new_notification"
+	NewMentionAction        = "new_mention"
 	ToggleOnlineEmission    = "toggle_online"
 	ToggleOfflineEmission   = "toggle_offline"
 	AddToTypingAction       = "addToTyping"
@@ -40,6 +54,8 @@ const (
 	AddRequestAction        = "add_request"
 	AddFriendAction         = "add_friend"
 	RemoveFriendAction      = "remove_friend"
+	InteractionCreateAction = "interaction_create"
+	InteractionDeferAction  = "interaction_deferred"
 	PushToTopAction         = "push_to_top"
 	RequestCountEmission    = "requestCount"
 	VoiceSignal             = "voice-signal"
@@ -23,6 +23,9 @@ const (
 
 	// Maximum message size allowed from peer.
 	maxMessageSize = 10000
+
+	// Close code sent to connections whose session got revoked
+	closeSessionRevoked = 4001
 )
 
 var newline = []byte{'\n'}
@@ -38,20 +41,26 @@ var upgrader = websocket.Upgrader{
 // Client represents the websockets client at the server
 type Client struct {
 	// The actual websockets connection.
-	ID    string
-	conn  *websocket.Conn
-	hub   *Hub
-	send  chan []byte
-	rooms map[*Room]bool
+	ID string
+	// SessionID is the login session the connection got opened with
+	SessionID string
+	// AccessTokenID is the access token the connection got opened with
+	AccessTokenID string
+	conn          *websocket.Conn
+	hub           *Hub
+	send          chan []byte
+	rooms         map[*Room]bool
 }
 
-func newClient(conn *websocket.Conn, hub *Hub, id string) *Client {
+func newClient(conn *websocket.Conn, hub *Hub, id string, sessionId string, accessTokenId string) *Client {
 	return &Client{
-		ID:    id,
-		conn:  conn,
-		hub:   hub,
-		send:  make(chan []byte, 256),
-		rooms: make(map[*Room]bool),
+		ID:            id,
+		SessionID:     sessionId,
+		AccessTokenID: accessTokenId,
+		conn:          conn,
+		hub:           hub,
+		send:          make(chan []byte, 256),
+		rooms:         make(map[*Room]bool),
 	}
 }
 
@@ -130,6 +139,14 @@ func (client *Client) disconnect() {
 	_ = client.conn.Close()
 }
 
+// close tells the client that its session got revoked and closes the connection.
+// The read pump then disconnects the client.
+func (client *Client) close() {
+	message := websocket.FormatCloseMessage(closeSessionRevoked, "session revoked")
+	_ = client.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(writeWait))
+	_ = client.conn.Close()
+}
+
 // ServeWs handles websockets requests from clients requests.
 func ServeWs(hub *Hub, ctx *gin.Context) {
 
@@ -140,7 +157,7 @@ func ServeWs(hub *Hub, ctx *gin.Context) {
 		return
 	}
 
-	client := newClient(conn, hub, userId)
+	client := newClient(conn, hub, userId, ctx.GetString("sessionId"), ctx.GetString("accessTokenId"))
 
 	go client.writePump()
 	go client.readPump()
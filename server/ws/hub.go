@@ -10,6 +10,7 @@ type Hub struct {
 	clients        map[*Client]bool
 	register       chan *Client
 	unregister     chan *Client
+	revoke         chan *revocation
 	broadcast      chan []byte
 	rooms          map[*Room]bool
 	channelService model.ChannelService
@@ -33,6 +34,7 @@ func NewWebsocketHub(c *Config) *Hub {
 		clients:        make(map[*Client]bool),
 		register:       make(chan *Client),
 		unregister:     make(chan *Client),
+		revoke:         make(chan *revocation),
 		broadcast:      make(chan []byte),
 		rooms:          make(map[*Room]bool),
 		channelService: c.ChannelService,
@@ -53,6 +55,9 @@ func (hub *Hub) Run() {
 		case client := <-hub.unregister:
 			hub.unregisterClient(client)
 
+		case r := <-hub.revoke:
+			hub.closeSessions(r)
+
 		case message := <-hub.broadcast:
 			hub.broadcastToClients(message)
 		}
@@ -67,6 +72,60 @@ func (hub *Hub) unregisterClient(client *Client) {
 	delete(hub.clients, client)
 }
 
+// revocation contains the revoked sessions and access tokens of a user
+type revocation struct {
+	userId         string
+	sessionIds     []string
+	accessTokenIds []string
+}
+
+// DisconnectSessions closes the connections of the user that belong to one of the given sessions
+func (hub *Hub) DisconnectSessions(userId string, sessionIds []string) {
+	if len(sessionIds) == 0 {
+		return
+	}
+
+	hub.revoke <- &revocation{userId: userId, sessionIds: sessionIds}
+}
+
+// DisconnectAccessTokens closes the connections of the user that got opened with one of the given access tokens
+func (hub *Hub) DisconnectAccessTokens(userId string, accessTokenIds []string) {
+	if len(accessTokenIds) == 0 {
+		return
+	}
+
+	hub.revoke <- &revocation{userId: userId, accessTokenIds: accessTokenIds}
+}
+
+func (hub *Hub) closeSessions(r *revocation) {
+	for client := range hub.clients {
+		if client.ID != r.userId {
+			continue
+		}
+
+		if r.matches(client) {
+			client.close()
+		}
+	}
+}
+
+// matches reports whether the client got opened with one of the revoked sessions or access tokens
+func (r *revocation) matches(client *Client) bool {
+	for _, id := range r.sessionIds {
+		if client.SessionID == id {
+			return true
+		}
+	}
+
+	for _, id := range r.accessTokenIds {
+		if client.AccessTokenID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (hub *Hub) broadcastToClients(message []byte) {
 	for client := range hub.clients {
 		client.send <- message
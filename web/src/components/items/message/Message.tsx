@@ -29,7 +29,8 @@ export const Message: React.FC<MessageProps> = ({ message, isCompact = false })
   const { guildId } = useParams<keyof RouterProps>() as RouterProps;
   const guild = useGetCurrentGuild(guildId);
   const isOwner = guild !== undefined && guild.ownerId === current?.id;
-  const showMenu = isAuthor || isOwner || message.attachment?.url;
+  const [attachment] = message.attachments;
+  const showMenu = isAuthor || isOwner || attachment?.url;
 
   const { isOpen: isDeleteOpen, onOpen: onDeleteOpen, onClose: onDeleteClose } = useDisclosure();
   const { isOpen: isEditOpen, onOpen: onEditOpen, onClose: onEditClose } = useDisclosure();
@@ -119,11 +120,11 @@ export const Message: React.FC<MessageProps> = ({ message, isCompact = false })
       {showMenu && (
         <>
           <Menu id={message.id} theme={theme.dark}>
-            {message.attachment?.filetype ? (
+            {attachment?.filetype ? (
               <Item
                 className="menu-item"
                 onClick={() => {
-                  if (message.attachment?.url) openInNewTab(message.attachment.url);
+                  if (attachment?.url) openInNewTab(attachment.url);
                 }}
               >
                 <Flex align="center" justify="space-between" w="full">
@@ -29,7 +29,7 @@ export const ChatScreen: React.FC = () => {
     {
       staleTime: 0,
       cacheTime: 0,
-      getNextPageParam: (lastPage) => (hasMore && lastPage.length ? lastPage[lastPage.length - 1].createdAt : ''),
+      getNextPageParam: (lastPage) => (hasMore && lastPage.length ? lastPage[lastPage.length - 1].id : ''),
     }
   );
 
@@ -58,7 +58,7 @@ export const DeleteMessageModal: React.FC<IProps> = ({ message, isOpen, onClose
                     {getTime(message.createdAt)}
                   </Text>
                 </Flex>
-                <Text>{message.attachment?.filename ?? message.text}</Text>
+                <Text>{message.attachments[0]?.filename ?? message.text}</Text>
               </Box>
             </Flex>
           </Flex>
@@ -2,8 +2,8 @@ import { AxiosResponse } from 'axios';
 import { request } from '../setupAxios';
 import { Message } from '../../models/message';
 
-export const getMessages = (id: string, cursor?: string): Promise<AxiosResponse<Message[]>> =>
-  request.get(`messages/${id}${cursor ? `?cursor=${cursor}` : ''}`);
+export const getMessages = (id: string, before?: string): Promise<AxiosResponse<Message[]>> =>
+  request.get(`messages/${id}${before ? `?before=${before}` : ''}`);
 
 export const sendMessage = (
   channelId: string,
@@ -5,7 +5,7 @@ export interface Message {
   text?: string;
   createdAt: string;
   updatedAt: string;
-  attachment?: Attachment;
+  attachments: Attachment[];
   user: Member;
 }
 
@@ -13,4 +13,10 @@ export interface Attachment {
   filename: string;
   filetype: string;
   url: string;
+  size: number;
+  width?: number;
+  height?: number;
+  duration?: number;
+  hash: string;
+  thumbnailUrl?: string;
 }
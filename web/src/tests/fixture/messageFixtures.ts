@@ -5,7 +5,7 @@ export const mockMessage: Message = {
   text: 'Hello World',
   createdAt: '2021-10-04T07:39:01.32804Z',
   updatedAt: '2021-10-04T07:39:01.32804Z',
-  attachment: undefined,
+  attachments: [],
   user: {
     id: '1444337838748340224',
     username: 'Sen',
@@ -23,7 +23,7 @@ export const mockMessageList: Message[] = [
     text: '40',
     createdAt: '2021-10-08T13:40:28.517656Z',
     updatedAt: '2021-10-08T13:40:28.517656Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -39,7 +39,7 @@ export const mockMessageList: Message[] = [
     text: '39',
     createdAt: '2021-10-08T13:40:26.661389Z',
     updatedAt: '2021-10-08T13:40:26.661389Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -55,7 +55,7 @@ export const mockMessageList: Message[] = [
     text: '38',
     createdAt: '2021-10-08T13:40:24.474183Z',
     updatedAt: '2021-10-08T13:40:24.474183Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -71,7 +71,7 @@ export const mockMessageList: Message[] = [
     text: '37',
     createdAt: '2021-10-08T13:40:20.657062Z',
     updatedAt: '2021-10-08T13:40:20.657062Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -87,7 +87,7 @@ export const mockMessageList: Message[] = [
     text: '36',
     createdAt: '2021-10-08T13:40:15.455995Z',
     updatedAt: '2021-10-08T13:40:15.455995Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -103,7 +103,7 @@ export const mockMessageList: Message[] = [
     text: '35',
     createdAt: '2021-10-08T13:40:11.442021Z',
     updatedAt: '2021-10-08T13:40:11.442021Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -119,7 +119,7 @@ export const mockMessageList: Message[] = [
     text: '34',
     createdAt: '2021-10-08T13:40:10.673121Z',
     updatedAt: '2021-10-08T13:40:10.673121Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -135,7 +135,7 @@ export const mockMessageList: Message[] = [
     text: '33',
     createdAt: '2021-10-08T13:40:09.37347Z',
     updatedAt: '2021-10-08T13:40:09.37347Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -151,7 +151,7 @@ export const mockMessageList: Message[] = [
     text: '32',
     createdAt: '2021-10-08T13:40:08.717298Z',
     updatedAt: '2021-10-08T13:40:08.717298Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -167,7 +167,7 @@ export const mockMessageList: Message[] = [
     text: '31',
     createdAt: '2021-10-08T13:40:07.218367Z',
     updatedAt: '2021-10-08T13:40:07.218367Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -183,7 +183,7 @@ export const mockMessageList: Message[] = [
     text: '30',
     createdAt: '2021-10-08T13:40:05.038445Z',
     updatedAt: '2021-10-08T13:40:05.038445Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -199,7 +199,7 @@ export const mockMessageList: Message[] = [
     text: '29',
     createdAt: '2021-10-08T13:40:04.053672Z',
     updatedAt: '2021-10-08T13:40:04.053672Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -215,7 +215,7 @@ export const mockMessageList: Message[] = [
     text: '28',
     createdAt: '2021-10-08T13:40:02.393155Z',
     updatedAt: '2021-10-08T13:40:02.393155Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -231,7 +231,7 @@ export const mockMessageList: Message[] = [
     text: '27',
     createdAt: '2021-10-08T13:40:00.766696Z',
     updatedAt: '2021-10-08T13:40:00.766696Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -247,7 +247,7 @@ export const mockMessageList: Message[] = [
     text: '26',
     createdAt: '2021-10-08T13:39:59.133462Z',
     updatedAt: '2021-10-08T13:39:59.133462Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -263,7 +263,7 @@ export const mockMessageList: Message[] = [
     text: '25',
     createdAt: '2021-10-08T13:39:58.433884Z',
     updatedAt: '2021-10-08T13:39:58.433884Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -279,7 +279,7 @@ export const mockMessageList: Message[] = [
     text: '24',
     createdAt: '2021-10-08T13:39:56.711199Z',
     updatedAt: '2021-10-08T13:39:56.711199Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -295,7 +295,7 @@ export const mockMessageList: Message[] = [
     text: '23',
     createdAt: '2021-10-08T13:39:56.245516Z',
     updatedAt: '2021-10-08T13:39:56.245516Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -311,7 +311,7 @@ export const mockMessageList: Message[] = [
     text: '22',
     createdAt: '2021-10-08T13:39:55.656156Z',
     updatedAt: '2021-10-08T13:39:55.656156Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -327,7 +327,7 @@ export const mockMessageList: Message[] = [
     text: '21',
     createdAt: '2021-10-08T13:39:54.93168Z',
     updatedAt: '2021-10-08T13:39:54.93168Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -343,7 +343,7 @@ export const mockMessageList: Message[] = [
     text: '20',
     createdAt: '2021-10-08T13:39:54.230331Z',
     updatedAt: '2021-10-08T13:39:54.230331Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -359,7 +359,7 @@ export const mockMessageList: Message[] = [
     text: '19',
     createdAt: '2021-10-08T13:39:53.335287Z',
     updatedAt: '2021-10-08T13:39:53.335287Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -375,7 +375,7 @@ export const mockMessageList: Message[] = [
     text: '18',
     createdAt: '2021-10-08T13:39:52.246774Z',
     updatedAt: '2021-10-08T13:39:52.246774Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -391,7 +391,7 @@ export const mockMessageList: Message[] = [
     text: '17',
     createdAt: '2021-10-08T13:39:51.022541Z',
     updatedAt: '2021-10-08T13:39:51.022541Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -407,7 +407,7 @@ export const mockMessageList: Message[] = [
     text: '16',
     createdAt: '2021-10-08T13:39:49.415463Z',
     updatedAt: '2021-10-08T13:39:49.415463Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -423,7 +423,7 @@ export const mockMessageList: Message[] = [
     text: '15',
     createdAt: '2021-10-08T13:39:48.688824Z',
     updatedAt: '2021-10-08T13:39:48.688824Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -439,7 +439,7 @@ export const mockMessageList: Message[] = [
     text: '14',
     createdAt: '2021-10-08T13:39:48.139847Z',
     updatedAt: '2021-10-08T13:39:48.139847Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -455,7 +455,7 @@ export const mockMessageList: Message[] = [
     text: '13',
     createdAt: '2021-10-08T13:39:47.422735Z',
     updatedAt: '2021-10-08T13:39:47.422735Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -471,7 +471,7 @@ export const mockMessageList: Message[] = [
     text: '12',
     createdAt: '2021-10-08T13:39:45.954377Z',
     updatedAt: '2021-10-08T13:39:45.954377Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -487,7 +487,7 @@ export const mockMessageList: Message[] = [
     text: '11',
     createdAt: '2021-10-08T13:39:45.010823Z',
     updatedAt: '2021-10-08T13:39:45.010823Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -503,7 +503,7 @@ export const mockMessageList: Message[] = [
     text: '10',
     createdAt: '2021-10-08T13:39:44.079948Z',
     updatedAt: '2021-10-08T13:39:44.079948Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -519,7 +519,7 @@ export const mockMessageList: Message[] = [
     text: '9',
     createdAt: '2021-10-08T13:39:42.022909Z',
     updatedAt: '2021-10-08T13:39:42.022909Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -535,7 +535,7 @@ export const mockMessageList: Message[] = [
     text: '8',
     createdAt: '2021-10-08T13:39:41.667508Z',
     updatedAt: '2021-10-08T13:39:41.667508Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -551,7 +551,7 @@ export const mockMessageList: Message[] = [
     text: '7',
     createdAt: '2021-10-08T13:39:41.271009Z',
     updatedAt: '2021-10-08T13:39:41.271009Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
@@ -567,7 +567,7 @@ export const mockMessageList: Message[] = [
     text: '6',
     createdAt: '2021-10-08T13:39:40.882862Z',
     updatedAt: '2021-10-08T13:39:40.882862Z',
-    attachment: undefined,
+    attachments: [],
     user: {
       id: '1444337838748340224',
       username: 'Sen',
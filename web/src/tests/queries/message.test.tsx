@@ -24,7 +24,7 @@ describe('useQuery - getMessages', () => {
           {
             staleTime: 0,
             cacheTime: 0,
-            getNextPageParam: (lastPage) => (lastPage.length ? lastPage[lastPage.length - 1].createdAt : ''),
+            getNextPageParam: (lastPage) => (lastPage.length ? lastPage[lastPage.length - 1].id : ''),
           }
         ),
       {
@@ -60,7 +60,7 @@ describe('useQuery - getMessages', () => {
           {
             staleTime: 0,
             cacheTime: 0,
-            getNextPageParam: (lastPage) => (lastPage.length ? lastPage[lastPage.length - 1].createdAt : ''),
+            getNextPageParam: (lastPage) => (lastPage.length ? lastPage[lastPage.length - 1].id : ''),
           }
         ),
       {